@@ -0,0 +1,77 @@
+package mcheck_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"mcheck"
+)
+
+// noTestNamespace is a check a program embedding mcheck registers through
+// the exported API alone: packs may not define resources in the test
+// namespace. It stays registered for the rest of the tests, so it only
+// reports while enabled.
+type noTestNamespace struct {
+	enabled atomic.Bool
+}
+
+func (c *noTestNamespace) Rule() mcheck.RuleInfo {
+	return mcheck.RuleInfo{ID: "EXT001", Name: "no-test-namespace", Description: "A resource is defined in the test namespace"}
+}
+
+func (c *noTestNamespace) Check(doc *mcheck.SemanticDocument) []mcheck.Finding {
+	if !c.enabled.Load() || !strings.HasPrefix(doc.Name, "data/test/") {
+		return nil
+	}
+	return []mcheck.Finding{{Severity: mcheck.SeverityWarning, Message: "resource in the test namespace"}}
+}
+
+var externalCheck = &noTestNamespace{}
+
+func init() {
+	mcheck.RegisterSemanticCheck(externalCheck)
+}
+
+func TestRegisterSemanticCheckExternally(t *testing.T) {
+	externalCheck.enabled.Store(true)
+	defer externalCheck.enabled.Store(false)
+
+	schemaDir := filepath.Join(t.TempDir(), "vanilla-mcdoc")
+	schema, err := os.ReadFile(filepath.Join("tests", "mcdocs", "damage_type.mcdoc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	files := map[string][]byte{
+		filepath.Join(schemaDir, "java", "data", "damage_type.mcdoc"):   schema,
+		filepath.Join(root, "data", "test", "damage_type", "acid.json"): []byte(`{"message_id": "acid", "exhaustion": 0.1, "scaling": "never"}`),
+		filepath.Join(root, "data", "pack", "damage_type", "acid.json"): []byte(`{"message_id": "acid", "exhaustion": 0.1, "scaling": "never"}`),
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	validator, err := mcheck.Config{Version: "1.20.1", SchemaDir: schemaDir, Edition: "java"}.NewValidator()
+	if err != nil {
+		t.Fatalf("NewValidator failed: %v", err)
+	}
+	findings, err := validator.CheckPack(context.Background(), root, nil, mcheck.PackOptions{})
+	if err != nil {
+		t.Fatalf("CheckPack failed: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Rule != "EXT001" || findings[0].File != "data/test/damage_type/acid.json" {
+		t.Errorf("Expected the registered check to report the test namespace, got %v", findings)
+	}
+	if rule, ok := mcheck.LookupRule("no-test-namespace"); !ok || rule.ID != "EXT001" {
+		t.Errorf("Expected the registered rule to be listed, got %v, %v", rule, ok)
+	}
+}
@@ -0,0 +1,593 @@
+package mcheck
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BuildStatements walks the syntax tree produced by Parse and fills Statements
+// with complete statements, including struct fields, types and attributes.
+// It is an alternative to Execute, which only records statement names.
+func (p *MCDocParser) BuildStatements() {
+	p.StatementBuilder.Init()
+
+	builder := astBuilder{buffer: p.buffer, file: p.File}
+	builder.collectComments(p.AST())
+	for root := p.AST(); root != nil; root = root.next {
+		if root.pegRule != ruleStart {
+			continue
+		}
+		for _, node := range childNodes(root, ruleStatement) {
+			if stmt := builder.statement(node); stmt != nil {
+				p.Statements = append(p.Statements, stmt)
+			}
+		}
+	}
+	p.EndComments = builder.commentsBefore(uint32(len(p.buffer)))
+}
+
+// astBuilder converts syntax tree nodes into statements and expressions
+type astBuilder struct {
+	buffer     []rune
+	file       string
+	lineStarts []uint32 // rune offsets of the lines of buffer, built on first use
+	comments   []*sourceCommentToken
+}
+
+// childNodes returns the direct children of node with the given rule
+func childNodes(node *node32, rule pegRule) []*node32 {
+	var nodes []*node32
+	for child := node.up; child != nil; child = child.next {
+		if child.pegRule == rule {
+			nodes = append(nodes, child)
+		}
+	}
+	return nodes
+}
+
+// childNode returns the first direct child of node with the given rule
+func childNode(node *node32, rule pegRule) *node32 {
+	for child := node.up; child != nil; child = child.next {
+		if child.pegRule == rule {
+			return child
+		}
+	}
+	return nil
+}
+
+func (b *astBuilder) text(node *node32) string {
+	return string(b.buffer[node.begin:node.end])
+}
+
+// token returns the captured text of a leaf rule like Identifier or Number,
+// without the trailing whitespace and comments the rule consumes
+func (b *astBuilder) token(node *node32) string {
+	if text := childNode(node, rulePegText); text != nil {
+		return b.text(text)
+	}
+	return strings.TrimSpace(b.text(node))
+}
+
+func (b *astBuilder) identifier(node *node32) Identifier {
+	return Identifier{Name: b.token(node)}
+}
+
+func (b *astBuilder) stringValue(node *node32) string {
+	raw := b.token(node)
+	if value, err := strconv.Unquote(raw); err == nil {
+		return value
+	}
+	return strings.Trim(raw, `"`)
+}
+
+// pos returns the position of the rune offset begin
+func (b *astBuilder) pos(begin uint32) SourcePos {
+	if b.lineStarts == nil {
+		b.lineStarts = []uint32{0}
+		for i, r := range b.buffer {
+			if r == '\n' {
+				b.lineStarts = append(b.lineStarts, uint32(i+1))
+			}
+		}
+	}
+	line := sort.Search(len(b.lineStarts), func(i int) bool { return b.lineStarts[i] > begin })
+	return SourcePos{File: b.file, Line: line}
+}
+
+// docBefore collects the /// doc comment block directly preceding pos
+func (b *astBuilder) docBefore(pos uint32) string {
+	end := int(pos)
+
+	// The text between the start of the line and pos must be whitespace only
+	start := end
+	for start > 0 && b.buffer[start-1] != '\n' {
+		start--
+		if !isSpace(b.buffer[start]) {
+			return ""
+		}
+	}
+
+	var lines []string
+	for start > 0 {
+		lineEnd := start - 1
+		lineStart := lineEnd
+		for lineStart > 0 && b.buffer[lineStart-1] != '\n' {
+			lineStart--
+		}
+		line := strings.TrimSpace(string(b.buffer[lineStart:lineEnd]))
+		if !strings.HasPrefix(line, "///") {
+			break
+		}
+		lines = append([]string{strings.TrimPrefix(line, "///")}, lines...)
+		start = lineStart
+	}
+
+	// Strip one leading space if every line shares it
+	shared := len(lines) > 0
+	for _, line := range lines {
+		if !strings.HasPrefix(line, " ") && line != "" {
+			shared = false
+		}
+	}
+	if shared {
+		for i, line := range lines {
+			lines[i] = strings.TrimPrefix(line, " ")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\r'
+}
+
+func (b *astBuilder) statement(node *node32) Statement {
+	var attributes []Attribute
+	var kind *node32
+	for child := node.up; child != nil; child = child.next {
+		switch child.pegRule {
+		case ruleAttribute:
+			attributes = append(attributes, b.attributes(child)...)
+		case ruleUseStmt, ruleTypeAlias, ruleStructDef, ruleEnumDef, ruleDispatchStmt, ruleInjectStmt:
+			kind = child
+		}
+	}
+	if kind == nil {
+		return nil
+	}
+
+	// Use statements have no doc, so comments before them are kept as is
+	doc := ""
+	if kind.pegRule != ruleUseStmt {
+		doc = b.docBefore(node.begin)
+	}
+	comments := b.leadingComments(kind.begin, node.begin, doc)
+
+	switch kind.pegRule {
+	case ruleUseStmt:
+		stmt := UseStatement{Path: b.path(childNode(kind, rulePath)), Comments: comments}
+		if alias := childNode(kind, ruleUseAlias); alias != nil {
+			stmt.Alias = b.token(childNode(alias, ruleIdentifier))
+		}
+		stmt.LineComment = b.lineComment(node)
+		return stmt
+	case ruleTypeAlias:
+		stmt := b.typeAlias(kind, attributes, doc)
+		stmt.Comments = append(comments, b.commentsBefore(b.codeEnd(node))...)
+		stmt.LineComment = b.lineComment(node)
+		return stmt
+	case ruleStructDef:
+		structExpr := b.structBody(kind)
+		structExpr.Name = &Identifier{Name: b.token(childNode(kind, ruleIdentifier))}
+		return StructStatement{
+			Name:        *structExpr.Name,
+			Struct:      structExpr,
+			Attributes:  attributes,
+			Doc:         doc,
+			Comments:    append(comments, b.commentsBefore(b.codeEnd(node))...),
+			LineComment: b.lineComment(node),
+		}
+	case ruleEnumDef:
+		stmt := b.enumDef(kind, attributes, doc)
+		stmt.Comments = append(comments, b.commentsBefore(b.codeEnd(node))...)
+		stmt.LineComment = b.lineComment(node)
+		return stmt
+	case ruleDispatchStmt:
+		stmt := b.dispatch(kind, attributes, doc)
+		stmt.Comments = append(comments, b.commentsBefore(b.codeEnd(node))...)
+		stmt.LineComment = b.lineComment(node)
+		return stmt
+	default:
+		stmt := b.inject(kind, attributes, doc)
+		stmt.Comments = append(comments, b.commentsBefore(b.codeEnd(node))...)
+		stmt.LineComment = b.lineComment(node)
+		return stmt
+	}
+}
+
+func (b *astBuilder) typeAlias(node *node32, attributes []Attribute, doc string) TypeAliasStatement {
+	stmt := TypeAliasStatement{Attributes: attributes, Doc: doc}
+
+	typeName := childNode(node, ruleTypeName)
+	if generic := childNode(typeName, ruleGenericType); generic != nil {
+		stmt.Name = b.identifier(childNode(generic, ruleIdentifier))
+		for _, param := range childNodes(childNode(generic, ruleGenericTypeParams), ruleType) {
+			stmt.TypeParams = append(stmt.TypeParams, strings.TrimSpace(b.text(param)))
+		}
+	} else {
+		stmt.Name = b.identifier(childNode(typeName, ruleIdentifier))
+	}
+
+	stmt.Type = b.typeExpr(childNode(node, ruleType))
+	return stmt
+}
+
+func (b *astBuilder) enumDef(node *node32, attributes []Attribute, doc string) EnumStatement {
+	stmt := EnumStatement{
+		Name:       b.identifier(childNode(node, ruleIdentifier)),
+		Type:       strings.TrimSpace(b.text(childNode(node, ruleType))),
+		Attributes: attributes,
+		Doc:        doc,
+	}
+
+	stmt.Values = b.enumValues(node)
+	stmt.EndComments = b.commentsBefore(childNode(node, ruleRBRACE).begin)
+	return stmt
+}
+
+// enumValues builds the values of a node holding an EnumValueList
+func (b *astBuilder) enumValues(node *node32) []EnumValueExpression {
+	list := childNode(node, ruleEnumValueList)
+	if list == nil {
+		return nil
+	}
+	var values []EnumValueExpression
+	for _, valueNode := range childNodes(list, ruleEnumValue) {
+		name := childNode(valueNode, ruleIdentifier)
+		value := EnumValueExpression{
+			Name: b.token(name),
+			Doc:  b.docBefore(valueNode.begin),
+		}
+		value.Comments = b.leadingComments(name.begin, valueNode.begin, value.Doc)
+		for _, attr := range childNodes(valueNode, ruleAttribute) {
+			value.Attributes = append(value.Attributes, b.attributes(attr)...)
+		}
+		if str := childNode(valueNode, ruleString); str != nil {
+			value.Value = StringLiteral{Value: b.stringValue(str)}
+		}
+		value.LineComment = b.lineComment(valueNode)
+		values = append(values, value)
+	}
+	return values
+}
+
+func (b *astBuilder) inject(node *node32, attributes []Attribute, doc string) InjectStatement {
+	stmt := InjectStatement{Attributes: attributes, Doc: doc}
+	if target := childNode(node, ruleInjectEnum); target != nil {
+		stmt.Path = b.path(childNode(target, rulePath))
+		stmt.Enum = true
+		stmt.Type = strings.TrimSpace(b.text(childNode(target, ruleType)))
+		stmt.Values = b.enumValues(target)
+		stmt.EndComments = b.commentsBefore(childNode(target, ruleRBRACE).begin)
+	} else {
+		target := childNode(node, ruleInjectStruct)
+		stmt.Path = b.path(childNode(target, rulePath))
+		stmt.Struct = b.structBody(target)
+	}
+	return stmt
+}
+
+func (b *astBuilder) dispatch(node *node32, attributes []Attribute, doc string) DispatchStatement {
+	pathNode := childNode(node, ruleDispatchPath)
+	stmt := DispatchStatement{
+		Registry:   b.registry(pathNode),
+		Attributes: attributes,
+		Doc:        doc,
+	}
+
+	for _, keyNode := range childNodes(childNode(pathNode, ruleDispatchKeyList), ruleDispatchKey) {
+		stmt.Keys = append(stmt.Keys, b.indexKey(keyNode))
+	}
+	if params := childNode(pathNode, ruleGenericTypeParams); params != nil {
+		for _, param := range childNodes(params, ruleType) {
+			stmt.TypeParams = append(stmt.TypeParams, strings.TrimSpace(b.text(param)))
+		}
+	}
+	stmt.Path = stmt.Registry + "[" + strings.Join(stmt.Keys, ",") + "]"
+
+	target := childNode(node, ruleDispatchTarget)
+	if typeNode := childNode(target, ruleType); typeNode != nil {
+		stmt.Target = b.typeExpr(typeNode)
+	} else {
+		structExpr := b.structBody(target)
+		structExpr.Name = &Identifier{Name: b.token(childNode(target, ruleIdentifier))}
+		structExpr.Doc = doc
+		stmt.Target = structExpr
+	}
+	return stmt
+}
+
+// registry returns the dispatcher name of a DispatchPath or ComplexReference, like minecraft:resource
+func (b *astBuilder) registry(node *node32) string {
+	namespace := b.token(childNode(node, ruleIdentifier))
+	resource := strings.Join(strings.Fields(b.text(childNode(node, ruleResourcePath))), "")
+	return namespace + ":" + resource
+}
+
+// indexKey returns the key of a DispatchKey or ComplexRefParam node
+func (b *astBuilder) indexKey(node *node32) string {
+	child := node.up
+	switch child.pegRule {
+	case ruleString:
+		return b.stringValue(child)
+	case ruleIdentifier:
+		return b.token(child)
+	}
+	return strings.Join(strings.Fields(b.text(child)), "")
+}
+
+func (b *astBuilder) path(node *node32) Path {
+	path := Path{}
+	for child := node.up; child != nil; child = child.next {
+		switch child.pegRule {
+		case ruleDoubleColon:
+			path.IsAbsolute = true
+		case rulePathSegments:
+			for _, segment := range childNodes(child, rulePathSegment) {
+				if ident := childNode(segment, ruleIdentifier); ident != nil {
+					path.Segments = append(path.Segments, PathSegment{Value: b.token(ident)})
+				} else {
+					path.Segments = append(path.Segments, PathSegment{Value: "super", IsSuper: true})
+				}
+			}
+		}
+	}
+	return path
+}
+
+// structBody builds a struct expression from a node holding a FieldList
+// and the brace closing it
+func (b *astBuilder) structBody(node *node32) StructExpression {
+	structExpr := StructExpression{Pos: b.pos(node.begin)}
+	if list := childNode(node, ruleFieldList); list != nil {
+		for _, item := range childNodes(list, ruleFieldOrSpread) {
+			child := item.up
+			switch child.pegRule {
+			case ruleField:
+				structExpr.Fields = append(structExpr.Fields, b.field(child))
+			case ruleSpreadField:
+				field := FieldExpression{Spread: true, Doc: b.docBefore(child.begin), Pos: b.pos(child.begin)}
+				field.Comments = b.leadingComments(childNode(child, ruleSPREAD).begin, child.begin, field.Doc)
+				for _, attr := range childNodes(child, ruleAttribute) {
+					field.Attributes = append(field.Attributes, b.attributes(attr)...)
+				}
+				field.Type = b.typeExpr(childNode(child, ruleType))
+				field.Comments = append(field.Comments, b.commentsBefore(b.codeEnd(child))...)
+				field.LineComment = b.lineComment(child)
+				structExpr.Fields = append(structExpr.Fields, field)
+			}
+		}
+	}
+	structExpr.EndComments = b.commentsBefore(childNode(node, ruleRBRACE).begin)
+	return structExpr
+}
+
+func (b *astBuilder) field(node *node32) FieldExpression {
+	field := FieldExpression{Doc: b.docBefore(node.begin), Pos: b.pos(node.begin)}
+	name := childNode(node, ruleNamedField)
+	if name == nil {
+		name = childNode(node, ruleComputedField)
+	}
+	field.Comments = b.leadingComments(name.begin, node.begin, field.Doc)
+	for child := node.up; child != nil; child = child.next {
+		switch child.pegRule {
+		case ruleAttribute:
+			field.Attributes = append(field.Attributes, b.attributes(child)...)
+		case ruleNamedField:
+			name := childNode(child, ruleFieldName)
+			field.Name = b.identifier(childNode(name, ruleIdentifier))
+			field.Optional = childNode(name, ruleQUESTION) != nil
+			field.Type = b.typeExpr(childNode(child, ruleType))
+		case ruleComputedField:
+			types := childNodes(child, ruleType)
+			field.Key = b.typeExpr(types[0])
+			field.Type = b.typeExpr(types[1])
+			field.Optional = childNode(child, ruleQUESTION) != nil
+		}
+	}
+	field.Comments = append(field.Comments, b.commentsBefore(b.codeEnd(node))...)
+	field.LineComment = b.lineComment(node)
+	return field
+}
+
+// typeExpr builds an expression from a Type node or any of its alternatives
+func (b *astBuilder) typeExpr(node *node32) Expression {
+	switch node.pegRule {
+	case ruleType:
+		return b.typeExpr(node.up)
+	case ruleUnionType:
+		union := UnionExpression{}
+		for _, alt := range childNodes(node, ruleType) {
+			union.Alternatives = append(union.Alternatives, b.typeExpr(alt))
+		}
+		return union
+	case ruleAttributedType:
+		attributed := AttributedExpression{}
+		for child := node.up; child != nil; child = child.next {
+			if child.pegRule == ruleAttribute {
+				attributed.Attributes = append(attributed.Attributes, b.attributes(child)...)
+			} else if child.pegRule != rule_ {
+				attributed.Type = b.typeExpr(child)
+			}
+		}
+		return attributed
+	case ruleArrayType:
+		// The range of a typed array like byte @ 0..1 [] @ 4 comes before
+		// its brackets and its length after them
+		array := ArrayExpression{}
+		closed := false
+		for child := node.up; child != nil; child = child.next {
+			switch child.pegRule {
+			case ruleType, ruleReferenceType:
+				array.Element = b.typeExpr(child)
+			case rulePrimitiveType:
+				array.Element = b.typeExpr(child)
+				array.Typed = array.Element.(PrimitiveExpression).Name
+			case ruleRBRACKET:
+				closed = true
+			case ruleArrayConstraint:
+				constraint := b.constraint(child)
+				if closed {
+					array.Length = &constraint
+				} else {
+					array.Element = ConstrainedExpression{Type: array.Element, Range: constraint}
+				}
+			}
+		}
+		return array
+	case ruleStructType:
+		structExpr := b.structBody(node)
+		if ident := childNode(node, ruleIdentifier); ident != nil {
+			structExpr.Name = &Identifier{Name: b.token(ident)}
+			structExpr.Doc = b.docBefore(node.begin)
+		}
+		return structExpr
+	case ruleConstrainedType:
+		return ConstrainedExpression{
+			Type:  b.typeExpr(node.up),
+			Range: b.constraint(childNode(node, ruleArrayConstraint)),
+		}
+	case ruleGenericType:
+		generic := GenericExpression{
+			Base: Path{Segments: []PathSegment{{Value: b.token(childNode(node, ruleIdentifier))}}},
+		}
+		for _, arg := range childNodes(childNode(node, ruleGenericTypeParams), ruleType) {
+			generic.Args = append(generic.Args, b.typeExpr(arg))
+		}
+		return generic
+	case rulePrimitiveType:
+		// The rule consumes the whitespace and comments after the name
+		return PrimitiveExpression{Name: strings.FieldsFunc(b.text(node), func(r rune) bool { return r < 'a' || r > 'z' })[0]}
+	case ruleReferenceType:
+		return b.typeExpr(node.up)
+	case ruleComplexReference:
+		return b.complexReference(node)
+	case rulePath:
+		return b.path(node)
+	case ruleIdentifier:
+		return Path{Segments: []PathSegment{{Value: b.token(node)}}}
+	case ruleLiteralType:
+		return b.literal(node.up)
+	}
+	return b.literal(node)
+}
+
+func (b *astBuilder) complexReference(node *node32) DispatchExpression {
+	dispatch := DispatchExpression{
+		Registry: b.registry(node),
+		Key:      b.indexKey(childNode(node, ruleComplexRefParam)),
+		Dynamic:  len(childNodes(node, ruleLBRACKET)) > 1,
+	}
+	if params := childNode(node, ruleGenericTypeParams); params != nil {
+		for _, arg := range childNodes(params, ruleType) {
+			dispatch.Args = append(dispatch.Args, b.typeExpr(arg))
+		}
+	}
+	return dispatch
+}
+
+func (b *astBuilder) literal(node *node32) Expression {
+	switch node.pegRule {
+	case ruleString:
+		return StringLiteral{Value: b.stringValue(node)}
+	case ruleNumber:
+		return NumberLiteral{Value: b.token(node)}
+	case ruleBoolean:
+		return BooleanLiteral{Value: b.token(node) == "true"}
+	}
+	return Identifier{Name: b.token(node)}
+}
+
+// constraint builds a range from an ArrayConstraint node like @ 0..10 or @ 4
+func (b *astBuilder) constraint(node *node32) RangeExpression {
+	if number := childNode(node, ruleNumber); number != nil {
+		value := b.token(number)
+		return RangeExpression{Min: &value, Max: &value}
+	}
+
+	rangeExpr := RangeExpression{}
+	seenOperator := false
+	for child := childNode(node, ruleRange).up; child != nil; child = child.next {
+		switch child.pegRule {
+		case ruleNumber:
+			value := b.token(child)
+			if seenOperator {
+				rangeExpr.Max = &value
+			} else {
+				rangeExpr.Min = &value
+			}
+		case ruleRangeOperator:
+			seenOperator = true
+			operator := strings.Join(strings.Fields(b.text(child)), "")
+			rangeExpr.MinExclusive = strings.HasPrefix(operator, "<")
+			rangeExpr.MaxExclusive = strings.HasSuffix(operator, "<")
+		}
+	}
+	return rangeExpr
+}
+
+// attributes builds the attributes of an Attribute node like #[since="1.17"]
+func (b *astBuilder) attributes(node *node32) []Attribute {
+	var attributes []Attribute
+	for _, item := range childNodes(childNode(node, ruleAttributeList), ruleAttributeItem) {
+		child := item.up
+		attr := Attribute{}
+		switch child.pegRule {
+		case ruleIdentifier:
+			attr.Name = b.token(child)
+		case ruleAttributePair:
+			attr.Name = b.token(childNode(child, ruleIdentifier))
+			attr.Value = b.attributeValue(childNode(child, ruleAttributeValue))
+		case ruleAttributeCall, ruleAttributeCallWithEquals:
+			attr.Name = b.token(childNode(child, ruleIdentifier))
+			attr.Value = b.attributeTree(childNode(child, ruleAttributeParamList))
+		}
+		attributes = append(attributes, attr)
+	}
+	return attributes
+}
+
+func (b *astBuilder) attributeTree(node *node32) AttributeTree {
+	tree := AttributeTree{}
+	if node == nil {
+		return tree
+	}
+	for _, param := range childNodes(node, ruleAttributeParam) {
+		child := param.up
+		if child.pegRule == ruleAttributePair {
+			tree.Named = append(tree.Named, AttributeArg{
+				Name:  b.token(childNode(child, ruleIdentifier)),
+				Value: b.attributeValue(childNode(child, ruleAttributeValue)),
+			})
+		} else {
+			tree.Positional = append(tree.Positional, b.attributeValue(child))
+		}
+	}
+	return tree
+}
+
+func (b *astBuilder) attributeValue(node *node32) Expression {
+	child := node.up
+	switch child.pegRule {
+	case ruleArrayLiteral:
+		array := ArrayLiteral{}
+		for _, value := range childNodes(child, ruleAttributeValue) {
+			array.Values = append(array.Values, b.attributeValue(value))
+		}
+		return array
+	case ruleComplexReference:
+		return b.complexReference(child)
+	}
+	return b.literal(child)
+}
@@ -0,0 +1,202 @@
+package mcheck
+
+import (
+	"testing"
+)
+
+// parseStatements parses mcdoc source and builds its statements
+func parseStatements(t *testing.T, input string) []Statement {
+	t.Helper()
+
+	parser := &MCDocParser{
+		Buffer: input,
+		Pretty: true,
+	}
+	if err := parser.Init(); err != nil {
+		t.Fatalf("Failed to initialize parser: %v", err)
+	}
+	if err := parser.Parse(); err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	parser.BuildStatements()
+	return parser.Statements
+}
+
+func TestBuildStatementsStruct(t *testing.T) {
+	statements := parseStatements(t, `use super::Other
+
+/// A test struct
+#[since="1.17"]
+struct Test {
+	/// The name
+	name: string,
+	#[until="1.18"]
+	count?: int @ 0..<10,
+	kind: (#[until="1.17"] "a" | "b"),
+	items: [Other] @ 1..,
+	...super::Base,
+	[#[id="item"] string]: int,
+}`)
+
+	if len(statements) != 2 {
+		t.Fatalf("Expected 2 statements, got %d", len(statements))
+	}
+
+	structStmt, ok := statements[1].(StructStatement)
+	if !ok {
+		t.Fatalf("Expected StructStatement, got %T", statements[1])
+	}
+	if structStmt.Name.Name != "Test" || structStmt.Doc != "A test struct" {
+		t.Errorf("Unexpected struct name %q or doc %q", structStmt.Name.Name, structStmt.Doc)
+	}
+	if len(structStmt.Attributes) != 1 || attributeString(structStmt.Attributes[0].Value) != "1.17" {
+		t.Errorf("Expected since attribute, got %v", structStmt.Attributes)
+	}
+
+	fields := structStmt.Struct.Fields
+	if len(fields) != 6 {
+		t.Fatalf("Expected 6 fields, got %d", len(fields))
+	}
+
+	expected := []string{
+		"name: string",
+		"count?: int @ 0..<10",
+		`kind: (#[until="1.17"] "a" | "b")`,
+		"items: [Other] @ 1..",
+		"...super::Base",
+		`[#[id="item"] string]: int`,
+	}
+	for i, field := range fields {
+		if field.String() != expected[i] {
+			t.Errorf("Field %d: expected %q, got %q", i, expected[i], field.String())
+		}
+	}
+
+	if fields[0].Doc != "The name" {
+		t.Errorf("Expected field doc 'The name', got %q", fields[0].Doc)
+	}
+	if len(fields[1].Attributes) != 1 || fields[1].Attributes[0].Name != "until" {
+		t.Errorf("Expected until attribute on count, got %v", fields[1].Attributes)
+	}
+}
+
+func TestBuildStatementsDispatchEnumAlias(t *testing.T) {
+	statements := parseStatements(t, `enum(string) Kind { A = "a", B = "b" }
+type Provider<T> = (T | struct { value: T })
+dispatch minecraft:resource["worldgen/thing", other] to struct Thing {
+	type: string,
+	config: minecraft:thing_config[[type]],
+	id: #[id(registry="item", tags="allowed")] string,
+}`)
+
+	if len(statements) != 3 {
+		t.Fatalf("Expected 3 statements, got %d", len(statements))
+	}
+
+	enumStmt := statements[0].(EnumStatement)
+	if enumStmt.Type != "string" || len(enumStmt.Values) != 2 || enumStmt.Values[1].Value.(StringLiteral).Value != "b" {
+		t.Errorf("Unexpected enum %+v", enumStmt)
+	}
+
+	aliasStmt := statements[1].(TypeAliasStatement)
+	if aliasStmt.Name.Name != "Provider" || len(aliasStmt.TypeParams) != 1 || aliasStmt.TypeParams[0] != "T" {
+		t.Errorf("Unexpected alias %+v", aliasStmt)
+	}
+
+	dispatchStmt := statements[2].(DispatchStatement)
+	if dispatchStmt.Registry != "minecraft:resource" {
+		t.Errorf("Expected registry minecraft:resource, got %s", dispatchStmt.Registry)
+	}
+	if len(dispatchStmt.Keys) != 2 || dispatchStmt.Keys[0] != "worldgen/thing" || dispatchStmt.Keys[1] != "other" {
+		t.Errorf("Unexpected dispatch keys %v", dispatchStmt.Keys)
+	}
+
+	target := dispatchStmt.Target.(StructExpression)
+	config := target.Fields[1].Type.(DispatchExpression)
+	if config.Registry != "minecraft:thing_config" || config.Key != "type" || !config.Dynamic {
+		t.Errorf("Unexpected dispatch reference %+v", config)
+	}
+
+	id := target.Fields[2].Type.(AttributedExpression)
+	tree, ok := id.Attributes[0].Value.(AttributeTree)
+	if !ok || len(tree.Named) != 2 || tree.Named[1].Name != "tags" {
+		t.Errorf("Expected attribute tree with 2 named args, got %v", id.Attributes[0].Value)
+	}
+}
+
+func TestBuildStatementsInjectAndUseAlias(t *testing.T) {
+	statements := parseStatements(t, `use ::java::util::text::Text as Component
+
+#[since="1.21"]
+inject struct super::item::ItemStack {
+	components?: Components,
+	...super::Shared,
+}
+
+inject enum(string) ::java::data::Kind {
+	C = "c",
+}`)
+
+	if len(statements) != 3 {
+		t.Fatalf("Expected 3 statements, got %d", len(statements))
+	}
+
+	use := statements[0].(UseStatement)
+	if use.Alias != "Component" || use.Name() != "Component" || use.Path.String() != "::java::util::text::Text" {
+		t.Errorf("Unexpected use statement %+v", use)
+	}
+
+	injectStruct := statements[1].(InjectStatement)
+	if injectStruct.Enum || injectStruct.Path.String() != "super::item::ItemStack" || len(injectStruct.Attributes) != 1 {
+		t.Errorf("Unexpected struct injection %+v", injectStruct)
+	}
+	if fields := injectStruct.Struct.Fields; len(fields) != 2 || fields[0].String() != "components?: Components" || !fields[1].Spread {
+		t.Errorf("Unexpected injected fields %v", fields)
+	}
+
+	injectEnum := statements[2].(InjectStatement)
+	if !injectEnum.Enum || injectEnum.Type != "string" || len(injectEnum.Values) != 1 || injectEnum.Values[0].Name != "C" {
+		t.Errorf("Unexpected enum injection %+v", injectEnum)
+	}
+}
+
+func TestBuildStatementsComments(t *testing.T) {
+	statements := parseStatements(t, `// Before the docs
+/// The docs
+#[since="1.20"]
+struct Test {
+	// Leading
+	/// Field docs
+	name: string, // trailing
+	count: int,
+	// At the end
+} // after
+
+enum(string) E {
+	A = "a", // a
+	// No more values
+}`)
+
+	if len(statements) != 2 {
+		t.Fatalf("Expected 2 statements, got %d", len(statements))
+	}
+	test := statements[0].(StructStatement)
+	if test.Doc != "The docs" || len(test.Comments) != 1 || test.Comments[0] != "// Before the docs" || test.LineComment != "// after" {
+		t.Errorf("Unexpected struct comments %q, doc %q, line comment %q", test.Comments, test.Doc, test.LineComment)
+	}
+	name := test.Struct.Fields[0]
+	if name.Doc != "Field docs" || len(name.Comments) != 1 || name.Comments[0] != "// Leading" || name.LineComment != "// trailing" {
+		t.Errorf("Unexpected field comments %q, doc %q, line comment %q", name.Comments, name.Doc, name.LineComment)
+	}
+	if count := test.Struct.Fields[1]; len(count.Comments) != 0 || count.LineComment != "" {
+		t.Errorf("Expected no comments on count, got %q %q", count.Comments, count.LineComment)
+	}
+	if end := test.Struct.EndComments; len(end) != 1 || end[0] != "// At the end" {
+		t.Errorf("Unexpected end comments %q", end)
+	}
+
+	enum := statements[1].(EnumStatement)
+	if enum.Values[0].LineComment != "// a" || len(enum.EndComments) != 1 || enum.EndComments[0] != "// No more values" {
+		t.Errorf("Unexpected enum comments %q %q", enum.Values[0].LineComment, enum.EndComments)
+	}
+}
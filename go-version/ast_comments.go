@@ -0,0 +1,119 @@
+package mcheck
+
+import "strings"
+
+// Comments are kept in the syntax tree so that mcheck fmt can write them
+// back. Each comment belongs to the statement, field or enum value it
+// precedes or is inside of, to the one whose last line it ends, or to the
+// end of the struct, enum or file it closes. Doc comments directly before a
+// declaration are its Doc rather than comments.
+
+// sourceCommentToken is a // or /// comment in the source being built
+type sourceCommentToken struct {
+	begin, end uint32 // rune offsets, end before the line break
+	text       string // the comment with its slashes, like "// TODO"
+	ownLine    bool   // nothing but whitespace precedes it on its line
+	claimed    bool   // attached to a declaration already
+}
+
+// collectComments finds the comments of the syntax tree, in order
+func (b *astBuilder) collectComments(root *node32) {
+	var walk func(node *node32)
+	walk = func(node *node32) {
+		for ; node != nil; node = node.next {
+			if node.pegRule != ruleComment {
+				walk(node.up)
+				continue
+			}
+			text := strings.TrimRight(b.text(node), "\r\n")
+			comment := &sourceCommentToken{begin: node.begin, end: node.begin + uint32(len([]rune(text))), text: text, ownLine: true}
+			for i := int(node.begin) - 1; i >= 0 && b.buffer[i] != '\n'; i-- {
+				if !isSpace(b.buffer[i]) {
+					comment.ownLine = false
+					break
+				}
+			}
+			b.comments = append(b.comments, comment)
+		}
+	}
+	walk(root)
+}
+
+// leadingComments claims the comments before pos, the start of a
+// declaration after its attributes, leaving out the doc comment block
+// directly before start, the start of its attributes, which is doc
+func (b *astBuilder) leadingComments(pos, start uint32, doc string) []string {
+	docLines := 0
+	if doc != "" {
+		docLines = strings.Count(doc, "\n") + 1
+	}
+	var before []*sourceCommentToken
+	for _, comment := range b.comments {
+		if !comment.claimed && comment.begin < pos {
+			before = append(before, comment)
+		}
+	}
+
+	// The doc block is the last comments before the attributes
+	attributesAt := 0
+	for attributesAt < len(before) && before[attributesAt].begin < start {
+		attributesAt++
+	}
+	docStart := max(attributesAt-docLines, 0)
+	var comments []string
+	for i, comment := range before {
+		comment.claimed = true
+		if i < docStart || i >= attributesAt {
+			comments = append(comments, comment.text)
+		}
+	}
+	return comments
+}
+
+// commentsBefore claims the comments before pos, like those inside a
+// declaration or before the brace closing a struct
+func (b *astBuilder) commentsBefore(pos uint32) []string {
+	var comments []string
+	for _, comment := range b.comments {
+		if !comment.claimed && comment.begin < pos {
+			comment.claimed = true
+			comments = append(comments, comment.text)
+		}
+	}
+	return comments
+}
+
+// lineComment claims the comment after node on the line its code ends on,
+// like the // note of x: int, // note
+func (b *astBuilder) lineComment(node *node32) string {
+	end := b.codeEnd(node)
+	line := b.pos(end - 1).Line
+	for _, comment := range b.comments {
+		if !comment.claimed && !comment.ownLine && comment.begin >= end && b.pos(comment.begin).Line == line {
+			comment.claimed = true
+			return comment.text
+		}
+	}
+	return ""
+}
+
+// codeEnd returns the end of the code of node, before the whitespace and
+// comments its last token consumes
+func (b *astBuilder) codeEnd(node *node32) uint32 {
+	pos := node.end
+	for {
+		for pos > node.begin && (isSpace(b.buffer[pos-1]) || b.buffer[pos-1] == '\n') {
+			pos--
+		}
+		found := false
+		for _, comment := range b.comments {
+			if comment.end == pos && comment.begin >= node.begin {
+				pos, found = comment.begin, true
+				break
+			}
+		}
+		if !found {
+			return pos
+		}
+	}
+}
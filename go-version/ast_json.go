@@ -0,0 +1,342 @@
+package mcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ParseFormats are the output formats of mcheck parse
+var ParseFormats = []string{"text", "json"}
+
+// ParseMCDocFile parses a schema file on its own, without the schema
+// directory it may belong to. Unlike validation it does not skip statements
+// that do not parse: the first syntax error fails the file.
+func ParseMCDocFile(path string) ([]Statement, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	parser, err := parseMCDocSource(string(content), path)
+	if err != nil {
+		return nil, err
+	}
+	return parser.Statements, nil
+}
+
+// MarshalStatements encodes parsed statements as a JSON array. Every
+// statement and expression is an object whose "kind" names its type, like
+// "struct" or "union", followed by its parts. Empty parts are left out.
+func MarshalStatements(statements []Statement) ([]byte, error) {
+	nodes := make([]interface{}, len(statements))
+	for i, stmt := range statements {
+		nodes[i] = statementNode(stmt)
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(nodes); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// FormatStatementsText describes parsed statements one per line, in an
+// mcdoc-like form built from the String methods of their expressions
+func FormatStatementsText(statements []Statement) string {
+	var b strings.Builder
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case UseStatement:
+			if s.Alias != "" {
+				fmt.Fprintf(&b, "use %s as %s\n", s.Path, s.Alias)
+			} else {
+				fmt.Fprintf(&b, "use %s\n", s.Path)
+			}
+		case TypeAliasStatement:
+			name := s.Name.Name
+			if len(s.TypeParams) > 0 {
+				name += "<" + strings.Join(s.TypeParams, ", ") + ">"
+			}
+			fmt.Fprintf(&b, "%stype %s = %s\n", attributesPrefix(s.Attributes), name, s.Type)
+		case StructStatement:
+			fmt.Fprintf(&b, "%s%s\n", attributesPrefix(s.Attributes), s.Struct)
+		case EnumStatement:
+			values := make([]string, len(s.Values))
+			for i, value := range s.Values {
+				values[i] = value.String()
+			}
+			fmt.Fprintf(&b, "%senum(%s) %s { %s }\n", attributesPrefix(s.Attributes), s.Type, s.Name.Name, strings.Join(values, ", "))
+		case DispatchStatement:
+			fmt.Fprintf(&b, "%sdispatch %s[%s] to %s\n", attributesPrefix(s.Attributes), s.Registry, strings.Join(s.Keys, ", "), s.Target)
+		case InjectStatement:
+			if s.Enum {
+				values := make([]string, len(s.Values))
+				for i, value := range s.Values {
+					values[i] = value.String()
+				}
+				fmt.Fprintf(&b, "inject enum(%s) %s { %s }\n", s.Type, s.Path, strings.Join(values, ", "))
+			} else {
+				fmt.Fprintf(&b, "inject %s %s\n", s.Path, strings.TrimPrefix(s.Struct.String(), "struct "))
+			}
+		}
+	}
+	return b.String()
+}
+
+// attributesPrefix writes attributes like #[since="1.17"] followed by a space
+func attributesPrefix(attributes []Attribute) string {
+	var b strings.Builder
+	for _, attr := range attributes {
+		b.WriteString(attr.String() + " ")
+	}
+	return b.String()
+}
+
+// astNode is a JSON object that keeps its keys in the order they were set,
+// so that "kind" comes first
+type astNode struct {
+	keys   []string
+	values []interface{}
+}
+
+func newASTNode(kind string) *astNode {
+	return (&astNode{}).put("kind", kind)
+}
+
+// set adds a key unless its value is empty, like "", false or an empty list
+func (n *astNode) set(key string, value interface{}) *astNode {
+	switch v := value.(type) {
+	case nil:
+		return n
+	case string:
+		if v == "" {
+			return n
+		}
+	case bool:
+		if !v {
+			return n
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return n
+		}
+	case []string:
+		if len(v) == 0 {
+			return n
+		}
+	case *astNode:
+		if v == nil {
+			return n
+		}
+	}
+	return n.put(key, value)
+}
+
+// put adds a key even if its value is empty, like a literal false or ""
+func (n *astNode) put(key string, value interface{}) *astNode {
+	n.keys = append(n.keys, key)
+	n.values = append(n.values, value)
+	return n
+}
+
+func (n *astNode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range n.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		// Ranges like 0..<10 keep their < unescaped
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(key)
+		buf.Truncate(buf.Len() - 1)
+		buf.WriteByte(':')
+		if err := encoder.Encode(n.values[i]); err != nil {
+			return nil, err
+		}
+		buf.Truncate(buf.Len() - 1)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func statementNode(stmt Statement) *astNode {
+	switch s := stmt.(type) {
+	case UseStatement:
+		return newASTNode("use").
+			set("path", s.Path.String()).
+			set("alias", s.Alias).
+			set("comments", s.Comments).
+			set("line_comment", s.LineComment)
+	case TypeAliasStatement:
+		return newASTNode("type").
+			set("name", s.Name.Name).
+			set("doc", s.Doc).
+			set("attributes", attributeNodes(s.Attributes)).
+			set("type_params", s.TypeParams).
+			set("type", expressionNode(s.Type)).
+			set("comments", s.Comments).
+			set("line_comment", s.LineComment)
+	case StructStatement:
+		return newASTNode("struct").
+			set("name", s.Name.Name).
+			set("doc", s.Doc).
+			set("attributes", attributeNodes(s.Attributes)).
+			set("pos", posString(s.Struct.Pos)).
+			set("fields", fieldNodes(s.Struct.Fields)).
+			set("comments", s.Comments).
+			set("line_comment", s.LineComment).
+			set("end_comments", s.Struct.EndComments)
+	case EnumStatement:
+		return newASTNode("enum").
+			set("name", s.Name.Name).
+			set("doc", s.Doc).
+			set("attributes", attributeNodes(s.Attributes)).
+			set("type", s.Type).
+			set("values", enumValueNodes(s.Values)).
+			set("comments", s.Comments).
+			set("line_comment", s.LineComment).
+			set("end_comments", s.EndComments)
+	case DispatchStatement:
+		return newASTNode("dispatch").
+			set("registry", s.Registry).
+			set("keys", s.Keys).
+			set("type_params", s.TypeParams).
+			set("doc", s.Doc).
+			set("attributes", attributeNodes(s.Attributes)).
+			set("target", expressionNode(s.Target)).
+			set("comments", s.Comments).
+			set("line_comment", s.LineComment)
+	case InjectStatement:
+		node := newASTNode("inject").
+			set("path", s.Path.String()).
+			set("doc", s.Doc).
+			set("attributes", attributeNodes(s.Attributes)).
+			set("comments", s.Comments).
+			set("line_comment", s.LineComment)
+		if s.Enum {
+			return node.set("enum", true).set("type", s.Type).set("values", enumValueNodes(s.Values)).set("end_comments", s.EndComments)
+		}
+		return node.set("fields", fieldNodes(s.Struct.Fields)).set("end_comments", s.Struct.EndComments)
+	}
+	return newASTNode(fmt.Sprintf("%T", stmt))
+}
+
+func expressionNode(expr Expression) *astNode {
+	switch e := expr.(type) {
+	case nil:
+		return nil
+	case PrimitiveExpression:
+		return newASTNode("primitive").set("name", e.Name)
+	case Path:
+		return newASTNode("reference").set("path", e.String())
+	case Identifier:
+		return newASTNode("identifier").set("name", e.Name)
+	case StringLiteral:
+		return newASTNode("string").put("value", e.Value)
+	case NumberLiteral:
+		return newASTNode("number").set("value", e.Value)
+	case BooleanLiteral:
+		return newASTNode("boolean").put("value", e.Value)
+	case StructExpression:
+		node := newASTNode("struct")
+		if e.Name != nil {
+			node.set("name", e.Name.Name)
+		}
+		return node.set("doc", e.Doc).set("pos", posString(e.Pos)).set("fields", fieldNodes(e.Fields)).set("end_comments", e.EndComments)
+	case ArrayExpression:
+		node := newASTNode("array").set("typed", e.Typed).set("element", expressionNode(e.Element))
+		if e.Length != nil {
+			node.set("length", e.Length.String())
+		}
+		return node
+	case ConstrainedExpression:
+		return newASTNode("constrained").set("type", expressionNode(e.Type)).set("range", e.Range.String())
+	case UnionExpression:
+		return newASTNode("union").set("alternatives", expressionNodes(e.Alternatives))
+	case GenericExpression:
+		return newASTNode("generic").set("base", expressionNode(e.Base)).set("args", expressionNodes(e.Args))
+	case DispatchExpression:
+		return newASTNode("dispatch").
+			set("registry", e.Registry).
+			set("key", e.Key).
+			set("dynamic", e.Dynamic).
+			set("args", expressionNodes(e.Args))
+	case AttributedExpression:
+		return newASTNode("attributed").set("attributes", attributeNodes(e.Attributes)).set("type", expressionNode(e.Type))
+	case AttributeTree:
+		node := newASTNode("tree").set("positional", expressionNodes(e.Positional))
+		var named []interface{}
+		for _, arg := range e.Named {
+			named = append(named, newASTNode("arg").set("name", arg.Name).set("value", expressionNode(arg.Value)))
+		}
+		return node.set("named", named)
+	case ArrayLiteral:
+		return newASTNode("list").set("values", expressionNodes(e.Values))
+	}
+	return newASTNode(fmt.Sprintf("%T", expr)).set("text", expr.String())
+}
+
+func expressionNodes(exprs []Expression) []interface{} {
+	nodes := make([]interface{}, len(exprs))
+	for i, expr := range exprs {
+		nodes[i] = expressionNode(expr)
+	}
+	return nodes
+}
+
+func fieldNodes(fields []FieldExpression) []interface{} {
+	nodes := make([]interface{}, len(fields))
+	for i, field := range fields {
+		kind := "field"
+		if field.Spread {
+			kind = "spread"
+		}
+		nodes[i] = newASTNode(kind).
+			set("name", field.Name.Name).
+			set("key", expressionNode(field.Key)).
+			set("optional", field.Optional).
+			set("doc", field.Doc).
+			set("attributes", attributeNodes(field.Attributes)).
+			set("pos", posString(field.Pos)).
+			set("type", expressionNode(field.Type)).
+			set("comments", field.Comments).
+			set("line_comment", field.LineComment)
+	}
+	return nodes
+}
+
+func attributeNodes(attributes []Attribute) []interface{} {
+	nodes := make([]interface{}, len(attributes))
+	for i, attr := range attributes {
+		nodes[i] = newASTNode("attribute").set("name", attr.Name).set("value", expressionNode(attr.Value))
+	}
+	return nodes
+}
+
+func enumValueNodes(values []EnumValueExpression) []interface{} {
+	nodes := make([]interface{}, len(values))
+	for i, value := range values {
+		nodes[i] = newASTNode("enum_value").
+			set("name", value.Name).
+			set("doc", value.Doc).
+			set("attributes", attributeNodes(value.Attributes)).
+			set("value", expressionNode(value.Value)).
+			set("comments", value.Comments).
+			set("line_comment", value.LineComment)
+	}
+	return nodes
+}
+
+// posString writes a known position like worldgen/biome.mcdoc:42, or ""
+func posString(pos SourcePos) string {
+	if pos.Line == 0 {
+		return ""
+	}
+	return pos.String()
+}
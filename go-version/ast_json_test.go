@@ -0,0 +1,98 @@
+package mcheck
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMarshalStatements(t *testing.T) {
+	statements := parseStatements(t, `use super::Other as O
+
+/// A test struct
+#[since="1.17"]
+struct Test {
+	count?: int @ 0..<10,
+	kind: ("a" | ""),
+	owner: #[uuid] int[] @ 4,
+	...O,
+	[#[id(registry="item", tags=allowed)] string]: boolean,
+}
+
+dispatch minecraft:resource[test] to Test
+`)
+	output, err := MarshalStatements(statements)
+	if err != nil {
+		t.Fatalf("MarshalStatements failed: %v", err)
+	}
+	if !strings.HasPrefix(string(output), "[\n  {\n    \"kind\": \"use\",") {
+		t.Errorf("Expected the kind of each node first, got %s", output)
+	}
+	if !strings.Contains(string(output), `"range": "0..<10"`) {
+		t.Errorf("Expected ranges to stay unescaped, got %s", output)
+	}
+
+	var nodes []map[string]interface{}
+	if err := json.Unmarshal(output, &nodes); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, output)
+	}
+	if len(nodes) != 3 {
+		t.Fatalf("Expected 3 statements, got %d", len(nodes))
+	}
+	if nodes[0]["path"] != "super::Other" || nodes[0]["alias"] != "O" {
+		t.Errorf("Unexpected use statement %v", nodes[0])
+	}
+	if nodes[1]["kind"] != "struct" || nodes[1]["doc"] != "A test struct" {
+		t.Errorf("Unexpected struct statement %v", nodes[1])
+	}
+
+	fields := nodes[1]["fields"].([]interface{})
+	kinds := make([]string, len(fields))
+	for i, field := range fields {
+		field := field.(map[string]interface{})
+		kinds[i] = field["kind"].(string) + ":" + field["type"].(map[string]interface{})["kind"].(string)
+	}
+	expected := "field:constrained field:union field:attributed spread:reference field:primitive"
+	if strings.Join(kinds, " ") != expected {
+		t.Errorf("Expected fields %s, got %s", expected, strings.Join(kinds, " "))
+	}
+
+	// Empty string literals are values, not parts left out
+	union := fields[1].(map[string]interface{})["type"].(map[string]interface{})
+	empty := union["alternatives"].([]interface{})[1].(map[string]interface{})
+	if value, ok := empty["value"]; !ok || value != "" {
+		t.Errorf("Expected an empty string literal, got %v", empty)
+	}
+
+	array := fields[2].(map[string]interface{})["type"].(map[string]interface{})["type"].(map[string]interface{})
+	if array["typed"] != "int" || array["length"] != "4" {
+		t.Errorf("Expected a typed int array of length 4, got %v", array)
+	}
+	key := fields[4].(map[string]interface{})["key"].(map[string]interface{})
+	attribute := key["attributes"].([]interface{})[0].(map[string]interface{})
+	if attribute["value"].(map[string]interface{})["kind"] != "tree" {
+		t.Errorf("Expected the id attribute arguments as a tree, got %v", attribute)
+	}
+}
+
+func TestParseMCDocFile(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.mcdoc")
+	os.WriteFile(good, []byte("struct Test {\n\tname: string,\n}\n"), 0644)
+	bad := filepath.Join(dir, "bad.mcdoc")
+	os.WriteFile(bad, []byte("struct Test {\n\tname string,\n}\n"), 0644)
+
+	statements, err := ParseMCDocFile(good)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", good, err)
+	}
+	if text := FormatStatementsText(statements); text != "struct Test { name: string }\n" {
+		t.Errorf("Unexpected text %q", text)
+	}
+
+	if _, err := ParseMCDocFile(bad); err == nil || !strings.Contains(err.Error(), "bad.mcdoc:2") {
+		t.Errorf("Expected a syntax error at bad.mcdoc:2, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package mcheck
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// AttributeArgs are the arguments of an attribute: the value of
+// #[color="hex_rgb"], or the positional and named arguments of
+// #[id(registry="item", exclude=["air"], tags=allowed)]. List arguments have
+// a value per element, others a single value.
+type AttributeArgs struct {
+	Positional []string
+	Named      map[string][]string
+}
+
+// Value returns the first positional argument, like hex_rgb in
+// #[color="hex_rgb"], or ""
+func (a AttributeArgs) Value() string {
+	if len(a.Positional) == 0 {
+		return ""
+	}
+	return a.Positional[0]
+}
+
+// Get returns a named argument, like allowed for tags=allowed, or ""
+func (a AttributeArgs) Get(name string) string {
+	if values := a.Named[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// attributeCheck validates a value that was accepted by the attributed type
+// against the meaning of an attribute like #[uuid] or #[color="hex_rgb"]
+type attributeCheck func(args AttributeArgs, value interface{}) error
+
+// attributeChecks are the attributes that constrain values, keyed by name.
+// Attributes not listed here are informational and accept any value.
+var attributeChecks = map[string]attributeCheck{
+	"uuid":            checkUUID,
+	"color":           checkColor,
+	"formatting_code": checkFormattingCodes,
+	"regex_pattern":   checkRegexPattern,
+	"id":              checkID,
+}
+
+// uuidPattern matches the forms java.util.UUID.fromString accepts, which
+// allows short hex groups
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{1,8}-[0-9a-fA-F]{1,4}-[0-9a-fA-F]{1,4}-[0-9a-fA-F]{1,4}-[0-9a-fA-F]{1,12}$`)
+
+// checkUUID accepts hyphenated UUID strings. The int array form is checked
+// by its schema type, int[] @ 4.
+func checkUUID(args AttributeArgs, value interface{}) error {
+	if text, ok := value.(string); ok && !uuidPattern.MatchString(text) {
+		return fmt.Errorf("invalid UUID %q", text)
+	}
+	return nil
+}
+
+// namedColors are the text formatting colors
+var namedColors = []string{
+	"black", "dark_blue", "dark_green", "dark_aqua", "dark_red", "dark_purple", "gold", "gray",
+	"dark_gray", "blue", "green", "aqua", "red", "light_purple", "yellow", "white",
+}
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// checkColor validates colors by their #[color] format: hex_rgb strings like
+// #RRGGBB, packed composite_rgb/dec_rgb integers, composite_argb integers and
+// named formatting colors. Lists of colors are checked element by element.
+func checkColor(args AttributeArgs, value interface{}) error {
+	argument := args.Value()
+	if list, ok := value.([]interface{}); ok {
+		for _, element := range list {
+			if err := checkColor(args, element); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		if hexColorPattern.MatchString(v) {
+			return nil
+		}
+		if argument == "named" || argument == "" {
+			for _, name := range namedColors {
+				if v == name {
+					return nil
+				}
+			}
+			return fmt.Errorf("invalid color %q, expected #RRGGBB or one of %s", v, strings.Join(namedColors, ", "))
+		}
+		return fmt.Errorf("invalid hex color %q, expected #RRGGBB", v)
+	case float64:
+		switch argument {
+		case "composite_argb":
+			if !isInt32(v) && !(v == math.Trunc(v) && v >= 0 && v <= math.MaxUint32) {
+				return fmt.Errorf("invalid ARGB color %v", v)
+			}
+		default:
+			if v != math.Trunc(v) || v < 0 || v > 0xFFFFFF {
+				return fmt.Errorf("invalid RGB color %v, expected an integer between 0 and %d", v, 0xFFFFFF)
+			}
+		}
+	}
+	return nil
+}
+
+// formattingCodes are the characters that may follow § in legacy formatted text
+const formattingCodes = "0123456789abcdefklmnorABCDEFKLMNOR"
+
+// checkFormattingCodes checks that every § in a string starts a valid legacy
+// formatting code
+func checkFormattingCodes(args AttributeArgs, value interface{}) error {
+	text, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	runes := []rune(text)
+	for i, r := range runes {
+		if r != '§' {
+			continue
+		}
+		if i+1 >= len(runes) || !strings.ContainsRune(formattingCodes, runes[i+1]) {
+			return fmt.Errorf("invalid formatting code at offset %d in %q", i, text)
+		}
+	}
+	return nil
+}
+
+// checkRegexPattern checks that a string is itself a valid regular expression.
+// The game uses Java regexes, so syntax RE2 lacks, like lookarounds and
+// backreferences, is accepted unchecked.
+func checkRegexPattern(args AttributeArgs, value interface{}) error {
+	text, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	if _, err := regexp.Compile(text); err != nil {
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) && (syntaxErr.Code == syntax.ErrInvalidPerlOp || syntaxErr.Code == syntax.ErrInvalidEscape) {
+			return nil
+		}
+		return fmt.Errorf("invalid regex %q: %v", text, err)
+	}
+	return nil
+}
+
+// idPattern matches resource locations like minecraft:oak_log, whose
+// namespace may be left out
+var idPattern = regexp.MustCompile(`^([a-z0-9_.-]+:)?[a-z0-9_./-]+$`)
+
+// checkID checks the resource locations of #[id] strings. Its registry
+// argument, or the value of #[id="item"], names what the id refers to. Tags,
+// written with a leading #, are only accepted with tags=allowed or
+// tags=required, and required with the latter; ids defining a resource, with
+// definition=true, are never tags. Ids listed in exclude are rejected.
+func checkID(args AttributeArgs, value interface{}) error {
+	text, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	registry := args.Get("registry")
+	if registry == "" {
+		registry = args.Value()
+	}
+	what := "id"
+	if registry != "" {
+		what = strings.TrimPrefix(registry, "minecraft:") + " id"
+	}
+
+	id, isTag := strings.CutPrefix(text, "#")
+	tags := args.Get("tags")
+	switch {
+	case isTag && args.Get("definition") == "true":
+		return fmt.Errorf("invalid %s %q, a definition cannot be a tag", what, text)
+	case isTag && tags != "allowed" && tags != "required":
+		return fmt.Errorf("invalid %s %q, tags are not allowed here", what, text)
+	case !isTag && tags == "required":
+		return fmt.Errorf("invalid %s %q, expected a tag starting with #", what, text)
+	}
+	if !idPattern.MatchString(id) {
+		return fmt.Errorf("invalid %s %q, expected a resource location like minecraft:name", what, text)
+	}
+
+	for _, excluded := range args.Named["exclude"] {
+		if normalizeID(excluded) == normalizeID(id) {
+			return fmt.Errorf("%s %s is not allowed here", what, text)
+		}
+	}
+	return nil
+}
+
+func isInt32(value float64) bool {
+	return value == math.Trunc(value) && value >= math.MinInt32 && value <= math.MaxInt32
+}
@@ -0,0 +1,195 @@
+package mcheck
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAttributeChecks(t *testing.T) {
+	_, ctx := convertSchema(t, "1.20.1", `struct Team {
+	owner: #[uuid] string,
+	owner_ints?: #[uuid] int[] @ 4,
+	hex: #[color="hex_rgb"] string,
+	packed: #[color="composite_rgb"] int,
+	argb?: #[color="composite_argb"] int,
+	named?: #[color="named"] string,
+	prefix?: #[formatting_code] string,
+	label?: #[id="item"] string,
+}`)
+	validator := ctx.Definitions["Team"]
+
+	valid := `"owner": "e7a2c4b0-1f3d-4b2a-9c6e-0d8f5a3b2c1d", "hex": "#ff8800", "packed": 16777215`
+	tests := []struct {
+		name     string
+		document string
+		valid    bool
+	}{
+		{"valid", `{` + valid + `}`, true},
+		{"short uuid groups", `{"owner": "1-2-3-4-5", "hex": "#000000", "packed": 0}`, true},
+		{"bad uuid", `{"owner": "not-a-uuid", "hex": "#000000", "packed": 0}`, false},
+		{"bad hex", `{"owner": "1-2-3-4-5", "hex": "#12345", "packed": 0}`, false},
+		{"rgb too large", `{"owner": "1-2-3-4-5", "hex": "#000000", "packed": 16777216}`, false},
+		{"negative argb", `{` + valid + `, "argb": -1}`, true},
+		{"named color", `{` + valid + `, "named": "dark_aqua"}`, true},
+		{"unknown named color", `{` + valid + `, "named": "turquoise"}`, false},
+		{"formatting codes", `{` + valid + `, "prefix": "§a[§lAdmin§r]"}`, true},
+		{"bad formatting code", `{` + valid + `, "prefix": "§z"}`, false},
+		{"trailing section sign", `{` + valid + `, "prefix": "oops§"}`, false},
+		{"informational attribute", `{` + valid + `, "label": "anything"}`, true},
+		{"uuid ints", `{` + valid + `, "owner_ints": [1, -2, 3, 2147483647]}`, true},
+		{"too few uuid ints", `{` + valid + `, "owner_ints": [1, 2, 3]}`, false},
+		{"uuid int out of range", `{` + valid + `, "owner_ints": [1, 2, 3, 2147483648]}`, false},
+		{"fractional uuid int", `{` + valid + `, "owner_ints": [1, 2, 3, 0.5]}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var document interface{}
+			if err := json.Unmarshal([]byte(tt.document), &document); err != nil {
+				t.Fatalf("Invalid test document: %v", err)
+			}
+			err := findingsError(validator.Validate(document, ctx))
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	// A value failing several attributes is reported by the first in order
+	// of their names
+	both := AttributedValidator{
+		InnerValidator: &PrimitiveValidator{Type: "string"},
+		Args:           map[string]AttributeArgs{"uuid": {}, "color": {Positional: []string{"hex_rgb"}}, "formatting_code": {}},
+	}
+	for i := 0; i < 10; i++ {
+		findings := both.Validate("§z", ctx)
+		if len(findings) != 1 || !strings.Contains(findings[0].Message, "invalid hex color") {
+			t.Fatalf("Expected the color to be reported, got %v", findings)
+		}
+	}
+}
+
+func TestTypedArrays(t *testing.T) {
+	_, ctx := convertSchema(t, "1.20.1", `struct Owned {
+	owner: #[uuid] int[] @ 4,
+	flags?: byte[] @ 1..3,
+	ids?: [int] @ 4,
+}`)
+	validator := ctx.Definitions["Owned"]
+
+	tests := []struct {
+		document string
+		expected string
+	}{
+		{`{"owner": [1, -2, 3, 2147483647]}`, ""},
+		{`{"owner": [1, 2, 3]}`, "at owner: int array must have 4 elements, got 3"},
+		{`{"owner": [1, 2, 3, -2147483649]}`, "at owner.[3]: value -2.147483649e+09 is out of range for int (-2147483648..2147483647)"},
+		{`{"owner": "1-2-3-4-5"}`, "at owner: expected int array, got string"},
+		{`{"owner": [1, 2, 3, 4], "flags": []}`, "at flags: flags must not be empty (length 1..3)"},
+		{`{"owner": [1, 2, 3, 4], "flags": [1, 2, 3, 4]}`, "at flags: byte array has 4 elements, expected 1..3"},
+		{`{"owner": [1, 2, 3, 4], "ids": [1]}`, "at ids: array length validation failed: at ids: value 1 must be greater than or equal to 4 (range 4)"},
+	}
+
+	for _, tt := range tests {
+		document, err := decodeJSON([]byte(tt.document))
+		if err != nil {
+			t.Fatalf("Invalid document %s: %v", tt.document, err)
+		}
+		var got []string
+		for _, finding := range validator.Validate(document, ctx) {
+			got = append(got, finding.text())
+		}
+		if strings.Join(got, "\n") != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.document, tt.expected, got)
+		}
+	}
+}
+
+func TestRegexAttributes(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", `struct Named {
+	key: #[regex="[a-z_]+"] string,
+	other?: #[regex="[a-z_]+"] string,
+	filter?: #[regex_pattern] string,
+}`)
+	validator := ctx.Definitions["Named"]
+
+	if len(converter.patterns) != 1 {
+		t.Errorf("Expected the shared pattern to be compiled once, got %d", len(converter.patterns))
+	}
+
+	tests := []struct {
+		name     string
+		document map[string]interface{}
+		valid    bool
+	}{
+		{"matching", map[string]interface{}{"key": "snake_case"}, true},
+		{"partial match", map[string]interface{}{"key": "Snake_case"}, false},
+		{"valid pattern", map[string]interface{}{"key": "a", "filter": "^stone_.*$"}, true},
+		{"java only syntax", map[string]interface{}{"key": "a", "filter": "foo(?=bar)"}, true},
+		{"invalid pattern", map[string]interface{}{"key": "a", "filter": "[unclosed"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := findingsError(validator.Validate(tt.document, ctx))
+			if (err == nil) != tt.valid {
+				t.Errorf("Expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+
+	converter = NewSchemaConverter(Version{1, 20, 1}, parseStatements(t, `type Broken = #[regex="(oops"] string`))
+	if _, err := converter.ConvertToValidators(); err == nil {
+		t.Error("Expected an error for an invalid regex attribute")
+	}
+}
+
+func TestIDAttribute(t *testing.T) {
+	_, ctx := convertSchema(t, "1.20.1", `struct Refs {
+	item?: #[id="item"] string,
+	block?: #[id(registry="block", exclude=["air", "minecraft:void_air"])] string,
+	biome?: #[id(registry="worldgen/biome", tags=allowed)] string,
+	tag?: #[id(registry="item", tags=required)] string,
+	name?: #[id(registry="worldgen/biome", definition=true)] string,
+	stone?: #[id=(registry="item", exclude=["stone"])] string,
+}`)
+	validator := ctx.Definitions["Refs"]
+
+	field, _ := findStructField(validator.(*StructValidator), "block", nil, ctx)
+	args := field.Validator.(*AttributedValidator).Args["id"]
+	if args.Get("registry") != "block" || strings.Join(args.Named["exclude"], " ") != "air minecraft:void_air" {
+		t.Errorf("Expected the registry and exclude list of #[id], got %+v", args)
+	}
+
+	tests := []struct {
+		document string
+		error    string
+	}{
+		{`{"item": "minecraft:stick", "block": "oak_log", "biome": "#minecraft:is_forest", "tag": "#logs", "name": "pack:my_biome"}`, ""},
+		{`{"item": "Minecraft:Stick"}`, `invalid item id "Minecraft:Stick", expected a resource location`},
+		{`{"item": "#minecraft:logs"}`, `invalid item id "#minecraft:logs", tags are not allowed here`},
+		{`{"block": "minecraft:air"}`, "block id minecraft:air is not allowed here"},
+		{`{"block": "void_air"}`, "block id void_air is not allowed here"},
+		{`{"tag": "minecraft:stick"}`, "expected a tag starting with #"},
+		{`{"name": "#pack:biomes"}`, "a definition cannot be a tag"},
+		{`{"stone": "minecraft:stone"}`, "item id minecraft:stone is not allowed here"},
+	}
+
+	for _, tt := range tests {
+		var document interface{}
+		if err := json.Unmarshal([]byte(tt.document), &document); err != nil {
+			t.Fatalf("Invalid test document: %v", err)
+		}
+		err := findingsError(validator.Validate(document, ctx))
+		switch {
+		case tt.error == "" && err != nil:
+			t.Errorf("Expected %s to be valid, got %v", tt.document, err)
+		case tt.error != "" && (err == nil || !strings.Contains(err.Error(), tt.error)):
+			t.Errorf("Expected %s to fail with %q, got %v", tt.document, tt.error, err)
+		}
+	}
+}
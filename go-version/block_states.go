@@ -0,0 +1,265 @@
+package mcheck
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stateProperty is a block state property and the values it takes
+type stateProperty struct {
+	name   string
+	values []string
+}
+
+func boolProperty(name string) stateProperty {
+	return stateProperty{name, []string{"true", "false"}}
+}
+
+func intProperty(name string, low, high int) stateProperty {
+	var values []string
+	for i := low; i <= high; i++ {
+		values = append(values, strconv.Itoa(i))
+	}
+	return stateProperty{name, values}
+}
+
+var (
+	axis          = stateProperty{"axis", []string{"x", "y", "z"}}
+	facing        = stateProperty{"facing", []string{"north", "south", "west", "east"}}
+	facingAll     = stateProperty{"facing", []string{"north", "east", "south", "west", "up", "down"}}
+	waterlogged   = boolProperty("waterlogged")
+	powered       = boolProperty("powered")
+	open          = boolProperty("open")
+	lit           = boolProperty("lit")
+	snowy         = boolProperty("snowy")
+	fluidLevel    = intProperty("level", 0, 15)
+	rotation      = intProperty("rotation", 0, 15)
+	cropAge       = intProperty("age", 0, 7)
+	verticalHalf  = stateProperty{"half", []string{"top", "bottom"}}
+	doorHalf      = stateProperty{"half", []string{"upper", "lower"}}
+	plantHalf     = doorHalf
+	fenceSides    = []stateProperty{boolProperty("north"), boolProperty("east"), boolProperty("south"), boolProperty("west")}
+	wallSideValue = []string{"none", "low", "tall"}
+)
+
+// blockStates are the properties of single blocks, keyed by id without the
+// minecraft namespace. Blocks without properties are listed too, so that
+// properties given to them are reported.
+var blockStates = map[string][]stateProperty{
+	"air":      nil,
+	"cave_air": nil,
+	"void_air": nil,
+
+	"stone": nil, "granite": nil, "diorite": nil, "andesite": nil,
+	"cobblestone": nil, "mossy_cobblestone": nil, "bedrock": nil, "obsidian": nil,
+	"dirt": nil, "coarse_dirt": nil, "rooted_dirt": nil, "clay": nil, "gravel": nil,
+	"sand": nil, "red_sand": nil, "sandstone": nil, "red_sandstone": nil,
+	"netherrack": nil, "soul_sand": nil, "soul_soil": nil, "end_stone": nil,
+	"tuff": nil, "calcite": nil, "dripstone_block": nil, "magma_block": nil,
+	"glowstone": nil, "ice": nil, "packed_ice": nil, "blue_ice": nil,
+	"snow_block": nil, "powder_snow": nil, "glass": nil, "tinted_glass": nil,
+	"bricks": nil, "moss_block": nil, "mud": nil, "packed_mud": nil,
+	"ancient_debris": nil, "amethyst_block": nil, "budding_amethyst": nil,
+	"coal_block": nil, "iron_block": nil, "gold_block": nil, "diamond_block": nil,
+	"emerald_block": nil, "lapis_block": nil, "redstone_block": nil,
+	"spawner": nil, "sponge": nil, "wet_sponge": nil, "structure_void": nil,
+	"red_mushroom": nil, "brown_mushroom": nil, "short_grass": nil, "fern": nil,
+	"dead_bush": nil, "cobweb": nil, "torch": nil, "soul_torch": nil,
+
+	"water": {fluidLevel},
+	"lava":  {fluidLevel},
+
+	"grass_block": {snowy},
+	"podzol":      {snowy},
+	"mycelium":    {snowy},
+	"snow":        {intProperty("layers", 1, 8)},
+	"farmland":    {intProperty("moisture", 0, 7)},
+
+	"deepslate":            {axis},
+	"basalt":               {axis},
+	"polished_basalt":      {axis},
+	"hay_block":            {axis},
+	"bone_block":           {axis},
+	"quartz_pillar":        {axis},
+	"purpur_pillar":        {axis},
+	"muddy_mangrove_roots": {axis},
+
+	"coal_ore": nil, "iron_ore": nil, "copper_ore": nil, "gold_ore": nil,
+	"diamond_ore": nil, "emerald_ore": nil, "lapis_ore": nil,
+	"redstone_ore":           {lit},
+	"deepslate_redstone_ore": {lit},
+
+	"wheat":                 {cropAge},
+	"carrots":               {cropAge},
+	"potatoes":              {cropAge},
+	"pumpkin_stem":          {cropAge},
+	"melon_stem":            {cropAge},
+	"beetroots":             {intProperty("age", 0, 3)},
+	"sweet_berry_bush":      {intProperty("age", 0, 3)},
+	"attached_pumpkin_stem": {facing},
+	"attached_melon_stem":   {facing},
+	"carved_pumpkin":        {facing},
+	"jack_o_lantern":        {facing},
+	"pumpkin":               nil,
+	"melon":                 nil,
+
+	"tall_grass": {plantHalf},
+	"large_fern": {plantHalf},
+	"sunflower":  {plantHalf},
+	"lilac":      {plantHalf},
+	"rose_bush":  {plantHalf},
+	"peony":      {plantHalf},
+
+	"wall_torch":      {facing},
+	"soul_wall_torch": {facing},
+	"lantern":         {boolProperty("hanging"), waterlogged},
+	"soul_lantern":    {boolProperty("hanging"), waterlogged},
+	"campfire":        {facing, lit, boolProperty("signal_fire"), waterlogged},
+	"soul_campfire":   {facing, lit, boolProperty("signal_fire"), waterlogged},
+
+	"chest":         {facing, stateProperty{"type", []string{"single", "left", "right"}}, waterlogged},
+	"trapped_chest": {facing, stateProperty{"type", []string{"single", "left", "right"}}, waterlogged},
+	"ender_chest":   {facing, waterlogged},
+	"barrel":        {facingAll, open},
+	"furnace":       {facing, lit},
+	"smoker":        {facing, lit},
+	"blast_furnace": {facing, lit},
+	"dispenser":     {facingAll, boolProperty("triggered")},
+	"dropper":       {facingAll, boolProperty("triggered")},
+	"observer":      {facingAll, powered},
+	"lectern":       {facing, boolProperty("has_book"), powered},
+
+	"suspicious_sand":   {intProperty("dusted", 0, 3)},
+	"suspicious_gravel": {intProperty("dusted", 0, 3)},
+
+	"light_weighted_pressure_plate": {intProperty("power", 0, 15)},
+	"heavy_weighted_pressure_plate": {intProperty("power", 0, 15)},
+}
+
+// blockFamilies are the properties of blocks sharing an id suffix, like all
+// stairs. A block listed in blockStates overrides its family, and longer
+// suffixes come first so that wall signs are not taken for signs.
+var blockFamilies = []struct {
+	suffix     string
+	properties []stateProperty
+}{
+	{"_wall_hanging_sign", []stateProperty{facing, waterlogged}},
+	{"_hanging_sign", []stateProperty{boolProperty("attached"), rotation, waterlogged}},
+	{"_wall_sign", []stateProperty{facing, waterlogged}},
+	{"_sign", []stateProperty{rotation, waterlogged}},
+	{"_glazed_terracotta", []stateProperty{facing}},
+	{"_fence_gate", []stateProperty{facing, boolProperty("in_wall"), open, powered}},
+	{"_pressure_plate", []stateProperty{powered}},
+	{"_stairs", []stateProperty{facing, verticalHalf, {"shape", []string{"straight", "inner_left", "inner_right", "outer_left", "outer_right"}}, waterlogged}},
+	{"_slab", []stateProperty{{"type", []string{"top", "bottom", "double"}}, waterlogged}},
+	{"_trapdoor", []stateProperty{facing, verticalHalf, open, powered, waterlogged}},
+	{"_door", []stateProperty{facing, doorHalf, {"hinge", []string{"left", "right"}}, open, powered}},
+	{"_button", []stateProperty{{"face", []string{"floor", "wall", "ceiling"}}, facing, powered}},
+	{"_fence", append(append([]stateProperty{}, fenceSides...), waterlogged)},
+	{"_wall", []stateProperty{
+		{"north", wallSideValue}, {"east", wallSideValue}, {"south", wallSideValue}, {"west", wallSideValue},
+		boolProperty("up"), waterlogged,
+	}},
+	{"_leaves", []stateProperty{intProperty("distance", 1, 7), boolProperty("persistent"), waterlogged}},
+	{"_sapling", []stateProperty{intProperty("stage", 0, 1)}},
+	{"_log", []stateProperty{axis}},
+	{"_wood", []stateProperty{axis}},
+	{"_stem", []stateProperty{axis}},
+	{"_hyphae", []stateProperty{axis}},
+	{"_planks", nil},
+	{"_wool", nil},
+	{"_concrete", nil},
+	{"_concrete_powder", nil},
+	{"_terracotta", nil},
+	{"_ore", nil},
+}
+
+// blockStateCases holds the validators built from the table: the
+// mcdoc:block_states and mcdoc:block_state_keys cases of each listed block
+// and of each family
+type blockStateCases struct {
+	blocks   map[string]blockStateCase
+	families []blockStateCase
+}
+
+type blockStateCase struct {
+	states Validator // struct of the properties
+	keys   Validator // enum of the property names
+}
+
+// Spyglass fills the mcdoc:block_states and mcdoc:block_state_keys
+// dispatchers from the block data of the game, and vanilla-mcdoc only
+// declares their fallback cases. builtinCase provides their cases for
+// the blocks packs use most, so block state properties like the axis of a log
+// or the level of a fluid are checked, and a %parent.output_state.Name
+// dispatch finds the properties of the block it names. Blocks missing from the
+// table fall back to the cases the schemas declare and are accepted.
+// Properties follow the latest release; targets older than a property accept
+// it rather than reject it.
+var builtinBlockStates = newBlockStateCases()
+
+func newBlockStateCases() *blockStateCases {
+	cases := &blockStateCases{blocks: make(map[string]blockStateCase)}
+	for block, properties := range blockStates {
+		cases.blocks[block] = newBlockStateCase(block, properties)
+	}
+	for _, family := range blockFamilies {
+		cases.families = append(cases.families, newBlockStateCase(strings.TrimPrefix(family.suffix, "_"), family.properties))
+	}
+	return cases
+}
+
+// lookup finds the case of a block id without namespace, or false if the
+// block is not known
+func (c *blockStateCases) lookup(block string) (blockStateCase, bool) {
+	if found, ok := c.blocks[block]; ok {
+		return found, true
+	}
+	for i, family := range blockFamilies {
+		if strings.HasSuffix(block, family.suffix) {
+			return c.families[i], true
+		}
+	}
+	return blockStateCase{}, false
+}
+
+// newBlockStateCase builds the validators of the properties of a block and
+// of their names
+func newBlockStateCase(name string, properties []stateProperty) blockStateCase {
+	sv := &StructValidator{Name: name + " properties"}
+	keys := &EnumValidator{Name: name + " property", Type: "string"}
+	for _, property := range properties {
+		values := &EnumValidator{Name: property.name, Type: "string"}
+		for _, value := range property.values {
+			values.Values = append(values.Values, EnumValue{Name: value, Value: value})
+		}
+		sv.Fields = append(sv.Fields, StructField{Name: property.name, Validator: values, Optional: true})
+		keys.Values = append(keys.Values, EnumValue{Name: property.name, Value: property.name})
+	}
+	sort.Slice(keys.Values, func(i, j int) bool { return keys.Values[i].Name < keys.Values[j].Name })
+	return blockStateCase{states: sv, keys: keys}
+}
+
+// builtinCase returns the case of a dispatcher the game data fills rather
+// than the schemas, or nil if the registry has no built-in cases or the key
+// is not known
+func builtinCase(registry, key string) Validator {
+	switch registry {
+	case "mcdoc:block_states":
+		if found, ok := builtinBlockStates.lookup(key); ok {
+			return found.states
+		}
+	case "mcdoc:block_state_keys":
+		if found, ok := builtinBlockStates.lookup(key); ok {
+			return found.keys
+		}
+	}
+	return nil
+}
+
+// hasBuiltinCases reports whether a dispatcher gets cases from builtinCase,
+// so that it counts as loaded without schemas declaring cases for it
+func hasBuiltinCases(registry string) bool {
+	return registry == "mcdoc:block_states" || registry == "mcdoc:block_state_keys"
+}
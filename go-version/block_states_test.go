@@ -0,0 +1,73 @@
+package mcheck
+
+import (
+	"strings"
+	"testing"
+)
+
+const blockStatesTestSchema = `struct BlockState {
+	Name: #[id="block"] string,
+	Properties?: mcdoc:block_states[[Name]],
+}
+
+struct CopyState {
+	block: #[id="block"] string,
+	properties: [mcdoc:block_state_keys[[%parent.block]]],
+}
+
+struct Rule {
+	output_state: BlockState,
+	modifier?: Modifier,
+}
+
+struct Modifier {
+	properties: mcdoc:block_states[[%parent.output_state.Name]],
+}
+
+dispatch mcdoc:block_state_keys[%unknown,%none] to string
+`
+
+func TestBlockStates(t *testing.T) {
+	_, ctx := convertSchema(t, "1.21.5", blockStatesTestSchema)
+	ctx.StrictSchema = true
+
+	tests := []struct {
+		name     string
+		typeName string
+		value    string
+		expected string // error, empty for valid
+	}{
+		{"log axis", "BlockState", `{"Name": "minecraft:oak_log", "Properties": {"axis": "x"}}`, ""},
+		{"bad log axis", "BlockState", `{"Name": "minecraft:stripped_birch_log", "Properties": {"axis": "w"}}`, `at Properties.axis: expected one of "x", "y", "z", got "w"`},
+		{"explicit block over family", "BlockState", `{"Name": "redstone_ore", "Properties": {"lit": "true"}}`, ""},
+		{"wall sign is not a sign", "BlockState", `{"Name": "oak_wall_sign", "Properties": {"rotation": "4"}}`, "at Properties: unexpected field 'rotation'"},
+		{"fluid level", "BlockState", `{"Name": "minecraft:water", "Properties": {"level": "16"}}`, "at Properties.level: expected one of"},
+		{"block without properties", "BlockState", `{"Name": "minecraft:stone", "Properties": {"axis": "y"}}`, "at Properties: unexpected field 'axis'"},
+		{"unknown block", "BlockState", `{"Name": "mymod:gadget", "Properties": {"charge": "3"}}`, ""},
+		{"copy state keys", "CopyState", `{"block": "minecraft:oak_stairs", "properties": ["facing", "half"]}`, ""},
+		{"copy state unknown key", "CopyState", `{"block": "minecraft:oak_slab", "properties": ["facing"]}`, `at properties.[0]: expected one of "type", "waterlogged", got "facing"`},
+		{"copy state of block without properties", "CopyState", `{"block": "minecraft:dirt", "properties": ["snowy"]}`, `dirt property takes no values, got "snowy"`},
+		{"copy state of unknown block", "CopyState", `{"block": "mymod:gadget", "properties": ["charge"]}`, ""},
+		{"parent output state", "Rule", `{"output_state": {"Name": "minecraft:chest"}, "modifier": {"properties": {"type": "left"}}}`, ""},
+		{"bad parent output state", "Rule", `{"output_state": {"Name": "minecraft:chest"}, "modifier": {"properties": {"type": "double"}}}`, `at modifier.properties.type: expected one of "single", "left", "right", got "double"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := decodeJSON([]byte(tt.value))
+			if err != nil {
+				t.Fatalf("Failed to parse JSON: %v", err)
+			}
+			findings := ctx.Definitions[tt.typeName].Validate(value, ctx)
+			finding, failed := firstError(findings)
+			switch {
+			case tt.expected == "" && failed:
+				t.Errorf("Expected valid, got %v", finding)
+			case tt.expected != "" && !failed:
+				t.Errorf("Expected error containing %q, got none", tt.expected)
+			case tt.expected != "" && !strings.Contains(finding.String(), tt.expected):
+				t.Errorf("Expected error containing %q, got %v", tt.expected, finding)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package mcheck
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// checkBudgets warns about packs over the size and complexity budgets set in
+// the options, which server admins use to catch packs that would slow down a
+// server before deploying them. Each budget is only checked when set.
+func checkBudgets(pack *Pack, opts PackOptions) []Finding {
+	var findings []Finding
+	over := func(file, message string) {
+		findings = append(findings, Finding{File: file, Severity: SeverityWarning, Message: message, Rule: RuleOverBudget})
+	}
+
+	if opts.MaxFiles > 0 {
+		if count := len(pack.Files()); count > opts.MaxFiles {
+			over("data", fmt.Sprintf("pack has %d files, over the budget of %d", count, opts.MaxFiles))
+		}
+	}
+
+	if opts.MaxFileSize > 0 {
+		for _, file := range pack.Files() {
+			if info, err := pack.files.Stat(file); err == nil && info.Size() > opts.MaxFileSize {
+				over(pack.RelativePath(file), fmt.Sprintf("file has %d bytes, over the budget of %d", info.Size(), opts.MaxFileSize))
+			}
+		}
+	}
+
+	if opts.MaxDepth > 0 {
+		for _, file := range pack.JSONFiles() {
+			f, err := pack.files.Open(file)
+			if err != nil {
+				continue
+			}
+			depth := jsonDepth(f)
+			f.Close()
+			if depth > opts.MaxDepth {
+				over(pack.RelativePath(file), fmt.Sprintf("JSON nests %d levels deep, over the budget of %d", depth, opts.MaxDepth))
+			}
+		}
+	}
+	return findings
+}
+
+// jsonDepth returns how deeply the objects and arrays of a JSON document
+// nest, scanning it as it is read so that huge files need not fit in memory.
+// Brackets within strings do not count.
+func jsonDepth(r io.Reader) int {
+	reader := bufio.NewReader(r)
+	depth, deepest := 0, 0
+	inString, escaped := false, false
+	for {
+		c, err := reader.ReadByte()
+		if err != nil {
+			return deepest
+		}
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			escaped = c == '\\'
+			inString = c != '"'
+		case c == '"':
+			inString = true
+		case strings.IndexByte("{[", c) >= 0:
+			depth++
+			deepest = max(deepest, depth)
+		case strings.IndexByte("}]", c) >= 0:
+			depth--
+		}
+	}
+}
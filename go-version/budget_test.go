@@ -0,0 +1,37 @@
+package mcheck
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBudgets(t *testing.T) {
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/recipe/flat.json":         []byte(`{"type": "crafting_shapeless", "ingredients": [{"item": "stone"}]}`),
+		"data/test/recipe/deep.json":         []byte(`{"a": {"b": [[{"c": "[[[[{{{{"}]]]}}`),
+		"data/test/function/big.mcfunction":  []byte(strings.Repeat("say hello\n", 20)),
+		"data/test/loot_table/escaped.json":  []byte(`{"text": "\"[[[[\\"}`),
+		"data/test/structure/house/tiny.nbt": []byte{10, 0, 0, 0},
+	})
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+
+	if findings := checkBudgets(pack, PackOptions{}); len(findings) != 0 {
+		t.Errorf("Expected no findings without budgets, got %v", findings)
+	}
+
+	var messages []string
+	for _, finding := range checkBudgets(pack, PackOptions{MaxFileSize: 100, MaxDepth: 4, MaxFiles: 4}) {
+		messages = append(messages, finding.String())
+	}
+	expected := []string{
+		"data: warning: pack has 5 files, over the budget of 4 [MCHECK044 over-budget]",
+		"data/test/function/big.mcfunction: warning: file has 200 bytes, over the budget of 100 [MCHECK044 over-budget]",
+		"data/test/recipe/deep.json: warning: JSON nests 5 levels deep, over the budget of 4 [MCHECK044 over-budget]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+}
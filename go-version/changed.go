@@ -0,0 +1,69 @@
+package mcheck
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// changedFiles asks git for the files below root that changed since ref,
+// including untracked files. Deleted files are included so that the files
+// referencing them are checked again.
+func changedFiles(root, ref string) ([]string, error) {
+	var files []string
+	for _, args := range [][]string{
+		{"diff", "--name-only", "--relative", ref, "--"},
+		{"ls-files", "--others", "--exclude-standard"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+		}
+
+		for _, line := range strings.Split(string(output), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				files = append(files, filepath.Join(root, filepath.FromSlash(line)))
+			}
+		}
+	}
+	return files, nil
+}
+
+// AffectedFiles returns the files that need checking when the given files
+// changed: the changed files that still exist and every file with a
+// reference to a changed or deleted resource
+func (p *Pack) AffectedFiles(changed []string) map[string]bool {
+	indexed := make(map[string]bool)
+	for _, resources := range p.Resources {
+		for _, file := range resources {
+			indexed[file] = true
+		}
+	}
+
+	affected := make(map[string]bool)
+	changedResources := make(map[string]bool)
+	for _, file := range changed {
+		file = filepath.Clean(file)
+		if indexed[file] {
+			affected[file] = true
+		}
+		if _, resourceType, id, ok := p.resourceOf(file); ok && resourceType != "" {
+			changedResources[resourceType+" "+id] = true
+		}
+	}
+
+	for _, reference := range p.References() {
+		for _, resourceType := range reference.Types {
+			if changedResources[resourceType+" "+reference.ID] {
+				affected[reference.File] = true
+			}
+		}
+	}
+	return affected
+}
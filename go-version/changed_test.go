@@ -0,0 +1,76 @@
+package mcheck
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestChangedFromAffectedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/function/init.mcfunction":     []byte("say hi\n"),
+		"data/test/function/old.mcfunction":      []byte("say old\n"),
+		"data/test/function/other.mcfunction":    []byte("say other\n"),
+		"data/minecraft/tags/function/load.json": []byte(`{"values": ["test:init"]}`),
+		"data/minecraft/tags/function/tick.json": []byte(`{"values": ["test:old"]}`),
+		"data/test/tags/function/misc.json":      []byte(`{"values": ["test:other"]}`),
+	})
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = root
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, output)
+		}
+	}
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	// Change one function, delete another and add a new untracked one
+	if err := os.WriteFile(filepath.Join(root, "data/test/function/init.mcfunction"), []byte("say changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(root, "data/test/function/old.mcfunction")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "data/test/function/new.mcfunction"), []byte("say new\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := changedFiles(root, "HEAD")
+	if err != nil {
+		t.Fatalf("changedFiles failed: %v", err)
+	}
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+
+	var affected []string
+	for file := range pack.AffectedFiles(changed) {
+		affected = append(affected, pack.RelativePath(file))
+	}
+	sort.Strings(affected)
+	expected := []string{
+		"data/minecraft/tags/function/load.json",
+		"data/minecraft/tags/function/tick.json",
+		"data/test/function/init.mcfunction",
+		"data/test/function/new.mcfunction",
+	}
+	if strings.Join(affected, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected affected files:\n%s", strings.Join(affected, "\n"))
+	}
+
+	if _, err := changedFiles(root, "no-such-ref"); err == nil {
+		t.Error("Expected an error for an unknown ref")
+	}
+}
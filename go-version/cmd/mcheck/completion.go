@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+
+	"mcheck"
+)
+
+// completionFunc completes the value of a flag or argument
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completeWords completes from a fixed list of words
+func completeWords(words []string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return words, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeResourceTypes completes the resource types of the schema directory
+// and edition given on the command line so far. Without a schema directory
+// there is nothing to offer.
+func completeResourceTypes(opts *options) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		validator, err := opts.Config.NewValidator()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return validator.ResourceTypes(), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func newGenDocsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "gen-docs <dir>",
+		Short: "Write a man page for each command to a directory",
+		Long: `gen-docs writes the man pages of mcheck and its commands, like mcheck.1 and
+mcheck-validate.1, to <dir>. Shell completions are written by mcheck
+completion.`,
+		Args:   cobra.ExactArgs(1),
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := os.MkdirAll(args[0], 0755); err != nil {
+				return err
+			}
+			manUsage(cmd.Root())
+			header := &doc.GenManHeader{Title: "MCHECK", Section: "1", Source: "mcheck " + mcheck.BuildVersion}
+			if err := doc.GenManTree(cmd.Root(), header, args[0]); err != nil {
+				return fmt.Errorf("failed to write man pages: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+var placeholderPattern = regexp.MustCompile(`<([\w-]+)>`)
+
+// manUsage writes the <placeholders> of the usage lines of a command tree in
+// capitals, as md2man would drop them as HTML tags
+func manUsage(cmd *cobra.Command) {
+	cmd.Use = placeholderPattern.ReplaceAllStringFunc(cmd.Use, func(placeholder string) string {
+		return strings.ToUpper(strings.Trim(placeholder, "<>"))
+	})
+	for _, child := range cmd.Commands() {
+		manUsage(child)
+	}
+}
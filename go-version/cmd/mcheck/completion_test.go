@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcheck"
+)
+
+// fixtureSchemaDir copies schemas from tests/mcdocs into a temporary
+// vanilla-mcdoc layout, each as java/data/<schema>.mcdoc
+func fixtureSchemaDir(t *testing.T, schemas ...string) string {
+	t.Helper()
+
+	schemaDir := filepath.Join(t.TempDir(), "vanilla-mcdoc")
+	for _, schemaName := range schemas {
+		schema, err := os.ReadFile(filepath.Join("..", "..", "tests", "mcdocs", filepath.Base(schemaName)+".mcdoc"))
+		if err != nil {
+			t.Fatalf("Failed to read %s schema: %v", schemaName, err)
+		}
+		schemaPath := filepath.Join(schemaDir, "java", "data", filepath.FromSlash(schemaName)+".mcdoc")
+		if err := os.MkdirAll(filepath.Dir(schemaPath), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(schemaPath, schema, 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", schemaPath, err)
+		}
+	}
+	return schemaDir
+}
+
+// complete runs the hidden command shells call to complete a command line
+func complete(t *testing.T, args ...string) []string {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := newRootCmd(&options{})
+	cmd.SetOut(&out)
+	cmd.SetArgs(append([]string{"__complete"}, args...))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Completing %v failed: %v", args, err)
+	}
+	// The last line is the directive
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	return lines[:len(lines)-1]
+}
+
+func TestCompletion(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t, "trim", "worldgen/biome")
+
+	types := complete(t, "validate", "--schema-dir", schemaDir, "--type", "")
+	expected := []string{"trim_material", "trim_pattern", "worldgen/biome"}
+	if strings.Join(types, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected --type to complete to %v, got %v", expected, types)
+	}
+
+	if types := complete(t, "schema", "path", "--schema-dir", schemaDir, ""); strings.Join(types, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected schema path to complete to %v, got %v", expected, types)
+	}
+
+	// Without schemas there are no types to offer
+	if types := complete(t, "validate", "--schema-dir", filepath.Join(t.TempDir(), "missing"), "--type", ""); len(types) != 0 {
+		t.Errorf("Expected no types without a schema directory, got %v", types)
+	}
+
+	versions := complete(t, "validate", "--version", "")
+	if strings.Join(versions, " ") != strings.Join(mcheck.KnownVersions, " ") {
+		t.Errorf("Expected --version to complete to the known versions, got %v", versions)
+	}
+	for _, version := range mcheck.KnownVersions {
+		if _, err := mcheck.ParseVersion(version); err != nil {
+			t.Errorf("Known version %s does not parse: %v", version, err)
+		}
+	}
+}
+
+func TestValidateResourceType(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t, "worldgen/biome")
+	biome, err := os.ReadFile(filepath.Join("..", "..", "tests", "good", "data", "worldgen", "biome", "basalt_deltas.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "basalt_deltas.json")
+	if err := os.WriteFile(file, biome, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Outside a datapack the type cannot be told from the path
+	if err := runValidate(&options{Config: mcheck.Config{Version: "1.20.1", SchemaDir: schemaDir, Edition: "java"}}, []string{file}); err == nil {
+		t.Errorf("Expected %s to fail without --type", file)
+	}
+	if err := runValidate(&options{Config: mcheck.Config{Version: "1.20.1", SchemaDir: schemaDir, Edition: "java", ResourceType: "worldgen/biome"}}, []string{file}); err != nil {
+		t.Errorf("Expected %s to validate as a worldgen/biome, got %v", file, err)
+	}
+	if err := runValidate(&options{Config: mcheck.Config{Version: "1.20.1", SchemaDir: schemaDir, Edition: "java", ResourceType: "worldgen/biome"}, versions: "1.20..1.20.2"}, []string{file}); err != nil {
+		t.Errorf("Expected %s to validate for 1.20 to 1.20.2, got %v", file, err)
+	}
+	if err := runValidate(&options{Config: mcheck.Config{Version: "1.20.1", SchemaDir: schemaDir, Edition: "java", ResourceType: "worldgen/biome"}, versions: "1.20..1.19"}, []string{file}); err == nil {
+		t.Errorf("Expected an error for an empty version range")
+	}
+}
+
+func TestGenDocs(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "man")
+	cmd := newRootCmd(&options{})
+	cmd.SetArgs([]string{"gen-docs", dir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("gen-docs failed: %v", err)
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "mcheck-validate.1"))
+	if err != nil {
+		t.Fatalf("Expected a man page for validate: %v", err)
+	}
+	if !strings.Contains(string(page), `mcheck validate FILE...`) || !strings.Contains(string(page), "--type") {
+		t.Errorf("Expected the validate man page to show its usage and flags, got:\n%s", page)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "mcheck-schema-path.1")); err != nil {
+		t.Errorf("Expected a man page for schema path: %v", err)
+	}
+}
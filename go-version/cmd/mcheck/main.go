@@ -0,0 +1,1056 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"mcheck"
+)
+
+// options are the persistent flags shared by every command
+type options struct {
+	mcheck.Config // the flags that change how files are checked
+
+	lang           string
+	style          string
+	format         string
+	template       string
+	notifyWebhook  string
+	notifyFindings bool
+	daemon         string // socket of the daemon validating for validate and pack
+
+	explain  bool   // set by validate --explain
+	versions string // set by validate --versions
+	timings  bool   // set by validate and pack --timings
+
+	recorded *mcheck.Timings // timings of the validators created, with --timings
+	profiles profiles        // set by the hidden profiling flags
+}
+
+// validator creates the validator the flags describe
+func (o *options) validator() (*mcheck.PEGMCDocValidator, error) {
+	validator, err := o.Config.NewValidator()
+	if err != nil {
+		return nil, err
+	}
+	validator.SetTimings(o.recorded)
+	return validator, nil
+}
+
+// output returns how the flags ask for findings to be printed
+func (o *options) output() (mcheck.Output, error) {
+	version := o.Version
+	if o.versions != "" {
+		version = o.versions
+	}
+	return mcheck.NewOutput(mcheck.OutputOptions{
+		Lang:           o.lang,
+		Style:          o.style,
+		Format:         o.format,
+		Template:       o.template,
+		Version:        version,
+		NotifyWebhook:  o.notifyWebhook,
+		NotifyFindings: o.notifyFindings,
+	})
+}
+
+// recordTimings makes the validators created afterwards record their
+// timings if --timings is set, and returns a function printing the report
+func (o *options) recordTimings() func() {
+	if !o.timings {
+		return func() {}
+	}
+	o.recorded = &mcheck.Timings{}
+	return func() {
+		fmt.Fprintln(os.Stderr)
+		o.recorded.Write(os.Stderr)
+	}
+}
+
+func main() {
+	opts := &options{}
+	err := newRootCmd(opts).Execute()
+	if stopErr := opts.profiles.stop(); stopErr != nil {
+		log.Print(stopErr)
+	}
+	if err != nil {
+		var exit mcheck.ExitError
+		if errors.As(err, &exit) {
+			log.Print(err)
+			os.Exit(exit.Code)
+		}
+		log.Fatal(err)
+	}
+}
+
+// newRootCmd builds the mcheck command tree. The profiles the flags ask
+// for are started before the command runs and must be stopped after it.
+func newRootCmd(opts *options) *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "mcheck",
+		Short: "Validate Minecraft datapack JSON and NBT files against mcdoc schemas",
+		Long: `mcheck is a tool for validating Minecraft datapack JSON files against
+mcdoc schemas with version-specific constraints.`,
+		// mcheck <file> predates the validate command and keeps working
+		Args:          cobra.ArbitraryArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.profiles.start()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			return runValidate(opts, args)
+		},
+	}
+
+	rootCmd.PersistentFlags().StringVarP(&opts.Version, "version", "v", "1.20.1", "Target Minecraft version")
+	rootCmd.PersistentFlags().StringVarP(&opts.SchemaDir, "schema-dir", "s", "", "Path to vanilla-mcdoc directory")
+	rootCmd.PersistentFlags().StringVarP(&opts.Edition, "edition", "e", "java", "Game edition, selecting the schemas in <schema-dir>/<edition>")
+	rootCmd.PersistentFlags().BoolVar(&opts.Lenient, "lenient", false, "Accept 0 and 1 for booleans and true and false for numbers with a warning, as the game does")
+	rootCmd.PersistentFlags().StringSliceVar(&opts.Features, "features", nil, "Enabled feature flags for experimental content, like update_1_21")
+	rootCmd.PersistentFlags().StringSliceVar(&opts.ExtraSchemas, "extra-schemas", nil, "Directories of mcdoc schemas for mods, laid out like <schema-dir>, whose dispatch keys are known and validated")
+	rootCmd.PersistentFlags().BoolVar(&opts.InclusiveUntil, "inclusive-until", false, `Treat #[until="X"] as still valid in X, as mcheck did before following vanilla-mcdoc`)
+	rootCmd.PersistentFlags().BoolVar(&opts.StrictSchema, "strict-schema", false, "Fail on values that cannot be fully validated, like those of schema types that could not be resolved")
+	rootCmd.PersistentFlags().BoolVar(&opts.WarnIncomplete, "warn-incomplete", false, "Report empty and cut off JSON files as warnings instead of errors, as when importing many files at once")
+	rootCmd.PersistentFlags().StringVar(&opts.lang, "lang", "en", "Language of the messages of findings, or a catalog file being translated, like de.json")
+	rootCmd.PersistentFlags().StringVar(&opts.style, "style", "plain", "Output style of findings: plain, emoji to mark their severity, or ascii to escape everything else for log systems")
+	rootCmd.PersistentFlags().StringVar(&opts.format, "format", mcheck.FormatText, "Output format of findings: text, or template to render them with the --template file")
+	rootCmd.PersistentFlags().StringVar(&opts.template, "template", "", "Go text/template file rendering a report of the findings, for --format=template")
+	rootCmd.PersistentFlags().StringVar(&opts.notifyWebhook, "notify-webhook", "", "Post a JSON summary to this Discord or Slack compatible webhook URL when validation fails")
+	rootCmd.PersistentFlags().BoolVar(&opts.notifyFindings, "notify-findings", false, "Include the findings in the --notify-webhook message")
+	rootCmd.PersistentFlags().StringVar(&opts.daemon, "daemon", "", "Validate through the mcheck daemon listening on the socket given with --daemon=<socket>, or on "+mcheck.DefaultDaemonSocket()+" if none is given")
+	rootCmd.PersistentFlags().Lookup("daemon").NoOptDefVal = mcheck.DefaultDaemonSocket()
+	opts.profiles.addFlags(rootCmd.PersistentFlags())
+	rootCmd.RegisterFlagCompletionFunc("version", completeWords(mcheck.KnownVersions))
+	rootCmd.RegisterFlagCompletionFunc("features", completeWords(mcheck.KnownFeatures))
+	rootCmd.RegisterFlagCompletionFunc("edition", completeWords(mcheck.Editions))
+	rootCmd.RegisterFlagCompletionFunc("lang", completeWords(mcheck.Languages()))
+	rootCmd.RegisterFlagCompletionFunc("style", completeWords(mcheck.OutputStyles))
+	rootCmd.RegisterFlagCompletionFunc("format", completeWords(mcheck.OutputFormats))
+
+	// hover moved to mcheck schema hover; the old name stays for editor integrations
+	hoverCmd := newHoverCmd(opts)
+	hoverCmd.Hidden = true
+
+	rootCmd.AddCommand(
+		newValidateCmd(opts),
+		newPackCmd(opts),
+		newHashCmd(),
+		newDiffCmd(opts),
+		newStatsCmd(opts),
+		newSchemaCmd(opts),
+		newParseCmd(),
+		newFmtCmd(),
+		newServeCmd(opts),
+		newDaemonCmd(opts),
+		newLSPCmd(opts),
+		newREPLCmd(opts),
+		newVersionCmd(opts),
+		newDoctorCmd(opts),
+		newHookCmd(opts),
+		newRulesCmd(),
+		newExplainCmd(opts),
+		newGenerateSampleCmd(opts),
+		newDocsCmd(opts),
+		newMutateCmd(opts),
+		newGenDocsCmd(),
+		hoverCmd,
+	)
+	return rootCmd
+}
+
+func newValidateCmd(opts *options) *cobra.Command {
+	validateCmd := &cobra.Command{
+		Use:   "validate <file>...",
+		Short: "Validate datapack files against their schemas",
+		Long: `validate checks each file against the schema of the resource type its path
+within a datapack names, like data/minecraft/worldgen/biome/plains.json for
+worldgen/biome. Files outside a datapack can be checked by naming their type
+with --type.`,
+		Args: cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"json", "nbt"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runValidate(opts, args)
+		},
+	}
+	validateCmd.Flags().StringVarP(&opts.ResourceType, "type", "t", "", "Resource type of the files, like worldgen/biome, instead of the one their path names")
+	validateCmd.Flags().BoolVar(&opts.explain, "explain", false, "Follow each finding with its schema declaration, version bounds and example values")
+	validateCmd.Flags().StringVar(&opts.versions, "versions", "", "Check for several versions, like 1.20.1,1.20.5..1.21 where 1.21 stands for every 1.21.x release")
+	validateCmd.Flags().BoolVar(&opts.timings, "timings", false, "Print the time spent parsing schemas, building validators, decoding and validating files, by resource type")
+	validateCmd.RegisterFlagCompletionFunc("type", completeResourceTypes(opts))
+	return validateCmd
+}
+
+// runValidate checks each file for the target version, or each version
+// --versions names, and prints its findings
+func runValidate(opts *options, files []string) error {
+	out, err := opts.output()
+	if err != nil {
+		return err
+	}
+	defer opts.recordTimings()()
+
+	versions := []string{opts.Version}
+	if opts.versions != "" {
+		parsed, err := mcheck.ParseVersions(opts.versions)
+		if err != nil {
+			return fmt.Errorf("invalid --versions: %w", err)
+		}
+		versions = versions[:0]
+		for _, version := range parsed {
+			versions = append(versions, version.String())
+		}
+	}
+
+	if opts.daemon != "" && opts.explain {
+		return fmt.Errorf("--explain cannot be used with --daemon")
+	}
+
+	var findings []mcheck.Finding
+	explainers := make(map[string]*mcheck.PEGMCDocValidator)
+	warned := false
+	for _, version := range versions {
+		versionOpts := *opts
+		versionOpts.Version = version
+		if opts.daemon != "" {
+			checked, err := versionOpts.CheckWithDaemon(opts.daemon, files, nil, mcheck.PackOptions{})
+			if err != nil {
+				return err
+			}
+			for _, finding := range checked {
+				if opts.versions != "" {
+					finding.Version = version
+				}
+				findings = append(findings, finding)
+			}
+			continue
+		}
+		validator, err := versionOpts.validator()
+		if err != nil {
+			return err
+		}
+		if !warned {
+			warned = warnOutdatedSchemas(validator, version)
+		}
+		if opts.versions == "" {
+			version = ""
+		}
+		explainers[version] = validator
+		for _, file := range files {
+			for _, finding := range validator.CheckFile(file, file) {
+				finding.Version = version
+				findings = append(findings, finding)
+			}
+		}
+	}
+	if !opts.explain {
+		return out.Print(findings, nil)
+	}
+	return out.Print(findings, explainers)
+}
+
+// warnOutdatedSchemas warns on stderr if the schemas predate the target
+// version, and reports whether it did
+func warnOutdatedSchemas(validator *mcheck.PEGMCDocValidator, version string) bool {
+	newest, predate := validator.SchemasPredate()
+	if predate {
+		fmt.Fprintf(os.Stderr, "warning: schemas predate %s; results may be incomplete (they were written for %s, update them)\n", version, newest)
+	}
+	return predate
+}
+
+func newPackCmd(opts *options) *cobra.Command {
+	var packOptions mcheck.PackOptions
+	var order []string
+	var vanilla string
+	packCmd := &cobra.Command{
+		Use:   "pack <datapack-dir>...",
+		Short: "Validate every file in a datapack and the references between them",
+		Long: `pack validates every file in a datapack and the references between them.
+Several datapacks are checked as a server loads them together, in the order
+given or the one --order names: references resolve in any of them, a
+resource one pack defines that a later one defines again is reported, and
+tags several packs define are reported with the values the game merges
+them into, warning about those that set replace.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Edition != "java" {
+				return fmt.Errorf("pack validation only supports java datapacks")
+			}
+			out, err := opts.output()
+			if err != nil {
+				return err
+			}
+			defer opts.recordTimings()()
+
+			roots, err := mcheck.OrderPacks(args, order)
+			if err != nil {
+				return err
+			}
+			if vanilla != "" {
+				if packOptions.Vanilla, err = mcheck.FindVanillaData(vanilla); err != nil {
+					return err
+				}
+			}
+			if opts.daemon != "" {
+				findings, err := opts.CheckWithDaemon(opts.daemon, nil, roots, packOptions)
+				if err != nil {
+					return err
+				}
+				return out.Print(findings, nil)
+			}
+
+			validator, err := opts.validator()
+			if err != nil {
+				return err
+			}
+			warnOutdatedSchemas(validator, opts.Version)
+			var findings []mcheck.Finding
+			if len(roots) == 1 {
+				findings, err = validator.CheckPack(context.Background(), roots[0], nil, packOptions)
+			} else {
+				findings, err = validator.CheckPacks(context.Background(), roots, packOptions)
+			}
+			if err != nil {
+				return err
+			}
+			return out.Print(findings, nil)
+		},
+	}
+	packCmd.Flags().StringVar(&vanilla, "vanilla", "", "Resolve references in the vanilla datapack of a version, found in vanilla-data/<version>, or in a datapack directory")
+	packCmd.Flags().StringSliceVar(&order, "order", nil, "Load order of the datapacks, first loaded first, naming each by its path or directory name")
+	packCmd.Flags().BoolVar(&packOptions.CheckNBT, "check-nbt", false, "Parse referenced structure files to verify they are valid NBT")
+	packCmd.Flags().BoolVar(&packOptions.ReportUnused, "unused", false, "Warn about resources and scoreboard objectives nothing in the pack uses")
+	packCmd.Flags().StringVar(&packOptions.ChangedFrom, "changed-from", "", "Only check files changed since a git ref and the files referencing them")
+	packCmd.Flags().Int64Var(&packOptions.MaxFileSize, "max-file-size", 0, "Warn about files larger than this many bytes")
+	packCmd.Flags().IntVar(&packOptions.MaxDepth, "max-depth", 0, "Warn about JSON files nesting objects and arrays deeper than this")
+	packCmd.Flags().IntVar(&packOptions.MaxFiles, "max-files", 0, "Warn if the pack has more resource files than this")
+	packCmd.Flags().BoolVar(&opts.timings, "timings", false, "Print the time spent parsing schemas, building validators, decoding and validating files, by resource type")
+	return packCmd
+}
+
+func newHashCmd() *cobra.Command {
+	var listFiles, asJSON bool
+	var expect string
+	hashCmd := &cobra.Command{
+		Use:   "hash <datapack-dir>...",
+		Short: "Print a content hash of datapacks",
+		Long: `hash prints a hash of the content of each datapack that only changes with
+what the game reads: files are hashed in order of their paths, JSON in a
+canonical form with sorted keys and no whitespace, and functions with \n line
+endings. Compare the hash of a deployed pack with that of the pack that was
+validated, or pass it to --expect to fail unless they match.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mismatched := 0
+			for _, root := range args {
+				hash, err := mcheck.HashPack(root)
+				if err != nil {
+					return err
+				}
+				if expect != "" && hash.Sum != expect && "sha256:"+expect != hash.Sum {
+					mismatched++
+				}
+				if asJSON {
+					output, err := json.MarshalIndent(hash, "", "  ")
+					if err != nil {
+						return err
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), string(output))
+					continue
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s  %s\n", hash.Sum, root)
+				if listFiles {
+					for _, file := range hash.Files {
+						fmt.Fprintf(cmd.OutOrStdout(), "  %s  %s\n", file.Sum, file.Path)
+					}
+				}
+			}
+			if mismatched > 0 {
+				return mcheck.ExitError{Code: mcheck.ExitInvalid, Err: fmt.Errorf("%d packs do not match %s", mismatched, expect)}
+			}
+			return nil
+		},
+	}
+	hashCmd.Flags().BoolVar(&listFiles, "files", false, "Also print the hash of each file, to find those that differ")
+	hashCmd.Flags().BoolVar(&asJSON, "json", false, "Print the hashes as JSON")
+	hashCmd.Flags().StringVar(&expect, "expect", "", "Fail unless every pack has this hash")
+	return hashCmd
+}
+
+func newDiffCmd(opts *options) *cobra.Command {
+	var asJSON, exitCode bool
+	diffCmd := &cobra.Command{
+		Use:   "diff <old-datapack-dir> <new-datapack-dir>",
+		Short: "Compare the resources of two datapacks",
+		Long: `diff compares the resources of two versions of a datapack by resource type
+and id, printing those added (+), removed (-) and changed (~). JSON resources
+are compared by value, so key order, whitespace, how numbers are written and
+resource ids given without the minecraft namespace their schema defaults to
+do not count as changes, and each added, removed or changed field is listed
+at its path. Other files are compared by content. --json prints the
+differences for tools reviewing releases, and --exit-code exits with 1 when
+the packs differ.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validator, err := opts.validator()
+			if err != nil {
+				return err
+			}
+			diff, err := validator.DiffPacks(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if asJSON {
+				output, err := json.MarshalIndent(diff, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(out, string(output))
+			} else {
+				for _, change := range diff.Added {
+					fmt.Fprintf(out, "+ %s %s\n", change.Type, change.ID)
+				}
+				for _, change := range diff.Removed {
+					fmt.Fprintf(out, "- %s %s\n", change.Type, change.ID)
+				}
+				for _, change := range diff.Changed {
+					fmt.Fprintf(out, "~ %s %s\n", change.Type, change.ID)
+					for _, field := range change.Fields {
+						fmt.Fprintf(out, "    %s\n", field)
+					}
+				}
+				fmt.Fprintf(out, "%d added, %d removed, %d changed\n", len(diff.Added), len(diff.Removed), len(diff.Changed))
+			}
+			if exitCode && !diff.Empty() {
+				return mcheck.ExitError{Code: mcheck.ExitInvalid, Err: fmt.Errorf("the datapacks differ")}
+			}
+			return nil
+		},
+	}
+	diffCmd.Flags().BoolVar(&asJSON, "json", false, "Print the differences as JSON")
+	diffCmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit with 1 when the datapacks differ")
+	return diffCmd
+}
+
+func newStatsCmd(opts *options) *cobra.Command {
+	var unusedOnly, asJSON bool
+	statsCmd := &cobra.Command{
+		Use:   "stats <datapack-dir>...",
+		Short: "Count how often datapacks use each field of the schemas",
+		Long: `stats walks the JSON files of datapacks like validate does and prints, for
+each struct of the schemas they use, how many objects they have and how many
+of those set each of its fields, marking the optional fields none of them
+set. Pack authors can audit the features their pack relies on, and schema
+authors see which parts of a schema real packs exercise. --unused prints only
+the optional fields that are never set.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validator, err := opts.validator()
+			if err != nil {
+				return err
+			}
+			stats, err := validator.PackStats(args...)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if asJSON {
+				output, err := json.MarshalIndent(stats, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(out, string(output))
+				return nil
+			}
+
+			if unusedOnly {
+				for _, s := range stats.Structs {
+					for _, field := range s.UnusedFields() {
+						fmt.Fprintf(out, "%s.%s  %s\n", s.Name, field, s.Source)
+					}
+				}
+				return nil
+			}
+			for _, resourceType := range slices.Sorted(maps.Keys(stats.Files)) {
+				fmt.Fprintf(out, "%s: %d files\n", resourceType, stats.Files[resourceType])
+			}
+			if stats.Skipped > 0 {
+				fmt.Fprintf(out, "skipped %d files without a schema, that do not parse or are too large to decode\n", stats.Skipped)
+			}
+			for _, s := range stats.Structs {
+				fmt.Fprintf(out, "\n%s (%s): %d objects\n", s.Name, s.Source, s.Objects)
+				for _, field := range s.Fields {
+					name := field.Name
+					if field.Optional {
+						name += "?"
+					}
+					fmt.Fprintf(out, "  %-32s %6d", name, field.Uses)
+					if field.Optional && field.Uses == 0 {
+						fmt.Fprint(out, "  never used")
+					}
+					fmt.Fprintln(out)
+				}
+			}
+			return nil
+		},
+	}
+	statsCmd.Flags().BoolVar(&unusedOnly, "unused", false, "Only print the optional fields that are never set")
+	statsCmd.Flags().BoolVar(&asJSON, "json", false, "Print the counts as JSON")
+	return statsCmd
+}
+
+func newSchemaCmd(opts *options) *cobra.Command {
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Inspect the schemas files are validated against",
+	}
+
+	typesCmd := &cobra.Command{
+		Use:   "types",
+		Short: "List the resource types that have a schema",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validator, err := opts.validator()
+			if err != nil {
+				return err
+			}
+			for _, resourceType := range validator.ResourceTypes() {
+				fmt.Println(resourceType)
+			}
+			return nil
+		},
+	}
+
+	pathCmd := &cobra.Command{
+		Use:   "path <resource-type>",
+		Short: "Print the schema file describing a resource type, like worldgen/biome",
+		Args:  cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completeResourceTypes(opts)(cmd, args, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validator, err := opts.validator()
+			if err != nil {
+				return err
+			}
+			schemaPath, err := validator.SchemaPath(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Println(schemaPath)
+			return nil
+		},
+	}
+
+	schemaCmd.AddCommand(typesCmd, pathCmd, newHoverCmd(opts))
+	return schemaCmd
+}
+
+func newHoverCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "hover <json-file> <json-pointer>",
+		Short: "Describe the schema of the value at a JSON pointer",
+		Long: `hover prints the resolved type, constraints, version range and docs of
+the value at a JSON pointer (like /noise/min_y) as JSON, for use by editor
+integrations.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validator, err := opts.validator()
+			if err != nil {
+				return err
+			}
+
+			info, err := validator.Hover(args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			output, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
+	}
+}
+
+func newParseCmd() *cobra.Command {
+	var format string
+	parseCmd := &cobra.Command{
+		Use:   "parse <schema.mcdoc>",
+		Short: "Print the statements a schema file parses to",
+		Long: `parse prints the statements of an mcdoc file as mcheck's parser sees them,
+one per line, or with --format json as their full syntax tree for debugging
+the grammar and for tools reusing the parser. The file is parsed on its own,
+without resolving the types it uses.`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"mcdoc"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			statements, err := mcheck.ParseMCDocFile(args[0])
+			if err != nil {
+				return err
+			}
+			switch format {
+			case "text":
+				fmt.Fprint(cmd.OutOrStdout(), mcheck.FormatStatementsText(statements))
+			case "json":
+				output, err := mcheck.MarshalStatements(statements)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			default:
+				return fmt.Errorf("unknown format %q, expected text or json", format)
+			}
+			return nil
+		},
+	}
+	parseCmd.Flags().StringVar(&format, "format", "text", "Output format: text, or json for the full syntax tree")
+	parseCmd.RegisterFlagCompletionFunc("format", completeWords(mcheck.ParseFormats))
+	return parseCmd
+}
+
+func newFmtCmd() *cobra.Command {
+	var write, list bool
+	fmtCmd := &cobra.Command{
+		Use:   "fmt <schema.mcdoc|dir>...",
+		Short: "Reformat mcdoc schema files in canonical style",
+		Long: `fmt reprints mcdoc files with tab indentation, one field, enum value and
+attribute per line, trailing commas and long unions split one alternative
+per line, like gofmt does for Go. Directories are searched for .mcdoc files.
+The formatted source is printed unless -w or -l is given. Files whose
+formatting would change their meaning or drop a comment are left alone and
+reported.`,
+		Args: cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"mcdoc"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return mcheck.FormatFiles(args, write, list, cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+	fmtCmd.Flags().BoolVarP(&write, "write", "w", false, "Write the formatted source back to the files")
+	fmtCmd.Flags().BoolVarP(&list, "list", "l", false, "List the files whose formatting differs")
+	return fmtCmd
+}
+
+func newServeCmd(opts *options) *cobra.Command {
+	var addr string
+	var timeout time.Duration
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve validation over HTTP",
+		Long: `serve answers POST /validate?path=<pack-path> requests, validating the
+request body as the file at path within a datapack, like
+data/minecraft/worldgen/biome/plains.json, and responding with the findings
+as JSON.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validator, err := opts.validator()
+			if err != nil {
+				return err
+			}
+			log.Printf("serving on http://%s", addr)
+			return http.ListenAndServe(addr, mcheck.NewValidationHandler(validator, timeout))
+		},
+	}
+	serveCmd.Flags().StringVar(&addr, "addr", "localhost:7878", "Address to listen on")
+	serveCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Give up on a request still being validated after this long, or 0 to wait for it")
+	return serveCmd
+}
+
+func newDaemonCmd(opts *options) *cobra.Command {
+	var socket string
+	daemonCmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Keep the schemas loaded and validate for other mcheck runs",
+		Long: `daemon stays resident with the schemas parsed and converted and the vanilla
+datapacks of --vanilla loaded, and validates for the runs of mcheck validate
+and mcheck pack given --daemon, which send it their files over a unix socket
+rather than loading the schemas themselves. The schemas of every resource
+type are loaded for the flags the daemon is started with, and on first use
+for the other versions and flags clients send. Files are sent by their path,
+so the daemon must be able to read them, and findings come back in English.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return mcheck.ServeDaemon(ctx, socket, opts.Config)
+		},
+	}
+	daemonCmd.Flags().StringVar(&socket, "socket", mcheck.DefaultDaemonSocket(), "Unix socket to listen on")
+	return daemonCmd
+}
+
+func newLSPCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lsp",
+		Short: "Run a language server over stdin and stdout",
+		Long: `lsp speaks the Language Server Protocol on stdin and stdout, publishing
+the findings of each open datapack JSON file as diagnostics. Edited schema
+files are reloaded and the open files checked again.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validator, err := opts.validator()
+			if err != nil {
+				return err
+			}
+			return mcheck.ServeLanguageServer(validator, os.Stdin, os.Stdout)
+		},
+	}
+}
+
+func newREPLCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "repl",
+		Short: "Explore schemas and try JSON snippets interactively",
+		Long: `repl loads the schema of a resource type and lets you list its fields,
+drill into nested types and check JSON snippets against the type at hand,
+which helps when writing worldgen files by hand. Enter help for the commands.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validator, err := opts.validator()
+			if err != nil {
+				return err
+			}
+			return mcheck.NewSchemaREPL(validator, cmd.OutOrStdout()).Run(cmd.InOrStdin())
+		},
+	}
+}
+
+func newVersionCmd(opts *options) *cobra.Command {
+	var asJSON bool
+	versionCmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the version of mcheck and its schemas",
+		Long: `version prints the version and source revision of mcheck, the revision of
+the schemas in the schema directory and the range of Minecraft versions it
+knows. Include it in bug reports.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := mcheck.BuildVersionInfo(opts.SchemaDir, opts.Version)
+			if !asJSON {
+				fmt.Fprint(cmd.OutOrStdout(), info)
+				return nil
+			}
+			output, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			return nil
+		},
+	}
+	versionCmd.Flags().BoolVar(&asJSON, "json", false, "Print the version information as JSON")
+	return versionCmd
+}
+
+func newDoctorCmd(opts *options) *cobra.Command {
+	var sample int
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that the schemas can be found and used",
+		Long: `doctor checks that the schema directory exists and is readable, that it
+holds schemas for the edition and registers resource types, parses a sample
+of the schema files and checks the target version, printing how to fix each
+problem it finds.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			failed := 0
+			for _, check := range mcheck.Doctor(opts.SchemaDir, opts.Edition, opts.Version, sample) {
+				fmt.Fprint(cmd.OutOrStdout(), check)
+				if !check.OK {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("doctor found %d problems", failed)
+			}
+			return nil
+		},
+	}
+	doctorCmd.Flags().IntVar(&sample, "sample", 20, "Number of schema files to parse, or 0 for all of them")
+	return doctorCmd
+}
+
+func newHookCmd(opts *options) *cobra.Command {
+	hookCmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Validate datapack files in a git pre-commit hook",
+	}
+
+	var force bool
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install a pre-commit hook validating the datapack files being committed",
+		Long: `install writes a pre-commit hook to the git repository in the working
+directory that runs mcheck hook run. A pre-commit hook mcheck did not write
+is only replaced with --force. Flags like --version given to install are passed
+on to the hook.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			executable, err := os.Executable()
+			if err != nil {
+				return err
+			}
+			var flags []string
+			cmd.Root().PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+				value := flag.Value.String()
+				switch {
+				case !flag.Changed || flag.Hidden:
+					return
+				case flag.Name == "schema-dir" || flag.Name == "template":
+					value, _ = filepath.Abs(value)
+				case flag.Name == "extra-schemas":
+					dirs := flag.Value.(pflag.SliceValue).GetSlice()
+					for i := range dirs {
+						dirs[i], _ = filepath.Abs(dirs[i])
+					}
+					value = strings.Join(dirs, ",")
+				case flag.Value.Type() == "stringSlice":
+					value = strings.Join(flag.Value.(pflag.SliceValue).GetSlice(), ",")
+				}
+				flags = append(flags, "--"+flag.Name+"="+value)
+			})
+			hook, err := mcheck.InstallHook(".", mcheck.PreCommitHook(executable, flags), force)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "installed %s\n", hook)
+			return nil
+		},
+	}
+	installCmd.Flags().BoolVar(&force, "force", false, "Replace an existing pre-commit hook")
+
+	runCmd := &cobra.Command{
+		Use:   "run",
+		Short: "Validate the datapack files staged for commit",
+		Long: `run validates the JSON and NBT files within data directories that are
+staged for commit, as validate does. Files are read from the working tree, so
+changes that are not staged are checked as well.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := mcheck.StagedFiles(".")
+			if err != nil || len(files) == 0 {
+				return err
+			}
+			return runValidate(opts, files)
+		},
+	}
+
+	hookCmd.AddCommand(installCmd, runCmd)
+	return hookCmd
+}
+
+func newRulesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rules",
+		Short: "List the rule ids reported with findings",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			for _, rule := range mcheck.Rules {
+				fmt.Printf("%s  %-20s %s\n", rule.ID, rule.Name, rule.Description)
+			}
+		},
+	}
+}
+
+func newExplainCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain <rule> [<file>...]",
+		Short: "Explain a rule and its findings in files",
+		Long: `explain describes a rule, given by id like MCHECK010 or by name like
+out-of-range. For each finding of the rule in the given files it prints the
+schema declaration of the offending value, the version bounds that applied to
+it and example values the schema accepts, all taken from the parsed schemas.`,
+		Args: cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return []string{"json"}, cobra.ShellCompDirectiveFilterFileExt
+			}
+			var ids []string
+			for _, rule := range mcheck.Rules {
+				ids = append(ids, rule.ID+"\t"+rule.Name)
+			}
+			return ids, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rule, ok := mcheck.LookupRule(args[0])
+			if !ok {
+				return fmt.Errorf("unknown rule %s, see mcheck rules", args[0])
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "%s %s: %s\n", rule.ID, rule.Name, rule.Description)
+			if len(args) == 1 {
+				return nil
+			}
+
+			validator, err := opts.validator()
+			if err != nil {
+				return err
+			}
+			for _, file := range args[1:] {
+				for _, finding := range validator.CheckFile(file, file) {
+					if finding.Rule != rule.ID {
+						continue
+					}
+					fmt.Fprintf(out, "\n%s\n", finding)
+					explanation, err := validator.Explain(file, finding)
+					if err != nil {
+						fmt.Fprintf(out, "  no explanation: %v\n", err)
+						continue
+					}
+					fmt.Fprint(out, explanation)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func newGenerateSampleCmd(opts *options) *cobra.Command {
+	var count int
+	var seed int64
+	generateCmd := &cobra.Command{
+		Use:   "generate-sample <resource-type>",
+		Short: "Print random documents that the schema of a resource type accepts",
+		Long: `generate-sample builds random documents of a resource type, like
+worldgen/biome, following the types, ranges, enums and dispatches of its
+schema for the target version, and prints each as JSON. The same seed gives
+the same documents.`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completeResourceTypes(opts)(cmd, args, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validator, err := opts.validator()
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("seed") {
+				seed = time.Now().UnixNano()
+			}
+			for i := 0; i < count; i++ {
+				sample, err := validator.GenerateSample(args[0], seed+int64(i))
+				if err != nil {
+					return err
+				}
+				output, err := validator.MarshalDocument(args[0], sample)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(output))
+			}
+			return nil
+		},
+	}
+	generateCmd.Flags().IntVarP(&count, "count", "n", 1, "Number of documents to print")
+	generateCmd.Flags().Int64Var(&seed, "seed", 0, "Seed of the random documents, random unless set")
+	return generateCmd
+}
+
+func newDocsCmd(opts *options) *cobra.Command {
+	var out, versions string
+	var markdown bool
+	docsCmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Write documentation of the schemas of every resource type",
+		Long: `docs writes a page for each resource type with a schema to
+<out>/<version>/<type>, like site/1.21.4/worldgen/biome.html or .md with --markdown, with a table of
+the fields of each type it uses, their version and feature gates and their
+docs, linked to the types and resources they name. The types are resolved
+like they are for validation, so the pages describe what is validated in
+that version. Each version gets an index, and <out> an index of the versions.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := "html"
+			if markdown {
+				format = "markdown"
+			}
+			targets := []string{opts.Version}
+			if versions != "" {
+				parsed, err := mcheck.ParseVersions(versions)
+				if err != nil {
+					return fmt.Errorf("invalid --versions: %w", err)
+				}
+				targets = targets[:0]
+				for _, version := range parsed {
+					targets = append(targets, version.String())
+				}
+			}
+
+			for _, version := range targets {
+				versionOpts := *opts
+				versionOpts.Version = version
+				validator, err := versionOpts.validator()
+				if err != nil {
+					return err
+				}
+				pages, problems := validator.DocPages(format)
+				for _, problem := range problems {
+					fmt.Fprintf(os.Stderr, "warning: %s: %v\n", version, problem)
+				}
+				if err := mcheck.WriteDocs(out, format, version, pages); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: documented %d resource types\n", version, len(pages))
+			}
+			return mcheck.WriteDocsVersions(out, format, targets)
+		},
+	}
+	docsCmd.Flags().StringVarP(&out, "out", "o", "site", "Directory to write the pages to")
+	docsCmd.Flags().BoolVar(&markdown, "markdown", false, "Write the pages as markdown instead of HTML")
+	docsCmd.Flags().StringVar(&versions, "versions", "", "Document several versions, like 1.20.1,1.21..1.21.4, instead of --version")
+	return docsCmd
+}
+
+func newMutateCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "mutate <good-json-file> <out-dir>",
+		Short: "Write mutated copies of a valid file that the schema must reject",
+		Long: `mutate deletes required fields and gives values the wrong type, an out of
+range number or a value outside their enum, writing each mutation to
+<out-dir>/data/<type>/<name>.<rule>.<path>.json where rule names the rule
+expected to reject it.`,
+		Args:   cobra.ExactArgs(2),
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validator, err := opts.validator()
+			if err != nil {
+				return err
+			}
+			return validator.WriteMutations(args[0], args[1])
+		},
+	}
+}
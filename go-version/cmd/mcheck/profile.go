@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+
+	"github.com/spf13/pflag"
+)
+
+// profiles are the profiles and execution trace the hidden --cpuprofile,
+// --memprofile and --trace flags ask for, for profiling mcheck on big packs
+// without changing it. The files can be read with go tool pprof and go tool
+// trace.
+type profiles struct {
+	cpu, mem, trace string
+
+	started bool
+	stops   []func() error // stop the running profiles, last started first
+}
+
+func (p *profiles) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&p.cpu, "cpuprofile", "", "Write a CPU profile to this file")
+	flags.StringVar(&p.mem, "memprofile", "", "Write a heap profile to this file when the command ends")
+	flags.StringVar(&p.trace, "trace", "", "Write an execution trace to this file")
+	for _, name := range []string{"cpuprofile", "memprofile", "trace"} {
+		flags.MarkHidden(name)
+	}
+}
+
+// start starts the CPU profile and the execution trace
+func (p *profiles) start() error {
+	p.started = true
+	if p.cpu != "" {
+		f, err := os.Create(p.cpu)
+		if err != nil {
+			return fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		p.stops = append(p.stops, func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		})
+	}
+	if p.trace != "" {
+		f, err := os.Create(p.trace)
+		if err != nil {
+			return fmt.Errorf("failed to create trace: %w", err)
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to start trace: %w", err)
+		}
+		p.stops = append(p.stops, func() error {
+			trace.Stop()
+			return f.Close()
+		})
+	}
+	return nil
+}
+
+// stop stops the running profiles and writes the heap profile. It does
+// nothing if the profiles were never started, like when parsing the flags
+// failed.
+func (p *profiles) stop() error {
+	if !p.started {
+		return nil
+	}
+	p.started = false
+	var errs []error
+	for i := len(p.stops) - 1; i >= 0; i-- {
+		errs = append(errs, p.stops[i]())
+	}
+	p.stops = nil
+
+	if p.mem != "" {
+		errs = append(errs, writeHeapProfile(p.mem))
+	}
+	return errors.Join(errs...)
+}
+
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create heap profile: %w", err)
+	}
+	defer f.Close()
+	// Collect garbage so the profile shows the memory still in use
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		return fmt.Errorf("failed to write heap profile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	p := &profiles{
+		cpu:   filepath.Join(dir, "cpu.pprof"),
+		mem:   filepath.Join(dir, "mem.pprof"),
+		trace: filepath.Join(dir, "trace.out"),
+	}
+	if err := p.stop(); err != nil {
+		t.Fatalf("Stopping profiles that never started failed: %v", err)
+	}
+	if _, err := os.Stat(p.mem); err == nil {
+		t.Error("Expected no heap profile before the profiles started")
+	}
+
+	if err := p.start(); err != nil {
+		t.Fatalf("Starting profiles failed: %v", err)
+	}
+	if err := p.stop(); err != nil {
+		t.Fatalf("Stopping profiles failed: %v", err)
+	}
+	for _, file := range []string{p.cpu, p.mem, p.trace} {
+		if info, err := os.Stat(file); err != nil || info.Size() == 0 {
+			t.Errorf("Expected %s to be written, got %v", filepath.Base(file), err)
+		}
+	}
+
+	bad := &profiles{cpu: filepath.Join(dir, "missing", "cpu.pprof")}
+	if err := bad.start(); err == nil {
+		t.Error("Expected an error for a profile in a missing directory")
+	}
+	bad.stop()
+}
@@ -0,0 +1,299 @@
+package mcheck
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Resource directories commands name ids of; most were singularized in 1.21
+var (
+	lootTableTypes    = []string{"loot_table", "loot_tables"}
+	advancementTypes  = []string{"advancement", "advancements"}
+	recipeTypes       = []string{"recipe", "recipes"}
+	predicateTypes    = []string{"predicate", "predicates"}
+	itemModifierTypes = []string{"item_modifier", "item_modifiers"}
+)
+
+// commandIDPattern matches a command argument that is a resource id, with an
+// optional namespace and, for tags, a leading #
+var commandIDPattern = regexp.MustCompile(`^#?([a-z0-9_.-]+:)?[a-z0-9_./-]+$`)
+
+// commandReference is a resource a command names
+type commandReference struct {
+	id    string
+	types []string
+	kind  string // like "loot table", to describe the reference
+}
+
+// functionCommandReferences finds the resources named by the commands of
+// functions: the functions they run, the loot tables they drop, the
+// advancements and recipes they grant, the predicates and item modifiers they
+// use and the registry entries the components of given items name. Commands
+// run by execute are included, macro lines are skipped since their arguments
+// are only known when the macro runs.
+//
+// Like the other extractors, only ids in namespaces the pack defines must
+// resolve. Vanilla defines no functions, so functions in the minecraft
+// namespace must resolve too, while other resources there may be vanilla.
+func functionCommandReferences(pack *Pack) []Reference {
+	var references []Reference
+	for _, functionType := range functionTypes {
+		for _, file := range sortedFiles(pack.Resources[functionType]) {
+			data, err := pack.files.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			for _, line := range commandLines(string(data)) {
+				for _, found := range commandReferences(commandArgs(line.text)) {
+					id := normalizeID(strings.TrimPrefix(found.id, "#"))
+					minecraft := strings.HasPrefix(id, "minecraft:") && found.kind != "function" && found.kind != "function tag"
+					if !pack.Defines(id) || minecraft {
+						continue
+					}
+					references = append(references, Reference{
+						File:        file,
+						Path:        []string{fmt.Sprintf("line %d", line.number)},
+						ID:          id,
+						Types:       found.types,
+						Description: fmt.Sprintf("%s %s", found.kind, id),
+					})
+				}
+			}
+		}
+	}
+	return references
+}
+
+// commandLine is a command of a function and the line it starts on
+type commandLine struct {
+	number int
+	text   string
+}
+
+// commandLines splits a function into its commands, joining lines continued
+// with a trailing backslash and skipping blank lines, comments and macro lines
+func commandLines(source string) []commandLine {
+	var commands []commandLine
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		number := i + 1
+		text := strings.TrimSpace(lines[i])
+		for strings.HasSuffix(text, "\\") && i+1 < len(lines) {
+			i++
+			text = strings.TrimSuffix(text, "\\") + strings.TrimSpace(lines[i])
+		}
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "$") {
+			continue
+		}
+		commands = append(commands, commandLine{number, text})
+	}
+	return commands
+}
+
+// commandArgs splits a command into its arguments at spaces outside of
+// brackets, braces and quotes, so that selectors like @e[tag=a, limit=1] and
+// items with components stay one argument
+func commandArgs(command string) []string {
+	var args []string
+	depth, start := 0, -1
+	var quote byte
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{' || c == '(':
+			depth++
+		case c == ']' || c == '}' || c == ')':
+			depth--
+		case c == ' ' && depth <= 0:
+			if start >= 0 {
+				args = append(args, command[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		args = append(args, command[start:])
+	}
+	return args
+}
+
+// commandReferences returns the resources a command names
+func commandReferences(args []string) []commandReference {
+	if len(args) == 0 {
+		return nil
+	}
+	var references []commandReference
+	add := func(id string, types []string, kind string) {
+		if !commandIDPattern.MatchString(id) {
+			return
+		}
+		references = append(references, commandReference{id, types, kind})
+	}
+	addFunction := func(id string) {
+		if strings.HasPrefix(id, "#") {
+			add(id, functionTagTypes, "function tag")
+		} else {
+			add(id, functionTypes, "function")
+		}
+	}
+	arg := func(i int) string {
+		if i < len(args) {
+			return args[i]
+		}
+		return ""
+	}
+
+	switch strings.TrimPrefix(args[0], "/") {
+	case "function":
+		// function <id> [<arguments> | with <source>]
+		addFunction(arg(1))
+	case "schedule":
+		// schedule function <id> <time> [append | replace]
+		if arg(1) == "function" {
+			addFunction(arg(2))
+		}
+	case "execute":
+		// Conditions on functions and predicates, then the command run
+		for i := 1; i < len(args); i++ {
+			switch {
+			case args[i] == "run":
+				return append(references, commandReferences(args[i+1:])...)
+			case (args[i] == "if" || args[i] == "unless") && arg(i+1) == "function":
+				addFunction(arg(i + 2))
+			case (args[i] == "if" || args[i] == "unless") && arg(i+1) == "predicate":
+				add(arg(i+2), predicateTypes, "predicate")
+			}
+		}
+	case "loot":
+		// loot <target> (loot <id> | fish <id> <pos> ...); the target is
+		// one to five arguments, none of them loot or fish
+		for i := 2; i < len(args); i++ {
+			if args[i] == "loot" || args[i] == "fish" {
+				add(arg(i+1), lootTableTypes, "loot table")
+				break
+			}
+		}
+	case "advancement":
+		// advancement (grant | revoke) <targets> (only | from | until | through) <id>
+		switch arg(3) {
+		case "only", "from", "until", "through":
+			add(arg(4), advancementTypes, "advancement")
+		}
+	case "recipe":
+		// recipe (give | take) <targets> (<id> | *)
+		add(arg(3), recipeTypes, "recipe")
+	case "item":
+		// item modify (block <pos> | entity <targets>) <slot> <modifier>
+		if arg(1) == "modify" {
+			switch arg(2) {
+			case "block":
+				add(arg(7), itemModifierTypes, "item modifier")
+			case "entity":
+				add(arg(5), itemModifierTypes, "item modifier")
+			}
+		}
+	case "give":
+		// give <targets> <item>[<components>] [<count>]
+		for _, found := range componentReferences(arg(2)) {
+			add(found.id, found.types, found.kind)
+		}
+	}
+	return references
+}
+
+// componentField is a field of an item component holding registry ids
+type componentField struct {
+	ids   *regexp.Regexp // matches the ids of the field, in the first group
+	types []string
+	kind  string
+}
+
+// componentIDPattern matches the namespaced ids in an SNBT component value
+const componentIDPattern = `[a-z0-9_.-]+:[a-z0-9_./-]+`
+
+var anyComponentID = regexp.MustCompile(`(` + componentIDPattern + `)`)
+
+// componentKey matches the ids given to a key of a component value
+func componentKey(key string) *regexp.Regexp {
+	return regexp.MustCompile(`\b` + key + `\s*:\s*["']?(` + componentIDPattern + `)`)
+}
+
+// componentFields are the item components naming entries of registries packs
+// can add to, like the enchantments of an item
+var componentFields = map[string][]componentField{
+	"enchantments":        {{anyComponentID, []string{"enchantment"}, "enchantment"}},
+	"stored_enchantments": {{anyComponentID, []string{"enchantment"}, "enchantment"}},
+	"jukebox_playable":    {{anyComponentID, []string{"jukebox_song"}, "jukebox song"}},
+	"instrument":          {{anyComponentID, []string{"instrument"}, "instrument"}},
+	"trim": {
+		{componentKey("pattern"), []string{"trim_pattern"}, "trim pattern"},
+		{componentKey("material"), []string{"trim_material"}, "trim material"},
+	},
+}
+
+// componentReferences returns the registry entries named by the components of
+// an item argument, like stick[enchantments={"test:zap":1}]. Components are
+// not parsed as SNBT; the ids are picked out of their text.
+func componentReferences(item string) []commandReference {
+	open := strings.IndexByte(item, '[')
+	if open < 0 || !strings.HasSuffix(item, "]") {
+		return nil
+	}
+
+	var references []commandReference
+	for _, component := range splitTopLevel(item[open+1:len(item)-1], ",") {
+		name, value, ok := strings.Cut(component, "=")
+		if !ok {
+			continue
+		}
+		name = strings.TrimPrefix(strings.TrimSpace(name), "minecraft:")
+		for _, field := range componentFields[name] {
+			for _, match := range field.ids.FindAllStringSubmatch(value, -1) {
+				references = append(references, commandReference{match[1], field.types, field.kind})
+			}
+		}
+	}
+	return references
+}
+
+// splitTopLevel splits the components of an item at separators outside of
+// nested values and quotes
+func splitTopLevel(components, separators string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(components); i++ {
+		c := components[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case depth == 0 && strings.IndexByte(separators, c) >= 0:
+			parts = append(parts, components[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, components[start:])
+}
@@ -0,0 +1,82 @@
+package mcheck
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommandArgs(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected []string
+	}{
+		{"say hi", []string{"say", "hi"}},
+		{"execute as @e[tag=a, limit=1] run  function test:go", []string{"execute", "as", "@e[tag=a, limit=1]", "run", "function", "test:go"}},
+		{`give @s stick[custom_name='"a ] b"', enchantments={"test:zap": 1}] 2`, []string{"give", "@s", `stick[custom_name='"a ] b"', enchantments={"test:zap": 1}]`, "2"}},
+		{`function test:macro {name: "x y"}`, []string{"function", "test:macro", `{name: "x y"}`}},
+	}
+	for _, tt := range tests {
+		if args := commandArgs(tt.command); !reflect.DeepEqual(args, tt.expected) {
+			t.Errorf("commandArgs(%q) = %q, expected %q", tt.command, args, tt.expected)
+		}
+	}
+}
+
+func TestFunctionCommandReferences(t *testing.T) {
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/function/main.mcfunction": []byte(strings.Join([]string{
+			"# function test:commented",
+			"function test:helper",
+			"function #test:hooks",
+			"function test:missing {value: 1}",
+			"execute if function test:check unless predicate test:raining run loot give @s loot test:reward",
+			"execute as @a at @s run advancement grant @s only test:first_steps",
+			"recipe give @a test:gadget",
+			"recipe take @a *",
+			"loot spawn ~ ~ ~ fish test:fishing ~ ~ ~ mainhand",
+			"loot replace entity @s weapon.mainhand loot minecraft:chests/simple_dungeon",
+			"schedule function test:later 10t",
+			"item modify entity @s weapon.mainhand test:sharpen",
+			"give @s diamond_sword[enchantments={levels:{\"test:zap\":1,\"minecraft:sharpness\":2}}, \\",
+			"  trim={pattern:\"test:stripes\",material:\"minecraft:gold\"}] 1",
+			"give @s stick",
+			"$function test:$(name)",
+			"function other:library",
+		}, "\n")),
+		"data/test/function/helper.mcfunction":   []byte("say help\n"),
+		"data/test/function/check.mcfunction":    []byte("return 1\n"),
+		"data/test/tags/function/hooks.json":     []byte(`{"values": ["test:helper"]}`),
+		"data/test/loot_table/reward.json":       []byte(`{"pools": []}`),
+		"data/test/advancement/first_steps.json": []byte(`{"criteria": {"tick": {"trigger": "minecraft:tick"}}}`),
+		"data/test/predicate/raining.json":       []byte(`{"condition": "minecraft:weather_check", "raining": true}`),
+		"data/test/enchantment/zap.json":         []byte(`{}`),
+		"data/minecraft/tags/function/load.json": []byte(`{"values": ["test:main"]}`),
+	})
+
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+
+	var messages []string
+	for _, finding := range checkReferences(pack, PackOptions{}) {
+		messages = append(messages, finding.String())
+	}
+	expected := []string{
+		"data/test/function/main.mcfunction: at line 4: function test:missing not found in pack [MCHECK040 missing-reference]",
+		"data/test/function/main.mcfunction: at line 7: recipe test:gadget not found in pack [MCHECK040 missing-reference]",
+		"data/test/function/main.mcfunction: at line 9: loot table test:fishing not found in pack [MCHECK040 missing-reference]",
+		"data/test/function/main.mcfunction: at line 11: function test:later not found in pack [MCHECK040 missing-reference]",
+		"data/test/function/main.mcfunction: at line 12: item modifier test:sharpen not found in pack [MCHECK040 missing-reference]",
+		"data/test/function/main.mcfunction: at line 13: trim pattern test:stripes not found in pack [MCHECK040 missing-reference]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+
+	// Resources commands name are used
+	for _, finding := range checkUnused(pack, PackOptions{ReportUnused: true}) {
+		t.Errorf("Unexpected unused resource: %s", finding)
+	}
+}
@@ -0,0 +1,75 @@
+package mcheck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config holds the settings that change how files are checked, as the
+// persistent flags of mcheck set them. The daemon keeps a validator for
+// each config it is sent.
+type Config struct {
+	Version        string   `json:"version"`
+	SchemaDir      string   `json:"schema_dir"` // absolute when sent to the daemon, since it runs elsewhere
+	Edition        string   `json:"edition"`
+	Lenient        bool     `json:"lenient,omitempty"`
+	Features       []string `json:"features,omitempty"`
+	InclusiveUntil bool     `json:"inclusive_until,omitempty"`
+	StrictSchema   bool     `json:"strict_schema,omitempty"`
+	WarnIncomplete bool     `json:"warn_incomplete,omitempty"`
+	ResourceType   string   `json:"resource_type,omitempty"`
+	ExtraSchemas   []string `json:"extra_schemas,omitempty"` // absolute when sent to the daemon, like SchemaDir
+}
+
+// NewValidator creates the validator a config describes, looking for the
+// schema directory if it names none
+func (c Config) NewValidator() (*PEGMCDocValidator, error) {
+	targetVersion, err := ParseVersion(c.Version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version format: %w", err)
+	}
+
+	schemaDir, schemas, err := findSchemaDir(c.SchemaDir)
+	if err != nil {
+		return nil, err
+	}
+
+	validator := NewPEGMCDocValidator(targetVersion, schemaDir)
+	validator.schemas = schemas
+	validator.SetLenient(c.Lenient)
+	if err := validator.SetEdition(c.Edition); err != nil {
+		return nil, err
+	}
+	if _, err := schemas.Stat(filepath.Join(schemaDir, c.Edition)); err != nil {
+		return nil, fmt.Errorf("schema directory %s has no %s schemas", schemaDir, c.Edition)
+	}
+	for _, dir := range c.ExtraSchemas {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("extra schema directory %s does not exist", dir)
+		}
+	}
+	validator.SetExtraSchemas(c.ExtraSchemas)
+	validator.SetResourceType(c.ResourceType)
+	validator.SetFeatures(c.Features)
+	validator.SetInclusiveUntil(c.InclusiveUntil)
+	validator.SetStrictSchema(c.StrictSchema)
+	validator.SetWarnIncomplete(c.WarnIncomplete)
+	return validator, nil
+}
+
+// findSchemaDir returns the schema directory to use and the files to read
+// it from, looking for vanilla-mcdoc in the working directory if none was
+// given and falling back to the schemas embedded in release binaries
+func findSchemaDir(schemaDir string) (string, fileSystem, error) {
+	if schemaDir != "" {
+		return schemaDir, osFiles, nil
+	}
+	if _, err := os.Stat("vanilla-mcdoc"); err == nil {
+		return "vanilla-mcdoc", osFiles, nil
+	}
+	if embeddedSchemas != nil {
+		return embeddedSchemaDir(embeddedSchemas)
+	}
+	return "", osFiles, fmt.Errorf("schema directory not found, please specify with --schema-dir")
+}
@@ -0,0 +1,369 @@
+package mcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDaemonSocket is where mcheck daemon listens and --daemon connects
+// unless given another socket
+func DefaultDaemonSocket() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, fmt.Sprintf("mcheck-%d.sock", os.Getuid()))
+}
+
+// daemonRequest asks the daemon to validate files, like mcheck validate, or
+// datapacks, like mcheck pack. Paths are relative to Dir, the working
+// directory of the client, and findings name files as the request does.
+type daemonRequest struct {
+	Config      Config      `json:"config"`
+	Dir         string      `json:"dir"`
+	Files       []string    `json:"files,omitempty"`
+	Packs       []string    `json:"packs,omitempty"` // in load order
+	PackOptions PackOptions `json:"pack_options"`
+}
+
+// daemonResponse holds the findings of a request, or why it failed
+type daemonResponse struct {
+	Findings []Finding `json:"findings"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// daemon keeps validators and vanilla datapacks in memory between
+// requests, so that each schema is parsed and converted once for all of
+// them
+type daemon struct {
+	mu         sync.Mutex
+	validators map[string]*PEGMCDocValidator // by their config as JSON
+	vanilla    map[string]*Pack              // vanilla datapacks by directory
+	watchers   map[string]*schemaWatcher     // by the schema directories and extra schemas of the validators
+}
+
+func newDaemon() *daemon {
+	return &daemon{
+		validators: make(map[string]*PEGMCDocValidator),
+		vanilla:    make(map[string]*Pack),
+		watchers:   make(map[string]*schemaWatcher),
+	}
+}
+
+// validator returns the validator of a config, creating it on first use
+func (d *daemon) validator(config Config) (*PEGMCDocValidator, error) {
+	key, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if validator, ok := d.validators[string(key)]; ok {
+		return validator, nil
+	}
+	validator, err := config.NewValidator()
+	if err != nil {
+		return nil, err
+	}
+	d.validators[string(key)] = validator
+	for _, dir := range validator.schemaDirs() {
+		if d.watchers[dir] == nil {
+			d.watchers[dir] = newSchemaWatcher(dir)
+		}
+	}
+	return validator, nil
+}
+
+// watch reloads the schema files that change below the schema directories
+// of the validators, looking every interval until ctx is done, so that the
+// edits of schema authors apply to the next request
+func (d *daemon) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.reload()
+		}
+	}
+}
+
+// reload reloads the changed schema files of each schema directory in the
+// validators using it, as their schema directory or as extra schemas
+func (d *daemon) reload() {
+	d.mu.Lock()
+	watchers := maps.Clone(d.watchers)
+	validators := slices.Collect(maps.Values(d.validators))
+	d.mu.Unlock()
+
+	for dir, watcher := range watchers {
+		changed := watcher.changes()
+		if len(changed) == 0 {
+			continue
+		}
+		dropped := 0
+		for _, validator := range validators {
+			if slices.Contains(validator.schemaDirs(), dir) {
+				dropped += validator.reloadSchemas(changed)
+			}
+		}
+		logReload(changed, dropped)
+	}
+}
+
+// vanillaPack returns the vanilla datapack in dir, loading it on first use
+func (d *daemon) vanillaPack(ctx context.Context, dir string) (*Pack, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if pack, ok := d.vanilla[dir]; ok {
+		return pack, nil
+	}
+	pack, err := LoadPackContext(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("vanilla datapack: %w", err)
+	}
+	d.vanilla[dir] = pack
+	return pack, nil
+}
+
+// warm loads the schema of every resource type, so that the first request
+// does not wait for them
+func (d *daemon) warm(validator *PEGMCDocValidator) {
+	start := time.Now()
+	for _, resourceType := range validator.ResourceTypes() {
+		if schemaPath, err := validator.SchemaPath(resourceType); err == nil {
+			validator.convertedSchema(schemaPath)
+		}
+	}
+	log.Printf("loaded the schemas of %d resource types in %s", len(validator.ResourceTypes()), time.Since(start).Round(time.Millisecond))
+}
+
+// check validates what a request names
+func (d *daemon) check(ctx context.Context, request daemonRequest) ([]Finding, error) {
+	validator, err := d.validator(request.Config)
+	if err != nil {
+		return nil, err
+	}
+	resolve := func(file string) string {
+		if filepath.IsAbs(file) {
+			return file
+		}
+		return filepath.Join(request.Dir, file)
+	}
+
+	var findings []Finding
+	for _, file := range request.Files {
+		fileFindings, err := validator.CheckFileContext(ctx, resolve(file), file)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, fileFindings...)
+	}
+	if len(request.Packs) == 0 {
+		return findings, nil
+	}
+
+	opts := request.PackOptions
+	if opts.Vanilla != "" {
+		opts.Vanilla = resolve(opts.Vanilla)
+		if opts.vanillaPack, err = d.vanillaPack(ctx, opts.Vanilla); err != nil {
+			return nil, err
+		}
+	}
+	roots := make([]string, len(request.Packs))
+	for i, root := range request.Packs {
+		roots[i] = resolve(root)
+	}
+	var packFindings []Finding
+	if len(roots) == 1 {
+		packFindings, err = validator.CheckPack(ctx, roots[0], nil, opts)
+	} else {
+		packFindings, err = validator.CheckPacks(ctx, roots, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Packs are named by their root, which was made absolute
+	for _, finding := range packFindings {
+		if rel, err := filepath.Rel(request.Dir, filepath.FromSlash(finding.File)); err == nil && filepath.IsAbs(finding.File) && !strings.HasPrefix(rel, "..") {
+			finding.File = filepath.ToSlash(rel)
+		}
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+// handler serves POST /check with a daemonRequest
+func (d *daemon) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/check", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "check requires POST", http.StatusMethodNotAllowed)
+			return
+		}
+		var request daemonRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var response daemonResponse
+		findings, err := d.check(r.Context(), request)
+		if err != nil {
+			response.Error = err.Error()
+		} else {
+			response.Findings = findings
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+	return mux
+}
+
+// ServeDaemon runs a daemon on a unix socket until ctx is done, with the
+// schemas of every resource type loaded for config and reloaded as they
+// change
+func ServeDaemon(ctx context.Context, socket string, config Config) error {
+	// The validator is kept under the config clients send, so that their
+	// first requests find its schemas loaded
+	request, err := config.daemonRequest()
+	if err != nil {
+		return err
+	}
+	d := newDaemon()
+	validator, err := d.validator(request.Config)
+	if err != nil {
+		return err
+	}
+	go d.warm(validator)
+
+	listener, err := listenDaemon(socket)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: d.handler()}
+	go d.watch(ctx, schemaPollInterval)
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+	log.Printf("listening on %s", socket)
+	if err := server.Serve(listener); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// listenDaemon listens on a unix socket, replacing the socket of a daemon
+// that is no longer running but refusing to take over one that is
+func listenDaemon(socket string) (net.Listener, error) {
+	if _, err := os.Stat(socket); err == nil {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("a daemon is already listening on %s", socket)
+		}
+		if err := os.Remove(socket); err != nil {
+			return nil, err
+		}
+	}
+	return net.Listen("unix", socket)
+}
+
+// daemonClient sends requests to the daemon listening on a socket
+type daemonClient struct {
+	socket string
+	client *http.Client
+}
+
+func newDaemonClient(socket string) *daemonClient {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		},
+	}
+	return &daemonClient{socket: socket, client: &http.Client{Transport: transport}}
+}
+
+// check sends a request and returns its findings
+func (c *daemonClient) check(request daemonRequest) ([]Finding, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Post("http://mcheck/check", "application/json", bytes.NewReader(body))
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return nil, fmt.Errorf("no mcheck daemon is listening on %s, start one with mcheck daemon", c.socket)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("the daemon answered %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	var response daemonResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("invalid response from the daemon: %w", err)
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return response.Findings, nil
+}
+
+// daemonRequest describes a request for the config, with paths relative to
+// the working directory. Embedded schemas are left for the daemon to find in
+// its own binary.
+func (c Config) daemonRequest() (daemonRequest, error) {
+	schemaDir, schemas, err := findSchemaDir(c.SchemaDir)
+	if err != nil {
+		return daemonRequest{}, err
+	}
+	if schemas.fsys == nil {
+		if c.SchemaDir, err = filepath.Abs(schemaDir); err != nil {
+			return daemonRequest{}, err
+		}
+	}
+	extraSchemas := make([]string, len(c.ExtraSchemas))
+	for i, dir := range c.ExtraSchemas {
+		if extraSchemas[i], err = filepath.Abs(dir); err != nil {
+			return daemonRequest{}, err
+		}
+	}
+	c.ExtraSchemas = extraSchemas
+	dir, err := os.Getwd()
+	if err != nil {
+		return daemonRequest{}, err
+	}
+	return daemonRequest{Dir: dir, Config: c}, nil
+}
+
+// CheckWithDaemon validates files, or datapacks, through the daemon
+// listening on socket, as a validator of the config would
+func (c Config) CheckWithDaemon(socket string, files, packs []string, packOptions PackOptions) ([]Finding, error) {
+	request, err := c.daemonRequest()
+	if err != nil {
+		return nil, err
+	}
+	request.Files, request.Packs, request.PackOptions = files, packs, packOptions
+	return newDaemonClient(socket).check(request)
+}
@@ -0,0 +1,136 @@
+package mcheck
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// startDaemon serves a daemon on a socket in a short temporary directory,
+// since socket paths are limited to about a hundred bytes
+func startDaemon(t *testing.T) (string, *daemon) {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "mcheck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "d.sock")
+
+	listener, err := listenDaemon(socket)
+	if err != nil {
+		t.Fatalf("listenDaemon failed: %v", err)
+	}
+	d := newDaemon()
+	server := &http.Server{Handler: d.handler()}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+	return socket, d
+}
+
+func TestDaemon(t *testing.T) {
+	socket, d := startDaemon(t)
+	schemaDir := fixtureSchemaDir(t, "damage_type")
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/damage_type/acid.json": []byte(`{"message_id": "acid", "exhaustion": 0.1, "scaling": "never"}`),
+		"data/test/damage_type/bad.json":  []byte(`{"message_id": "bad", "scaling": "never"}`),
+	})
+	client := newDaemonClient(socket)
+	request := daemonRequest{
+		Config: Config{Version: "1.20.1", SchemaDir: schemaDir, Edition: "java"},
+		Dir:    filepath.Dir(root),
+		Files:  []string{filepath.Join(filepath.Base(root), "data", "test", "damage_type", "bad.json")},
+	}
+
+	findings, err := client.check(request)
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if len(findings) != 1 || findings[0].File != request.Files[0] || !strings.Contains(findings[0].Message, "'exhaustion' is missing") {
+		t.Errorf("Expected the missing exhaustion of bad.json named as requested, got %v", findings)
+	}
+
+	// Packs are checked with the validator of the same config
+	request.Files, request.Packs = nil, []string{filepath.Base(root), filepath.Base(root)}
+	findings, err = client.check(request)
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	expected := filepath.ToSlash(filepath.Join(filepath.Base(root), "data", "test", "damage_type", "bad.json"))
+	if len(findings) == 0 || findings[0].File != expected {
+		t.Errorf("Expected findings named by the pack as requested, like %s, got %v", expected, findings)
+	}
+	if len(d.validators) != 1 {
+		t.Errorf("Expected one validator for both requests, got %d", len(d.validators))
+	}
+
+	request.Config.Version = "latest"
+	if _, err := client.check(request); err == nil || !strings.Contains(err.Error(), "invalid version") {
+		t.Errorf("Expected the daemon's error, got %v", err)
+	}
+
+	if _, err := listenDaemon(socket); err == nil || !strings.Contains(err.Error(), "already listening") {
+		t.Errorf("Expected a second daemon on the socket to fail, got %v", err)
+	}
+}
+
+func TestDaemonNotRunning(t *testing.T) {
+	dir, err := os.MkdirTemp("", "mcheck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "d.sock")
+
+	if _, err := newDaemonClient(socket).check(daemonRequest{}); err == nil || !strings.Contains(err.Error(), "start one with mcheck daemon") {
+		t.Errorf("Expected to be told to start a daemon, got %v", err)
+	}
+
+	// The socket of a daemon that did not shut down is taken over
+	if err := os.WriteFile(socket, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := listenDaemon(socket)
+	if err != nil {
+		t.Fatalf("Expected a stale socket to be replaced, got %v", err)
+	}
+	listener.Close()
+}
+
+func TestDaemonReloadExtraSchemas(t *testing.T) {
+	extraDir := t.TempDir()
+	schemaPath := filepath.Join(extraDir, "java", "data", "damage_type.mcdoc")
+	if err := os.MkdirAll(filepath.Dir(schemaPath), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	schema, err := os.ReadFile(filepath.Join("tests", "mcdocs", "damage_type.mcdoc"))
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	if err := os.WriteFile(schemaPath, schema, 0o644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	d := newDaemon()
+	validator, err := d.validator(Config{Version: "1.20.1", SchemaDir: fixtureSchemaDir(t, "trim"), Edition: "java", ExtraSchemas: []string{extraDir}})
+	if err != nil {
+		t.Fatalf("validator failed: %v", err)
+	}
+	bad := []byte(`{"message_id": "bad", "scaling": "never"}`)
+	if findings := validator.CheckDocument("data/test/damage_type/bad.json", "bad.json", bad); len(findings) != 1 {
+		t.Fatalf("Expected the missing exhaustion to be found, got %v", findings)
+	}
+
+	// Edits of the extra schemas apply to the next request
+	schema = bytes.Replace(schema, []byte("exhaustion:"), []byte("exhaustion?:"), 1)
+	if err := os.WriteFile(schemaPath, schema, 0o644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	d.reload()
+	if findings := validator.CheckDocument("data/test/damage_type/bad.json", "bad.json", bad); len(findings) != 0 {
+		t.Errorf("Expected the edited extra schema to allow a missing exhaustion, got %v", findings)
+	}
+}
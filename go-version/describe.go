@@ -0,0 +1,173 @@
+package mcheck
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DescribeType returns a short mcdoc-like description of the type a validator accepts
+func DescribeType(v Validator) string {
+	return describeTypeIn(v, nil)
+}
+
+// describeTypeIn describes the type a validator accepts in the version of
+// ctx, leaving out the union alternatives gated out of it. A nil ctx
+// describes every alternative.
+func describeTypeIn(v Validator, ctx *ValidationContext) string {
+	switch t := v.(type) {
+	case *PrimitiveValidator:
+		if t.Unresolved != "" {
+			return t.Type + " (" + t.Unresolved + ")"
+		}
+		return t.Type
+	case *RangeValidator:
+		return describeRange(t)
+	case *ConstrainedValidator:
+		return describeTypeIn(t.InnerValidator, ctx) + " @ " + describeTypeIn(t.Constraint, ctx)
+	case *ArrayValidator:
+		result := "[" + describeTypeIn(t.ElementValidator, ctx) + "]"
+		if t.Typed != "" {
+			result = describeTypeIn(t.ElementValidator, ctx) + "[]"
+		}
+		if t.LengthConstraint != nil {
+			result += " @ " + describeRange(t.LengthConstraint)
+		}
+		return result
+	case *StructValidator:
+		if t.Name != "" {
+			return "struct " + t.Name
+		}
+		return "struct"
+	case *BasicStructValidator:
+		return "struct"
+	case *UnionValidator:
+		all := t.Alternatives
+		if ctx != nil {
+			all = t.applicable(ctx)
+		}
+		var alternatives []string
+		for _, alt := range all {
+			alternatives = append(alternatives, describeTypeIn(alt, ctx))
+		}
+		return "(" + strings.Join(alternatives, " | ") + ")"
+	case *LiteralValidator:
+		if str, ok := t.Value.(string); ok {
+			return strconv.Quote(str)
+		}
+		return fmt.Sprintf("%v", t.Value)
+	case *ReferenceValidator:
+		return t.TypeName
+	case *AttributedValidator:
+		return describeAttributes(t.Attributes) + describeTypeIn(t.InnerValidator, ctx)
+	case *EnumValidator:
+		return fmt.Sprintf("enum(%s) %s", t.Type, t.Name)
+	case *DispatchValidator:
+		if t.Dynamic {
+			return t.Registry + "[[" + t.Key + "]]"
+		}
+		return t.Registry + "[" + t.Key + "]"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// describeBounds formats the gates of a declaration like "since 1.19, until
+// 1.20.4, feature update_1_21"
+func describeBounds(bounds BaseValidator) string {
+	var parts []string
+	if bounds.Since != "" {
+		parts = append(parts, "since "+bounds.Since)
+	}
+	if bounds.Until != "" {
+		parts = append(parts, "until "+bounds.Until)
+	}
+	if bounds.Feature != "" {
+		parts = append(parts, "feature "+bounds.Feature)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeRange formats a range validator using mcdoc range syntax like 0..<10
+func describeRange(rv *RangeValidator) string {
+	result := ""
+	if rv.Min != nil {
+		result += strconv.FormatFloat(*rv.Min, 'g', -1, 64)
+	}
+	if rv.Min != nil && rv.Max != nil && *rv.Min == *rv.Max && !rv.MinExclusive && !rv.MaxExclusive {
+		return result
+	}
+	if rv.MinExclusive {
+		result += "<"
+	}
+	result += ".."
+	if rv.MaxExclusive {
+		result += "<"
+	}
+	if rv.Max != nil {
+		result += strconv.FormatFloat(*rv.Max, 'g', -1, 64)
+	}
+	return result
+}
+
+// describeAttributes formats attributes as #[name=value] prefixes in a stable order
+func describeAttributes(attributes map[string]string) string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := ""
+	for _, name := range names {
+		if attributes[name] == "" {
+			result += "#[" + name + "] "
+		} else {
+			result += "#[" + name + "=" + strconv.Quote(attributes[name]) + "] "
+		}
+	}
+	return result
+}
+
+// dispatchCase is a case of a dispatcher and every key mapping to it, like
+// the legacy_single_pool_element and single_pool_element of one dispatch
+type dispatchCase struct {
+	Keys      []string
+	Validator Validator
+}
+
+// dispatchCases groups the cases of a dispatcher by their validator, so that
+// the keys of a dispatch listing several are described together. Keys are
+// sorted, and cases by their first key.
+func dispatchCases(cases map[string]Validator) []dispatchCase {
+	keys := make([]string, 0, len(cases))
+	for key := range cases {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var grouped []dispatchCase
+	for _, key := range keys {
+		validator := cases[key]
+		found := false
+		for i := range grouped {
+			if sameValidator(grouped[i].Validator, validator) {
+				grouped[i].Keys = append(grouped[i].Keys, key)
+				found = true
+				break
+			}
+		}
+		if !found {
+			grouped = append(grouped, dispatchCase{Keys: []string{key}, Validator: validator})
+		}
+	}
+	return grouped
+}
+
+// sameValidator reports whether a and b are the same validator, without
+// panicking on validators of types that cannot be compared
+func sameValidator(a, b Validator) bool {
+	typ := reflect.TypeOf(a)
+	return typ == reflect.TypeOf(b) && typ != nil && typ.Comparable() && a == b
+}
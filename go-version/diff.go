@@ -0,0 +1,204 @@
+package mcheck
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// PackDiff lists the resources that differ between an old and a new
+// version of a datapack
+type PackDiff struct {
+	Added   []ResourceChange `json:"added"`
+	Removed []ResourceChange `json:"removed"`
+	Changed []ResourceChange `json:"changed"`
+}
+
+// Empty reports whether the packs have the same resources with the same
+// content
+func (d *PackDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ResourceChange is a resource only one of the packs has, or that both
+// have with different content
+type ResourceChange struct {
+	Type string `json:"type"` // resource type, like worldgen/biome
+	ID   string `json:"id"`
+	File string `json:"file"` // slash separated path below the root of the pack that has it, the new one if both do
+
+	// Fields are the values that changed in a JSON resource. Other files,
+	// like functions and structures, are only reported as changed.
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// Field change kinds
+const (
+	FieldAdded   = "added"
+	FieldRemoved = "removed"
+	FieldChanged = "changed"
+)
+
+// FieldChange is a value of a JSON resource that was added, removed or
+// changed. Values are compared after normalization, so that key order,
+// whitespace and the formatting of numbers do not count as changes.
+type FieldChange struct {
+	Path []string    `json:"path"` // like Finding.Path, empty for the whole document
+	Kind string      `json:"kind"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+func (c FieldChange) String() string {
+	at := strings.Join(c.Path, ".")
+	if at == "" {
+		at = "(document)"
+	}
+	switch c.Kind {
+	case FieldAdded:
+		return fmt.Sprintf("+ %s: %s", at, canonicalString(c.New))
+	case FieldRemoved:
+		return fmt.Sprintf("- %s: %s", at, canonicalString(c.Old))
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", at, canonicalString(c.Old), canonicalString(c.New))
+}
+
+// DiffPacks compares the resources of the datapacks in oldRoot and newRoot
+// by resource type and id. JSON resources are compared by their values, as
+// Normalize returns them for the target version, and reported with the
+// fields that differ; other files by their content, normalized as HashPack
+// does.
+func (v *PEGMCDocValidator) DiffPacks(oldRoot, newRoot string) (*PackDiff, error) {
+	oldPack, err := LoadPack(oldRoot)
+	if err != nil {
+		return nil, err
+	}
+	newPack, err := LoadPack(newRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// Empty lists rather than nulls, for the tools reading --json
+	diff := &PackDiff{Added: []ResourceChange{}, Removed: []ResourceChange{}, Changed: []ResourceChange{}}
+	for _, resourceType := range unionKeys(oldPack.Resources, newPack.Resources) {
+		oldFiles, newFiles := oldPack.Resources[resourceType], newPack.Resources[resourceType]
+		for _, id := range unionKeys(oldFiles, newFiles) {
+			oldFile, inOld := oldFiles[id]
+			newFile, inNew := newFiles[id]
+			change := ResourceChange{Type: resourceType, ID: id}
+			switch {
+			case !inOld:
+				change.File = newPack.RelativePath(newFile)
+				diff.Added = append(diff.Added, change)
+			case !inNew:
+				change.File = oldPack.RelativePath(oldFile)
+				diff.Removed = append(diff.Removed, change)
+			default:
+				change.File = newPack.RelativePath(newFile)
+				changed, err := v.diffResource(oldPack, oldFile, newPack, newFile, &change)
+				if err != nil {
+					return nil, err
+				}
+				if changed {
+					diff.Changed = append(diff.Changed, change)
+				}
+			}
+		}
+	}
+	return diff, nil
+}
+
+// diffResource compares the files of a resource both packs have, adding
+// the fields that changed to change
+func (v *PEGMCDocValidator) diffResource(oldPack *Pack, oldFile string, newPack *Pack, newFile string, change *ResourceChange) (bool, error) {
+	oldContent, err := oldPack.files.ReadFile(oldFile)
+	if err != nil {
+		return false, err
+	}
+	newContent, err := newPack.files.ReadFile(newFile)
+	if err != nil {
+		return false, err
+	}
+
+	if strings.EqualFold(path.Ext(oldFile), ".json") && strings.EqualFold(path.Ext(newFile), ".json") {
+		oldValue, oldErr := v.Normalize(oldContent, change.Type, v.targetVersion)
+		newValue, newErr := v.Normalize(newContent, change.Type, v.targetVersion)
+		if oldErr == nil && newErr == nil {
+			if Equal(oldValue, newValue) {
+				return false, nil
+			}
+			diffValues(nil, oldValue, newValue, &change.Fields)
+			return len(change.Fields) > 0, nil
+		}
+	}
+	return !bytes.Equal(normalizeContent(oldFile, oldContent), normalizeContent(newFile, newContent)), nil
+}
+
+// diffValues appends the changes from oldValue to newValue at path,
+// descending into the objects and arrays both have
+func diffValues(at []string, oldValue, newValue interface{}, changes *[]FieldChange) {
+	child := func(segment string) []string {
+		return append(at[:len(at):len(at)], segment)
+	}
+
+	switch o := oldValue.(type) {
+	case map[string]interface{}:
+		if n, ok := newValue.(map[string]interface{}); ok {
+			for _, key := range unionKeys(o, n) {
+				oldElem, inOld := o[key]
+				newElem, inNew := n[key]
+				switch {
+				case !inOld:
+					*changes = append(*changes, FieldChange{Path: child(key), Kind: FieldAdded, New: newElem})
+				case !inNew:
+					*changes = append(*changes, FieldChange{Path: child(key), Kind: FieldRemoved, Old: oldElem})
+				default:
+					diffValues(child(key), oldElem, newElem, changes)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if n, ok := newValue.([]interface{}); ok {
+			for i := 0; i < max(len(o), len(n)); i++ {
+				segment := fmt.Sprintf("[%d]", i)
+				switch {
+				case i >= len(o):
+					*changes = append(*changes, FieldChange{Path: child(segment), Kind: FieldAdded, New: n[i]})
+				case i >= len(n):
+					*changes = append(*changes, FieldChange{Path: child(segment), Kind: FieldRemoved, Old: o[i]})
+				default:
+					diffValues(child(segment), o[i], n[i], changes)
+				}
+			}
+			return
+		}
+	}
+	if !Equal(oldValue, newValue) {
+		*changes = append(*changes, FieldChange{Path: at, Kind: FieldChanged, Old: oldValue, New: newValue})
+	}
+}
+
+// canonicalString writes a canonical value as compact JSON with sorted keys
+func canonicalString(value interface{}) string {
+	var buf bytes.Buffer
+	writeCanonicalJSON(&buf, value)
+	return buf.String()
+}
+
+// unionKeys returns the keys of both maps, sorted
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
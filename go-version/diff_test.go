@@ -0,0 +1,55 @@
+package mcheck
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffPacks(t *testing.T) {
+	oldRoot := writePackFiles(t, map[string][]byte{
+		"data/test/damage_type/acid.json":    []byte(`{"message_id": "acid", "exhaustion": 0.1, "scaling": "never"}`),
+		"data/test/damage_type/frost.json":   []byte(`{"message_id": "frost", "exhaustion": 0, "scaling": "always", "effects": "freezing"}`),
+		"data/test/damage_type/sting.json":   []byte(`{"message_id": "sting", "exhaustion": 0, "scaling": "never"}`),
+		"data/test/worldgen/biome/a.json":    []byte(`{"features": [["test:a"], ["test:b"]], "seed": 12345678901234567890}`),
+		"data/test/function/load.mcfunction": []byte("say hi\r\n"),
+	})
+	newRoot := writePackFiles(t, map[string][]byte{
+		"data/test/damage_type/acid.json":    []byte("{\n  \"scaling\": \"never\",\n  \"exhaustion\": 1e-1,\n  \"message_id\": \"acid\"\n}\n"),
+		"data/test/damage_type/frost.json":   []byte(`{"message_id": "frost", "exhaustion": 0.5, "scaling": "always", "death_message_type": "default"}`),
+		"data/test/damage_type/cut.json":     []byte(`{"message_id": "cut", "exhaustion": 0, "scaling": "never"}`),
+		"data/test/worldgen/biome/a.json":    []byte(`{"features": [["test:a"]], "seed": 12345678901234567891}`),
+		"data/test/function/load.mcfunction": []byte("say hi\n"),
+	})
+
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, fixtureSchemaDir(t, "damage_type"))
+	diff, err := validator.DiffPacks(oldRoot, newRoot)
+	if err != nil {
+		t.Fatalf("DiffPacks failed: %v", err)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].ID != "test:cut" || diff.Added[0].File != "data/test/damage_type/cut.json" {
+		t.Errorf("Expected test:cut to be added, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].ID != "test:sting" {
+		t.Errorf("Expected test:sting to be removed, got %+v", diff.Removed)
+	}
+
+	// Key order, whitespace and number formatting are not changes
+	changed := make(map[string][]string)
+	for _, change := range diff.Changed {
+		for _, field := range change.Fields {
+			changed[change.ID] = append(changed[change.ID], field.String())
+		}
+	}
+	if len(diff.Changed) != 2 {
+		t.Errorf("Expected frost and the biome to change, got %+v", diff.Changed)
+	}
+	expected := map[string]string{
+		"test:frost": `+ death_message_type: "default"; - effects: "freezing"; ~ exhaustion: 0 -> 0.5`,
+		"test:a":     `- features.[1]: ["test:b"]; ~ seed: 12345678901234567890 -> 12345678901234567891`,
+	}
+	for id, fields := range expected {
+		if got := strings.Join(changed[id], "; "); got != fields {
+			t.Errorf("Expected the fields of %s to be %s, got %s", id, fields, got)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:generate peg grammar.peg
+
+// Package mcheck validates Minecraft datapack files against the mcdoc
+// schemas of vanilla-mcdoc, for the version a pack targets. It parses and
+// converts the schemas into validators, checks JSON and NBT files and whole
+// datapacks with the references between them, and reports findings. The
+// mcheck command in cmd/mcheck is a CLI over it.
+package mcheck
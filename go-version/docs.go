@@ -0,0 +1,520 @@
+package mcheck
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// docSpan is a piece of a type description. Types documented elsewhere link
+// to where they are.
+type docSpan struct {
+	Text string
+	Href string
+}
+
+// docRow is a row of a section: a field of a struct, a value of an enum or a
+// case of a dispatcher
+type docRow struct {
+	Name     string
+	Type     []docSpan
+	Optional bool
+	Badges   []string // version and feature gates, like since 1.20.5
+	Doc      string
+	Source   string
+}
+
+// docSection documents a type used by a resource type, the first being the
+// resource type itself
+type docSection struct {
+	Anchor string
+	Title  string
+	Kind   string // struct, enum, dispatch or type
+	Source string
+	Badges []string
+	Type   []docSpan // what a type that is no struct, enum or dispatcher stands for
+	Rows   []docRow
+	Column string // heading of the type column of the rows
+}
+
+// DocPage documents a resource type for a version
+type DocPage struct {
+	Version      string
+	ResourceType string
+	Schema       string // the schema file declaring it
+	Sections     []docSection
+}
+
+// docBuilder builds the sections of a page, documenting each type the first
+// time it is named
+type docBuilder struct {
+	ctx          *ValidationContext
+	resourceType string
+	pages        map[string]bool // resource types with a page, to link to
+	ext          string
+
+	sections []docSection
+	anchors  map[string]string // by the title of each documented type
+	queued   []queuedSection
+}
+
+type queuedSection struct {
+	anchor, title string
+	validator     Validator
+}
+
+// DocPages documents each resource type with a schema in the target
+// version, for pages of a format. The types are resolved by the converter
+// used for validation, so the documentation describes what is validated.
+// Resource types whose schema cannot be loaded are left out, with the errors
+// saying why.
+func (v *PEGMCDocValidator) DocPages(format string) ([]*DocPage, []error) {
+	resourceTypes := v.ResourceTypes()
+	pages := make(map[string]bool)
+	for _, resourceType := range resourceTypes {
+		pages[resourceType] = true
+	}
+
+	var documented []*DocPage
+	var problems []error
+	for _, resourceType := range resourceTypes {
+		converter, mainValidator, schemaPath, err := v.loadResourceType(resourceType)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", resourceType, err))
+			continue
+		}
+		ctx := v.newContext(converter)
+		if !mainValidator.AppliesForVersion(ctx) {
+			continue
+		}
+		b := &docBuilder{ctx: ctx, resourceType: resourceType, pages: pages, ext: docsExt(format), anchors: make(map[string]string)}
+		b.link(resourceType, mainValidator)
+		for len(b.queued) > 0 {
+			next := b.queued[0]
+			b.queued = b.queued[1:]
+			b.sections = append(b.sections, b.section(next))
+		}
+		documented = append(documented, &DocPage{
+			Version:      v.targetVersion.String(),
+			ResourceType: resourceType,
+			Schema:       v.schemaName(schemaPath),
+			Sections:     b.sections,
+		})
+	}
+	return documented, problems
+}
+
+var anchorPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// link documents a type in a section of its own, if it is not yet, and
+// returns a link to it
+func (b *docBuilder) link(title string, validator Validator) docSpan {
+	anchor, ok := b.anchors[title]
+	if !ok {
+		anchor = strings.Trim(anchorPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+		for taken := true; taken; {
+			taken = false
+			for _, other := range b.anchors {
+				if other == anchor {
+					anchor += "-"
+					taken = true
+				}
+			}
+		}
+		b.anchors[title] = anchor
+		b.queued = append(b.queued, queuedSection{anchor, title, validator})
+	}
+	return docSpan{Text: title, Href: "#" + anchor}
+}
+
+// pageLink links to the page of another resource type
+func (b *docBuilder) pageLink(resourceType string) string {
+	return docsRoot(b.resourceType) + resourceType + b.ext
+}
+
+// docsRoot links from the page of a resource type to the directory of its
+// version
+func docsRoot(resourceType string) string {
+	return strings.Repeat("../", strings.Count(resourceType, "/"))
+}
+
+// section documents a queued type
+func (b *docBuilder) section(queued queuedSection) docSection {
+	s := docSection{Anchor: queued.anchor, Title: queued.title, Column: "Type"}
+	validator := queued.validator
+	for depth := 0; depth < 64 && validator != nil; depth++ {
+		if reference, ok := validator.(*ReferenceValidator); ok {
+			validator = b.ctx.Definitions[reference.TypeName]
+			continue
+		}
+		if attributed, ok := validator.(*AttributedValidator); ok && !hasOtherAttributes(attributed) {
+			s.Badges = append(s.Badges, docBadges(attributed.BaseValidator)...)
+			validator = attributed.InnerValidator
+			continue
+		}
+		break
+	}
+
+	switch t := validator.(type) {
+	case *StructValidator:
+		s.Kind, s.Source = "struct", t.Source.String()
+		s.Rows = b.fields(queued.title, t)
+	case *EnumValidator:
+		s.Kind, s.Column = "enum "+t.Type, "Value"
+		for _, value := range t.Values {
+			if value.AppliesForVersion(b.ctx) {
+				s.Rows = append(s.Rows, docRow{Name: value.Name, Type: []docSpan{{Text: fmt.Sprintf("%#v", value.Value)}}, Badges: docBadges(value.BaseValidator), Doc: value.Doc})
+			}
+		}
+	case *DispatchValidator:
+		s.Kind = "dispatch"
+		for _, c := range dispatchCases(b.ctx.Dispatches[t.Registry]) {
+			if !c.Validator.AppliesForVersion(b.ctx) {
+				continue
+			}
+			name := strings.Join(c.Keys, ", ")
+			s.Rows = append(s.Rows, docRow{Name: name, Type: b.spans(t.Registry+"["+name+"]", c.Validator)})
+		}
+	case nil:
+		s.Kind, s.Type = "type", []docSpan{{Text: "unresolved"}}
+	default:
+		s.Kind, s.Type = "type", b.spans(queued.title, validator)
+	}
+	return s
+}
+
+// fields documents the fields of a struct that exist in the version
+func (b *docBuilder) fields(owner string, sv *StructValidator) []docRow {
+	var rows []docRow
+	for _, field := range sv.Fields {
+		if !field.AppliesForVersion(b.ctx) || !field.Validator.AppliesForVersion(b.ctx) {
+			continue
+		}
+		badges := docBadges(field.BaseValidator)
+		if attributed, ok := field.Validator.(*AttributedValidator); ok {
+			badges = append(badges, docBadges(attributed.BaseValidator)...)
+		}
+		rows = append(rows, docRow{
+			Name:     field.Name,
+			Type:     b.spans(owner+"."+field.Name, field.Validator),
+			Optional: field.Optional,
+			Badges:   badges,
+			Doc:      field.Doc,
+			Source:   field.Source.String(),
+		})
+	}
+	for _, spread := range sv.SpreadFields {
+		if spread.AppliesForVersion(b.ctx) {
+			rows = append(rows, docRow{Name: "...", Type: b.spans(owner+"...", spread), Doc: "The fields of this type"})
+		}
+	}
+	for _, dynamic := range sv.DynamicFields {
+		if dynamic.AppliesForVersion(b.ctx) {
+			rows = append(rows, docRow{Name: "[" + DescribeType(dynamic.Key) + "]", Type: b.spans(owner+"[]", dynamic.Validator), Optional: dynamic.Optional, Badges: docBadges(dynamic.BaseValidator)})
+		}
+	}
+	return rows
+}
+
+// spans describes a type like DescribeType, linking the named types, the
+// anonymous structs and the dispatchers it uses to their sections, and the
+// resources it references to their pages. owner names anonymous types.
+func (b *docBuilder) spans(owner string, validator Validator) []docSpan {
+	switch t := validator.(type) {
+	case *ConstrainedValidator:
+		return append(b.spans(owner, t.InnerValidator), docSpan{Text: " @ " + DescribeType(t.Constraint)})
+	case *ArrayValidator:
+		element := b.spans(owner+"[]", t.ElementValidator)
+		var spans []docSpan
+		if t.Typed != "" {
+			spans = append(element, docSpan{Text: "[]"})
+		} else {
+			spans = append(append([]docSpan{{Text: "["}}, element...), docSpan{Text: "]"})
+		}
+		if t.LengthConstraint != nil {
+			spans = append(spans, docSpan{Text: " @ " + describeRange(t.LengthConstraint)})
+		}
+		return spans
+	case *StructValidator:
+		if t.Name != "" {
+			return []docSpan{b.link(t.Name, t)}
+		}
+		return []docSpan{b.link(owner, t)}
+	case *EnumValidator:
+		if t.Name != "" {
+			return []docSpan{b.link(t.Name, t)}
+		}
+		return []docSpan{b.link(owner, t)}
+	case *ReferenceValidator:
+		if b.ctx.Definitions[t.TypeName] == nil {
+			return []docSpan{{Text: t.TypeName}}
+		}
+		return []docSpan{b.link(t.TypeName, t)}
+	case *UnionValidator:
+		alternatives := t.applicable(b.ctx)
+		if len(alternatives) == 1 {
+			return b.spans(owner, alternatives[0])
+		}
+		spans := []docSpan{{Text: "("}}
+		for i, alternative := range alternatives {
+			if i > 0 {
+				spans = append(spans, docSpan{Text: " | "})
+			}
+			spans = append(spans, b.spans(fmt.Sprintf("%s (%d)", owner, i+1), alternative)...)
+		}
+		return append(spans, docSpan{Text: ")"})
+	case *AttributedValidator:
+		var spans []docSpan
+		if text := docAttributes(t); text != "" {
+			span := docSpan{Text: text}
+			if registry := docRegistry(t); b.pages[registry] {
+				span.Href = b.pageLink(registry)
+			}
+			spans = append(spans, span)
+		}
+		return append(spans, b.spans(owner, t.InnerValidator)...)
+	case *DispatchValidator:
+		if t.Registry == "minecraft:resource" && !t.Dynamic && b.pages[t.Key] {
+			return []docSpan{{Text: DescribeType(t), Href: b.pageLink(t.Key)}}
+		}
+		if len(b.ctx.Dispatches[t.Registry]) == 0 {
+			return []docSpan{{Text: DescribeType(t)}}
+		}
+		span := b.link(t.Registry, t)
+		span.Text = DescribeType(t)
+		return []docSpan{span}
+	}
+	return []docSpan{{Text: describeTypeIn(validator, b.ctx)}}
+}
+
+// hasOtherAttributes reports whether an attributed type has attributes
+// other than its version and feature gates
+func hasOtherAttributes(av *AttributedValidator) bool {
+	for name := range av.Attributes {
+		if name != "since" && name != "until" && name != "feature" {
+			return true
+		}
+	}
+	return false
+}
+
+// docAttributes writes the attributes of a type other than its gates as the
+// schema does, like #[id(registry="item", exclude=["air"])]
+func docAttributes(av *AttributedValidator) string {
+	var names []string
+	for name := range av.Attributes {
+		if name != "since" && name != "until" && name != "feature" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	text := ""
+	for _, name := range names {
+		switch value := av.Attributes[name]; {
+		case value == "":
+			text += "#[" + name + "] "
+		case strings.HasPrefix(value, "("):
+			text += "#[" + name + value + "] "
+		default:
+			text += "#[" + name + "=" + strconv.Quote(value) + "] "
+		}
+	}
+	return text
+}
+
+// docRegistry returns the registry an #[id] attribute references, like
+// worldgen/biome for #[id="worldgen/biome"]
+func docRegistry(av *AttributedValidator) string {
+	args, ok := av.Args["id"]
+	if !ok {
+		return ""
+	}
+	if registry := args.Get("registry"); registry != "" {
+		return strings.TrimPrefix(registry, "minecraft:")
+	}
+	return strings.TrimPrefix(args.Value(), "minecraft:")
+}
+
+// docBadges describes the gates of a declaration, like since 1.20.5
+func docBadges(bounds BaseValidator) []string {
+	var badges []string
+	if bounds.Since != "" {
+		badges = append(badges, "since "+bounds.Since)
+	}
+	if bounds.Until != "" {
+		badges = append(badges, "until "+bounds.Until)
+	}
+	if bounds.Feature != "" {
+		badges = append(badges, "feature "+bounds.Feature)
+	}
+	return badges
+}
+
+// docsExt is the file extension of the pages of a format
+func docsExt(format string) string {
+	if format == "markdown" {
+		return ".md"
+	}
+	return ".html"
+}
+
+// docsIndex lists the resource types documented for a version
+type docsIndex struct {
+	Version string
+	Pages   []*DocPage
+}
+
+// WriteDocs writes the pages of a version below dir/version, with an index
+// linking to them
+func WriteDocs(dir, format, version string, pages []*DocPage) error {
+	versionDir := filepath.Join(dir, version)
+	ext := docsExt(format)
+	for _, page := range pages {
+		file := filepath.Join(versionDir, filepath.FromSlash(page.ResourceType)+ext)
+		if err := writeDocsFile(file, format, "page", page); err != nil {
+			return err
+		}
+	}
+	return writeDocsFile(filepath.Join(versionDir, "index"+ext), format, "index", docsIndex{version, pages})
+}
+
+// WriteDocsVersions writes the index of the documented versions to dir
+func WriteDocsVersions(dir, format string, versions []string) error {
+	return writeDocsFile(filepath.Join(dir, "index"+docsExt(format)), format, "versions", versions)
+}
+
+// writeDocsFile executes a template of a format into a file
+func writeDocsFile(file, format, name string, data interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	err = executeDocsTemplate(f, format, name, data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func executeDocsTemplate(w io.Writer, format, name string, data interface{}) error {
+	if format == "markdown" {
+		return markdownDocs.ExecuteTemplate(w, name, data)
+	}
+	return htmlDocs.ExecuteTemplate(w, name, data)
+}
+
+// docsFuncs are the functions of the templates of both formats
+var docsFuncs = map[string]interface{}{
+	"root": docsRoot,
+	// cell escapes text for a cell of a markdown table
+	"cell": func(text string) string {
+		return strings.NewReplacer("|", `\|`, "\n", "<br>").Replace(text)
+	},
+}
+
+var markdownDocs = template.Must(template.New("markdown").Funcs(docsFuncs).Parse(`
+{{- define "versions"}}# Schemas
+{{range .}}
+- [{{.}}]({{.}}/index.md)
+{{- end}}
+{{end}}
+
+{{- define "index"}}# Resource types in {{.Version}}
+{{range .Pages}}
+- [{{.ResourceType}}]({{.ResourceType}}.md)
+{{- end}}
+{{end}}
+
+{{- define "spans"}}{{range .}}{{if .Href}}[{{cell .Text}}]({{.Href}}){{else}}{{cell .Text}}{{end}}{{end}}{{end}}
+
+{{- define "page"}}# {{.ResourceType}}
+
+[{{.Version}}]({{root .ResourceType}}index.md) · declared in ` + "`{{.Schema}}`" + `
+{{range .Sections}}
+## <a id="{{.Anchor}}"></a>{{.Title}}
+
+{{.Kind}}{{range .Badges}} ` + "`{{.}}`" + `{{end}}{{with .Source}} · {{.}}{{end}}
+{{with .Type}}
+{{template "spans" .}}
+{{end}}
+{{- if .Rows}}
+| Name | {{.Column}} | Description |
+| --- | --- | --- |
+{{range .Rows}}| {{cell .Name}}{{if .Optional}}?{{end}} | {{template "spans" .Type}}{{range .Badges}} ` + "`{{.}}`" + `{{end}} | {{cell .Doc}} |
+{{end}}{{end}}{{end}}{{end}}
+`))
+
+var htmlDocs = htmltemplate.Must(htmltemplate.New("html").Funcs(docsFuncs).Parse(`
+{{- define "head"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.5em; text-align: left; vertical-align: top; }
+code { font-size: 0.95em; }
+.badge { font-size: 0.8em; background: #e8eef8; border-radius: 0.3em; padding: 0 0.4em; margin-left: 0.3em; white-space: nowrap; }
+.optional { color: #777; }
+.meta { color: #555; }
+</style>
+</head>
+<body>
+{{end}}
+
+{{- define "versions"}}{{template "head" "Schemas"}}<h1>Schemas</h1>
+<ul>
+{{- range .}}
+<li><a href="{{.}}/index.html">{{.}}</a></li>
+{{- end}}
+</ul>
+</body>
+</html>
+{{end}}
+
+{{- define "index"}}{{template "head" (print "Resource types in " .Version)}}<h1>Resource types in {{.Version}}</h1>
+<ul>
+{{- range .Pages}}
+<li><a href="{{.ResourceType}}.html">{{.ResourceType}}</a></li>
+{{- end}}
+</ul>
+</body>
+</html>
+{{end}}
+
+{{- define "spans"}}<code>{{range .}}{{if .Href}}<a href="{{.Href}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}{{end}}</code>{{end}}
+
+{{- define "badges"}}{{range .}}<span class="badge">{{.}}</span>{{end}}{{end}}
+
+{{- define "page"}}{{template "head" (print .ResourceType " in " .Version)}}<h1>{{.ResourceType}}</h1>
+<p class="meta"><a href="{{root .ResourceType}}index.html">{{.Version}}</a> · declared in <code>{{.Schema}}</code></p>
+{{- range .Sections}}
+<h2 id="{{.Anchor}}">{{.Title}}</h2>
+<p class="meta">{{.Kind}}{{template "badges" .Badges}}{{with .Source}} · {{.}}{{end}}</p>
+{{- with .Type}}
+<p>{{template "spans" .}}</p>
+{{- end}}
+{{- if .Rows}}
+<table>
+<tr><th>Name</th><th>{{.Column}}</th><th>Description</th></tr>
+{{- range .Rows}}
+<tr><td><code>{{.Name}}</code>{{if .Optional}} <span class="optional">optional</span>{{end}}</td><td>{{template "spans" .Type}}{{template "badges" .Badges}}</td><td>{{.Doc}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+</body>
+</html>
+{{end}}
+`))
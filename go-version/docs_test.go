@@ -0,0 +1,99 @@
+package mcheck
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDocPages(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t, "trim", "worldgen/biome")
+	page := func(version, resourceType string) *DocPage {
+		t.Helper()
+		targetVersion, _ := ParseVersion(version)
+		pages, problems := NewPEGMCDocValidator(targetVersion, schemaDir).DocPages("html")
+		if len(problems) > 0 {
+			t.Fatalf("Expected every schema to load, got %v", problems)
+		}
+		for _, page := range pages {
+			if page.ResourceType == resourceType {
+				return page
+			}
+		}
+		t.Fatalf("Expected a page for %s in %s", resourceType, version)
+		return nil
+	}
+	row := func(section docSection, name string) (docRow, bool) {
+		for _, row := range section.Rows {
+			if row.Name == name {
+				return row, true
+			}
+		}
+		return docRow{}, false
+	}
+
+	// Fields are documented for the version they exist in
+	old := page("1.20.1", "trim_pattern").Sections[0]
+	if _, ok := row(old, "decal"); ok {
+		t.Error("Expected decal to be left out before 1.20.2")
+	}
+	newer := page("1.21.4", "trim_pattern").Sections[0]
+	decal, ok := row(newer, "decal")
+	if !ok || !decal.Optional || strings.Join(decal.Badges, ",") != "since 1.20.2" {
+		t.Errorf("Expected decal to be optional since 1.20.2, got %+v", decal)
+	}
+	if asset, _ := row(newer, "asset_id"); len(asset.Type) == 0 || !strings.HasPrefix(asset.Type[0].Text, `#[id(registry="texture"`) {
+		t.Errorf("Expected the attributes of asset_id as the schema writes them, got %+v", asset.Type)
+	}
+
+	// Named types get sections of their own, linked from where they are used
+	biome := page("1.21.4", "worldgen/biome")
+	modifier, _ := row(biome.Sections[0], "temperature_modifier")
+	if len(modifier.Type) != 1 || modifier.Type[0].Href != "#temperaturemodifier" {
+		t.Fatalf("Expected a link to the TemperatureModifier section, got %+v", modifier.Type)
+	}
+	found := false
+	for _, section := range biome.Sections {
+		if section.Anchor == "temperaturemodifier" {
+			found = true
+			if frozen, ok := row(section, "Frozen"); !strings.HasPrefix(section.Kind, "enum") || !ok || frozen.Type[0].Text != `"frozen"` {
+				t.Errorf("Expected the values of the enum, got %+v", section)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected a section for TemperatureModifier")
+	}
+
+	b := &docBuilder{resourceType: "worldgen/biome", ext: ".html"}
+	if link := b.pageLink("trim_pattern"); link != "../trim_pattern.html" {
+		t.Errorf("Expected a link relative to the page, got %s", link)
+	}
+}
+
+func TestWriteDocs(t *testing.T) {
+	targetVersion, _ := ParseVersion("1.21.4")
+	pages, _ := NewPEGMCDocValidator(targetVersion, fixtureSchemaDir(t, "worldgen/biome")).DocPages("markdown")
+	dir := t.TempDir()
+	if err := WriteDocs(dir, "markdown", "1.21.4", pages); err != nil {
+		t.Fatalf("writeDocs failed: %v", err)
+	}
+	if err := WriteDocsVersions(dir, "markdown", []string{"1.21.4"}); err != nil {
+		t.Fatalf("writeDocsVersions failed: %v", err)
+	}
+
+	for file, expected := range map[string]string{
+		"index.md":                 "- [1.21.4](1.21.4/index.md)",
+		"1.21.4/index.md":          "- [worldgen/biome](worldgen/biome.md)",
+		"1.21.4/worldgen/biome.md": "| temperature_modifier? | [TemperatureModifier](#temperaturemodifier) |",
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected %s to contain %q, got:\n%s", file, expected, content)
+		}
+	}
+}
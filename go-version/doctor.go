@@ -0,0 +1,227 @@
+package mcheck
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DoctorCheck is the outcome of one check made by mcheck doctor
+type DoctorCheck struct {
+	Name   string
+	OK     bool
+	Detail string   // what was found
+	Notes  []string // the files or errors behind a failure
+	Fix    string   // what to do about a failure
+}
+
+func (c DoctorCheck) String() string {
+	status := "ok  "
+	if !c.OK {
+		status = "FAIL"
+	}
+	result := fmt.Sprintf("%s  %s: %s\n", status, c.Name, c.Detail)
+	for _, note := range c.Notes {
+		result += "      " + note + "\n"
+	}
+	if !c.OK && c.Fix != "" {
+		result += "      fix: " + c.Fix + "\n"
+	}
+	return result
+}
+
+// maxDoctorNotes bounds the failures listed for a single check
+const maxDoctorNotes = 5
+
+// cloneFix is the remedy for a missing or unusable schema directory
+const cloneFix = "clone the schemas with git clone https://github.com/SpyglassMC/vanilla-mcdoc and pass --schema-dir vanilla-mcdoc, or run mcheck where that directory is"
+
+// Doctor checks that the schemas are where mcheck looks for them and can be
+// used, parsing sample schema files spread over the edition's schemas, or
+// all of them if sample is 0, and that the target version is known
+func Doctor(schemaDir, edition, version string, sample int) []DoctorCheck {
+	return append(schemaChecks(schemaDir, edition, version, sample), versionCheck(version))
+}
+
+// schemaChecks checks the schema directory. Checks that depend on an earlier
+// one that failed are skipped.
+func schemaChecks(schemaDir, edition, version string, sample int) []DoctorCheck {
+	var checks []DoctorCheck
+
+	dir, schemas, err := findSchemaDir(schemaDir)
+	if err != nil {
+		return append(checks, DoctorCheck{Name: "schema directory", Detail: err.Error(), Fix: cloneFix})
+	}
+	if info, err := schemas.Stat(dir); err != nil || !info.IsDir() {
+		detail := fmt.Sprintf("%s is not a directory", dir)
+		if err != nil {
+			detail = err.Error()
+		}
+		return append(checks, DoctorCheck{Name: "schema directory", Detail: detail, Fix: cloneFix})
+	}
+	if _, err := schemas.ReadDir(dir); err != nil {
+		return append(checks, DoctorCheck{Name: "schema directory", Detail: err.Error(), Fix: "make " + dir + " readable by the user running mcheck"})
+	}
+	checks = append(checks, DoctorCheck{Name: "schema directory", OK: true, Detail: dir})
+
+	editionDir := filepath.Join(dir, edition)
+	if _, err := schemas.Stat(editionDir); err != nil {
+		check := DoctorCheck{Name: edition + " schemas", Detail: editionDir + " does not exist"}
+		switch {
+		case filepath.Base(filepath.Clean(dir)) == edition:
+			// --schema-dir vanilla-mcdoc/java instead of vanilla-mcdoc
+			check.Fix = "pass the directory holding " + edition + "/ instead: --schema-dir " + filepath.Dir(filepath.Clean(dir))
+		case len(editionDirs(schemas, dir)) > 0:
+			check.Fix = "the directory has schemas for " + strings.Join(editionDirs(schemas, dir), ", ") + "; select one with --edition"
+		default:
+			check.Fix = cloneFix
+		}
+		return append(checks, check)
+	}
+
+	index, err := buildSchemaIndex(schemas, dir)
+	if err != nil {
+		return append(checks, DoctorCheck{Name: edition + " schemas", Detail: err.Error(), Fix: "make every file below " + dir + " readable by the user running mcheck"})
+	}
+	prefix := editionDir + string(filepath.Separator)
+	var files []string
+	for _, file := range index.Modules {
+		if strings.HasPrefix(file, prefix) {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	if len(files) == 0 {
+		return append(checks, DoctorCheck{Name: edition + " schemas", Detail: "no .mcdoc files in " + editionDir, Fix: cloneFix})
+	}
+	checks = append(checks, DoctorCheck{Name: edition + " schemas", OK: true, Detail: fmt.Sprintf("%d files", len(files))})
+
+	checks = append(checks, registryCheck(index, prefix), schemaVersionCheck(index, dir, edition, version))
+
+	validator := NewPEGMCDocValidator(Version{}, dir)
+	validator.schemas = schemas
+	var failures []string
+	failed := 0
+	sampled := sampleFiles(files, sample)
+	for _, file := range sampled {
+		// Statements that do not parse are skipped, but their types go unchecked
+		var syntaxErr *mcdocSyntaxError
+		if _, err := validator.parsedSchema(file); errors.As(err, &syntaxErr) {
+			failures = append(failures, syntaxErr.Error())
+			failed++
+		} else if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", file, err))
+			failed++
+		} else if skipped := validator.skippedStatements(file); len(skipped) > 0 {
+			for _, statement := range skipped {
+				failures = append(failures, fmt.Sprintf("%v, skipped the statement", statement.err))
+			}
+			failed++
+		}
+	}
+	parsing := DoctorCheck{Name: "schema parsing", OK: len(failures) == 0, Detail: fmt.Sprintf("%d of %d files parsed", len(sampled)-failed, len(sampled))}
+	if len(failures) > 0 {
+		parsing.Notes = limitNotes(failures)
+		parsing.Fix = "update the schemas, with git -C " + dir + " pull for a clone; if they are current, report the files with the output of mcheck version"
+	}
+	return append(checks, parsing)
+}
+
+// registryCheck checks that the schemas register resource types under
+// minecraft:resource, which is how the schema of a pack file is found
+func registryCheck(index *SchemaIndex, prefix string) DoctorCheck {
+	resourceTypes := 0
+	for _, file := range index.Dispatches["minecraft:resource"] {
+		if strings.HasPrefix(file, prefix) {
+			resourceTypes++
+		}
+	}
+	if resourceTypes == 0 {
+		return DoctorCheck{
+			Name:   "registries",
+			Detail: "no resource types are dispatched from minecraft:resource",
+			Fix:    "the directory does not look like vanilla-mcdoc; " + cloneFix,
+		}
+	}
+	return DoctorCheck{
+		Name:   "registries",
+		OK:     true,
+		Detail: fmt.Sprintf("%d resource types, %d dispatch registries", resourceTypes, len(index.Dispatches)),
+	}
+}
+
+// schemaVersionCheck checks that the schemas know of the target version. A
+// target that is not a known version is left to versionCheck.
+func schemaVersionCheck(index *SchemaIndex, dir, edition, version string) DoctorCheck {
+	check := DoctorCheck{Name: "schema versions", OK: true}
+	newest, ok := index.Version(edition)
+	if !ok {
+		check.Detail = "the schemas name no versions"
+		return check
+	}
+	check.Detail = "up to " + newest.String()
+	if target, err := ParseVersion(version); err == nil && slices.Contains(KnownVersions, version) {
+		if _, predate := index.Predates(edition, target); predate {
+			check.OK = false
+			check.Detail = fmt.Sprintf("schemas predate %s; results may be incomplete, they were written for %s", version, newest)
+			check.Fix = "update the schemas, with git -C " + dir + " pull for a clone, or target an older version with --version"
+		}
+	}
+	return check
+}
+
+// versionCheck checks that the target version parses and is one mcheck knows
+func versionCheck(version string) DoctorCheck {
+	check := DoctorCheck{Name: "target version"}
+	target, err := ParseVersion(version)
+	if err != nil {
+		check.Detail = err.Error()
+		check.Fix = "pass a release like --version " + KnownVersions[len(KnownVersions)-1]
+		return check
+	}
+	oldest, _ := ParseVersion(KnownVersions[0])
+	newest, _ := ParseVersion(KnownVersions[len(KnownVersions)-1])
+	if target.Compare(oldest) < 0 || target.Compare(newest) > 0 {
+		check.Detail = fmt.Sprintf("%s is outside the known versions %s to %s", version, KnownVersions[0], KnownVersions[len(KnownVersions)-1])
+		check.Fix = "pass a known version with --version, or update mcheck"
+		return check
+	}
+	check.OK = true
+	check.Detail = version
+	return check
+}
+
+// editionDirs lists the editions with a directory in dir
+func editionDirs(schemas fileSystem, dir string) []string {
+	var found []string
+	for _, edition := range Editions {
+		if _, err := schemas.Stat(filepath.Join(dir, edition)); err == nil {
+			found = append(found, edition)
+		}
+	}
+	return found
+}
+
+// sampleFiles picks n files spread evenly over files, or all of them if n
+// is 0 or covers them all
+func sampleFiles(files []string, n int) []string {
+	if n <= 0 || n >= len(files) {
+		return files
+	}
+	sample := make([]string, n)
+	for i := range sample {
+		sample[i] = files[i*len(files)/n]
+	}
+	return sample
+}
+
+// limitNotes keeps the first few notes, counting the rest
+func limitNotes(notes []string) []string {
+	if len(notes) <= maxDoctorNotes {
+		return notes
+	}
+	return append(notes[:maxDoctorNotes:maxDoctorNotes], fmt.Sprintf("and %d more", len(notes)-maxDoctorNotes))
+}
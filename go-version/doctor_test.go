@@ -0,0 +1,100 @@
+package mcheck
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// failedChecks returns the names of the checks that failed
+func failedChecks(checks []DoctorCheck) []string {
+	var failed []string
+	for _, check := range checks {
+		if !check.OK {
+			failed = append(failed, check.Name)
+		}
+	}
+	return failed
+}
+
+func TestDoctor(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t, "trim", "worldgen/biome")
+	checks := Doctor(schemaDir, "java", "1.20.1", 0)
+	if failed := failedChecks(checks); len(failed) != 0 {
+		t.Errorf("Expected every check to pass, got failures %v:\n%v", failed, checks)
+	}
+	if len(checks) != 6 {
+		t.Errorf("Expected 6 checks, got %v", checks)
+	}
+
+	tests := []struct {
+		name      string
+		schemaDir string
+		edition   string
+		version   string
+		failed    string
+		fix       string
+	}{
+		{"missing directory", filepath.Join(t.TempDir(), "missing"), "java", "1.20.1", "schema directory", "git clone"},
+		{"edition directory", filepath.Join(schemaDir, "java"), "java", "1.20.1", "java schemas", "--schema-dir " + schemaDir},
+		{"other edition", schemaDir, "bedrock", "1.20.1", "bedrock schemas", "schemas for java"},
+		{"unknown version", schemaDir, "java", "1.99", "target version", "--version"},
+		{"invalid version", schemaDir, "java", "latest", "target version", "--version"},
+		{"outdated schemas", schemaDir, "java", "1.21.6", "schema versions", "update the schemas"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checks := Doctor(test.schemaDir, test.edition, test.version, 0)
+			failed := failedChecks(checks)
+			if len(failed) != 1 || failed[0] != test.failed {
+				t.Fatalf("Expected %s to fail, got %v", test.failed, checks)
+			}
+			for _, check := range checks {
+				if !check.OK && !strings.Contains(check.Fix, test.fix) {
+					t.Errorf("Expected the fix for %s to mention %q, got %q", check.Name, test.fix, check.Fix)
+				}
+			}
+		})
+	}
+}
+
+func TestDoctorBrokenSchemas(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t, "trim")
+	broken := filepath.Join(schemaDir, "java", "data", "broken.mcdoc")
+	if err := os.WriteFile(broken, []byte("struct Broken {\n\tname: \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := Doctor(schemaDir, "java", "1.20.1", 0)
+	if failed := failedChecks(checks); len(failed) != 1 || failed[0] != "schema parsing" {
+		t.Fatalf("Expected schema parsing to fail, got %v", checks)
+	}
+	for _, check := range checks {
+		if check.Name == "schema parsing" && (len(check.Notes) != 1 || !strings.HasPrefix(check.Notes[0], broken+":3:1: expected type after ':'")) {
+			t.Errorf("Expected the broken file to be listed with where it stops parsing, got %v", check.Notes)
+		}
+	}
+
+	// Without dispatches there is no way to find the schema of a file
+	empty := filepath.Join(t.TempDir(), "vanilla-mcdoc")
+	if err := os.MkdirAll(filepath.Join(empty, "java"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(empty, "java", "util.mcdoc"), []byte("struct Util {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if failed := failedChecks(Doctor(empty, "java", "1.20.1", 0)); len(failed) != 1 || failed[0] != "registries" {
+		t.Errorf("Expected registries to fail, got %v", failed)
+	}
+}
+
+func TestSampleFiles(t *testing.T) {
+	files := []string{"a", "b", "c", "d", "e", "f"}
+	if sample := sampleFiles(files, 3); strings.Join(sample, "") != "ace" {
+		t.Errorf("Expected an even sample, got %v", sample)
+	}
+	if sample := sampleFiles(files, 0); len(sample) != len(files) {
+		t.Errorf("Expected all files, got %v", sample)
+	}
+}
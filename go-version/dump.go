@@ -0,0 +1,184 @@
+package mcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DumpValidator writes the tree of a validator in a canonical text form, one
+// node per line with its children indented below it, so that tests can
+// compare what a schema converts to with a golden file. Doc comments and
+// source positions are left out, so that editing the comments or layout of
+// a schema does not change its dump. A struct met again inside itself is
+// marked recursive rather than written again, which ends recursive types.
+func DumpValidator(v Validator) string {
+	d := &validatorDumper{seen: make(map[*StructValidator]bool)}
+	d.dump(v, 0)
+	return d.buf.String()
+}
+
+// Dump writes the types and dispatcher cases of the converted statements
+// like DumpValidator: types sorted by name, then the cases of each
+// dispatcher sorted by key, the keys sharing a case listed together
+func (sc *SchemaConverter) Dump() string {
+	d := &validatorDumper{}
+
+	names := make([]string, 0, len(sc.definitions))
+	for name := range sc.definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d.line(0, "type %s", name)
+		d.seen = make(map[*StructValidator]bool)
+		d.dump(sc.definitions[name], 1)
+	}
+
+	registries := make([]string, 0, len(sc.dispatches))
+	for registry := range sc.dispatches {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+	for _, registry := range registries {
+		for _, c := range dispatchCases(sc.dispatches[registry]) {
+			d.line(0, "dispatch %s[%s]", registry, strings.Join(c.Keys, ", "))
+			d.seen = make(map[*StructValidator]bool)
+			d.dump(c.Validator, 1)
+		}
+	}
+	return d.buf.String()
+}
+
+type validatorDumper struct {
+	buf  strings.Builder
+	seen map[*StructValidator]bool // structs being dumped, to end recursion
+}
+
+func (d *validatorDumper) line(depth int, format string, args ...interface{}) {
+	d.buf.WriteString(strings.Repeat("  ", depth))
+	fmt.Fprintf(&d.buf, format, args...)
+	d.buf.WriteByte('\n')
+}
+
+// bounded appends the version bounds of a node to its text
+func bounded(text string, bounds BaseValidator) string {
+	if described := describeBounds(bounds); described != "" {
+		return text + " (" + described + ")"
+	}
+	return text
+}
+
+func (d *validatorDumper) dump(v Validator, depth int) {
+	switch t := v.(type) {
+	case nil:
+		d.line(depth, "nil")
+	case *PrimitiveValidator:
+		text := t.Type
+		if t.Unresolved != "" {
+			text += " unresolved " + strconv.Quote(t.Unresolved)
+		}
+		d.line(depth, "%s", bounded(text, t.BaseValidator))
+	case *RangeValidator:
+		d.line(depth, "%s", bounded("range "+describeRange(t), t.BaseValidator))
+	case *ConstrainedValidator:
+		d.line(depth, "%s", bounded("constrained", t.BaseValidator))
+		d.dump(t.InnerValidator, depth+1)
+		d.dump(t.Constraint, depth+1)
+	case *ArrayValidator:
+		text := "array"
+		if t.Typed != "" {
+			text = t.Typed + " array"
+		}
+		if t.LengthConstraint != nil {
+			text += " length " + describeRange(t.LengthConstraint)
+		}
+		d.line(depth, "%s", bounded(text, t.BaseValidator))
+		d.dump(t.ElementValidator, depth+1)
+	case *StructValidator:
+		d.dumpStruct(t, depth)
+	case *BasicStructValidator:
+		d.line(depth, "%s", bounded("struct with any fields", t.BaseValidator))
+	case *UnionValidator:
+		d.line(depth, "%s", bounded("union", t.BaseValidator))
+		for _, alt := range t.Alternatives {
+			d.dump(alt, depth+1)
+		}
+	case *LiteralValidator:
+		value, err := json.Marshal(t.Value)
+		if err != nil {
+			value = []byte(fmt.Sprintf("%v", t.Value))
+		}
+		d.line(depth, "%s", bounded("literal "+string(value), t.BaseValidator))
+	case *ReferenceValidator:
+		d.line(depth, "%s", bounded("ref "+t.TypeName, t.BaseValidator))
+	case *AttributedValidator:
+		// Version bounds are written like those of every other node
+		attributes := make(map[string]string, len(t.Attributes))
+		for name, value := range t.Attributes {
+			if name != "since" && name != "until" && name != "feature" {
+				attributes[name] = value
+			}
+		}
+		d.line(depth, "%s", bounded(strings.TrimSpace("attributed "+describeAttributes(attributes)), t.BaseValidator))
+		d.dump(t.InnerValidator, depth+1)
+	case *EnumValidator:
+		d.line(depth, "%s", bounded(fmt.Sprintf("enum(%s) %s", t.Type, t.Name), t.BaseValidator))
+		for _, value := range t.Values {
+			encoded, err := json.Marshal(value.Value)
+			if err != nil {
+				encoded = []byte(fmt.Sprintf("%v", value.Value))
+			}
+			d.line(depth+1, "%s", bounded(fmt.Sprintf("value %s = %s", value.Name, encoded), value.BaseValidator))
+		}
+	case *DispatchValidator:
+		d.line(depth, "%s", bounded("dispatch "+DescribeType(t), t.BaseValidator))
+	default:
+		d.line(depth, "%T", v)
+	}
+}
+
+// dumpStruct writes the fields, spreads and computed fields of a struct in
+// the order the schema declares them
+func (d *validatorDumper) dumpStruct(sv *StructValidator, depth int) {
+	text := "struct"
+	if sv.Name != "" {
+		text += " " + sv.Name
+	}
+	if d.seen[sv] {
+		d.line(depth, "%s (recursive)", text)
+		return
+	}
+	d.seen[sv] = true
+	defer delete(d.seen, sv)
+
+	d.line(depth, "%s", bounded(text, sv.BaseValidator))
+	spread := 0
+	spreadsUpTo := func(fields int) {
+		for ; spread < len(sv.SpreadFields) && spreadPosition(sv, spread) <= fields; spread++ {
+			d.line(depth+1, "spread")
+			d.dump(sv.SpreadFields[spread], depth+2)
+		}
+	}
+	for i, field := range sv.Fields {
+		spreadsUpTo(i)
+		name := field.Name
+		if field.Optional {
+			name += "?"
+		}
+		d.line(depth+1, "%s", bounded("field "+name, field.BaseValidator))
+		d.dump(field.Validator, depth+2)
+	}
+	spreadsUpTo(len(sv.Fields))
+	for _, field := range sv.DynamicFields {
+		text := "computed"
+		if field.Optional {
+			text += "?"
+		}
+		d.line(depth+1, "%s", bounded(text, field.BaseValidator))
+		d.dump(field.Key, depth+2)
+		d.dump(field.Validator, depth+2)
+	}
+}
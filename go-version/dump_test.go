@@ -0,0 +1,123 @@
+package mcheck
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden validator dumps in tests/golden")
+
+// goldenSchemas are the schemas in tests/mcdocs whose converted validators
+// are compared with the dumps in tests/golden
+var goldenSchemas = []string{"data_component", "jukebox_song", "loot_table", "template_pool", "trim"}
+
+func TestConverterGolden(t *testing.T) {
+	for _, name := range goldenSchemas {
+		t.Run(name, func(t *testing.T) {
+			source, err := os.ReadFile(filepath.Join("tests", "mcdocs", name+".mcdoc"))
+			if err != nil {
+				t.Fatalf("Failed to read schema: %v", err)
+			}
+			converter, _ := convertSchema(t, "1.21", string(source))
+			got := converter.Dump()
+
+			golden := filepath.Join("tests", "golden", name+".txt")
+			if *updateGolden {
+				if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+					t.Fatalf("Failed to create directory: %v", err)
+				}
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatalf("Failed to write %s: %v", golden, err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read %s, run go test -run TestConverterGolden -update to create it: %v", golden, err)
+			}
+			if diff := dumpDiff(string(expected), got); diff != "" {
+				t.Errorf("%s differs from the converted schema, run with -update if the change is intended:\n%s", golden, diff)
+			}
+		})
+	}
+}
+
+// dumpDiff describes the first line where two dumps differ with the lines
+// around it, or returns "" if they are the same
+func dumpDiff(expected, got string) string {
+	expectedLines := strings.Split(expected, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(expectedLines) || i < len(gotLines); i++ {
+		line := func(lines []string) string {
+			if i < len(lines) {
+				return lines[i]
+			}
+			return "<end>"
+		}
+		if line(expectedLines) == line(gotLines) {
+			continue
+		}
+		var b strings.Builder
+		for j := i - 3; j < i; j++ {
+			if j >= 0 {
+				fmt.Fprintf(&b, "  %4d   %s\n", j+1, expectedLines[j])
+			}
+		}
+		fmt.Fprintf(&b, "- %4d   %s\n", i+1, line(expectedLines))
+		fmt.Fprintf(&b, "+ %4d   %s\n", i+1, line(gotLines))
+		return b.String()
+	}
+	return ""
+}
+
+func TestDumpValidator(t *testing.T) {
+	_, ctx := convertSchema(t, "1.21", `struct Node {
+	#[since="1.20"]
+	name?: #[id="item"] string @ 1..,
+	...Base,
+	children: [Node] @ ..4,
+	[string]: (int @ 0.. | "none"),
+	parent?: struct Parent { node: Node },
+}
+
+struct Base {
+	kind: minecraft:kind[[name]],
+}`)
+
+	expected := `struct Node
+  field name? (since 1.20)
+    attributed #[id="item"]
+      constrained
+        string
+        range 1..
+  spread
+    ref Base
+  field children
+    array length ..4
+      ref Node
+  field parent?
+    struct Parent
+      field node
+        ref Node
+  computed
+    string
+    union
+      constrained
+        int
+        range 0..
+      literal "none"
+`
+	if got := DumpValidator(ctx.Definitions["Node"]); got != expected {
+		t.Errorf("Unexpected dump:\n%s", dumpDiff(expected, got))
+	}
+
+	recursive := &StructValidator{Name: "Loop"}
+	recursive.Fields = []StructField{{Name: "self", Validator: recursive}}
+	if got := DumpValidator(recursive); got != "struct Loop\n  field self\n    struct Loop (recursive)\n" {
+		t.Errorf("Expected the recursive struct to be marked, got:\n%s", got)
+	}
+}
@@ -0,0 +1,32 @@
+package mcheck
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// embeddedSchemas holds the vanilla-mcdoc schemas release binaries are
+// built with, or nil. It is set by schemas_embed.go, which is only built
+// with -tags embedschemas after make embed-schemas has unpacked a tarball
+// into schemas/.
+var embeddedSchemas fs.FS
+
+// embeddedSchemaDir returns the schema directory at the root of files, like
+// SpyglassMC-vanilla-mcdoc-1a2b3c4, and the files to read it from. The
+// schemas are read from files as they are, and the directory keeps its name
+// so readSchemaBundle can tell their revision.
+func embeddedSchemaDir(files fs.FS) (string, fileSystem, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return "", fileSystem{}, err
+	}
+	if len(entries) != 1 || !entries[0].IsDir() {
+		return "", fileSystem{}, fmt.Errorf("embedded schemas should be a single directory, got %d entries", len(entries))
+	}
+	name := entries[0].Name()
+	sub, err := fs.Sub(files, name)
+	if err != nil {
+		return "", fileSystem{}, err
+	}
+	return name, newFileSystem(name, sub), nil
+}
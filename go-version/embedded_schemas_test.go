@@ -0,0 +1,50 @@
+package mcheck
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedSchemas(t *testing.T) {
+	schema, err := os.ReadFile(filepath.Join("tests", "mcdocs", "damage_type.mcdoc"))
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	files := fstest.MapFS{
+		"SpyglassMC-vanilla-mcdoc-1a2b3c4/java/data/damage_type.mcdoc": {Data: schema},
+	}
+	dir, schemas, err := embeddedSchemaDir(files)
+	if err != nil {
+		t.Fatalf("embeddedSchemaDir failed: %v", err)
+	}
+	if dir != "SpyglassMC-vanilla-mcdoc-1a2b3c4" {
+		t.Errorf("Unexpected schema directory %s", dir)
+	}
+	if bundle := readSchemaBundle(schemas, dir); bundle.Commit != "1a2b3c4" {
+		t.Errorf("Expected the revision of the embedded schemas, got %+v", bundle)
+	}
+
+	// The schemas are read where they are embedded, with nothing written
+	// to disk, wherever mcheck runs
+	defer func(saved fs.FS) { embeddedSchemas = saved }(embeddedSchemas)
+	embeddedSchemas = files
+	t.Chdir(t.TempDir())
+	validator, err := Config{Version: "1.20.1", Edition: "java"}.NewValidator()
+	if err != nil {
+		t.Fatalf("NewValidator failed: %v", err)
+	}
+	findings := validator.CheckDocument("data/test/damage_type/float.json", "float.json", []byte(`{"message_id": "float", "exhaustion": -1, "scaling": "never"}`))
+	if len(findings) != 1 || findings[0].Rule != RuleOutOfRange {
+		t.Errorf("Expected the embedded schema to report the exhaustion, got %v", findings)
+	}
+	if entries, err := os.ReadDir("."); err != nil || len(entries) != 0 {
+		t.Errorf("Expected nothing to be written, got %v %v", entries, err)
+	}
+
+	if _, _, err := embeddedSchemaDir(fstest.MapFS{"a.mcdoc": {}, "b.mcdoc": {}}); err == nil {
+		t.Errorf("Expected an error for schemas that are not a single directory")
+	}
+}
@@ -0,0 +1,67 @@
+package mcheck
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+const sharpnessEnchantment = `{
+	"description": {"translate": "enchantment.minecraft.sharpness"},
+	"exclusive_set": "#minecraft:exclusive_set/damage",
+	"supported_items": "#minecraft:enchantable/sharp_weapon",
+	"primary_items": "#minecraft:enchantable/sword",
+	"weight": 10,
+	"max_level": 5,
+	"min_cost": {"base": 1, "per_level_above_first": 11},
+	"max_cost": {"base": 21, "per_level_above_first": 11},
+	"anvil_cost": 1,
+	"slots": ["mainhand"],
+	"effects": {
+		"minecraft:damage": [
+			{"effect": {"type": "minecraft:add", "value": {"type": "minecraft:linear", "base": 1.0, "per_level_above_first": 0.5}}}
+		]
+	}
+}`
+
+func TestEnchantmentValidation(t *testing.T) {
+	schema, err := os.ReadFile("tests/mcdocs/enchantment.mcdoc")
+	if err != nil {
+		t.Fatalf("Failed to read enchantment schema: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		version  Version
+		document string
+		error    string
+	}{
+		{"sharpness", Version{1, 21, 0}, sharpnessEnchantment, ""},
+		{"constant level based value", Version{1, 21, 0},
+			strings.Replace(sharpnessEnchantment, `{"type": "minecraft:linear", "base": 1.0, "per_level_above_first": 0.5}`, `2.5`, 1), ""},
+		{"incomplete level based value", Version{1, 21, 0},
+			strings.Replace(sharpnessEnchantment, `, "per_level_above_first": 0.5}`, `}`, 1),
+			"at effects.minecraft:damage.[0].effect.value: value does not match any union alternative"},
+		{"unknown value effect field", Version{1, 21, 0},
+			strings.Replace(sharpnessEnchantment, `"type": "minecraft:add", `, `"type": "minecraft:add", "amount": 1, `, 1),
+			"at effects.minecraft:damage.[0].effect: unexpected field 'amount'"},
+		{"effect component shape", Version{1, 21, 0},
+			strings.Replace(sharpnessEnchantment, `"minecraft:damage": [`, `"minecraft:damage": 3, "minecraft:knockback": [`, 1),
+			"at effects.minecraft:damage: expected array"},
+		{"weight range", Version{1, 21, 0}, strings.Replace(sharpnessEnchantment, `"weight": 10`, `"weight": 0`, 1), "at weight:"},
+		{"before 1.21", Version{1, 20, 6}, sharpnessEnchantment, "enchantment files require Minecraft 1.21 or later, target is 1.20.6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaDir, jsonPath := writeTestPack(t, "enchantment", string(schema), tt.document)
+			err := NewPEGMCDocValidator(tt.version, schemaDir).ValidateJSON(jsonPath)
+			switch {
+			case tt.error == "" && err != nil:
+				t.Errorf("Expected valid, got %v", err)
+			case tt.error != "" && (err == nil || !strings.Contains(err.Error(), tt.error)):
+				t.Errorf("Expected error containing %q, got %v", tt.error, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package mcheck
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Explanation describes the schema behind a finding: the declaration of the
+// offending value, the version bounds that applied to it and values the
+// schema accepts in its place. It is built from the parsed schema, so it
+// matches the schema revision and version the finding was reported for.
+type Explanation struct {
+	Finding     Finding  `json:"finding"`
+	Type        string   `json:"type"`
+	Constraints []string `json:"constraints,omitempty"`
+	Source      string   `json:"source,omitempty"`  // schema file and line of the declaration
+	Excerpt     []string `json:"excerpt,omitempty"` // the declaration, as written in the schema
+	Since       string   `json:"since,omitempty"`
+	Until       string   `json:"until,omitempty"`
+	Feature     string   `json:"feature,omitempty"`
+	Target      string   `json:"target"`             // version the value was checked for
+	Examples    []string `json:"examples,omitempty"` // values the schema accepts, as JSON
+}
+
+// maxExamples bounds the example values of an explanation
+const maxExamples = 3
+
+// maxExcerptLines bounds the schema excerpt of an explanation
+const maxExcerptLines = 12
+
+// maxExampleDepth bounds how deep example objects and lists are built
+const maxExampleDepth = 4
+
+var missingFieldPattern = regexp.MustCompile(`^required field '([^']*)' is missing`)
+
+// Explain explains a finding reported for the JSON file at jsonPath. Findings
+// about a file as a whole, like invalid JSON, have no schema to explain.
+func (v *PEGMCDocValidator) Explain(jsonPath string, finding Finding) (*Explanation, error) {
+	converter, mainValidator, err := v.loadSchemaFor(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+	content, err := v.files.ReadFile(jsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON file: %w", err)
+	}
+	document, err := decodeJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	// A missing field is reported at the object missing it
+	var segments []string
+	for _, element := range finding.Path {
+		segments = append(segments, strings.TrimSuffix(strings.TrimPrefix(element, "["), "]"))
+	}
+	if finding.Rule == RuleMissingField {
+		if match := missingFieldPattern.FindStringSubmatch(finding.Message); match != nil {
+			segments = append(segments, match[1])
+		}
+	}
+	escape := strings.NewReplacer("~", "~0", "/", "~1")
+	pointer := ""
+	for _, segment := range segments {
+		pointer += "/" + escape.Replace(segment)
+	}
+
+	validator, info, ctx, err := resolvePointer(mainValidator, document, pointer, segments, v.newContext(converter))
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &Explanation{
+		Finding:     finding,
+		Type:        info.Type,
+		Constraints: info.Constraints,
+		Source:      info.Source,
+		Since:       info.Since,
+		Until:       info.Until,
+		Feature:     info.Feature,
+		Target:      v.targetVersion.String(),
+	}
+	if explanation.Source == "" {
+		explanation.Source = finding.Source
+	}
+	if explanation.Source == "" {
+		if sv, ok := concreteValidator(validator, nil, ctx).(*StructValidator); ok && sv.Source.Line > 0 {
+			explanation.Source = sv.Source.String()
+		}
+	}
+	explanation.Excerpt = v.schemaExcerpt(explanation.Source)
+	for _, example := range exampleValues(validator, ctx) {
+		encoded, err := marshalInSchemaOrder(example, validator, ctx, "")
+		if err == nil {
+			explanation.Examples = append(explanation.Examples, string(encoded))
+		}
+	}
+	return explanation, nil
+}
+
+// Gating describes how the version bounds of the declaration applied to the
+// target version
+func (e Explanation) Gating() string {
+	bounds := describeBounds(BaseValidator{Since: e.Since, Until: e.Until, Feature: e.Feature})
+	if bounds == "" {
+		return "all versions, checked for " + e.Target
+	}
+	return bounds + ", checked for " + e.Target
+}
+
+func (e Explanation) String() string {
+	var b strings.Builder
+	if e.Source != "" {
+		fmt.Fprintf(&b, "  schema:      %s\n", e.Source)
+		for _, line := range e.Excerpt {
+			fmt.Fprintf(&b, "               | %s\n", line)
+		}
+	}
+	fmt.Fprintf(&b, "  type:        %s\n", e.Type)
+	if len(e.Constraints) > 0 {
+		fmt.Fprintf(&b, "  constraints: %s\n", strings.Join(e.Constraints, "; "))
+	}
+	fmt.Fprintf(&b, "  versions:    %s\n", e.Gating())
+	if len(e.Examples) > 0 {
+		fmt.Fprintf(&b, "  examples:    %s\n", strings.Join(e.Examples, ", "))
+	}
+	return b.String()
+}
+
+// schemaExcerpt returns the declaration at a source position like
+// worldgen/biome.mcdoc:42, up to the line closing the brackets it opens
+func (v *PEGMCDocValidator) schemaExcerpt(source string) []string {
+	colon := strings.LastIndex(source, ":")
+	if colon < 0 {
+		return nil
+	}
+	var line int
+	if _, err := fmt.Sscan(source[colon+1:], &line); err != nil || line < 1 {
+		return nil
+	}
+	file, err := v.schemas.Open(v.schemaFile(source[:colon]))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var excerpt []string
+	depth := 0
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		if n < line {
+			continue
+		}
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		excerpt = append(excerpt, text)
+		depth += strings.Count(text, "{") + strings.Count(text, "(") + strings.Count(text, "[")
+		depth -= strings.Count(text, "}") + strings.Count(text, ")") + strings.Count(text, "]")
+		if depth <= 0 && !strings.HasPrefix(strings.TrimSpace(text), "#[") {
+			break
+		}
+		if len(excerpt) == maxExcerptLines {
+			excerpt = append(excerpt, "...")
+			break
+		}
+	}
+	return unindent(excerpt)
+}
+
+// schemaFile returns the path of a schema file named by schemaName
+func (v *PEGMCDocValidator) schemaFile(name string) string {
+	path := filepath.Join(v.schemaDir, v.edition, "data", filepath.FromSlash(name))
+	if _, err := v.schemas.Stat(path); err == nil {
+		return path
+	}
+	return filepath.Join(v.schemaDir, filepath.FromSlash(name))
+}
+
+// unindent removes the indentation the lines share
+func unindent(lines []string) []string {
+	shared := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" || line == "..." {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if shared < 0 || indent < shared {
+			shared = indent
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= shared && shared > 0 {
+			lines[i] = line[shared:]
+		}
+	}
+	return lines
+}
+
+// exampleValues returns values a validator accepts, built from its type and
+// constraints. Candidates the validator rejects, like strings that are not
+// valid ids, are left out.
+func exampleValues(validator Validator, ctx *ValidationContext) []interface{} {
+	var examples []interface{}
+	for _, candidate := range exampleCandidates(validator, ctx, 0) {
+		if len(examples) == maxExamples {
+			break
+		}
+		if hasErrors(validator.Validate(candidate, ctx)) || containsValue(examples, candidate) {
+			continue
+		}
+		examples = append(examples, candidate)
+	}
+	return examples
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, existing := range values {
+		if reflect.DeepEqual(existing, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// exampleCandidates proposes values for a validator, most typical first
+func exampleCandidates(validator Validator, ctx *ValidationContext, depth int) []interface{} {
+	if validator == nil || depth > maxExampleDepth || !validator.AppliesForVersion(ctx) {
+		return nil
+	}
+
+	switch v := validator.(type) {
+	case *PrimitiveValidator:
+		switch v.Type {
+		case "string":
+			return []interface{}{"minecraft:example", "example"}
+		case "byte", "short", "int", "long":
+			return []interface{}{float64(0), float64(1)}
+		case "float", "double":
+			return []interface{}{float64(0), 0.5, float64(1)}
+		case "boolean":
+			return []interface{}{true, false}
+		}
+	case *RangeValidator:
+		return rangeCandidates(v, false)
+	case *ConstrainedValidator:
+		rv, ok := v.Constraint.(*RangeValidator)
+		if !ok {
+			return exampleCandidates(v.InnerValidator, ctx, depth)
+		}
+		if primitive, ok := v.InnerValidator.(*PrimitiveValidator); ok && primitive.Type == "string" {
+			var candidates []interface{}
+			for _, length := range rangeCandidates(rv, true) {
+				candidates = append(candidates, strings.Repeat("a", int(length.(float64))))
+			}
+			return candidates
+		}
+		primitive, ok := v.InnerValidator.(*PrimitiveValidator)
+		return rangeCandidates(rv, ok && isIntegerType(primitive.Type))
+	case *EnumValidator:
+		var candidates []interface{}
+		for _, value := range v.Values {
+			if value.AppliesForVersion(ctx) {
+				candidates = append(candidates, value.Value)
+			}
+		}
+		return candidates
+	case *LiteralValidator:
+		return []interface{}{v.Value}
+	case *UnionValidator:
+		var candidates []interface{}
+		for _, alt := range v.applicable(ctx) {
+			if altCandidates := exampleCandidates(alt, ctx, depth); len(altCandidates) > 0 {
+				candidates = append(candidates, altCandidates[0])
+			}
+		}
+		return candidates
+	case *ReferenceValidator:
+		return exampleCandidates(ctx.Definitions[v.TypeName], ctx, depth+1)
+	case *AttributedValidator:
+		return exampleCandidates(v.InnerValidator, ctx, depth)
+	case *DispatchValidator:
+		if !v.Dynamic {
+			return exampleCandidates(v.Resolve(nil, ctx), ctx, depth+1)
+		}
+	case *ArrayValidator:
+		length := 1
+		if v.LengthConstraint != nil && v.LengthConstraint.Min != nil {
+			length = int(*v.LengthConstraint.Min)
+		}
+		elements := exampleCandidates(v.ElementValidator, ctx, depth+1)
+		if len(elements) == 0 || length == 0 {
+			return []interface{}{[]interface{}{}}
+		}
+		array := make([]interface{}, length)
+		for i := range array {
+			array[i] = elements[0]
+		}
+		return []interface{}{array}
+	case *StructValidator:
+		obj := make(map[string]interface{})
+		v.exampleFields(obj, ctx, depth)
+		return []interface{}{obj}
+	}
+	return nil
+}
+
+// exampleFields fills in the required fields of a struct and the structs it
+// spreads
+func (sv StructValidator) exampleFields(obj map[string]interface{}, ctx *ValidationContext, depth int) {
+	for _, field := range sv.Fields {
+		if field.Optional || !field.AppliesForVersion(ctx) {
+			continue
+		}
+		if _, exists := obj[field.Name]; exists {
+			continue
+		}
+		if candidates := exampleCandidates(field.Validator, ctx, depth+1); len(candidates) > 0 {
+			obj[field.Name] = candidates[0]
+		}
+	}
+	for _, spread := range sv.SpreadFields {
+		if spreadStruct, ok := resolveSpread(spread, obj, ctx, depth); ok && spreadStruct != nil && spreadStruct.AppliesForVersion(ctx) {
+			spreadStruct.exampleFields(obj, ctx, depth+1)
+		}
+	}
+}
+
+// rangeCandidates returns the bounds and middle of a range, keeping integers
+// whole
+func rangeCandidates(rv *RangeValidator, integer bool) []interface{} {
+	step := 0.5
+	if integer {
+		step = 1
+	}
+	var candidates []interface{}
+	low, high := math.Inf(-1), math.Inf(1)
+	if rv.Min != nil {
+		low = *rv.Min
+		if rv.MinExclusive {
+			low += step
+		}
+		candidates = append(candidates, low)
+	}
+	if rv.Min != nil && rv.Max != nil {
+		middle := (*rv.Min + *rv.Max) / 2
+		if integer {
+			middle = math.Floor(middle)
+		}
+		candidates = append(candidates, middle)
+	}
+	if rv.Max != nil {
+		high = *rv.Max
+		if rv.MaxExclusive {
+			high -= step
+		}
+		candidates = append(candidates, high)
+	}
+	if rv.Min == nil && rv.Max == nil {
+		candidates = append(candidates, float64(0))
+	}
+	if low > high {
+		return nil
+	}
+	return candidates
+}
@@ -0,0 +1,107 @@
+package mcheck
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const explainTestSchema = `dispatch minecraft:resource["worldgen/thing"] to struct Thing {
+	/// The height
+	#[since="1.19"]
+	height: int @ 0..384,
+	mode: Mode,
+	settings: struct Settings {
+		scale: float @ 0.5..2,
+		tags: [string] @ 1..,
+	},
+}
+
+enum(string) Mode {
+	Fast = "fast",
+	Slow = "slow",
+}
+`
+
+func TestExplain(t *testing.T) {
+	schemaDir, jsonPath := writeTestPack(t, "worldgen/thing", explainTestSchema,
+		`{"height": 500, "mode": "medium", "settings": {"scale": 1}}`)
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, schemaDir)
+
+	tests := []struct {
+		rule     string
+		source   string
+		excerpt  []string
+		gating   string
+		examples []string
+	}{
+		{RuleOutOfRange, "worldgen/thing.mcdoc:3", []string{`#[since="1.19"]`, "height: int @ 0..384,"}, "since 1.19, checked for 1.20.1", []string{"0", "192", "384"}},
+		{RuleInvalidEnumValue, "worldgen/thing.mcdoc:5", []string{"mode: Mode,"}, "all versions, checked for 1.20.1", []string{`"fast"`, `"slow"`}},
+		{RuleMissingField, "worldgen/thing.mcdoc:8", []string{"tags: [string] @ 1..,"}, "all versions, checked for 1.20.1", []string{`["minecraft:example"]`}},
+	}
+
+	findings := validator.CheckFile(jsonPath, jsonPath)
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			var finding *Finding
+			for i := range findings {
+				if findings[i].Rule == tt.rule {
+					finding = &findings[i]
+				}
+			}
+			if finding == nil {
+				t.Fatalf("Expected a %s finding, got %v", tt.rule, findings)
+			}
+
+			explanation, err := validator.Explain(jsonPath, *finding)
+			if err != nil {
+				t.Fatalf("Failed to explain %v: %v", finding, err)
+			}
+			if explanation.Source != tt.source {
+				t.Errorf("Expected source %s, got %s", tt.source, explanation.Source)
+			}
+			if !reflect.DeepEqual(explanation.Excerpt, tt.excerpt) {
+				t.Errorf("Expected excerpt %q, got %q", tt.excerpt, explanation.Excerpt)
+			}
+			if explanation.Gating() != tt.gating {
+				t.Errorf("Expected gating %q, got %q", tt.gating, explanation.Gating())
+			}
+			if !reflect.DeepEqual(explanation.Examples, tt.examples) {
+				t.Errorf("Expected examples %v, got %v", tt.examples, explanation.Examples)
+			}
+		})
+	}
+}
+
+func TestExplainStructExcerpt(t *testing.T) {
+	schemaDir, jsonPath := writeTestPack(t, "worldgen/thing", explainTestSchema,
+		`{"height": 1, "mode": "fast"}`)
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, schemaDir)
+
+	findings := validator.CheckFile(jsonPath, jsonPath)
+	if len(findings) != 1 {
+		t.Fatalf("Expected the settings field to be missing, got %v", findings)
+	}
+	explanation, err := validator.Explain(jsonPath, findings[0])
+	if err != nil {
+		t.Fatalf("Failed to explain %v: %v", findings[0], err)
+	}
+	expected := "settings: struct Settings {\n\tscale: float @ 0.5..2,\n\ttags: [string] @ 1..,\n},"
+	if excerpt := strings.Join(explanation.Excerpt, "\n"); excerpt != expected {
+		t.Errorf("Expected excerpt %q, got %q", expected, excerpt)
+	}
+	if len(explanation.Examples) != 1 || explanation.Examples[0] != `{"scale":0.5,"tags":["minecraft:example"]}` {
+		t.Errorf("Expected an example object with the required fields, got %v", explanation.Examples)
+	}
+}
+
+func TestLookupRule(t *testing.T) {
+	for _, name := range []string{"MCHECK010", "mcheck010", "out-of-range"} {
+		if rule, ok := LookupRule(name); !ok || rule.ID != RuleOutOfRange {
+			t.Errorf("Expected %s to name %s, got %v", name, RuleOutOfRange, rule)
+		}
+	}
+	if _, ok := LookupRule("MCHECK999"); ok {
+		t.Errorf("Expected MCHECK999 to be unknown")
+	}
+}
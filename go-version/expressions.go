@@ -1,4 +1,6 @@
-package main
+package mcheck
+
+import "strconv"
 
 // Expression represents a value in the mcdoc AST
 type Expression interface {
@@ -44,6 +46,23 @@ func (i Identifier) String() string {
 	return i.Name
 }
 
+// SourcePos is where a declaration appears in a schema file, like
+// worldgen/biome.mcdoc:42
+type SourcePos struct {
+	File string // schema file relative to the data directory of its edition, if known
+	Line int    // 1-based, 0 if unknown
+}
+
+func (p SourcePos) String() string {
+	if p.Line == 0 {
+		return p.File
+	}
+	if p.File == "" {
+		return "line " + strconv.Itoa(p.Line)
+	}
+	return p.File + ":" + strconv.Itoa(p.Line)
+}
+
 // String represents a string literal
 type StringLiteral struct {
 	Value string
@@ -78,6 +97,10 @@ func (b BooleanLiteral) String() string {
 type StructExpression struct {
 	Name   *Identifier // optional name for inline structs
 	Fields []FieldExpression
+	Doc    string
+	Pos    SourcePos
+
+	EndComments []string // comments after the last field, like "// TODO"
 }
 
 func (s StructExpression) String() string {
@@ -98,16 +121,239 @@ func (s StructExpression) String() string {
 
 // FieldExpression represents a field in a struct
 type FieldExpression struct {
-	Name     Identifier
-	Type     Expression
-	Optional bool
+	Name       Identifier
+	Type       Expression
+	Optional   bool
+	Spread     bool       // ...Type fields reuse the fields of another struct
+	Key        Expression // key type of computed fields like [string]: Type
+	Attributes []Attribute
+	Doc        string
+	Pos        SourcePos
+
+	Comments    []string // comments before the field or inside it
+	LineComment string   // comment after the field on its last line
 }
 
 func (f FieldExpression) String() string {
+	if f.Spread {
+		return "..." + f.Type.String()
+	}
 	result := f.Name.Name
+	if f.Key != nil {
+		result = "[" + f.Key.String() + "]"
+	}
 	if f.Optional {
 		result += "?"
 	}
 	result += ": " + f.Type.String()
 	return result
-}
\ No newline at end of file
+}
+
+// PrimitiveExpression represents a builtin type like string, int or boolean
+type PrimitiveExpression struct {
+	Name string
+}
+
+func (p PrimitiveExpression) String() string {
+	return p.Name
+}
+
+// RangeExpression represents a numeric range like 0..10, 1<.. or ..<5
+type RangeExpression struct {
+	Min          *string
+	Max          *string
+	MinExclusive bool
+	MaxExclusive bool
+}
+
+func (r RangeExpression) String() string {
+	result := ""
+	if r.Min != nil {
+		result += *r.Min
+	}
+	if r.Min != nil && r.Max != nil && *r.Min == *r.Max && !r.MinExclusive && !r.MaxExclusive {
+		return result
+	}
+	if r.MinExclusive {
+		result += "<"
+	}
+	result += ".."
+	if r.MaxExclusive {
+		result += "<"
+	}
+	if r.Max != nil {
+		result += *r.Max
+	}
+	return result
+}
+
+// ConstrainedExpression represents a type with a range constraint like int @ 0..10
+type ConstrainedExpression struct {
+	Type  Expression
+	Range RangeExpression
+}
+
+func (c ConstrainedExpression) String() string {
+	return c.Type.String() + " @ " + c.Range.String()
+}
+
+// ArrayExpression represents a list type like [string] @ 1.. or a typed
+// array like int[] @ 4
+type ArrayExpression struct {
+	Element Expression
+	Length  *RangeExpression
+	Typed   string // element type of a typed array like int[]: "byte", "int" or "long"
+}
+
+func (a ArrayExpression) String() string {
+	result := "[" + a.Element.String() + "]"
+	if a.Typed != "" {
+		result = a.Element.String() + "[]"
+	}
+	if a.Length != nil {
+		result += " @ " + a.Length.String()
+	}
+	return result
+}
+
+// UnionExpression represents a union type like (string | int)
+type UnionExpression struct {
+	Alternatives []Expression
+}
+
+func (u UnionExpression) String() string {
+	result := "("
+	for i, alt := range u.Alternatives {
+		if i > 0 {
+			result += " | "
+		}
+		result += alt.String()
+	}
+	return result + ")"
+}
+
+// GenericExpression represents a type with type arguments like FloatProvider<float>
+type GenericExpression struct {
+	Base Expression
+	Args []Expression
+}
+
+func (g GenericExpression) String() string {
+	result := g.Base.String() + "<"
+	for i, arg := range g.Args {
+		if i > 0 {
+			result += ", "
+		}
+		result += arg.String()
+	}
+	return result + ">"
+}
+
+// DispatchExpression represents a dispatcher access like minecraft:block_predicate[[type]]
+// or minecraft:resource[biome]. Dynamic accesses read their key from the value being validated.
+type DispatchExpression struct {
+	Registry string
+	Key      string
+	Dynamic  bool
+	Args     []Expression
+}
+
+func (d DispatchExpression) String() string {
+	if d.Dynamic {
+		return d.Registry + "[[" + d.Key + "]]"
+	}
+	return d.Registry + "[" + d.Key + "]"
+}
+
+// Attribute represents an attribute like #[since="1.17"] or #[id(registry="item")]
+type Attribute struct {
+	Name  string
+	Value Expression // nil for bare attributes like #[canonical]
+}
+
+func (a Attribute) String() string {
+	if a.Value == nil {
+		return "#[" + a.Name + "]"
+	}
+	if _, ok := a.Value.(AttributeTree); ok {
+		return "#[" + a.Name + a.Value.String() + "]"
+	}
+	return "#[" + a.Name + "=" + a.Value.String() + "]"
+}
+
+// AttributeTree represents the parenthesized argument list of an attribute
+type AttributeTree struct {
+	Positional []Expression
+	Named      []AttributeArg
+}
+
+// AttributeArg is a named value inside an attribute tree
+type AttributeArg struct {
+	Name  string
+	Value Expression
+}
+
+func (t AttributeTree) String() string {
+	result := "("
+	n := 0
+	for _, value := range t.Positional {
+		if n > 0 {
+			result += ", "
+		}
+		result += value.String()
+		n++
+	}
+	for _, arg := range t.Named {
+		if n > 0 {
+			result += ", "
+		}
+		result += arg.Name + "=" + arg.Value.String()
+		n++
+	}
+	return result + ")"
+}
+
+// ArrayLiteral represents a list of values inside an attribute like ["air"]
+type ArrayLiteral struct {
+	Values []Expression
+}
+
+func (a ArrayLiteral) String() string {
+	result := "["
+	for i, value := range a.Values {
+		if i > 0 {
+			result += ", "
+		}
+		result += value.String()
+	}
+	return result + "]"
+}
+
+// AttributedExpression represents a type preceded by one or more attributes
+type AttributedExpression struct {
+	Attributes []Attribute
+	Type       Expression
+}
+
+func (a AttributedExpression) String() string {
+	result := ""
+	for _, attr := range a.Attributes {
+		result += attr.String() + " "
+	}
+	return result + a.Type.String()
+}
+
+// EnumValueExpression represents a single named value of an enum
+type EnumValueExpression struct {
+	Name       string
+	Value      Expression
+	Attributes []Attribute
+	Doc        string
+
+	Comments    []string // comments before the value
+	LineComment string   // comment after the value on its line
+}
+
+func (e EnumValueExpression) String() string {
+	return e.Name + " = " + e.Value.String()
+}
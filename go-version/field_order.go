@@ -0,0 +1,192 @@
+package mcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// structMembers are the fields of a struct and of the structs it spreads
+type structMembers struct {
+	Fields     []StructField
+	Dynamic    []DynamicField
+	Unresolved []Validator // spreads that cannot be resolved without a value
+}
+
+// declaredMembers collects the members of a struct that apply to the target
+// version in the order the schema declares them, with the fields of each
+// spread struct in place of the spread, like the type of a loot pool entry
+// ahead of the fields of its dispatch case. Spreads of dispatches resolve
+// against obj, which may be nil. A field declared again is kept where it
+// first appears.
+func declaredMembers(sv *StructValidator, obj map[string]interface{}, ctx *ValidationContext) structMembers {
+	var members structMembers
+	seen := make(map[string]bool)
+	var collect func(sv *StructValidator, depth int)
+	collect = func(sv *StructValidator, depth int) {
+		spread := 0
+		spreadsUpTo := func(fields int) {
+			for ; spread < len(sv.SpreadFields) && spreadPosition(sv, spread) <= fields; spread++ {
+				spreadStruct, ok := resolveSpread(sv.SpreadFields[spread], obj, ctx, depth)
+				if !ok {
+					members.Unresolved = append(members.Unresolved, sv.SpreadFields[spread])
+				} else if spreadStruct != nil && spreadStruct.AppliesForVersion(ctx) {
+					collect(spreadStruct, depth+1)
+				}
+			}
+		}
+		for i, field := range sv.Fields {
+			spreadsUpTo(i)
+			if !seen[field.Name] && field.AppliesForVersion(ctx) {
+				seen[field.Name] = true
+				members.Fields = append(members.Fields, field)
+			}
+		}
+		spreadsUpTo(len(sv.Fields))
+		for _, field := range sv.DynamicFields {
+			if field.AppliesForVersion(ctx) {
+				members.Dynamic = append(members.Dynamic, field)
+			}
+		}
+	}
+	collect(sv, 0)
+	return members
+}
+
+// spreadPosition returns the number of fields declared before a spread.
+// Structs not built from a schema have their spreads after their fields.
+func spreadPosition(sv *StructValidator, spread int) int {
+	if spread < len(sv.SpreadAt) {
+		return sv.SpreadAt[spread]
+	}
+	return len(sv.Fields)
+}
+
+// marshalInSchemaOrder encodes value as JSON like json.MarshalIndent, but
+// writes the fields of objects in the order their schema declares them
+// rather than sorted, so that generated and rewritten documents read like
+// vanilla's. Keys the schema does not declare, like those of computed
+// fields, follow in sorted order. An empty indent writes compact JSON.
+func marshalInSchemaOrder(value interface{}, validator Validator, ctx *ValidationContext, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeInSchemaOrder(&buf, value, validator, ctx, "", indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeInSchemaOrder(buf *bytes.Buffer, value interface{}, validator Validator, ctx *ValidationContext, prefix, indent string) error {
+	newline := func(prefix string) {
+		if indent != "" {
+			buf.WriteByte('\n')
+			buf.WriteString(prefix)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		sv, _ := concreteValidator(validator, v, ctx).(*StructValidator)
+		objCtx := ctx.WithParent(v)
+		var members structMembers
+		if sv != nil {
+			members = declaredMembers(sv, v, objCtx)
+		}
+
+		keys := make([]string, 0, len(v))
+		declared := make(map[string]Validator, len(v))
+		for _, field := range members.Fields {
+			if _, exists := v[field.Name]; exists {
+				keys = append(keys, field.Name)
+				declared[field.Name] = field.Validator
+			}
+		}
+		var rest []string
+		for key := range v {
+			if _, ok := declared[key]; !ok {
+				rest = append(rest, key)
+			}
+		}
+		sort.Strings(rest)
+		keys = append(keys, rest...)
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(prefix + indent)
+			name, err := json.Marshal(key)
+			if err != nil {
+				return err
+			}
+			buf.Write(name)
+			buf.WriteByte(':')
+			if indent != "" {
+				buf.WriteByte(' ')
+			}
+			child, ok := declared[key]
+			if !ok {
+				child = dynamicFieldValidator(members.Dynamic, key, objCtx)
+			}
+			if err := writeInSchemaOrder(buf, v[key], child, objCtx.Child(key), prefix+indent, indent); err != nil {
+				return err
+			}
+		}
+		newline(prefix)
+		buf.WriteByte('}')
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		var element Validator
+		if av, ok := concreteValidator(validator, v, ctx).(*ArrayValidator); ok {
+			element = av.ElementValidator
+		}
+		arrCtx := ctx.WithParent(v)
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(prefix + indent)
+			if err := writeInSchemaOrder(buf, item, element, arrCtx, prefix+indent, indent); err != nil {
+				return err
+			}
+		}
+		newline(prefix)
+		buf.WriteByte(']')
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(encoded)
+	}
+	return nil
+}
+
+// dynamicFieldValidator returns the validator of the first computed field
+// whose key type accepts key, or nil
+func dynamicFieldValidator(fields []DynamicField, key string, ctx *ValidationContext) Validator {
+	for _, field := range fields {
+		if !hasErrors(field.Key.Validate(key, ctx)) {
+			return field.Validator
+		}
+	}
+	return nil
+}
+
+// MarshalDocument encodes a document of a resource type as indented JSON
+// with the fields of its objects in the order the schema declares them
+func (v *PEGMCDocValidator) MarshalDocument(resourceType string, document interface{}) ([]byte, error) {
+	converter, mainValidator, _, err := v.loadResourceType(resourceType)
+	if err != nil {
+		return nil, err
+	}
+	return marshalInSchemaOrder(document, mainValidator, v.newContext(converter), "  ")
+}
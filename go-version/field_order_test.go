@@ -0,0 +1,99 @@
+package mcheck
+
+import (
+	"strings"
+	"testing"
+)
+
+const fieldOrderTestSchema = `dispatch minecraft:resource[loot_table] to struct LootTable {
+	type?: string,
+	pools?: [LootPool],
+	functions?: [struct { function: string }],
+}
+
+struct LootPool {
+	rolls: float,
+	entries: [Entry],
+	[#[id="item"] string]: struct Weight { weight: int, quality?: int },
+}
+
+struct Entry {
+	type: string,
+	...minecraft:entry[[type]],
+	conditions?: [string],
+}
+
+struct Weighted {
+	weight?: int,
+	quality?: int,
+}
+
+dispatch minecraft:entry[item] to struct ItemEntry {
+	...Weighted,
+	name: string,
+	#[until="1.20"]
+	count?: int,
+	functions?: [string],
+}
+`
+
+func TestDeclaredMembers(t *testing.T) {
+	_, ctx := convertSchema(t, "1.20.1", fieldOrderTestSchema)
+	entry := ctx.Definitions["Entry"].(*StructValidator)
+
+	names := func(obj map[string]interface{}) string {
+		var names []string
+		for _, field := range declaredMembers(entry, obj, ctx.WithParent(obj)).Fields {
+			names = append(names, field.Name)
+		}
+		return strings.Join(names, " ")
+	}
+	if got := names(map[string]interface{}{"type": "minecraft:item"}); got != "type weight quality name functions conditions" {
+		t.Errorf("Expected the fields of the item case in place of the spread, got %q", got)
+	}
+	if got := names(map[string]interface{}{"type": "minecraft:empty"}); got != "type conditions" {
+		t.Errorf("Expected the fields of Entry for an unknown case, got %q", got)
+	}
+	if members := declaredMembers(entry, nil, ctx); len(members.Unresolved) != 1 {
+		t.Errorf("Expected the dispatch to be unresolved without a value, got %v", members.Unresolved)
+	}
+
+	pool := ctx.Definitions["LootPool"].(*StructValidator)
+	if members := declaredMembers(pool, nil, ctx); len(members.Fields) != 2 || len(members.Dynamic) != 1 {
+		t.Errorf("Expected two fields and a computed field, got %+v", members)
+	}
+}
+
+func TestMarshalInSchemaOrder(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", fieldOrderTestSchema)
+	document, err := decodeJSON([]byte(`{
+		"pools": [{
+			"minecraft:stick": {"quality": 1, "weight": 2},
+			"entries": [{"name": "minecraft:stick", "conditions": [], "weight": 1, "type": "minecraft:item", "extra": true}],
+			"rolls": 1
+		}],
+		"functions": [{"function": "set_count"}],
+		"type": "minecraft:chest"
+	}`))
+	if err != nil {
+		t.Fatalf("Invalid document: %v", err)
+	}
+	lootTable := converter.MainValidatorFor("loot_table")
+
+	compact, err := marshalInSchemaOrder(document, lootTable, ctx, "")
+	if err != nil {
+		t.Fatalf("marshalInSchemaOrder failed: %v", err)
+	}
+	expected := `{"type":"minecraft:chest","pools":[{"rolls":1,"entries":[{"type":"minecraft:item","weight":1,"name":"minecraft:stick","conditions":[],"extra":true}],"minecraft:stick":{"weight":2,"quality":1}}],"functions":[{"function":"set_count"}]}`
+	if string(compact) != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, compact)
+	}
+
+	indented, err := marshalInSchemaOrder(map[string]interface{}{"pools": []interface{}{}, "type": "a", "z": map[string]interface{}{}}, lootTable, ctx, "  ")
+	if err != nil {
+		t.Fatalf("marshalInSchemaOrder failed: %v", err)
+	}
+	if expected := "{\n  \"type\": \"a\",\n  \"pools\": [],\n  \"z\": {}\n}"; string(indented) != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, indented)
+	}
+}
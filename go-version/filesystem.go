@@ -0,0 +1,247 @@
+package mcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// fileSystem reads the files mcheck checks and the schemas it loads. mcheck
+// builds paths by joining a directory with names, like
+// vanilla-mcdoc/java/data/trim.mcdoc; a fileSystem backed by an fs.FS serves
+// the paths below its root from the FS, so that embedded schemas, zipped
+// packs and in-memory test files are read by the same code as files on disk.
+type fileSystem struct {
+	root string          // directory the names in fsys are relative to
+	fsys fs.FS           // nil for the files of the operating system
+	ctx  context.Context // ends reading when done, if set
+}
+
+// osFiles reads files from the operating system
+var osFiles = fileSystem{}
+
+// newFileSystem serves the paths below root from fsys, or from the
+// operating system if fsys is nil
+func newFileSystem(root string, fsys fs.FS) fileSystem {
+	return fileSystem{root: root, fsys: fsys}
+}
+
+// withContext returns the file system reading files until ctx is done,
+// after which opening, reading and walking fail with the error of ctx. It
+// is set for a single check, so that cancelling it stops the check at the
+// next read, even halfway through a file that is streamed.
+func (f fileSystem) withContext(ctx context.Context) fileSystem {
+	f.ctx = ctx
+	return f
+}
+
+// err returns the error of the context of the file system once it is done
+func (f fileSystem) err() error {
+	if f.ctx == nil {
+		return nil
+	}
+	return f.ctx.Err()
+}
+
+// done returns the channel closed once the context of the file system is
+// done, or nil if it has none
+func (f fileSystem) done() <-chan struct{} {
+	if f.ctx == nil {
+		return nil
+	}
+	return f.ctx.Done()
+}
+
+// contextFile is a file whose reads fail once its context is done
+type contextFile struct {
+	fs.File
+	ctx context.Context
+}
+
+func (f contextFile) Read(p []byte) (int, error) {
+	if err := f.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return f.File.Read(p)
+}
+
+// name returns the name of a path below root in fsys
+func (f fileSystem) name(op, file string) (string, error) {
+	rel, err := filepath.Rel(f.root, file)
+	name := filepath.ToSlash(rel)
+	if err != nil || !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: file, Err: fs.ErrNotExist}
+	}
+	return name, nil
+}
+
+func (f fileSystem) Open(file string) (fs.File, error) {
+	if err := f.err(); err != nil {
+		return nil, &fs.PathError{Op: "open", Path: file, Err: err}
+	}
+	opened, err := f.open(file)
+	if err != nil || f.ctx == nil {
+		return opened, err
+	}
+	return contextFile{opened, f.ctx}, nil
+}
+
+func (f fileSystem) open(file string) (fs.File, error) {
+	if f.fsys == nil {
+		return os.Open(file)
+	}
+	name, err := f.name("open", file)
+	if err != nil {
+		return nil, err
+	}
+	return f.fsys.Open(name)
+}
+
+func (f fileSystem) ReadFile(file string) ([]byte, error) {
+	if err := f.err(); err != nil {
+		return nil, &fs.PathError{Op: "open", Path: file, Err: err}
+	}
+	if f.fsys == nil {
+		return os.ReadFile(file)
+	}
+	name, err := f.name("open", file)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(f.fsys, name)
+}
+
+func (f fileSystem) Stat(file string) (fs.FileInfo, error) {
+	if err := f.err(); err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: file, Err: err}
+	}
+	if f.fsys == nil {
+		return os.Stat(file)
+	}
+	name, err := f.name("stat", file)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Stat(f.fsys, name)
+}
+
+func (f fileSystem) ReadDir(dir string) ([]fs.DirEntry, error) {
+	if f.fsys == nil {
+		return os.ReadDir(dir)
+	}
+	name, err := f.name("open", dir)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadDir(f.fsys, name)
+}
+
+// maxWalkDepth bounds how deep WalkDir descends, which also ends the cycles
+// of symbolic links it cannot detect, like those within an fs.FS
+const maxWalkDepth = 64
+
+var (
+	errLinkCycle  = errors.New("symbolic link to a directory containing it")
+	errBrokenLink = errors.New("broken symbolic link")
+	errTooDeep    = fmt.Errorf("nested more than %d directories deep", maxWalkDepth)
+)
+
+// WalkDir walks the tree at dir like filepath.WalkDir, passing fn the paths
+// of the files joined with dir. Unlike filepath.WalkDir it follows symbolic
+// links, since packs link in resources they share. Links back into a
+// directory being walked, broken links and directories nested too deep are
+// passed to fn with an error rather than walked, like directories that
+// cannot be read, so that fn can skip them and go on.
+func (f fileSystem) WalkDir(dir string, fn fs.WalkDirFunc) error {
+	if err := f.err(); err != nil {
+		return err
+	}
+	info, err := f.Stat(dir)
+	if err != nil {
+		err = fn(dir, nil, err)
+	} else {
+		err = f.walkDir(dir, fs.FileInfoToDirEntry(info), []fs.FileInfo{info}, fn)
+	}
+	if err == fs.SkipDir || err == fs.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walkDir walks an entry whose directory and its ancestors are parents
+func (f fileSystem) walkDir(file string, entry fs.DirEntry, parents []fs.FileInfo, fn fs.WalkDirFunc) error {
+	// Walking stops once the context is done, rather than passing the
+	// entries left to fn with its error
+	if err := f.err(); err != nil {
+		return err
+	}
+	if err := fn(file, entry, nil); err != nil || !entry.IsDir() {
+		if err == fs.SkipDir && entry.IsDir() {
+			return nil
+		}
+		return err
+	}
+
+	entries, err := f.ReadDir(file)
+	if err != nil {
+		if err := fn(file, entry, err); err != nil {
+			if err == fs.SkipDir {
+				return nil
+			}
+			return err
+		}
+	}
+	for _, child := range entries {
+		path := filepath.Join(file, child.Name())
+		resolved, info, err := f.follow(path, child, parents)
+		if err != nil {
+			err = fn(path, child, err)
+		} else {
+			err = f.walkDir(path, resolved, append(parents, info), fn)
+		}
+		if err == fs.SkipDir {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// follow resolves an entry of a directory being walked: a symbolic link
+// becomes the entry of its target, and a directory must not be one of the
+// parents being walked or nested too deep below them
+func (f fileSystem) follow(path string, entry fs.DirEntry, parents []fs.FileInfo) (fs.DirEntry, fs.FileInfo, error) {
+	var info fs.FileInfo
+	var err error
+	switch {
+	case entry.Type()&fs.ModeSymlink != 0:
+		info, err = f.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			err = &fs.PathError{Op: "stat", Path: path, Err: errBrokenLink}
+		}
+	case entry.IsDir():
+		info, err = entry.Info()
+	default:
+		return entry, nil, nil
+	}
+	if err != nil {
+		return entry, nil, err
+	}
+
+	if info.IsDir() {
+		if len(parents) > maxWalkDepth {
+			return entry, nil, &fs.PathError{Op: "walk", Path: path, Err: errTooDeep}
+		}
+		for _, parent := range parents {
+			if os.SameFile(parent, info) {
+				return entry, nil, &fs.PathError{Op: "walk", Path: path, Err: errLinkCycle}
+			}
+		}
+	}
+	return fs.FileInfoToDirEntry(info), info, nil
+}
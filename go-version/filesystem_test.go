@@ -0,0 +1,328 @@
+package mcheck
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCheckPackFS(t *testing.T) {
+	schema, err := os.ReadFile(filepath.Join("tests", "mcdocs", "damage_type.mcdoc"))
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	schemas := fstest.MapFS{"java/data/damage_type.mcdoc": {Data: schema}}
+	pack := map[string]string{
+		"data/test/damage_type/acid.json":  `{"message_id": "acid", "exhaustion": 0.1, "scaling": "never"}`,
+		"data/test/damage_type/float.json": `{"message_id": "float", "exhaustion": -1, "scaling": "never"}`,
+	}
+	expected := "data/test/damage_type/float.json: at exhaustion: value -1 must be greater than or equal to 0 (range 0..) [MCHECK010 out-of-range]"
+
+	// Neither the schemas nor the pack exist on disk
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, filepath.Join(t.TempDir(), "vanilla-mcdoc"))
+	validator.SetSchemaFS(schemas)
+
+	files := fstest.MapFS{}
+	for name, content := range pack {
+		files[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	for name, content := range pack {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		file.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to write zip: %v", err)
+	}
+	zipped, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read zip: %v", err)
+	}
+
+	packs := []struct {
+		name string
+		root string
+		fsys fs.FS
+	}{
+		{"memory", "pack", files},
+		{"zip", "pack.zip", zipped},
+	}
+	for _, pack := range packs {
+		t.Run(pack.name, func(t *testing.T) {
+			findings, err := validator.CheckPack(context.Background(), pack.root, pack.fsys, PackOptions{})
+			if err != nil {
+				t.Fatalf("CheckPack failed: %v", err)
+			}
+			var messages []string
+			for _, finding := range findings {
+				messages = append(messages, finding.String())
+			}
+			if strings.Join(messages, "\n") != expected {
+				t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+			}
+		})
+	}
+
+	if _, err := validator.CheckPack(context.Background(), "pack", files, PackOptions{ChangedFrom: "HEAD"}); err == nil {
+		t.Error("Expected checking changed files of a pack in memory to fail")
+	}
+	if _, err := LoadPackFS("pack", fstest.MapFS{"pack.mcmeta": {Data: []byte("{}")}}); err == nil {
+		t.Error("Expected a pack without data directory to fail")
+	}
+}
+
+func TestSetFileFS(t *testing.T) {
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, fixtureSchemaDir(t, "damage_type"))
+	validator.SetFileFS("pack", fstest.MapFS{
+		"data/test/damage_type/acid.json": {Data: []byte(`{"message_id": "acid", "exhaustion": 0.1, "scaling": "never"}`)},
+		"data/test/damage_type/bad.json":  {Data: []byte(`{"message_id": "bad", "scaling": "never"}`)},
+	})
+
+	if err := validator.ValidateJSON(filepath.Join("pack", "data", "test", "damage_type", "acid.json")); err != nil {
+		t.Errorf("Expected acid.json to be valid, got %v", err)
+	}
+	err := validator.ValidateJSON(filepath.Join("pack", "data", "test", "damage_type", "bad.json"))
+	if err == nil || !strings.Contains(err.Error(), "required field 'exhaustion' is missing") {
+		t.Errorf("Expected the missing exhaustion, got %v", err)
+	}
+
+	// Paths outside the root are not in the file system
+	err = validator.ValidateJSON(filepath.Join("elsewhere", "data", "test", "damage_type", "acid.json"))
+	if err == nil || !strings.Contains(err.Error(), "failed to read JSON file") {
+		t.Errorf("Expected a read error, got %v", err)
+	}
+}
+
+func TestWalkDirLinks(t *testing.T) {
+	root := writePackFiles(t, map[string][]byte{
+		"pack/data/test/recipe/bread.json":   []byte(`{}`),
+		"shared/loot_table/chests/ruin.json": []byte(`{}`),
+		"shared/recipe/cake.json":            []byte(`{}`),
+	})
+	pack := filepath.Join(root, "pack")
+	deep := filepath.Join(pack, "data", "test", "function")
+	for i := 0; i < maxWalkDepth; i++ {
+		deep = filepath.Join(deep, "d")
+	}
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	links := map[string]string{
+		filepath.Join(pack, "data", "shared"):                   filepath.Join(root, "shared"),
+		filepath.Join(pack, "data", "test", "loop"):             filepath.Join(pack, "data"),
+		filepath.Join(pack, "data", "test", "recipe", "gone"):   filepath.Join(root, "missing.json"),
+		filepath.Join(pack, "data", "test", "recipe", "a.json"): filepath.Join(root, "shared", "recipe", "cake.json"),
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("Cannot create symbolic links: %v", err)
+		}
+	}
+
+	loaded, err := LoadPack(pack)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+	for _, tt := range []struct{ resourceType, id string }{
+		{"recipe", "test:bread"},
+		{"recipe", "test:a"},
+		{"recipe", "shared:cake"},
+		{"loot_table", "shared:chests/ruin"},
+	} {
+		if _, ok := loaded.Lookup(tt.id, tt.resourceType); !ok {
+			t.Errorf("Expected %s %s to be indexed", tt.resourceType, tt.id)
+		}
+	}
+
+	var messages []string
+	for _, finding := range loaded.Skipped {
+		messages = append(messages, finding.String())
+	}
+	expected := []string{
+		"data/test/function/" + strings.Repeat("d/", maxWalkDepth-2) + "d: warning: skipped: nested more than 64 directories deep [MCHECK022 unreadable-file]",
+		"data/test/loop: warning: skipped: symbolic link to a directory containing it [MCHECK022 unreadable-file]",
+		"data/test/recipe/gone: warning: skipped: broken symbolic link [MCHECK022 unreadable-file]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected warnings:\n%s", strings.Join(messages, "\n"))
+	}
+}
+
+func TestWalkDirUnreadable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("Directory permissions do not apply to root")
+	}
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/recipe/bread.json":    []byte(`{}`),
+		"data/test/loot_table/ruin.json": []byte(`{}`),
+	})
+	locked := filepath.Join(root, "data", "test", "loot_table")
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Skipf("Cannot change permissions: %v", err)
+	}
+	defer os.Chmod(locked, 0o755)
+
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+	if _, ok := pack.Lookup("test:bread", "recipe"); !ok {
+		t.Error("Expected the readable recipe to be indexed")
+	}
+	if len(pack.Skipped) != 1 || pack.Skipped[0].File != "data/test/loot_table" || !strings.Contains(pack.Skipped[0].Message, "permission denied") {
+		t.Errorf("Expected the locked directory to be skipped, got %v", pack.Skipped)
+	}
+}
+
+// cancellingFS cancels a context once a file is opened
+type cancellingFS struct {
+	fs.FS
+	cancel func()
+	name   string
+}
+
+func (c cancellingFS) Open(name string) (fs.File, error) {
+	if name == c.name {
+		c.cancel()
+	}
+	return c.FS.Open(name)
+}
+
+func TestCheckPackContext(t *testing.T) {
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, fixtureSchemaDir(t, "damage_type"))
+	files := fstest.MapFS{
+		"data/test/damage_type/a.json": {Data: []byte(`{"message_id": "a", "exhaustion": 0.1, "scaling": "never"}`)},
+		"data/test/damage_type/b.json": {Data: []byte(`{"message_id": "b", "exhaustion": 0.1, "scaling": "never"}`)},
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := validator.CheckPack(cancelled, "pack", files, PackOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled context to stop loading the pack, got %v", err)
+	}
+	if _, err := validator.CheckDocumentContext(cancelled, "data/test/damage_type/a.json", "a.json", files["data/test/damage_type/a.json"].Data); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled context to stop checking a document, got %v", err)
+	}
+
+	// Cancelled halfway, while reading the first file
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	halfway := cancellingFS{files, cancel, "data/test/damage_type/a.json"}
+	if findings, err := validator.CheckPack(ctx, "pack", halfway, PackOptions{}); !errors.Is(err, context.Canceled) || findings != nil {
+		t.Errorf("Expected the check to stop once cancelled, got %v, %v", findings, err)
+	}
+
+	findings, err := validator.CheckPack(context.Background(), "pack", files, PackOptions{})
+	if err != nil || len(findings) != 0 {
+		t.Errorf("Expected the pack to pass without cancellation, got %v, %v", findings, err)
+	}
+}
+
+func TestContextFile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	files := newFileSystem("pack", fstest.MapFS{"a.json": {Data: []byte("{}")}}).withContext(ctx)
+	file, err := files.Open(filepath.Join("pack", "a.json"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer file.Close()
+
+	cancel()
+	if _, err := file.Read(make([]byte, 2)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected reading an open file to fail once cancelled, got %v", err)
+	}
+	if _, err := files.ReadFile(filepath.Join("pack", "a.json")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected ReadFile to fail once cancelled, got %v", err)
+	}
+	if err := files.WalkDir("pack", func(string, fs.DirEntry, error) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected WalkDir to fail once cancelled, got %v", err)
+	}
+}
+
+func TestValidatePack(t *testing.T) {
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, fixtureSchemaDir(t, "damage_type"))
+	files := fstest.MapFS{
+		"data/test/damage_type/a.json": {Data: []byte(`{"message_id": "a", "scaling": "never"}`)},
+		"data/test/damage_type/b.json": {Data: []byte(`{"message_id": "b", "exhaustion": -1, "scaling": "never"}`)},
+		"data/test/damage_type/C.json": {Data: []byte(`{"message_id": "c", "exhaustion": 0.1, "scaling": "never"}`)},
+	}
+	expected, err := validator.CheckPack(context.Background(), "pack", files, PackOptions{})
+	if err != nil || len(expected) != 3 {
+		t.Fatalf("Expected three findings, got %v, %v", expected, err)
+	}
+
+	stream, wait, err := validator.ValidatePack(context.Background(), "pack", files, PackOptions{})
+	if err != nil {
+		t.Fatalf("ValidatePack failed: %v", err)
+	}
+	var streamed []string
+	for finding := range stream {
+		streamed = append(streamed, finding.String())
+	}
+	if err := wait(); err != nil {
+		t.Errorf("Expected the check to end without error, got %v", err)
+	}
+	var collected []string
+	for _, finding := range expected {
+		collected = append(collected, finding.String())
+	}
+	if strings.Join(streamed, "\n") != strings.Join(collected, "\n") {
+		t.Errorf("Expected the streamed findings to be\n%s\ngot\n%s", strings.Join(collected, "\n"), strings.Join(streamed, "\n"))
+	}
+
+	// Without fsys the pack is read from root on disk
+	onDisk := make(map[string][]byte)
+	for name, file := range files {
+		onDisk[name] = file.Data
+	}
+	stream, _, err = validator.ValidatePack(context.Background(), writePackFiles(t, onDisk), nil, PackOptions{})
+	if err != nil {
+		t.Fatalf("ValidatePack failed: %v", err)
+	}
+	streamed = streamed[:0]
+	for finding := range stream {
+		streamed = append(streamed, finding.String())
+	}
+	if strings.Join(streamed, "\n") != strings.Join(collected, "\n") {
+		t.Errorf("Expected the findings of the pack on disk to be\n%s\ngot\n%s", strings.Join(collected, "\n"), strings.Join(streamed, "\n"))
+	}
+
+	if _, _, err := validator.ValidatePack(context.Background(), "pack", fstest.MapFS{}, PackOptions{}); err == nil {
+		t.Error("Expected a pack without data directory to fail right away")
+	}
+
+	// A reader giving up cancels the check, which closes the channel
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, wait, err = validator.ValidatePack(ctx, "pack", files, PackOptions{})
+	if err != nil {
+		t.Fatalf("ValidatePack failed: %v", err)
+	}
+	cancel()
+	for range stream {
+	}
+	if ctx.Err() == nil {
+		t.Error("Expected the context to tell the check ended early")
+	}
+
+	// The error ending a check early is returned by wait
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	halfway := cancellingFS{files, cancel, "data/test/damage_type/a.json"}
+	if _, wait, err = validator.ValidatePack(ctx, "pack", halfway, PackOptions{}); err != nil {
+		t.Fatalf("ValidatePack failed: %v", err)
+	}
+	if err := wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected wait to return the error ending the check, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package mcheck
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Severity indicates how serious a finding is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info" // a note, like a value that could only be partly checked
+)
+
+// Finding is a single problem found while checking a datapack
+type Finding struct {
+	File     string   `json:"file"`           // pack-relative path of the file the finding is about
+	Path     []string `json:"path,omitempty"` // path to the offending value inside the file, if any
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Rule     string   `json:"rule,omitempty"`    // rule id, like RuleMissingReference
+	Schema   string   `json:"schema,omitempty"`  // schema type the offending value is declared by, if known
+	Source   string   `json:"source,omitempty"`  // schema file and line of the declaration, like worldgen/biome.mcdoc:42
+	Version  string   `json:"version,omitempty"` // target version, when files are checked for several
+
+	// format and args are the message before its arguments were filled in,
+	// which a Catalog translates
+	format string
+	args   []interface{}
+}
+
+func (f Finding) String() string {
+	result := f.File + ": "
+	if f.Version != "" {
+		result = fmt.Sprintf("%s (%s): ", f.File, f.Version)
+	}
+	switch f.Severity {
+	case SeverityWarning:
+		result += "warning: "
+	case SeverityInfo:
+		result += "note: "
+	}
+	result += f.text()
+	if f.Rule != "" {
+		result += fmt.Sprintf(" [%s %s]", f.Rule, ruleName(f.Rule))
+	}
+	return result
+}
+
+// text describes the finding without its file and rule, like
+// "at pools.[0]: required field 'rolls' is missing"
+func (f Finding) text() string {
+	if len(f.Path) == 0 {
+		return f.Message
+	}
+	return fmt.Sprintf("at %s: %s", strings.Join(f.Path, "."), f.Message)
+}
+
+// FindingsError is the error of a validation that failed, holding the
+// findings that failed it
+type FindingsError []Finding
+
+func (e FindingsError) Error() string {
+	messages := make([]string, len(e))
+	for i, finding := range e {
+		messages[i] = finding.text()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// findingsError returns the error findings as a FindingsError, or nil if
+// validation passed
+func findingsError(findings []Finding) error {
+	var failed FindingsError
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			failed = append(failed, finding)
+		}
+	}
+	if failed == nil {
+		return nil
+	}
+	return failed
+}
+
+// firstError returns the first finding that fails validation
+func firstError(findings []Finding) (Finding, bool) {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return finding, true
+		}
+	}
+	return Finding{}, false
+}
+
+// findingFromError converts an error about a whole file into a finding
+func findingFromError(file string, err error) Finding {
+	finding := Finding{File: file, Severity: SeverityError, Message: err.Error()}
+	var ruleErr RuleError
+	if errors.As(err, &ruleErr) {
+		finding.Rule = ruleErr.Rule
+	}
+	return finding
+}
+
+// SchemaProblem reports whether a finding is about the schemas rather than
+// the file: a schema that is missing, cannot be parsed or refers to types it
+// does not define says nothing about whether the file is valid, and neither
+// does a value that could not be fully checked
+func (f Finding) SchemaProblem() bool {
+	return f.Rule == RuleSchemaNotFound || f.Rule == RuleSchemaError || f.Rule == RulePartiallyValidated
+}
+
+// splitFindings separates the findings about files from schema problems
+func splitFindings(findings []Finding) (fileFindings, schemaProblems []Finding) {
+	for _, finding := range findings {
+		if finding.SchemaProblem() {
+			schemaProblems = append(schemaProblems, finding)
+		} else {
+			fileFindings = append(fileFindings, finding)
+		}
+	}
+	return fileFindings, schemaProblems
+}
+
+// hasErrors reports whether any finding has error severity
+func hasErrors(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,313 @@
+package mcheck
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fixtureSchemaDir copies schemas from tests/mcdocs into a temporary
+// vanilla-mcdoc layout. Each schema is a path below java/data, like
+// "damage_type" or "variants/wolf", whose base name is the file in tests/mcdocs.
+// Schemas outside java/data are given relative to it, like ../util/block_state.
+func fixtureSchemaDir(t *testing.T, schemas ...string) string {
+	t.Helper()
+
+	schemaDir := filepath.Join(t.TempDir(), "vanilla-mcdoc")
+	for _, schemaName := range schemas {
+		schema, err := os.ReadFile(filepath.Join("tests", "mcdocs", path.Base(schemaName)+".mcdoc"))
+		if err != nil {
+			t.Fatalf("Failed to read %s schema: %v", schemaName, err)
+		}
+		schemaPath := filepath.Join(schemaDir, "java", "data", filepath.FromSlash(schemaName)+".mcdoc")
+		if err := os.MkdirAll(filepath.Dir(schemaPath), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(schemaPath, schema, 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", schemaPath, err)
+		}
+	}
+	return schemaDir
+}
+
+// registryFixtures lists the registries with fixtures in tests/good/data and
+// tests/bad/data, the schema in tests/mcdocs describing them and the version
+// to validate them at. Bad lists the expected error of each bad fixture.
+var registryFixtures = []struct {
+	resourceType string
+	schema       string
+	version      Version
+	bad          map[string]string
+}{
+	{"damage_type", "damage_type", Version{1, 20, 1}, map[string]string{
+		"missing_message_id.json":         "required field 'message_id' is missing; required by damage_type.mcdoc:7 [MCHECK002",
+		"negative_exhaustion.json":        "at exhaustion: value -1 must be greater than or equal to 0",
+		"unknown_death_message_type.json": `at death_message_type: expected one of "default", "fall_variants", "intentional_game_design", got "dramatic"`,
+		"unknown_effects.json":            `at effects: expected one of "hurt", "thorns", "drowning", "burning", "poking", "freezing", got "shivering"`,
+		"unknown_scaling.json":            `at scaling: expected one of "never", "always", "when_caused_by_living_non_player", got "sometimes"`,
+	}},
+	{"chat_type", "chat_type", Version{1, 20, 1}, map[string]string{
+		"missing_translation_key.json": "at chat: required field 'translation_key' is missing",
+		"overlay.json":                 "unexpected field 'overlay'",
+		"team_name_parameter.json":     `at chat.parameters.[0]: expected one of "sender", "content", "target", got "team_name"`,
+	}},
+	{"trim_pattern", "trim", Version{1, 20, 2}, map[string]string{
+		"decal_not_boolean.json":     "at decal: expected boolean, got string",
+		"missing_template_item.json": "required field 'template_item' is missing",
+	}},
+	{"trim_material", "trim", Version{1, 20, 1}, map[string]string{
+		"item_model_index_range.json": "at item_model_index: value 1.5 must be less than or equal to 1",
+		"unknown_armor_material.json": "at override_armor_materials: unexpected key 'copper', keys must be #[id] ArmorMaterial: expected one of",
+	}},
+	{"wolf_variant", "variants/wolf", Version{1, 20, 6}, map[string]string{
+		"assets.json":                "unexpected field 'assets'",
+		"missing_angry_texture.json": "required field 'angry_texture' is missing",
+	}},
+	{"painting_variant", "painting", Version{1, 21, 0}, map[string]string{
+		"fractional_height.json": "at height: expected integer, got float",
+		"too_wide.json":          "at width: value 17 must be less than or equal to 16",
+	}},
+	{"jukebox_song", "jukebox_song", Version{1, 21, 0}, map[string]string{
+		"comparator_output_range.json": "at comparator_output: value 16 must be less than or equal to 15",
+		"zero_length.json":             "at length_in_seconds: value 0 must be greater than 0",
+	}},
+	{"banner_pattern", "banner_pattern", Version{1, 20, 5}, map[string]string{
+		"missing_translation_key.json": "required field 'translation_key' is missing",
+		"numeric_asset_id.json":        "at asset_id: expected string",
+	}},
+	{"loot_table", "loot_table", Version{1, 21, 0}, map[string]string{
+		"child_weight.json":    "at pools.[0].entries.[0].children.[0].weight: value 0 must be greater than or equal to 1",
+		"loot_table_name.json": "required field 'value' is missing",
+		"tag_expand.json":      "at pools.[0].entries.[0].expand: expected boolean, got string",
+		"uniform_rolls.json":   "at pools.[0].rolls",
+	}},
+}
+
+// TestRegistryFixtures validates the good and bad fixtures of registries
+// whose schemas live in tests/mcdocs. Every good fixture must pass, and every
+// bad fixture must fail with the listed error. Schemas shared by several
+// registries are found through the dispatch they declare.
+func TestRegistryFixtures(t *testing.T) {
+	for _, tt := range registryFixtures {
+		validator := NewPEGMCDocValidator(tt.version, fixtureSchemaDir(t, tt.schema))
+
+		good, err := filepath.Glob(filepath.Join("tests", "good", "data", tt.resourceType, "*.json"))
+		if err != nil || len(good) == 0 {
+			t.Fatalf("No good %s fixtures found: %v", tt.resourceType, err)
+		}
+		for _, path := range good {
+			t.Run(path, func(t *testing.T) {
+				if err := validator.ValidateJSON(path); err != nil {
+					t.Errorf("Expected valid, got %v", err)
+				}
+			})
+		}
+
+		bad, err := filepath.Glob(filepath.Join("tests", "bad", "data", tt.resourceType, "*.json"))
+		if err != nil {
+			t.Fatalf("Failed to list bad %s fixtures: %v", tt.resourceType, err)
+		}
+		if len(bad) != len(tt.bad) {
+			t.Errorf("Expected %d bad %s fixtures, found %d", len(tt.bad), tt.resourceType, len(bad))
+		}
+		for _, path := range bad {
+			t.Run(path, func(t *testing.T) {
+				expected, ok := tt.bad[filepath.Base(path)]
+				if !ok {
+					t.Fatalf("No expected error listed for %s", path)
+				}
+				findings := validator.CheckFile(path, path)
+				if finding, ok := firstError(findings); !ok || !strings.Contains(finding.String(), expected) {
+					t.Errorf("Expected error containing %q, got %v", expected, findings)
+				}
+			})
+		}
+	}
+}
+
+// TestChatTypeVersions checks that the version-gated union alternatives and
+// spreads of chat_type select the shape of the target version
+func TestChatTypeVersions(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t, "chat_type")
+
+	tests := []struct {
+		name     string
+		version  Version
+		document string
+		error    string
+	}{
+		{"decoration without style", Version{1, 20, 1},
+			`{"chat": {"translation_key": "chat.type.text", "parameters": ["sender", "content"]}}`, ""},
+		{"decoration in 1.19", Version{1, 19, 0},
+			`{"chat": {"translation_key": "chat.type.text", "parameters": ["sender", "content"]}}`,
+			"at chat: unexpected field 'parameters'"},
+		{"text display in 1.19", Version{1, 19, 0},
+			`{"chat": {"decoration": {"translation_key": "chat.type.text", "parameters": ["team_name"], "style": {}}}}`, ""},
+		{"text display style required in 1.19", Version{1, 19, 0},
+			`{"chat": {"decoration": {"translation_key": "chat.type.text", "parameters": ["sender"]}}}`,
+			"at chat.decoration: required field 'style' is missing"},
+		{"narration priority in 1.19", Version{1, 19, 0},
+			`{"narration": {"priority": "loud"}}`,
+			`at narration.priority: expected one of "chat", "system", got "loud"`},
+		{"text display in 1.20", Version{1, 20, 1},
+			`{"chat": {"decoration": {"translation_key": "chat.type.text", "parameters": ["sender"]}}}`,
+			"at chat: required field 'translation_key' is missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonPath := filepath.Join(t.TempDir(), "data", "test", "chat_type", "example.json")
+			if err := os.MkdirAll(filepath.Dir(jsonPath), 0o755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(jsonPath, []byte(tt.document), 0o644); err != nil {
+				t.Fatalf("Failed to write %s: %v", jsonPath, err)
+			}
+			err := NewPEGMCDocValidator(tt.version, schemaDir).ValidateJSON(jsonPath)
+			switch {
+			case tt.error == "" && err != nil:
+				t.Errorf("Expected valid, got %v", err)
+			case tt.error != "" && (err == nil || !strings.Contains(err.Error(), tt.error)):
+				t.Errorf("Expected error containing %q, got %v", tt.error, err)
+			}
+		})
+	}
+}
+
+// TestWorldPresetFixtures validates the world preset fixtures, whose schemas
+// import the dimension, chunk generator and biome source schemas, and checks
+// that both preset types are rejected before 1.19
+func TestWorldPresetFixtures(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t, "worldgen/world_preset", "worldgen/dimension", "worldgen/dimension/chunk_generator",
+		"worldgen/dimension/biome_source", "worldgen/noise_settings", "worldgen/structure_set")
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, schemaDir)
+
+	bad := map[string]string{
+		"dimension_tag.json":     "at dimensions: unexpected key '#minecraft:overworld'",
+		"missing_generator.json": "at dimensions.minecraft:overworld: required field 'generator' is missing",
+		"no_overworld.json":      "at dimensions: world preset has no minecraft:overworld dimension, which the game requires [MCHECK057 world-preset]",
+		"tall_layer.json":        "at settings.layers.[0].height: value 5000 must be less than or equal to 4096",
+	}
+	for _, resourceType := range []string{"world_preset", "flat_level_generator_preset"} {
+		good, _ := filepath.Glob(filepath.Join("tests", "good", "data", "worldgen", resourceType, "*.json"))
+		if len(good) == 0 {
+			t.Fatalf("No good %s fixtures found", resourceType)
+		}
+		for _, path := range good {
+			t.Run(path, func(t *testing.T) {
+				if findings := validator.CheckFile(path, path); len(findings) > 0 {
+					t.Errorf("Expected valid, got %v", findings)
+				}
+
+				old := NewPEGMCDocValidator(Version{1, 18, 2}, schemaDir)
+				expected := "worldgen/" + resourceType + " files require Minecraft 1.19 or later, target is 1.18.2"
+				if err := old.ValidateJSON(path); err == nil || !strings.Contains(err.Error(), expected) {
+					t.Errorf("Expected error containing %q, got %v", expected, err)
+				}
+			})
+		}
+
+		badFiles, _ := filepath.Glob(filepath.Join("tests", "bad", "data", "worldgen", resourceType, "*.json"))
+		for _, path := range badFiles {
+			t.Run(path, func(t *testing.T) {
+				expected, ok := bad[filepath.Base(path)]
+				if !ok {
+					t.Fatalf("No expected error listed for %s", path)
+				}
+				findings := validator.CheckFile(path, path)
+				if finding, ok := firstError(findings); !ok || !strings.Contains(finding.String(), expected) {
+					t.Errorf("Expected error containing %q, got %v", expected, findings)
+				}
+			})
+		}
+	}
+}
+
+// featureFixtures lists the worldgen registries that dispatch their config or
+// elements on a type, with the dispatcher and the expected error of each
+// bad fixture. Every type the schema dispatches to needs a good fixture named
+// after it, so that a regression in any single type is caught.
+var featureFixtures = []struct {
+	resourceType string
+	dispatcher   string
+	bad          map[string]string
+}{
+	{"configured_feature", "minecraft:feature_config", map[string]string{
+		"disk_radius.json":           "at config.radius",
+		"ore_too_large.json":         "at config.size: value 80 must be less than or equal to 64",
+		"patch_without_feature.json": "at config: required field 'feature' is missing",
+		"tree_bad_placer.json":       "at config.trunk_placer.base_height: value 40 must be less than or equal to 32",
+	}},
+	{"configured_carver", "minecraft:carver_config", map[string]string{
+		"canyon_missing_shape.json":  "at config: required field 'shape' is missing",
+		"floor_level_range.json":     "at config.floor_level",
+		"probability_above_one.json": "at config.probability: value 1.5 must be less than or equal to 1",
+	}},
+	{"template_pool", "minecraft:template_pool_element", map[string]string{
+		"flat_projection.json":    `at elements.[0].element.projection: expected one of "rigid", "terrain_matching", got "flat"`,
+		"fractional_weight.json":  "at elements.[0].weight: expected integer, got float",
+		"heavy_weight.json":       "at elements.[0].weight: value 151 must be less than or equal to 150",
+		"missing_processors.json": "at elements.[0].element: required field 'processors' is missing",
+		"zero_weight.json":        "at elements.[0].weight: value 0 must be greater than or equal to 1",
+	}},
+	{"processor_list", "minecraft:template_processor", map[string]string{
+		"capped_negative_limit.json": "at processors.[0].limit: value -1 must be greater than or equal to 0",
+		"ignore_bad_axis.json":       `at processors.[0].blocks.[0].Properties.axis: expected one of "x", "y", "z", got "w"`,
+		"linear_chance.json":         "at processors.[0].rules.[0].position_predicate.max_chance: value 2 must be less than or equal to 1",
+		"output_state_property.json": "at processors.[0].rules.[0].output_state.Properties: unexpected field 'facing'",
+		"stairs_shape.json":          `at [0].rules.[0].input_predicate.block_state.Properties.shape: expected one of "straight", "inner_left", "inner_right", "outer_left", "outer_right", got "corner"`,
+	}},
+}
+
+func TestFeatureFixtures(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t, "worldgen", "worldgen/carver", "worldgen/feature", "worldgen/feature/tree",
+		"worldgen/feature/block_state_provider", "worldgen/feature/block_predicate", "worldgen/feature/placement",
+		"worldgen/processor_list", "worldgen/template_pool", "worldgen/dimension/biome_source", "../util/block_state")
+	validator := NewPEGMCDocValidator(Version{1, 21, 5}, schemaDir)
+
+	for _, tt := range featureFixtures {
+		converter, _, _, err := validator.loadResourceType("worldgen/" + tt.resourceType)
+		if err != nil {
+			t.Fatalf("Failed to load worldgen/%s: %v", tt.resourceType, err)
+		}
+		ctx := validator.newContext(converter)
+		types := converter.Dispatches()[tt.dispatcher]
+		if len(types) == 0 {
+			t.Fatalf("No %s cases were converted", tt.dispatcher)
+		}
+		for key, config := range types {
+			if !config.AppliesForVersion(ctx) {
+				continue
+			}
+			path := filepath.Join("tests", "good", "data", "worldgen", tt.resourceType, key+".json")
+			t.Run(path, func(t *testing.T) {
+				if _, err := os.Stat(path); err != nil {
+					t.Fatalf("No fixture for %s %s", tt.dispatcher, key)
+				}
+				findings := validator.CheckFile(path, path)
+				if finding, ok := firstError(findings); ok {
+					t.Errorf("Expected valid, got %v", finding)
+				}
+			})
+		}
+
+		bad, _ := filepath.Glob(filepath.Join("tests", "bad", "data", "worldgen", tt.resourceType, "*.json"))
+		if len(bad) != len(tt.bad) {
+			t.Errorf("Expected %d bad %s fixtures, found %d", len(tt.bad), tt.resourceType, len(bad))
+		}
+		for _, path := range bad {
+			t.Run(path, func(t *testing.T) {
+				expected, ok := tt.bad[filepath.Base(path)]
+				if !ok {
+					t.Fatalf("No expected error listed for %s", path)
+				}
+				findings := validator.CheckFile(path, path)
+				if finding, ok := firstError(findings); !ok || !strings.Contains(finding.String(), expected) {
+					t.Errorf("Expected error containing %q, got %v", expected, findings)
+				}
+			})
+		}
+	}
+}
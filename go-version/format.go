@@ -0,0 +1,452 @@
+package mcheck
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// formatLineWidth is how long a union may get on one line before its
+// alternatives are written one per line
+const formatLineWidth = 80
+
+// FormatMCDoc reprints mcdoc source in canonical form: tab indentation, one
+// field, enum value and attribute per line with trailing commas, long
+// unions split one alternative per line, and a blank line between top level
+// statements other than consecutive uses. file names the source in errors.
+//
+// Formatting must not change what the schema means: the formatted source is
+// parsed again and must describe the same statements and keep every
+// comment. Comments are written back on the lines before the declaration
+// they belong to, or at the end of its last line if that is where they were.
+func FormatMCDoc(source, file string) (string, error) {
+	parser, err := parseMCDocSource(source, file)
+	if err != nil {
+		return "", err
+	}
+	f := &mcdocFormatter{}
+	f.statements(parser.Statements)
+	if len(parser.EndComments) > 0 {
+		if len(parser.Statements) > 0 {
+			f.buf.WriteString("\n")
+		}
+		f.header(parser.EndComments, "", nil, 0)
+	}
+	formatted := f.buf.String()
+
+	reparsed, err := parseMCDocSource(formatted, file)
+	if err != nil {
+		return "", fmt.Errorf("%s: formatting produced source that does not parse: %w", file, err)
+	}
+	if FormatStatementsText(reparsed.Statements) != FormatStatementsText(parser.Statements) {
+		return "", fmt.Errorf("%s: formatting would change the statements of the schema", file)
+	}
+	kept := make(map[string]int)
+	for _, comment := range sourceComments(reparsed) {
+		kept[comment.text]++
+	}
+	for _, comment := range sourceComments(parser) {
+		if kept[comment.text] == 0 {
+			return "", fmt.Errorf("%s:%d: formatting would drop the comment %q", file, comment.line, comment.text)
+		}
+		kept[comment.text]--
+	}
+	return formatted, nil
+}
+
+// FormatFiles formats the schema files at paths, and the .mcdoc files in
+// directories among them. It prints the formatted source to out, or with
+// list the names of the files whose formatting differs, and with write
+// rewrites those files in place. Files that cannot be formatted are
+// reported and skipped; the error returned counts them.
+func FormatFiles(paths []string, write, list bool, out, errOut io.Writer) error {
+	failed := 0
+	formatFile := func(file string) {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+			failed++
+			return
+		}
+		formatted, err := FormatMCDoc(string(content), file)
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+			failed++
+			return
+		}
+		changed := formatted != string(content)
+		if list && changed {
+			fmt.Fprintln(out, file)
+		}
+		if write && changed {
+			if err := os.WriteFile(file, []byte(formatted), 0644); err != nil {
+				fmt.Fprintln(errOut, err)
+				failed++
+			}
+		}
+		if !list && !write {
+			fmt.Fprint(out, formatted)
+		}
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+			failed++
+			continue
+		}
+		if !info.IsDir() {
+			formatFile(path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && strings.HasSuffix(file, ".mcdoc") {
+				formatFile(file)
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d files could not be formatted", failed)
+	}
+	return nil
+}
+
+// parseMCDocSource parses mcdoc source and builds its statements
+func parseMCDocSource(source, file string) (*MCDocParser, error) {
+	source, err := mcdocText([]byte(source))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	parser := &MCDocParser{Buffer: source, Pretty: true}
+	parser.File = file
+	if err := parser.Init(); err != nil {
+		return nil, fmt.Errorf("failed to initialize parser: %w", err)
+	}
+	if err := parser.Parse(); err != nil {
+		return nil, describeParseError(parser, err, file)
+	}
+	parser.BuildStatements()
+	return parser, nil
+}
+
+// sourceComment is a // or /// comment of a schema, its text without the
+// slashes and surrounding spaces
+type sourceComment struct {
+	text string
+	line int
+}
+
+// sourceComments returns the comments of parsed source, in order
+func sourceComments(parser *MCDocParser) []sourceComment {
+	builder := astBuilder{buffer: parser.buffer}
+	var comments []sourceComment
+	var walk func(node *node32)
+	walk = func(node *node32) {
+		for ; node != nil; node = node.next {
+			if node.pegRule == ruleComment {
+				text := strings.TrimSpace(strings.TrimLeft(builder.text(node), "/"))
+				comments = append(comments, sourceComment{text: text, line: builder.pos(node.begin).Line})
+				continue
+			}
+			walk(node.up)
+		}
+	}
+	walk(parser.AST())
+	return comments
+}
+
+// mcdocFormatter writes statements in canonical form
+type mcdocFormatter struct {
+	buf strings.Builder
+}
+
+func (f *mcdocFormatter) statements(statements []Statement) {
+	for i, stmt := range statements {
+		if i > 0 {
+			_, use := stmt.(UseStatement)
+			_, previousUse := statements[i-1].(UseStatement)
+			if !use || !previousUse {
+				f.buf.WriteString("\n")
+			}
+		}
+		f.statement(stmt)
+	}
+}
+
+func (f *mcdocFormatter) statement(stmt Statement) {
+	switch s := stmt.(type) {
+	case UseStatement:
+		f.header(s.Comments, "", nil, 0)
+		text := "use " + s.Path.String()
+		if s.Alias != "" {
+			text += " as " + s.Alias
+		}
+		f.line(text, s.LineComment)
+	case TypeAliasStatement:
+		f.header(s.Comments, s.Doc, s.Attributes, 0)
+		name := s.Name.Name
+		if len(s.TypeParams) > 0 {
+			name += "<" + strings.Join(s.TypeParams, ", ") + ">"
+		}
+		f.line("type "+name+" = "+f.typeString(s.Type, 0), s.LineComment)
+	case StructStatement:
+		f.header(s.Comments, s.Doc, s.Attributes, 0)
+		f.line("struct "+s.Name.Name+" "+f.fields(s.Struct, 0), s.LineComment)
+	case EnumStatement:
+		f.header(s.Comments, s.Doc, s.Attributes, 0)
+		f.line("enum("+s.Type+") "+s.Name.Name+" "+f.enumValues(s.Values, s.EndComments), s.LineComment)
+	case DispatchStatement:
+		f.header(s.Comments, s.Doc, s.Attributes, 0)
+		keys := make([]string, len(s.Keys))
+		for i, key := range s.Keys {
+			keys[i] = formatIndexKey(key)
+		}
+		path := s.Registry + "[" + strings.Join(keys, ", ") + "]"
+		if len(s.TypeParams) > 0 {
+			path += "<" + strings.Join(s.TypeParams, ", ") + ">"
+		}
+		target := f.typeString(s.Target, 0)
+		if structExpr, ok := s.Target.(StructExpression); ok && structExpr.Name != nil {
+			target = "struct " + structExpr.Name.Name + " " + f.fields(structExpr, 0)
+		}
+		f.line("dispatch "+path+" to "+target, s.LineComment)
+	case InjectStatement:
+		f.header(s.Comments, s.Doc, s.Attributes, 0)
+		if s.Enum {
+			f.line("inject enum("+s.Type+") "+s.Path.String()+" "+f.enumValues(s.Values, s.EndComments), s.LineComment)
+		} else {
+			f.line("inject struct "+s.Path.String()+" "+f.fields(s.Struct, 0), s.LineComment)
+		}
+	}
+}
+
+// line writes the text of a statement followed by its line comment
+func (f *mcdocFormatter) line(text, comment string) {
+	f.buf.WriteString(withLineComment(text, comment) + "\n")
+}
+
+func withLineComment(text, comment string) string {
+	if comment == "" {
+		return text
+	}
+	return text + " " + comment
+}
+
+// header writes the comments, doc comment and attributes of a declaration,
+// each on a line of its own
+func (f *mcdocFormatter) header(comments []string, doc string, attributes []Attribute, depth int) {
+	f.buf.WriteString(formatHeader(comments, doc, attributes, depth))
+}
+
+func formatHeader(comments []string, doc string, attributes []Attribute, depth int) string {
+	var b strings.Builder
+	indent := strings.Repeat("\t", depth)
+	for _, comment := range comments {
+		b.WriteString(indent + comment + "\n")
+	}
+	if doc != "" {
+		for _, line := range strings.Split(doc, "\n") {
+			switch {
+			case line == "":
+				b.WriteString(indent + "///\n")
+			case strings.HasPrefix(line, " "):
+				b.WriteString(indent + "///" + line + "\n")
+			default:
+				b.WriteString(indent + "/// " + line + "\n")
+			}
+		}
+	}
+	for _, attr := range attributes {
+		b.WriteString(indent + formatAttribute(attr) + "\n")
+	}
+	return b.String()
+}
+
+// fields writes the body of a struct, one field per line at depth+1
+func (f *mcdocFormatter) fields(structExpr StructExpression, depth int) string {
+	if len(structExpr.Fields) == 0 && len(structExpr.EndComments) == 0 {
+		return "{}"
+	}
+	indent := strings.Repeat("\t", depth+1)
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, field := range structExpr.Fields {
+		b.WriteString(formatHeader(field.Comments, field.Doc, field.Attributes, depth+1))
+		b.WriteString(indent)
+		switch {
+		case field.Spread:
+			b.WriteString("...")
+		case field.Key != nil:
+			b.WriteString("[" + f.typeString(field.Key, depth+1) + "]")
+		default:
+			b.WriteString(field.Name.Name)
+		}
+		if !field.Spread {
+			if field.Optional {
+				b.WriteString("?")
+			}
+			b.WriteString(": ")
+		}
+		b.WriteString(withLineComment(f.typeString(field.Type, depth+1)+",", field.LineComment) + "\n")
+	}
+	b.WriteString(formatHeader(structExpr.EndComments, "", nil, depth+1))
+	b.WriteString(strings.Repeat("\t", depth) + "}")
+	return b.String()
+}
+
+func (f *mcdocFormatter) enumValues(values []EnumValueExpression, endComments []string) string {
+	if len(values) == 0 && len(endComments) == 0 {
+		return "{}"
+	}
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, value := range values {
+		b.WriteString(formatHeader(value.Comments, value.Doc, value.Attributes, 1))
+		b.WriteString("\t" + withLineComment(value.Name+" = "+formatValue(value.Value)+",", value.LineComment) + "\n")
+	}
+	b.WriteString(formatHeader(endComments, "", nil, 1))
+	b.WriteString("}")
+	return b.String()
+}
+
+// typeString writes a type expression found at an indentation depth, which
+// its struct bodies and split unions are indented from
+func (f *mcdocFormatter) typeString(expr Expression, depth int) string {
+	switch e := expr.(type) {
+	case StructExpression:
+		result := "struct "
+		if e.Name != nil {
+			result += e.Name.Name + " "
+		}
+		return result + f.fields(e, depth)
+	case UnionExpression:
+		alternatives := make([]string, len(e.Alternatives))
+		multiline := false
+		for i, alt := range e.Alternatives {
+			alternatives[i] = f.typeString(alt, depth+1)
+			multiline = multiline || strings.Contains(alternatives[i], "\n")
+		}
+		single := "(" + strings.Join(alternatives, " | ") + ")"
+		if !multiline && depth*4+len(single) <= formatLineWidth {
+			return single
+		}
+		indent := strings.Repeat("\t", depth+1)
+		return "(\n" + indent + strings.Join(alternatives, " |\n"+indent) + " |\n" + strings.Repeat("\t", depth) + ")"
+	case AttributedExpression:
+		var b strings.Builder
+		for _, attr := range e.Attributes {
+			b.WriteString(formatAttribute(attr) + " ")
+		}
+		return b.String() + f.typeString(e.Type, depth)
+	case ArrayExpression:
+		var result string
+		if e.Typed != "" {
+			result = f.typeString(e.Element, depth) + "[]"
+			if _, constrained := e.Element.(ConstrainedExpression); constrained {
+				result = f.typeString(e.Element, depth) + " []"
+			}
+		} else {
+			result = "[" + f.typeString(e.Element, depth) + "]"
+		}
+		if e.Length != nil {
+			result += " @ " + e.Length.String()
+		}
+		return result
+	case ConstrainedExpression:
+		return f.typeString(e.Type, depth) + " @ " + e.Range.String()
+	case GenericExpression:
+		return f.typeString(e.Base, depth) + "<" + f.typeList(e.Args, depth) + ">"
+	case DispatchExpression:
+		return formatDispatch(e, f.typeList(e.Args, depth))
+	}
+	return formatValue(expr)
+}
+
+func (f *mcdocFormatter) typeList(exprs []Expression, depth int) string {
+	types := make([]string, len(exprs))
+	for i, expr := range exprs {
+		types[i] = f.typeString(expr, depth)
+	}
+	return strings.Join(types, ", ")
+}
+
+// formatDispatch writes a dispatcher access like minecraft:resource[[type]],
+// with the type arguments args if there are any
+func formatDispatch(e DispatchExpression, args string) string {
+	result := e.Registry + "[" + formatIndexKey(e.Key) + "]"
+	if e.Dynamic {
+		result = e.Registry + "[[" + formatIndexKey(e.Key) + "]]"
+	}
+	if args != "" {
+		result += "<" + args + ">"
+	}
+	return result
+}
+
+// formatAttribute writes an attribute like #[since="1.17"], #[canonical] or
+// #[id(registry="item", tags=allowed)]
+func formatAttribute(attr Attribute) string {
+	switch value := attr.Value.(type) {
+	case nil:
+		return "#[" + attr.Name + "]"
+	case AttributeTree:
+		var args []string
+		for _, positional := range value.Positional {
+			args = append(args, formatValue(positional))
+		}
+		for _, named := range value.Named {
+			args = append(args, named.Name+"="+formatValue(named.Value))
+		}
+		return "#[" + attr.Name + "(" + strings.Join(args, ", ") + ")]"
+	}
+	return "#[" + attr.Name + "=" + formatValue(attr.Value) + "]"
+}
+
+// formatValue writes literals, references and attribute values
+func formatValue(expr Expression) string {
+	switch e := expr.(type) {
+	case nil:
+		return ""
+	case StringLiteral:
+		return `"` + e.Value + `"`
+	case ArrayLiteral:
+		values := make([]string, len(e.Values))
+		for i, value := range e.Values {
+			values[i] = formatValue(value)
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case DispatchExpression:
+		args := make([]string, len(e.Args))
+		for i, arg := range e.Args {
+			args[i] = (&mcdocFormatter{}).typeString(arg, 0)
+		}
+		return formatDispatch(e, strings.Join(args, ", "))
+	}
+	return expr.String()
+}
+
+// plainIndexKey matches the dispatch keys that need no quotes: identifiers,
+// static keys like %unknown and accessors like %parent.type
+var plainIndexKey = regexp.MustCompile(`^(%?[a-zA-Z_][a-zA-Z0-9_]*)(\.[a-zA-Z_][a-zA-Z0-9_]*)*$`)
+
+// formatIndexKey writes a dispatch key, quoting keys like "minecraft:stone"
+// that are not identifiers
+func formatIndexKey(key string) string {
+	if plainIndexKey.MatchString(key) {
+		return key
+	}
+	return `"` + key + `"`
+}
@@ -0,0 +1,146 @@
+package mcheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatMCDoc(t *testing.T) {
+	source := `use super::Other as O
+use ::java::util::text::Text
+/// A test struct
+#[since="1.17"] struct Test {
+	name: string, #[until="1.18"] count?: int @ 0..<10,
+	items: [O] @ 1.., bits?: byte @ 0..1 [] @ 4,
+	...super::Base,
+	[#[id=(registry="item", tags=allowed)] string]: int,
+	nested: struct { a: minecraft:resource[[type]] },
+	long: (VeryLongTypeNameNumberOne | VeryLongTypeNameNumberTwo | VeryLongTypeNameNumberThree),
+}
+enum(string) Color { Red = "red", #[since="1.20"] Blue = "blue" }
+dispatch minecraft:resource["minecraft:test", %unknown] to Test
+`
+	expected := `use super::Other as O
+use ::java::util::text::Text
+
+/// A test struct
+#[since="1.17"]
+struct Test {
+	name: string,
+	#[until="1.18"]
+	count?: int @ 0..<10,
+	items: [O] @ 1..,
+	bits?: byte @ 0..1 [] @ 4,
+	...super::Base,
+	[#[id(registry="item", tags=allowed)] string]: int,
+	nested: struct {
+		a: minecraft:resource[[type]],
+	},
+	long: (
+		VeryLongTypeNameNumberOne |
+		VeryLongTypeNameNumberTwo |
+		VeryLongTypeNameNumberThree |
+	),
+}
+
+enum(string) Color {
+	Red = "red",
+	#[since="1.20"]
+	Blue = "blue",
+}
+
+dispatch minecraft:resource["minecraft:test", %unknown] to Test
+`
+	formatted, err := FormatMCDoc(source, "test.mcdoc")
+	if err != nil {
+		t.Fatalf("FormatMCDoc failed: %v", err)
+	}
+	if formatted != expected {
+		t.Errorf("Unexpected formatting:\n%s", formatted)
+	}
+
+	again, err := FormatMCDoc(formatted, "test.mcdoc")
+	if err != nil || again != formatted {
+		t.Errorf("Expected formatting to be stable, got %v:\n%s", err, again)
+	}
+}
+
+func TestFormatMCDocKeepsComments(t *testing.T) {
+	source := `// Header
+use super::A // the A
+// before the docs
+/// Docs
+struct Test { // opening
+	// leading
+	name: string, // trailing
+	x: struct {
+		// empty
+	}, ...A,
+	// at the end
+} // after
+enum(string) E { One = "1", // one
+	// last
+}
+// end of file
+`
+	expected := `// Header
+use super::A // the A
+
+// before the docs
+/// Docs
+struct Test {
+	// opening
+	// leading
+	name: string, // trailing
+	x: struct {
+		// empty
+	},
+	...A,
+	// at the end
+} // after
+
+enum(string) E {
+	One = "1", // one
+	// last
+}
+
+// end of file
+`
+	formatted, err := FormatMCDoc(source, "a.mcdoc")
+	if err != nil {
+		t.Fatalf("FormatMCDoc failed: %v", err)
+	}
+	if formatted != expected {
+		t.Errorf("Unexpected formatting:\n%s", formatted)
+	}
+
+	// Doc comments are kept whatever their spacing
+	formatted, err = FormatMCDoc("///Docs\n///\n///  indented\nstruct A {}\n", "a.mcdoc")
+	if err != nil {
+		t.Fatalf("FormatMCDoc failed: %v", err)
+	}
+	if formatted != "/// Docs\n///\n///  indented\nstruct A {}\n" {
+		t.Errorf("Unexpected doc comment %q", formatted)
+	}
+}
+
+// TestFormatSchemaFixtures formats the fixture schemas, which must keep
+// their meaning and be stable once formatted
+func TestFormatSchemaFixtures(t *testing.T) {
+	files, _ := filepath.Glob("tests/mcdocs/*.mcdoc")
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		formatted, err := FormatMCDoc(string(content), file)
+		if err != nil {
+			t.Errorf("%v", err)
+			continue
+		}
+		if again, err := FormatMCDoc(formatted, file); err != nil || again != formatted {
+			t.Errorf("%s: formatting is not stable: %v", file, err)
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package mcheck
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// maxSampleDepth is the nesting beyond which samples stay small: optional
+// fields are left out, lists get their minimum length and unions prefer
+// alternatives that are not objects or lists, so that recursive types like
+// density functions end
+const maxSampleDepth = 6
+
+// maxSampleAttempts bounds how many samples GenerateSample builds before
+// giving up on a type it cannot satisfy, like strings with a #[regex]
+const maxSampleAttempts = 50
+
+// sampleGenerator builds random values from validators. Values follow the
+// types, ranges, enums, attributes and dispatches of the schema, so that the
+// schema accepts them; a value the schema rejects points at a difference
+// between the generator and the validators.
+type sampleGenerator struct {
+	rand *rand.Rand
+}
+
+func newSampleGenerator(seed int64) *sampleGenerator {
+	return &sampleGenerator{rand: rand.New(rand.NewSource(seed))}
+}
+
+// GenerateSample builds a random document of a resource type that its schema
+// accepts
+func (v *PEGMCDocValidator) GenerateSample(resourceType string, seed int64) (interface{}, error) {
+	converter, mainValidator, _, err := v.loadResourceType(resourceType)
+	if err != nil {
+		return nil, err
+	}
+	ctx := v.newContext(converter)
+
+	generator := newSampleGenerator(seed)
+	var rejected error
+	for attempt := 0; attempt < maxSampleAttempts; attempt++ {
+		sample, ok := generator.generate(mainValidator, ctx, 0)
+		if !ok {
+			continue
+		}
+		if rejected = findingsError(mainValidator.Validate(sample, ctx)); rejected == nil {
+			return sample, nil
+		}
+	}
+	if rejected != nil {
+		return nil, fmt.Errorf("could not generate a valid %s sample: %w", resourceType, rejected)
+	}
+	return nil, fmt.Errorf("could not generate a %s sample", resourceType)
+}
+
+// loadResourceType loads the schema of a resource type, returning its
+// converter, its validator and the schema file
+func (v *PEGMCDocValidator) loadResourceType(resourceType string) (*SchemaConverter, Validator, string, error) {
+	schemaPath, err := v.SchemaPath(resourceType)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if _, err := v.schemas.Stat(schemaPath); err != nil {
+		return nil, nil, "", fmt.Errorf("no schema for %s: %w", resourceType, err)
+	}
+	converter, err := v.convertedSchema(schemaPath)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	mainValidator := converter.MainValidatorFor(resourceType)
+	if mainValidator == nil {
+		return nil, nil, "", fmt.Errorf("%s declares no type for %s", v.schemaName(schemaPath), resourceType)
+	}
+	return converter, mainValidator, schemaPath, nil
+}
+
+// generate builds a random value for validator. It reports false if it
+// cannot, like for a union none of whose alternatives apply.
+func (g *sampleGenerator) generate(validator Validator, ctx *ValidationContext, depth int) (interface{}, bool) {
+	// References can nest without bound, like recursive aliases
+	if validator == nil || depth > 4*maxSampleDepth || !validator.AppliesForVersion(ctx) {
+		return nil, false
+	}
+
+	switch v := validator.(type) {
+	case *PrimitiveValidator:
+		return g.primitive(v.Type), true
+	case *RangeValidator:
+		return g.number(v, false)
+	case *ConstrainedValidator:
+		rv, ok := v.Constraint.(*RangeValidator)
+		if !ok {
+			return g.generate(v.InnerValidator, ctx, depth)
+		}
+		if primitive, ok := concreteValidator(v.InnerValidator, nil, ctx).(*PrimitiveValidator); ok {
+			switch primitive.Type {
+			case "string":
+				length, ok := g.number(rv, true)
+				if !ok {
+					return nil, false
+				}
+				return g.word(int(length)), true
+			case "byte", "short", "int", "long":
+				return g.number(rv, true)
+			case "float", "double":
+				return g.number(rv, false)
+			}
+		}
+		// Other constrained types, like enums of numbers, are kept if they
+		// are in range
+		value, ok := g.generate(v.InnerValidator, ctx, depth)
+		return value, ok && !hasErrors(rv.Validate(value, ctx))
+	case *EnumValidator:
+		var values []interface{}
+		for _, value := range v.Values {
+			if value.AppliesForVersion(ctx) {
+				values = append(values, value.Value)
+			}
+		}
+		if len(values) == 0 {
+			return nil, false
+		}
+		return values[g.rand.Intn(len(values))], true
+	case *LiteralValidator:
+		return v.Value, true
+	case *UnionValidator:
+		return g.union(v, ctx, depth)
+	case *ReferenceValidator:
+		return g.generate(ctx.Definitions[v.TypeName], ctx, depth+1)
+	case *AttributedValidator:
+		return g.attributed(v, ctx, depth)
+	case *DispatchValidator:
+		target := g.dispatchCase(v, ctx)
+		if target == nil {
+			// Without cases the dispatch accepts any value
+			return map[string]interface{}{}, true
+		}
+		return g.generate(target, ctx, depth+1)
+	case *ArrayValidator:
+		return g.array(v, ctx, depth)
+	case *StructValidator:
+		obj := make(map[string]interface{})
+		if !g.fields(v, obj, ctx, ctx.WithParent(obj), depth) {
+			return nil, false
+		}
+		return obj, true
+	case *BasicStructValidator:
+		return map[string]interface{}{}, true
+	}
+	return nil, false
+}
+
+func (g *sampleGenerator) primitive(primitive string) interface{} {
+	switch primitive {
+	case "string":
+		return g.word(3 + g.rand.Intn(6))
+	case "byte", "short", "int", "long", "any", "unsafe":
+		return float64(g.rand.Intn(201) - 100)
+	case "float", "double":
+		return math.Round(g.rand.Float64()*2000-1000) / 10
+	case "boolean":
+		return g.rand.Intn(2) == 0
+	}
+	return nil
+}
+
+// word returns a random lowercase word of length letters
+func (g *sampleGenerator) word(length int) string {
+	letters := make([]byte, length)
+	for i := range letters {
+		letters[i] = byte('a' + g.rand.Intn(26))
+	}
+	return string(letters)
+}
+
+// number returns a random number within a range. Unbounded sides reach 1000
+// past the other bound, or past 0. It reports false for empty ranges.
+func (g *sampleGenerator) number(rv *RangeValidator, integer bool) (float64, bool) {
+	low, high := -1000.0, 1000.0
+	switch {
+	case rv.Min != nil && rv.Max != nil:
+		low, high = *rv.Min, *rv.Max
+	case rv.Min != nil:
+		low, high = *rv.Min, *rv.Min+1000
+	case rv.Max != nil:
+		low, high = *rv.Max-1000, *rv.Max
+	}
+
+	if integer {
+		first, last := math.Ceil(low), math.Floor(high)
+		if rv.Min != nil && rv.MinExclusive && first == low {
+			first++
+		}
+		if rv.Max != nil && rv.MaxExclusive && last == high {
+			last--
+		}
+		if first > last {
+			return 0, false
+		}
+		return first + float64(g.rand.Int63n(int64(last-first)+1)), true
+	}
+
+	if low > high || (low == high && (rv.MinExclusive || rv.MaxExclusive)) {
+		return 0, false
+	}
+	value := low + g.rand.Float64()*(high-low)
+	// Prefer short numbers where they stay in range
+	if rounded := math.Round(value*1000) / 1000; !hasErrors(rv.Validate(rounded, &ValidationContext{})) {
+		return rounded, true
+	}
+	if hasErrors(rv.Validate(value, &ValidationContext{})) {
+		value = (low + high) / 2
+	}
+	return value, true
+}
+
+// union generates one of the alternatives that apply, in random order. Deep
+// in a sample, alternatives that are not objects or lists come first.
+func (g *sampleGenerator) union(uv *UnionValidator, ctx *ValidationContext, depth int) (interface{}, bool) {
+	alternatives := append([]Validator(nil), uv.applicable(ctx)...)
+	g.rand.Shuffle(len(alternatives), func(i, j int) {
+		alternatives[i], alternatives[j] = alternatives[j], alternatives[i]
+	})
+	if depth >= maxSampleDepth {
+		sort.SliceStable(alternatives, func(i, j int) bool {
+			return !isCompound(alternatives[i], ctx) && isCompound(alternatives[j], ctx)
+		})
+	}
+
+	for _, alt := range alternatives {
+		if value, ok := g.generate(alt, ctx, depth+1); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// isCompound reports whether a validator accepts objects or lists
+func isCompound(validator Validator, ctx *ValidationContext) bool {
+	switch concreteValidator(validator, nil, ctx).(type) {
+	case *StructValidator, *ArrayValidator, *DispatchValidator, *UnionValidator, nil:
+		return true
+	}
+	return false
+}
+
+// attributed generates values that the attribute checks accept, like UUIDs
+// and colors, and namespaced ids for #[id] strings
+func (g *sampleGenerator) attributed(av *AttributedValidator, ctx *ValidationContext, depth int) (interface{}, bool) {
+	primitive, _ := concreteValidator(av.InnerValidator, nil, ctx).(*PrimitiveValidator)
+	isString := primitive != nil && primitive.Type == "string"
+
+	var value interface{}
+	switch {
+	case hasAttribute(av, "uuid") && isString:
+		value = fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", g.rand.Uint32(), g.rand.Intn(1<<16), g.rand.Intn(1<<16), g.rand.Intn(1<<16), g.rand.Int63n(1<<48))
+	case hasAttribute(av, "uuid"):
+		value = []interface{}{float64(g.rand.Int31()), float64(g.rand.Int31()), float64(g.rand.Int31()), float64(g.rand.Int31())}
+	case hasAttribute(av, "color") && isString:
+		value = fmt.Sprintf("#%06x", g.rand.Intn(1<<24))
+	case hasAttribute(av, "color") && primitive != nil:
+		value = float64(g.rand.Intn(1 << 24))
+	case hasAttribute(av, "regex_pattern") && isString:
+		value = "[a-z]+"
+	case hasAttribute(av, "id") && isString:
+		value = "minecraft:" + g.word(3+g.rand.Intn(6))
+		if av.Args["id"].Get("tags") == "required" {
+			value = "#" + value.(string)
+		}
+	default:
+		var ok bool
+		if value, ok = g.generate(av.InnerValidator, ctx, depth); !ok {
+			return nil, false
+		}
+	}
+
+	if text, ok := value.(string); ok && av.Pattern != nil && !av.Pattern.MatchString(text) {
+		return nil, false
+	}
+	return value, !hasErrors(av.Validate(value, ctx))
+}
+
+func hasAttribute(av *AttributedValidator, name string) bool {
+	_, ok := av.Attributes[name]
+	return ok
+}
+
+// dispatchCase picks the dispatcher case for a value being generated. For a
+// dynamic dispatch on a field of the enclosing object, like [[type]], it picks
+// a random case and sets the field to its key.
+func (g *sampleGenerator) dispatchCase(dv *DispatchValidator, ctx *ValidationContext) Validator {
+	cases := ctx.Dispatches[dv.Registry]
+	if !dv.Dynamic || len(ctx.Parents) == 0 || strings.Contains(dv.Key, ".") || strings.HasPrefix(dv.Key, "%") {
+		return dv.Resolve(nil, ctx)
+	}
+	if key, found := dv.dynamicKey(nil, ctx); found {
+		if target, ok := cases[key]; ok && target.AppliesForVersion(ctx) {
+			return target
+		}
+	}
+
+	var keys []string
+	for key, target := range cases {
+		if !strings.HasPrefix(key, "%") && target.AppliesForVersion(ctx) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return dv.Resolve(nil, ctx)
+	}
+	sort.Strings(keys)
+	key := keys[g.rand.Intn(len(keys))]
+	if parent, ok := ctx.Parents[len(ctx.Parents)-1].(map[string]interface{}); ok {
+		if !strings.Contains(key, ":") {
+			parent[dv.Key] = "minecraft:" + key
+		} else {
+			parent[dv.Key] = key
+		}
+	}
+	return cases[key]
+}
+
+// array generates a list whose length is within the list's length range,
+// with at most three elements more than it requires
+func (g *sampleGenerator) array(av *ArrayValidator, ctx *ValidationContext, depth int) (interface{}, bool) {
+	minimum, maximum := 0, 3
+	if rv := av.LengthConstraint; rv != nil {
+		if rv.Min != nil {
+			minimum = int(math.Ceil(*rv.Min))
+			if rv.MinExclusive && float64(minimum) == *rv.Min {
+				minimum++
+			}
+		}
+		maximum = minimum + 3
+		if rv.Max != nil {
+			limit := int(math.Floor(*rv.Max))
+			if rv.MaxExclusive && float64(limit) == *rv.Max {
+				limit--
+			}
+			if limit < maximum {
+				maximum = limit
+			}
+		}
+	}
+	if maximum < minimum {
+		return nil, false
+	}
+	length := minimum
+	if depth < maxSampleDepth {
+		length += g.rand.Intn(maximum - minimum + 1)
+	}
+
+	elements := make([]interface{}, length)
+	for i := range elements {
+		element, ok := g.generate(av.ElementValidator, ctx.Child(fmt.Sprintf("[%d]", i)), depth+1)
+		if !ok {
+			return nil, false
+		}
+		elements[i] = element
+	}
+	return elements, true
+}
+
+// fields fills in the fields of a struct and the structs it spreads. Fields
+// already present, like the key of a dispatch set by a later field, are kept.
+func (g *sampleGenerator) fields(sv *StructValidator, obj map[string]interface{}, ctx, objCtx *ValidationContext, depth int) bool {
+	for _, field := range sv.Fields {
+		if !field.AppliesForVersion(ctx) {
+			continue
+		}
+		if _, exists := obj[field.Name]; exists {
+			continue
+		}
+		if field.Optional && (depth >= maxSampleDepth || g.rand.Intn(2) == 0) {
+			continue
+		}
+		value, ok := g.generate(field.Validator, objCtx.Child(field.Name), depth+1)
+		if !ok {
+			if field.Optional {
+				continue
+			}
+			return false
+		}
+		obj[field.Name] = value
+	}
+
+	for _, dynamic := range sv.DynamicFields {
+		if !dynamic.AppliesForVersion(ctx) || depth >= maxSampleDepth {
+			continue
+		}
+		for n := g.rand.Intn(3); n > 0; n-- {
+			key, ok := g.generate(dynamic.Key, objCtx, depth+1)
+			name, isString := key.(string)
+			if !ok || !isString {
+				break
+			}
+			if _, exists := obj[name]; exists {
+				continue
+			}
+			value, ok := g.generate(dynamic.Validator, objCtx.Child(name), depth+1)
+			if !ok {
+				break
+			}
+			obj[name] = value
+		}
+	}
+
+	for _, spread := range sv.SpreadFields {
+		if dv, ok := unwrapDispatch(spread, ctx); ok {
+			g.dispatchCase(dv, objCtx)
+		}
+		spreadStruct, ok := resolveSpread(spread, obj, objCtx, depth)
+		if !ok || spreadStruct == nil || !spreadStruct.AppliesForVersion(ctx) {
+			continue
+		}
+		if !g.fields(spreadStruct, obj, ctx, objCtx, depth+1) {
+			return false
+		}
+	}
+	return true
+}
+
+// unwrapDispatch finds the dispatch a spread resolves through, if any
+func unwrapDispatch(validator Validator, ctx *ValidationContext) (*DispatchValidator, bool) {
+	for depth := 0; validator != nil && depth <= maxSpreadDepth; depth++ {
+		switch v := validator.(type) {
+		case *DispatchValidator:
+			return v, true
+		case *ReferenceValidator:
+			validator = ctx.Definitions[v.TypeName]
+		case *AttributedValidator:
+			validator = v.InnerValidator
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
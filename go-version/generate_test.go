@@ -0,0 +1,107 @@
+package mcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// sampleSchemas are the test schemas whose generated documents are checked,
+// with the resource type to generate and the version to generate it for
+var sampleSchemas = []struct {
+	resourceType string
+	schemas      []string
+	version      Version
+}{
+	{"worldgen/biome", []string{"worldgen/biome"}, Version{1, 20, 1}},
+	{"worldgen/biome", []string{"worldgen/biome"}, Version{1, 17, 1}},
+	{"worldgen/density_function", []string{"worldgen/density_function"}, Version{1, 20, 1}},
+	{"worldgen/noise_settings", []string{"worldgen/noise_settings", "worldgen/density_function"}, Version{1, 20, 1}},
+}
+
+// TestGeneratedSamplesValidate is a property test of the schema converter:
+// every document generated from a schema must be accepted by it, after a
+// round trip through JSON and when streamed
+func TestGeneratedSamplesValidate(t *testing.T) {
+	schemas := sampleSchemas
+	for _, fixture := range registryFixtures {
+		schemas = append(schemas, struct {
+			resourceType string
+			schemas      []string
+			version      Version
+		}{fixture.resourceType, []string{fixture.schema}, fixture.version})
+	}
+
+	for _, tt := range schemas {
+		t.Run(tt.resourceType+"@"+tt.version.String(), func(t *testing.T) {
+			validator := NewPEGMCDocValidator(tt.version, fixtureSchemaDir(t, tt.schemas...))
+			converter, mainValidator, _, err := validator.loadResourceType(tt.resourceType)
+			if err != nil {
+				t.Fatalf("Failed to load %s: %v", tt.resourceType, err)
+			}
+			ctx := validator.newContext(converter)
+
+			generator := newSampleGenerator(1)
+			generated := 0
+			for i := 0; i < 100; i++ {
+				sample, ok := generator.generate(mainValidator, ctx, 0)
+				if !ok {
+					continue
+				}
+				generated++
+
+				content, err := json.Marshal(sample)
+				if err != nil {
+					t.Fatalf("Failed to encode sample: %v", err)
+				}
+				decoded, err := decodeJSON(content)
+				if err != nil {
+					t.Fatalf("Failed to decode sample %s: %v", content, err)
+				}
+				if err := findingsError(mainValidator.Validate(decoded, ctx)); err != nil {
+					t.Fatalf("Generated sample was rejected: %v\n%s", err, content)
+				}
+				findings, err := streamJSON(bytes.NewReader(content), mainValidator, ctx)
+				if err != nil || hasErrors(findings) {
+					t.Fatalf("Generated sample was rejected when streamed: %v %v\n%s", err, findings, content)
+				}
+			}
+			if generated == 0 {
+				t.Errorf("No %s samples could be generated", tt.resourceType)
+			}
+		})
+	}
+}
+
+func TestGenerateSample(t *testing.T) {
+	schemaDir, _ := writeTestPack(t, "worldgen/thing", explainTestSchema, `{}`)
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, schemaDir)
+
+	first, err := validator.GenerateSample("worldgen/thing", 7)
+	if err != nil {
+		t.Fatalf("Failed to generate a sample: %v", err)
+	}
+	again, err := validator.GenerateSample("worldgen/thing", 7)
+	if err != nil {
+		t.Fatalf("Failed to generate a sample: %v", err)
+	}
+	firstJSON, _ := json.Marshal(first)
+	againJSON, _ := json.Marshal(again)
+	if !bytes.Equal(firstJSON, againJSON) {
+		t.Errorf("Expected the same seed to give the same sample, got %s and %s", firstJSON, againJSON)
+	}
+
+	// Before 1.19 height does not exist
+	old := NewPEGMCDocValidator(Version{1, 18, 2}, schemaDir)
+	sample, err := old.GenerateSample("worldgen/thing", 7)
+	if err != nil {
+		t.Fatalf("Failed to generate a sample: %v", err)
+	}
+	if _, ok := sample.(map[string]interface{})["height"]; ok {
+		t.Errorf("Expected no height field before 1.19, got %v", sample)
+	}
+
+	if _, err := validator.GenerateSample("worldgen/unknown", 7); err == nil {
+		t.Errorf("Expected an error for a resource type without a schema")
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package mcheck
 
 // Code generated by peg grammar.peg DO NOT EDIT.
 
@@ -21,6 +21,7 @@ const (
 	ruleStart
 	ruleStatement
 	ruleUseStmt
+	ruleUseAlias
 	rulePath
 	rulePathSegments
 	rulePathSegment
@@ -42,6 +43,9 @@ const (
 	ruleDispatchKeyList
 	ruleDispatchKey
 	ruleDispatchTarget
+	ruleInjectStmt
+	ruleInjectStruct
+	ruleInjectEnum
 	ruleSpreadStruct
 	ruleType
 	ruleAttributedType
@@ -125,6 +129,7 @@ var rul3s = [...]string{
 	"Start",
 	"Statement",
 	"UseStmt",
+	"UseAlias",
 	"Path",
 	"PathSegments",
 	"PathSegment",
@@ -146,6 +151,9 @@ var rul3s = [...]string{
 	"DispatchKeyList",
 	"DispatchKey",
 	"DispatchTarget",
+	"InjectStmt",
+	"InjectStruct",
+	"InjectEnum",
 	"SpreadStruct",
 	"Type",
 	"AttributedType",
@@ -338,7 +346,7 @@ type MCDocParser struct {
 
 	Buffer string
 	buffer []rune
-	rules  [101]func() bool
+	rules  [105]func() bool
 	parse  func(rule ...int) error
 	reset  func()
 	Pretty bool
@@ -610,7 +618,7 @@ func (p *MCDocParser) Init(options ...func(*MCDocParser) error) error {
 			position, tokenIndex = position0, tokenIndex0
 			return false
 		},
-		/* 1 Statement <- <(Attribute* _ (UseStmt / TypeAlias / StructDef / EnumDef / DispatchStmt) _)> */
+		/* 1 Statement <- <(Attribute* _ (UseStmt / TypeAlias / StructDef / EnumDef / DispatchStmt / InjectStmt) _)> */
 		func() bool {
 			position5, tokenIndex5 := position, tokenIndex
 			{
@@ -655,6 +663,12 @@ func (p *MCDocParser) Init(options ...func(*MCDocParser) error) error {
 				l13:
 					position, tokenIndex = position9, tokenIndex9
 					if !_rules[ruleDispatchStmt]() {
+						goto l14
+					}
+					goto l9
+				l14:
+					position, tokenIndex = position9, tokenIndex9
+					if !_rules[ruleInjectStmt]() {
 						goto l5
 					}
 				}
@@ -669,3201 +683,3549 @@ func (p *MCDocParser) Init(options ...func(*MCDocParser) error) error {
 			position, tokenIndex = position5, tokenIndex5
 			return false
 		},
-		/* 2 UseStmt <- <('u' 's' 'e' _ Path Action2)> */
+		/* 2 UseStmt <- <('u' 's' 'e' _ Path UseAlias? Action2)> */
 		func() bool {
-			position14, tokenIndex14 := position, tokenIndex
+			position15, tokenIndex15 := position, tokenIndex
 			{
-				position15 := position
+				position16 := position
 				if buffer[position] != rune('u') {
-					goto l14
+					goto l15
 				}
 				position++
 				if buffer[position] != rune('s') {
-					goto l14
+					goto l15
 				}
 				position++
 				if buffer[position] != rune('e') {
-					goto l14
+					goto l15
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l14
+					goto l15
 				}
 				if !_rules[rulePath]() {
-					goto l14
+					goto l15
+				}
+				{
+					position17, tokenIndex17 := position, tokenIndex
+					if !_rules[ruleUseAlias]() {
+						goto l17
+					}
+					goto l18
+				l17:
+					position, tokenIndex = position17, tokenIndex17
 				}
+			l18:
 				if !_rules[ruleAction2]() {
-					goto l14
+					goto l15
+				}
+				add(ruleUseStmt, position16)
+			}
+			return true
+		l15:
+			position, tokenIndex = position15, tokenIndex15
+			return false
+		},
+		/* 3 UseAlias <- <('a' 's' _ Identifier)> */
+		func() bool {
+			position19, tokenIndex19 := position, tokenIndex
+			{
+				position20 := position
+				if buffer[position] != rune('a') {
+					goto l19
 				}
-				add(ruleUseStmt, position15)
+				position++
+				if buffer[position] != rune('s') {
+					goto l19
+				}
+				position++
+				if !_rules[rule_]() {
+					goto l19
+				}
+				if !_rules[ruleIdentifier]() {
+					goto l19
+				}
+				add(ruleUseAlias, position20)
 			}
 			return true
-		l14:
-			position, tokenIndex = position14, tokenIndex14
+		l19:
+			position, tokenIndex = position19, tokenIndex19
 			return false
 		},
-		/* 3 Path <- <((DoubleColon PathSegments Action3) / (PathSegments Action4))> */
+		/* 4 Path <- <((DoubleColon PathSegments Action3) / (PathSegments Action4))> */
 		func() bool {
-			position16, tokenIndex16 := position, tokenIndex
+			position21, tokenIndex21 := position, tokenIndex
 			{
-				position17 := position
+				position22 := position
 				{
-					position18, tokenIndex18 := position, tokenIndex
+					position23, tokenIndex23 := position, tokenIndex
 					if !_rules[ruleDoubleColon]() {
-						goto l19
+						goto l24
 					}
 					if !_rules[rulePathSegments]() {
-						goto l19
+						goto l24
 					}
 					if !_rules[ruleAction3]() {
-						goto l19
+						goto l24
 					}
-					goto l18
-				l19:
-					position, tokenIndex = position18, tokenIndex18
+					goto l23
+				l24:
+					position, tokenIndex = position23, tokenIndex23
 					if !_rules[rulePathSegments]() {
-						goto l16
+						goto l21
 					}
 					if !_rules[ruleAction4]() {
-						goto l16
+						goto l21
 					}
 				}
-			l18:
-				add(rulePath, position17)
+			l23:
+				add(rulePath, position22)
 			}
 			return true
-		l16:
-			position, tokenIndex = position16, tokenIndex16
+		l21:
+			position, tokenIndex = position21, tokenIndex21
 			return false
 		},
-		/* 4 PathSegments <- <(PathSegment (DoubleColon PathSegment)*)> */
+		/* 5 PathSegments <- <(PathSegment (DoubleColon PathSegment)*)> */
 		func() bool {
-			position20, tokenIndex20 := position, tokenIndex
+			position25, tokenIndex25 := position, tokenIndex
 			{
-				position21 := position
+				position26 := position
 				if !_rules[rulePathSegment]() {
-					goto l20
+					goto l25
 				}
-			l22:
+			l27:
 				{
-					position23, tokenIndex23 := position, tokenIndex
+					position28, tokenIndex28 := position, tokenIndex
 					if !_rules[ruleDoubleColon]() {
-						goto l23
+						goto l28
 					}
 					if !_rules[rulePathSegment]() {
-						goto l23
+						goto l28
 					}
-					goto l22
-				l23:
-					position, tokenIndex = position23, tokenIndex23
+					goto l27
+				l28:
+					position, tokenIndex = position28, tokenIndex28
 				}
-				add(rulePathSegments, position21)
+				add(rulePathSegments, position26)
 			}
 			return true
-		l20:
-			position, tokenIndex = position20, tokenIndex20
+		l25:
+			position, tokenIndex = position25, tokenIndex25
 			return false
 		},
-		/* 5 PathSegment <- <(('s' 'u' 'p' 'e' 'r' Action5) / Identifier)> */
+		/* 6 PathSegment <- <(('s' 'u' 'p' 'e' 'r' Action5) / Identifier)> */
 		func() bool {
-			position24, tokenIndex24 := position, tokenIndex
+			position29, tokenIndex29 := position, tokenIndex
 			{
-				position25 := position
+				position30 := position
 				{
-					position26, tokenIndex26 := position, tokenIndex
+					position31, tokenIndex31 := position, tokenIndex
 					if buffer[position] != rune('s') {
-						goto l27
+						goto l32
 					}
 					position++
 					if buffer[position] != rune('u') {
-						goto l27
+						goto l32
 					}
 					position++
 					if buffer[position] != rune('p') {
-						goto l27
+						goto l32
 					}
 					position++
 					if buffer[position] != rune('e') {
-						goto l27
+						goto l32
 					}
 					position++
 					if buffer[position] != rune('r') {
-						goto l27
+						goto l32
 					}
 					position++
 					if !_rules[ruleAction5]() {
-						goto l27
+						goto l32
 					}
-					goto l26
-				l27:
-					position, tokenIndex = position26, tokenIndex26
+					goto l31
+				l32:
+					position, tokenIndex = position31, tokenIndex31
 					if !_rules[ruleIdentifier]() {
-						goto l24
+						goto l29
 					}
 				}
-			l26:
-				add(rulePathSegment, position25)
+			l31:
+				add(rulePathSegment, position30)
 			}
 			return true
-		l24:
-			position, tokenIndex = position24, tokenIndex24
+		l29:
+			position, tokenIndex = position29, tokenIndex29
 			return false
 		},
-		/* 6 TypeAlias <- <('t' 'y' 'p' 'e' _ TypeName _ EQUALS Type)> */
+		/* 7 TypeAlias <- <('t' 'y' 'p' 'e' _ TypeName _ EQUALS Type)> */
 		func() bool {
-			position28, tokenIndex28 := position, tokenIndex
+			position33, tokenIndex33 := position, tokenIndex
 			{
-				position29 := position
+				position34 := position
 				if buffer[position] != rune('t') {
-					goto l28
+					goto l33
 				}
 				position++
 				if buffer[position] != rune('y') {
-					goto l28
+					goto l33
 				}
 				position++
 				if buffer[position] != rune('p') {
-					goto l28
+					goto l33
 				}
 				position++
 				if buffer[position] != rune('e') {
-					goto l28
+					goto l33
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l28
+					goto l33
 				}
 				if !_rules[ruleTypeName]() {
-					goto l28
+					goto l33
 				}
 				if !_rules[rule_]() {
-					goto l28
+					goto l33
 				}
 				if !_rules[ruleEQUALS]() {
-					goto l28
+					goto l33
 				}
 				if !_rules[ruleType]() {
-					goto l28
+					goto l33
 				}
-				add(ruleTypeAlias, position29)
+				add(ruleTypeAlias, position34)
 			}
 			return true
-		l28:
-			position, tokenIndex = position28, tokenIndex28
+		l33:
+			position, tokenIndex = position33, tokenIndex33
 			return false
 		},
-		/* 7 TypeName <- <(GenericType / Identifier)> */
+		/* 8 TypeName <- <(GenericType / Identifier)> */
 		func() bool {
-			position30, tokenIndex30 := position, tokenIndex
+			position35, tokenIndex35 := position, tokenIndex
 			{
-				position31 := position
+				position36 := position
 				{
-					position32, tokenIndex32 := position, tokenIndex
+					position37, tokenIndex37 := position, tokenIndex
 					if !_rules[ruleGenericType]() {
-						goto l33
+						goto l38
 					}
-					goto l32
-				l33:
-					position, tokenIndex = position32, tokenIndex32
+					goto l37
+				l38:
+					position, tokenIndex = position37, tokenIndex37
 					if !_rules[ruleIdentifier]() {
-						goto l30
+						goto l35
 					}
 				}
-			l32:
-				add(ruleTypeName, position31)
+			l37:
+				add(ruleTypeName, position36)
 			}
 			return true
-		l30:
-			position, tokenIndex = position30, tokenIndex30
+		l35:
+			position, tokenIndex = position35, tokenIndex35
 			return false
 		},
-		/* 8 StructDef <- <('s' 't' 'r' 'u' 'c' 't' _ Identifier _ LBRACE Action6 FieldList? RBRACE Action7 Action8)> */
+		/* 9 StructDef <- <('s' 't' 'r' 'u' 'c' 't' _ Identifier _ LBRACE Action6 FieldList? RBRACE Action7 Action8)> */
 		func() bool {
-			position34, tokenIndex34 := position, tokenIndex
+			position39, tokenIndex39 := position, tokenIndex
 			{
-				position35 := position
+				position40 := position
 				if buffer[position] != rune('s') {
-					goto l34
+					goto l39
 				}
 				position++
 				if buffer[position] != rune('t') {
-					goto l34
+					goto l39
 				}
 				position++
 				if buffer[position] != rune('r') {
-					goto l34
+					goto l39
 				}
 				position++
 				if buffer[position] != rune('u') {
-					goto l34
+					goto l39
 				}
 				position++
 				if buffer[position] != rune('c') {
-					goto l34
+					goto l39
 				}
 				position++
 				if buffer[position] != rune('t') {
-					goto l34
+					goto l39
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l34
+					goto l39
 				}
 				if !_rules[ruleIdentifier]() {
-					goto l34
+					goto l39
 				}
 				if !_rules[rule_]() {
-					goto l34
+					goto l39
 				}
 				if !_rules[ruleLBRACE]() {
-					goto l34
+					goto l39
 				}
 				if !_rules[ruleAction6]() {
-					goto l34
+					goto l39
 				}
 				{
-					position36, tokenIndex36 := position, tokenIndex
+					position41, tokenIndex41 := position, tokenIndex
 					if !_rules[ruleFieldList]() {
-						goto l36
+						goto l41
 					}
-					goto l37
-				l36:
-					position, tokenIndex = position36, tokenIndex36
+					goto l42
+				l41:
+					position, tokenIndex = position41, tokenIndex41
 				}
-			l37:
+			l42:
 				if !_rules[ruleRBRACE]() {
-					goto l34
+					goto l39
 				}
 				if !_rules[ruleAction7]() {
-					goto l34
+					goto l39
 				}
 				if !_rules[ruleAction8]() {
-					goto l34
+					goto l39
 				}
-				add(ruleStructDef, position35)
+				add(ruleStructDef, position40)
 			}
 			return true
-		l34:
-			position, tokenIndex = position34, tokenIndex34
+		l39:
+			position, tokenIndex = position39, tokenIndex39
 			return false
 		},
-		/* 9 FieldList <- <(FieldOrSpread (COMMA FieldOrSpread)* COMMA?)> */
+		/* 10 FieldList <- <(FieldOrSpread (COMMA FieldOrSpread)* COMMA?)> */
 		func() bool {
-			position38, tokenIndex38 := position, tokenIndex
+			position43, tokenIndex43 := position, tokenIndex
 			{
-				position39 := position
+				position44 := position
 				if !_rules[ruleFieldOrSpread]() {
-					goto l38
+					goto l43
 				}
-			l40:
+			l45:
 				{
-					position41, tokenIndex41 := position, tokenIndex
+					position46, tokenIndex46 := position, tokenIndex
 					if !_rules[ruleCOMMA]() {
-						goto l41
+						goto l46
 					}
 					if !_rules[ruleFieldOrSpread]() {
-						goto l41
+						goto l46
 					}
-					goto l40
-				l41:
-					position, tokenIndex = position41, tokenIndex41
+					goto l45
+				l46:
+					position, tokenIndex = position46, tokenIndex46
 				}
 				{
-					position42, tokenIndex42 := position, tokenIndex
+					position47, tokenIndex47 := position, tokenIndex
 					if !_rules[ruleCOMMA]() {
-						goto l42
+						goto l47
 					}
-					goto l43
-				l42:
-					position, tokenIndex = position42, tokenIndex42
+					goto l48
+				l47:
+					position, tokenIndex = position47, tokenIndex47
 				}
-			l43:
-				add(ruleFieldList, position39)
+			l48:
+				add(ruleFieldList, position44)
 			}
 			return true
-		l38:
-			position, tokenIndex = position38, tokenIndex38
+		l43:
+			position, tokenIndex = position43, tokenIndex43
 			return false
 		},
-		/* 10 FieldOrSpread <- <(SpreadField / Field)> */
+		/* 11 FieldOrSpread <- <(SpreadField / Field)> */
 		func() bool {
-			position44, tokenIndex44 := position, tokenIndex
+			position49, tokenIndex49 := position, tokenIndex
 			{
-				position45 := position
+				position50 := position
 				{
-					position46, tokenIndex46 := position, tokenIndex
+					position51, tokenIndex51 := position, tokenIndex
 					if !_rules[ruleSpreadField]() {
-						goto l47
+						goto l52
 					}
-					goto l46
-				l47:
-					position, tokenIndex = position46, tokenIndex46
+					goto l51
+				l52:
+					position, tokenIndex = position51, tokenIndex51
 					if !_rules[ruleField]() {
-						goto l44
+						goto l49
 					}
 				}
-			l46:
-				add(ruleFieldOrSpread, position45)
+			l51:
+				add(ruleFieldOrSpread, position50)
 			}
 			return true
-		l44:
-			position, tokenIndex = position44, tokenIndex44
+		l49:
+			position, tokenIndex = position49, tokenIndex49
 			return false
 		},
-		/* 11 Field <- <(Attribute* _ Action9 (ComputedField / NamedField) Action10)> */
+		/* 12 Field <- <(Attribute* _ Action9 (ComputedField / NamedField) Action10)> */
 		func() bool {
-			position48, tokenIndex48 := position, tokenIndex
+			position53, tokenIndex53 := position, tokenIndex
 			{
-				position49 := position
-			l50:
+				position54 := position
+			l55:
 				{
-					position51, tokenIndex51 := position, tokenIndex
+					position56, tokenIndex56 := position, tokenIndex
 					if !_rules[ruleAttribute]() {
-						goto l51
+						goto l56
 					}
-					goto l50
-				l51:
-					position, tokenIndex = position51, tokenIndex51
+					goto l55
+				l56:
+					position, tokenIndex = position56, tokenIndex56
 				}
 				if !_rules[rule_]() {
-					goto l48
+					goto l53
 				}
 				if !_rules[ruleAction9]() {
-					goto l48
+					goto l53
 				}
 				{
-					position52, tokenIndex52 := position, tokenIndex
+					position57, tokenIndex57 := position, tokenIndex
 					if !_rules[ruleComputedField]() {
-						goto l53
+						goto l58
 					}
-					goto l52
-				l53:
-					position, tokenIndex = position52, tokenIndex52
+					goto l57
+				l58:
+					position, tokenIndex = position57, tokenIndex57
 					if !_rules[ruleNamedField]() {
-						goto l48
+						goto l53
 					}
 				}
-			l52:
+			l57:
 				if !_rules[ruleAction10]() {
-					goto l48
+					goto l53
 				}
-				add(ruleField, position49)
+				add(ruleField, position54)
 			}
 			return true
-		l48:
-			position, tokenIndex = position48, tokenIndex48
+		l53:
+			position, tokenIndex = position53, tokenIndex53
 			return false
 		},
-		/* 12 ComputedField <- <(LBRACKET Type RBRACKET QUESTION? COLON Type)> */
+		/* 13 ComputedField <- <(LBRACKET Type RBRACKET QUESTION? COLON Type)> */
 		func() bool {
-			position54, tokenIndex54 := position, tokenIndex
+			position59, tokenIndex59 := position, tokenIndex
 			{
-				position55 := position
+				position60 := position
 				if !_rules[ruleLBRACKET]() {
-					goto l54
+					goto l59
 				}
 				if !_rules[ruleType]() {
-					goto l54
+					goto l59
 				}
 				if !_rules[ruleRBRACKET]() {
-					goto l54
+					goto l59
 				}
 				{
-					position56, tokenIndex56 := position, tokenIndex
+					position61, tokenIndex61 := position, tokenIndex
 					if !_rules[ruleQUESTION]() {
-						goto l56
+						goto l61
 					}
-					goto l57
-				l56:
-					position, tokenIndex = position56, tokenIndex56
+					goto l62
+				l61:
+					position, tokenIndex = position61, tokenIndex61
 				}
-			l57:
+			l62:
 				if !_rules[ruleCOLON]() {
-					goto l54
+					goto l59
 				}
 				if !_rules[ruleType]() {
-					goto l54
+					goto l59
 				}
-				add(ruleComputedField, position55)
+				add(ruleComputedField, position60)
 			}
 			return true
-		l54:
-			position, tokenIndex = position54, tokenIndex54
+		l59:
+			position, tokenIndex = position59, tokenIndex59
 			return false
 		},
-		/* 13 NamedField <- <(FieldName Action11 COLON Type)> */
+		/* 14 NamedField <- <(FieldName Action11 COLON Type)> */
 		func() bool {
-			position58, tokenIndex58 := position, tokenIndex
+			position63, tokenIndex63 := position, tokenIndex
 			{
-				position59 := position
+				position64 := position
 				if !_rules[ruleFieldName]() {
-					goto l58
+					goto l63
 				}
 				if !_rules[ruleAction11]() {
-					goto l58
+					goto l63
 				}
 				if !_rules[ruleCOLON]() {
-					goto l58
+					goto l63
 				}
 				if !_rules[ruleType]() {
-					goto l58
+					goto l63
 				}
-				add(ruleNamedField, position59)
+				add(ruleNamedField, position64)
 			}
 			return true
-		l58:
-			position, tokenIndex = position58, tokenIndex58
+		l63:
+			position, tokenIndex = position63, tokenIndex63
 			return false
 		},
-		/* 14 SpreadField <- <(Attribute* _ SPREAD Type)> */
+		/* 15 SpreadField <- <(Attribute* _ SPREAD Type)> */
 		func() bool {
-			position60, tokenIndex60 := position, tokenIndex
+			position65, tokenIndex65 := position, tokenIndex
 			{
-				position61 := position
-			l62:
+				position66 := position
+			l67:
 				{
-					position63, tokenIndex63 := position, tokenIndex
+					position68, tokenIndex68 := position, tokenIndex
 					if !_rules[ruleAttribute]() {
-						goto l63
+						goto l68
 					}
-					goto l62
-				l63:
-					position, tokenIndex = position63, tokenIndex63
+					goto l67
+				l68:
+					position, tokenIndex = position68, tokenIndex68
 				}
 				if !_rules[rule_]() {
-					goto l60
+					goto l65
 				}
 				if !_rules[ruleSPREAD]() {
-					goto l60
+					goto l65
 				}
 				if !_rules[ruleType]() {
-					goto l60
+					goto l65
 				}
-				add(ruleSpreadField, position61)
+				add(ruleSpreadField, position66)
 			}
 			return true
-		l60:
-			position, tokenIndex = position60, tokenIndex60
+		l65:
+			position, tokenIndex = position65, tokenIndex65
 			return false
 		},
-		/* 15 FieldName <- <(Identifier QUESTION? Action12)> */
+		/* 16 FieldName <- <(Identifier QUESTION? Action12)> */
 		func() bool {
-			position64, tokenIndex64 := position, tokenIndex
+			position69, tokenIndex69 := position, tokenIndex
 			{
-				position65 := position
+				position70 := position
 				if !_rules[ruleIdentifier]() {
-					goto l64
+					goto l69
 				}
 				{
-					position66, tokenIndex66 := position, tokenIndex
+					position71, tokenIndex71 := position, tokenIndex
 					if !_rules[ruleQUESTION]() {
-						goto l66
+						goto l71
 					}
-					goto l67
-				l66:
-					position, tokenIndex = position66, tokenIndex66
+					goto l72
+				l71:
+					position, tokenIndex = position71, tokenIndex71
 				}
-			l67:
+			l72:
 				if !_rules[ruleAction12]() {
-					goto l64
+					goto l69
 				}
-				add(ruleFieldName, position65)
+				add(ruleFieldName, position70)
 			}
 			return true
-		l64:
-			position, tokenIndex = position64, tokenIndex64
+		l69:
+			position, tokenIndex = position69, tokenIndex69
 			return false
 		},
-		/* 16 EnumDef <- <('e' 'n' 'u' 'm' _ LPAREN Type RPAREN Identifier _ LBRACE EnumValueList? RBRACE)> */
+		/* 17 EnumDef <- <('e' 'n' 'u' 'm' _ LPAREN Type RPAREN Identifier _ LBRACE EnumValueList? RBRACE)> */
 		func() bool {
-			position68, tokenIndex68 := position, tokenIndex
+			position73, tokenIndex73 := position, tokenIndex
 			{
-				position69 := position
+				position74 := position
 				if buffer[position] != rune('e') {
-					goto l68
+					goto l73
 				}
 				position++
 				if buffer[position] != rune('n') {
-					goto l68
+					goto l73
 				}
 				position++
 				if buffer[position] != rune('u') {
-					goto l68
+					goto l73
 				}
 				position++
 				if buffer[position] != rune('m') {
-					goto l68
+					goto l73
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l68
+					goto l73
 				}
 				if !_rules[ruleLPAREN]() {
-					goto l68
+					goto l73
 				}
 				if !_rules[ruleType]() {
-					goto l68
+					goto l73
 				}
 				if !_rules[ruleRPAREN]() {
-					goto l68
+					goto l73
 				}
 				if !_rules[ruleIdentifier]() {
-					goto l68
+					goto l73
 				}
 				if !_rules[rule_]() {
-					goto l68
+					goto l73
 				}
 				if !_rules[ruleLBRACE]() {
-					goto l68
+					goto l73
 				}
 				{
-					position70, tokenIndex70 := position, tokenIndex
+					position75, tokenIndex75 := position, tokenIndex
 					if !_rules[ruleEnumValueList]() {
-						goto l70
+						goto l75
 					}
-					goto l71
-				l70:
-					position, tokenIndex = position70, tokenIndex70
+					goto l76
+				l75:
+					position, tokenIndex = position75, tokenIndex75
 				}
-			l71:
+			l76:
 				if !_rules[ruleRBRACE]() {
-					goto l68
+					goto l73
 				}
-				add(ruleEnumDef, position69)
+				add(ruleEnumDef, position74)
 			}
 			return true
-		l68:
-			position, tokenIndex = position68, tokenIndex68
+		l73:
+			position, tokenIndex = position73, tokenIndex73
 			return false
 		},
-		/* 17 EnumValueList <- <(EnumValue (COMMA EnumValue)* COMMA?)> */
+		/* 18 EnumValueList <- <(EnumValue (COMMA EnumValue)* COMMA?)> */
 		func() bool {
-			position72, tokenIndex72 := position, tokenIndex
+			position77, tokenIndex77 := position, tokenIndex
 			{
-				position73 := position
+				position78 := position
 				if !_rules[ruleEnumValue]() {
-					goto l72
+					goto l77
 				}
-			l74:
+			l79:
 				{
-					position75, tokenIndex75 := position, tokenIndex
+					position80, tokenIndex80 := position, tokenIndex
 					if !_rules[ruleCOMMA]() {
-						goto l75
+						goto l80
 					}
 					if !_rules[ruleEnumValue]() {
-						goto l75
+						goto l80
 					}
-					goto l74
-				l75:
-					position, tokenIndex = position75, tokenIndex75
+					goto l79
+				l80:
+					position, tokenIndex = position80, tokenIndex80
 				}
 				{
-					position76, tokenIndex76 := position, tokenIndex
+					position81, tokenIndex81 := position, tokenIndex
 					if !_rules[ruleCOMMA]() {
-						goto l76
+						goto l81
 					}
-					goto l77
-				l76:
-					position, tokenIndex = position76, tokenIndex76
+					goto l82
+				l81:
+					position, tokenIndex = position81, tokenIndex81
 				}
-			l77:
-				add(ruleEnumValueList, position73)
+			l82:
+				add(ruleEnumValueList, position78)
 			}
 			return true
-		l72:
-			position, tokenIndex = position72, tokenIndex72
+		l77:
+			position, tokenIndex = position77, tokenIndex77
 			return false
 		},
-		/* 18 EnumValue <- <(Attribute* _ Identifier _ EQUALS String)> */
+		/* 19 EnumValue <- <(Attribute* _ Identifier _ EQUALS String)> */
 		func() bool {
-			position78, tokenIndex78 := position, tokenIndex
+			position83, tokenIndex83 := position, tokenIndex
 			{
-				position79 := position
-			l80:
+				position84 := position
+			l85:
 				{
-					position81, tokenIndex81 := position, tokenIndex
+					position86, tokenIndex86 := position, tokenIndex
 					if !_rules[ruleAttribute]() {
-						goto l81
+						goto l86
 					}
-					goto l80
-				l81:
-					position, tokenIndex = position81, tokenIndex81
+					goto l85
+				l86:
+					position, tokenIndex = position86, tokenIndex86
 				}
 				if !_rules[rule_]() {
-					goto l78
+					goto l83
 				}
 				if !_rules[ruleIdentifier]() {
-					goto l78
+					goto l83
 				}
 				if !_rules[rule_]() {
-					goto l78
+					goto l83
 				}
 				if !_rules[ruleEQUALS]() {
-					goto l78
+					goto l83
 				}
 				if !_rules[ruleString]() {
-					goto l78
+					goto l83
 				}
-				add(ruleEnumValue, position79)
+				add(ruleEnumValue, position84)
 			}
 			return true
-		l78:
-			position, tokenIndex = position78, tokenIndex78
+		l83:
+			position, tokenIndex = position83, tokenIndex83
 			return false
 		},
-		/* 19 DispatchStmt <- <('d' 'i' 's' 'p' 'a' 't' 'c' 'h' _ DispatchPath _ ('t' 'o') _ DispatchTarget)> */
+		/* 20 DispatchStmt <- <('d' 'i' 's' 'p' 'a' 't' 'c' 'h' _ DispatchPath _ ('t' 'o') _ DispatchTarget)> */
 		func() bool {
-			position82, tokenIndex82 := position, tokenIndex
+			position87, tokenIndex87 := position, tokenIndex
 			{
-				position83 := position
+				position88 := position
 				if buffer[position] != rune('d') {
-					goto l82
+					goto l87
 				}
 				position++
 				if buffer[position] != rune('i') {
-					goto l82
+					goto l87
 				}
 				position++
 				if buffer[position] != rune('s') {
-					goto l82
+					goto l87
 				}
 				position++
 				if buffer[position] != rune('p') {
-					goto l82
+					goto l87
 				}
 				position++
 				if buffer[position] != rune('a') {
-					goto l82
+					goto l87
 				}
 				position++
 				if buffer[position] != rune('t') {
-					goto l82
+					goto l87
 				}
 				position++
 				if buffer[position] != rune('c') {
-					goto l82
+					goto l87
 				}
 				position++
 				if buffer[position] != rune('h') {
-					goto l82
+					goto l87
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l82
+					goto l87
 				}
 				if !_rules[ruleDispatchPath]() {
-					goto l82
+					goto l87
 				}
 				if !_rules[rule_]() {
-					goto l82
+					goto l87
 				}
 				if buffer[position] != rune('t') {
-					goto l82
+					goto l87
 				}
 				position++
 				if buffer[position] != rune('o') {
-					goto l82
+					goto l87
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l82
+					goto l87
 				}
 				if !_rules[ruleDispatchTarget]() {
-					goto l82
+					goto l87
 				}
-				add(ruleDispatchStmt, position83)
+				add(ruleDispatchStmt, position88)
 			}
 			return true
-		l82:
-			position, tokenIndex = position82, tokenIndex82
+		l87:
+			position, tokenIndex = position87, tokenIndex87
 			return false
 		},
-		/* 20 DispatchPath <- <(Identifier COLON ResourcePath LBRACKET DispatchKeyList RBRACKET (LT GenericTypeParams RT)?)> */
+		/* 21 DispatchPath <- <(Identifier COLON ResourcePath LBRACKET DispatchKeyList RBRACKET (LT GenericTypeParams RT)?)> */
 		func() bool {
-			position84, tokenIndex84 := position, tokenIndex
+			position89, tokenIndex89 := position, tokenIndex
 			{
-				position85 := position
+				position90 := position
 				if !_rules[ruleIdentifier]() {
-					goto l84
+					goto l89
 				}
 				if !_rules[ruleCOLON]() {
-					goto l84
+					goto l89
 				}
 				if !_rules[ruleResourcePath]() {
-					goto l84
+					goto l89
 				}
 				if !_rules[ruleLBRACKET]() {
-					goto l84
+					goto l89
 				}
 				if !_rules[ruleDispatchKeyList]() {
-					goto l84
+					goto l89
 				}
 				if !_rules[ruleRBRACKET]() {
-					goto l84
+					goto l89
 				}
 				{
-					position86, tokenIndex86 := position, tokenIndex
+					position91, tokenIndex91 := position, tokenIndex
 					if !_rules[ruleLT]() {
-						goto l86
+						goto l91
 					}
 					if !_rules[ruleGenericTypeParams]() {
-						goto l86
+						goto l91
 					}
 					if !_rules[ruleRT]() {
-						goto l86
+						goto l91
 					}
-					goto l87
-				l86:
-					position, tokenIndex = position86, tokenIndex86
+					goto l92
+				l91:
+					position, tokenIndex = position91, tokenIndex91
 				}
-			l87:
-				add(ruleDispatchPath, position85)
+			l92:
+				add(ruleDispatchPath, position90)
 			}
 			return true
-		l84:
-			position, tokenIndex = position84, tokenIndex84
+		l89:
+			position, tokenIndex = position89, tokenIndex89
 			return false
 		},
-		/* 21 DispatchKeyList <- <(DispatchKey (COMMA DispatchKey)* COMMA?)> */
+		/* 22 DispatchKeyList <- <(DispatchKey (COMMA DispatchKey)* COMMA?)> */
 		func() bool {
-			position88, tokenIndex88 := position, tokenIndex
+			position93, tokenIndex93 := position, tokenIndex
 			{
-				position89 := position
+				position94 := position
 				if !_rules[ruleDispatchKey]() {
-					goto l88
+					goto l93
 				}
-			l90:
+			l95:
 				{
-					position91, tokenIndex91 := position, tokenIndex
+					position96, tokenIndex96 := position, tokenIndex
 					if !_rules[ruleCOMMA]() {
-						goto l91
+						goto l96
 					}
 					if !_rules[ruleDispatchKey]() {
-						goto l91
+						goto l96
 					}
-					goto l90
-				l91:
-					position, tokenIndex = position91, tokenIndex91
+					goto l95
+				l96:
+					position, tokenIndex = position96, tokenIndex96
 				}
 				{
-					position92, tokenIndex92 := position, tokenIndex
+					position97, tokenIndex97 := position, tokenIndex
 					if !_rules[ruleCOMMA]() {
-						goto l92
+						goto l97
 					}
-					goto l93
-				l92:
-					position, tokenIndex = position92, tokenIndex92
+					goto l98
+				l97:
+					position, tokenIndex = position97, tokenIndex97
 				}
-			l93:
-				add(ruleDispatchKeyList, position89)
+			l98:
+				add(ruleDispatchKeyList, position94)
 			}
 			return true
-		l88:
-			position, tokenIndex = position88, tokenIndex88
+		l93:
+			position, tokenIndex = position93, tokenIndex93
 			return false
 		},
-		/* 22 DispatchKey <- <(StaticIndexKey / String / Identifier)> */
+		/* 23 DispatchKey <- <(StaticIndexKey / String / Identifier)> */
 		func() bool {
-			position94, tokenIndex94 := position, tokenIndex
+			position99, tokenIndex99 := position, tokenIndex
 			{
-				position95 := position
+				position100 := position
 				{
-					position96, tokenIndex96 := position, tokenIndex
+					position101, tokenIndex101 := position, tokenIndex
 					if !_rules[ruleStaticIndexKey]() {
-						goto l97
+						goto l102
 					}
-					goto l96
-				l97:
-					position, tokenIndex = position96, tokenIndex96
+					goto l101
+				l102:
+					position, tokenIndex = position101, tokenIndex101
 					if !_rules[ruleString]() {
-						goto l98
+						goto l103
 					}
-					goto l96
-				l98:
-					position, tokenIndex = position96, tokenIndex96
+					goto l101
+				l103:
+					position, tokenIndex = position101, tokenIndex101
 					if !_rules[ruleIdentifier]() {
-						goto l94
+						goto l99
 					}
 				}
-			l96:
-				add(ruleDispatchKey, position95)
+			l101:
+				add(ruleDispatchKey, position100)
 			}
 			return true
-		l94:
-			position, tokenIndex = position94, tokenIndex94
+		l99:
+			position, tokenIndex = position99, tokenIndex99
 			return false
 		},
-		/* 23 DispatchTarget <- <(('s' 't' 'r' 'u' 'c' 't' _ Identifier _ LBRACE FieldList? RBRACE) / Type)> */
+		/* 24 DispatchTarget <- <(('s' 't' 'r' 'u' 'c' 't' _ Identifier _ LBRACE FieldList? RBRACE) / Type)> */
 		func() bool {
-			position99, tokenIndex99 := position, tokenIndex
+			position104, tokenIndex104 := position, tokenIndex
 			{
-				position100 := position
+				position105 := position
 				{
-					position101, tokenIndex101 := position, tokenIndex
+					position106, tokenIndex106 := position, tokenIndex
 					if buffer[position] != rune('s') {
-						goto l102
+						goto l107
 					}
 					position++
 					if buffer[position] != rune('t') {
-						goto l102
+						goto l107
 					}
 					position++
 					if buffer[position] != rune('r') {
-						goto l102
+						goto l107
 					}
 					position++
 					if buffer[position] != rune('u') {
-						goto l102
+						goto l107
 					}
 					position++
 					if buffer[position] != rune('c') {
-						goto l102
+						goto l107
 					}
 					position++
 					if buffer[position] != rune('t') {
-						goto l102
+						goto l107
 					}
 					position++
 					if !_rules[rule_]() {
-						goto l102
+						goto l107
 					}
 					if !_rules[ruleIdentifier]() {
-						goto l102
+						goto l107
 					}
 					if !_rules[rule_]() {
-						goto l102
+						goto l107
 					}
 					if !_rules[ruleLBRACE]() {
-						goto l102
+						goto l107
 					}
 					{
-						position103, tokenIndex103 := position, tokenIndex
+						position108, tokenIndex108 := position, tokenIndex
 						if !_rules[ruleFieldList]() {
-							goto l103
+							goto l108
 						}
-						goto l104
-					l103:
-						position, tokenIndex = position103, tokenIndex103
+						goto l109
+					l108:
+						position, tokenIndex = position108, tokenIndex108
 					}
-				l104:
+				l109:
 					if !_rules[ruleRBRACE]() {
-						goto l102
+						goto l107
 					}
-					goto l101
-				l102:
-					position, tokenIndex = position101, tokenIndex101
+					goto l106
+				l107:
+					position, tokenIndex = position106, tokenIndex106
 					if !_rules[ruleType]() {
-						goto l99
+						goto l104
 					}
 				}
-			l101:
-				add(ruleDispatchTarget, position100)
+			l106:
+				add(ruleDispatchTarget, position105)
 			}
 			return true
-		l99:
-			position, tokenIndex = position99, tokenIndex99
+		l104:
+			position, tokenIndex = position104, tokenIndex104
 			return false
 		},
-		/* 24 SpreadStruct <- <(SPREAD ('s' 't' 'r' 'u' 'c' 't') _ Identifier _ LBRACE FieldList? RBRACE)> */
-		nil,
-		/* 25 Type <- <(UnionType / AttributedType / ArrayType / StructType / ConstrainedType / GenericType / PrimitiveType / ReferenceType / LiteralType)> */
+		/* 25 InjectStmt <- <('i' 'n' 'j' 'e' 'c' 't' _ (InjectStruct / InjectEnum))> */
 		func() bool {
-			position106, tokenIndex106 := position, tokenIndex
+			position110, tokenIndex110 := position, tokenIndex
 			{
-				position107 := position
+				position111 := position
+				if buffer[position] != rune('i') {
+					goto l110
+				}
+				position++
+				if buffer[position] != rune('n') {
+					goto l110
+				}
+				position++
+				if buffer[position] != rune('j') {
+					goto l110
+				}
+				position++
+				if buffer[position] != rune('e') {
+					goto l110
+				}
+				position++
+				if buffer[position] != rune('c') {
+					goto l110
+				}
+				position++
+				if buffer[position] != rune('t') {
+					goto l110
+				}
+				position++
+				if !_rules[rule_]() {
+					goto l110
+				}
 				{
-					position108, tokenIndex108 := position, tokenIndex
-					if !_rules[ruleUnionType]() {
-						goto l109
-					}
-					goto l108
-				l109:
-					position, tokenIndex = position108, tokenIndex108
-					if !_rules[ruleAttributedType]() {
-						goto l110
-					}
-					goto l108
-				l110:
-					position, tokenIndex = position108, tokenIndex108
-					if !_rules[ruleArrayType]() {
-						goto l111
-					}
-					goto l108
-				l111:
-					position, tokenIndex = position108, tokenIndex108
-					if !_rules[ruleStructType]() {
-						goto l112
-					}
-					goto l108
-				l112:
-					position, tokenIndex = position108, tokenIndex108
-					if !_rules[ruleConstrainedType]() {
+					position112, tokenIndex112 := position, tokenIndex
+					if !_rules[ruleInjectStruct]() {
 						goto l113
 					}
-					goto l108
+					goto l112
 				l113:
-					position, tokenIndex = position108, tokenIndex108
-					if !_rules[ruleGenericType]() {
-						goto l114
-					}
-					goto l108
-				l114:
-					position, tokenIndex = position108, tokenIndex108
-					if !_rules[rulePrimitiveType]() {
-						goto l115
-					}
-					goto l108
-				l115:
-					position, tokenIndex = position108, tokenIndex108
-					if !_rules[ruleReferenceType]() {
-						goto l116
-					}
-					goto l108
-				l116:
-					position, tokenIndex = position108, tokenIndex108
-					if !_rules[ruleLiteralType]() {
-						goto l106
+					position, tokenIndex = position112, tokenIndex112
+					if !_rules[ruleInjectEnum]() {
+						goto l110
 					}
 				}
-			l108:
-				add(ruleType, position107)
+			l112:
+				add(ruleInjectStmt, position111)
 			}
 			return true
-		l106:
-			position, tokenIndex = position106, tokenIndex106
+		l110:
+			position, tokenIndex = position110, tokenIndex110
 			return false
 		},
-		/* 26 AttributedType <- <(Attribute+ _ (UnionType / ArrayType / ConstrainedType / StructType / GenericType / PrimitiveType / ReferenceType / LiteralType))> */
+		/* 26 InjectStruct <- <('s' 't' 'r' 'u' 'c' 't' _ Path LBRACE FieldList? RBRACE)> */
 		func() bool {
-			position117, tokenIndex117 := position, tokenIndex
+			position114, tokenIndex114 := position, tokenIndex
 			{
-				position118 := position
-				if !_rules[ruleAttribute]() {
-					goto l117
+				position115 := position
+				if buffer[position] != rune('s') {
+					goto l114
 				}
-			l119:
-				{
+				position++
+				if buffer[position] != rune('t') {
+					goto l114
+				}
+				position++
+				if buffer[position] != rune('r') {
+					goto l114
+				}
+				position++
+				if buffer[position] != rune('u') {
+					goto l114
+				}
+				position++
+				if buffer[position] != rune('c') {
+					goto l114
+				}
+				position++
+				if buffer[position] != rune('t') {
+					goto l114
+				}
+				position++
+				if !_rules[rule_]() {
+					goto l114
+				}
+				if !_rules[rulePath]() {
+					goto l114
+				}
+				if !_rules[ruleLBRACE]() {
+					goto l114
+				}
+				{
+					position116, tokenIndex116 := position, tokenIndex
+					if !_rules[ruleFieldList]() {
+						goto l116
+					}
+					goto l117
+				l116:
+					position, tokenIndex = position116, tokenIndex116
+				}
+			l117:
+				if !_rules[ruleRBRACE]() {
+					goto l114
+				}
+				add(ruleInjectStruct, position115)
+			}
+			return true
+		l114:
+			position, tokenIndex = position114, tokenIndex114
+			return false
+		},
+		/* 27 InjectEnum <- <('e' 'n' 'u' 'm' _ LPAREN Type RPAREN Path LBRACE EnumValueList? RBRACE)> */
+		func() bool {
+			position118, tokenIndex118 := position, tokenIndex
+			{
+				position119 := position
+				if buffer[position] != rune('e') {
+					goto l118
+				}
+				position++
+				if buffer[position] != rune('n') {
+					goto l118
+				}
+				position++
+				if buffer[position] != rune('u') {
+					goto l118
+				}
+				position++
+				if buffer[position] != rune('m') {
+					goto l118
+				}
+				position++
+				if !_rules[rule_]() {
+					goto l118
+				}
+				if !_rules[ruleLPAREN]() {
+					goto l118
+				}
+				if !_rules[ruleType]() {
+					goto l118
+				}
+				if !_rules[ruleRPAREN]() {
+					goto l118
+				}
+				if !_rules[rulePath]() {
+					goto l118
+				}
+				if !_rules[ruleLBRACE]() {
+					goto l118
+				}
+				{
 					position120, tokenIndex120 := position, tokenIndex
-					if !_rules[ruleAttribute]() {
+					if !_rules[ruleEnumValueList]() {
 						goto l120
 					}
-					goto l119
+					goto l121
 				l120:
 					position, tokenIndex = position120, tokenIndex120
 				}
-				if !_rules[rule_]() {
-					goto l117
+			l121:
+				if !_rules[ruleRBRACE]() {
+					goto l118
 				}
+				add(ruleInjectEnum, position119)
+			}
+			return true
+		l118:
+			position, tokenIndex = position118, tokenIndex118
+			return false
+		},
+		/* 28 SpreadStruct <- <(SPREAD ('s' 't' 'r' 'u' 'c' 't') _ Identifier _ LBRACE FieldList? RBRACE)> */
+		nil,
+		/* 29 Type <- <(UnionType / AttributedType / ArrayType / StructType / ConstrainedType / GenericType / PrimitiveType / ReferenceType / LiteralType)> */
+		func() bool {
+			position123, tokenIndex123 := position, tokenIndex
+			{
+				position124 := position
 				{
-					position121, tokenIndex121 := position, tokenIndex
+					position125, tokenIndex125 := position, tokenIndex
 					if !_rules[ruleUnionType]() {
-						goto l122
+						goto l126
 					}
-					goto l121
-				l122:
-					position, tokenIndex = position121, tokenIndex121
+					goto l125
+				l126:
+					position, tokenIndex = position125, tokenIndex125
+					if !_rules[ruleAttributedType]() {
+						goto l127
+					}
+					goto l125
+				l127:
+					position, tokenIndex = position125, tokenIndex125
 					if !_rules[ruleArrayType]() {
+						goto l128
+					}
+					goto l125
+				l128:
+					position, tokenIndex = position125, tokenIndex125
+					if !_rules[ruleStructType]() {
+						goto l129
+					}
+					goto l125
+				l129:
+					position, tokenIndex = position125, tokenIndex125
+					if !_rules[ruleConstrainedType]() {
+						goto l130
+					}
+					goto l125
+				l130:
+					position, tokenIndex = position125, tokenIndex125
+					if !_rules[ruleGenericType]() {
+						goto l131
+					}
+					goto l125
+				l131:
+					position, tokenIndex = position125, tokenIndex125
+					if !_rules[rulePrimitiveType]() {
+						goto l132
+					}
+					goto l125
+				l132:
+					position, tokenIndex = position125, tokenIndex125
+					if !_rules[ruleReferenceType]() {
+						goto l133
+					}
+					goto l125
+				l133:
+					position, tokenIndex = position125, tokenIndex125
+					if !_rules[ruleLiteralType]() {
 						goto l123
 					}
-					goto l121
-				l123:
-					position, tokenIndex = position121, tokenIndex121
+				}
+			l125:
+				add(ruleType, position124)
+			}
+			return true
+		l123:
+			position, tokenIndex = position123, tokenIndex123
+			return false
+		},
+		/* 30 AttributedType <- <(Attribute+ _ (UnionType / ArrayType / ConstrainedType / StructType / GenericType / PrimitiveType / ReferenceType / LiteralType))> */
+		func() bool {
+			position134, tokenIndex134 := position, tokenIndex
+			{
+				position135 := position
+				if !_rules[ruleAttribute]() {
+					goto l134
+				}
+			l136:
+				{
+					position137, tokenIndex137 := position, tokenIndex
+					if !_rules[ruleAttribute]() {
+						goto l137
+					}
+					goto l136
+				l137:
+					position, tokenIndex = position137, tokenIndex137
+				}
+				if !_rules[rule_]() {
+					goto l134
+				}
+				{
+					position138, tokenIndex138 := position, tokenIndex
+					if !_rules[ruleUnionType]() {
+						goto l139
+					}
+					goto l138
+				l139:
+					position, tokenIndex = position138, tokenIndex138
+					if !_rules[ruleArrayType]() {
+						goto l140
+					}
+					goto l138
+				l140:
+					position, tokenIndex = position138, tokenIndex138
 					if !_rules[ruleConstrainedType]() {
-						goto l124
+						goto l141
 					}
-					goto l121
-				l124:
-					position, tokenIndex = position121, tokenIndex121
+					goto l138
+				l141:
+					position, tokenIndex = position138, tokenIndex138
 					if !_rules[ruleStructType]() {
-						goto l125
+						goto l142
 					}
-					goto l121
-				l125:
-					position, tokenIndex = position121, tokenIndex121
+					goto l138
+				l142:
+					position, tokenIndex = position138, tokenIndex138
 					if !_rules[ruleGenericType]() {
-						goto l126
+						goto l143
 					}
-					goto l121
-				l126:
-					position, tokenIndex = position121, tokenIndex121
+					goto l138
+				l143:
+					position, tokenIndex = position138, tokenIndex138
 					if !_rules[rulePrimitiveType]() {
-						goto l127
+						goto l144
 					}
-					goto l121
-				l127:
-					position, tokenIndex = position121, tokenIndex121
+					goto l138
+				l144:
+					position, tokenIndex = position138, tokenIndex138
 					if !_rules[ruleReferenceType]() {
-						goto l128
+						goto l145
 					}
-					goto l121
-				l128:
-					position, tokenIndex = position121, tokenIndex121
+					goto l138
+				l145:
+					position, tokenIndex = position138, tokenIndex138
 					if !_rules[ruleLiteralType]() {
-						goto l117
+						goto l134
 					}
 				}
-			l121:
-				add(ruleAttributedType, position118)
+			l138:
+				add(ruleAttributedType, position135)
 			}
 			return true
-		l117:
-			position, tokenIndex = position117, tokenIndex117
+		l134:
+			position, tokenIndex = position134, tokenIndex134
 			return false
 		},
-		/* 27 ConstrainedType <- <((PrimitiveType / ReferenceType / LiteralType) ArrayConstraint)> */
+		/* 31 ConstrainedType <- <((PrimitiveType / ReferenceType / LiteralType) ArrayConstraint)> */
 		func() bool {
-			position129, tokenIndex129 := position, tokenIndex
+			position146, tokenIndex146 := position, tokenIndex
 			{
-				position130 := position
+				position147 := position
 				{
-					position131, tokenIndex131 := position, tokenIndex
+					position148, tokenIndex148 := position, tokenIndex
 					if !_rules[rulePrimitiveType]() {
-						goto l132
+						goto l149
 					}
-					goto l131
-				l132:
-					position, tokenIndex = position131, tokenIndex131
+					goto l148
+				l149:
+					position, tokenIndex = position148, tokenIndex148
 					if !_rules[ruleReferenceType]() {
-						goto l133
+						goto l150
 					}
-					goto l131
-				l133:
-					position, tokenIndex = position131, tokenIndex131
+					goto l148
+				l150:
+					position, tokenIndex = position148, tokenIndex148
 					if !_rules[ruleLiteralType]() {
-						goto l129
+						goto l146
 					}
 				}
-			l131:
+			l148:
 				if !_rules[ruleArrayConstraint]() {
-					goto l129
+					goto l146
 				}
-				add(ruleConstrainedType, position130)
+				add(ruleConstrainedType, position147)
 			}
 			return true
-		l129:
-			position, tokenIndex = position129, tokenIndex129
+		l146:
+			position, tokenIndex = position146, tokenIndex146
 			return false
 		},
-		/* 28 UnionType <- <(LPAREN Type (PIPE Type)* PIPE? RPAREN)> */
+		/* 32 UnionType <- <(LPAREN Type (PIPE Type)* PIPE? RPAREN)> */
 		func() bool {
-			position134, tokenIndex134 := position, tokenIndex
+			position151, tokenIndex151 := position, tokenIndex
 			{
-				position135 := position
+				position152 := position
 				if !_rules[ruleLPAREN]() {
-					goto l134
+					goto l151
 				}
 				if !_rules[ruleType]() {
-					goto l134
+					goto l151
 				}
-			l136:
+			l153:
 				{
-					position137, tokenIndex137 := position, tokenIndex
+					position154, tokenIndex154 := position, tokenIndex
 					if !_rules[rulePIPE]() {
-						goto l137
+						goto l154
 					}
 					if !_rules[ruleType]() {
-						goto l137
+						goto l154
 					}
-					goto l136
-				l137:
-					position, tokenIndex = position137, tokenIndex137
+					goto l153
+				l154:
+					position, tokenIndex = position154, tokenIndex154
 				}
 				{
-					position138, tokenIndex138 := position, tokenIndex
+					position155, tokenIndex155 := position, tokenIndex
 					if !_rules[rulePIPE]() {
-						goto l138
+						goto l155
 					}
-					goto l139
-				l138:
-					position, tokenIndex = position138, tokenIndex138
+					goto l156
+				l155:
+					position, tokenIndex = position155, tokenIndex155
 				}
-			l139:
+			l156:
 				if !_rules[ruleRPAREN]() {
-					goto l134
+					goto l151
 				}
-				add(ruleUnionType, position135)
+				add(ruleUnionType, position152)
 			}
 			return true
-		l134:
-			position, tokenIndex = position134, tokenIndex134
+		l151:
+			position, tokenIndex = position151, tokenIndex151
 			return false
 		},
-		/* 29 ArrayType <- <((LBRACKET Type RBRACKET ArrayConstraint?) / (PrimitiveType LBRACKET RBRACKET) / (ReferenceType LBRACKET RBRACKET))> */
+		/* 33 ArrayType <- <((LBRACKET Type RBRACKET ArrayConstraint?) / (PrimitiveType ArrayConstraint? LBRACKET RBRACKET ArrayConstraint?) / (ReferenceType LBRACKET RBRACKET))> */
 		func() bool {
-			position140, tokenIndex140 := position, tokenIndex
+			position157, tokenIndex157 := position, tokenIndex
 			{
-				position141 := position
+				position158 := position
 				{
-					position142, tokenIndex142 := position, tokenIndex
+					position159, tokenIndex159 := position, tokenIndex
 					if !_rules[ruleLBRACKET]() {
-						goto l143
+						goto l160
 					}
 					if !_rules[ruleType]() {
-						goto l143
+						goto l160
 					}
 					if !_rules[ruleRBRACKET]() {
-						goto l143
+						goto l160
 					}
 					{
-						position144, tokenIndex144 := position, tokenIndex
+						position161, tokenIndex161 := position, tokenIndex
 						if !_rules[ruleArrayConstraint]() {
-							goto l144
+							goto l161
 						}
-						goto l145
-					l144:
-						position, tokenIndex = position144, tokenIndex144
+						goto l162
+					l161:
+						position, tokenIndex = position161, tokenIndex161
 					}
-				l145:
-					goto l142
-				l143:
-					position, tokenIndex = position142, tokenIndex142
+				l162:
+					goto l159
+				l160:
+					position, tokenIndex = position159, tokenIndex159
 					if !_rules[rulePrimitiveType]() {
-						goto l146
+						goto l163
+					}
+					{
+						position164, tokenIndex164 := position, tokenIndex
+						if !_rules[ruleArrayConstraint]() {
+							goto l164
+						}
+						goto l165
+					l164:
+						position, tokenIndex = position164, tokenIndex164
 					}
+				l165:
 					if !_rules[ruleLBRACKET]() {
-						goto l146
+						goto l163
 					}
 					if !_rules[ruleRBRACKET]() {
-						goto l146
+						goto l163
 					}
-					goto l142
-				l146:
-					position, tokenIndex = position142, tokenIndex142
+					{
+						position166, tokenIndex166 := position, tokenIndex
+						if !_rules[ruleArrayConstraint]() {
+							goto l166
+						}
+						goto l167
+					l166:
+						position, tokenIndex = position166, tokenIndex166
+					}
+				l167:
+					goto l159
+				l163:
+					position, tokenIndex = position159, tokenIndex159
 					if !_rules[ruleReferenceType]() {
-						goto l140
+						goto l157
 					}
 					if !_rules[ruleLBRACKET]() {
-						goto l140
+						goto l157
 					}
 					if !_rules[ruleRBRACKET]() {
-						goto l140
+						goto l157
 					}
 				}
-			l142:
-				add(ruleArrayType, position141)
+			l159:
+				add(ruleArrayType, position158)
 			}
 			return true
-		l140:
-			position, tokenIndex = position140, tokenIndex140
+		l157:
+			position, tokenIndex = position157, tokenIndex157
 			return false
 		},
-		/* 30 StructType <- <('s' 't' 'r' 'u' 'c' 't' _ Identifier? _ LBRACE FieldList? RBRACE)> */
+		/* 34 StructType <- <('s' 't' 'r' 'u' 'c' 't' _ Identifier? _ LBRACE FieldList? RBRACE)> */
 		func() bool {
-			position147, tokenIndex147 := position, tokenIndex
+			position168, tokenIndex168 := position, tokenIndex
 			{
-				position148 := position
+				position169 := position
 				if buffer[position] != rune('s') {
-					goto l147
+					goto l168
 				}
 				position++
 				if buffer[position] != rune('t') {
-					goto l147
+					goto l168
 				}
 				position++
 				if buffer[position] != rune('r') {
-					goto l147
+					goto l168
 				}
 				position++
 				if buffer[position] != rune('u') {
-					goto l147
+					goto l168
 				}
 				position++
 				if buffer[position] != rune('c') {
-					goto l147
+					goto l168
 				}
 				position++
 				if buffer[position] != rune('t') {
-					goto l147
+					goto l168
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l147
+					goto l168
 				}
 				{
-					position149, tokenIndex149 := position, tokenIndex
+					position170, tokenIndex170 := position, tokenIndex
 					if !_rules[ruleIdentifier]() {
-						goto l149
+						goto l170
 					}
-					goto l150
-				l149:
-					position, tokenIndex = position149, tokenIndex149
+					goto l171
+				l170:
+					position, tokenIndex = position170, tokenIndex170
 				}
-			l150:
+			l171:
 				if !_rules[rule_]() {
-					goto l147
+					goto l168
 				}
 				if !_rules[ruleLBRACE]() {
-					goto l147
+					goto l168
 				}
 				{
-					position151, tokenIndex151 := position, tokenIndex
+					position172, tokenIndex172 := position, tokenIndex
 					if !_rules[ruleFieldList]() {
-						goto l151
+						goto l172
 					}
-					goto l152
-				l151:
-					position, tokenIndex = position151, tokenIndex151
+					goto l173
+				l172:
+					position, tokenIndex = position172, tokenIndex172
 				}
-			l152:
+			l173:
 				if !_rules[ruleRBRACE]() {
-					goto l147
+					goto l168
 				}
-				add(ruleStructType, position148)
+				add(ruleStructType, position169)
 			}
 			return true
-		l147:
-			position, tokenIndex = position147, tokenIndex147
+		l168:
+			position, tokenIndex = position168, tokenIndex168
 			return false
 		},
-		/* 31 GenericType <- <(Identifier LT GenericTypeParams RT)> */
+		/* 35 GenericType <- <(Identifier LT GenericTypeParams RT)> */
 		func() bool {
-			position153, tokenIndex153 := position, tokenIndex
+			position174, tokenIndex174 := position, tokenIndex
 			{
-				position154 := position
+				position175 := position
 				if !_rules[ruleIdentifier]() {
-					goto l153
+					goto l174
 				}
 				if !_rules[ruleLT]() {
-					goto l153
+					goto l174
 				}
 				if !_rules[ruleGenericTypeParams]() {
-					goto l153
+					goto l174
 				}
 				if !_rules[ruleRT]() {
-					goto l153
+					goto l174
 				}
-				add(ruleGenericType, position154)
+				add(ruleGenericType, position175)
 			}
 			return true
-		l153:
-			position, tokenIndex = position153, tokenIndex153
+		l174:
+			position, tokenIndex = position174, tokenIndex174
 			return false
 		},
-		/* 32 GenericTypeParams <- <(Type (COMMA Type)*)> */
+		/* 36 GenericTypeParams <- <(Type (COMMA Type)*)> */
 		func() bool {
-			position155, tokenIndex155 := position, tokenIndex
+			position176, tokenIndex176 := position, tokenIndex
 			{
-				position156 := position
+				position177 := position
 				if !_rules[ruleType]() {
-					goto l155
+					goto l176
 				}
-			l157:
+			l178:
 				{
-					position158, tokenIndex158 := position, tokenIndex
+					position179, tokenIndex179 := position, tokenIndex
 					if !_rules[ruleCOMMA]() {
-						goto l158
+						goto l179
 					}
 					if !_rules[ruleType]() {
-						goto l158
+						goto l179
 					}
-					goto l157
-				l158:
-					position, tokenIndex = position158, tokenIndex158
+					goto l178
+				l179:
+					position, tokenIndex = position179, tokenIndex179
 				}
-				add(ruleGenericTypeParams, position156)
+				add(ruleGenericTypeParams, position177)
 			}
 			return true
-		l155:
-			position, tokenIndex = position155, tokenIndex155
+		l176:
+			position, tokenIndex = position176, tokenIndex176
 			return false
 		},
-		/* 33 PrimitiveType <- <((('s' 't' 'r' 'i' 'n' 'g') / ('d' 'o' 'u' 'b' 'l' 'e') / ('f' 'l' 'o' 'a' 't') / ('i' 'n' 't') / ('b' 'o' 'o' 'l' 'e' 'a' 'n') / ('a' 'n' 'y')) _)> */
+		/* 37 PrimitiveType <- <((('s' 't' 'r' 'i' 'n' 'g') / ('d' 'o' 'u' 'b' 'l' 'e') / ('f' 'l' 'o' 'a' 't') / ('i' 'n' 't') / ('b' 'y' 't' 'e') / ('s' 'h' 'o' 'r' 't') / ('l' 'o' 'n' 'g') / ('b' 'o' 'o' 'l' 'e' 'a' 'n') / ('a' 'n' 'y') / ('u' 'n' 's' 'a' 'f' 'e')) !([a-z] / [A-Z] / [0-9] / '_') _)> */
 		func() bool {
-			position159, tokenIndex159 := position, tokenIndex
+			position180, tokenIndex180 := position, tokenIndex
 			{
-				position160 := position
+				position181 := position
 				{
-					position161, tokenIndex161 := position, tokenIndex
+					position182, tokenIndex182 := position, tokenIndex
 					if buffer[position] != rune('s') {
-						goto l162
+						goto l183
 					}
 					position++
 					if buffer[position] != rune('t') {
-						goto l162
+						goto l183
 					}
 					position++
 					if buffer[position] != rune('r') {
-						goto l162
+						goto l183
 					}
 					position++
 					if buffer[position] != rune('i') {
-						goto l162
+						goto l183
 					}
 					position++
 					if buffer[position] != rune('n') {
-						goto l162
+						goto l183
 					}
 					position++
 					if buffer[position] != rune('g') {
-						goto l162
+						goto l183
 					}
 					position++
-					goto l161
-				l162:
-					position, tokenIndex = position161, tokenIndex161
+					goto l182
+				l183:
+					position, tokenIndex = position182, tokenIndex182
 					if buffer[position] != rune('d') {
-						goto l163
+						goto l184
 					}
 					position++
 					if buffer[position] != rune('o') {
-						goto l163
+						goto l184
 					}
 					position++
 					if buffer[position] != rune('u') {
-						goto l163
+						goto l184
 					}
 					position++
 					if buffer[position] != rune('b') {
-						goto l163
+						goto l184
 					}
 					position++
 					if buffer[position] != rune('l') {
-						goto l163
+						goto l184
 					}
 					position++
 					if buffer[position] != rune('e') {
-						goto l163
+						goto l184
 					}
 					position++
-					goto l161
-				l163:
-					position, tokenIndex = position161, tokenIndex161
+					goto l182
+				l184:
+					position, tokenIndex = position182, tokenIndex182
 					if buffer[position] != rune('f') {
-						goto l164
+						goto l185
 					}
 					position++
 					if buffer[position] != rune('l') {
-						goto l164
+						goto l185
 					}
 					position++
 					if buffer[position] != rune('o') {
-						goto l164
+						goto l185
 					}
 					position++
 					if buffer[position] != rune('a') {
-						goto l164
+						goto l185
 					}
 					position++
 					if buffer[position] != rune('t') {
-						goto l164
+						goto l185
 					}
 					position++
-					goto l161
-				l164:
-					position, tokenIndex = position161, tokenIndex161
+					goto l182
+				l185:
+					position, tokenIndex = position182, tokenIndex182
 					if buffer[position] != rune('i') {
-						goto l165
+						goto l186
 					}
 					position++
 					if buffer[position] != rune('n') {
-						goto l165
+						goto l186
 					}
 					position++
 					if buffer[position] != rune('t') {
-						goto l165
+						goto l186
 					}
 					position++
-					goto l161
-				l165:
-					position, tokenIndex = position161, tokenIndex161
+					goto l182
+				l186:
+					position, tokenIndex = position182, tokenIndex182
+					if buffer[position] != rune('b') {
+						goto l187
+					}
+					position++
+					if buffer[position] != rune('y') {
+						goto l187
+					}
+					position++
+					if buffer[position] != rune('t') {
+						goto l187
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l187
+					}
+					position++
+					goto l182
+				l187:
+					position, tokenIndex = position182, tokenIndex182
+					if buffer[position] != rune('s') {
+						goto l188
+					}
+					position++
+					if buffer[position] != rune('h') {
+						goto l188
+					}
+					position++
+					if buffer[position] != rune('o') {
+						goto l188
+					}
+					position++
+					if buffer[position] != rune('r') {
+						goto l188
+					}
+					position++
+					if buffer[position] != rune('t') {
+						goto l188
+					}
+					position++
+					goto l182
+				l188:
+					position, tokenIndex = position182, tokenIndex182
+					if buffer[position] != rune('l') {
+						goto l189
+					}
+					position++
+					if buffer[position] != rune('o') {
+						goto l189
+					}
+					position++
+					if buffer[position] != rune('n') {
+						goto l189
+					}
+					position++
+					if buffer[position] != rune('g') {
+						goto l189
+					}
+					position++
+					goto l182
+				l189:
+					position, tokenIndex = position182, tokenIndex182
 					if buffer[position] != rune('b') {
-						goto l166
+						goto l190
 					}
 					position++
 					if buffer[position] != rune('o') {
-						goto l166
+						goto l190
 					}
 					position++
 					if buffer[position] != rune('o') {
-						goto l166
+						goto l190
 					}
 					position++
 					if buffer[position] != rune('l') {
-						goto l166
+						goto l190
 					}
 					position++
 					if buffer[position] != rune('e') {
-						goto l166
+						goto l190
 					}
 					position++
 					if buffer[position] != rune('a') {
-						goto l166
+						goto l190
 					}
 					position++
 					if buffer[position] != rune('n') {
-						goto l166
+						goto l190
 					}
 					position++
-					goto l161
-				l166:
-					position, tokenIndex = position161, tokenIndex161
+					goto l182
+				l190:
+					position, tokenIndex = position182, tokenIndex182
 					if buffer[position] != rune('a') {
-						goto l159
+						goto l191
 					}
 					position++
 					if buffer[position] != rune('n') {
-						goto l159
+						goto l191
 					}
 					position++
 					if buffer[position] != rune('y') {
-						goto l159
+						goto l191
+					}
+					position++
+					goto l182
+				l191:
+					position, tokenIndex = position182, tokenIndex182
+					if buffer[position] != rune('u') {
+						goto l180
+					}
+					position++
+					if buffer[position] != rune('n') {
+						goto l180
 					}
 					position++
+					if buffer[position] != rune('s') {
+						goto l180
+					}
+					position++
+					if buffer[position] != rune('a') {
+						goto l180
+					}
+					position++
+					if buffer[position] != rune('f') {
+						goto l180
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l180
+					}
+					position++
+				}
+			l182:
+				{
+					position192, tokenIndex192 := position, tokenIndex
+					{
+						position193, tokenIndex193 := position, tokenIndex
+						if c := buffer[position]; c < rune('a') || c > rune('z') {
+							goto l194
+						}
+						position++
+						goto l193
+					l194:
+						position, tokenIndex = position193, tokenIndex193
+						if c := buffer[position]; c < rune('A') || c > rune('Z') {
+							goto l195
+						}
+						position++
+						goto l193
+					l195:
+						position, tokenIndex = position193, tokenIndex193
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l196
+						}
+						position++
+						goto l193
+					l196:
+						position, tokenIndex = position193, tokenIndex193
+						if buffer[position] != rune('_') {
+							goto l192
+						}
+						position++
+					}
+				l193:
+					goto l180
+				l192:
+					position, tokenIndex = position192, tokenIndex192
 				}
-			l161:
 				if !_rules[rule_]() {
-					goto l159
+					goto l180
 				}
-				add(rulePrimitiveType, position160)
+				add(rulePrimitiveType, position181)
 			}
 			return true
-		l159:
-			position, tokenIndex = position159, tokenIndex159
+		l180:
+			position, tokenIndex = position180, tokenIndex180
 			return false
 		},
-		/* 34 ReferenceType <- <(ComplexReference / Path / Identifier)> */
+		/* 38 ReferenceType <- <(ComplexReference / Path / Identifier)> */
 		func() bool {
-			position167, tokenIndex167 := position, tokenIndex
+			position197, tokenIndex197 := position, tokenIndex
 			{
-				position168 := position
+				position198 := position
 				{
-					position169, tokenIndex169 := position, tokenIndex
+					position199, tokenIndex199 := position, tokenIndex
 					if !_rules[ruleComplexReference]() {
-						goto l170
+						goto l200
 					}
-					goto l169
-				l170:
-					position, tokenIndex = position169, tokenIndex169
+					goto l199
+				l200:
+					position, tokenIndex = position199, tokenIndex199
 					if !_rules[rulePath]() {
-						goto l171
+						goto l201
 					}
-					goto l169
-				l171:
-					position, tokenIndex = position169, tokenIndex169
+					goto l199
+				l201:
+					position, tokenIndex = position199, tokenIndex199
 					if !_rules[ruleIdentifier]() {
-						goto l167
+						goto l197
 					}
 				}
-			l169:
-				add(ruleReferenceType, position168)
+			l199:
+				add(ruleReferenceType, position198)
 			}
 			return true
-		l167:
-			position, tokenIndex = position167, tokenIndex167
+		l197:
+			position, tokenIndex = position197, tokenIndex197
 			return false
 		},
-		/* 35 ComplexReference <- <(Identifier COLON ResourcePath ((LBRACKET LBRACKET ComplexRefParam RBRACKET RBRACKET) / (LBRACKET ComplexRefParam RBRACKET)) (LT GenericTypeParams RT)?)> */
+		/* 39 ComplexReference <- <(Identifier COLON ResourcePath ((LBRACKET LBRACKET ComplexRefParam RBRACKET RBRACKET) / (LBRACKET ComplexRefParam RBRACKET)) (LT GenericTypeParams RT)?)> */
 		func() bool {
-			position172, tokenIndex172 := position, tokenIndex
+			position202, tokenIndex202 := position, tokenIndex
 			{
-				position173 := position
+				position203 := position
 				if !_rules[ruleIdentifier]() {
-					goto l172
+					goto l202
 				}
 				if !_rules[ruleCOLON]() {
-					goto l172
+					goto l202
 				}
 				if !_rules[ruleResourcePath]() {
-					goto l172
+					goto l202
 				}
 				{
-					position174, tokenIndex174 := position, tokenIndex
+					position204, tokenIndex204 := position, tokenIndex
 					if !_rules[ruleLBRACKET]() {
-						goto l175
+						goto l205
 					}
 					if !_rules[ruleLBRACKET]() {
-						goto l175
+						goto l205
 					}
 					if !_rules[ruleComplexRefParam]() {
-						goto l175
+						goto l205
 					}
 					if !_rules[ruleRBRACKET]() {
-						goto l175
+						goto l205
 					}
 					if !_rules[ruleRBRACKET]() {
-						goto l175
+						goto l205
 					}
-					goto l174
-				l175:
-					position, tokenIndex = position174, tokenIndex174
+					goto l204
+				l205:
+					position, tokenIndex = position204, tokenIndex204
 					if !_rules[ruleLBRACKET]() {
-						goto l172
+						goto l202
 					}
 					if !_rules[ruleComplexRefParam]() {
-						goto l172
+						goto l202
 					}
 					if !_rules[ruleRBRACKET]() {
-						goto l172
+						goto l202
 					}
 				}
-			l174:
+			l204:
 				{
-					position176, tokenIndex176 := position, tokenIndex
+					position206, tokenIndex206 := position, tokenIndex
 					if !_rules[ruleLT]() {
-						goto l176
+						goto l206
 					}
 					if !_rules[ruleGenericTypeParams]() {
-						goto l176
+						goto l206
 					}
 					if !_rules[ruleRT]() {
-						goto l176
+						goto l206
 					}
-					goto l177
-				l176:
-					position, tokenIndex = position176, tokenIndex176
+					goto l207
+				l206:
+					position, tokenIndex = position206, tokenIndex206
 				}
-			l177:
-				add(ruleComplexReference, position173)
+			l207:
+				add(ruleComplexReference, position203)
 			}
 			return true
-		l172:
-			position, tokenIndex = position172, tokenIndex172
+		l202:
+			position, tokenIndex = position202, tokenIndex202
 			return false
 		},
-		/* 36 ResourcePath <- <(Identifier ('/' Identifier)*)> */
+		/* 40 ResourcePath <- <(Identifier ('/' Identifier)*)> */
 		func() bool {
-			position178, tokenIndex178 := position, tokenIndex
+			position208, tokenIndex208 := position, tokenIndex
 			{
-				position179 := position
+				position209 := position
 				if !_rules[ruleIdentifier]() {
-					goto l178
+					goto l208
 				}
-			l180:
+			l210:
 				{
-					position181, tokenIndex181 := position, tokenIndex
+					position211, tokenIndex211 := position, tokenIndex
 					if buffer[position] != rune('/') {
-						goto l181
+						goto l211
 					}
 					position++
 					if !_rules[ruleIdentifier]() {
-						goto l181
+						goto l211
 					}
-					goto l180
-				l181:
-					position, tokenIndex = position181, tokenIndex181
+					goto l210
+				l211:
+					position, tokenIndex = position211, tokenIndex211
 				}
-				add(ruleResourcePath, position179)
+				add(ruleResourcePath, position209)
 			}
 			return true
-		l178:
-			position, tokenIndex = position178, tokenIndex178
+		l208:
+			position, tokenIndex = position208, tokenIndex208
 			return false
 		},
-		/* 37 ComplexRefParam <- <(DottedPath / StaticIndexKey / String / Identifier)> */
+		/* 41 ComplexRefParam <- <(DottedPath / StaticIndexKey / String / Identifier)> */
 		func() bool {
-			position182, tokenIndex182 := position, tokenIndex
+			position212, tokenIndex212 := position, tokenIndex
 			{
-				position183 := position
+				position213 := position
 				{
-					position184, tokenIndex184 := position, tokenIndex
+					position214, tokenIndex214 := position, tokenIndex
 					if !_rules[ruleDottedPath]() {
-						goto l185
+						goto l215
 					}
-					goto l184
-				l185:
-					position, tokenIndex = position184, tokenIndex184
+					goto l214
+				l215:
+					position, tokenIndex = position214, tokenIndex214
 					if !_rules[ruleStaticIndexKey]() {
-						goto l186
+						goto l216
 					}
-					goto l184
-				l186:
-					position, tokenIndex = position184, tokenIndex184
+					goto l214
+				l216:
+					position, tokenIndex = position214, tokenIndex214
 					if !_rules[ruleString]() {
-						goto l187
+						goto l217
 					}
-					goto l184
-				l187:
-					position, tokenIndex = position184, tokenIndex184
+					goto l214
+				l217:
+					position, tokenIndex = position214, tokenIndex214
 					if !_rules[ruleIdentifier]() {
-						goto l182
+						goto l212
 					}
 				}
-			l184:
-				add(ruleComplexRefParam, position183)
+			l214:
+				add(ruleComplexRefParam, position213)
 			}
 			return true
-		l182:
-			position, tokenIndex = position182, tokenIndex182
+		l212:
+			position, tokenIndex = position212, tokenIndex212
 			return false
 		},
-		/* 38 DottedPath <- <((StaticIndexKey / Identifier) ('.' Identifier)+)> */
+		/* 42 DottedPath <- <((StaticIndexKey / Identifier) ('.' Identifier)+)> */
 		func() bool {
-			position188, tokenIndex188 := position, tokenIndex
+			position218, tokenIndex218 := position, tokenIndex
 			{
-				position189 := position
+				position219 := position
 				{
-					position190, tokenIndex190 := position, tokenIndex
+					position220, tokenIndex220 := position, tokenIndex
 					if !_rules[ruleStaticIndexKey]() {
-						goto l191
+						goto l221
 					}
-					goto l190
-				l191:
-					position, tokenIndex = position190, tokenIndex190
+					goto l220
+				l221:
+					position, tokenIndex = position220, tokenIndex220
 					if !_rules[ruleIdentifier]() {
-						goto l188
+						goto l218
 					}
 				}
-			l190:
+			l220:
 				if buffer[position] != rune('.') {
-					goto l188
+					goto l218
 				}
 				position++
 				if !_rules[ruleIdentifier]() {
-					goto l188
+					goto l218
 				}
-			l192:
+			l222:
 				{
-					position193, tokenIndex193 := position, tokenIndex
+					position223, tokenIndex223 := position, tokenIndex
 					if buffer[position] != rune('.') {
-						goto l193
+						goto l223
 					}
 					position++
 					if !_rules[ruleIdentifier]() {
-						goto l193
+						goto l223
 					}
-					goto l192
-				l193:
-					position, tokenIndex = position193, tokenIndex193
+					goto l222
+				l223:
+					position, tokenIndex = position223, tokenIndex223
 				}
-				add(ruleDottedPath, position189)
+				add(ruleDottedPath, position219)
 			}
 			return true
-		l188:
-			position, tokenIndex = position188, tokenIndex188
+		l218:
+			position, tokenIndex = position218, tokenIndex218
 			return false
 		},
-		/* 39 StaticIndexKey <- <((('%' 'f' 'a' 'l' 'l' 'b' 'a' 'c' 'k') / ('%' 'k' 'e' 'y') / ('%' 'p' 'a' 'r' 'e' 'n' 't') / ('%' 'n' 'o' 'n' 'e') / ('%' 'u' 'n' 'k' 'n' 'o' 'w' 'n')) _)> */
+		/* 43 StaticIndexKey <- <((('%' 'f' 'a' 'l' 'l' 'b' 'a' 'c' 'k') / ('%' 'k' 'e' 'y') / ('%' 'p' 'a' 'r' 'e' 'n' 't') / ('%' 'n' 'o' 'n' 'e') / ('%' 'u' 'n' 'k' 'n' 'o' 'w' 'n')) _)> */
 		func() bool {
-			position194, tokenIndex194 := position, tokenIndex
+			position224, tokenIndex224 := position, tokenIndex
 			{
-				position195 := position
+				position225 := position
 				{
-					position196, tokenIndex196 := position, tokenIndex
+					position226, tokenIndex226 := position, tokenIndex
 					if buffer[position] != rune('%') {
-						goto l197
+						goto l227
 					}
 					position++
 					if buffer[position] != rune('f') {
-						goto l197
+						goto l227
 					}
 					position++
 					if buffer[position] != rune('a') {
-						goto l197
+						goto l227
 					}
 					position++
 					if buffer[position] != rune('l') {
-						goto l197
+						goto l227
 					}
 					position++
 					if buffer[position] != rune('l') {
-						goto l197
+						goto l227
 					}
 					position++
 					if buffer[position] != rune('b') {
-						goto l197
+						goto l227
 					}
 					position++
 					if buffer[position] != rune('a') {
-						goto l197
+						goto l227
 					}
 					position++
 					if buffer[position] != rune('c') {
-						goto l197
+						goto l227
 					}
 					position++
 					if buffer[position] != rune('k') {
-						goto l197
+						goto l227
 					}
 					position++
-					goto l196
-				l197:
-					position, tokenIndex = position196, tokenIndex196
+					goto l226
+				l227:
+					position, tokenIndex = position226, tokenIndex226
 					if buffer[position] != rune('%') {
-						goto l198
+						goto l228
 					}
 					position++
 					if buffer[position] != rune('k') {
-						goto l198
+						goto l228
 					}
 					position++
 					if buffer[position] != rune('e') {
-						goto l198
+						goto l228
 					}
 					position++
 					if buffer[position] != rune('y') {
-						goto l198
+						goto l228
 					}
 					position++
-					goto l196
-				l198:
-					position, tokenIndex = position196, tokenIndex196
+					goto l226
+				l228:
+					position, tokenIndex = position226, tokenIndex226
 					if buffer[position] != rune('%') {
-						goto l199
+						goto l229
 					}
 					position++
 					if buffer[position] != rune('p') {
-						goto l199
+						goto l229
 					}
 					position++
 					if buffer[position] != rune('a') {
-						goto l199
+						goto l229
 					}
 					position++
 					if buffer[position] != rune('r') {
-						goto l199
+						goto l229
 					}
 					position++
 					if buffer[position] != rune('e') {
-						goto l199
+						goto l229
 					}
 					position++
 					if buffer[position] != rune('n') {
-						goto l199
+						goto l229
 					}
 					position++
 					if buffer[position] != rune('t') {
-						goto l199
+						goto l229
 					}
 					position++
-					goto l196
-				l199:
-					position, tokenIndex = position196, tokenIndex196
+					goto l226
+				l229:
+					position, tokenIndex = position226, tokenIndex226
 					if buffer[position] != rune('%') {
-						goto l200
+						goto l230
 					}
 					position++
 					if buffer[position] != rune('n') {
-						goto l200
+						goto l230
 					}
 					position++
 					if buffer[position] != rune('o') {
-						goto l200
+						goto l230
 					}
 					position++
 					if buffer[position] != rune('n') {
-						goto l200
+						goto l230
 					}
 					position++
 					if buffer[position] != rune('e') {
-						goto l200
+						goto l230
 					}
 					position++
-					goto l196
-				l200:
-					position, tokenIndex = position196, tokenIndex196
+					goto l226
+				l230:
+					position, tokenIndex = position226, tokenIndex226
 					if buffer[position] != rune('%') {
-						goto l194
+						goto l224
 					}
 					position++
 					if buffer[position] != rune('u') {
-						goto l194
+						goto l224
 					}
 					position++
 					if buffer[position] != rune('n') {
-						goto l194
+						goto l224
 					}
 					position++
 					if buffer[position] != rune('k') {
-						goto l194
+						goto l224
 					}
 					position++
 					if buffer[position] != rune('n') {
-						goto l194
+						goto l224
 					}
 					position++
 					if buffer[position] != rune('o') {
-						goto l194
+						goto l224
 					}
 					position++
 					if buffer[position] != rune('w') {
-						goto l194
+						goto l224
 					}
 					position++
 					if buffer[position] != rune('n') {
-						goto l194
+						goto l224
 					}
 					position++
 				}
-			l196:
+			l226:
 				if !_rules[rule_]() {
-					goto l194
+					goto l224
 				}
-				add(ruleStaticIndexKey, position195)
+				add(ruleStaticIndexKey, position225)
 			}
 			return true
-		l194:
-			position, tokenIndex = position194, tokenIndex194
+		l224:
+			position, tokenIndex = position224, tokenIndex224
 			return false
 		},
-		/* 40 LiteralType <- <(String / Number / Boolean)> */
+		/* 44 LiteralType <- <(String / Number / Boolean)> */
 		func() bool {
-			position201, tokenIndex201 := position, tokenIndex
+			position231, tokenIndex231 := position, tokenIndex
 			{
-				position202 := position
+				position232 := position
 				{
-					position203, tokenIndex203 := position, tokenIndex
+					position233, tokenIndex233 := position, tokenIndex
 					if !_rules[ruleString]() {
-						goto l204
+						goto l234
 					}
-					goto l203
-				l204:
-					position, tokenIndex = position203, tokenIndex203
+					goto l233
+				l234:
+					position, tokenIndex = position233, tokenIndex233
 					if !_rules[ruleNumber]() {
-						goto l205
+						goto l235
 					}
-					goto l203
-				l205:
-					position, tokenIndex = position203, tokenIndex203
+					goto l233
+				l235:
+					position, tokenIndex = position233, tokenIndex233
 					if !_rules[ruleBoolean]() {
-						goto l201
+						goto l231
 					}
 				}
-			l203:
-				add(ruleLiteralType, position202)
+			l233:
+				add(ruleLiteralType, position232)
 			}
 			return true
-		l201:
-			position, tokenIndex = position201, tokenIndex201
+		l231:
+			position, tokenIndex = position231, tokenIndex231
 			return false
 		},
-		/* 41 ArrayConstraint <- <(AT (Range / Number))> */
+		/* 45 ArrayConstraint <- <(AT (Range / Number))> */
 		func() bool {
-			position206, tokenIndex206 := position, tokenIndex
+			position236, tokenIndex236 := position, tokenIndex
 			{
-				position207 := position
+				position237 := position
 				if !_rules[ruleAT]() {
-					goto l206
+					goto l236
 				}
 				{
-					position208, tokenIndex208 := position, tokenIndex
+					position238, tokenIndex238 := position, tokenIndex
 					if !_rules[ruleRange]() {
-						goto l209
+						goto l239
 					}
-					goto l208
-				l209:
-					position, tokenIndex = position208, tokenIndex208
+					goto l238
+				l239:
+					position, tokenIndex = position238, tokenIndex238
 					if !_rules[ruleNumber]() {
-						goto l206
+						goto l236
 					}
 				}
-			l208:
-				add(ruleArrayConstraint, position207)
+			l238:
+				add(ruleArrayConstraint, position237)
 			}
 			return true
-		l206:
-			position, tokenIndex = position206, tokenIndex206
+		l236:
+			position, tokenIndex = position236, tokenIndex236
 			return false
 		},
-		/* 42 Range <- <((Number RangeOperator Number) / (Number RangeOperator) / (RangeOperator Number))> */
+		/* 46 Range <- <((Number RangeOperator Number) / (Number RangeOperator) / (RangeOperator Number))> */
 		func() bool {
-			position210, tokenIndex210 := position, tokenIndex
+			position240, tokenIndex240 := position, tokenIndex
 			{
-				position211 := position
+				position241 := position
 				{
-					position212, tokenIndex212 := position, tokenIndex
+					position242, tokenIndex242 := position, tokenIndex
 					if !_rules[ruleNumber]() {
-						goto l213
+						goto l243
 					}
 					if !_rules[ruleRangeOperator]() {
-						goto l213
+						goto l243
 					}
 					if !_rules[ruleNumber]() {
-						goto l213
+						goto l243
 					}
-					goto l212
-				l213:
-					position, tokenIndex = position212, tokenIndex212
+					goto l242
+				l243:
+					position, tokenIndex = position242, tokenIndex242
 					if !_rules[ruleNumber]() {
-						goto l214
+						goto l244
 					}
 					if !_rules[ruleRangeOperator]() {
-						goto l214
+						goto l244
 					}
-					goto l212
-				l214:
-					position, tokenIndex = position212, tokenIndex212
+					goto l242
+				l244:
+					position, tokenIndex = position242, tokenIndex242
 					if !_rules[ruleRangeOperator]() {
-						goto l210
+						goto l240
 					}
 					if !_rules[ruleNumber]() {
-						goto l210
+						goto l240
 					}
 				}
-			l212:
-				add(ruleRange, position211)
+			l242:
+				add(ruleRange, position241)
 			}
 			return true
-		l210:
-			position, tokenIndex = position210, tokenIndex210
+		l240:
+			position, tokenIndex = position240, tokenIndex240
 			return false
 		},
-		/* 43 RangeOperator <- <(LT? DOTDOT LT?)> */
+		/* 47 RangeOperator <- <(LT? DOTDOT LT?)> */
 		func() bool {
-			position215, tokenIndex215 := position, tokenIndex
+			position245, tokenIndex245 := position, tokenIndex
 			{
-				position216 := position
+				position246 := position
 				{
-					position217, tokenIndex217 := position, tokenIndex
+					position247, tokenIndex247 := position, tokenIndex
 					if !_rules[ruleLT]() {
-						goto l217
+						goto l247
 					}
-					goto l218
-				l217:
-					position, tokenIndex = position217, tokenIndex217
+					goto l248
+				l247:
+					position, tokenIndex = position247, tokenIndex247
 				}
-			l218:
+			l248:
 				if !_rules[ruleDOTDOT]() {
-					goto l215
+					goto l245
 				}
 				{
-					position219, tokenIndex219 := position, tokenIndex
+					position249, tokenIndex249 := position, tokenIndex
 					if !_rules[ruleLT]() {
-						goto l219
+						goto l249
 					}
-					goto l220
-				l219:
-					position, tokenIndex = position219, tokenIndex219
+					goto l250
+				l249:
+					position, tokenIndex = position249, tokenIndex249
 				}
-			l220:
-				add(ruleRangeOperator, position216)
+			l250:
+				add(ruleRangeOperator, position246)
 			}
 			return true
-		l215:
-			position, tokenIndex = position215, tokenIndex215
+		l245:
+			position, tokenIndex = position245, tokenIndex245
 			return false
 		},
-		/* 44 Attribute <- <('#' LBRACKET AttributeList RBRACKET)> */
+		/* 48 Attribute <- <('#' LBRACKET AttributeList RBRACKET)> */
 		func() bool {
-			position221, tokenIndex221 := position, tokenIndex
+			position251, tokenIndex251 := position, tokenIndex
 			{
-				position222 := position
+				position252 := position
 				if buffer[position] != rune('#') {
-					goto l221
+					goto l251
 				}
 				position++
 				if !_rules[ruleLBRACKET]() {
-					goto l221
+					goto l251
 				}
 				if !_rules[ruleAttributeList]() {
-					goto l221
+					goto l251
 				}
 				if !_rules[ruleRBRACKET]() {
-					goto l221
+					goto l251
 				}
-				add(ruleAttribute, position222)
+				add(ruleAttribute, position252)
 			}
 			return true
-		l221:
-			position, tokenIndex = position221, tokenIndex221
+		l251:
+			position, tokenIndex = position251, tokenIndex251
 			return false
 		},
-		/* 45 AttributeList <- <(AttributeItem (COMMA AttributeItem)*)> */
+		/* 49 AttributeList <- <(AttributeItem (COMMA AttributeItem)*)> */
 		func() bool {
-			position223, tokenIndex223 := position, tokenIndex
+			position253, tokenIndex253 := position, tokenIndex
 			{
-				position224 := position
+				position254 := position
 				if !_rules[ruleAttributeItem]() {
-					goto l223
+					goto l253
 				}
-			l225:
+			l255:
 				{
-					position226, tokenIndex226 := position, tokenIndex
+					position256, tokenIndex256 := position, tokenIndex
 					if !_rules[ruleCOMMA]() {
-						goto l226
+						goto l256
 					}
 					if !_rules[ruleAttributeItem]() {
-						goto l226
+						goto l256
 					}
-					goto l225
-				l226:
-					position, tokenIndex = position226, tokenIndex226
+					goto l255
+				l256:
+					position, tokenIndex = position256, tokenIndex256
 				}
-				add(ruleAttributeList, position224)
+				add(ruleAttributeList, position254)
 			}
 			return true
-		l223:
-			position, tokenIndex = position223, tokenIndex223
+		l253:
+			position, tokenIndex = position253, tokenIndex253
 			return false
 		},
-		/* 46 AttributeItem <- <(AttributePair / AttributeCall / AttributeCallWithEquals / Identifier)> */
+		/* 50 AttributeItem <- <(AttributePair / AttributeCall / AttributeCallWithEquals / Identifier)> */
 		func() bool {
-			position227, tokenIndex227 := position, tokenIndex
+			position257, tokenIndex257 := position, tokenIndex
 			{
-				position228 := position
+				position258 := position
 				{
-					position229, tokenIndex229 := position, tokenIndex
+					position259, tokenIndex259 := position, tokenIndex
 					if !_rules[ruleAttributePair]() {
-						goto l230
+						goto l260
 					}
-					goto l229
-				l230:
-					position, tokenIndex = position229, tokenIndex229
+					goto l259
+				l260:
+					position, tokenIndex = position259, tokenIndex259
 					if !_rules[ruleAttributeCall]() {
-						goto l231
+						goto l261
 					}
-					goto l229
-				l231:
-					position, tokenIndex = position229, tokenIndex229
+					goto l259
+				l261:
+					position, tokenIndex = position259, tokenIndex259
 					if !_rules[ruleAttributeCallWithEquals]() {
-						goto l232
+						goto l262
 					}
-					goto l229
-				l232:
-					position, tokenIndex = position229, tokenIndex229
+					goto l259
+				l262:
+					position, tokenIndex = position259, tokenIndex259
 					if !_rules[ruleIdentifier]() {
-						goto l227
+						goto l257
 					}
 				}
-			l229:
-				add(ruleAttributeItem, position228)
+			l259:
+				add(ruleAttributeItem, position258)
 			}
 			return true
-		l227:
-			position, tokenIndex = position227, tokenIndex227
+		l257:
+			position, tokenIndex = position257, tokenIndex257
 			return false
 		},
-		/* 47 AttributeCallWithEquals <- <(Identifier EQUALS LPAREN AttributeParamList? RPAREN)> */
+		/* 51 AttributeCallWithEquals <- <(Identifier EQUALS LPAREN AttributeParamList? RPAREN)> */
 		func() bool {
-			position233, tokenIndex233 := position, tokenIndex
+			position263, tokenIndex263 := position, tokenIndex
 			{
-				position234 := position
+				position264 := position
 				if !_rules[ruleIdentifier]() {
-					goto l233
+					goto l263
 				}
 				if !_rules[ruleEQUALS]() {
-					goto l233
+					goto l263
 				}
 				if !_rules[ruleLPAREN]() {
-					goto l233
+					goto l263
 				}
 				{
-					position235, tokenIndex235 := position, tokenIndex
+					position265, tokenIndex265 := position, tokenIndex
 					if !_rules[ruleAttributeParamList]() {
-						goto l235
+						goto l265
 					}
-					goto l236
-				l235:
-					position, tokenIndex = position235, tokenIndex235
+					goto l266
+				l265:
+					position, tokenIndex = position265, tokenIndex265
 				}
-			l236:
+			l266:
 				if !_rules[ruleRPAREN]() {
-					goto l233
+					goto l263
 				}
-				add(ruleAttributeCallWithEquals, position234)
+				add(ruleAttributeCallWithEquals, position264)
 			}
 			return true
-		l233:
-			position, tokenIndex = position233, tokenIndex233
+		l263:
+			position, tokenIndex = position263, tokenIndex263
 			return false
 		},
-		/* 48 AttributeCall <- <(Identifier LPAREN AttributeParamList? RPAREN)> */
+		/* 52 AttributeCall <- <(Identifier LPAREN AttributeParamList? RPAREN)> */
 		func() bool {
-			position237, tokenIndex237 := position, tokenIndex
+			position267, tokenIndex267 := position, tokenIndex
 			{
-				position238 := position
+				position268 := position
 				if !_rules[ruleIdentifier]() {
-					goto l237
+					goto l267
 				}
 				if !_rules[ruleLPAREN]() {
-					goto l237
+					goto l267
 				}
 				{
-					position239, tokenIndex239 := position, tokenIndex
+					position269, tokenIndex269 := position, tokenIndex
 					if !_rules[ruleAttributeParamList]() {
-						goto l239
+						goto l269
 					}
-					goto l240
-				l239:
-					position, tokenIndex = position239, tokenIndex239
+					goto l270
+				l269:
+					position, tokenIndex = position269, tokenIndex269
 				}
-			l240:
+			l270:
 				if !_rules[ruleRPAREN]() {
-					goto l237
+					goto l267
 				}
-				add(ruleAttributeCall, position238)
+				add(ruleAttributeCall, position268)
 			}
 			return true
-		l237:
-			position, tokenIndex = position237, tokenIndex237
+		l267:
+			position, tokenIndex = position267, tokenIndex267
 			return false
 		},
-		/* 49 AttributeParamList <- <(AttributeParam (COMMA AttributeParam)*)> */
+		/* 53 AttributeParamList <- <(AttributeParam (COMMA AttributeParam)*)> */
 		func() bool {
-			position241, tokenIndex241 := position, tokenIndex
+			position271, tokenIndex271 := position, tokenIndex
 			{
-				position242 := position
+				position272 := position
 				if !_rules[ruleAttributeParam]() {
-					goto l241
+					goto l271
 				}
-			l243:
+			l273:
 				{
-					position244, tokenIndex244 := position, tokenIndex
+					position274, tokenIndex274 := position, tokenIndex
 					if !_rules[ruleCOMMA]() {
-						goto l244
+						goto l274
 					}
 					if !_rules[ruleAttributeParam]() {
-						goto l244
+						goto l274
 					}
-					goto l243
-				l244:
-					position, tokenIndex = position244, tokenIndex244
+					goto l273
+				l274:
+					position, tokenIndex = position274, tokenIndex274
 				}
-				add(ruleAttributeParamList, position242)
+				add(ruleAttributeParamList, position272)
 			}
 			return true
-		l241:
-			position, tokenIndex = position241, tokenIndex241
+		l271:
+			position, tokenIndex = position271, tokenIndex271
 			return false
 		},
-		/* 50 AttributeParam <- <(AttributePair / AttributeValue)> */
+		/* 54 AttributeParam <- <(AttributePair / AttributeValue)> */
 		func() bool {
-			position245, tokenIndex245 := position, tokenIndex
+			position275, tokenIndex275 := position, tokenIndex
 			{
-				position246 := position
+				position276 := position
 				{
-					position247, tokenIndex247 := position, tokenIndex
+					position277, tokenIndex277 := position, tokenIndex
 					if !_rules[ruleAttributePair]() {
-						goto l248
+						goto l278
 					}
-					goto l247
-				l248:
-					position, tokenIndex = position247, tokenIndex247
+					goto l277
+				l278:
+					position, tokenIndex = position277, tokenIndex277
 					if !_rules[ruleAttributeValue]() {
-						goto l245
+						goto l275
 					}
 				}
-			l247:
-				add(ruleAttributeParam, position246)
+			l277:
+				add(ruleAttributeParam, position276)
 			}
 			return true
-		l245:
-			position, tokenIndex = position245, tokenIndex245
+		l275:
+			position, tokenIndex = position275, tokenIndex275
 			return false
 		},
-		/* 51 AttributePair <- <(Identifier EQUALS AttributeValue)> */
+		/* 55 AttributePair <- <(Identifier EQUALS AttributeValue)> */
 		func() bool {
-			position249, tokenIndex249 := position, tokenIndex
+			position279, tokenIndex279 := position, tokenIndex
 			{
-				position250 := position
+				position280 := position
 				if !_rules[ruleIdentifier]() {
-					goto l249
+					goto l279
 				}
 				if !_rules[ruleEQUALS]() {
-					goto l249
+					goto l279
 				}
 				if !_rules[ruleAttributeValue]() {
-					goto l249
+					goto l279
 				}
-				add(ruleAttributePair, position250)
+				add(ruleAttributePair, position280)
 			}
 			return true
-		l249:
-			position, tokenIndex = position249, tokenIndex249
+		l279:
+			position, tokenIndex = position279, tokenIndex279
 			return false
 		},
-		/* 52 AttributeValue <- <(ArrayLiteral / ComplexReference / String / Number / Boolean / Identifier)> */
+		/* 56 AttributeValue <- <(ArrayLiteral / ComplexReference / String / Number / Boolean / Identifier)> */
 		func() bool {
-			position251, tokenIndex251 := position, tokenIndex
+			position281, tokenIndex281 := position, tokenIndex
 			{
-				position252 := position
+				position282 := position
 				{
-					position253, tokenIndex253 := position, tokenIndex
+					position283, tokenIndex283 := position, tokenIndex
 					if !_rules[ruleArrayLiteral]() {
-						goto l254
+						goto l284
 					}
-					goto l253
-				l254:
-					position, tokenIndex = position253, tokenIndex253
+					goto l283
+				l284:
+					position, tokenIndex = position283, tokenIndex283
 					if !_rules[ruleComplexReference]() {
-						goto l255
+						goto l285
 					}
-					goto l253
-				l255:
-					position, tokenIndex = position253, tokenIndex253
+					goto l283
+				l285:
+					position, tokenIndex = position283, tokenIndex283
 					if !_rules[ruleString]() {
-						goto l256
+						goto l286
 					}
-					goto l253
-				l256:
-					position, tokenIndex = position253, tokenIndex253
+					goto l283
+				l286:
+					position, tokenIndex = position283, tokenIndex283
 					if !_rules[ruleNumber]() {
-						goto l257
+						goto l287
 					}
-					goto l253
-				l257:
-					position, tokenIndex = position253, tokenIndex253
+					goto l283
+				l287:
+					position, tokenIndex = position283, tokenIndex283
 					if !_rules[ruleBoolean]() {
-						goto l258
+						goto l288
 					}
-					goto l253
-				l258:
-					position, tokenIndex = position253, tokenIndex253
+					goto l283
+				l288:
+					position, tokenIndex = position283, tokenIndex283
 					if !_rules[ruleIdentifier]() {
-						goto l251
+						goto l281
 					}
 				}
-			l253:
-				add(ruleAttributeValue, position252)
+			l283:
+				add(ruleAttributeValue, position282)
 			}
 			return true
-		l251:
-			position, tokenIndex = position251, tokenIndex251
+		l281:
+			position, tokenIndex = position281, tokenIndex281
 			return false
 		},
-		/* 53 ArrayLiteral <- <(LBRACKET (AttributeValue (COMMA AttributeValue)*)? RBRACKET)> */
+		/* 57 ArrayLiteral <- <(LBRACKET (AttributeValue (COMMA AttributeValue)*)? RBRACKET)> */
 		func() bool {
-			position259, tokenIndex259 := position, tokenIndex
+			position289, tokenIndex289 := position, tokenIndex
 			{
-				position260 := position
+				position290 := position
 				if !_rules[ruleLBRACKET]() {
-					goto l259
+					goto l289
 				}
 				{
-					position261, tokenIndex261 := position, tokenIndex
+					position291, tokenIndex291 := position, tokenIndex
 					if !_rules[ruleAttributeValue]() {
-						goto l261
+						goto l291
 					}
-				l263:
+				l293:
 					{
-						position264, tokenIndex264 := position, tokenIndex
+						position294, tokenIndex294 := position, tokenIndex
 						if !_rules[ruleCOMMA]() {
-							goto l264
+							goto l294
 						}
 						if !_rules[ruleAttributeValue]() {
-							goto l264
+							goto l294
 						}
-						goto l263
-					l264:
-						position, tokenIndex = position264, tokenIndex264
+						goto l293
+					l294:
+						position, tokenIndex = position294, tokenIndex294
 					}
-					goto l262
-				l261:
-					position, tokenIndex = position261, tokenIndex261
+					goto l292
+				l291:
+					position, tokenIndex = position291, tokenIndex291
 				}
-			l262:
+			l292:
 				if !_rules[ruleRBRACKET]() {
-					goto l259
+					goto l289
 				}
-				add(ruleArrayLiteral, position260)
+				add(ruleArrayLiteral, position290)
 			}
 			return true
-		l259:
-			position, tokenIndex = position259, tokenIndex259
+		l289:
+			position, tokenIndex = position289, tokenIndex289
 			return false
 		},
-		/* 54 Comment <- <('/' '/' (!EOL .)* (EOL / !.))> */
+		/* 58 Comment <- <('/' '/' (!EOL .)* (EOL / !.))> */
 		func() bool {
-			position265, tokenIndex265 := position, tokenIndex
+			position295, tokenIndex295 := position, tokenIndex
 			{
-				position266 := position
+				position296 := position
 				if buffer[position] != rune('/') {
-					goto l265
+					goto l295
 				}
 				position++
 				if buffer[position] != rune('/') {
-					goto l265
+					goto l295
 				}
 				position++
-			l267:
+			l297:
 				{
-					position268, tokenIndex268 := position, tokenIndex
+					position298, tokenIndex298 := position, tokenIndex
 					{
-						position269, tokenIndex269 := position, tokenIndex
+						position299, tokenIndex299 := position, tokenIndex
 						if !_rules[ruleEOL]() {
-							goto l269
+							goto l299
 						}
-						goto l268
-					l269:
-						position, tokenIndex = position269, tokenIndex269
+						goto l298
+					l299:
+						position, tokenIndex = position299, tokenIndex299
 					}
 					if !matchDot() {
-						goto l268
+						goto l298
 					}
-					goto l267
-				l268:
-					position, tokenIndex = position268, tokenIndex268
+					goto l297
+				l298:
+					position, tokenIndex = position298, tokenIndex298
 				}
 				{
-					position270, tokenIndex270 := position, tokenIndex
+					position300, tokenIndex300 := position, tokenIndex
 					if !_rules[ruleEOL]() {
-						goto l271
+						goto l301
 					}
-					goto l270
-				l271:
-					position, tokenIndex = position270, tokenIndex270
+					goto l300
+				l301:
+					position, tokenIndex = position300, tokenIndex300
 					{
-						position272, tokenIndex272 := position, tokenIndex
+						position302, tokenIndex302 := position, tokenIndex
 						if !matchDot() {
-							goto l272
+							goto l302
 						}
-						goto l265
-					l272:
-						position, tokenIndex = position272, tokenIndex272
+						goto l295
+					l302:
+						position, tokenIndex = position302, tokenIndex302
 					}
 				}
-			l270:
-				add(ruleComment, position266)
+			l300:
+				add(ruleComment, position296)
 			}
 			return true
-		l265:
-			position, tokenIndex = position265, tokenIndex265
+		l295:
+			position, tokenIndex = position295, tokenIndex295
 			return false
 		},
-		/* 55 DocComment <- <('/' '/' '/' (!EOL .)* (EOL / !.))> */
+		/* 59 DocComment <- <('/' '/' '/' (!EOL .)* (EOL / !.))> */
 		func() bool {
-			position273, tokenIndex273 := position, tokenIndex
+			position303, tokenIndex303 := position, tokenIndex
 			{
-				position274 := position
+				position304 := position
 				if buffer[position] != rune('/') {
-					goto l273
+					goto l303
 				}
 				position++
 				if buffer[position] != rune('/') {
-					goto l273
+					goto l303
 				}
 				position++
 				if buffer[position] != rune('/') {
-					goto l273
+					goto l303
 				}
 				position++
-			l275:
+			l305:
 				{
-					position276, tokenIndex276 := position, tokenIndex
+					position306, tokenIndex306 := position, tokenIndex
 					{
-						position277, tokenIndex277 := position, tokenIndex
+						position307, tokenIndex307 := position, tokenIndex
 						if !_rules[ruleEOL]() {
-							goto l277
+							goto l307
 						}
-						goto l276
-					l277:
-						position, tokenIndex = position277, tokenIndex277
+						goto l306
+					l307:
+						position, tokenIndex = position307, tokenIndex307
 					}
 					if !matchDot() {
-						goto l276
+						goto l306
 					}
-					goto l275
-				l276:
-					position, tokenIndex = position276, tokenIndex276
+					goto l305
+				l306:
+					position, tokenIndex = position306, tokenIndex306
 				}
 				{
-					position278, tokenIndex278 := position, tokenIndex
+					position308, tokenIndex308 := position, tokenIndex
 					if !_rules[ruleEOL]() {
-						goto l279
+						goto l309
 					}
-					goto l278
-				l279:
-					position, tokenIndex = position278, tokenIndex278
+					goto l308
+				l309:
+					position, tokenIndex = position308, tokenIndex308
 					{
-						position280, tokenIndex280 := position, tokenIndex
+						position310, tokenIndex310 := position, tokenIndex
 						if !matchDot() {
-							goto l280
+							goto l310
 						}
-						goto l273
-					l280:
-						position, tokenIndex = position280, tokenIndex280
+						goto l303
+					l310:
+						position, tokenIndex = position310, tokenIndex310
 					}
 				}
-			l278:
-				add(ruleDocComment, position274)
+			l308:
+				add(ruleDocComment, position304)
 			}
 			return true
-		l273:
-			position, tokenIndex = position273, tokenIndex273
+		l303:
+			position, tokenIndex = position303, tokenIndex303
 			return false
 		},
-		/* 56 Identifier <- <(<(([a-z] / [A-Z] / '_') ([a-z] / [A-Z] / [0-9] / '_')*)> _ Action13)> */
+		/* 60 Identifier <- <(<(([a-z] / [A-Z] / '_') ([a-z] / [A-Z] / [0-9] / '_')*)> _ Action13)> */
 		func() bool {
-			position281, tokenIndex281 := position, tokenIndex
+			position311, tokenIndex311 := position, tokenIndex
 			{
-				position282 := position
+				position312 := position
 				{
-					position283 := position
+					position313 := position
 					{
-						position284, tokenIndex284 := position, tokenIndex
+						position314, tokenIndex314 := position, tokenIndex
 						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l285
+							goto l315
 						}
 						position++
-						goto l284
-					l285:
-						position, tokenIndex = position284, tokenIndex284
+						goto l314
+					l315:
+						position, tokenIndex = position314, tokenIndex314
 						if c := buffer[position]; c < rune('A') || c > rune('Z') {
-							goto l286
+							goto l316
 						}
 						position++
-						goto l284
-					l286:
-						position, tokenIndex = position284, tokenIndex284
+						goto l314
+					l316:
+						position, tokenIndex = position314, tokenIndex314
 						if buffer[position] != rune('_') {
-							goto l281
+							goto l311
 						}
 						position++
 					}
-				l284:
-				l287:
+				l314:
+				l317:
 					{
-						position288, tokenIndex288 := position, tokenIndex
+						position318, tokenIndex318 := position, tokenIndex
 						{
-							position289, tokenIndex289 := position, tokenIndex
+							position319, tokenIndex319 := position, tokenIndex
 							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l290
+								goto l320
 							}
 							position++
-							goto l289
-						l290:
-							position, tokenIndex = position289, tokenIndex289
+							goto l319
+						l320:
+							position, tokenIndex = position319, tokenIndex319
 							if c := buffer[position]; c < rune('A') || c > rune('Z') {
-								goto l291
+								goto l321
 							}
 							position++
-							goto l289
-						l291:
-							position, tokenIndex = position289, tokenIndex289
+							goto l319
+						l321:
+							position, tokenIndex = position319, tokenIndex319
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l292
+								goto l322
 							}
 							position++
-							goto l289
-						l292:
-							position, tokenIndex = position289, tokenIndex289
+							goto l319
+						l322:
+							position, tokenIndex = position319, tokenIndex319
 							if buffer[position] != rune('_') {
-								goto l288
+								goto l318
 							}
 							position++
 						}
-					l289:
-						goto l287
-					l288:
-						position, tokenIndex = position288, tokenIndex288
+					l319:
+						goto l317
+					l318:
+						position, tokenIndex = position318, tokenIndex318
 					}
-					add(rulePegText, position283)
+					add(rulePegText, position313)
 				}
 				if !_rules[rule_]() {
-					goto l281
+					goto l311
 				}
 				if !_rules[ruleAction13]() {
-					goto l281
+					goto l311
 				}
-				add(ruleIdentifier, position282)
+				add(ruleIdentifier, position312)
 			}
 			return true
-		l281:
-			position, tokenIndex = position281, tokenIndex281
+		l311:
+			position, tokenIndex = position311, tokenIndex311
 			return false
 		},
-		/* 57 String <- <(<('"' (!'"' .)* '"')> _ Action14)> */
+		/* 61 String <- <(<('"' (!'"' .)* '"')> _ Action14)> */
 		func() bool {
-			position293, tokenIndex293 := position, tokenIndex
+			position323, tokenIndex323 := position, tokenIndex
 			{
-				position294 := position
+				position324 := position
 				{
-					position295 := position
+					position325 := position
 					if buffer[position] != rune('"') {
-						goto l293
+						goto l323
 					}
 					position++
-				l296:
+				l326:
 					{
-						position297, tokenIndex297 := position, tokenIndex
+						position327, tokenIndex327 := position, tokenIndex
 						{
-							position298, tokenIndex298 := position, tokenIndex
+							position328, tokenIndex328 := position, tokenIndex
 							if buffer[position] != rune('"') {
-								goto l298
+								goto l328
 							}
 							position++
-							goto l297
-						l298:
-							position, tokenIndex = position298, tokenIndex298
+							goto l327
+						l328:
+							position, tokenIndex = position328, tokenIndex328
 						}
 						if !matchDot() {
-							goto l297
+							goto l327
 						}
-						goto l296
-					l297:
-						position, tokenIndex = position297, tokenIndex297
+						goto l326
+					l327:
+						position, tokenIndex = position327, tokenIndex327
 					}
 					if buffer[position] != rune('"') {
-						goto l293
+						goto l323
 					}
 					position++
-					add(rulePegText, position295)
+					add(rulePegText, position325)
 				}
 				if !_rules[rule_]() {
-					goto l293
+					goto l323
 				}
 				if !_rules[ruleAction14]() {
-					goto l293
+					goto l323
 				}
-				add(ruleString, position294)
+				add(ruleString, position324)
 			}
 			return true
-		l293:
-			position, tokenIndex = position293, tokenIndex293
+		l323:
+			position, tokenIndex = position323, tokenIndex323
 			return false
 		},
-		/* 58 Number <- <(<('-'? [0-9]+ ('.' [0-9]+)?)> _ Action15)> */
+		/* 62 Number <- <(<('-'? [0-9]+ ('.' [0-9]+)?)> _ Action15)> */
 		func() bool {
-			position299, tokenIndex299 := position, tokenIndex
+			position329, tokenIndex329 := position, tokenIndex
 			{
-				position300 := position
+				position330 := position
 				{
-					position301 := position
+					position331 := position
 					{
-						position302, tokenIndex302 := position, tokenIndex
+						position332, tokenIndex332 := position, tokenIndex
 						if buffer[position] != rune('-') {
-							goto l302
+							goto l332
 						}
 						position++
-						goto l303
-					l302:
-						position, tokenIndex = position302, tokenIndex302
+						goto l333
+					l332:
+						position, tokenIndex = position332, tokenIndex332
 					}
-				l303:
+				l333:
 					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l299
+						goto l329
 					}
 					position++
-				l304:
+				l334:
 					{
-						position305, tokenIndex305 := position, tokenIndex
+						position335, tokenIndex335 := position, tokenIndex
 						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l305
+							goto l335
 						}
 						position++
-						goto l304
-					l305:
-						position, tokenIndex = position305, tokenIndex305
+						goto l334
+					l335:
+						position, tokenIndex = position335, tokenIndex335
 					}
 					{
-						position306, tokenIndex306 := position, tokenIndex
+						position336, tokenIndex336 := position, tokenIndex
 						if buffer[position] != rune('.') {
-							goto l306
+							goto l336
 						}
 						position++
 						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l306
+							goto l336
 						}
 						position++
-					l308:
+					l338:
 						{
-							position309, tokenIndex309 := position, tokenIndex
+							position339, tokenIndex339 := position, tokenIndex
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l309
+								goto l339
 							}
 							position++
-							goto l308
-						l309:
-							position, tokenIndex = position309, tokenIndex309
+							goto l338
+						l339:
+							position, tokenIndex = position339, tokenIndex339
 						}
-						goto l307
-					l306:
-						position, tokenIndex = position306, tokenIndex306
+						goto l337
+					l336:
+						position, tokenIndex = position336, tokenIndex336
 					}
-				l307:
-					add(rulePegText, position301)
+				l337:
+					add(rulePegText, position331)
 				}
 				if !_rules[rule_]() {
-					goto l299
+					goto l329
 				}
 				if !_rules[ruleAction15]() {
-					goto l299
+					goto l329
 				}
-				add(ruleNumber, position300)
+				add(ruleNumber, position330)
 			}
 			return true
-		l299:
-			position, tokenIndex = position299, tokenIndex299
+		l329:
+			position, tokenIndex = position329, tokenIndex329
 			return false
 		},
-		/* 59 Boolean <- <(<(('t' 'r' 'u' 'e') / ('f' 'a' 'l' 's' 'e'))> _ Action16)> */
+		/* 63 Boolean <- <(<(('t' 'r' 'u' 'e') / ('f' 'a' 'l' 's' 'e'))> _ Action16)> */
 		func() bool {
-			position310, tokenIndex310 := position, tokenIndex
+			position340, tokenIndex340 := position, tokenIndex
 			{
-				position311 := position
+				position341 := position
 				{
-					position312 := position
+					position342 := position
 					{
-						position313, tokenIndex313 := position, tokenIndex
+						position343, tokenIndex343 := position, tokenIndex
 						if buffer[position] != rune('t') {
-							goto l314
+							goto l344
 						}
 						position++
 						if buffer[position] != rune('r') {
-							goto l314
+							goto l344
 						}
 						position++
 						if buffer[position] != rune('u') {
-							goto l314
+							goto l344
 						}
 						position++
 						if buffer[position] != rune('e') {
-							goto l314
+							goto l344
 						}
 						position++
-						goto l313
-					l314:
-						position, tokenIndex = position313, tokenIndex313
+						goto l343
+					l344:
+						position, tokenIndex = position343, tokenIndex343
 						if buffer[position] != rune('f') {
-							goto l310
+							goto l340
 						}
 						position++
 						if buffer[position] != rune('a') {
-							goto l310
+							goto l340
 						}
 						position++
 						if buffer[position] != rune('l') {
-							goto l310
+							goto l340
 						}
 						position++
 						if buffer[position] != rune('s') {
-							goto l310
+							goto l340
 						}
 						position++
 						if buffer[position] != rune('e') {
-							goto l310
+							goto l340
 						}
 						position++
 					}
-				l313:
-					add(rulePegText, position312)
+				l343:
+					add(rulePegText, position342)
 				}
 				if !_rules[rule_]() {
-					goto l310
+					goto l340
 				}
 				if !_rules[ruleAction16]() {
-					goto l310
+					goto l340
 				}
-				add(ruleBoolean, position311)
+				add(ruleBoolean, position341)
 			}
 			return true
-		l310:
-			position, tokenIndex = position310, tokenIndex310
+		l340:
+			position, tokenIndex = position340, tokenIndex340
 			return false
 		},
-		/* 60 LBRACE <- <('{' _)> */
+		/* 64 LBRACE <- <('{' _)> */
 		func() bool {
-			position315, tokenIndex315 := position, tokenIndex
+			position345, tokenIndex345 := position, tokenIndex
 			{
-				position316 := position
+				position346 := position
 				if buffer[position] != rune('{') {
-					goto l315
+					goto l345
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l315
+					goto l345
 				}
-				add(ruleLBRACE, position316)
+				add(ruleLBRACE, position346)
 			}
 			return true
-		l315:
-			position, tokenIndex = position315, tokenIndex315
+		l345:
+			position, tokenIndex = position345, tokenIndex345
 			return false
 		},
-		/* 61 RBRACE <- <('}' _)> */
+		/* 65 RBRACE <- <('}' _)> */
 		func() bool {
-			position317, tokenIndex317 := position, tokenIndex
+			position347, tokenIndex347 := position, tokenIndex
 			{
-				position318 := position
+				position348 := position
 				if buffer[position] != rune('}') {
-					goto l317
+					goto l347
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l317
+					goto l347
 				}
-				add(ruleRBRACE, position318)
+				add(ruleRBRACE, position348)
 			}
 			return true
-		l317:
-			position, tokenIndex = position317, tokenIndex317
+		l347:
+			position, tokenIndex = position347, tokenIndex347
 			return false
 		},
-		/* 62 LBRACKET <- <('[' _)> */
+		/* 66 LBRACKET <- <('[' _)> */
 		func() bool {
-			position319, tokenIndex319 := position, tokenIndex
+			position349, tokenIndex349 := position, tokenIndex
 			{
-				position320 := position
+				position350 := position
 				if buffer[position] != rune('[') {
-					goto l319
+					goto l349
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l319
+					goto l349
 				}
-				add(ruleLBRACKET, position320)
+				add(ruleLBRACKET, position350)
 			}
 			return true
-		l319:
-			position, tokenIndex = position319, tokenIndex319
+		l349:
+			position, tokenIndex = position349, tokenIndex349
 			return false
 		},
-		/* 63 RBRACKET <- <(']' _)> */
+		/* 67 RBRACKET <- <(']' _)> */
 		func() bool {
-			position321, tokenIndex321 := position, tokenIndex
+			position351, tokenIndex351 := position, tokenIndex
 			{
-				position322 := position
+				position352 := position
 				if buffer[position] != rune(']') {
-					goto l321
+					goto l351
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l321
+					goto l351
 				}
-				add(ruleRBRACKET, position322)
+				add(ruleRBRACKET, position352)
 			}
 			return true
-		l321:
-			position, tokenIndex = position321, tokenIndex321
+		l351:
+			position, tokenIndex = position351, tokenIndex351
 			return false
 		},
-		/* 64 LPAREN <- <('(' _)> */
+		/* 68 LPAREN <- <('(' _)> */
 		func() bool {
-			position323, tokenIndex323 := position, tokenIndex
+			position353, tokenIndex353 := position, tokenIndex
 			{
-				position324 := position
+				position354 := position
 				if buffer[position] != rune('(') {
-					goto l323
+					goto l353
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l323
+					goto l353
 				}
-				add(ruleLPAREN, position324)
+				add(ruleLPAREN, position354)
 			}
 			return true
-		l323:
-			position, tokenIndex = position323, tokenIndex323
+		l353:
+			position, tokenIndex = position353, tokenIndex353
 			return false
 		},
-		/* 65 RPAREN <- <(')' _)> */
+		/* 69 RPAREN <- <(')' _)> */
 		func() bool {
-			position325, tokenIndex325 := position, tokenIndex
+			position355, tokenIndex355 := position, tokenIndex
 			{
-				position326 := position
+				position356 := position
 				if buffer[position] != rune(')') {
-					goto l325
+					goto l355
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l325
+					goto l355
 				}
-				add(ruleRPAREN, position326)
+				add(ruleRPAREN, position356)
 			}
 			return true
-		l325:
-			position, tokenIndex = position325, tokenIndex325
+		l355:
+			position, tokenIndex = position355, tokenIndex355
 			return false
 		},
-		/* 66 COMMA <- <(',' _)> */
+		/* 70 COMMA <- <(',' _)> */
 		func() bool {
-			position327, tokenIndex327 := position, tokenIndex
+			position357, tokenIndex357 := position, tokenIndex
 			{
-				position328 := position
+				position358 := position
 				if buffer[position] != rune(',') {
-					goto l327
+					goto l357
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l327
+					goto l357
 				}
-				add(ruleCOMMA, position328)
+				add(ruleCOMMA, position358)
 			}
 			return true
-		l327:
-			position, tokenIndex = position327, tokenIndex327
+		l357:
+			position, tokenIndex = position357, tokenIndex357
 			return false
 		},
-		/* 67 COLON <- <(':' _)> */
+		/* 71 COLON <- <(':' _)> */
 		func() bool {
-			position329, tokenIndex329 := position, tokenIndex
+			position359, tokenIndex359 := position, tokenIndex
 			{
-				position330 := position
+				position360 := position
 				if buffer[position] != rune(':') {
-					goto l329
+					goto l359
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l329
+					goto l359
 				}
-				add(ruleCOLON, position330)
+				add(ruleCOLON, position360)
 			}
 			return true
-		l329:
-			position, tokenIndex = position329, tokenIndex329
+		l359:
+			position, tokenIndex = position359, tokenIndex359
 			return false
 		},
-		/* 68 SEMICOLON <- <(';' _)> */
+		/* 72 SEMICOLON <- <(';' _)> */
 		nil,
-		/* 69 EQUALS <- <('=' _)> */
+		/* 73 EQUALS <- <('=' _)> */
 		func() bool {
-			position332, tokenIndex332 := position, tokenIndex
+			position362, tokenIndex362 := position, tokenIndex
 			{
-				position333 := position
+				position363 := position
 				if buffer[position] != rune('=') {
-					goto l332
+					goto l362
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l332
+					goto l362
 				}
-				add(ruleEQUALS, position333)
+				add(ruleEQUALS, position363)
 			}
 			return true
-		l332:
-			position, tokenIndex = position332, tokenIndex332
+		l362:
+			position, tokenIndex = position362, tokenIndex362
 			return false
 		},
-		/* 70 PIPE <- <('|' _)> */
+		/* 74 PIPE <- <('|' _)> */
 		func() bool {
-			position334, tokenIndex334 := position, tokenIndex
+			position364, tokenIndex364 := position, tokenIndex
 			{
-				position335 := position
+				position365 := position
 				if buffer[position] != rune('|') {
-					goto l334
+					goto l364
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l334
+					goto l364
 				}
-				add(rulePIPE, position335)
+				add(rulePIPE, position365)
 			}
 			return true
-		l334:
-			position, tokenIndex = position334, tokenIndex334
+		l364:
+			position, tokenIndex = position364, tokenIndex364
 			return false
 		},
-		/* 71 DOT <- <('.' _)> */
+		/* 75 DOT <- <('.' _)> */
 		nil,
-		/* 72 SPREAD <- <('.' '.' '.' _)> */
+		/* 76 SPREAD <- <('.' '.' '.' _)> */
 		func() bool {
-			position337, tokenIndex337 := position, tokenIndex
+			position367, tokenIndex367 := position, tokenIndex
 			{
-				position338 := position
+				position368 := position
 				if buffer[position] != rune('.') {
-					goto l337
+					goto l367
 				}
 				position++
 				if buffer[position] != rune('.') {
-					goto l337
+					goto l367
 				}
 				position++
 				if buffer[position] != rune('.') {
-					goto l337
+					goto l367
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l337
+					goto l367
 				}
-				add(ruleSPREAD, position338)
+				add(ruleSPREAD, position368)
 			}
 			return true
-		l337:
-			position, tokenIndex = position337, tokenIndex337
+		l367:
+			position, tokenIndex = position367, tokenIndex367
 			return false
 		},
-		/* 73 AT <- <('@' _)> */
+		/* 77 AT <- <('@' _)> */
 		func() bool {
-			position339, tokenIndex339 := position, tokenIndex
+			position369, tokenIndex369 := position, tokenIndex
 			{
-				position340 := position
+				position370 := position
 				if buffer[position] != rune('@') {
-					goto l339
+					goto l369
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l339
+					goto l369
 				}
-				add(ruleAT, position340)
+				add(ruleAT, position370)
 			}
 			return true
-		l339:
-			position, tokenIndex = position339, tokenIndex339
+		l369:
+			position, tokenIndex = position369, tokenIndex369
 			return false
 		},
-		/* 74 LT <- <('<' _)> */
+		/* 78 LT <- <('<' _)> */
 		func() bool {
-			position341, tokenIndex341 := position, tokenIndex
+			position371, tokenIndex371 := position, tokenIndex
 			{
-				position342 := position
+				position372 := position
 				if buffer[position] != rune('<') {
-					goto l341
+					goto l371
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l341
+					goto l371
 				}
-				add(ruleLT, position342)
+				add(ruleLT, position372)
 			}
 			return true
-		l341:
-			position, tokenIndex = position341, tokenIndex341
+		l371:
+			position, tokenIndex = position371, tokenIndex371
 			return false
 		},
-		/* 75 RT <- <('>' _)> */
+		/* 79 RT <- <('>' _)> */
 		func() bool {
-			position343, tokenIndex343 := position, tokenIndex
+			position373, tokenIndex373 := position, tokenIndex
 			{
-				position344 := position
+				position374 := position
 				if buffer[position] != rune('>') {
-					goto l343
+					goto l373
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l343
+					goto l373
 				}
-				add(ruleRT, position344)
+				add(ruleRT, position374)
 			}
 			return true
-		l343:
-			position, tokenIndex = position343, tokenIndex343
+		l373:
+			position, tokenIndex = position373, tokenIndex373
 			return false
 		},
-		/* 76 DOTDOT <- <('.' '.' _)> */
+		/* 80 DOTDOT <- <('.' '.' _)> */
 		func() bool {
-			position345, tokenIndex345 := position, tokenIndex
+			position375, tokenIndex375 := position, tokenIndex
 			{
-				position346 := position
+				position376 := position
 				if buffer[position] != rune('.') {
-					goto l345
+					goto l375
 				}
 				position++
 				if buffer[position] != rune('.') {
-					goto l345
+					goto l375
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l345
+					goto l375
 				}
-				add(ruleDOTDOT, position346)
+				add(ruleDOTDOT, position376)
 			}
 			return true
-		l345:
-			position, tokenIndex = position345, tokenIndex345
+		l375:
+			position, tokenIndex = position375, tokenIndex375
 			return false
 		},
-		/* 77 QUESTION <- <('?' _)> */
+		/* 81 QUESTION <- <('?' _)> */
 		func() bool {
-			position347, tokenIndex347 := position, tokenIndex
+			position377, tokenIndex377 := position, tokenIndex
 			{
-				position348 := position
+				position378 := position
 				if buffer[position] != rune('?') {
-					goto l347
+					goto l377
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l347
+					goto l377
 				}
-				add(ruleQUESTION, position348)
+				add(ruleQUESTION, position378)
 			}
 			return true
-		l347:
-			position, tokenIndex = position347, tokenIndex347
+		l377:
+			position, tokenIndex = position377, tokenIndex377
 			return false
 		},
-		/* 78 DoubleColon <- <(':' ':' _)> */
+		/* 82 DoubleColon <- <(':' ':' _)> */
 		func() bool {
-			position349, tokenIndex349 := position, tokenIndex
+			position379, tokenIndex379 := position, tokenIndex
 			{
-				position350 := position
+				position380 := position
 				if buffer[position] != rune(':') {
-					goto l349
+					goto l379
 				}
 				position++
 				if buffer[position] != rune(':') {
-					goto l349
+					goto l379
 				}
 				position++
 				if !_rules[rule_]() {
-					goto l349
+					goto l379
 				}
-				add(ruleDoubleColon, position350)
+				add(ruleDoubleColon, position380)
 			}
 			return true
-		l349:
-			position, tokenIndex = position349, tokenIndex349
+		l379:
+			position, tokenIndex = position379, tokenIndex379
 			return false
 		},
-		/* 79 SingleColon <- <(':' _)> */
+		/* 83 SingleColon <- <(':' _)> */
 		nil,
-		/* 80 _ <- <(' ' / '\t' / '\r' / '\n' / Comment / DocComment)*> */
+		/* 84 _ <- <(' ' / '\t' / '\r' / '\n' / Comment / DocComment)*> */
 		func() bool {
 			{
-				position353 := position
-			l354:
+				position383 := position
+			l384:
 				{
-					position355, tokenIndex355 := position, tokenIndex
+					position385, tokenIndex385 := position, tokenIndex
 					{
-						position356, tokenIndex356 := position, tokenIndex
+						position386, tokenIndex386 := position, tokenIndex
 						if buffer[position] != rune(' ') {
-							goto l357
+							goto l387
 						}
 						position++
-						goto l356
-					l357:
-						position, tokenIndex = position356, tokenIndex356
+						goto l386
+					l387:
+						position, tokenIndex = position386, tokenIndex386
 						if buffer[position] != rune('\t') {
-							goto l358
+							goto l388
 						}
 						position++
-						goto l356
-					l358:
-						position, tokenIndex = position356, tokenIndex356
+						goto l386
+					l388:
+						position, tokenIndex = position386, tokenIndex386
 						if buffer[position] != rune('\r') {
-							goto l359
+							goto l389
 						}
 						position++
-						goto l356
-					l359:
-						position, tokenIndex = position356, tokenIndex356
+						goto l386
+					l389:
+						position, tokenIndex = position386, tokenIndex386
 						if buffer[position] != rune('\n') {
-							goto l360
+							goto l390
 						}
 						position++
-						goto l356
-					l360:
-						position, tokenIndex = position356, tokenIndex356
+						goto l386
+					l390:
+						position, tokenIndex = position386, tokenIndex386
 						if !_rules[ruleComment]() {
-							goto l361
+							goto l391
 						}
-						goto l356
-					l361:
-						position, tokenIndex = position356, tokenIndex356
+						goto l386
+					l391:
+						position, tokenIndex = position386, tokenIndex386
 						if !_rules[ruleDocComment]() {
-							goto l355
+							goto l385
 						}
 					}
-				l356:
-					goto l354
-				l355:
-					position, tokenIndex = position355, tokenIndex355
+				l386:
+					goto l384
+				l385:
+					position, tokenIndex = position385, tokenIndex385
 				}
-				add(rule_, position353)
+				add(rule_, position383)
 			}
 			return true
 		},
-		/* 81 EOL <- <(('\r' '\n') / '\n' / '\r')> */
+		/* 85 EOL <- <(('\r' '\n') / '\n' / '\r')> */
 		func() bool {
-			position362, tokenIndex362 := position, tokenIndex
+			position392, tokenIndex392 := position, tokenIndex
 			{
-				position363 := position
+				position393 := position
 				{
-					position364, tokenIndex364 := position, tokenIndex
+					position394, tokenIndex394 := position, tokenIndex
 					if buffer[position] != rune('\r') {
-						goto l365
+						goto l395
 					}
 					position++
 					if buffer[position] != rune('\n') {
-						goto l365
+						goto l395
 					}
 					position++
-					goto l364
-				l365:
-					position, tokenIndex = position364, tokenIndex364
+					goto l394
+				l395:
+					position, tokenIndex = position394, tokenIndex394
 					if buffer[position] != rune('\n') {
-						goto l366
+						goto l396
 					}
 					position++
-					goto l364
-				l366:
-					position, tokenIndex = position364, tokenIndex364
+					goto l394
+				l396:
+					position, tokenIndex = position394, tokenIndex394
 					if buffer[position] != rune('\r') {
-						goto l362
+						goto l392
 					}
 					position++
 				}
-			l364:
-				add(ruleEOL, position363)
+			l394:
+				add(ruleEOL, position393)
 			}
 			return true
-		l362:
-			position, tokenIndex = position362, tokenIndex362
+		l392:
+			position, tokenIndex = position392, tokenIndex392
 			return false
 		},
-		/* 83 Action0 <- <{ p.Init() }> */
+		/* 87 Action0 <- <{ p.Init() }> */
 		func() bool {
 			{
 				add(ruleAction0, position)
 			}
 			return true
 		},
-		/* 84 Action1 <- <{ p.PrintDebug() }> */
+		/* 88 Action1 <- <{ p.PrintDebug() }> */
 		func() bool {
 			{
 				add(ruleAction1, position)
 			}
 			return true
 		},
-		/* 85 Action2 <- <{ p.PopPathAndAddUseStatement() }> */
+		/* 89 Action2 <- <{ p.PopPathAndAddUseStatement() }> */
 		func() bool {
 			{
 				add(ruleAction2, position)
 			}
 			return true
 		},
-		/* 86 Action3 <- <{ p.BuildPathFromSegments(true) }> */
+		/* 90 Action3 <- <{ p.BuildPathFromSegments(true) }> */
 		func() bool {
 			{
 				add(ruleAction3, position)
 			}
 			return true
 		},
-		/* 87 Action4 <- <{ p.BuildPathFromSegments(false) }> */
+		/* 91 Action4 <- <{ p.BuildPathFromSegments(false) }> */
 		func() bool {
 			{
 				add(ruleAction4, position)
 			}
 			return true
 		},
-		/* 88 Action5 <- <{ p.PushSuperKeyword() }> */
+		/* 92 Action5 <- <{ p.PushSuperKeyword() }> */
 		func() bool {
 			{
 				add(ruleAction5, position)
 			}
 			return true
 		},
-		/* 89 Action6 <- <{ p.BeginStruct() }> */
+		/* 93 Action6 <- <{ p.BeginStruct() }> */
 		func() bool {
 			{
 				add(ruleAction6, position)
 			}
 			return true
 		},
-		/* 90 Action7 <- <{ p.EndStruct() }> */
+		/* 94 Action7 <- <{ p.EndStruct() }> */
 		func() bool {
 			{
 				add(ruleAction7, position)
 			}
 			return true
 		},
-		/* 91 Action8 <- <{ p.PopStructAndAddStatement() }> */
+		/* 95 Action8 <- <{ p.PopStructAndAddStatement() }> */
 		func() bool {
 			{
 				add(ruleAction8, position)
 			}
 			return true
 		},
-		/* 92 Action9 <- <{ p.BeginField() }> */
+		/* 96 Action9 <- <{ p.BeginField() }> */
 		func() bool {
 			{
 				add(ruleAction9, position)
 			}
 			return true
 		},
-		/* 93 Action10 <- <{ p.EndField() }> */
+		/* 97 Action10 <- <{ p.EndField() }> */
 		func() bool {
 			{
 				add(ruleAction10, position)
 			}
 			return true
 		},
-		/* 94 Action11 <- <{ p.AddFieldColon() }> */
+		/* 98 Action11 <- <{ p.AddFieldColon() }> */
 		func() bool {
 			{
 				add(ruleAction11, position)
 			}
 			return true
 		},
-		/* 95 Action12 <- <{ p.MarkFieldOptional() }> */
+		/* 99 Action12 <- <{ p.MarkFieldOptional() }> */
 		func() bool {
 			{
 				add(ruleAction12, position)
@@ -3871,28 +4233,28 @@ func (p *MCDocParser) Init(options ...func(*MCDocParser) error) error {
 			return true
 		},
 		nil,
-		/* 97 Action13 <- <{ p.PushIdentifier(buffer[begin:end]) }> */
+		/* 101 Action13 <- <{ p.PushIdentifier(buffer[begin:end]) }> */
 		func() bool {
 			{
 				add(ruleAction13, position)
 			}
 			return true
 		},
-		/* 98 Action14 <- <{ p.PushString(buffer[begin:end]) }> */
+		/* 102 Action14 <- <{ p.PushString(buffer[begin:end]) }> */
 		func() bool {
 			{
 				add(ruleAction14, position)
 			}
 			return true
 		},
-		/* 99 Action15 <- <{ p.PushNumber(buffer[begin:end]) }> */
+		/* 103 Action15 <- <{ p.PushNumber(buffer[begin:end]) }> */
 		func() bool {
 			{
 				add(ruleAction15, position)
 			}
 			return true
 		},
-		/* 100 Action16 <- <{ p.PushBoolean(buffer[begin:end]) }> */
+		/* 104 Action16 <- <{ p.PushBoolean(buffer[begin:end]) }> */
 		func() bool {
 			{
 				add(ruleAction16, position)
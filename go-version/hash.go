@@ -0,0 +1,151 @@
+package mcheck
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PackHash is the content hash of a datapack, built from the hashes of its
+// files so that it does not depend on the order they are read in
+type PackHash struct {
+	Sum   string     `json:"sum"` // like sha256:1a2b...
+	Files []FileHash `json:"files"`
+}
+
+// FileHash is the hash of the normalized content of a file of a pack
+type FileHash struct {
+	Path string `json:"path"` // slash separated path below the pack root
+	Sum  string `json:"sum"`
+}
+
+// HashPack hashes the files below root, leaving out hidden files and
+// directories like .git. Content is normalized first so that the hash only
+// changes with what the game reads: JSON files are hashed in a canonical
+// form with sorted keys and no whitespace, and functions without a byte
+// order mark and with \n line endings. Symbolic links are followed, and
+// those that are broken or loop are left out, like the checks of a pack do,
+// so that the hash covers the files that are checked.
+func HashPack(root string) (PackHash, error) {
+	var hash PackHash
+	err := osFiles.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+		if err != nil && d != nil && file != root {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && file != root {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		content, err := osFiles.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(normalizeContent(file, content))
+		hash.Files = append(hash.Files, FileHash{Path: filepath.ToSlash(rel), Sum: hex.EncodeToString(sum[:])})
+		return nil
+	})
+	if err != nil {
+		return PackHash{}, err
+	}
+
+	sort.Slice(hash.Files, func(i, j int) bool { return hash.Files[i].Path < hash.Files[j].Path })
+	total := sha256.New()
+	for _, file := range hash.Files {
+		fmt.Fprintf(total, "%s\x00%s\n", file.Path, file.Sum)
+	}
+	hash.Sum = "sha256:" + hex.EncodeToString(total.Sum(nil))
+	return hash, nil
+}
+
+// normalizeContent returns the content of a file in the form it is hashed
+// in. JSON that does not parse is hashed as it is.
+func normalizeContent(file string, content []byte) []byte {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json", ".mcmeta":
+		decoder := json.NewDecoder(bytes.NewReader(replaceNonFiniteLiterals(bytes.TrimPrefix(content, utf8BOM))))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil || decoder.More() {
+			return content
+		}
+		var buf bytes.Buffer
+		writeCanonicalJSON(&buf, value)
+		return buf.Bytes()
+	case ".mcfunction":
+		content = bytes.TrimPrefix(content, utf8BOM)
+		return bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	}
+	return content
+}
+
+// writeCanonicalJSON writes a value decoded with UseNumber with sorted keys,
+// no whitespace and numbers in their shortest form, so 1.0 and 1 are alike.
+// Integers keep every digit, since seeds do not fit a float64.
+func writeCanonicalJSON(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalJSON(buf, key)
+			buf.WriteByte(':')
+			writeCanonicalJSON(buf, v[key])
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalJSON(buf, element)
+		}
+		buf.WriteByte(']')
+	case json.Number:
+		buf.WriteString(canonicalNumber(string(v)))
+	default:
+		encoded, _ := json.Marshal(v)
+		buf.Write(encoded)
+	}
+}
+
+// canonicalNumber writes a JSON number in its shortest form
+func canonicalNumber(number string) string {
+	if integer, ok := new(big.Int).SetString(number, 10); ok {
+		return integer.String()
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil {
+		if f == float64(int64(f)) && f > -1e15 && f < 1e15 {
+			return strconv.FormatInt(int64(f), 10)
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return number
+}
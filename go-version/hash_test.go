@@ -0,0 +1,64 @@
+package mcheck
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHashPack(t *testing.T) {
+	original := writePackFiles(t, map[string][]byte{
+		"pack.mcmeta":                        []byte(`{"pack": {"pack_format": 48, "description": "test"}}`),
+		"data/test/recipe/stick.json":        []byte(`{"type": "minecraft:crafting_shapeless", "count": 1.0, "seed": 1234567890123456789}`),
+		"data/test/function/init.mcfunction": []byte("say hi\nsay bye\n"),
+		"data/test/structure/house.nbt":      []byte{0x0a, 0x00, 0x00, 0x00},
+	})
+	reformatted := writePackFiles(t, map[string][]byte{
+		"pack.mcmeta":                        []byte("{\n  \"pack\": {\n    \"description\": \"test\",\n    \"pack_format\": 48\n  }\n}\n"),
+		"data/test/recipe/stick.json":        []byte("\xef\xbb\xbf{\"seed\": 1234567890123456789, \"count\": 1, \"type\": \"minecraft:crafting_shapeless\"}"),
+		"data/test/function/init.mcfunction": []byte("say hi\r\nsay bye\r\n"),
+		"data/test/structure/house.nbt":      []byte{0x0a, 0x00, 0x00, 0x00},
+		".git/HEAD":                          []byte("ref: refs/heads/main\n"),
+	})
+	changed := writePackFiles(t, map[string][]byte{
+		"pack.mcmeta":                        []byte(`{"pack": {"pack_format": 48, "description": "test"}}`),
+		"data/test/recipe/stick.json":        []byte(`{"type": "minecraft:crafting_shapeless", "count": 1.0, "seed": 1234567890123456788}`),
+		"data/test/function/init.mcfunction": []byte("say hi\nsay bye\n"),
+		"data/test/structure/house.nbt":      []byte{0x0a, 0x00, 0x00, 0x00},
+	})
+
+	hash, err := HashPack(original)
+	if err != nil {
+		t.Fatalf("HashPack failed: %v", err)
+	}
+	if !strings.HasPrefix(hash.Sum, "sha256:") || len(hash.Files) != 4 || hash.Files[0].Path != "data/test/function/init.mcfunction" {
+		t.Errorf("Unexpected hash %+v", hash)
+	}
+	if other, _ := HashPack(reformatted); other.Sum != hash.Sum {
+		t.Errorf("Expected reformatting not to change the hash, got %+v and %+v", hash, other)
+	}
+	if other, _ := HashPack(changed); other.Sum == hash.Sum {
+		t.Errorf("Expected a changed seed to change the hash")
+	}
+
+	// Linked files are hashed like the checks read them, and loops are
+	// left out
+	linked := writePackFiles(t, map[string][]byte{
+		"pack.mcmeta":                        []byte(`{"pack": {"pack_format": 48, "description": "test"}}`),
+		"data/test/function/init.mcfunction": []byte("say hi\nsay bye\n"),
+		"data/test/structure/house.nbt":      []byte{0x0a, 0x00, 0x00, 0x00},
+	})
+	links := map[string]string{
+		filepath.Join(linked, "data", "test", "recipe"): filepath.Join(original, "data", "test", "recipe"),
+		filepath.Join(linked, "data", "test", "loop"):   filepath.Join(linked, "data"),
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("Cannot create symbolic links: %v", err)
+		}
+	}
+	if other, err := HashPack(linked); err != nil || other.Sum != hash.Sum {
+		t.Errorf("Expected linked files to be hashed, got %+v (%v)", other, err)
+	}
+}
@@ -0,0 +1,106 @@
+package mcheck
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// hookMarker identifies pre-commit hooks written by mcheck hook install, so
+// that installing again replaces them but leaves other hooks alone
+const hookMarker = "# Installed by mcheck hook install"
+
+// StagedFiles asks git for the datapack files staged for commit in the
+// repository at root: JSON and NBT files within a data directory that are
+// added, copied, modified or renamed
+func StagedFiles(root string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z")
+	cmd.Dir = root
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var files []string
+	for _, name := range strings.Split(string(output), "\x00") {
+		if name != "" && isDatapackFile(name) {
+			files = append(files, filepath.Join(root, filepath.FromSlash(name)))
+		}
+	}
+	return files, nil
+}
+
+// isDatapackFile reports whether validate can tell the resource type of a
+// file from its path, like data/minecraft/recipe/stick.json
+func isDatapackFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".nbt":
+	default:
+		return false
+	}
+	parts := pathParts(name)
+	for _, part := range parts[:len(parts)-1] {
+		if strings.EqualFold(part, "data") {
+			return true
+		}
+	}
+	return false
+}
+
+// PreCommitHook writes the script of a pre-commit hook running
+// mcheck hook run with the given flags, like --version=1.21.1
+func PreCommitHook(executable string, flags []string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString(hookMarker + ": validates the datapack files being\n")
+	b.WriteString("# committed. Skip it once with git commit --no-verify.\n")
+	b.WriteString("exec " + shellQuote(executable))
+	for _, flag := range flags {
+		b.WriteString(" " + shellQuote(flag))
+	}
+	b.WriteString(" hook run\n")
+	return b.String()
+}
+
+// shellQuote quotes a word for sh unless it is plain
+func shellQuote(word string) string {
+	if word != "" && strings.Trim(word, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=.,/:@+") == "" {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// InstallHook writes script as the pre-commit hook of the git repository
+// dir belongs to and returns its path. A hook mcheck did not write is only
+// replaced if force is set.
+func InstallHook(dir, script string, force bool) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", "hooks/pre-commit")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	hook := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(hook) {
+		hook = filepath.Join(dir, hook)
+	}
+
+	if existing, err := os.ReadFile(hook); err == nil && !force && !strings.Contains(string(existing), hookMarker) {
+		return "", fmt.Errorf("%s already exists, use --force to replace it", hook)
+	}
+	if err := os.MkdirAll(filepath.Dir(hook), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(hook, []byte(script), 0755); err != nil {
+		return "", err
+	}
+	// WriteFile keeps the mode of a file that exists
+	return hook, os.Chmod(hook, 0755)
+}
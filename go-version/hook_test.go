@@ -0,0 +1,66 @@
+package mcheck
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHookInstallAndStagedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/recipe/stick.json":        []byte(`{}`),
+		"data/test/structure/house.nbt":      []byte{},
+		"data/test/function/init.mcfunction": []byte("say hi\n"),
+		"pack.mcmeta":                        []byte(`{}`),
+		"notes.json":                         []byte(`{}`),
+	})
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = root
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, output)
+	}
+	cmd = exec.Command("git", "add", "-A")
+	cmd.Dir = root
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git add failed: %v\n%s", err, output)
+	}
+
+	staged, err := StagedFiles(root)
+	if err != nil {
+		t.Fatalf("stagedFiles failed: %v", err)
+	}
+	expected := []string{filepath.Join(root, "data/test/recipe/stick.json"), filepath.Join(root, "data/test/structure/house.nbt")}
+	if strings.Join(staged, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected staged files %v, got %v", expected, staged)
+	}
+
+	script := PreCommitHook("/usr/local/bin/mcheck", []string{"--version=1.21.1", "--schema-dir=/srv/vanilla mcdoc"})
+	if !strings.HasSuffix(script, "exec /usr/local/bin/mcheck --version=1.21.1 '--schema-dir=/srv/vanilla mcdoc' hook run\n") {
+		t.Errorf("Unexpected hook script:\n%s", script)
+	}
+	hook, err := InstallHook(root, script, false)
+	if err != nil {
+		t.Fatalf("installHook failed: %v", err)
+	}
+	if info, err := os.Stat(hook); err != nil || info.Mode()&0100 == 0 {
+		t.Errorf("Expected an executable hook at %s, got %v", hook, err)
+	}
+
+	// A hook of mcheck's own is replaced, another one only with force
+	if _, err := InstallHook(root, script, false); err != nil {
+		t.Errorf("Expected the mcheck hook to be replaced, got %v", err)
+	}
+	os.WriteFile(hook, []byte("#!/bin/sh\nmake lint\n"), 0755)
+	if _, err := InstallHook(root, script, false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("Expected an existing hook to be kept, got %v", err)
+	}
+	if _, err := InstallHook(root, script, true); err != nil {
+		t.Errorf("Expected --force to replace the hook, got %v", err)
+	}
+}
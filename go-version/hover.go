@@ -0,0 +1,241 @@
+package mcheck
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HoverInfo describes the schema of the value at a JSON pointer in a document
+type HoverInfo struct {
+	Pointer     string   `json:"pointer"`
+	Type        string   `json:"type"`
+	Constraints []string `json:"constraints,omitempty"`
+	Since       string   `json:"since,omitempty"`
+	Until       string   `json:"until,omitempty"`
+	Feature     string   `json:"feature,omitempty"` // feature flag the field needs
+	Optional    bool     `json:"optional,omitempty"`
+	Doc         string   `json:"doc,omitempty"`
+	Source      string   `json:"source,omitempty"` // schema file and line declaring the field
+}
+
+// Hover resolves the schema of the value at pointer in the JSON file at jsonPath
+func (v *PEGMCDocValidator) Hover(jsonPath, pointer string) (*HoverInfo, error) {
+	converter, mainValidator, err := v.loadSchemaFor(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonContent, err := v.files.ReadFile(jsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON file: %w", err)
+	}
+
+	jsonData, err := decodeJSON(jsonContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	segments, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveHover(mainValidator, jsonData, pointer, segments, v.newContext(converter))
+}
+
+// parseJSONPointer splits an RFC 6901 JSON pointer like /noise/min_y into segments
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with '/'", pointer)
+	}
+
+	segments := strings.Split(pointer[1:], "/")
+	for i, segment := range segments {
+		segment = strings.ReplaceAll(segment, "~1", "/")
+		segments[i] = strings.ReplaceAll(segment, "~0", "~")
+	}
+	return segments, nil
+}
+
+// resolveHover walks the validator tree along segments, following the document
+// where it exists so that unions and dispatches resolve to the matching case
+func resolveHover(validator Validator, value interface{}, pointer string, segments []string, ctx *ValidationContext) (*HoverInfo, error) {
+	_, info, _, err := resolvePointer(validator, value, pointer, segments, ctx)
+	return info, err
+}
+
+// resolvePointer resolves the validator of the value at pointer along with
+// its hover information and the context it is validated in
+func resolvePointer(validator Validator, value interface{}, pointer string, segments []string, ctx *ValidationContext) (Validator, *HoverInfo, *ValidationContext, error) {
+	info := &HoverInfo{Pointer: pointer}
+
+	current := validator
+	for _, segment := range segments {
+		container := concreteValidator(current, value, ctx)
+		switch c := container.(type) {
+		case *StructValidator:
+			obj, _ := value.(map[string]interface{})
+			ctx = ctx.WithParent(obj)
+			field, ok := findStructField(c, segment, obj, ctx)
+			if !ok {
+				return nil, nil, nil, fmt.Errorf("no field '%s' in %s at %s", segment, DescribeType(c), pointer)
+			}
+			current, value = field.Validator, obj[segment]
+			info.Doc, info.Optional = field.Doc, field.Optional
+			info.Since, info.Until, info.Feature = field.Since, field.Until, field.Feature
+			info.Source = field.Source.String()
+		case *ArrayValidator:
+			index, err := strconv.Atoi(segment)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("expected array index at %s, got '%s'", pointer, segment)
+			}
+			arr, _ := value.([]interface{})
+			ctx = ctx.WithParent(arr)
+			value = nil
+			if index >= 0 && index < len(arr) {
+				value = arr[index]
+			}
+			current = c.ElementValidator
+			info.Doc, info.Optional, info.Since, info.Until, info.Feature, info.Source = "", false, "", "", "", ""
+		default:
+			return nil, nil, nil, fmt.Errorf("cannot resolve '%s' at %s: value is not an object or array", segment, pointer)
+		}
+		ctx = ctx.Child(segment)
+	}
+
+	info.Type = describeTypeIn(current, ctx)
+	info.Constraints = collectConstraints(current, ctx, info)
+	return current, info, ctx, nil
+}
+
+// concreteValidator unwraps references, attributes, dispatches and unions until
+// it reaches the validator that decides the shape of value
+func concreteValidator(v Validator, value interface{}, ctx *ValidationContext) Validator {
+	// Bounded to avoid looping forever on self-referencing aliases
+	for depth := 0; depth < 64 && v != nil; depth++ {
+		switch t := v.(type) {
+		case *ReferenceValidator:
+			v = ctx.Definitions[t.TypeName]
+		case *AttributedValidator:
+			v = t.InnerValidator
+		case *ConstrainedValidator:
+			v = t.InnerValidator
+		case *DispatchValidator:
+			v = t.Resolve(value, ctx)
+		case *UnionValidator:
+			v = pickAlternative(t, value, ctx)
+		default:
+			return v
+		}
+	}
+	return v
+}
+
+// pickAlternative chooses the union alternative that best matches value
+func pickAlternative(uv *UnionValidator, value interface{}, ctx *ValidationContext) Validator {
+	if value != nil {
+		for _, alt := range uv.Alternatives {
+			if !hasErrors(alt.Validate(value, ctx)) {
+				return alt
+			}
+		}
+	}
+
+	for _, alt := range uv.Alternatives {
+		switch concreteValidator(alt, value, ctx).(type) {
+		case *StructValidator:
+			if _, ok := value.(map[string]interface{}); ok {
+				return alt
+			}
+		case *ArrayValidator:
+			if _, ok := value.([]interface{}); ok {
+				return alt
+			}
+		}
+	}
+
+	if alternatives := uv.applicable(ctx); len(alternatives) > 0 {
+		return alternatives[0]
+	}
+	return nil
+}
+
+// findStructField looks up a field by name, including fields provided by spreads
+func findStructField(sv *StructValidator, name string, obj map[string]interface{}, ctx *ValidationContext) (StructField, bool) {
+	for _, field := range sv.Fields {
+		if field.Name == name && field.AppliesForVersion(ctx) {
+			return field, true
+		}
+	}
+
+	for _, spread := range sv.SpreadFields {
+		if spreadStruct, ok := concreteValidator(spread, obj, ctx).(*StructValidator); ok {
+			if field, found := findStructField(spreadStruct, name, obj, ctx); found {
+				return field, true
+			}
+		}
+	}
+	return StructField{}, false
+}
+
+// collectConstraints lists the constraints a validator applies on top of its base type,
+// filling in version bounds from attributes when the field itself has none
+func collectConstraints(v Validator, ctx *ValidationContext, info *HoverInfo) []string {
+	var constraints []string
+	for depth := 0; depth < 64 && v != nil; depth++ {
+		switch t := v.(type) {
+		case *AttributedValidator:
+			if info.Since == "" {
+				info.Since = t.Since
+			}
+			if info.Until == "" {
+				info.Until = t.Until
+			}
+			if info.Feature == "" {
+				info.Feature = t.Feature
+			}
+			names := make([]string, 0, len(t.Attributes))
+			for name := range t.Attributes {
+				if name != "since" && name != "until" && name != "feature" {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				constraints = append(constraints, strings.TrimSpace(describeAttributes(map[string]string{name: t.Attributes[name]})))
+			}
+			v = t.InnerValidator
+		case *ConstrainedValidator:
+			label := "range"
+			if primitive, ok := t.InnerValidator.(*PrimitiveValidator); ok && primitive.Type == "string" {
+				label = "length"
+			}
+			constraints = append(constraints, label+" "+DescribeType(t.Constraint))
+			v = t.InnerValidator
+		case *ArrayValidator:
+			if t.LengthConstraint != nil {
+				constraints = append(constraints, "length "+describeRange(t.LengthConstraint))
+			}
+			return constraints
+		case *EnumValidator:
+			var values []string
+			for _, enumValue := range t.Values {
+				if enumValue.AppliesForVersion(ctx) {
+					values = append(values, fmt.Sprintf("%#v", enumValue.Value))
+				}
+			}
+			constraints = append(constraints, "one of "+strings.Join(values, ", "))
+			return constraints
+		case *ReferenceValidator:
+			v = ctx.Definitions[t.TypeName]
+		default:
+			return constraints
+		}
+	}
+	return constraints
+}
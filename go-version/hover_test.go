@@ -0,0 +1,123 @@
+package mcheck
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestPack creates a schema directory with a single schema and a datapack
+// file for it, returning the schema directory and the JSON file path
+func writeTestPack(t *testing.T, resourceType, schema, document string) (string, string) {
+	t.Helper()
+
+	root := t.TempDir()
+	schemaPath := filepath.Join(root, "vanilla-mcdoc", "java", "data", filepath.FromSlash(resourceType)+".mcdoc")
+	jsonPath := filepath.Join(root, "pack", "data", "test", filepath.FromSlash(resourceType), "example.json")
+
+	for path, content := range map[string]string{schemaPath: schema, jsonPath: document} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	return filepath.Join(root, "vanilla-mcdoc"), jsonPath
+}
+
+func TestHover(t *testing.T) {
+	schemaDir, jsonPath := writeTestPack(t, "worldgen/thing", converterTestSchema, `{
+		"type": "simple",
+		"size": 2,
+		"mode": "fast",
+		"tags": ["a"],
+		"value": 1,
+		"config": {"amount": 3}
+	}`)
+
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, schemaDir)
+
+	tests := []struct {
+		pointer     string
+		typeName    string
+		constraints []string
+		since       string
+	}{
+		{"", "struct Thing", nil, ""},
+		{"/size", "int @ 1..4", []string{"range 1..4"}, ""},
+		{"/name", "string @ 1..", []string{"length 1.."}, ""},
+		{"/mode", "Mode", []string{`one of "fast", "slow"`}, ""},
+		{"/tags", "[string] @ ..2", []string{"length ..2"}, ""},
+		{"/tags/0", "string", nil, ""},
+		{"/newer", "boolean", nil, "1.19"},
+		{"/config/amount", "int", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			info, err := validator.Hover(jsonPath, tt.pointer)
+			if err != nil {
+				t.Fatalf("Hover failed: %v", err)
+			}
+			if info.Type != tt.typeName {
+				t.Errorf("Expected type %q, got %q", tt.typeName, info.Type)
+			}
+			if len(info.Constraints) != len(tt.constraints) {
+				t.Fatalf("Expected constraints %v, got %v", tt.constraints, info.Constraints)
+			}
+			for i := range tt.constraints {
+				if info.Constraints[i] != tt.constraints[i] {
+					t.Errorf("Expected constraint %q, got %q", tt.constraints[i], info.Constraints[i])
+				}
+			}
+			if info.Since != tt.since {
+				t.Errorf("Expected since %q, got %q", tt.since, info.Since)
+			}
+		})
+	}
+
+	info, err := validator.Hover(jsonPath, "/size")
+	if err != nil || info.Doc != "How big the thing is." {
+		t.Errorf("Expected doc comment for /size, got %+v (%v)", info, err)
+	}
+
+	if _, err := validator.Hover(jsonPath, "/missing"); err == nil {
+		t.Error("Expected hover on unknown field to fail")
+	}
+}
+
+func TestParseJSONPointer(t *testing.T) {
+	segments, err := parseJSONPointer("/a~1b/c~0d/0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"a/b", "c~d", "0"}
+	if len(segments) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, segments)
+	}
+	for i := range expected {
+		if segments[i] != expected[i] {
+			t.Errorf("Expected segment %q, got %q", expected[i], segments[i])
+		}
+	}
+
+	if _, err := parseJSONPointer("noslash"); err == nil {
+		t.Error("Expected error for pointer without leading slash")
+	}
+}
+
+func TestCollectConstraintsOrder(t *testing.T) {
+	validator := &AttributedValidator{
+		InnerValidator: &PrimitiveValidator{Type: "string"},
+		Attributes:     map[string]string{"since": "1.20", "regex": "^a", "color": "", "id": "item", "deprecated": "", "text_component": ""},
+	}
+	info := &HoverInfo{}
+	constraints := collectConstraints(validator, &ValidationContext{}, info)
+	expected := []string{"#[color]", "#[deprecated]", `#[id="item"]`, `#[regex="^a"]`, "#[text_component]"}
+	if strings.Join(constraints, " ") != strings.Join(expected, " ") || info.Since != "" {
+		t.Errorf("Expected the attributes in order %v, got %v", expected, constraints)
+	}
+}
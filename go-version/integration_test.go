@@ -1,4 +1,4 @@
-package main
+package mcheck
 
 import (
 	"testing"
@@ -7,7 +7,7 @@ import (
 // TestMainCleanupIntegration verifies that the cleaned up main.go works correctly
 func TestMainCleanupIntegration(t *testing.T) {
 	// Test that we can create a PEG validator without the old code
-	version, err := parseVersion("1.20.1")
+	version, err := ParseVersion("1.20.1")
 	if err != nil {
 		t.Fatalf("Failed to parse version: %v", err)
 	}
@@ -34,7 +34,7 @@ func TestMainCleanupIntegration(t *testing.T) {
 
 // TestPEGValidatorEndToEnd tests the complete validation flow
 func TestPEGValidatorEndToEnd(t *testing.T) {
-	version, err := parseVersion("1.20.1")
+	version, err := ParseVersion("1.20.1")
 	if err != nil {
 		t.Fatalf("Failed to parse version: %v", err)
 	}
@@ -0,0 +1,190 @@
+package mcheck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// componentsSince is the version that replaced item NBT with item components
+var componentsSince = Version{1, 20, 5}
+
+// componentRegistry is the dispatcher vanilla-mcdoc declares the schema of
+// each item component in
+const componentRegistry = "minecraft:data_component"
+
+// checkFunction checks the item components of the commands of a function
+// against their schemas: those of the items give creates and clear matches,
+// and those of the item stacks data writes. Commands still using the item NBT
+// of 1.20.4 and older, which no longer parses, are reported too. Targets
+// older than 1.20.5 and schemas without component types are not checked.
+func (v *PEGMCDocValidator) checkFunction(path, name string, pack *Pack) []Finding {
+	if v.targetVersion.Compare(componentsSince) < 0 || len(v.schemaIndex().DispatcherFiles(componentRegistry)) == 0 {
+		return nil
+	}
+	data, err := pack.files.ReadFile(path)
+	if err != nil {
+		return []Finding{findingFromError(name, RuleError{RuleUnreadableFile, err})}
+	}
+
+	var findings []Finding
+	for _, line := range commandLines(string(data)) {
+		linePath := []string{fmt.Sprintf("line %d", line.number)}
+		for _, finding := range v.commandFindings(executedCommand(commandArgs(line.text)), linePath) {
+			finding.File = name
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// executedCommand returns the command execute runs, or args if it is not an
+// execute command
+func executedCommand(args []string) []string {
+	if len(args) == 0 || strings.TrimPrefix(args[0], "/") != "execute" {
+		return args
+	}
+	for i, arg := range args {
+		if arg == "run" {
+			return executedCommand(args[i+1:])
+		}
+	}
+	return nil
+}
+
+// commandFindings checks the items and item stacks of a command
+func (v *PEGMCDocValidator) commandFindings(args []string, path []string) []Finding {
+	if len(args) < 3 {
+		return nil
+	}
+	switch strings.TrimPrefix(args[0], "/") {
+	case "give":
+		// give <targets> <item>[<components>] [<count>]
+		return v.itemArgumentFindings(args[2], ",", path)
+	case "clear":
+		// clear <targets> <item predicate> [<count>], where the predicate
+		// matches components with = and tests them with ~, like
+		// stick[damage=0|custom_data~{a:1}]
+		return v.itemArgumentFindings(args[2], ",|", path)
+	case "data":
+		// data merge <target> <nbt> and data modify <target> <path> ... value <nbt>
+		var findings []Finding
+		for i := 3; i < len(args); i++ {
+			arg := args[i]
+			if !strings.HasPrefix(arg, "{") && !(args[i-1] == "value" && strings.HasPrefix(arg, "[")) {
+				continue
+			}
+			value, err := parseSNBT(arg)
+			if err != nil {
+				findings = append(findings, Finding{Path: path, Severity: SeverityError, Message: fmt.Sprintf("invalid SNBT: %v", err), Rule: RuleInvalidSNBT})
+				continue
+			}
+			findings = append(findings, v.itemStackFindings(value, path)...)
+		}
+		return findings
+	}
+	return nil
+}
+
+// itemArgumentFindings checks an item argument, like
+// diamond_sword[damage=5,enchantments={levels:{sharpness:3}}]. Components
+// removed with ! or tested with ~ rather than matched are not checked.
+func (v *PEGMCDocValidator) itemArgumentFindings(item, separators string, path []string) []Finding {
+	if open := strings.IndexAny(item, "[{"); open >= 0 && item[open] == '{' {
+		return []Finding{{
+			Path:     path,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("item %s has NBT, which %s replaced with components like %s[custom_data=...]", item[:open], componentsSince, item[:open]),
+			Rule:     RuleItemNBT,
+		}}
+	}
+	open := strings.IndexByte(item, '[')
+	if open < 0 || !strings.HasSuffix(item, "]") {
+		return nil
+	}
+
+	var findings []Finding
+	for _, component := range splitTopLevel(item[open+1:len(item)-1], separators) {
+		component = strings.TrimSpace(component)
+		end := strings.IndexAny(component, "=~")
+		if end < 0 || component[end] != '=' || strings.HasPrefix(component, "!") {
+			continue
+		}
+		key, text := strings.TrimSpace(component[:end]), strings.TrimSpace(component[end+1:])
+		value, err := parseSNBT(text)
+		if err != nil {
+			findings = append(findings, Finding{Path: appendPath(path, key), Severity: SeverityError, Message: fmt.Sprintf("invalid SNBT: %v", err), Rule: RuleInvalidSNBT})
+			continue
+		}
+		findings = append(findings, v.componentFindings(key, value, path)...)
+	}
+	return findings
+}
+
+// itemStackFindings checks the item stacks in NBT written by a data command:
+// the components of any compound holding them, and compounds still using the
+// Count and tag fields of item stacks before 1.20.5
+func (v *PEGMCDocValidator) itemStackFindings(value interface{}, path []string) []Finding {
+	var findings []Finding
+	switch value := value.(type) {
+	case map[string]interface{}:
+		_, hasID := value["id"].(string)
+		_, hasCount := value["Count"]
+		_, hasTag := value["tag"].(map[string]interface{})
+		if hasID && (hasCount || hasTag) {
+			findings = append(findings, Finding{
+				Path:     path,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("item stack has Count or tag, which %s replaced with count and components", componentsSince),
+				Rule:     RuleItemNBT,
+			})
+		}
+		if components, ok := value["components"].(map[string]interface{}); ok {
+			var keys []string
+			for _, key := range sortedKeys(components, &keys) {
+				findings = append(findings, v.componentFindings(key, components[key], appendPath(path, "components"))...)
+			}
+		}
+		var keys []string
+		for _, key := range sortedKeys(value, &keys) {
+			if key != "components" {
+				findings = append(findings, v.itemStackFindings(value[key], appendPath(path, key))...)
+			}
+		}
+	case []interface{}:
+		for i, element := range value {
+			findings = append(findings, v.itemStackFindings(element, appendPath(path, fmt.Sprintf("[%d]", i)))...)
+		}
+	}
+	return findings
+}
+
+// componentFindings validates the value of a component against the case of
+// the data component dispatcher for it. The component is reported as
+// unknown if no schema file declares it, and a schema that fails to parse
+// as a schema problem.
+func (v *PEGMCDocValidator) componentFindings(key string, value interface{}, path []string) []Finding {
+	name := strings.TrimPrefix(key, "minecraft:")
+	schemaPath := v.schemaIndex().DispatchFile(componentRegistry, name)
+	if schemaPath == "" {
+		return []Finding{{
+			Path:     appendPath(path, key),
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("unknown item component '%s'", key),
+			Rule:     RuleUnknownField,
+		}}
+	}
+	converter, err := v.convertedSchema(schemaPath)
+	if err != nil {
+		finding := findingFromError("", err)
+		finding.Path = appendPath(path, key)
+		return []Finding{finding}
+	}
+	validator, ok := converter.Dispatches()[componentRegistry][name]
+	if !ok {
+		return nil
+	}
+	ctx := v.newContext(converter)
+	ctx.NBT = true
+	ctx.Path = appendPath(path, key)
+	return validator.Validate(value, ctx)
+}
@@ -0,0 +1,57 @@
+package mcheck
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFunctionItemComponents(t *testing.T) {
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/function/items.mcfunction": []byte(strings.Join([]string{
+			"give @s diamond_sword[damage=5,enchantments={levels:{\"minecraft:sharpness\":3}},rarity=epic] 1",
+			"give @s stick[damage=-1]",
+			"give @s stick{display:{Name:'\"Wand\"'}}",
+			"execute as @a run give @s book[stored_enchantments={levels:{\"minecraft:mending\":0}},!max_stack_size]",
+			"give @s stick[max_stack_size=\"many\"]",
+			"give @s stick[enchantments={levels:{minecraft:sharpness:3}}]",
+			"give @s stick[glint=true]",
+			"clear @s stick[max_stack_size=0|damage=3|custom_data~{a:1}]",
+			"data merge entity @e[type=item,limit=1] {Item:{id:\"minecraft:stick\",count:1,components:{\"minecraft:rarity\":\"legendary\"}}}",
+			"data modify block ~ ~ ~ Items append value {id:\"minecraft:stick\",Count:1b,tag:{}}",
+			"give @s stick[unbreakable={}]",
+		}, "\n")),
+	})
+	validator := NewPEGMCDocValidator(Version{1, 21, 0}, fixtureSchemaDir(t, "../world/component/data_component"))
+	findings, err := validator.CheckPack(context.Background(), root, nil, PackOptions{})
+	if err != nil {
+		t.Fatalf("CheckPack failed: %v", err)
+	}
+
+	var messages []string
+	for _, finding := range findings {
+		if !strings.HasPrefix(finding.Rule, "MCHECK04") || finding.Rule == RuleItemNBT {
+			messages = append(messages, finding.String())
+		}
+	}
+	expected := []string{
+		"data/test/function/items.mcfunction: at line 2.damage: value -1 must be greater than or equal to 0 (range 0..) [MCHECK010 out-of-range]",
+		"data/test/function/items.mcfunction: at line 3: item stick has NBT, which 1.20.5 replaced with components like stick[custom_data=...] [MCHECK047 item-nbt]",
+		"data/test/function/items.mcfunction: at line 4.stored_enchantments.levels.minecraft:mending: value 0 must be greater than or equal to 1 (range 1..255) [MCHECK010 out-of-range]",
+		"data/test/function/items.mcfunction: at line 5.max_stack_size: expected int, got string (range 1..99) [MCHECK003 wrong-type]",
+		"data/test/function/items.mcfunction: at line 6.enchantments: invalid SNBT: at column 29: expected ',' or '}' [MCHECK023 invalid-snbt]",
+		"data/test/function/items.mcfunction: at line 7.glint: unknown item component 'glint' [MCHECK001 unknown-field]",
+		"data/test/function/items.mcfunction: at line 8.max_stack_size: value 0 must be greater than or equal to 1 (range 1..99) [MCHECK010 out-of-range]",
+		`data/test/function/items.mcfunction: at line 9.Item.components.minecraft:rarity: expected one of "common", "uncommon", "rare", "epic", got "legendary" [MCHECK005 invalid-enum-value]`,
+		"data/test/function/items.mcfunction: at line 10: item stack has Count or tag, which 1.20.5 replaced with count and components [MCHECK047 item-nbt]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+
+	// Item NBT was how items were written before components
+	validator = NewPEGMCDocValidator(Version{1, 20, 4}, fixtureSchemaDir(t, "../world/component/data_component"))
+	if findings, err := validator.CheckPack(context.Background(), root, nil, PackOptions{}); err != nil || len(findings) != 0 {
+		t.Errorf("Expected no findings for 1.20.4, got %v (%v)", findings, err)
+	}
+}
@@ -0,0 +1,213 @@
+package mcheck
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// contextSources are the loot context entries item modifier functions read
+// from, by function and field. An item modifier applied with /item modify
+// only has the entity it targets, "this", so any other source needs the
+// modifier to be referenced from a loot table that provides it.
+var contextSources = map[string]string{
+	"copy_components":  "source",
+	"copy_name":        "source",
+	"copy_custom_data": "source",
+	"copy_nbt":         "source",
+	"fill_player_head": "entity",
+	"set_lore":         "entity",
+	"set_name":         "entity",
+}
+
+// contextFunctions are the functions that always need loot context /item
+// modify does not give, with what they need
+var contextFunctions = map[string]string{
+	"copy_state":               "a block state",
+	"apply_bonus":              "a tool",
+	"looting_enchant":          "a killer",
+	"enchanted_count_increase": "an attacker",
+}
+
+// maxEnchantmentLevel is the highest enchantment level items store; levels
+// beyond it are clamped
+const maxEnchantmentLevel = 255
+
+// checkItemModifiers checks the functions of an item modifier: sources the
+// function needs but /item modify does not provide, functions overridden by
+// a later function in the same sequence, and counts and enchantment levels
+// the game clamps
+func checkItemModifiers(value map[string]interface{}) []Finding {
+	var findings []Finding
+	checkItemFunction(value, nil, &findings)
+	return findings
+}
+
+// checkItemModifierList checks an item modifier file that is a list of
+// functions, which the game reads as a sequence
+func checkItemModifierList(functions []interface{}) []Finding {
+	var findings []Finding
+	checkFunctionSequence(functions, nil, &findings)
+	return findings
+}
+
+// checkItemFunction checks a single function, descending into sequences
+func checkItemFunction(function map[string]interface{}, path []string, findings *[]Finding) {
+	warn := func(field, format string, args ...interface{}) {
+		*findings = append(*findings, Finding{
+			Path:     appendPath(path, field),
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf(format, args...),
+			Rule:     RuleItemModifier,
+		})
+	}
+
+	name := functionName(function)
+	if needs, ok := contextFunctions[name]; ok {
+		warn("function", "%s needs %s, which /item modify does not provide; only use this modifier from loot tables that do", name, needs)
+	}
+	if field, ok := contextSources[name]; ok {
+		if source := contextSource(function[field]); source != "" && source != "this" {
+			warn(field, "%s %s %s is not available to /item modify; only use this modifier from loot tables that provide it", name, field, source)
+		}
+	}
+
+	switch name {
+	case "sequence":
+		functions, _ := function["functions"].([]interface{})
+		checkFunctionSequence(functions, appendPath(path, "functions"), findings)
+	case "set_count":
+		checkNumberProvider(function["count"], appendPath(path, "count"), findings)
+		if count, ok := function["count"].(float64); ok && count < 0 && !isTrue(function["add"]) {
+			warn("count", "count %g empties the stack", count)
+		}
+	case "limit_count":
+		limit, _ := function["limit"].(map[string]interface{})
+		low, hasLow := limit["min"].(float64)
+		high, hasHigh := limit["max"].(float64)
+		if hasLow && hasHigh && low > high {
+			warn("limit", "limit min %g is greater than max %g", low, high)
+		}
+	case "set_damage":
+		checkNumberProvider(function["damage"], appendPath(path, "damage"), findings)
+		if damage, ok := function["damage"].(float64); ok && (damage < 0 || damage > 1) && !isTrue(function["add"]) {
+			warn("damage", "damage %g is outside 0..1 and is clamped", damage)
+		}
+	case "enchant_with_levels":
+		checkNumberProvider(function["levels"], appendPath(path, "levels"), findings)
+	case "set_enchantments":
+		enchantments, _ := function["enchantments"].(map[string]interface{})
+		var ids []string
+		for _, id := range sortedKeys(enchantments, &ids) {
+			level := enchantments[id]
+			levelPath := appendPath(path, "enchantments", id)
+			checkNumberProvider(level, levelPath, findings)
+			if level, ok := level.(float64); ok && (level < 0 || level > maxEnchantmentLevel) {
+				*findings = append(*findings, Finding{
+					Path:     levelPath,
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("%s level %g is outside 0..%d and is clamped", id, level, maxEnchantmentLevel),
+					Rule:     RuleItemModifier,
+				})
+			}
+		}
+	}
+}
+
+// checkFunctionSequence checks the functions of a sequence, and warns about
+// functions whose effect a later unconditional function replaces
+func checkFunctionSequence(functions []interface{}, path []string, findings *[]Finding) {
+	for i, element := range functions {
+		function, ok := element.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		checkItemFunction(function, appendPath(path, strconv.Itoa(i)), findings)
+		for j := i + 1; j < len(functions); j++ {
+			later, ok := functions[j].(map[string]interface{})
+			if ok && overrides(later, function) {
+				*findings = append(*findings, Finding{
+					Path:     appendPath(path, strconv.Itoa(i)),
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("%s has no effect, the %s at index %d replaces it", functionName(function), functionName(later), j),
+					Rule:     RuleItemModifier,
+				})
+				break
+			}
+		}
+	}
+}
+
+// overrides reports whether a later function always replaces what an
+// earlier one did: setting the count, damage or the same name again without
+// conditions or adding to it
+func overrides(later, earlier map[string]interface{}) bool {
+	if conditions, _ := later["conditions"].([]interface{}); len(conditions) > 0 || isTrue(later["add"]) {
+		return false
+	}
+	switch functionName(later) {
+	case "set_count":
+		earlierName := functionName(earlier)
+		return earlierName == "set_count" || earlierName == "limit_count"
+	case "set_damage":
+		return functionName(earlier) == "set_damage"
+	case "set_name":
+		return functionName(earlier) == "set_name" && earlier["target"] == later["target"]
+	}
+	return false
+}
+
+// checkNumberProvider warns about uniform number providers whose min is
+// greater than their max, which the game rejects when the function runs
+func checkNumberProvider(value interface{}, path []string, findings *[]Finding) {
+	provider, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	providerType, _ := provider["type"].(string)
+	if providerType != "" && strings.TrimPrefix(providerType, "minecraft:") != "uniform" {
+		return
+	}
+	low, hasLow := provider["min"].(float64)
+	high, hasHigh := provider["max"].(float64)
+	if hasLow && hasHigh && low > high {
+		*findings = append(*findings, Finding{
+			Path:     path,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("uniform min %g is greater than max %g", low, high),
+			Rule:     RuleItemModifier,
+		})
+	}
+}
+
+// functionName returns the name of an item function without the minecraft
+// namespace
+func functionName(function map[string]interface{}) string {
+	name, _ := function["function"].(string)
+	return strings.TrimPrefix(name, "minecraft:")
+}
+
+// contextSource returns the loot context entity a function reads from, given
+// as a name or as a {"type": "context", "target": ...} source
+func contextSource(value interface{}) string {
+	switch source := value.(type) {
+	case string:
+		return source
+	case map[string]interface{}:
+		if sourceType, _ := source["type"].(string); strings.TrimPrefix(sourceType, "minecraft:") == "context" {
+			target, _ := source["target"].(string)
+			return target
+		}
+	}
+	return ""
+}
+
+func isTrue(value interface{}) bool {
+	b, _ := value.(bool)
+	return b
+}
+
+// appendPath returns path extended by elements without modifying path
+func appendPath(path []string, elements ...string) []string {
+	return append(append([]string{}, path...), elements...)
+}
@@ -0,0 +1,100 @@
+package mcheck
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestItemModifierChecks(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		expected []string
+	}{
+		{
+			"plain modifier",
+			`{"function": "minecraft:set_count", "count": {"min": 1, "max": 3}}`,
+			nil,
+		},
+		{
+			"block entity source",
+			`{"function": "minecraft:copy_components", "source": "block_entity", "include": ["minecraft:custom_name"]}`,
+			[]string{": warning: at source: copy_components source block_entity is not available to /item modify; only use this modifier from loot tables that provide it [MCHECK056 item-modifier]"},
+		},
+		{
+			"context source object",
+			`{"function": "copy_custom_data", "source": {"type": "minecraft:context", "target": "killer"}, "ops": []}`,
+			[]string{": warning: at source: copy_custom_data source killer is not available to /item modify; only use this modifier from loot tables that provide it [MCHECK056 item-modifier]"},
+		},
+		{
+			"this entity",
+			`{"function": "minecraft:fill_player_head", "entity": "this"}`,
+			nil,
+		},
+		{
+			"tool needed",
+			`{"function": "minecraft:apply_bonus", "enchantment": "minecraft:fortune", "formula": "minecraft:ore_drops"}`,
+			[]string{": warning: at function: apply_bonus needs a tool, which /item modify does not provide; only use this modifier from loot tables that do [MCHECK056 item-modifier]"},
+		},
+		{
+			"bounds",
+			`{"function": "minecraft:sequence", "functions": [
+				{"function": "minecraft:limit_count", "limit": {"min": 5, "max": 2}},
+				{"function": "minecraft:set_damage", "damage": 1.5},
+				{"function": "minecraft:set_enchantments", "enchantments": {"minecraft:sharpness": 300, "minecraft:unbreaking": {"type": "minecraft:uniform", "min": 3, "max": 1}}}
+			]}`,
+			[]string{
+				": warning: at functions.0.limit: limit min 5 is greater than max 2 [MCHECK056 item-modifier]",
+				": warning: at functions.1.damage: damage 1.5 is outside 0..1 and is clamped [MCHECK056 item-modifier]",
+				": warning: at functions.2.enchantments.minecraft:sharpness: minecraft:sharpness level 300 is outside 0..255 and is clamped [MCHECK056 item-modifier]",
+				": warning: at functions.2.enchantments.minecraft:unbreaking: uniform min 3 is greater than max 1 [MCHECK056 item-modifier]",
+			},
+		},
+		{
+			"overridden functions",
+			`[
+				{"function": "minecraft:limit_count", "limit": {"max": 16}},
+				{"function": "minecraft:set_name", "name": "a", "target": "custom_name"},
+				{"function": "minecraft:set_count", "count": 2, "add": true},
+				{"function": "minecraft:set_name", "name": "b", "target": "item_name"},
+				{"function": "minecraft:set_count", "count": 4, "conditions": [{"condition": "minecraft:random_chance", "chance": 0.5}]},
+				{"function": "minecraft:set_count", "count": 8},
+				{"function": "minecraft:set_name", "name": "c", "target": "custom_name"}
+			]`,
+			[]string{
+				": warning: at 0: limit_count has no effect, the set_count at index 5 replaces it [MCHECK056 item-modifier]",
+				": warning: at 1: set_name has no effect, the set_name at index 6 replaces it [MCHECK056 item-modifier]",
+				": warning: at 2: set_count has no effect, the set_count at index 5 replaces it [MCHECK056 item-modifier]",
+				": warning: at 4: set_count has no effect, the set_count at index 5 replaces it [MCHECK056 item-modifier]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.document), &value); err != nil {
+				t.Fatalf("Invalid test document: %v", err)
+			}
+			var findings []Finding
+			switch value := value.(type) {
+			case map[string]interface{}:
+				for _, check := range fileChecks["item_modifier"] {
+					findings = append(findings, check(value)...)
+				}
+			case []interface{}:
+				for _, check := range listChecks["item_modifier"] {
+					findings = append(findings, check(value)...)
+				}
+			}
+			var messages []string
+			for _, finding := range findings {
+				messages = append(messages, finding.String())
+			}
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
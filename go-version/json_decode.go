@@ -0,0 +1,220 @@
+package mcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// nonFiniteLiterals are the number literals Minecraft's lenient JSON reader
+// accepts but encoding/json does not, with the values they stand for
+var nonFiniteLiterals = []struct {
+	literal string
+	value   float64
+}{
+	{"-Infinity", math.Inf(-1)},
+	{"+Infinity", math.Inf(1)},
+	{"Infinity", math.Inf(1)},
+	{"NaN", math.NaN()},
+}
+
+// nonFiniteMarker prefixes the strings that stand in for non-finite numbers
+// while decoding; it is written to the document as a \u0000 escape
+const nonFiniteMarker = "\x00mcheck:"
+
+// decodeJSON decodes a datapack JSON document. Besides standard JSON it
+// accepts the NaN, Infinity and -Infinity literals the game reads, decoding
+// them to the matching float64 values so that range checks can report them,
+// and a UTF-8 byte order mark. Documents that are not UTF-8 fail with an
+// encodingError.
+func decodeJSON(data []byte) (interface{}, error) {
+	data, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+	data = replaceNonFiniteLiterals(data)
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		if incomplete := incompleteJSON(data, err); incomplete != nil {
+			return nil, incomplete
+		}
+		return nil, err
+	}
+	return restoreNonFinite(value), nil
+}
+
+// incompleteError reports a JSON document that is empty or ends before it
+// is complete. Neither happens to files written by hand, so they were
+// likely clobbered by a merge tool or cut off while being copied.
+type incompleteError struct {
+	empty   bool
+	line    int    // the last line of the document, if known
+	closing string // what would close the strings, arrays and objects left open
+}
+
+func (e *incompleteError) Error() string {
+	if e.empty {
+		return "file is empty, did a merge tool clobber it?"
+	}
+	message := "file ends in the middle of its JSON document"
+	if e.line > 0 {
+		message += fmt.Sprintf(" at line %d", e.line)
+	}
+	if e.closing != "" {
+		message += fmt.Sprintf(", %s would close it", e.closing)
+	}
+	return message + "; was it cut off while being copied or merged?"
+}
+
+// incompleteJSON returns an incompleteError if err is data failing to
+// decode because it is empty or ends too soon
+func incompleteJSON(data []byte, err error) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &incompleteError{empty: true}
+	}
+	if !endedTooSoon(err) {
+		return nil
+	}
+	return &incompleteError{
+		line:    bytes.Count(bytes.TrimRight(data, " \t\r\n"), []byte("\n")) + 1,
+		closing: closingDelimiters(data),
+	}
+}
+
+// endedTooSoon reports whether err is encoding/json failing to decode a
+// document that ends before it is complete
+func endedTooSoon(err error) bool {
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) {
+		return syntax.Error() == "unexpected end of JSON input"
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// closingDelimiters returns the quote, brackets and braces that would close
+// the string, arrays and objects left open at the end of data
+func closingDelimiters(data []byte) string {
+	var open []byte
+	inString, escaped := false, false
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			open = append(open, '}')
+		case '[':
+			open = append(open, ']')
+		case '}', ']':
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+		}
+	}
+	closing := ""
+	if inString {
+		closing = `"`
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		closing += string(open[i])
+	}
+	return closing
+}
+
+// jsonError attaches a rule to an error decoding a JSON document. A stream
+// ending before the document does is reported like a file decoded in full,
+// without knowing where it ended.
+func jsonError(err error) error {
+	var encoding *encodingError
+	if errors.As(err, &encoding) {
+		return RuleError{RuleInvalidEncoding, encoding}
+	}
+	var incomplete *incompleteError
+	if errors.As(err, &incomplete) {
+		return RuleError{RuleIncompleteJSON, incomplete}
+	}
+	if endedTooSoon(err) {
+		return RuleError{RuleIncompleteJSON, &incompleteError{}}
+	}
+	return RuleError{RuleInvalidJSON, fmt.Errorf("failed to parse JSON: %w", err)}
+}
+
+// replaceNonFiniteLiterals quotes non-finite number literals outside of
+// strings so that encoding/json can decode the document
+func replaceNonFiniteLiterals(data []byte) []byte {
+	if !bytes.Contains(data, []byte("NaN")) && !bytes.Contains(data, []byte("Infinity")) {
+		return data
+	}
+
+	var result bytes.Buffer
+	inString, escaped := false, false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			result.WriteByte(c)
+			continue
+		}
+		if c == '"' {
+			inString = true
+			result.WriteByte(c)
+			continue
+		}
+
+		replaced := false
+		for _, nonFinite := range nonFiniteLiterals {
+			if bytes.HasPrefix(data[i:], []byte(nonFinite.literal)) {
+				result.WriteString(`"\u0000mcheck:` + nonFinite.literal + `"`)
+				i += len(nonFinite.literal) - 1
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			result.WriteByte(c)
+		}
+	}
+	return result.Bytes()
+}
+
+// restoreNonFinite replaces the marker strings left by replaceNonFiniteLiterals
+// with the numbers they stand for
+func restoreNonFinite(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		for _, nonFinite := range nonFiniteLiterals {
+			if v == nonFiniteMarker+nonFinite.literal {
+				return nonFinite.value
+			}
+		}
+	case map[string]interface{}:
+		for key, element := range v {
+			v[key] = restoreNonFinite(element)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = restoreNonFinite(element)
+		}
+	}
+	return value
+}
@@ -0,0 +1,126 @@
+package mcheck
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSONNonFinite(t *testing.T) {
+	value, err := decodeJSON([]byte(`{"a": NaN, "b": [Infinity, -Infinity, +Infinity], "c": "NaN and Infinity", "d": "say \"NaN\"", "e": 1e3, "f": -0.0}`))
+	if err != nil {
+		t.Fatalf("decodeJSON failed: %v", err)
+	}
+	object := value.(map[string]interface{})
+
+	if a, ok := object["a"].(float64); !ok || !math.IsNaN(a) {
+		t.Errorf("Expected NaN, got %v", object["a"])
+	}
+	b := object["b"].([]interface{})
+	if b[0] != math.Inf(1) || b[1] != math.Inf(-1) || b[2] != math.Inf(1) {
+		t.Errorf("Expected infinities, got %v", b)
+	}
+	if object["c"] != "NaN and Infinity" || object["d"] != `say "NaN"` {
+		t.Errorf("Strings should be left alone, got %q and %q", object["c"], object["d"])
+	}
+	if object["e"] != 1000.0 {
+		t.Errorf("Expected exponent notation to decode to 1000, got %v", object["e"])
+	}
+	if f := object["f"].(float64); f != 0 || !math.Signbit(f) {
+		t.Errorf("Expected negative zero, got %v", f)
+	}
+
+	if _, err := decodeJSON([]byte(`{"a": Nope}`)); err == nil {
+		t.Error("Expected invalid literals to still fail")
+	}
+}
+
+func TestFloatSpecialValues(t *testing.T) {
+	_, ctx := convertSchema(t, "1.20.1", `struct Numbers {
+	chance?: float @ 0..1,
+	positive?: float @ 0<..,
+	unbounded?: float @ 0..,
+	count?: int @ 1..4,
+	free?: float,
+}`)
+	validator := ctx.Definitions["Numbers"]
+
+	tests := []struct {
+		name     string
+		document string
+		error    string
+	}{
+		{"exponent", `{"chance": 5e-1, "count": 2e0}`, ""},
+		{"negative zero in closed range", `{"chance": -0.0}`, ""},
+		{"negative zero in open range", `{"positive": -0.0}`, "must be greater than 0 (range 0<..)"},
+		{"NaN", `{"chance": NaN}`, "value NaN is outside range 0..1"},
+		{"infinity above max", `{"chance": Infinity}`, "(range 0..1)"},
+		{"infinity without max", `{"unbounded": Infinity}`, ""},
+		{"negative infinity", `{"unbounded": -Infinity}`, "(range 0..)"},
+		{"unconstrained NaN", `{"free": NaN}`, ""},
+		{"infinite int", `{"count": Infinity}`, "expected integer, got float (range 1..4)"},
+		{"wrong type", `{"chance": "0.5"}`, "expected float, got string (range 0..1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := decodeJSON([]byte(tt.document))
+			if err != nil {
+				t.Fatalf("Invalid test document: %v", err)
+			}
+			err = findingsError(validator.Validate(document, ctx))
+			switch {
+			case tt.error == "" && err != nil:
+				t.Errorf("Expected valid, got %v", err)
+			case tt.error != "" && (err == nil || !strings.Contains(err.Error(), tt.error)):
+				t.Errorf("Expected error containing %q, got %v", tt.error, err)
+			}
+		})
+	}
+}
+
+func TestIncompleteJSON(t *testing.T) {
+	tests := []struct {
+		document string
+		message  string
+	}{
+		{"", "file is empty, did a merge tool clobber it?"},
+		{" \r\n\t", "file is empty, did a merge tool clobber it?"},
+		{"{\n  \"a\": [1, {\"b\": \"te", `file ends in the middle of its JSON document at line 2, "}]} would close it; was it cut off while being copied or merged?`},
+		{"{\"a\": \"}\", \"b\": tr", "file ends in the middle of its JSON document at line 1, } would close it; was it cut off while being copied or merged?"},
+		{"\n\ntru", "file ends in the middle of its JSON document at line 3; was it cut off while being copied or merged?"},
+	}
+	for _, tt := range tests {
+		_, err := decodeJSON([]byte(tt.document))
+		ruleErr, ok := jsonError(err).(RuleError)
+		if !ok || ruleErr.Rule != RuleIncompleteJSON || err.Error() != tt.message {
+			t.Errorf("Expected %q for %q, got %v", tt.message, tt.document, err)
+		}
+	}
+
+	for _, document := range []string{`{"a": }`, `{"a": 1}}`} {
+		if _, err := decodeJSON([]byte(document)); err == nil || jsonError(err).(RuleError).Rule != RuleInvalidJSON {
+			t.Errorf("Expected %s to be invalid rather than incomplete, got %v", document, err)
+		}
+	}
+}
+
+func TestWarnIncomplete(t *testing.T) {
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, fixtureSchemaDir(t, "damage_type"))
+	path := filepath.Join("data", "test", "damage_type", "acid.json")
+
+	findings := validator.CheckDocument(path, "acid.json", nil)
+	if len(findings) != 1 || findings[0].Rule != RuleIncompleteJSON || findings[0].Severity != SeverityError {
+		t.Errorf("Expected an empty file to be an error, got %v", findings)
+	}
+	validator.SetWarnIncomplete(true)
+	findings = validator.CheckDocument(path, "acid.json", []byte(`{"message_id": "acid", `))
+	if len(findings) != 1 || findings[0].Rule != RuleIncompleteJSON || findings[0].Severity != SeverityWarning {
+		t.Errorf("Expected a cut off file to be a warning, got %v", findings)
+	}
+	findings = validator.CheckDocument(path, "acid.json", []byte(`{"message_id": "acid", }`))
+	if len(findings) != 1 || findings[0].Rule != RuleInvalidJSON || findings[0].Severity != SeverityError {
+		t.Errorf("Expected invalid JSON to remain an error, got %v", findings)
+	}
+}
@@ -0,0 +1,84 @@
+package mcheck
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// locales holds a message catalog for each language findings can be
+// reported in. A catalog maps the English format of each message to its
+// translation. en.json maps every message to itself and is the template for
+// new languages: copy it to <language>.json and translate its values, keeping
+// verbs like %s and %g. Verbs may be reordered with indexes like %[2]s.
+//
+//go:embed locales/*.json
+var locales embed.FS
+
+// Catalog translates the messages of findings into a language
+type Catalog struct {
+	Language string
+	messages map[string]string // English message format -> translated format
+}
+
+// Languages lists the languages that have a catalog
+func Languages() []string {
+	files, _ := fs.Glob(locales, "locales/*.json")
+	var languages []string
+	for _, file := range files {
+		languages = append(languages, strings.TrimSuffix(path.Base(file), ".json"))
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// LoadCatalog loads the catalog of a language, like de, or a catalog file
+// being translated, like ./de.json. English is the language of the messages
+// themselves and has no catalog, so its catalog is nil.
+func LoadCatalog(language string) (*Catalog, error) {
+	if language == "" || language == "en" {
+		return nil, nil
+	}
+
+	var content []byte
+	var err error
+	if strings.HasSuffix(language, ".json") {
+		content, err = os.ReadFile(language)
+	} else if content, err = locales.ReadFile("locales/" + language + ".json"); err != nil {
+		return nil, fmt.Errorf("unknown language %q, expected one of %s or a catalog file", language, strings.Join(Languages(), ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &Catalog{Language: strings.TrimSuffix(filepath.Base(language), ".json")}
+	if err := json.Unmarshal(content, &catalog.messages); err != nil {
+		return nil, fmt.Errorf("invalid catalog %s: %w", language, err)
+	}
+	return catalog, nil
+}
+
+// Localize translates the message of a finding. Messages the catalog does not
+// translate stay English, as do details appended to a message after it was
+// made, like the schema requiring a missing field.
+func (c *Catalog) Localize(finding Finding) Finding {
+	if c == nil || finding.format == "" {
+		return finding
+	}
+	translated := c.messages[finding.format]
+	if translated == "" {
+		return finding
+	}
+	details, ok := strings.CutPrefix(finding.Message, fmt.Sprintf(finding.format, finding.args...))
+	if !ok {
+		return finding
+	}
+	finding.Message = fmt.Sprintf(translated, finding.args...) + details
+	return finding
+}
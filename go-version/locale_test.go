@@ -0,0 +1,124 @@
+package mcheck
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestCatalogTemplate checks that the English catalog, the template for
+// translations, lists exactly the messages validators report
+func TestCatalogTemplate(t *testing.T) {
+	formats := make(map[string]bool)
+	fset := token.NewFileSet()
+	sources, _ := filepath.Glob("*.go")
+	for _, source := range sources {
+		if strings.HasSuffix(source, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, source, nil, 0)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", source, err)
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) < 3 {
+				return true
+			}
+			var name string
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				name = fun.Name
+			case *ast.SelectorExpr:
+				name = fun.Sel.Name
+			}
+			if name != "failf" && name != "warnf" && name != "notef" && name != "structFinding" {
+				return true
+			}
+			if literal, ok := call.Args[2].(*ast.BasicLit); ok {
+				if format, err := strconv.Unquote(literal.Value); err == nil && format != "%s" {
+					formats[format] = true
+				}
+			}
+			return true
+		})
+	}
+
+	content, err := locales.ReadFile("locales/en.json")
+	if err != nil {
+		t.Fatalf("Failed to read the English catalog: %v", err)
+	}
+	var messages map[string]string
+	if err := json.Unmarshal(content, &messages); err != nil {
+		t.Fatalf("Invalid English catalog: %v", err)
+	}
+	for format := range formats {
+		if _, ok := messages[format]; !ok {
+			t.Errorf("locales/en.json is missing %q", format)
+		}
+	}
+	for format, message := range messages {
+		if !formats[format] {
+			t.Errorf("locales/en.json lists %q, which no validator reports", format)
+		}
+		if message != format {
+			t.Errorf("locales/en.json translates %q to %q", format, message)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	catalogFile := filepath.Join(t.TempDir(), "de.json")
+	os.WriteFile(catalogFile, []byte(`{
+		"required field '%s' is missing": "Pflichtfeld '%s' fehlt",
+		"value %g must be greater than or equal to %g (range %s)": "Wert muss mindestens %[2]g sein, ist aber %[1]g (Bereich %[3]s)",
+		"expected string, got %T": ""
+	}`), 0o644)
+	catalog, err := LoadCatalog(catalogFile)
+	if err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+	if catalog.Language != "de" {
+		t.Errorf("Expected language de, got %s", catalog.Language)
+	}
+
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, fixtureSchemaDir(t, "damage_type"))
+	tests := []struct {
+		document string
+		expected string
+	}{
+		{`{"exhaustion": 0.1, "scaling": "never"}`, "Pflichtfeld 'message_id' fehlt; required by damage_type.mcdoc:7"},
+		{`{"message_id": "a", "exhaustion": -1, "scaling": "never"}`, "at exhaustion: Wert muss mindestens 0 sein, ist aber -1 (Bereich 0..)"},
+		{`{"message_id": 1, "exhaustion": 0.1, "scaling": "never"}`, "at message_id: expected string, got float64"},
+		{`{"message_id": "a", "exhaustion": 0.1, "scaling": "sometimes"}`, `at scaling: expected one of "never"`},
+	}
+	for _, tt := range tests {
+		findings := validator.CheckDocument(filepath.Join("data", "test", "damage_type", "x.json"), "x.json", []byte(tt.document))
+		if len(findings) != 1 {
+			t.Fatalf("Expected one finding for %s, got %v", tt.document, findings)
+		}
+		localized := catalog.Localize(findings[0])
+		if !strings.HasPrefix(localized.text(), tt.expected) {
+			t.Errorf("Expected %q, got %q", tt.expected, localized.text())
+		}
+	}
+
+	// English needs no catalog
+	if catalog, err := LoadCatalog("en"); err != nil || catalog != nil {
+		t.Errorf("Expected no catalog for English, got %v (%v)", catalog, err)
+	}
+	var english *Catalog
+	finding := failf(&ValidationContext{}, RuleWrongType, "expected string, got %T", 1.0)[0]
+	if english.Localize(finding).Message != "expected string, got float64" {
+		t.Errorf("Expected the English message, got %s", english.Localize(finding).Message)
+	}
+	if _, err := LoadCatalog("xx"); err == nil || !strings.Contains(err.Error(), "expected one of en") {
+		t.Errorf("Expected an unknown language error, got %v", err)
+	}
+}
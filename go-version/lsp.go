@@ -0,0 +1,337 @@
+package mcheck
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+)
+
+// languageServer publishes the findings of open documents as diagnostics
+// over the Language Server Protocol. Documents are synced in full and
+// checked on every change.
+type languageServer struct {
+	validator *PEGMCDocValidator
+	in        *bufio.Reader
+	out       io.Writer
+
+	// mu is held while handling a message, since schema reloads check the
+	// open documents again from another goroutine
+	mu        sync.Mutex
+	documents map[string]string // text of the open documents by URI
+}
+
+func newLanguageServer(validator *PEGMCDocValidator, in io.Reader, out io.Writer) *languageServer {
+	return &languageServer{validator: validator, in: bufio.NewReader(in), out: out, documents: make(map[string]string)}
+}
+
+// reloadSchemas reloads changed schema files and checks the open documents
+// again
+func (s *languageServer) reloadSchemas(changed []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	logReload(changed, s.validator.reloadSchemas(changed))
+	for uri, text := range s.documents {
+		s.check(uri, text)
+	}
+}
+
+type rpcMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Code     string   `json:"code,omitempty"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspDocument struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+// ServeLanguageServer speaks the Language Server Protocol on in and out
+// until the client exits, publishing the findings of validator for each open
+// document and checking them again when schema files change
+func ServeLanguageServer(validator *PEGMCDocValidator, in io.Reader, out io.Writer) error {
+	server := newLanguageServer(validator, in, out)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, dir := range validator.schemaDirs() {
+		go watchSchemas(ctx, dir, schemaPollInterval, server.reloadSchemas)
+	}
+	return server.Run()
+}
+
+// Run serves requests until the client sends exit or closes the input
+func (s *languageServer) Run() error {
+	for {
+		message, err := s.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if s.handle(message) {
+			return nil
+		}
+	}
+}
+
+// handle answers a message, reporting whether it asked the server to exit
+func (s *languageServer) handle(message *rpcMessage) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch message.Method {
+	case "initialize":
+		s.respond(message.ID, map[string]interface{}{
+			"capabilities": map[string]interface{}{"textDocumentSync": 1},
+			"serverInfo":   map[string]string{"name": "mcheck", "version": BuildVersion},
+		}, nil)
+	case "shutdown":
+		s.respond(message.ID, nil, nil)
+	case "exit":
+		return true
+	case "textDocument/didOpen":
+		var params struct {
+			TextDocument lspDocument `json:"textDocument"`
+		}
+		if json.Unmarshal(message.Params, &params) == nil {
+			s.documents[params.TextDocument.URI] = params.TextDocument.Text
+			s.check(params.TextDocument.URI, params.TextDocument.Text)
+		}
+	case "textDocument/didChange":
+		var params struct {
+			TextDocument   lspDocument `json:"textDocument"`
+			ContentChanges []struct {
+				Text string `json:"text"`
+			} `json:"contentChanges"`
+		}
+		if json.Unmarshal(message.Params, &params) == nil && len(params.ContentChanges) > 0 {
+			text := params.ContentChanges[len(params.ContentChanges)-1].Text
+			s.documents[params.TextDocument.URI] = text
+			s.check(params.TextDocument.URI, text)
+		}
+	case "textDocument/didClose":
+		var params struct {
+			TextDocument lspDocument `json:"textDocument"`
+		}
+		if json.Unmarshal(message.Params, &params) == nil {
+			delete(s.documents, params.TextDocument.URI)
+			s.publish(params.TextDocument.URI, []lspDiagnostic{})
+		}
+	default:
+		// Requests need an answer, notifications can be ignored
+		if len(message.ID) > 0 {
+			s.respond(message.ID, nil, &rpcError{Code: -32601, Message: "method not found: " + message.Method})
+		}
+	}
+	return false
+}
+
+// check publishes the findings of a document as diagnostics. Documents
+// outside a datapack's data directory are left alone.
+func (s *languageServer) check(uri, text string) {
+	path, ok := uriPath(uri)
+	if !ok || !strings.EqualFold(filepath.Ext(path), ".json") {
+		return
+	}
+	if _, err := s.validator.determineResourceType(path); err != nil {
+		return
+	}
+
+	diagnostics := []lspDiagnostic{}
+	for _, finding := range s.validator.CheckDocument(path, uri, []byte(text)) {
+		severity := 1
+		switch finding.Severity {
+		case SeverityWarning:
+			severity = 2
+		case SeverityInfo:
+			severity = 3
+		}
+		// The document is not wrong, it just could not be checked
+		if finding.SchemaProblem() {
+			severity = 3
+		}
+		start, end := locateJSONPath([]byte(text), finding.Path)
+		diagnostics = append(diagnostics, lspDiagnostic{
+			Range:    lspRange{Start: positionAt(text, start), End: positionAt(text, end)},
+			Severity: severity,
+			Code:     finding.Rule,
+			Source:   "mcheck",
+			Message:  finding.Message,
+		})
+	}
+	s.publish(uri, diagnostics)
+}
+
+func (s *languageServer) publish(uri string, diagnostics []lspDiagnostic) {
+	s.write(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "textDocument/publishDiagnostics",
+		"params":  map[string]interface{}{"uri": uri, "diagnostics": diagnostics},
+	})
+}
+
+func (s *languageServer) respond(id json.RawMessage, result interface{}, rpcErr *rpcError) {
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": id}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+	s.write(response)
+}
+
+// read reads a message framed by a Content-Length header
+func (s *languageServer) read() (*rpcMessage, error) {
+	header, err := textproto.NewReader(s.in).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length: %w", err)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return nil, err
+	}
+	var message rpcMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+	return &message, nil
+}
+
+func (s *languageServer) write(message interface{}) {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+// uriPath returns the file path of a file URI
+func uriPath(uri string) (string, bool) {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return "", false
+	}
+	path := parsed.Path
+	// file:///C:/pack/... names a Windows drive
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path), true
+}
+
+// positionAt converts a byte offset into a line and a character offset in
+// UTF-16 code units, as the protocol counts them
+func positionAt(text string, offset int) lspPosition {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	line := strings.Count(text[:offset], "\n")
+	lineStart := strings.LastIndex(text[:offset], "\n") + 1
+	character := 0
+	for _, r := range text[lineStart:offset] {
+		if n := utf16.RuneLen(r); n > 0 {
+			character += n
+		} else {
+			character++
+		}
+	}
+	return lspPosition{Line: line, Character: character}
+}
+
+// locateJSONPath returns the byte range of the value at path in a JSON
+// document, like the path of a ValidationError. Objects and arrays are
+// located by their opening delimiter, so that a finding about a whole object
+// does not cover all of it. If the path does not exist, as for a missing
+// field, the deepest enclosing value is located instead.
+func locateJSONPath(content []byte, path []string) (int, int) {
+	bom := len(content) - len(bytes.TrimPrefix(content, utf8BOM))
+	decoder := json.NewDecoder(bytes.NewReader(content[bom:]))
+	start, end, _ := locateValue(decoder, content[bom:], path)
+	return start + bom, end + bom
+}
+
+func locateValue(decoder *json.Decoder, content []byte, path []string) (int, int, error) {
+	start := skipSeparators(content, int(decoder.InputOffset()))
+	token, err := decoder.Token()
+	if err != nil {
+		return start, start, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return start, start, err
+			}
+			if len(path) > 0 && key == path[0] {
+				return locateValue(decoder, content, path[1:])
+			}
+			if _, _, err := locateValue(decoder, content, nil); err != nil {
+				return start, start, err
+			}
+		}
+	case json.Delim('['):
+		for index := 0; decoder.More(); index++ {
+			if len(path) > 0 && path[0] == "["+strconv.Itoa(index)+"]" {
+				return locateValue(decoder, content, path[1:])
+			}
+			if _, _, err := locateValue(decoder, content, nil); err != nil {
+				return start, start, err
+			}
+		}
+	default:
+		return start, int(decoder.InputOffset()), nil
+	}
+
+	// The closing delimiter of the object or array
+	if _, err := decoder.Token(); err != nil {
+		return start, start, err
+	}
+	return start, start + 1, nil
+}
+
+// skipSeparators skips the whitespace, commas and colons before a value
+func skipSeparators(content []byte, offset int) int {
+	for offset < len(content) && strings.IndexByte(" \t\r\n,:", content[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
@@ -0,0 +1,125 @@
+package mcheck
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLocateJSONPath(t *testing.T) {
+	document := `{
+  "message_id": "fall",
+  "effects": {"sound": [1, "two", {"x": null}]},
+  "ünïcode": "é", "after": true
+}`
+	tests := []struct {
+		path     []string
+		expected string
+		line     int
+		char     int
+	}{
+		{[]string{"message_id"}, `"fall"`, 1, 16},
+		{[]string{"effects", "sound", "[1]"}, `"two"`, 2, 27},
+		{[]string{"effects", "sound", "[2]", "x"}, `null`, 2, 40},
+		{[]string{"effects", "sound"}, `[`, 2, 23},
+		{[]string{"effects", "missing"}, `{`, 2, 13},
+		{[]string{"after"}, `true`, 3, 27},
+		{nil, `{`, 0, 0},
+	}
+
+	for _, tt := range tests {
+		start, end := locateJSONPath([]byte(document), tt.path)
+		if got := document[start:end]; got != tt.expected {
+			t.Errorf("%v: expected %s, located %s", tt.path, tt.expected, got)
+		}
+		if position := positionAt(document, start); position.Line != tt.line || position.Character != tt.char {
+			t.Errorf("%v: expected %d:%d, got %d:%d", tt.path, tt.line, tt.char, position.Line, position.Character)
+		}
+	}
+}
+
+func TestLanguageServer(t *testing.T) {
+	targetVersion, _ := ParseVersion("1.20.1")
+	validator := NewPEGMCDocValidator(targetVersion, fixtureSchemaDir(t, "damage_type"))
+
+	uri := "file:///packs/test/data/test/damage_type/fall.json"
+	var input strings.Builder
+	send := func(message string) {
+		fmt.Fprintf(&input, "Content-Length: %d\r\n\r\n%s", len(message), message)
+	}
+	send(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {}}`)
+	send(`{"jsonrpc": "2.0", "method": "initialized", "params": {}}`)
+	send(`{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": {"textDocument": {"uri": "` + uri + `", "text": "{\"message_id\": \"fall\", \"exhaustion\": -1, \"scaling\": \"never\"}"}}}`)
+	send(`{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": {"textDocument": {"uri": "file:///notes/todo.json", "text": "{}"}}}`)
+	send(`{"jsonrpc": "2.0", "method": "textDocument/didChange", "params": {"textDocument": {"uri": "` + uri + `"}, "contentChanges": [{"text": "{\"message_id\": \"fall\", \"exhaustion\": 0, \"scaling\": \"never\"}"}]}}`)
+	send(`{"jsonrpc": "2.0", "id": 2, "method": "textDocument/hover", "params": {}}`)
+	send(`{"jsonrpc": "2.0", "id": 3, "method": "shutdown"}`)
+	send(`{"jsonrpc": "2.0", "method": "exit"}`)
+
+	output, writer := io.Pipe()
+	go func() {
+		if err := newLanguageServer(validator, strings.NewReader(input.String()), writer).Run(); err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+		writer.Close()
+	}()
+
+	var messages []map[string]interface{}
+	reader := bufio.NewReader(output)
+	for {
+		header, err := textproto.NewReader(reader).ReadMIMEHeader()
+		if err != nil {
+			break
+		}
+		length, _ := strconv.Atoi(header.Get("Content-Length"))
+		body := make([]byte, length)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			t.Fatalf("Failed to read message: %v", err)
+		}
+		var message map[string]interface{}
+		if err := json.Unmarshal(body, &message); err != nil {
+			t.Fatalf("Invalid message %s: %v", body, err)
+		}
+		messages = append(messages, message)
+	}
+
+	if len(messages) != 5 {
+		t.Fatalf("Expected 5 messages, got %d: %v", len(messages), messages)
+	}
+	if messages[0]["id"] != 1.0 || messages[0]["result"] == nil {
+		t.Errorf("Expected an initialize result, got %v", messages[0])
+	}
+
+	diagnostics := func(message map[string]interface{}) []interface{} {
+		params, _ := message["params"].(map[string]interface{})
+		if message["method"] != "textDocument/publishDiagnostics" || params["uri"] != uri {
+			t.Fatalf("Expected diagnostics for %s, got %v", uri, message)
+		}
+		list, _ := params["diagnostics"].([]interface{})
+		return list
+	}
+	opened := diagnostics(messages[1])
+	if len(opened) != 1 {
+		t.Fatalf("Expected one diagnostic, got %v", opened)
+	}
+	diagnostic := opened[0].(map[string]interface{})
+	start := diagnostic["range"].(map[string]interface{})["start"].(map[string]interface{})
+	if diagnostic["code"] != RuleOutOfRange || start["character"] != 37.0 || diagnostic["severity"] != 1.0 {
+		t.Errorf("Expected an out of range error at the exhaustion value, got %v", diagnostic)
+	}
+	if changed := diagnostics(messages[2]); len(changed) != 0 {
+		t.Errorf("Expected the fixed document to have no diagnostics, got %v", changed)
+	}
+
+	if messages[3]["id"] != 2.0 || messages[3]["error"] == nil {
+		t.Errorf("Expected an error for the unsupported request, got %v", messages[3])
+	}
+	if result, ok := messages[4]["result"]; messages[4]["id"] != 3.0 || !ok || result != nil {
+		t.Errorf("Expected a null shutdown result, got %v", messages[4])
+	}
+}
@@ -0,0 +1,243 @@
+package mcheck
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// moduleOf returns the module path of a schema file relative to the schema
+// directory or the extra schemas holding it, like ["java", "data", "worldgen", "biome"] for
+// java/data/worldgen/biome.mcdoc. A mod.mcdoc file is the module of its directory.
+func (v *PEGMCDocValidator) moduleOf(schemaPath string) []string {
+	root, _ := v.schemaRoot(schemaPath)
+	rel, err := filepath.Rel(root, schemaPath)
+	if err != nil {
+		return nil
+	}
+	module := strings.Split(filepath.ToSlash(strings.TrimSuffix(rel, ".mcdoc")), "/")
+	if module[len(module)-1] == "mod" {
+		module = module[:len(module)-1]
+	}
+	return module
+}
+
+// moduleFile finds the schema file of a module, either <module>.mcdoc or
+// <module>/mod.mcdoc, returning "" if neither exists
+func (v *PEGMCDocValidator) moduleFile(module []string) string {
+	if len(module) == 0 {
+		return ""
+	}
+	return v.schemaIndex().ModuleFile(module)
+}
+
+// resolveModule returns the module a path like super::util::Weighted or
+// ::java::util::text::Text names a type in, relative to the module of the
+// file the path appears in. Each leading super goes up one module. Paths of
+// a single segment name local types and have no module.
+func resolveModule(current []string, path Path) ([]string, bool) {
+	if len(path.Segments) < 2 && !path.IsAbsolute {
+		return nil, false
+	}
+
+	var module []string
+	if !path.IsAbsolute {
+		module = append(module, current...)
+	}
+	for _, segment := range path.Segments[:len(path.Segments)-1] {
+		if segment.IsSuper {
+			if len(module) == 0 {
+				return nil, false
+			}
+			module = module[:len(module)-1]
+			continue
+		}
+		module = append(module, segment.Value)
+	}
+	return module, true
+}
+
+// parsePath splits a path like super::util::Weighted, which may have spaces
+// around its separators, into its segments
+func parsePath(text string) Path {
+	text = strings.Join(strings.Fields(text), "")
+	path := Path{IsAbsolute: strings.HasPrefix(text, "::")}
+	for _, segment := range strings.Split(strings.TrimPrefix(text, "::"), "::") {
+		path.Segments = append(path.Segments, PathSegment{Value: segment, IsSuper: segment == "super"})
+	}
+	return path
+}
+
+// importFiles returns the schema files that the statements of schemaPath
+// refer to through use statements and module paths, the files declaring
+// cases of the dispatchers they dispatch to, and the files injecting into the
+// types they declare, following their own references in turn. Files that are missing or fail to parse are skipped, leaving the
+// types they define to be accepted as any.
+func (v *PEGMCDocValidator) importFiles(schemaPath string, statements []Statement) []string {
+	type pending struct {
+		module     []string
+		statements []Statement
+	}
+
+	loaded := map[string]bool{filepath.Clean(schemaPath): true}
+	queue := []pending{{v.moduleOf(schemaPath), statements}}
+	var files []string
+	load := func(file string, module []string) {
+		if file == "" || loaded[filepath.Clean(file)] {
+			return
+		}
+		loaded[filepath.Clean(file)] = true
+
+		imported, err := v.parsedSchema(file)
+		if err != nil {
+			return
+		}
+		files = append(files, file)
+		queue = append(queue, pending{module, imported})
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, path := range statementPaths(current.statements) {
+			module, ok := resolveModule(current.module, path)
+			if !ok {
+				continue
+			}
+			// Prefer the file declaring the type, falling back to the
+			// module for paths naming something the index does not know
+			file := v.schemaIndex().TypeFile(module, path.Segments[len(path.Segments)-1].Value)
+			if file == "" {
+				file = v.moduleFile(module)
+			}
+			load(file, module)
+		}
+		for _, registry := range statementDispatchers(current.statements) {
+			for _, file := range v.schemaIndex().DispatcherFiles(registry) {
+				load(file, v.moduleOf(file))
+			}
+		}
+		for _, name := range declaredTypes(current.statements) {
+			for _, file := range v.schemaIndex().InjectionFiles(current.module, name) {
+				load(file, v.moduleOf(file))
+			}
+		}
+	}
+	return files
+}
+
+// loadImports parses the schema files importFiles found
+func (v *PEGMCDocValidator) loadImports(files []string) []Statement {
+	var imports []Statement
+	for _, file := range files {
+		imported, _ := v.parsedSchema(file)
+		imports = append(imports, imported...)
+	}
+	return imports
+}
+
+// statementPaths collects the paths used by statements, in use statements
+// and in the types they declare
+func statementPaths(statements []Statement) []Path {
+	var paths []Path
+	walkStatements(statements, func(expr Expression) {
+		if path, ok := expr.(Path); ok {
+			paths = append(paths, path)
+		}
+	})
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case UseStatement:
+			paths = append(paths, s.Path)
+		case InjectStatement:
+			paths = append(paths, s.Path)
+		}
+	}
+	return paths
+}
+
+// declaredTypes collects the names of the top level types statements
+// declare, including the structs dispatch statements declare
+func declaredTypes(statements []Statement) []string {
+	var names []string
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case StructStatement:
+			names = append(names, s.Name.Name)
+		case EnumStatement:
+			names = append(names, s.Name.Name)
+		case TypeAliasStatement:
+			names = append(names, s.Name.Name)
+		case DispatchStatement:
+			if target, ok := s.Target.(StructExpression); ok && target.Name != nil {
+				names = append(names, target.Name.Name)
+			}
+		}
+	}
+	return names
+}
+
+// statementDispatchers collects the dispatchers the types of statements
+// dispatch to, like minecraft:feature_config, whose cases may be declared
+// in any schema file
+func statementDispatchers(statements []Statement) []string {
+	var registries []string
+	seen := make(map[string]bool)
+	walkStatements(statements, func(expr Expression) {
+		if dispatch, ok := expr.(DispatchExpression); ok && !seen[dispatch.Registry] {
+			seen[dispatch.Registry] = true
+			registries = append(registries, dispatch.Registry)
+		}
+	})
+	return registries
+}
+
+// walkStatements calls visit for every type expression of the types
+// statements declare
+func walkStatements(statements []Statement, visit func(Expression)) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case StructStatement:
+			walkExpression(s.Struct, visit)
+		case TypeAliasStatement:
+			walkExpression(s.Type, visit)
+		case DispatchStatement:
+			walkExpression(s.Target, visit)
+		case InjectStatement:
+			walkExpression(s.Struct, visit)
+		}
+	}
+}
+
+// walkExpression calls visit for a type expression and every expression
+// within it
+func walkExpression(expr Expression, visit func(Expression)) {
+	visit(expr)
+	switch e := expr.(type) {
+	case GenericExpression:
+		walkExpression(e.Base, visit)
+		for _, arg := range e.Args {
+			walkExpression(arg, visit)
+		}
+	case DispatchExpression:
+		for _, arg := range e.Args {
+			walkExpression(arg, visit)
+		}
+	case UnionExpression:
+		for _, alt := range e.Alternatives {
+			walkExpression(alt, visit)
+		}
+	case ArrayExpression:
+		walkExpression(e.Element, visit)
+	case ConstrainedExpression:
+		walkExpression(e.Type, visit)
+	case AttributedExpression:
+		walkExpression(e.Type, visit)
+	case StructExpression:
+		for _, field := range e.Fields {
+			if field.Key != nil {
+				walkExpression(field.Key, visit)
+			}
+			walkExpression(field.Type, visit)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package mcheck
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveModule(t *testing.T) {
+	biome := []string{"java", "data", "worldgen", "biome"}
+	tests := []struct {
+		path     string
+		module   []string
+		resolved bool
+	}{
+		{"Biome", nil, false},
+		{"super::Carvers", []string{"java", "data", "worldgen"}, true},
+		{"super::super::util::Weighted", []string{"java", "data", "util"}, true},
+		{"::java::util::text::Text", []string{"java", "util", "text"}, true},
+		{"noise::NoiseRange", []string{"java", "data", "worldgen", "biome", "noise"}, true},
+		{"super::super::super::super::super::Root", nil, false},
+	}
+
+	for _, tt := range tests {
+		module, resolved := resolveModule(biome, parsePath(tt.path))
+		if resolved != tt.resolved || !reflect.DeepEqual(module, tt.module) {
+			t.Errorf("resolveModule(%s) = %v, %v; expected %v, %v", tt.path, module, resolved, tt.module, tt.resolved)
+		}
+	}
+}
+
+func TestCrossFileModules(t *testing.T) {
+	root := t.TempDir()
+	schemaDir := filepath.Join(root, "vanilla-mcdoc")
+	for name, content := range map[string]string{
+		"java/data/worldgen/biome.mcdoc": `use super::super::util::Weighted
+use ::java::util::text::Text
+
+dispatch minecraft:resource["worldgen/biome"] to struct Biome {
+	name: Text,
+	carvers: super::Carvers,
+	spawns: [Weighted],
+	...super::Shared,
+}
+`,
+		"java/data/worldgen/mod.mcdoc": `struct Shared {
+	temperature: float @ -2..2,
+}
+
+enum(string) Carvers {
+	Cave = "cave",
+	Canyon = "canyon",
+}
+`,
+		"java/data/util.mcdoc": `struct Weighted {
+	weight: int @ 1..,
+	color?: super::super::util::color::Color,
+}
+`,
+		"java/util/color.mcdoc": `type Color = int @ 0..16777215
+`,
+		"java/util/text.mcdoc": `type Text = (string | struct TextComponent {
+	text: string,
+})
+`,
+	} {
+		path := filepath.Join(schemaDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	valid := `{"name": {"text": "Plains"}, "carvers": "cave", "spawns": [{"weight": 2, "color": 255}], "temperature": 0.8}`
+	tests := []struct {
+		name     string
+		document string
+		error    string
+	}{
+		{"valid", valid, ""},
+		{"enum from parent module", strings.Replace(valid, `"cave"`, `"ravine"`, 1), "at carvers: expected one of"},
+		{"struct from grandparent module", strings.Replace(valid, `"weight": 2`, `"weight": 0`, 1), "at spawns.[0].weight: value 0 must be greater than or equal to 1"},
+		{"import of an import", strings.Replace(valid, `"color": 255`, `"color": 33554432`, 1), "at spawns.[0].color: value 3.3554432e+07 must be less than or equal to 1.6777215e+07"},
+		{"absolute import", strings.Replace(valid, `{"text": "Plains"}`, `{"txt": "Plains"}`, 1), "at name: value does not match any union alternative"},
+		{"spread from parent module", strings.Replace(valid, `, "temperature": 0.8`, ``, 1), "required field 'temperature' is missing"},
+	}
+
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, schemaDir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonPath := filepath.Join(t.TempDir(), "data", "test", "worldgen", "biome", "plains.json")
+			if err := os.MkdirAll(filepath.Dir(jsonPath), 0o755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(jsonPath, []byte(tt.document), 0o644); err != nil {
+				t.Fatalf("Failed to write %s: %v", jsonPath, err)
+			}
+			err := validator.ValidateJSON(jsonPath)
+			switch {
+			case tt.error == "" && err != nil:
+				t.Errorf("Expected valid, got %v", err)
+			case tt.error != "" && (err == nil || !strings.Contains(err.Error(), tt.error)):
+				t.Errorf("Expected error containing %q, got %v", tt.error, err)
+			}
+		})
+	}
+}
+
+func TestInjections(t *testing.T) {
+	root := t.TempDir()
+	schemaDir := filepath.Join(root, "vanilla-mcdoc")
+	for name, content := range map[string]string{
+		"java/data/thing.mcdoc": `use ::java::util::Item as ItemLike
+use ::java::util::Mode
+
+dispatch minecraft:resource[thing] to struct Thing {
+	item: ItemLike,
+	mode: Mode,
+}
+`,
+		"java/util/mod.mcdoc": `struct Item {
+	id: string,
+}
+
+enum(string) Mode {
+	Fast = "fast",
+}
+`,
+		"java/mods/extra.mcdoc": `inject struct ::java::util::Item {
+	count?: int @ 1..99,
+}
+
+inject enum(string) super::util::Mode {
+	#[since="1.21"]
+	Slow = "slow",
+}
+
+#[since="1.21"]
+inject struct ::java::data::thing::Thing {
+	extra?: boolean,
+}
+`,
+	} {
+		path := filepath.Join(schemaDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	valid := `{"item": {"id": "stone", "count": 2}, "mode": "slow", "extra": true}`
+	tests := []struct {
+		name     string
+		version  Version
+		document string
+		error    string
+	}{
+		{"valid", Version{1, 21, 0}, valid, ""},
+		{"field injected into an aliased import", Version{1, 21, 0}, strings.Replace(valid, `"count": 2`, `"count": 100`, 1), "at item.count: value 100 must be less than or equal to 99"},
+		{"declared fields are kept", Version{1, 21, 0}, strings.Replace(valid, `"id": "stone", `, ``, 1), "required field 'id' is missing"},
+		{"enum value before its version", Version{1, 20, 1}, strings.Replace(valid, `, "extra": true`, ``, 1), "at mode: expected one of"},
+		{"injection into a dispatched struct", Version{1, 21, 0}, strings.Replace(valid, `true`, `1`, 1), "at extra: expected boolean"},
+		{"injection before its version", Version{1, 20, 1}, strings.Replace(valid, `"slow"`, `"fast"`, 1), "unexpected field 'extra'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonPath := filepath.Join(t.TempDir(), "data", "test", "thing", "example.json")
+			if err := os.MkdirAll(filepath.Dir(jsonPath), 0o755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(jsonPath, []byte(tt.document), 0o644); err != nil {
+				t.Fatalf("Failed to write %s: %v", jsonPath, err)
+			}
+			err := NewPEGMCDocValidator(tt.version, schemaDir).ValidateJSON(jsonPath)
+			switch {
+			case tt.error == "" && err != nil:
+				t.Errorf("Expected valid, got %v", err)
+			case tt.error != "" && (err == nil || !strings.Contains(err.Error(), tt.error)):
+				t.Errorf("Expected error containing %q, got %v", tt.error, err)
+			}
+		})
+	}
+}
+
+func TestExtraSchemas(t *testing.T) {
+	root := t.TempDir()
+	schemaDir := filepath.Join(root, "vanilla-mcdoc")
+	extraDir := filepath.Join(root, "create-mcdoc")
+	for name, content := range map[string]string{
+		"vanilla-mcdoc/java/data/recipe.mcdoc": `dispatch minecraft:resource[recipe] to struct Recipe {
+	type: string,
+	...minecraft:recipe_serializer[[type]],
+}
+
+dispatch minecraft:recipe_serializer[smelting] to struct Smelting {
+	ingredient: super::util::Ingredient,
+}
+`,
+		"vanilla-mcdoc/java/data/util.mcdoc": `struct Ingredient {
+	item: string,
+}
+`,
+		"create-mcdoc/java/data/create/recipe.mcdoc": `dispatch minecraft:recipe_serializer["create:mixing"] to struct Mixing {
+	ingredients: [super::super::util::Ingredient],
+}
+`,
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	tests := []struct {
+		document string
+		expected string
+	}{
+		{`{"type": "create:mixing", "ingredients": [{"item": "minecraft:sugar"}]}`, ""},
+		{`{"type": "create:mixing", "ingredients": [{}]}`, "required field 'item' is missing"},
+		{`{"type": "create:mixin", "ingredients": []}`, `"create:mixin" is not a recipe_serializer the extra schemas declare, did you mean "create:mixing"?`},
+		{`{"type": "smeltin", "ingredient": {"item": "minecraft:sand"}}`, `"smeltin" is not a known recipe_serializer, did you mean "smelting"?`},
+	}
+
+	validator, err := Config{Version: "1.20.1", SchemaDir: schemaDir, Edition: "java", ExtraSchemas: []string{extraDir}}.NewValidator()
+	if err != nil {
+		t.Fatalf("NewValidator failed: %v", err)
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, finding := range validator.CheckDocument("data/test/recipe/mix.json", "mix.json", []byte(tt.document)) {
+			got = append(got, finding.Message)
+		}
+		if text := strings.Join(got, "\n"); tt.expected == "" && text != "" || !strings.Contains(text, tt.expected) {
+			t.Errorf("Expected %q for %s, got %q", tt.expected, tt.document, got)
+		}
+	}
+
+	if _, err := (Config{Version: "1.20.1", SchemaDir: schemaDir, Edition: "java", ExtraSchemas: []string{filepath.Join(root, "missing")}}).NewValidator(); err == nil {
+		t.Error("Expected an error for extra schemas that do not exist")
+	}
+}
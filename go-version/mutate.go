@@ -0,0 +1,259 @@
+package mcheck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Mutation is a change to a valid document that the schema should reject
+// with Rule, like deleting a required field or giving a value the wrong type
+type Mutation struct {
+	Name   string   // kind of mutation, like "delete" or "wrong-type"
+	Path   []string // path of the mutated value, as in ValidationError
+	Rule   string   // rule expected to reject the mutated document
+	Delete bool     // remove the value instead of replacing it
+	Value  interface{}
+}
+
+// Apply returns a copy of document with the mutation applied
+func (m Mutation) Apply(document interface{}) interface{} {
+	result := copyJSON(document)
+	if len(m.Path) == 0 {
+		return m.Value
+	}
+
+	current := result
+	for i, segment := range m.Path {
+		last := i == len(m.Path)-1
+		switch container := current.(type) {
+		case map[string]interface{}:
+			if !last {
+				current = container[segment]
+			} else if m.Delete {
+				delete(container, segment)
+			} else {
+				container[segment] = m.Value
+			}
+		case []interface{}:
+			index, err := strconv.Atoi(strings.Trim(segment, "[]"))
+			if err != nil || index >= len(container) {
+				return result
+			}
+			if !last {
+				current = container[index]
+			} else {
+				container[index] = m.Value
+			}
+		}
+	}
+	return result
+}
+
+// String names the mutation like "missing-field at pools.[0].rolls"
+func (m Mutation) String() string {
+	if len(m.Path) == 0 {
+		return ruleName(m.Rule)
+	}
+	return fmt.Sprintf("%s at %s", ruleName(m.Rule), strings.Join(m.Path, "."))
+}
+
+// Mutate derives mutations from a valid JSON file, one for each required
+// field that can be deleted and each value that can be given the wrong type,
+// an out of range number or a value outside its enum. Values below unions are
+// left alone since any alternative might accept the mutated value.
+func (v *PEGMCDocValidator) Mutate(jsonPath string) (interface{}, []Mutation, error) {
+	converter, mainValidator, err := v.loadSchemaFor(jsonPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := v.files.ReadFile(jsonPath)
+	if err != nil {
+		return nil, nil, RuleError{RuleUnreadableFile, fmt.Errorf("failed to read JSON file: %w", err)}
+	}
+	document, err := decodeJSON(content)
+	if err != nil {
+		return nil, nil, jsonError(err)
+	}
+
+	m := &mutator{seen: make(map[string]bool)}
+	m.walk(document, mainValidator, v.newContext(converter), 0)
+	return document, m.mutations, nil
+}
+
+type mutator struct {
+	mutations []Mutation
+	seen      map[string]bool
+}
+
+func (m *mutator) add(mutation Mutation) {
+	key := mutation.Name + " " + strings.Join(mutation.Path, "\x00")
+	if m.seen[key] {
+		return
+	}
+	m.seen[key] = true
+	m.mutations = append(m.mutations, mutation)
+}
+
+func (m *mutator) walk(value interface{}, validator Validator, ctx *ValidationContext, depth int) {
+	if validator == nil || depth > maxSpreadDepth || !validator.AppliesForVersion(ctx) {
+		return
+	}
+
+	switch v := validator.(type) {
+	case *ReferenceValidator:
+		m.walk(value, ctx.Definitions[v.TypeName], ctx, depth+1)
+	case *AttributedValidator:
+		m.walk(value, v.InnerValidator, ctx, depth+1)
+	case *DispatchValidator:
+		m.walk(value, v.Resolve(value, ctx), ctx, depth+1)
+	case *ConstrainedValidator:
+		if _, isString := value.(string); !isString {
+			if rangeValidator, ok := v.Constraint.(*RangeValidator); ok {
+				if outside, ok := outOfRange(rangeValidator, v.InnerValidator); ok {
+					m.add(Mutation{Name: "out-of-range", Path: ctx.Path, Rule: RuleOutOfRange, Value: outside})
+				}
+			}
+		}
+		m.walk(value, v.InnerValidator, ctx, depth+1)
+	case *PrimitiveValidator:
+		if wrong, ok := wrongType(v.Type); ok {
+			m.add(Mutation{Name: "wrong-type", Path: ctx.Path, Rule: RuleWrongType, Value: wrong})
+		}
+	case *EnumValidator:
+		if invalid, ok := invalidEnumValue(v); ok {
+			m.add(Mutation{Name: "bad-enum", Path: ctx.Path, Rule: RuleInvalidEnumValue, Value: invalid})
+		}
+	case *ArrayValidator:
+		m.add(Mutation{Name: "wrong-type", Path: ctx.Path, Rule: RuleWrongType, Value: "mcheck"})
+		if arr, ok := value.([]interface{}); ok && len(arr) > 0 {
+			m.walk(arr[0], v.ElementValidator, ctx.WithParent(arr).Child("[0]"), depth+1)
+		}
+	case *StructValidator:
+		if obj, ok := value.(map[string]interface{}); ok {
+			m.walkStruct(obj, v, ctx, ctx.WithParent(obj), nil, depth)
+		}
+	}
+}
+
+// walkStruct mutates the fields of obj that sv and its spreads describe
+func (m *mutator) walkStruct(obj map[string]interface{}, sv *StructValidator, ctx, objCtx *ValidationContext, overridden map[string]bool, depth int) {
+	for _, field := range sv.Fields {
+		fieldValue, exists := obj[field.Name]
+		if !exists || !field.AppliesForVersion(ctx) || overridden[field.Name] {
+			continue
+		}
+		fieldCtx := objCtx.Child(field.Name)
+		if !field.Optional {
+			m.add(Mutation{Name: "delete", Path: fieldCtx.Path, Rule: RuleMissingField, Delete: true})
+		}
+		m.walk(fieldValue, field.Validator, fieldCtx, depth+1)
+	}
+
+	declared := sv.declaredFields(ctx, overridden)
+	for _, spread := range sv.SpreadFields {
+		spreadStruct, ok := resolveSpread(spread, obj, objCtx, depth)
+		if ok && spreadStruct != nil && spreadStruct.AppliesForVersion(ctx) {
+			m.walkStruct(obj, spreadStruct, ctx, objCtx, declared, depth+1)
+		}
+	}
+}
+
+// wrongType returns a value of a different type than a primitive accepts
+func wrongType(primitive string) (interface{}, bool) {
+	switch primitive {
+	case "string":
+		return float64(0), true
+	case "byte", "short", "int", "long", "float", "double", "boolean":
+		return "mcheck", true
+	}
+	return nil, false
+}
+
+// outOfRange returns a number just outside a range, keeping integers whole
+func outOfRange(rv *RangeValidator, inner Validator) (float64, bool) {
+	step := 1.0
+	if primitive, ok := inner.(*PrimitiveValidator); ok && !isIntegerType(primitive.Type) {
+		step = 0.5
+	}
+	switch {
+	case rv.Max != nil && rv.MaxExclusive:
+		return *rv.Max, true
+	case rv.Max != nil:
+		return *rv.Max + step, true
+	case rv.Min != nil && rv.MinExclusive:
+		return *rv.Min, true
+	case rv.Min != nil:
+		return *rv.Min - step, true
+	}
+	return 0, false
+}
+
+// invalidEnumValue returns a value of an enum's type that none of its values match
+func invalidEnumValue(ev *EnumValidator) (interface{}, bool) {
+	if ev.Type == "string" {
+		return "mcheck_invalid", true
+	}
+	largest := 0.0
+	for _, value := range ev.Values {
+		if number, ok := value.Value.(float64); ok && number > largest {
+			largest = number
+		}
+	}
+	return largest + 1, true
+}
+
+// copyJSON deep copies a decoded JSON value
+func copyJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			result[key] = copyJSON(elem)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, elem := range v {
+			result[i] = copyJSON(elem)
+		}
+		return result
+	}
+	return value
+}
+
+// mutationFileName replaces the characters of a path that are awkward in file names
+var mutationFileName = strings.NewReplacer(":", "_", "/", "_", "[", "", "]", "")
+
+// WriteMutations writes every mutation of a valid JSON file below
+// outDir/data/<type>, naming each file after the rule expected to reject it
+func (v *PEGMCDocValidator) WriteMutations(jsonPath, outDir string) error {
+	document, mutations, err := v.Mutate(jsonPath)
+	if err != nil {
+		return err
+	}
+	resourceType, err := v.determineResourceType(jsonPath)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(outDir, "data", filepath.FromSlash(resourceType))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	base := strings.TrimSuffix(filepath.Base(jsonPath), filepath.Ext(jsonPath))
+	for _, mutation := range mutations {
+		content, err := v.MarshalDocument(resourceType, mutation.Apply(document))
+		if err != nil {
+			return err
+		}
+		name := mutationFileName.Replace(strings.Join(append([]string{base, ruleName(mutation.Rule)}, mutation.Path...), "."))
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), append(content, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("wrote %d mutations of %s to %s\n", len(mutations), jsonPath, dir)
+	return nil
+}
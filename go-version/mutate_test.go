@@ -0,0 +1,104 @@
+package mcheck
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMutationApply(t *testing.T) {
+	document := map[string]interface{}{
+		"name":  "a",
+		"pools": []interface{}{map[string]interface{}{"rolls": float64(1)}},
+	}
+
+	deleted := Mutation{Path: []string{"name"}, Delete: true}.Apply(document)
+	if _, ok := deleted.(map[string]interface{})["name"]; ok {
+		t.Errorf("Expected name to be deleted, got %v", deleted)
+	}
+
+	replaced := Mutation{Path: []string{"pools", "[0]", "rolls"}, Value: "mcheck"}.Apply(document)
+	expected := map[string]interface{}{
+		"name":  "a",
+		"pools": []interface{}{map[string]interface{}{"rolls": "mcheck"}},
+	}
+	if !reflect.DeepEqual(replaced, expected) {
+		t.Errorf("Expected %v, got %v", expected, replaced)
+	}
+
+	// The original document is left alone
+	if document["name"] != "a" || document["pools"].([]interface{})[0].(map[string]interface{})["rolls"] != float64(1) {
+		t.Errorf("Apply modified the original document: %v", document)
+	}
+}
+
+// TestMutatedFixtures mutates every good registry fixture and checks that
+// each mutation is rejected with the rule it expects
+func TestMutatedFixtures(t *testing.T) {
+	total := 0
+	for _, tt := range registryFixtures {
+		validator := NewPEGMCDocValidator(tt.version, fixtureSchemaDir(t, tt.schema))
+		good, _ := filepath.Glob(filepath.Join("tests", "good", "data", tt.resourceType, "*.json"))
+
+		for _, path := range good {
+			document, mutations, err := validator.Mutate(path)
+			if err != nil {
+				t.Fatalf("Failed to mutate %s: %v", path, err)
+			}
+			total += len(mutations)
+
+			for _, mutation := range mutations {
+				t.Run(path+" "+mutation.String(), func(t *testing.T) {
+					content, err := json.Marshal(mutation.Apply(document))
+					if err != nil {
+						t.Fatalf("Failed to encode mutation: %v", err)
+					}
+					mutated := filepath.Join(t.TempDir(), "data", filepath.FromSlash(tt.resourceType), "mutated.json")
+					if err := os.MkdirAll(filepath.Dir(mutated), 0o755); err != nil {
+						t.Fatalf("Failed to create directory: %v", err)
+					}
+					if err := os.WriteFile(mutated, content, 0o644); err != nil {
+						t.Fatalf("Failed to write mutation: %v", err)
+					}
+
+					findings := validator.CheckFile(mutated, mutated)
+					if finding, ok := firstError(findings); !ok || finding.Rule != mutation.Rule {
+						t.Errorf("Expected %s finding for %s, got %v", mutation.Rule, content, findings)
+					}
+				})
+			}
+		}
+	}
+	if total < 50 {
+		t.Errorf("Expected at least 50 mutations of the good fixtures, got %d", total)
+	}
+}
+
+func TestWriteMutations(t *testing.T) {
+	validator := NewPEGMCDocValidator(Version{1, 21, 0}, fixtureSchemaDir(t, "painting"))
+	out := t.TempDir()
+	if err := validator.WriteMutations(filepath.Join("tests", "good", "data", "painting_variant", "kebab.json"), out); err != nil {
+		t.Fatalf("WriteMutations failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(out, "data", "painting_variant"))
+	if err != nil {
+		t.Fatalf("Failed to read mutations: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	for _, expected := range []string{
+		"kebab.missing-field.width.json",
+		"kebab.out-of-range.width.json",
+		"kebab.wrong-type.asset_id.json",
+	} {
+		if !strings.Contains(strings.Join(names, " "), expected) {
+			t.Errorf("Expected %s among %v", expected, names)
+		}
+	}
+}
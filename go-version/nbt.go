@@ -0,0 +1,222 @@
+package mcheck
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// NBT tag types
+const (
+	tagEnd byte = iota
+	tagByte
+	tagShort
+	tagInt
+	tagLong
+	tagFloat
+	tagDouble
+	tagByteArray
+	tagString
+	tagList
+	tagCompound
+	tagIntArray
+	tagLongArray
+)
+
+// maxNBTDepth bounds the nesting of lists and compounds, like the game does
+const maxNBTDepth = 512
+
+// ReadNBT reads a binary NBT document with a compound root tag, which may be
+// gzip compressed. Values are decoded into the same shapes encoding/json
+// produces so that they can be validated against mcdoc schemas: compounds
+// become maps, lists and arrays become slices and all numbers become float64.
+func ReadNBT(r io.Reader) (string, map[string]interface{}, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(2)
+	if err == nil && bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid gzip stream: %w", err)
+		}
+		defer gz.Close()
+		buffered = bufio.NewReader(gz)
+	}
+
+	reader := &nbtReader{r: buffered}
+	tagType, err := reader.byte()
+	if err != nil {
+		return "", nil, fmt.Errorf("truncated NBT header: %w", err)
+	}
+	if tagType != tagCompound {
+		return "", nil, fmt.Errorf("root tag has type %d, expected compound", tagType)
+	}
+	name, err := reader.string()
+	if err != nil {
+		return "", nil, fmt.Errorf("truncated NBT header: %w", err)
+	}
+	root, err := reader.compound(0)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, root, nil
+}
+
+type nbtReader struct {
+	r io.Reader
+}
+
+func (nr *nbtReader) read(data interface{}) error {
+	if err := binary.Read(nr.r, binary.BigEndian, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return fmt.Errorf("truncated NBT data: %w", err)
+	}
+	return nil
+}
+
+func (nr *nbtReader) byte() (byte, error) {
+	var b byte
+	err := nr.read(&b)
+	return b, err
+}
+
+func (nr *nbtReader) length() (int, error) {
+	var length int32
+	if err := nr.read(&length); err != nil {
+		return 0, err
+	}
+	if length < 0 {
+		return 0, fmt.Errorf("invalid NBT length %d", length)
+	}
+	return int(length), nil
+}
+
+func (nr *nbtReader) string() (string, error) {
+	var length uint16
+	if err := nr.read(&length); err != nil {
+		return "", err
+	}
+	data := make([]byte, length)
+	if err := nr.read(data); err != nil {
+		return "", err
+	}
+	// NBT strings use Java's modified UTF-8, which only differs from UTF-8
+	// for NUL and supplementary characters
+	return string(data), nil
+}
+
+func (nr *nbtReader) compound(depth int) (map[string]interface{}, error) {
+	if depth > maxNBTDepth {
+		return nil, fmt.Errorf("NBT nested deeper than %d", maxNBTDepth)
+	}
+
+	result := make(map[string]interface{})
+	for {
+		tagType, err := nr.byte()
+		if err != nil {
+			return nil, err
+		}
+		if tagType == tagEnd {
+			return result, nil
+		}
+		name, err := nr.string()
+		if err != nil {
+			return nil, err
+		}
+		value, err := nr.payload(tagType, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = value
+	}
+}
+
+func (nr *nbtReader) payload(tagType byte, depth int) (interface{}, error) {
+	switch tagType {
+	case tagByte:
+		var v int8
+		err := nr.read(&v)
+		return float64(v), err
+	case tagShort:
+		var v int16
+		err := nr.read(&v)
+		return float64(v), err
+	case tagInt:
+		var v int32
+		err := nr.read(&v)
+		return float64(v), err
+	case tagLong:
+		var v int64
+		err := nr.read(&v)
+		return float64(v), err
+	case tagFloat:
+		var v uint32
+		err := nr.read(&v)
+		return float64(math.Float32frombits(v)), err
+	case tagDouble:
+		var v uint64
+		err := nr.read(&v)
+		return math.Float64frombits(v), err
+	case tagString:
+		return nr.string()
+	case tagByteArray, tagIntArray, tagLongArray:
+		return nr.array(tagType)
+	case tagList:
+		return nr.list(depth)
+	case tagCompound:
+		return nr.compound(depth)
+	default:
+		return nil, fmt.Errorf("unknown NBT tag type %d", tagType)
+	}
+}
+
+func (nr *nbtReader) array(tagType byte) ([]interface{}, error) {
+	length, err := nr.length()
+	if err != nil {
+		return nil, err
+	}
+
+	elementType := map[byte]byte{tagByteArray: tagByte, tagIntArray: tagInt, tagLongArray: tagLong}[tagType]
+	result := make([]interface{}, 0, min(length, 4096))
+	for i := 0; i < length; i++ {
+		value, err := nr.payload(elementType, 0)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+func (nr *nbtReader) list(depth int) ([]interface{}, error) {
+	if depth > maxNBTDepth {
+		return nil, fmt.Errorf("NBT nested deeper than %d", maxNBTDepth)
+	}
+
+	elementType, err := nr.byte()
+	if err != nil {
+		return nil, err
+	}
+	length, err := nr.length()
+	if err != nil {
+		return nil, err
+	}
+	if elementType == tagEnd && length > 0 {
+		return nil, fmt.Errorf("list of end tags with length %d", length)
+	}
+
+	result := make([]interface{}, 0, min(length, 4096))
+	for i := 0; i < length; i++ {
+		value, err := nr.payload(elementType, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package mcheck
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// nbtWriter builds binary NBT documents for tests
+type nbtWriter struct {
+	bytes.Buffer
+}
+
+func (w *nbtWriter) write(values ...interface{}) *nbtWriter {
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			binary.Write(&w.Buffer, binary.BigEndian, uint16(len(s)))
+			w.WriteString(s)
+			continue
+		}
+		binary.Write(&w.Buffer, binary.BigEndian, value)
+	}
+	return w
+}
+
+// tag writes the header of a named tag
+func (w *nbtWriter) tag(tagType byte, name string) *nbtWriter {
+	return w.write(tagType, name)
+}
+
+func TestReadNBT(t *testing.T) {
+	w := &nbtWriter{}
+	w.tag(tagCompound, "root")
+	w.tag(tagByte, "flag").write(int8(1))
+	w.tag(tagShort, "short").write(int16(-2))
+	w.tag(tagInt, "int").write(int32(300))
+	w.tag(tagLong, "long").write(int64(1) << 40)
+	w.tag(tagFloat, "float").write(math.Float32bits(0.5))
+	w.tag(tagDouble, "double").write(math.Float64bits(2.25))
+	w.tag(tagString, "name").write("stone")
+	w.tag(tagIntArray, "ints").write(int32(2), int32(7), int32(8))
+	w.tag(tagList, "list").write(tagCompound, int32(1))
+	w.tag(tagString, "inner").write("x").write(tagEnd)
+	w.tag(tagList, "empty").write(tagEnd, int32(0))
+	w.write(tagEnd)
+
+	for name, data := range map[string][]byte{"raw": w.Bytes(), "gzip": gzipBytes(t, w.Bytes())} {
+		t.Run(name, func(t *testing.T) {
+			rootName, root, err := ReadNBT(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("ReadNBT failed: %v", err)
+			}
+			if rootName != "root" {
+				t.Errorf("Expected root name %q, got %q", "root", rootName)
+			}
+
+			expected := map[string]interface{}{
+				"flag": 1.0, "short": -2.0, "int": 300.0, "long": float64(int64(1) << 40),
+				"float": 0.5, "double": 2.25, "name": "stone",
+			}
+			for key, want := range expected {
+				if root[key] != want {
+					t.Errorf("%s: expected %v, got %v", key, want, root[key])
+				}
+			}
+			if ints, ok := root["ints"].([]interface{}); !ok || len(ints) != 2 || ints[1] != 8.0 {
+				t.Errorf("Unexpected int array %v", root["ints"])
+			}
+			list, ok := root["list"].([]interface{})
+			if !ok || len(list) != 1 || list[0].(map[string]interface{})["inner"] != "x" {
+				t.Errorf("Unexpected list %v", root["list"])
+			}
+			if empty, ok := root["empty"].([]interface{}); !ok || len(empty) != 0 {
+				t.Errorf("Unexpected empty list %v", root["empty"])
+			}
+		})
+	}
+}
+
+func TestReadNBTErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []byte
+		error string
+	}{
+		{"empty", nil, "truncated NBT header"},
+		{"not a compound", []byte{tagList, 0, 0}, "expected compound"},
+		{"truncated", []byte{tagCompound, 0, 0, tagInt, 0, 1, 'a', 0}, "truncated NBT data"},
+		{"unknown tag", []byte{tagCompound, 0, 0, 99, 0, 0}, "unknown NBT tag type 99"},
+		{"negative length", []byte{tagCompound, 0, 0, tagIntArray, 0, 0, 0xff, 0xff, 0xff, 0xff}, "invalid NBT length"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ReadNBT(bytes.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("Expected error containing %q, got %v", tt.error, err)
+			}
+		})
+	}
+}
+
+func TestValidateNBT(t *testing.T) {
+	schema := `struct StructureNBT {
+	DataVersion: int @ 0..,
+	size: [int @ 0..] @ 3,
+	blocks: [struct StructureBlock {
+		state: int @ 0..,
+		pos: [int @ 0..] @ 3,
+		waterlogged?: boolean,
+	}],
+}`
+
+	structure := func(size int32) []byte {
+		w := &nbtWriter{}
+		w.tag(tagCompound, "")
+		w.tag(tagInt, "DataVersion").write(int32(3465))
+		w.tag(tagList, "size").write(tagInt, int32(3), size, int32(1), int32(1))
+		w.tag(tagList, "blocks").write(tagCompound, int32(1))
+		w.tag(tagInt, "state").write(int32(0))
+		w.tag(tagList, "pos").write(tagInt, int32(3), int32(0), int32(0), int32(0))
+		w.tag(tagByte, "waterlogged").write(int8(1))
+		w.write(tagEnd, tagEnd)
+		return gzipBytes(t, w.Bytes())
+	}
+
+	root := t.TempDir()
+	schemaDir := filepath.Join(root, "vanilla-mcdoc")
+	files := map[string][]byte{
+		filepath.Join(schemaDir, "java", "world", "structure.mcdoc"):        []byte(schema),
+		filepath.Join(root, "pack", "data", "test", "structure", "ok.nbt"):  structure(2),
+		filepath.Join(root, "pack", "data", "test", "structure", "bad.nbt"): structure(-1),
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, schemaDir)
+	if err := validator.ValidateFile(filepath.Join(root, "pack", "data", "test", "structure", "ok.nbt")); err != nil {
+		t.Errorf("Expected valid structure, got %v", err)
+	}
+	err := validator.ValidateFile(filepath.Join(root, "pack", "data", "test", "structure", "bad.nbt"))
+	if err == nil || !strings.Contains(err.Error(), "size.[0]") {
+		t.Errorf("Expected an error at size.[0], got %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+package mcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Normalize decodes a JSON document of a resource type into the form Equal
+// compares, so that tools can tell documents the game reads alike from
+// ones that differ. Keys are unordered and numbers keep every digit but are
+// written in their shortest form, so 1.0 and 1 are alike. The resource ids
+// the schema of the type declares in version get the minecraft namespace
+// the game reads them with, so stone and minecraft:stone are alike too.
+// Documents of resource types without a schema are only decoded.
+func (v *PEGMCDocValidator) Normalize(doc []byte, resourceType string, version Version) (interface{}, error) {
+	normalized, err := normalizeJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	converter, mainValidator, _, err := v.loadResourceType(resourceType)
+	if err != nil {
+		return normalized, nil
+	}
+	// Unions and dispatches are resolved against the document as it is
+	// validated, with float64 numbers
+	value, err := decodeJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	ctx := v.newContext(converter)
+	ctx.Version = version
+	return normalizeIDs(value, normalized, mainValidator, ctx, 0), nil
+}
+
+// Equal reports whether two documents returned by Normalize, or decoded
+// some other way, are alike. Objects are alike if they have the same keys
+// with alike values, whatever their order, and numbers if they have the same
+// value, whether they are json.Numbers, float64s or ints: 1, 1.0 and 1e0
+// are alike, while integers too large for a float64 keep every digit.
+func Equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, aElem := range a {
+			bElem, ok := b[key]
+			if !ok || !Equal(aElem, bElem) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !Equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if aNumber, ok := numberKey(a); ok {
+		bNumber, ok := numberKey(b)
+		return ok && aNumber == bNumber
+	}
+	switch b.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	}
+	return a == b
+}
+
+// numberKey returns a key that is the same for numbers of the same value,
+// whatever their type. Floats stand for the shortest decimal they are
+// written as, so that the float64 0.1 is alike the json.Number 0.1.
+func numberKey(value interface{}) (string, bool) {
+	var text string
+	switch v := value.(type) {
+	case json.Number:
+		text = string(v)
+	case nonFiniteNumber:
+		return string(v), true
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return "NaN", true
+		case math.IsInf(v, 1):
+			return "Infinity", true
+		case math.IsInf(v, -1):
+			return "-Infinity", true
+		}
+		text = strconv.FormatFloat(v, 'g', -1, 64)
+	case int:
+		text = strconv.Itoa(v)
+	case int64:
+		text = strconv.FormatInt(v, 10)
+	default:
+		return "", false
+	}
+	number, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return text, true
+	}
+	return number.RatString(), true
+}
+
+// nonFiniteNumber is a NaN or infinite number of a decoded document, which
+// JSON has no number for
+type nonFiniteNumber string
+
+// normalizeJSON decodes a JSON document keeping every digit of its
+// numbers, which are written in their shortest form so that 1.0 and 1 are
+// alike
+func normalizeJSON(doc []byte) (interface{}, error) {
+	text, err := decodeText(doc)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(replaceNonFiniteLiterals(text)))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected content after the JSON document")
+	}
+	return canonicalNumbers(value), nil
+}
+
+// canonicalNumbers rewrites the numbers of a value decoded with UseNumber
+// in their shortest form, and the markers replaceNonFiniteLiterals left as
+// nonFiniteNumbers
+func canonicalNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		return json.Number(canonicalNumber(string(v)))
+	case string:
+		for _, nonFinite := range nonFiniteLiterals {
+			if v != nonFiniteMarker+nonFinite.literal {
+				continue
+			}
+			switch {
+			case math.IsNaN(nonFinite.value):
+				return nonFiniteNumber("NaN")
+			case nonFinite.value > 0:
+				return nonFiniteNumber("Infinity")
+			}
+			return nonFiniteNumber("-Infinity")
+		}
+	case map[string]interface{}:
+		for key, element := range v {
+			v[key] = canonicalNumbers(element)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = canonicalNumbers(element)
+		}
+	}
+	return value
+}
+
+// normalizeIDs adds the default namespace to the resource ids of
+// normalized, the normalized form of value, that validator declares with
+// an #[id] attribute. It returns normalized with the ids replaced.
+func normalizeIDs(value, normalized interface{}, validator Validator, ctx *ValidationContext, depth int) interface{} {
+	if validator == nil || depth > maxSpreadDepth || !validator.AppliesForVersion(ctx) {
+		return normalized
+	}
+
+	switch v := validator.(type) {
+	case *ReferenceValidator:
+		return normalizeIDs(value, normalized, ctx.Definitions[v.TypeName], ctx, depth+1)
+	case *AttributedValidator:
+		if id, ok := normalized.(string); ok && id != "" && hasAttribute(v, "id") {
+			if tag, isTag := strings.CutPrefix(id, "#"); isTag {
+				return "#" + normalizeID(tag)
+			}
+			return normalizeID(id)
+		}
+		return normalizeIDs(value, normalized, v.InnerValidator, ctx, depth+1)
+	case *ConstrainedValidator:
+		return normalizeIDs(value, normalized, v.InnerValidator, ctx, depth+1)
+	case *DispatchValidator:
+		return normalizeIDs(value, normalized, v.Resolve(value, ctx), ctx, depth+1)
+	case *UnionValidator:
+		return normalizeIDs(value, normalized, pickAlternative(v, value, ctx), ctx, depth+1)
+	case *ArrayValidator:
+		arr, ok := value.([]interface{})
+		normalizedArr, normalizedOK := normalized.([]interface{})
+		if ok && normalizedOK {
+			arrCtx := ctx.WithParent(arr)
+			for i, elem := range arr {
+				normalizedArr[i] = normalizeIDs(elem, normalizedArr[i], v.ElementValidator, arrCtx.Child(fmt.Sprintf("[%d]", i)), depth+1)
+			}
+		}
+	case *StructValidator:
+		obj, ok := value.(map[string]interface{})
+		normalizedObj, normalizedOK := normalized.(map[string]interface{})
+		if ok && normalizedOK {
+			normalizeStructIDs(obj, normalizedObj, v, ctx, ctx.WithParent(obj), nil, depth)
+		}
+	}
+	return normalized
+}
+
+// normalizeStructIDs normalizes the ids of the fields of obj that sv and
+// its spreads describe, in normalizedObj
+func normalizeStructIDs(obj, normalizedObj map[string]interface{}, sv *StructValidator, ctx, objCtx *ValidationContext, overridden map[string]bool, depth int) {
+	for _, field := range sv.Fields {
+		fieldValue, exists := obj[field.Name]
+		if !exists || !field.AppliesForVersion(ctx) || overridden[field.Name] {
+			continue
+		}
+		normalizedObj[field.Name] = normalizeIDs(fieldValue, normalizedObj[field.Name], field.Validator, objCtx.Child(field.Name), depth+1)
+	}
+
+	declared := sv.declaredFields(ctx, overridden)
+	for _, spread := range sv.SpreadFields {
+		spreadStruct, ok := resolveSpread(spread, obj, objCtx, depth)
+		if ok && spreadStruct != nil && spreadStruct.AppliesForVersion(ctx) {
+			normalizeStructIDs(obj, normalizedObj, spreadStruct, ctx, objCtx, declared, depth+1)
+		}
+	}
+
+	if len(sv.DynamicFields) == 0 {
+		return
+	}
+	for key, elem := range obj {
+		if _, known := findStructField(sv, key, obj, objCtx); known {
+			continue
+		}
+		for _, dynamic := range sv.DynamicFields {
+			if dynamic.AppliesForVersion(ctx) && !hasErrors(dynamic.Key.Validate(key, objCtx)) {
+				normalizedObj[key] = normalizeIDs(elem, normalizedObj[key], dynamic.Validator, objCtx.Child(key), depth+1)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package mcheck
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, fixtureSchemaDir(t, "trim"))
+	normalize := func(resourceType, doc string) interface{} {
+		t.Helper()
+		normalized, err := validator.Normalize([]byte(doc), resourceType, Version{1, 20, 1})
+		if err != nil {
+			t.Fatalf("Normalize failed: %v", err)
+		}
+		return normalized
+	}
+
+	gold := `{"asset_name": "gold", "description": "Gold", "ingredient": "gold_ingot", "item_model_index": 0.6}`
+	tests := []struct {
+		name         string
+		resourceType string
+		doc          string
+		equal        bool
+	}{
+		{"key order, numbers and namespaces", "trim_material", `{"item_model_index": 6e-1, "ingredient": "minecraft:gold_ingot", "description": "Gold", "asset_name": "minecraft:gold"}`, true},
+		{"changed value", "trim_material", `{"asset_name": "gold", "description": "Gold", "ingredient": "gold_ingot", "item_model_index": 0.7}`, false},
+		{"namespace of a string that is not an id", "trim_material", `{"asset_name": "gold", "description": "minecraft:Gold", "ingredient": "gold_ingot", "item_model_index": 0.6}`, false},
+		{"namespace without a schema", "damage_type", `{"asset_name": "minecraft:gold", "description": "Gold", "ingredient": "minecraft:gold_ingot", "item_model_index": 0.6}`, false},
+		{"numbers without a schema", "damage_type", `{"item_model_index": 0.60, "ingredient": "gold_ingot", "description": "Gold", "asset_name": "gold"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Equal(normalize(tt.resourceType, gold), normalize(tt.resourceType, tt.doc)) != tt.equal {
+				t.Errorf("Expected Equal to be %v", tt.equal)
+			}
+		})
+	}
+
+	// Integers keep every digit
+	if Equal(normalize("damage_type", `[12345678901234567890]`), normalize("damage_type", `[12345678901234567891]`)) {
+		t.Error("Expected seeds differing in their last digit to differ")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  interface{}
+		equal bool
+	}{
+		{"int and float64", 1, float64(1), true},
+		{"int64 and json.Number", int64(-3), json.Number("-3.0"), true},
+		{"float64 and json.Number", 0.1, json.Number("1e-1"), true},
+		{"large float64 and json.Number", 1e20, json.Number("100000000000000000000"), true},
+		{"different numbers", 1, json.Number("1.5"), false},
+		{"integers differing in their last digit", json.Number("12345678901234567890"), json.Number("12345678901234567891"), false},
+		{"number and string", json.Number("1"), "1", false},
+		{"NaN", math.NaN(), nonFiniteNumber("NaN"), true},
+		{"infinities", math.Inf(1), nonFiniteNumber("-Infinity"), false},
+		{"key order", map[string]interface{}{"a": 1, "b": []interface{}{"x", true}}, map[string]interface{}{"b": []interface{}{"x", true}, "a": json.Number("1")}, true},
+		{"missing key", map[string]interface{}{"a": 1, "b": nil}, map[string]interface{}{"a": 1, "c": nil}, false},
+		{"array order", []interface{}{1, 2}, []interface{}{2, 1}, false},
+		{"array and object", []interface{}{}, map[string]interface{}{}, false},
+		{"null and object", nil, map[string]interface{}{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Equal(tt.a, tt.b) != tt.equal || Equal(tt.b, tt.a) != tt.equal {
+				t.Errorf("Expected Equal(%v, %v) to be %v", tt.a, tt.b, tt.equal)
+			}
+		})
+	}
+}
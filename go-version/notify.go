@@ -0,0 +1,122 @@
+package mcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// webhookMessage is the JSON posted to --notify-webhook. Discord shows
+// content and Slack text, so both carry the summary; the counts and
+// findings are for other services reading the JSON.
+type webhookMessage struct {
+	Content        string    `json:"content"`
+	Text           string    `json:"text"`
+	Version        string    `json:"version"`
+	Errors         int       `json:"errors"`
+	Warnings       int       `json:"warnings"`
+	SchemaProblems int       `json:"schema_problems"`
+	Findings       []Finding `json:"findings,omitempty"`
+}
+
+// maxWebhookText is the most Discord shows of a message
+const maxWebhookText = 2000
+
+// webhookTimeout bounds posting to a webhook, which should not hold up a
+// scheduled validation for long
+const webhookTimeout = 10 * time.Second
+
+// newWebhookMessage summarizes a failed validation. With all set, the
+// findings are listed in the text as far as it fits, and all of them are
+// included in the JSON.
+func newWebhookMessage(report Report, all bool) webhookMessage {
+	message := webhookMessage{
+		Version:        report.Version,
+		Errors:         report.Errors,
+		Warnings:       report.Warnings,
+		SchemaProblems: len(report.SchemaProblems),
+	}
+	findings := append(append([]Finding(nil), report.Findings...), report.SchemaProblems...)
+	files := make(map[string]bool)
+	for _, finding := range findings {
+		files[finding.File] = true
+	}
+
+	text := fmt.Sprintf("mcheck: validation for %s failed with %s and %s in %s",
+		report.Version, plural(report.Errors, "error"), plural(report.Warnings, "warning"), plural(len(files), "file"))
+	if len(report.SchemaProblems) > 0 {
+		text += fmt.Sprintf(", %s kept files from being fully checked", plural(len(report.SchemaProblems), "schema problem"))
+	}
+	if all {
+		message.Findings = findings
+		for i, finding := range findings {
+			line := "\n" + finding.String()
+			// Leave room to say how many did not fit after this one
+			rest := ""
+			if i < len(findings)-1 {
+				rest = fmt.Sprintf("\nand %d more", len(findings)-i-1)
+			}
+			if len(text)+len(line)+len(rest) > maxWebhookText {
+				text += fmt.Sprintf("\nand %d more", len(findings)-i)
+				break
+			}
+			text += line
+		}
+	}
+	message.Content, message.Text = text, text
+	return message
+}
+
+// notify posts a summary of findings to the webhook the output names if
+// err failed the validation, and returns err. Failing to post is only
+// warned about, so that it does not hide the outcome of the validation.
+func (out Output) notify(findings []Finding, err error) error {
+	if err == nil || out.webhook == "" {
+		return err
+	}
+	message := newWebhookMessage(newReport(findings, out.version, out.catalog), out.webhookFindings)
+	if notifyErr := postWebhook(out.webhook, message); notifyErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", notifyErr)
+	}
+	return err
+}
+
+// plural writes a count of things, like 1 error or 2 errors
+func plural(n int, thing string) string {
+	if n == 1 {
+		return "1 " + thing
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
+
+// postWebhook posts a message to a Discord or Slack compatible webhook
+func postWebhook(webhook string, message webhookMessage) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// The URL of a webhook holds its secret token, which a url.Error
+		// would print to the logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("posting to the webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		response, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("the webhook answered %s: %s", resp.Status, strings.TrimSpace(string(response)))
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package mcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookMessage(t *testing.T) {
+	findings := []Finding{
+		{File: "a.json", Severity: SeverityError, Message: "expected int", Rule: RuleWrongType},
+		{File: "a.json", Severity: SeverityWarning, Message: "boolean written as 1", Rule: RuleBooleanNumber},
+		{File: "b.json", Severity: SeverityError, Message: "schema file not found", Rule: RuleSchemaNotFound},
+	}
+	report := newReport(findings, "1.21.4", nil)
+
+	summary := newWebhookMessage(report, false)
+	expected := "mcheck: validation for 1.21.4 failed with 2 errors and 1 warning in 2 files, 1 schema problem kept files from being fully checked"
+	if summary.Content != expected || summary.Text != expected {
+		t.Errorf("Expected the summary %q, got %+v", expected, summary)
+	}
+	if summary.Findings != nil || summary.Errors != 2 || summary.SchemaProblems != 1 {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+
+	full := newWebhookMessage(report, true)
+	if len(full.Findings) != 3 || !strings.Contains(full.Content, "\na.json: expected int [") {
+		t.Errorf("Expected the findings to be listed, got %+v", full)
+	}
+
+	// Discord rejects messages over 2000 characters
+	var many []Finding
+	for i := range 100 {
+		many = append(many, Finding{File: fmt.Sprintf("data/test/loot_table/table_%d.json", i), Severity: SeverityError, Message: "required field 'pools' is missing"})
+	}
+	long := newWebhookMessage(newReport(many, "1.21.4", nil), true)
+	if len(long.Content) > maxWebhookText || !strings.HasSuffix(long.Content, " more") || len(long.Findings) != 100 {
+		t.Errorf("Expected the listed findings to be cut short at %d characters, got %d:\n%s", maxWebhookText, len(long.Content), long.Content)
+	}
+}
+
+func TestPostWebhook(t *testing.T) {
+	var received webhookMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "expected JSON", http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	findings := []Finding{{File: "a.json", Severity: SeverityError, Message: "expected int", Rule: RuleWrongType}}
+	out := Output{version: "1.20.1", webhook: server.URL}
+	err := out.Print(findings, nil)
+	if err == nil {
+		t.Fatal("Expected the findings to fail")
+	}
+	if received.Errors != 1 || !strings.Contains(received.Content, "validation for 1.20.1 failed") {
+		t.Errorf("Expected the failure to be posted, got %+v", received)
+	}
+
+	received = webhookMessage{}
+	if err := out.Print(nil, nil); err != nil || received.Content != "" {
+		t.Errorf("Expected a passing validation not to be posted, got %v, %+v", err, received)
+	}
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unknown webhook", http.StatusNotFound)
+	}))
+	defer rejecting.Close()
+	if err := postWebhook(rejecting.URL, webhookMessage{}); err == nil || !strings.Contains(err.Error(), "unknown webhook") {
+		t.Errorf("Expected the webhook's answer in the error, got %v", err)
+	}
+
+	// Errors leave out the token in the path of the webhook
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	if err := postWebhook(closed.URL+"/api/webhooks/123/secret-token", webhookMessage{}); err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Expected an error without the token of the webhook, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package mcheck
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// objectiveUse is where a pack file creates or uses a scoreboard objective
+type objectiveUse struct {
+	file      string
+	path      []string
+	objective string
+}
+
+// checkObjectives warns about scoreboard objectives that functions, predicates
+// and loot tables use but no function creates with scoreboard objectives add.
+// Scores of such objectives read as unset and cannot be set, which breaks a
+// pack without any error from the game. The objective may come from another
+// pack or be added by hand, so this is only a warning. With ReportUnused,
+// objectives that are created but never used are reported too.
+func checkObjectives(pack *Pack, opts PackOptions) []Finding {
+	var created, used []objectiveUse
+	for _, functionType := range functionTypes {
+		for _, file := range sortedFiles(pack.Resources[functionType]) {
+			data, err := pack.files.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			for _, line := range commandLines(string(data)) {
+				path := []string{fmt.Sprintf("line %d", line.number)}
+				adds, uses := commandObjectives(commandArgs(line.text))
+				for _, objective := range textObjectivePattern.FindAllStringSubmatch(line.text, -1) {
+					uses = append(uses, objective[1])
+				}
+				for _, objective := range adds {
+					created = append(created, objectiveUse{file, path, objective})
+				}
+				for _, objective := range uses {
+					used = append(used, objectiveUse{file, path, objective})
+				}
+			}
+		}
+	}
+	for _, file := range pack.JSONFiles() {
+		if value, ok := readValue(pack.files, file); ok {
+			jsonObjectives(file, nil, value, &used)
+		}
+	}
+
+	isCreated := make(map[string]bool)
+	for _, use := range created {
+		isCreated[use.objective] = true
+	}
+	isUsed := make(map[string]bool)
+	for _, use := range used {
+		isUsed[use.objective] = true
+	}
+
+	var findings []Finding
+	reported := make(map[string]bool)
+	for _, use := range used {
+		key := use.file + "\x00" + use.objective
+		if isCreated[use.objective] || reported[key] || strings.Contains(use.objective, "$(") {
+			continue
+		}
+		reported[key] = true
+		findings = append(findings, Finding{
+			File:     pack.RelativePath(use.file),
+			Path:     use.path,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("scoreboard objective %s is used but no function creates it", use.objective),
+			Rule:     RuleUndefinedObjective,
+		})
+	}
+	if opts.ReportUnused {
+		reported := make(map[string]bool)
+		for _, use := range created {
+			if isUsed[use.objective] || reported[use.objective] {
+				continue
+			}
+			reported[use.objective] = true
+			findings = append(findings, Finding{
+				File:     pack.RelativePath(use.file),
+				Path:     use.path,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("scoreboard objective %s is created but never used", use.objective),
+				Rule:     RuleUnusedObjective,
+			})
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].File < findings[j].File })
+	return findings
+}
+
+// textObjectivePattern matches the objective of a score text component in a
+// command, like {"score": {"name": "@s", "objective": "kills"}} in tellraw
+var textObjectivePattern = regexp.MustCompile(`\bobjective["']?\s*:\s*["']([^"']+)["']`)
+
+// scoreComparisons are the operators of execute if score comparing two scores
+var scoreComparisons = []string{"<", "<=", "=", ">=", ">"}
+
+// commandObjectives returns the objectives a command creates and those it
+// uses. Removing or modifying an objective counts as using it.
+func commandObjectives(args []string) (created, used []string) {
+	arg := func(i int) string {
+		if i < len(args) {
+			return args[i]
+		}
+		return ""
+	}
+	use := func(objective string) {
+		if objective != "" && objective != "*" {
+			used = append(used, objective)
+		}
+	}
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	switch strings.TrimPrefix(args[0], "/") {
+	case "scoreboard":
+		switch arg(1) + " " + arg(2) {
+		case "objectives add":
+			// scoreboard objectives add <objective> <criteria> [<display name>]
+			if arg(3) != "" {
+				created = append(created, arg(3))
+			}
+		case "objectives remove", "objectives modify":
+			use(arg(3))
+		case "objectives setdisplay":
+			// scoreboard objectives setdisplay <slot> [<objective>]
+			use(arg(4))
+		case "players set", "players add", "players remove", "players get", "players reset", "players enable":
+			// scoreboard players <action> <targets> <objective> ...
+			use(arg(4))
+		case "players operation":
+			// scoreboard players operation <targets> <objective> <operation> <source> <objective>
+			use(arg(4))
+			use(arg(7))
+		case "players display":
+			// scoreboard players display (name | numberformat) <targets> <objective> ...
+			use(arg(5))
+		}
+	case "trigger":
+		// trigger <objective> [(add | set) <value>]
+		use(arg(1))
+	case "execute":
+		for i := 1; i < len(args); i++ {
+			switch {
+			case args[i] == "run":
+				runCreated, runUsed := commandObjectives(args[i+1:])
+				return append(created, runCreated...), append(used, runUsed...)
+			case (args[i] == "if" || args[i] == "unless") && arg(i+1) == "score":
+				// if score <target> <objective> (matches <range> | <op> <source> <objective>)
+				use(arg(i + 3))
+				for _, comparison := range scoreComparisons {
+					if arg(i+4) == comparison {
+						use(arg(i + 6))
+					}
+				}
+			case args[i] == "store" && arg(i+2) == "score":
+				// store (result | success) score <targets> <objective>
+				use(arg(i + 4))
+			}
+		}
+	}
+	return created, used
+}
+
+// jsonObjectives appends the objectives used by a JSON file: the scores of
+// entity predicates, score number providers and score text components
+func jsonObjectives(file string, path []string, value interface{}, used *[]objectiveUse) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if scores, ok := v["scores"].(map[string]interface{}); ok {
+			var keys []string
+			for _, objective := range sortedKeys(scores, &keys) {
+				*used = append(*used, objectiveUse{file, appendPath(path, "scores", objective), objective})
+			}
+		}
+		if kind, _ := v["type"].(string); normalizeID(kind) == "minecraft:score" {
+			if objective, ok := v["score"].(string); ok {
+				*used = append(*used, objectiveUse{file, appendPath(path, "score"), objective})
+			}
+		}
+		if score, ok := v["score"].(map[string]interface{}); ok {
+			if objective, ok := score["objective"].(string); ok {
+				*used = append(*used, objectiveUse{file, appendPath(path, "score", "objective"), objective})
+			}
+		}
+		var keys []string
+		for _, key := range sortedKeys(v, &keys) {
+			jsonObjectives(file, appendPath(path, key), v[key], used)
+		}
+	case []interface{}:
+		for i, element := range v {
+			jsonObjectives(file, appendPath(path, strconv.Itoa(i)), element, used)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package mcheck
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestObjectives(t *testing.T) {
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/function/load.mcfunction": []byte(strings.Join([]string{
+			"scoreboard objectives add kills playerKillCount",
+			"scoreboard objectives add timer dummy \"Timer\"",
+			"scoreboard objectives add menu trigger",
+			"scoreboard objectives add leftover dummy",
+		}, "\n")),
+		"data/test/function/tick.mcfunction": []byte(strings.Join([]string{
+			"scoreboard players add @a timer 1",
+			"execute as @a if score @s kills matches 1.. run scoreboard players reset @s kills",
+			"execute store result score #max timer run scoreboard players get @s deaths",
+			"scoreboard players enable @a menu",
+			"scoreboard players operation @s timer += @s bonus",
+			"execute if score @s timer > @s deaths run say late",
+			`tellraw @a {"score": {"name": "@s", "objective": "mana"}}`,
+			"$scoreboard players set @s $(objective) 1",
+		}, "\n")),
+		"data/test/predicate/rich.json":   []byte(`{"condition": "minecraft:entity_properties", "entity": "this", "predicate": {"scores": {"coins": {"min": 10}}}}`),
+		"data/test/loot_table/prize.json": []byte(`{"pools": [{"rolls": {"type": "minecraft:score", "target": "this", "score": "kills"}, "entries": []}]}`),
+	})
+
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+
+	var messages []string
+	for _, finding := range checkObjectives(pack, PackOptions{}) {
+		messages = append(messages, finding.String())
+	}
+	expected := []string{
+		"data/test/function/tick.mcfunction: warning: at line 3: scoreboard objective deaths is used but no function creates it [MCHECK045 undefined-objective]",
+		"data/test/function/tick.mcfunction: warning: at line 5: scoreboard objective bonus is used but no function creates it [MCHECK045 undefined-objective]",
+		"data/test/function/tick.mcfunction: warning: at line 7: scoreboard objective mana is used but no function creates it [MCHECK045 undefined-objective]",
+		"data/test/predicate/rich.json: warning: at predicate.scores.coins: scoreboard objective coins is used but no function creates it [MCHECK045 undefined-objective]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+
+	messages = nil
+	for _, finding := range checkObjectives(pack, PackOptions{ReportUnused: true}) {
+		if finding.Rule == RuleUnusedObjective {
+			messages = append(messages, finding.String())
+		}
+	}
+	expected = []string{
+		"data/test/function/load.mcfunction: warning: at line 4: scoreboard objective leftover is created but never used [MCHECK046 unused-objective]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+}
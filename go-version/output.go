@@ -0,0 +1,128 @@
+package mcheck
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Exit codes of commands that check files. Other failures, like invalid
+// flags, exit with 1 as well.
+const (
+	ExitInvalid       = 1 // some files are invalid
+	ExitSchemaProblem = 2 // no file is invalid, but some could not be checked
+)
+
+// ExitError fails a command with a specific exit code
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// OutputOptions are how findings should be printed, as the --lang, --style,
+// --format, --template and --notify-webhook flags give them
+type OutputOptions struct {
+	Lang           string
+	Style          string
+	Format         string
+	Template       string // template file, for FormatTemplate
+	Version        string // target version, or versions, named by the report
+	NotifyWebhook  string
+	NotifyFindings bool
+}
+
+// NewOutput returns the output the options ask for
+func NewOutput(opts OutputOptions) (Output, error) {
+	catalog, err := LoadCatalog(opts.Lang)
+	if err != nil {
+		return Output{}, err
+	}
+	style, err := parseOutputStyle(opts.Style)
+	if err != nil {
+		return Output{}, err
+	}
+	out := Output{
+		catalog:         catalog,
+		style:           style,
+		version:         opts.Version,
+		webhook:         opts.NotifyWebhook,
+		webhookFindings: opts.NotifyFindings,
+	}
+	switch opts.Format {
+	case FormatText, "":
+		if opts.Template != "" {
+			return Output{}, fmt.Errorf("--template needs --format=%s", FormatTemplate)
+		}
+	case FormatTemplate:
+		if opts.Template == "" {
+			return Output{}, fmt.Errorf("--format=%s needs a --template file", FormatTemplate)
+		}
+		if out.template, err = parseReportTemplate(opts.Template); err != nil {
+			return Output{}, err
+		}
+	default:
+		return Output{}, fmt.Errorf("unknown output format %q, expected one of %s", opts.Format, strings.Join(OutputFormats, ", "))
+	}
+	return out, nil
+}
+
+// Print prints findings to stdout, failing with an ExitError if any of them
+// is an error. Given validators by the version findings were checked for,
+// findings about values within a file are followed by their explanation;
+// their files must be named by their paths.
+//
+// Schema problems follow in a section of their own: they are not problems
+// with the files, and errors among them only fail with ExitSchemaProblem if
+// no file is invalid.
+//
+// With a template, the report it renders is printed instead. A failure is
+// posted to the webhook of the output, if it has one.
+func (out Output) Print(findings []Finding, explainers map[string]*PEGMCDocValidator) error {
+	fileFindings, schemaProblems := splitFindings(findings)
+	if out.template != nil {
+		var rendered strings.Builder
+		if err := out.template.Execute(&rendered, newReport(findings, out.version, out.catalog)); err != nil {
+			return err
+		}
+		fmt.Print(out.style.text(rendered.String()))
+		return out.notify(findings, findingsExit(fileFindings, schemaProblems))
+	}
+	for _, finding := range fileFindings {
+		fmt.Println(out.finding(finding))
+		if explainer := explainers[finding.Version]; explainer != nil {
+			if explanation, err := explainer.Explain(finding.File, finding); err == nil {
+				fmt.Print(out.style.text(explanation.String()))
+			}
+		}
+	}
+	if len(schemaProblems) > 0 {
+		if len(fileFindings) > 0 {
+			fmt.Println()
+		}
+		fmt.Println("schema problems, not problems with the files but keeping them from being fully checked:")
+		for _, finding := range schemaProblems {
+			fmt.Println("  " + out.finding(finding))
+		}
+		if hasErrors(schemaProblems) {
+			fmt.Fprintln(os.Stderr, "run mcheck doctor to check the schema directory")
+		}
+	}
+	return out.notify(findings, findingsExit(fileFindings, schemaProblems))
+}
+
+// findingsExit fails with the exit code findings call for
+func findingsExit(fileFindings, schemaProblems []Finding) error {
+	switch {
+	case hasErrors(fileFindings) && hasErrors(schemaProblems):
+		return ExitError{ExitInvalid, fmt.Errorf("%d problems found, and %d schema problems", len(fileFindings), len(schemaProblems))}
+	case hasErrors(fileFindings):
+		return ExitError{ExitInvalid, fmt.Errorf("%d problems found", len(fileFindings))}
+	case hasErrors(schemaProblems):
+		return ExitError{ExitSchemaProblem, fmt.Errorf("%d schema problems kept files from being checked", len(schemaProblems))}
+	}
+	return nil
+}
@@ -0,0 +1,419 @@
+package mcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Pack is an index of the resources in a datapack directory
+type Pack struct {
+	Root       string
+	Namespaces map[string]bool
+	Resources  map[string]map[string]string // resource type -> resource id -> file path
+	Skipped    []Finding                    // warnings about entries of data/ that could not be indexed
+
+	// Others are the packs loaded together with this one, like the other
+	// datapacks of a server. References resolve in them as well.
+	Others []*Pack
+
+	// Vanilla is the vanilla datapack of the target version, if known.
+	// References resolve in it, but unlike Others it does not make the pack
+	// define the minecraft namespace: it is only as complete as the data
+	// it was extracted from.
+	Vanilla *Pack
+
+	files fileSystem // where the files of the pack are read from
+}
+
+// PackOptions controls the optional checks run on datapacks
+type PackOptions struct {
+	CheckNBT     bool   // parse referenced structure files to verify they are valid NBT
+	ChangedFrom  string // only check files changed since this git ref and the files referencing them
+	ReportUnused bool   // warn about resources nothing reachable references and objectives nothing uses
+
+	// Budgets warn about packs that could slow down a server. Each is only
+	// checked when it is above zero.
+	MaxFileSize int64 // bytes a single file may have
+	MaxDepth    int   // levels the objects and arrays of a JSON file may nest
+	MaxFiles    int   // resource files the pack may have
+
+	// Version is the version references to vanilla resources are checked
+	// for. The version of the validator is used if it is not set.
+	Version Version
+
+	// Vanilla is the directory of the vanilla datapack of Version, like
+	// one extracted by tools/vanilla-fixtures, for references to resolve in
+	Vanilla string
+
+	vanillaPack *Pack // Vanilla loaded before, like by the daemon
+}
+
+// LoadPack walks the data directory of a datapack and indexes its resources
+func LoadPack(root string) (*Pack, error) {
+	return LoadPackContext(context.Background(), root)
+}
+
+// LoadPackContext loads a datapack like LoadPack, stopping with the error
+// of ctx once it is done
+func LoadPackContext(ctx context.Context, root string) (*Pack, error) {
+	return loadPack(ctx, root, osFiles)
+}
+
+// LoadPackFS indexes a datapack held by fsys, like a zipped pack or one
+// built in memory. Its files are named by their path below root, which
+// findings are reported relative to.
+func LoadPackFS(root string, fsys fs.FS) (*Pack, error) {
+	return loadPack(context.Background(), root, newFileSystem(root, fsys))
+}
+
+func loadPack(ctx context.Context, root string, files fileSystem) (*Pack, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	pack := &Pack{
+		Root:       root,
+		Namespaces: make(map[string]bool),
+		Resources:  make(map[string]map[string]string),
+		files:      files,
+	}
+
+	dataDir := filepath.Join(root, "data")
+	if _, err := files.Stat(dataDir); err != nil {
+		return nil, fmt.Errorf("not a datapack, no data directory in %s", root)
+	}
+
+	err := files.withContext(ctx).WalkDir(dataDir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil && d != nil {
+			pack.Skipped = append(pack.Skipped, skippedFinding(pack.RelativePath(file), err))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		namespace, resourceType, id, ok := pack.resourceOf(file)
+		if !ok {
+			return nil
+		}
+		pack.Namespaces[namespace] = true
+		if resourceType != "" {
+			if pack.Resources[resourceType] == nil {
+				pack.Resources[resourceType] = make(map[string]string)
+			}
+			pack.Resources[resourceType][id] = file
+		}
+		return nil
+	})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to index datapack: %w", err)
+	}
+
+	return pack, nil
+}
+
+// skippedFinding warns about an entry of the data directory that could not
+// be indexed, like a directory that cannot be read
+func skippedFinding(file string, err error) Finding {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+	return Finding{
+		File:     file,
+		Severity: SeverityWarning,
+		Message:  fmt.Sprintf("skipped: %v", err),
+		Rule:     RuleUnreadableFile,
+	}
+}
+
+// resourceOf returns the namespace, resource type and resource id of a file
+// in the data directory of the pack. The resource type is empty for files
+// nested too shallowly to have one, like data/<ns>/worldgen/foo.json.
+func (p *Pack) resourceOf(file string) (namespace, resourceType, id string, ok bool) {
+	rel, err := filepath.Rel(filepath.Join(p.Root, "data"), file)
+	if err != nil {
+		return "", "", "", false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 3 || parts[0] == ".." {
+		return "", "", "", false
+	}
+
+	namespace = parts[0]
+	resourceType, name := splitResourcePath(parts[1:])
+	return namespace, resourceType, namespace + ":" + name, true
+}
+
+// splitResourcePath splits the path below a namespace into a resource type and
+// a resource name without extension, e.g. worldgen/biome/foo.json becomes
+// ("worldgen/biome", "foo")
+func splitResourcePath(parts []string) (string, string) {
+	typeLength := 1
+	switch parts[0] {
+	case "worldgen":
+		typeLength = 2
+	case "tags":
+		typeLength = 2
+		if len(parts) > 1 && parts[1] == "worldgen" {
+			typeLength = 3
+		}
+	}
+	if len(parts) <= typeLength {
+		return "", ""
+	}
+
+	name := strings.Join(parts[typeLength:], "/")
+	return strings.Join(parts[:typeLength], "/"), strings.TrimSuffix(name, path.Ext(name))
+}
+
+// normalizeID adds the default minecraft namespace to resource ids without one
+func normalizeID(id string) string {
+	if !strings.Contains(id, ":") {
+		return "minecraft:" + id
+	}
+	return id
+}
+
+// Lookup returns the file for a resource id of one of the given resource
+// types, in the pack, one of the packs loaded with it or vanilla
+func (p *Pack) Lookup(id string, resourceTypes ...string) (string, bool) {
+	id = normalizeID(id)
+	packs := append([]*Pack{p}, p.Others...)
+	if p.Vanilla != nil {
+		packs = append(packs, p.Vanilla)
+	}
+	for _, pack := range packs {
+		for _, resourceType := range resourceTypes {
+			if file, ok := pack.Resources[resourceType][id]; ok {
+				return file, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Defines reports whether the pack, or one of the packs loaded with it,
+// contains files for the namespace of id. References into other namespaces
+// usually point at vanilla or other packs.
+func (p *Pack) Defines(id string) bool {
+	namespace, _, _ := strings.Cut(normalizeID(id), ":")
+	for _, pack := range append([]*Pack{p}, p.Others...) {
+		if pack.Namespaces[namespace] {
+			return true
+		}
+	}
+	return false
+}
+
+// JSONFiles returns the paths of all JSON resources in the pack, sorted
+func (p *Pack) JSONFiles() []string {
+	return p.filesWithExtension(".json")
+}
+
+// NBTFiles returns the paths of all NBT resources in the pack, sorted
+func (p *Pack) NBTFiles() []string {
+	return p.filesWithExtension(".nbt")
+}
+
+// FunctionFiles returns the paths of all functions in the pack, sorted
+func (p *Pack) FunctionFiles() []string {
+	return p.filesWithExtension(".mcfunction")
+}
+
+// Files returns the paths of all resources in the pack, sorted
+func (p *Pack) Files() []string {
+	return p.filesWithExtension("")
+}
+
+// filesWithExtension returns the paths of the resources with an extension,
+// or of all resources if ext is empty, sorted
+func (p *Pack) filesWithExtension(ext string) []string {
+	var files []string
+	for _, resources := range p.Resources {
+		for _, file := range resources {
+			if ext == "" || strings.EqualFold(path.Ext(file), ext) {
+				files = append(files, file)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// RelativePath returns a file path relative to the pack root for reporting
+func (p *Pack) RelativePath(file string) string {
+	if rel, err := filepath.Rel(p.Root, file); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return file
+}
+
+// CheckPack checks every JSON and NBT file in a datapack with CheckFile and
+// the item components of the commands of its functions, then runs the
+// pack-level checks that look at references between files. The pack is read
+// from the directory root on disk, or if fsys is set from fsys, naming its
+// files by their path below root; packs that are not on disk have no git
+// history, so opts.ChangedFrom cannot be used for them. Once ctx is done the
+// check stops at the next file it reads and returns the error of ctx, so
+// that a long check can be cancelled or given a deadline.
+func (v *PEGMCDocValidator) CheckPack(ctx context.Context, root string, fsys fs.FS, opts PackOptions) ([]Finding, error) {
+	pack, err := openPack(ctx, root, fsys, opts)
+	if err != nil {
+		return nil, err
+	}
+	return v.validatePack(ctx, pack, opts)
+}
+
+// findingBuffer is how many findings ValidatePack checks ahead of the
+// reader of its channel
+const findingBuffer = 64
+
+// ValidatePack checks a datapack like CheckPack, sending the findings on the
+// channel as each file is checked rather than once the whole pack is, so
+// that findings of large packs can be shown as they come. The pack-level
+// checks, which need every file, come last. Problems loading the pack are
+// returned right away; the channel is closed once the check ends, which is
+// early if ctx is done or a file cannot be read. wait then returns the error
+// that ended it early, dropping the findings left on the channel, or nil.
+// Cancel ctx to stop reading from the channel before it is closed.
+func (v *PEGMCDocValidator) ValidatePack(ctx context.Context, root string, fsys fs.FS, opts PackOptions) (findings <-chan Finding, wait func() error, err error) {
+	pack, err := openPack(ctx, root, fsys, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts, only, err := v.preparePack(ctx, pack, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stream := make(chan Finding, findingBuffer)
+	var checkErr error
+	go func() {
+		defer close(stream)
+		checkErr = v.checkPack(ctx, pack, opts, only, func(finding Finding) {
+			select {
+			case stream <- finding:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	wait = func() error {
+		for range stream {
+		}
+		return checkErr
+	}
+	return stream, wait, nil
+}
+
+// openPack loads the pack CheckPack and ValidatePack check
+func openPack(ctx context.Context, root string, fsys fs.FS, opts PackOptions) (*Pack, error) {
+	if fsys == nil {
+		return loadPack(ctx, root, osFiles)
+	}
+	if opts.ChangedFrom != "" {
+		return nil, fmt.Errorf("checking changed files needs a pack on disk")
+	}
+	return loadPack(ctx, root, newFileSystem(root, fsys))
+}
+
+func (v *PEGMCDocValidator) validatePack(ctx context.Context, pack *Pack, opts PackOptions) ([]Finding, error) {
+	opts, only, err := v.preparePack(ctx, pack, opts)
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	if err := v.checkPack(ctx, pack, opts, only, func(finding Finding) { findings = append(findings, finding) }); err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// preparePack fills in the options of checking a pack and loads what they
+// name. Only lists the files to check, by their path in the pack, if
+// opts.ChangedFrom limits the check to some of them.
+func (v *PEGMCDocValidator) preparePack(ctx context.Context, pack *Pack, opts PackOptions) (PackOptions, map[string]bool, error) {
+	if opts.Version == (Version{}) {
+		opts.Version = v.targetVersion
+	}
+	if opts.Vanilla != "" && pack.Vanilla == nil {
+		vanilla := opts.vanillaPack
+		if vanilla == nil {
+			var err error
+			if vanilla, err = LoadPackContext(ctx, opts.Vanilla); err != nil {
+				return opts, nil, fmt.Errorf("vanilla datapack: %w", err)
+			}
+		}
+		pack.Vanilla = vanilla
+	}
+
+	// Limit the check to changed files and their referencers if requested
+	var only map[string]bool
+	if opts.ChangedFrom != "" {
+		changed, err := changedFiles(pack.Root, opts.ChangedFrom)
+		if err != nil {
+			return opts, nil, err
+		}
+		only = make(map[string]bool)
+		for file := range pack.AffectedFiles(changed) {
+			only[pack.RelativePath(file)] = true
+		}
+	}
+	return opts, only, nil
+}
+
+// checkPack checks the files of a pack, or those in only if it is set, and
+// runs the pack-level checks, passing each finding to emit in turn
+func (v *PEGMCDocValidator) checkPack(ctx context.Context, pack *Pack, opts PackOptions, only map[string]bool, emit func(Finding)) error {
+	for _, finding := range pack.Skipped {
+		if only == nil || only[finding.File] {
+			emit(finding)
+		}
+	}
+	for _, file := range append(pack.JSONFiles(), pack.NBTFiles()...) {
+		if only != nil && !only[pack.RelativePath(file)] {
+			continue
+		}
+		findings, err := v.checkFile(ctx, file, pack.RelativePath(file), pack)
+		if err != nil {
+			return err
+		}
+		for _, finding := range findings {
+			emit(finding)
+		}
+	}
+	for _, file := range pack.FunctionFiles() {
+		if only != nil && !only[pack.RelativePath(file)] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, finding := range v.checkFunction(file, pack.RelativePath(file), pack) {
+			emit(finding)
+		}
+	}
+
+	for _, check := range packChecks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, finding := range check(pack, opts) {
+			if only == nil || only[finding.File] {
+				emit(finding)
+			}
+		}
+	}
+	return nil
+}
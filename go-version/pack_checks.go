@@ -0,0 +1,171 @@
+package mcheck
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// packChecks are run by CheckPack once every file has been validated
+// against its schema. Each check looks at the pack as a whole, like the
+// references between its files.
+var packChecks = []func(pack *Pack, opts PackOptions) []Finding{
+	checkFileNames,
+	checkReferences,
+	checkUnused,
+	checkBudgets,
+	checkObjectives,
+}
+
+// checkFileNames reports files and directories below data/ with names the
+// game does not load. Resource paths may only use a-z, 0-9, _, - and ., and a
+// pack made on a case-insensitive file system can seem to work there while
+// losing resources on a Linux server. A bad directory is reported once rather
+// than for every file in it.
+func checkFileNames(pack *Pack, opts PackOptions) []Finding {
+	reported := make(map[string]bool)
+	var findings []Finding
+	for _, resources := range pack.Resources {
+		for _, file := range resources {
+			rel, err := filepath.Rel(filepath.Join(pack.Root, "data"), file)
+			if err != nil {
+				continue
+			}
+			parts := strings.Split(filepath.ToSlash(rel), "/")
+			for i, part := range parts {
+				problem := fileNameProblem(part)
+				if problem == "" {
+					continue
+				}
+				name := path.Join("data", path.Join(parts[:i+1]...))
+				if !reported[name] {
+					kind := "directory"
+					if i == len(parts)-1 {
+						kind = "file"
+					}
+					reported[name] = true
+					findings = append(findings, Finding{
+						File:     name,
+						Severity: SeverityError,
+						Message:  fmt.Sprintf("%s name %q has %s; the game only loads resources named with a-z, 0-9, _, - and .", kind, part, problem),
+						Rule:     RuleInvalidFileName,
+					})
+				}
+				break
+			}
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].File < findings[j].File })
+	return findings
+}
+
+// fileNameProblem describes what makes a name invalid in a resource path, or
+// returns "" if it is valid
+func fileNameProblem(name string) string {
+	var upper, spaces bool
+	var invalid []string
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r == ' ':
+			spaces = true
+		default:
+			if quoted := fmt.Sprintf("%q", r); !slices.Contains(invalid, quoted) {
+				invalid = append(invalid, quoted)
+			}
+		}
+	}
+
+	var problems []string
+	if upper {
+		problems = append(problems, "uppercase letters")
+	}
+	if spaces {
+		problems = append(problems, "spaces")
+	}
+	switch {
+	case len(invalid) == 1:
+		problems = append(problems, "the invalid character "+invalid[0])
+	case len(invalid) > 1:
+		problems = append(problems, "the invalid characters "+strings.Join(invalid, " "))
+	}
+	return strings.Join(problems, " and ")
+}
+
+// checkReferences checks that every reference between pack files resolves,
+// and with CheckNBT that referenced structure files are valid NBT
+func checkReferences(pack *Pack, opts PackOptions) []Finding {
+	var findings []Finding
+	for _, reference := range pack.References() {
+		target, ok := pack.Resolve(reference)
+		if !ok && reference.Vanilla != nil {
+			if finding, missing := missingVanillaReference(reference, opts.Version); missing {
+				finding.File = pack.RelativePath(reference.File)
+				findings = append(findings, finding)
+			}
+			continue
+		}
+		if !ok {
+			findings = append(findings, Finding{
+				File:     pack.RelativePath(reference.File),
+				Path:     reference.Path,
+				Severity: SeverityError,
+				Message:  reference.Description + " not found in pack",
+				Rule:     RuleMissingReference,
+			})
+			continue
+		}
+
+		if opts.CheckNBT && strings.HasSuffix(target, ".nbt") {
+			if err := checkStructureNBT(pack, target); err != nil {
+				findings = append(findings, Finding{
+					File:     pack.RelativePath(reference.File),
+					Path:     reference.Path,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("structure %s is not a valid structure file: %v", reference.ID, err),
+					Rule:     RuleInvalidStructure,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// missingVanillaReference reports a reference the pack does not resolve that
+// is not a vanilla resource in version either. Vanilla resources mcheck does
+// not know about are only warned about, since its lists can fall behind the
+// game.
+func missingVanillaReference(reference Reference, version Version) (Finding, bool) {
+	finding := Finding{Path: reference.Path, Severity: SeverityError, Rule: RuleMissingReference}
+	since, known := reference.Vanilla[reference.ID]
+	switch {
+	case known && version.Compare(since) >= 0:
+		return finding, false
+	case known:
+		finding.Message = fmt.Sprintf("%s not found in pack, and vanilla only has it since %s", reference.Description, since)
+	case strings.HasPrefix(reference.ID, "minecraft:"):
+		finding.Severity = SeverityWarning
+		finding.Message = fmt.Sprintf("%s not found in pack or vanilla %s", reference.Description, version)
+	default:
+		finding.Message = reference.Description + " not found in pack"
+	}
+	return finding, true
+}
+
+// checkStructureNBT parses a structure file, which must be a possibly gzip
+// compressed NBT file with a compound root tag
+func checkStructureNBT(pack *Pack, file string) error {
+	f, err := pack.files.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, _, err = ReadNBT(f)
+	return err
+}
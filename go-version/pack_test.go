@@ -0,0 +1,343 @@
+package mcheck
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePackFiles creates a datapack from a map of pack-relative paths to file
+// contents and returns its root
+func writePackFiles(t *testing.T, files map[string][]byte) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	return root
+}
+
+// gzipBytes compresses data the way structure files are stored
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	writer.Close()
+	return buf.Bytes()
+}
+
+func TestLoadPack(t *testing.T) {
+	root := writePackFiles(t, map[string][]byte{
+		"pack.mcmeta":                                        []byte(`{}`),
+		"data/test/worldgen/biome/forest.json":               []byte(`{}`),
+		"data/test/tags/worldgen/biome/hot.json":             []byte(`{}`),
+		"data/test/tags/item/logs.json":                      []byte(`{}`),
+		"data/test/structure/house/small.nbt":                []byte{},
+		"data/minecraft/loot_table/blocks/dirt.json":         []byte(`{}`),
+		"data/test/worldgen/template_pool/town/streets.json": []byte(`{}`),
+	})
+
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+
+	tests := []struct {
+		resourceType string
+		id           string
+	}{
+		{"worldgen/biome", "test:forest"},
+		{"tags/worldgen/biome", "test:hot"},
+		{"tags/item", "test:logs"},
+		{"structure", "test:house/small"},
+		{"loot_table", "blocks/dirt"},
+		{"worldgen/template_pool", "test:town/streets"},
+	}
+	for _, tt := range tests {
+		if _, ok := pack.Lookup(tt.id, tt.resourceType); !ok {
+			t.Errorf("Expected %s %s to be indexed", tt.resourceType, tt.id)
+		}
+	}
+
+	if !pack.Defines("test:anything") || pack.Defines("other:anything") {
+		t.Errorf("Unexpected namespaces %v", pack.Namespaces)
+	}
+	if files := pack.JSONFiles(); len(files) != 5 {
+		t.Errorf("Expected 5 JSON files, got %d", len(files))
+	}
+
+	if _, err := LoadPack(t.TempDir()); err == nil {
+		t.Error("Expected an error for a directory without data")
+	}
+}
+
+func TestStructureReferences(t *testing.T) {
+	validNBT := gzipBytes(t, []byte{10, 0, 0, 0})
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/structure/house.nbt":   validNBT,
+		"data/test/structures/legacy.nbt": validNBT,
+		"data/test/structure/broken.nbt":  []byte("not nbt"),
+		"data/test/structure/list.nbt":    gzipBytes(t, []byte{9, 0, 0}),
+		"data/test/worldgen/template_pool/town.json": []byte(`{
+			"fallback": "minecraft:empty",
+			"elements": [
+				{"weight": 1, "element": {"element_type": "minecraft:single_pool_element", "location": "test:house", "processors": "minecraft:empty", "projection": "rigid"}},
+				{"weight": 1, "element": {"element_type": "minecraft:single_pool_element", "location": "test:legacy", "processors": "minecraft:empty", "projection": "rigid"}},
+				{"weight": 1, "element": {"element_type": "minecraft:single_pool_element", "location": "test:missing", "processors": "minecraft:empty", "projection": "rigid"}},
+				{"weight": 1, "element": {"element_type": "minecraft:single_pool_element", "location": "minecraft:village/plains/houses/plains_small_house_1", "processors": "minecraft:empty", "projection": "rigid"}},
+				{"weight": 1, "element": {"element_type": "minecraft:list_pool_element", "projection": "rigid", "elements": [
+					{"element_type": "minecraft:single_pool_element", "location": "test:broken", "processors": "minecraft:empty", "projection": "rigid"},
+					{"element_type": "minecraft:single_pool_element", "location": "test:list", "processors": "minecraft:empty", "projection": "rigid"}
+				]}}
+			]
+		}`),
+		"data/test/worldgen/structure/town.json": []byte(`{}`),
+		"data/test/worldgen/structure_set/towns.json": []byte(`{
+			"structures": [
+				{"structure": "test:town", "weight": 1},
+				{"structure": "test:city", "weight": 1},
+				{"structure": "minecraft:village_plains", "weight": 1}
+			],
+			"placement": {"type": "minecraft:random_spread", "salt": 1, "spacing": 32, "separation": 8}
+		}`),
+	})
+
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+
+	var messages []string
+	for _, check := range packChecks {
+		for _, finding := range check(pack, PackOptions{}) {
+			messages = append(messages, finding.String())
+		}
+	}
+	expected := []string{
+		"data/test/worldgen/template_pool/town.json: at elements.2.element.location: structure file for test:missing not found in pack [MCHECK040 missing-reference]",
+		"data/test/worldgen/structure_set/towns.json: at structures.1.structure: structure test:city not found in pack [MCHECK040 missing-reference]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+
+	findings := checkReferences(pack, PackOptions{CheckNBT: true})
+	if len(findings) != 4 {
+		t.Fatalf("Expected 4 findings with NBT checks, got %v", findings)
+	}
+	for _, finding := range findings[1:3] {
+		if !strings.Contains(finding.Message, "expected compound") {
+			t.Errorf("Expected a root tag error, got %q", finding.Message)
+		}
+	}
+	if got := strings.Join(findings[2].Path, "."); got != "elements.4.element.elements.1.location" {
+		t.Errorf("Unexpected path %s", got)
+	}
+}
+
+func TestTemplatePoolReferences(t *testing.T) {
+	validNBT := gzipBytes(t, []byte{10, 0, 0, 0})
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/structure/house.nbt":                 validNBT,
+		"data/test/worldgen/processor_list/mossy.json":  []byte(`{"processors": []}`),
+		"data/test/worldgen/template_pool/streets.json": []byte(`{"fallback": "minecraft:empty", "elements": []}`),
+		"data/test/worldgen/template_pool/houses.json": []byte(`{
+			"fallback": "test:streets",
+			"elements": [
+				{"weight": 1, "element": {"element_type": "minecraft:single_pool_element", "location": "test:house", "processors": "test:mossy", "projection": "rigid"}},
+				{"weight": 1, "element": {"element_type": "minecraft:single_pool_element", "location": "test:house", "processors": "test:cracked", "projection": "rigid"}},
+				{"weight": 1, "element": {"element_type": "minecraft:single_pool_element", "location": "test:house", "processors": {"processors": []}, "projection": "rigid"}},
+				{"weight": 1, "element": {"element_type": "minecraft:list_pool_element", "projection": "rigid", "elements": [
+					{"element_type": "minecraft:single_pool_element", "location": "test:house", "processors": "minecraft:mossify_10_percent", "projection": "rigid"},
+					{"element_type": "minecraft:single_pool_element", "location": "test:house", "processors": "test:rusty", "projection": "rigid"}
+				]}}
+			]
+		}`),
+		"data/test/worldgen/template_pool/plazas.json": []byte(`{"fallback": "test:squares", "elements": []}`),
+	})
+
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+
+	var messages []string
+	for _, finding := range checkReferences(pack, PackOptions{}) {
+		messages = append(messages, finding.String())
+	}
+	expected := []string{
+		"data/test/worldgen/template_pool/houses.json: at elements.1.element.processors: processor list test:cracked not found in pack [MCHECK040 missing-reference]",
+		"data/test/worldgen/template_pool/houses.json: at elements.3.element.elements.1.processors: processor list test:rusty not found in pack [MCHECK040 missing-reference]",
+		"data/test/worldgen/template_pool/plazas.json: at fallback: fallback template pool test:squares not found in pack [MCHECK040 missing-reference]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+}
+
+func TestFunctionTagReferences(t *testing.T) {
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/function/init.mcfunction":      []byte("say hi\n"),
+		"data/test/functions/legacy.mcfunction":   []byte("say hi\n"),
+		"data/test/tags/function/setup.json":      []byte(`{"values": ["test:init"]}`),
+		"data/minecraft/tags/function/load.json":  []byte(`{"values": ["test:init", "#test:setup", "test:legacy", "test:missing", "#test:nope"]}`),
+		"data/minecraft/tags/functions/tick.json": []byte(`{"values": [{"id": "test:gone", "required": false}, {"id": "test:absent"}, "loop"]}`),
+	})
+
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+
+	var messages []string
+	for _, finding := range checkReferences(pack, PackOptions{}) {
+		messages = append(messages, finding.String())
+	}
+	expected := []string{
+		"data/minecraft/tags/function/load.json: at values.3: function test:missing referenced by #minecraft:load not found in pack [MCHECK040 missing-reference]",
+		"data/minecraft/tags/function/load.json: at values.4: function tag #test:nope referenced by #minecraft:load not found in pack [MCHECK040 missing-reference]",
+		"data/minecraft/tags/functions/tick.json: at values.1.id: function test:absent referenced by #minecraft:tick not found in pack [MCHECK040 missing-reference]",
+		"data/minecraft/tags/functions/tick.json: at values.2: function minecraft:loop referenced by #minecraft:tick not found in pack [MCHECK040 missing-reference]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+}
+
+func TestRecipeTagReferences(t *testing.T) {
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/tags/item/gems.json":      []byte(`{"values": ["minecraft:diamond"]}`),
+		"data/test/tags/items/metals.json":   []byte(`{"values": ["minecraft:iron_ingot"]}`),
+		"data/minecraft/tags/item/mine.json": []byte(`{"values": ["minecraft:stone"]}`),
+		"data/test/recipe/shaped.json": []byte(`{"type": "minecraft:crafting_shaped", "pattern": ["ab"], "key": {
+			"a": {"tag": "test:gems"}, "b": [{"tag": "test:missing"}, {"item": "minecraft:stick"}]
+		}, "result": {"id": "minecraft:stone"}}`),
+		"data/test/recipe/shapeless.json": []byte(`{"type": "minecraft:crafting_shapeless", "ingredients": [
+			"#test:metals", "#minecraft:planks", "#minecraft:mine", "#minecraft:not_a_tag", "#other:tag", "minecraft:stick"
+		], "result": {"id": "minecraft:stone"}}`),
+		"data/test/recipes/smelt.json": []byte(`{"type": "minecraft:smelting", "ingredient": {"tag": "minecraft:pale_oak_logs"}, "result": "minecraft:charcoal"}`),
+	})
+
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+
+	var messages []string
+	for _, finding := range checkReferences(pack, PackOptions{Version: Version{1, 21, 1}}) {
+		messages = append(messages, finding.String())
+	}
+	expected := []string{
+		"data/test/recipe/shaped.json: at key.b.0.tag: item tag #test:missing not found in pack [MCHECK040 missing-reference]",
+		"data/test/recipe/shapeless.json: warning: at ingredients.3: item tag #minecraft:not_a_tag not found in pack or vanilla 1.21.1 [MCHECK040 missing-reference]",
+		"data/test/recipes/smelt.json: at ingredient.tag: item tag #minecraft:pale_oak_logs not found in pack, and vanilla only has it since 1.21.2 [MCHECK040 missing-reference]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+}
+
+func TestFileNames(t *testing.T) {
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/worldgen/biome/forest.json":         []byte(`{}`),
+		"data/test/worldgen/biome/Dark Forest.json":    []byte(`{}`),
+		"data/test/loot_table/chests/tower+1.json":     []byte(`{}`),
+		"data/test/structure/house/v1.0-small.nbt":     []byte{},
+		"data/test/function/Setup/load.mcfunction":     []byte(``),
+		"data/test/function/Setup/tick.mcfunction":     []byte(``),
+		"data/MyPack/recipe/bread.json":                []byte(`{}`),
+		"data/test/advancement/café/ünïcode.json":      []byte(`{}`),
+		"data/test/worldgen/template_pool/town/A.JSON": []byte(`{}`),
+	})
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+
+	var messages []string
+	for _, finding := range checkFileNames(pack, PackOptions{}) {
+		messages = append(messages, finding.String())
+	}
+	expected := []string{
+		`data/MyPack: directory name "MyPack" has uppercase letters; the game only loads resources named with a-z, 0-9, _, - and . [MCHECK043 invalid-file-name]`,
+		`data/test/advancement/café: directory name "café" has the invalid character 'é'; the game only loads resources named with a-z, 0-9, _, - and . [MCHECK043 invalid-file-name]`,
+		`data/test/function/Setup: directory name "Setup" has uppercase letters; the game only loads resources named with a-z, 0-9, _, - and . [MCHECK043 invalid-file-name]`,
+		`data/test/loot_table/chests/tower+1.json: file name "tower+1.json" has the invalid character '+'; the game only loads resources named with a-z, 0-9, _, - and . [MCHECK043 invalid-file-name]`,
+		`data/test/worldgen/biome/Dark Forest.json: file name "Dark Forest.json" has uppercase letters and spaces; the game only loads resources named with a-z, 0-9, _, - and . [MCHECK043 invalid-file-name]`,
+		`data/test/worldgen/template_pool/town/A.JSON: file name "A.JSON" has uppercase letters; the game only loads resources named with a-z, 0-9, _, - and . [MCHECK043 invalid-file-name]`,
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+
+	if problem := fileNameProblem("a~b!"); problem != "the invalid characters '~' '!'" {
+		t.Errorf("Unexpected problem %q", problem)
+	}
+}
+
+func TestVanillaReferences(t *testing.T) {
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/worldgen/biome/meadow.json": []byte(`{"features": [[], ["minecraft:trees_plains", "test:flowers", "minecraft:trees_typo"], "#test:ores"],
+			"carvers": {"air": ["minecraft:cave", "test:canyon"]}}`),
+		"data/test/worldgen/placed_feature/flowers.json": []byte(`{"feature": "minecraft:flower_default", "placement": []}`),
+	})
+	vanilla := writePackFiles(t, map[string][]byte{
+		"data/minecraft/worldgen/placed_feature/trees_plains.json":       []byte(`{}`),
+		"data/minecraft/worldgen/configured_feature/flower_default.json": []byte(`{}`),
+		"data/minecraft/worldgen/configured_carver/cave.json":            []byte(`{}`),
+	})
+
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+	check := func() string {
+		var messages []string
+		for _, finding := range checkReferences(pack, PackOptions{}) {
+			messages = append(messages, finding.String())
+		}
+		return strings.Join(messages, "\n")
+	}
+
+	// Without vanilla data only the references into the pack are checked
+	expected := []string{
+		"data/test/worldgen/biome/meadow.json: at features.2: placed feature tag #test:ores not found in pack [MCHECK040 missing-reference]",
+		"data/test/worldgen/biome/meadow.json: at carvers.air.1: carver test:canyon not found in pack [MCHECK040 missing-reference]",
+	}
+	if messages := check(); messages != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", messages)
+	}
+
+	if pack.Vanilla, err = LoadPack(vanilla); err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+	expected = []string{
+		"data/test/worldgen/biome/meadow.json: at features.1.2: placed feature minecraft:trees_typo not found in pack [MCHECK040 missing-reference]",
+		"data/test/worldgen/biome/meadow.json: at features.2: placed feature tag #test:ores not found in pack [MCHECK040 missing-reference]",
+		"data/test/worldgen/biome/meadow.json: at carvers.air.1: carver test:canyon not found in pack [MCHECK040 missing-reference]",
+	}
+	if messages := check(); messages != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings with vanilla data:\n%s", messages)
+	}
+
+	if dir, err := FindVanillaData(vanilla); err != nil || dir != vanilla {
+		t.Errorf("Expected a vanilla datapack directory to be used as is, got %s %v", dir, err)
+	}
+	if _, err := FindVanillaData("1.21.1"); err == nil || !strings.Contains(err.Error(), "vanilla-fixtures") {
+		t.Errorf("Expected missing vanilla data to explain how to extract it, got %v", err)
+	}
+}
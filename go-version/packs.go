@@ -0,0 +1,275 @@
+package mcheck
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// CheckPacks checks datapacks on disk loaded together, like the datapacks of
+// a server, given in load order. Each pack is checked like CheckPack, with
+// references resolving in any of the packs, and a resource defined by two
+// packs is reported on the later one, which replaces it. Tags are merged
+// instead, and reported with the values they end up with. Findings name
+// files by the root of their pack followed by their path within it.
+func (v *PEGMCDocValidator) CheckPacks(ctx context.Context, roots []string, opts PackOptions) ([]Finding, error) {
+	packs := make([]*Pack, len(roots))
+	for i, root := range roots {
+		pack, err := LoadPackContext(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		packs[i] = pack
+	}
+	vanilla := opts.vanillaPack
+	if opts.Vanilla != "" && vanilla == nil {
+		var err error
+		if vanilla, err = LoadPackContext(ctx, opts.Vanilla); err != nil {
+			return nil, fmt.Errorf("vanilla datapack: %w", err)
+		}
+	}
+	for i, pack := range packs {
+		pack.Vanilla = vanilla
+		for j, other := range packs {
+			if i != j {
+				pack.Others = append(pack.Others, other)
+			}
+		}
+	}
+
+	var findings []Finding
+	for _, pack := range packs {
+		packFindings, err := v.validatePack(ctx, pack, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, finding := range packFindings {
+			findings = append(findings, packFinding(pack, finding))
+		}
+	}
+	findings = append(findings, packConflicts(packs)...)
+	return append(findings, mergedTags(packs)...), nil
+}
+
+// packFinding names the file of a finding about a pack by the pack's root,
+// like server/packs/terrain/data/... rather than data/...
+func packFinding(pack *Pack, finding Finding) Finding {
+	if finding.File != "" && !filepath.IsAbs(finding.File) {
+		finding.File = path.Join(filepath.ToSlash(pack.Root), finding.File)
+	}
+	return finding
+}
+
+// packConflicts reports the resources of each pack that a pack loaded
+// earlier defines as well. Tags are left out: the game merges the values of
+// tags of the same name rather than replacing them, see mergedTags.
+func packConflicts(packs []*Pack) []Finding {
+	var findings []Finding
+	for i, pack := range packs {
+		for _, file := range pack.Files() {
+			_, resourceType, id, ok := pack.resourceOf(file)
+			if !ok || resourceType == "" || strings.HasPrefix(resourceType, "tags/") {
+				continue
+			}
+			for _, earlier := range packs[:i] {
+				if _, defined := earlier.Resources[resourceType][id]; defined {
+					findings = append(findings, packFinding(pack, Finding{
+						File:     pack.RelativePath(file),
+						Severity: SeverityWarning,
+						Message:  fmt.Sprintf("%s %s replaces the one in %s, which loads earlier", resourceType, id, earlier.Root),
+						Rule:     RulePackConflict,
+					}))
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// MergedTag is a tag several packs define, with the values the game ends
+// up with after loading them in order
+type MergedTag struct {
+	Type   string   // tag type, like tags/function
+	ID     string   // tag id, like minecraft:load
+	Values []string // entries like test:init, #test:setup or test:optional?
+	Files  []string // the files defining the tag, in load order
+}
+
+// MergeTags merges the tags defined by more than one of the packs, given in
+// load order. Each pack's values are added to those of the packs before it,
+// unless it sets replace, which drops them. Dropped names the files whose
+// values a replace dropped, by the file replacing them.
+func MergeTags(packs []*Pack) (merged []MergedTag, dropped map[string][]string) {
+	type tagKey struct{ tagType, id string }
+	var keys []tagKey
+	defined := make(map[tagKey][]*Pack)
+	for _, pack := range packs {
+		for _, resourceType := range sortedResourceTypes(pack) {
+			if !strings.HasPrefix(resourceType, "tags/") {
+				continue
+			}
+			for _, id := range sortedIDs(pack.Resources[resourceType]) {
+				key := tagKey{resourceType, id}
+				if defined[key] == nil {
+					keys = append(keys, key)
+				}
+				defined[key] = append(defined[key], pack)
+			}
+		}
+	}
+
+	dropped = make(map[string][]string)
+	for _, key := range keys {
+		if len(defined[key]) < 2 {
+			continue
+		}
+		tag := MergedTag{Type: key.tagType, ID: key.id}
+		for _, pack := range defined[key] {
+			file := pack.Resources[key.tagType][key.id]
+			value, _ := pack.readJSON(file)
+			if replace, _ := value["replace"].(bool); replace {
+				dropped[file] = append(dropped[file], tag.Files...)
+				tag.Values = nil
+			}
+			tag.Files = append(tag.Files, file)
+			entries, _ := value["values"].([]interface{})
+			for _, entry := range entries {
+				if name := tagEntryName(entry); name != "" && !slices.Contains(tag.Values, name) {
+					tag.Values = append(tag.Values, name)
+				}
+			}
+		}
+		merged = append(merged, tag)
+	}
+	return merged, dropped
+}
+
+// tagEntryName writes a tag entry as an id or #tag, followed by ? if it is
+// not required
+func tagEntryName(entry interface{}) string {
+	switch v := entry.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		id, _ := v["id"].(string)
+		if required, ok := v["required"].(bool); ok && !required && id != "" {
+			return id + "?"
+		}
+		return id
+	}
+	return ""
+}
+
+// sortedResourceTypes returns the resource types of a pack in order
+func sortedResourceTypes(pack *Pack) []string {
+	types := make([]string, 0, len(pack.Resources))
+	for resourceType := range pack.Resources {
+		types = append(types, resourceType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// mergedTags notes the values of each tag several packs define, on the
+// file of the last of them, and warns about those that set replace and
+// drop the values of the packs before them
+func mergedTags(packs []*Pack) []Finding {
+	owner := make(map[string]*Pack)
+	for _, pack := range packs {
+		for _, file := range pack.Files() {
+			owner[file] = pack
+		}
+	}
+	relative := func(file string) string {
+		return packFinding(owner[file], Finding{File: owner[file].RelativePath(file)}).File
+	}
+
+	merged, dropped := MergeTags(packs)
+	var findings []Finding
+	for _, tag := range merged {
+		for _, file := range tag.Files {
+			if len(dropped[file]) == 0 {
+				continue
+			}
+			var others []string
+			for _, other := range dropped[file] {
+				others = append(others, relative(other))
+			}
+			findings = append(findings, Finding{
+				File:     relative(file),
+				Path:     []string{"replace"},
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("tag #%s replaces the values of %s, which load earlier", tag.ID, strings.Join(others, ", ")),
+				Rule:     RuleMergedTag,
+			})
+		}
+		values := strings.Join(tag.Values, ", ")
+		if values == "" {
+			values = "no values"
+		}
+		findings = append(findings, Finding{
+			File:     relative(tag.Files[len(tag.Files)-1]),
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("tag #%s is merged from %d packs into %s", tag.ID, len(tag.Files), values),
+			Rule:     RuleMergedTag,
+		})
+	}
+	return findings
+}
+
+// OrderPacks puts the pack roots in the load order given by order, which
+// names each of them once by its path or directory name
+func OrderPacks(roots, order []string) ([]string, error) {
+	if len(order) == 0 {
+		return roots, nil
+	}
+	if len(order) != len(roots) {
+		return nil, fmt.Errorf("--order names %d packs, but %d were given", len(order), len(roots))
+	}
+	ordered := make([]string, 0, len(roots))
+	used := make(map[string]bool)
+	for _, name := range order {
+		found := ""
+		for _, root := range roots {
+			if filepath.Clean(root) == filepath.Clean(name) || filepath.Base(filepath.Clean(root)) == name {
+				found = root
+				break
+			}
+		}
+		if found == "" {
+			return nil, fmt.Errorf("--order names %s, which is not one of the packs given", name)
+		}
+		if used[found] {
+			return nil, fmt.Errorf("--order names %s twice", name)
+		}
+		used[found] = true
+		ordered = append(ordered, found)
+	}
+	return ordered, nil
+}
+
+// vanillaDataDir is where findVanillaData looks for the vanilla datapacks
+// of each version, as tools/vanilla-fixtures --out vanilla-data writes them
+const vanillaDataDir = "vanilla-data"
+
+// FindVanillaData returns the directory of the vanilla datapack --vanilla
+// names, either directly or by its version in vanilla-data
+func FindVanillaData(vanilla string) (string, error) {
+	if _, err := os.Stat(filepath.Join(vanilla, "data")); err == nil {
+		return vanilla, nil
+	}
+	version, err := ParseVersion(vanilla)
+	if err != nil {
+		return "", fmt.Errorf("--vanilla %s is neither a datapack directory nor a version", vanilla)
+	}
+	dir := filepath.Join(vanillaDataDir, version.String())
+	if _, err := os.Stat(filepath.Join(dir, "data")); err != nil {
+		return "", fmt.Errorf("no vanilla data for %s in %s, extract it with go run ./tools/vanilla-fixtures --out %s --version %s <server.jar>", version, dir, vanillaDataDir, version)
+	}
+	return dir, nil
+}
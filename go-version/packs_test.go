@@ -0,0 +1,107 @@
+package mcheck
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckPacks(t *testing.T) {
+	library := writePackFiles(t, map[string][]byte{
+		"data/lib/function/setup.mcfunction":  []byte("say setup\n"),
+		"data/lib/function/shared.mcfunction": []byte("say library\n"),
+		"data/lib/tags/function/hooks.json":   []byte(`{"values": ["lib:setup"]}`),
+	})
+	game := writePackFiles(t, map[string][]byte{
+		"data/game/function/init.mcfunction":     []byte("function lib:setup\n"),
+		"data/lib/function/shared.mcfunction":    []byte("say game\n"),
+		"data/lib/tags/function/hooks.json":      []byte(`{"values": ["game:init"]}`),
+		"data/minecraft/tags/function/load.json": []byte(`{"values": ["#lib:hooks", "lib:setup", "lib:missing"]}`),
+	})
+
+	validator := NewPEGMCDocValidator(Version{1, 21, 1}, fixtureSchemaDir(t, "tag"))
+	findings, err := validator.CheckPacks(context.Background(), []string{library, game}, PackOptions{})
+	if err != nil {
+		t.Fatalf("CheckPacks failed: %v", err)
+	}
+	var messages []string
+	for _, finding := range findings {
+		if finding.Rule == RuleMissingReference || finding.Rule == RulePackConflict {
+			messages = append(messages, finding.String())
+		}
+	}
+	gameFile := filepath.ToSlash(game) + "/data"
+	expected := []string{
+		gameFile + "/minecraft/tags/function/load.json: at values.2: function lib:missing referenced by #minecraft:load not found in pack [MCHECK040 missing-reference]",
+		gameFile + "/lib/function/shared.mcfunction: warning: function lib:shared replaces the one in " + library + ", which loads earlier [MCHECK048 pack-conflict]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+}
+
+func TestOrderPacks(t *testing.T) {
+	roots := []string{"packs/terrain", "packs/mobs", "other/base"}
+	ordered, err := OrderPacks(roots, []string{"base", "packs/mobs", "terrain"})
+	if err != nil || strings.Join(ordered, " ") != "other/base packs/mobs packs/terrain" {
+		t.Errorf("Unexpected order %v, %v", ordered, err)
+	}
+	if _, err := OrderPacks(roots, []string{"base", "mobs"}); err == nil {
+		t.Errorf("Expected an error for an order leaving out a pack")
+	}
+	if _, err := OrderPacks(roots, []string{"base", "mobs", "unknown"}); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("Expected an error for an unknown pack, got %v", err)
+	}
+	if _, err := OrderPacks(roots, []string{"base", "mobs", "mobs"}); err == nil {
+		t.Errorf("Expected an error for a pack named twice")
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	var packs []*Pack
+	var roots []string
+	for _, files := range []map[string][]byte{
+		{"data/minecraft/tags/block/logs.json": []byte(`{"values": ["a:log"]}`)},
+		{"data/minecraft/tags/block/logs.json": []byte(`{"values": ["b:log", {"id": "b:maybe", "required": false}, "a:log"]}`)},
+		{
+			"data/minecraft/tags/block/logs.json":   []byte(`{"replace": true, "values": ["#c:logs"]}`),
+			"data/minecraft/tags/block/leaves.json": []byte(`{"values": ["c:leaves"]}`),
+		},
+	} {
+		root := writePackFiles(t, files)
+		pack, err := LoadPack(root)
+		if err != nil {
+			t.Fatalf("LoadPack failed: %v", err)
+		}
+		packs = append(packs, pack)
+		roots = append(roots, filepath.ToSlash(root))
+	}
+
+	merged, dropped := MergeTags(packs)
+	if len(merged) != 1 || merged[0].ID != "minecraft:logs" || strings.Join(merged[0].Values, " ") != "#c:logs" || len(merged[0].Files) != 3 {
+		t.Fatalf("Unexpected merged tags %+v", merged)
+	}
+	if replacing := dropped[merged[0].Files[2]]; len(replacing) != 2 {
+		t.Errorf("Expected the third pack to drop the values of the others, got %v", dropped)
+	}
+
+	var messages []string
+	for _, finding := range mergedTags(packs) {
+		messages = append(messages, finding.String())
+	}
+	logs := "/data/minecraft/tags/block/logs.json"
+	expected := []string{
+		roots[2] + logs + ": warning: at replace: tag #minecraft:logs replaces the values of " + roots[0] + logs + ", " + roots[1] + logs + ", which load earlier [MCHECK049 merged-tag]",
+		roots[2] + logs + ": note: tag #minecraft:logs is merged from 3 packs into #c:logs [MCHECK049 merged-tag]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+
+	// Without the replacing pack the values add up
+	merged, _ = MergeTags(packs[:2])
+	if len(merged) != 1 || strings.Join(merged[0].Values, " ") != "a:log b:log b:maybe?" {
+		t.Errorf("Unexpected merged tags %+v", merged)
+	}
+}
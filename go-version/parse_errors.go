@@ -0,0 +1,223 @@
+package mcheck
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// mcdocSyntaxError is a schema that does not parse, reported at the place
+// parsing stopped along with what the grammar would have accepted there
+type mcdocSyntaxError struct {
+	file     string
+	line     int // from one
+	column   int // from one, in characters
+	expected []string
+	after    string // the token before the error, empty at the start of the file
+	found    string // the token at the error, empty at the end of the file
+}
+
+func (e *mcdocSyntaxError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.file, e.line, e.column, e.message())
+}
+
+// message describes the error without its position, like expected type
+// after ':'
+func (e *mcdocSyntaxError) message() string {
+	var text string
+	switch {
+	case len(e.expected) > 0:
+		text = "expected " + joinAlternatives(e.expected)
+	case e.found != "":
+		return fmt.Sprintf("unexpected '%s'", e.found)
+	default:
+		return "unexpected end of file"
+	}
+	if e.after != "" {
+		text += fmt.Sprintf(" after '%s'", e.after)
+	}
+	if e.found == "" {
+		text += " at the end of the file"
+	}
+	return text
+}
+
+// joinAlternatives lists alternatives like a, b or c
+func joinAlternatives(alternatives []string) string {
+	if len(alternatives) == 1 {
+		return alternatives[0]
+	}
+	return strings.Join(alternatives[:len(alternatives)-1], ", ") + " or " + alternatives[len(alternatives)-1]
+}
+
+// syntaxProbes are what the grammar could expect somewhere, each with text
+// that matches it. Parsing a schema up to an error followed by the text of a
+// probe tells whether the probe would have been accepted there. Parsers
+// only track how far the rules they matched reach, so the text of a probe
+// ends in a whole rule, like the type after a dispatcher's to, and rules
+// a probe would only start, like the resource path after the colon of
+// minecraft:item, do not count.
+var syntaxProbes = []struct {
+	text, expected string
+}{
+	{"int[]", "type"},
+	{"x", "name"},
+	{`"x"`, "string"},
+	{"1..", "range"},
+	{"1", "number"},
+	{"use x", "statement"},
+	// Punctuation that separates or closes what comes before it
+	{": [int]", "':'"},
+	{"=", "'='"},
+	{",", "','"},
+	{"|", "'|'"},
+	{"{", "'{'"},
+	{"}", "'}'"},
+	{")", "')'"},
+	{"]", "']'"},
+	{">", "'>'"},
+	{"to x", "'to'"},
+}
+
+// continuationProbes are punctuation that continues what comes before it,
+// like the ? of an optional field or the @ of a range, expected only if
+// nothing in syntaxProbes is
+var continuationProbes = []struct {
+	text, expected string
+}{
+	{"(", "'('"},
+	{"[", "'['"},
+	{"?", "'?'"},
+	{"@", "'@'"},
+	{"<", "'<'"},
+	{"...", "'...'"},
+}
+
+// describeParseError turns the error of parsing a schema into one naming
+// the line and column parsing stopped at and what would have been accepted
+// there, like expected type after ':'. Errors other than those of the
+// parser are returned as they are.
+func describeParseError(parser *MCDocParser, err error, file string) error {
+	var parseErr *parseError
+	if !errors.As(err, &parseErr) {
+		return err
+	}
+	buffer := []rune(parser.Buffer)
+	position := int(parseErr.max.end)
+	if position > len(buffer) {
+		position = len(buffer)
+	}
+
+	described := &mcdocSyntaxError{file: file, line: 1, column: 1}
+	for _, c := range buffer[:position] {
+		if c == '\n' {
+			described.line, described.column = described.line+1, 1
+		} else {
+			described.column++
+		}
+	}
+	prefix := string(buffer[:position])
+	if tokens := mcdocTokens(buffer[:position], 0); len(tokens) > 0 {
+		described.after = tokens[len(tokens)-1]
+	}
+	if tokens := mcdocTokens(buffer[position:], 1); len(tokens) > 0 {
+		described.found = tokens[0]
+	}
+
+	accepted := acceptedProbes(prefix, syntaxProbes)
+	if len(accepted) == 0 {
+		accepted = acceptedProbes(prefix, continuationProbes)
+	}
+	for _, expected := range accepted {
+		if !coveredProbe(expected, accepted) {
+			described.expected = append(described.expected, expected)
+		}
+	}
+	return described
+}
+
+// probesCovering lists the probes whose text also matches another, like the
+// names, strings and brackets types start with, which would only repeat
+// what the other already says
+var probesCovering = map[string][]string{
+	"type": {"name", "string", "number", "range", "'('", "'['", "'to'"},
+	"name": {"'to'"},
+}
+
+func coveredProbe(expected string, accepted []string) bool {
+	for _, other := range accepted {
+		for _, covered := range probesCovering[other] {
+			if covered == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// acceptedProbes returns the probes that parse when they follow prefix
+func acceptedProbes(prefix string, probes []struct{ text, expected string }) []string {
+	var accepted []string
+	for _, probe := range probes {
+		text := prefix
+		// Keep words apart, like a name after the struct keyword
+		if text != "" && isWordRune(rune(text[len(text)-1])) && isWordRune(rune(probe.text[0])) {
+			text += " "
+		}
+		text += probe.text
+
+		parser := &MCDocParser{Buffer: text}
+		if parser.Init() != nil {
+			continue
+		}
+		err := parser.Parse()
+		var parseErr *parseError
+		if err == nil || errors.As(err, &parseErr) && int(parseErr.max.end) >= len([]rune(text)) {
+			accepted = append(accepted, probe.expected)
+		}
+	}
+	return accepted
+}
+
+// mcdocTokens splits schema text into tokens, leaving out whitespace and
+// comments, for naming the tokens around a syntax error. A limit above zero
+// stops after that many tokens.
+func mcdocTokens(runes []rune, limit int) []string {
+	var tokens []string
+	for i := 0; i < len(runes) && (limit <= 0 || len(tokens) < limit); {
+		c := runes[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+			continue
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '"':
+			for i++; i < len(runes) && runes[i] != '"' && runes[i] != '\n'; i++ {
+			}
+			if i < len(runes) && runes[i] == '"' {
+				i++
+			}
+		case isWordRune(c):
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+		case c == '.' && i+2 < len(runes) && runes[i+1] == '.' && runes[i+2] == '.':
+			i += 3
+		case (c == '.' || c == ':') && i+1 < len(runes) && runes[i+1] == c:
+			i += 2
+		default:
+			i++
+		}
+		tokens = append(tokens, string(runes[start:i]))
+	}
+	return tokens
+}
+
+func isWordRune(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
@@ -0,0 +1,53 @@
+package mcheck
+
+import "testing"
+
+func TestDescribeParseError(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"missing field type", "struct A {\n\tname: ,\n}\n", "a.mcdoc:2:8: expected type after ':'"},
+		{"missing colon", "struct A {\n\tname string,\n}\n", "a.mcdoc:2:7: expected ':' after 'name'"},
+		{"missing comma", "struct A {\n\tname: int\n\tother: int,\n}\n", "a.mcdoc:3:2: expected ',' or '}' after 'int'"},
+		{"unclosed struct", "struct A {\n\tname: int,\n", "a.mcdoc:3:1: expected name or '}' after ',' at the end of the file"},
+		{"unnamed struct", "struct {\n}\n", "a.mcdoc:1:8: expected name after 'struct'"},
+		{"empty range", "struct A {\n\tv: int @ ,\n}\n", "a.mcdoc:2:11: expected range or number after '@'"},
+		{"stray brace", "struct A {}\n}\n", "a.mcdoc:2:1: expected statement after '}'"},
+		{"enum without type", "enum A {}\n", "a.mcdoc:1:6: expected '(' after 'enum'"},
+		{"enum number", "enum(string) A {\n\tX = 1,\n}\n", "a.mcdoc:2:6: expected string after '='"},
+		{"dispatch without to", "dispatch minecraft:resource[a] struct A {}\n", "a.mcdoc:1:32: expected 'to' after ']'"},
+		{"unclosed union", "type A = (int | string\n", "a.mcdoc:2:1: expected '|' or ')' after 'string' at the end of the file"},
+		{"unclosed array", "struct A { a: [int }\n", "a.mcdoc:1:20: expected ']' after 'int'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &MCDocParser{Buffer: tt.source}
+			if err := parser.Init(); err != nil {
+				t.Fatal(err)
+			}
+			err := parser.Parse()
+			if err == nil {
+				t.Fatal("Expected the schema not to parse")
+			}
+			if described := describeParseError(parser, err, "a.mcdoc"); described.Error() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, described)
+			}
+		})
+	}
+}
+
+func TestMCDocTokens(t *testing.T) {
+	tokens := mcdocTokens([]rune("a: int @ 1.., // note\n\t...minecraft::b \"x y\""), 0)
+	expected := []string{"a", ":", "int", "@", "1", "..", ",", "...", "minecraft", "::", "b", `"x y"`}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %q, got %q", expected, tokens)
+	}
+	for i := range expected {
+		if tokens[i] != expected[i] {
+			t.Errorf("Expected %q, got %q", expected, tokens)
+			break
+		}
+	}
+}
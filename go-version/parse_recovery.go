@@ -0,0 +1,130 @@
+package mcheck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// skippedStatement is a top level statement of a schema that does not parse
+// and was left out of it, so that the rest of the schema can still be used
+type skippedStatement struct {
+	file  string   // schema file, like worldgen/biome.mcdoc
+	line  int      // line the statement starts on, from one
+	names []string // types the statement declares
+	err   error
+}
+
+// source returns the file and line of the statement, like
+// worldgen/biome.mcdoc:12
+func (s skippedStatement) source() string {
+	return fmt.Sprintf("%s:%d", s.file, s.line)
+}
+
+func (s skippedStatement) Error() string {
+	return fmt.Sprintf("skipped the statement at line %d: %v", s.line, s.err)
+}
+
+// skippedStatementsError is returned along with the statements of a schema
+// parsed without the statements that do not parse
+type skippedStatementsError struct {
+	skipped []skippedStatement
+}
+
+func (e *skippedStatementsError) Error() string {
+	messages := make([]string, len(e.skipped))
+	for i, skipped := range e.skipped {
+		messages[i] = skipped.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// statementSpan is the lines of a top level statement, along with the doc
+// comments and attributes before it
+type statementSpan struct {
+	start, end int // lines, from zero, end exclusive
+}
+
+// statementSpans splits a schema into its top level statements. Statements
+// start at the beginning of a line with a keyword, an attribute or a
+// comment, while the lines of their bodies are indented or start with a
+// closing bracket, like the } ending a struct or the ] to of a dispatch
+// listing its keys one per line. Doc comments and attributes stay with the
+// statement they precede. Counting brackets instead would let a statement
+// missing one swallow the rest of the file.
+func statementSpans(lines []string) []statementSpan {
+	var spans []statementSpan
+	start, content := 0, false
+	for i, line := range lines {
+		startsStatement := line != "" && (isLetter(line[0]) || line[0] == '#' || line[0] == '/')
+		if startsStatement && content {
+			spans = append(spans, statementSpan{start, i})
+			start, content = i, false
+		}
+		if !isPreamble(line) {
+			content = true
+		}
+	}
+	if start < len(lines) {
+		spans = append(spans, statementSpan{start, len(lines)})
+	}
+	return spans
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+// recoverStatements finds the top level statements of a schema that does
+// not parse as a whole which are to blame, parsing each on its own at its
+// place in the file so that errors name the right lines. It returns the
+// source with those statements blanked out, keeping the lines of the rest,
+// along with the statements it skipped. Skipped statements are named by
+// file, like finding sources, and their errors by path.
+func recoverStatements(source, file, path string) (string, []skippedStatement) {
+	lines := strings.Split(source, "\n")
+	var skipped []skippedStatement
+	for _, span := range statementSpans(lines) {
+		text := strings.Join(lines[span.start:span.end], "\n")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		parser := &MCDocParser{Buffer: strings.Repeat("\n", span.start) + text}
+		if err := parser.Init(); err != nil {
+			continue
+		}
+		if err := parser.Parse(); err != nil {
+			var names []string
+			for _, match := range indexType.FindAllStringSubmatch(text, -1) {
+				names = append(names, match[1])
+			}
+			skipped = append(skipped, skippedStatement{
+				file:  file,
+				line:  firstContentLine(lines, span) + 1,
+				names: names,
+				err:   describeParseError(parser, err, path),
+			})
+			for i := span.start; i < span.end; i++ {
+				lines[i] = ""
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), skipped
+}
+
+// firstContentLine returns the line of a span the statement itself starts
+// on, after its doc comments and attributes
+func firstContentLine(lines []string, span statementSpan) int {
+	for i := span.start; i < span.end; i++ {
+		if !isPreamble(lines[i]) {
+			return i
+		}
+	}
+	return span.start
+}
+
+// isPreamble reports whether a line holds nothing but comments or
+// attributes, which belong to the statement after them
+func isPreamble(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#[") && strings.HasSuffix(trimmed, "]")
+}
@@ -0,0 +1,91 @@
+package mcheck
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStatementSpans(t *testing.T) {
+	lines := strings.Split(`use ::java::util::Text
+
+/// A thing
+#[since="1.20"]
+struct Thing {
+	name: string,
+}
+
+dispatch minecraft:resource[
+	a,
+	b,
+] to struct Other {}
+// trailing comment`, "\n")
+
+	expected := []statementSpan{{0, 2}, {2, 8}, {8, 12}, {12, 13}}
+	if spans := statementSpans(lines); !reflect.DeepEqual(spans, expected) {
+		t.Errorf("Expected spans %v, got %v", expected, spans)
+	}
+}
+
+func TestRecoverStatements(t *testing.T) {
+	source := `struct Good {
+	name: string,
+}
+
+/// Missing the type of a field
+struct Broken {
+	name: ,
+}
+
+type Alias = Good
+`
+	recovered, skipped := recoverStatements(source, "worldgen/thing.mcdoc", "vanilla-mcdoc/java/data/worldgen/thing.mcdoc")
+	if len(skipped) != 1 {
+		t.Fatalf("Expected one skipped statement, got %v", skipped)
+	}
+	if skipped[0].source() != "worldgen/thing.mcdoc:6" || !reflect.DeepEqual(skipped[0].names, []string{"Broken"}) {
+		t.Errorf("Expected Broken to be skipped at line 6, got %s declaring %v", skipped[0].source(), skipped[0].names)
+	}
+	if expected := "vanilla-mcdoc/java/data/worldgen/thing.mcdoc:7:8: expected type after ':'"; skipped[0].err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, skipped[0].err)
+	}
+
+	lines := strings.Split(recovered, "\n")
+	if len(lines) != strings.Count(source, "\n")+1 || strings.Contains(recovered, "Broken") || lines[9] != "type Alias = Good" {
+		t.Errorf("Expected only the broken statement blanked out, got:\n%s", recovered)
+	}
+}
+
+func TestSkippedStatements(t *testing.T) {
+	schemaDir, jsonPath := writeTestPack(t, "worldgen/thing", `dispatch minecraft:resource["worldgen/thing"] to struct Thing {
+	name: string,
+	broken?: Broken,
+}
+
+struct Broken {
+	value: int @,
+}
+`, `{"name": 1, "broken": "anything"}`)
+
+	validator := NewPEGMCDocValidator(Version{1, 20, 0}, schemaDir)
+	findings := validator.CheckFile(jsonPath, "example.json")
+	if len(findings) != 3 {
+		t.Fatalf("Expected a type error, a partial validation and a schema warning, got %v", findings)
+	}
+	if findings[0].Rule != RuleWrongType || !reflect.DeepEqual(findings[0].Path, []string{"name"}) {
+		t.Errorf("Expected name to be checked, got %v", findings[0])
+	}
+	if findings[1].Rule != RulePartiallyValidated || !strings.Contains(findings[1].Message, "type Broken is declared by a statement that does not parse") {
+		t.Errorf("Expected broken to accept any value, got %v", findings[1])
+	}
+	warning := findings[2]
+	if warning.Rule != RuleSchemaError || warning.Severity != SeverityWarning || warning.Source != "worldgen/thing.mcdoc:6" {
+		t.Errorf("Expected a schema warning from worldgen/thing.mcdoc:6, got %v", warning)
+	}
+
+	skipped := validator.skippedStatements(filepath.Join(schemaDir, "java", "data", "worldgen", "thing.mcdoc"))
+	if len(skipped) != 1 {
+		t.Errorf("Expected the skipped statement to be recorded, got %v", skipped)
+	}
+}
@@ -1,100 +1,644 @@
-package main
+package mcheck
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
+	"path"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 // PEGMCDocValidator uses the PEG parser for validation
 type PEGMCDocValidator struct {
-	targetVersion Version
-	schemaDir     string
+	targetVersion  Version
+	schemaDir      string
+	edition        string          // schema root below schemaDir, "java" unless set
+	lenient        bool            // accept boolean/number mixups with a warning
+	resourceType   string          // resource type of every file, if set
+	features       map[string]bool // enabled feature flags
+	inclusiveUntil bool            // keep declarations in their #[until] version
+	strictSchema   bool            // fail on values that cannot be fully validated
+	warnIncomplete bool            // report empty and cut off JSON files as warnings
+	schemas        fileSystem      // where schema files are read from
+	extraSchemas   []string        // schema directories of mods, read from disk alongside schemaDir
+	files          fileSystem      // where the files to check are read from
+	timings        *Timings        // records where checking spends its time, if set
+
+	indexMu sync.Mutex
+	index   *SchemaIndex // built from schemaDir on first use
+	cache   schemaCache  // parsed and converted schema files
 }
 
 func NewPEGMCDocValidator(targetVersion Version, schemaDir string) *PEGMCDocValidator {
 	return &PEGMCDocValidator{
 		targetVersion: targetVersion,
 		schemaDir:     schemaDir,
+		edition:       "java",
 	}
 }
 
-func (v *PEGMCDocValidator) ValidateJSON(jsonPath string) error {
-	// Determine the schema file to use
-	schemaPath, err := v.determineSchemaPath(jsonPath)
+// Editions are the game editions whose packs can be validated. Each has its
+// schemas in a directory of the same name in the schema directory.
+var Editions = []string{"java", "bedrock"}
+
+// SetEdition selects the edition whose schemas and pack layout are used
+func (v *PEGMCDocValidator) SetEdition(edition string) error {
+	for _, known := range Editions {
+		if edition == known {
+			v.edition = edition
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown edition %q, expected one of %s", edition, strings.Join(Editions, ", "))
+}
+
+// SetLenient makes validation accept 0 and 1 for booleans and true and false
+// for numbers, as the game does, reporting them as warnings instead of errors
+func (v *PEGMCDocValidator) SetLenient(lenient bool) {
+	v.lenient = lenient
+}
+
+// SetFeatures enables feature flags, like update_1_21, so that content the
+// schemas gate behind them validates. A minecraft: prefix is optional.
+func (v *PEGMCDocValidator) SetFeatures(features []string) {
+	v.features = make(map[string]bool)
+	for _, feature := range features {
+		v.features[strings.TrimPrefix(feature, "minecraft:")] = true
+	}
+}
+
+// SetInclusiveUntil restores the old reading of #[until="1.19"] as the last
+// version with a declaration. vanilla-mcdoc means the first version without
+// it, which is the default.
+func (v *PEGMCDocValidator) SetInclusiveUntil(inclusive bool) {
+	v.inclusiveUntil = inclusive
+}
+
+// SetStrictSchema makes values the schemas cannot fully validate errors
+// instead of notes: values of types that could not be resolved, objects with
+// spreads that could not be resolved, values of dispatchers that are not
+// loaded and files whose schema does not dispatch their resource type
+func (v *PEGMCDocValidator) SetStrictSchema(strict bool) {
+	v.strictSchema = strict
+}
+
+// SetWarnIncomplete reports JSON files that are empty or end in the middle
+// of their document as warnings instead of errors, for bulk imports where
+// some files are expected to be unfinished
+func (v *PEGMCDocValidator) SetWarnIncomplete(warn bool) {
+	v.warnIncomplete = warn
+}
+
+// SetSchemaFS reads the schemas from fsys instead of the schema directory,
+// like schemas embedded in the binary. Paths below the schema directory name
+// the files of fsys relative to it. Call it before validating anything.
+func (v *PEGMCDocValidator) SetSchemaFS(fsys fs.FS) {
+	v.schemas = newFileSystem(v.schemaDir, fsys)
+}
+
+// SetExtraSchemas loads the schemas of mods from dirs, laid out like the
+// schema directory, as if they were part of it: the dispatch keys they
+// declare are known and validated against their cases. Declarations of the
+// schema directory win over theirs. Call it before validating anything.
+func (v *PEGMCDocValidator) SetExtraSchemas(dirs []string) {
+	v.extraSchemas = dirs
+}
+
+// SetFileFS reads the files to check from fsys, naming them by their path
+// below root, instead of from disk. CheckPack reads packs from an fs.FS
+// without it.
+func (v *PEGMCDocValidator) SetFileFS(root string, fsys fs.FS) {
+	v.files = newFileSystem(root, fsys)
+}
+
+// SetTimings records where checking files spends its time in timings, which
+// several validators may share. A nil timings stops recording.
+func (v *PEGMCDocValidator) SetTimings(timings *Timings) {
+	v.timings = timings
+}
+
+// SetResourceType validates every file as a resource of the given type, like
+// worldgen/biome, instead of the type its path within a datapack names
+func (v *PEGMCDocValidator) SetResourceType(resourceType string) {
+	v.resourceType = resourceType
+}
+
+// schemaIndex returns the index of the schema directory, building it the
+// first time it is needed. A directory that cannot be read gives an empty
+// index, leaving the missing schemas to be reported where they are used.
+func (v *PEGMCDocValidator) schemaIndex() *SchemaIndex {
+	v.indexMu.Lock()
+	defer v.indexMu.Unlock()
+	if v.index == nil {
+		index, err := v.buildIndex()
+		if err != nil {
+			index = &SchemaIndex{}
+		}
+		v.index = index
+	}
+	return v.index
+}
+
+// buildIndex indexes the schema directory, with the declarations of the
+// extra schemas merged in
+func (v *PEGMCDocValidator) buildIndex() (*SchemaIndex, error) {
+	index, err := buildSchemaIndex(v.schemas, v.schemaDir)
 	if err != nil {
-		return fmt.Errorf("failed to determine schema path: %w", err)
+		return nil, err
 	}
+	for _, dir := range v.extraSchemas {
+		extra, err := buildSchemaIndex(osFiles, dir)
+		if err != nil {
+			return nil, err
+		}
+		index.merge(extra)
+	}
+	return index, nil
+}
 
-	// Check if schema file exists
-	if _, err := os.Stat(schemaPath); os.IsNotExist(err) {
-		return fmt.Errorf("schema file not found: %s", schemaPath)
+// schemaDirs returns the schema directory followed by the extra schemas,
+// whose files are watched for edits
+func (v *PEGMCDocValidator) schemaDirs() []string {
+	return append([]string{v.schemaDir}, v.extraSchemas...)
+}
+
+// schemaRoot returns the directory a schema file belongs to, the schema
+// directory or one of the extra schemas, and the files to read it from
+func (v *PEGMCDocValidator) schemaRoot(schemaPath string) (string, fileSystem) {
+	if rel, err := filepath.Rel(v.schemaDir, schemaPath); err == nil && !strings.HasPrefix(rel, "..") {
+		return v.schemaDir, v.schemas
+	}
+	for _, dir := range v.extraSchemas {
+		if rel, err := filepath.Rel(dir, schemaPath); err == nil && !strings.HasPrefix(rel, "..") {
+			return dir, osFiles
+		}
 	}
+	return v.schemaDir, v.schemas
+}
 
-	// Validating JSON against schema
+// SchemasPredate reports whether the schemas were written before the target
+// version, so that what it added goes unchecked, and returns the version
+// they were written for
+func (v *PEGMCDocValidator) SchemasPredate() (Version, bool) {
+	return v.schemaIndex().Predates(v.edition, v.targetVersion)
+}
 
-	// Parse the mcdoc schema using our PEG parser
-	statements, _, err := v.parseSchemaWithPEG(schemaPath)
+// bedrockPackDirs are the top level directories of a bedrock behavior pack
+// that hold JSON resources, which unlike java packs have no data directory
+var bedrockPackDirs = []string{
+	"animation_controllers", "animations", "biomes", "blocks", "dialogue", "entities",
+	"feature_rules", "features", "item_catalog", "items", "loot_tables", "recipes",
+	"spawn_rules", "trading", "volumes",
+}
+
+func (v *PEGMCDocValidator) ValidateJSON(jsonPath string) error {
+	return validationError(v.validateJSON(v.files, jsonPath))
+}
+
+// validationError returns the error of validating a file: the problem that
+// kept it from being validated, or the findings that failed it
+func validationError(findings []Finding, err error) error {
 	if err != nil {
-		return fmt.Errorf("failed to parse schema with PEG: %w", err)
+		return err
+	}
+	if err := findingsError(findings); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
 	}
+	return nil
+}
 
-	// Schema parsed successfully
+// validateJSON validates a JSON file read from files, returning the findings
+// about its content, or an error if it could not be validated at all
+func (v *PEGMCDocValidator) validateJSON(files fileSystem, jsonPath string) ([]Finding, error) {
+	// Parse and convert the schema for this file
+	converter, mainValidator, err := v.loadSchemaFor(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Huge files are validated while they are read
+	if info, err := files.Stat(jsonPath); err == nil && info.Size() > streamingSize {
+		return v.streamJSON(files, jsonPath, converter, mainValidator)
+	}
 
 	// Read and parse the JSON file
-	jsonContent, err := os.ReadFile(jsonPath)
+	jsonContent, err := files.ReadFile(jsonPath)
 	if err != nil {
-		return fmt.Errorf("failed to read JSON file: %w", err)
+		return nil, RuleError{RuleUnreadableFile, fmt.Errorf("failed to read JSON file: %w", err)}
 	}
 
-	var jsonData map[string]interface{}
-	if err := json.Unmarshal(jsonContent, &jsonData); err != nil {
-		return fmt.Errorf("failed to parse JSON: %w", err)
+	_, findings, err := v.validateDecoded(files.done(), jsonPath, converter, mainValidator, jsonContent)
+	return findings, err
+}
+
+// validateContent validates JSON content as the file jsonPath, whose path
+// within its pack determines the resource type. It returns the decoded
+// document along with the findings about it. Validation stops early once
+// done is closed, leaving the findings incomplete.
+func (v *PEGMCDocValidator) validateContent(done <-chan struct{}, jsonPath string, content []byte) (interface{}, []Finding, error) {
+	converter, mainValidator, err := v.loadSchemaFor(jsonPath)
+	if err != nil {
+		return nil, nil, err
 	}
+	return v.validateDecoded(done, jsonPath, converter, mainValidator, content)
+}
 
-	// Convert parsed statements to proper validators
-	converter := NewSchemaConverter(v.targetVersion, statements)
-	validatorMap, err := converter.ConvertToValidators()
+func (v *PEGMCDocValidator) validateDecoded(done <-chan struct{}, jsonPath string, converter *SchemaConverter, mainValidator Validator, content []byte) (interface{}, []Finding, error) {
+	resourceType, _ := v.determineResourceType(jsonPath)
+	v.timings.addFile(resourceType)
+	start := time.Now()
+	jsonData, err := decodeJSON(content)
+	v.timings.add(resourceType, phaseDecode, time.Since(start))
 	if err != nil {
-		return fmt.Errorf("failed to convert statements to validators: %w", err)
+		return nil, nil, jsonError(err)
 	}
 
-	// Create validation context
-	ctx := &ValidationContext{
-		Version:     v.targetVersion,
-		Path:        []string{},
-		Definitions: validatorMap,
+	// Resource types only exist in some versions, like enchantments since 1.21
+	ctx := v.newContext(converter)
+	ctx.Done = done
+	if bounded, ok := mainValidator.(*AttributedValidator); ok && !bounded.AppliesForVersion(ctx) {
+		return nil, nil, RuleError{RuleUnsupportedResource, unsupportedVersionError(resourceType, bounded.BaseValidator, ctx)}
+	}
+
+	// Perform actual JSON validation against the parsed schema
+	start = time.Now()
+	findings := mainValidator.Validate(jsonData, ctx)
+	v.timings.add(resourceType, phaseValidate, time.Since(start))
+	return jsonData, append(findings, converter.Warnings()...), nil
+}
+
+// nbtSchemas maps the resource types of NBT files to the schema file under
+// java/ and the name of the struct describing them
+var nbtSchemas = map[string]struct{ schema, name string }{
+	"structure":  {"world/structure", "StructureNBT"},
+	"structures": {"world/structure", "StructureNBT"},
+}
+
+// ValidateFile validates a datapack file, choosing JSON or NBT validation by
+// its extension
+func (v *PEGMCDocValidator) ValidateFile(path string) error {
+	return validationError(v.validateFile(v.files, path))
+}
+
+// streamJSON validates a JSON file like validateJSON without decoding it
+// in full, for files too large to hold in memory as a decoded tree
+func (v *PEGMCDocValidator) streamJSON(files fileSystem, jsonPath string, converter *SchemaConverter, mainValidator Validator) ([]Finding, error) {
+	ctx := v.newContext(converter)
+	ctx.Done = files.done()
+	resourceType, _ := v.determineResourceType(jsonPath)
+	if bounded, ok := mainValidator.(*AttributedValidator); ok && !bounded.AppliesForVersion(ctx) {
+		return nil, RuleError{RuleUnsupportedResource, unsupportedVersionError(resourceType, bounded.BaseValidator, ctx)}
+	}
+
+	// Streamed files are decoded as they are validated
+	v.timings.addFile(resourceType)
+	start := time.Now()
+	defer func() { v.timings.add(resourceType, phaseValidate, time.Since(start)) }()
+	findings, err := streamFile(files, jsonPath, mainValidator, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(findings, converter.Warnings()...), nil
+}
+
+// validateFile validates a file read from files, returning the findings
+// about its content, or an error if it could not be validated at all
+func (v *PEGMCDocValidator) validateFile(files fileSystem, path string) ([]Finding, error) {
+	if strings.EqualFold(filepath.Ext(path), ".nbt") {
+		return v.validateNBT(files, path)
+	}
+	return v.validateJSON(files, path)
+}
+
+// CheckFile validates a file against its schema and, if it passes, runs the
+// semantic checks for its resource type. Findings are reported for name.
+func (v *PEGMCDocValidator) CheckFile(path, name string) []Finding {
+	findings, _ := v.checkFile(context.Background(), path, name, nil)
+	return findings
+}
+
+// CheckFileContext checks a file like CheckFile, unless ctx is done before
+// the check ends: reading and validating the file then stop, and the error
+// of ctx is returned instead of findings. A schema being loaded is loaded in full,
+// since it is kept for the files checked after it.
+func (v *PEGMCDocValidator) CheckFileContext(ctx context.Context, path, name string) ([]Finding, error) {
+	return v.checkFile(ctx, path, name, nil)
+}
+
+// checkFile checks a file like CheckFileContext. Files checked as part of a
+// pack are read from the pack, which is handed to the registered semantic
+// checks.
+func (v *PEGMCDocValidator) checkFile(ctx context.Context, path, name string, pack *Pack) ([]Finding, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	files := v.files
+	if pack != nil {
+		files = pack.files
+	}
+	findings := v.checkFileIn(files.withContext(ctx), path, name, pack)
+	// Reads failing once ctx is done are no problem with the file
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// checkFileIn checks a file read from files
+func (v *PEGMCDocValidator) checkFileIn(files fileSystem, path, name string, pack *Pack) []Finding {
+	// Files small enough to decode are read once for both kinds of checks
+	if !strings.EqualFold(filepath.Ext(path), ".nbt") {
+		if info, err := files.Stat(path); err == nil && info.Size() <= streamingSize {
+			if content, err := files.ReadFile(path); err == nil {
+				return v.checkDocument(files.done(), path, name, content, pack)
+			}
+		}
+	}
+
+	findings, err := v.validateFile(files, path)
+	findings = v.problemFindings(name, findings, err)
+	if err != nil || hasErrors(findings) {
+		return findings
+	}
+	// JSON files this large were validated while streaming, and are not
+	// decoded in full for the checks that need their value either
+	if value, ok := readValue(files, path); ok {
+		findings = append(findings, v.semanticFindings(path, name, value, pack)...)
+	} else if !strings.EqualFold(filepath.Ext(path), ".nbt") {
+		findings = append(findings, streamedFinding(name, pack != nil)...)
+	}
+	return findings
+}
+
+// streamedFinding warns that a JSON file too large to decode was only
+// validated against its schema, naming the checks that were skipped
+func streamedFinding(name string, inPack bool) []Finding {
+	findings := warnf(&ValidationContext{}, RulePartiallyValidated, "file is too large to decode, so only its schema was checked and its semantic checks were skipped")
+	if inPack {
+		findings = warnf(&ValidationContext{}, RulePartiallyValidated, "file is too large to decode, so only its schema was checked and its semantic, reference, tag and objective checks were skipped")
+	}
+	findings[0].File = name
+	return findings
+}
+
+// CheckDocument checks JSON content like CheckFile, for documents that are
+// not read from disk, like the unsaved text of an editor. The path of the
+// document within its pack determines its resource type.
+func (v *PEGMCDocValidator) CheckDocument(path, name string, content []byte) []Finding {
+	return v.checkDocument(nil, path, name, content, nil)
+}
+
+// CheckDocumentContext checks JSON content like CheckDocument, returning the
+// error of ctx instead of findings as soon as it is done, like when the
+// client of a request has gone away. Validation stops at the next value it
+// checks; a schema being loaded is loaded in full in the background, since
+// it is kept for the documents checked after it.
+func (v *PEGMCDocValidator) CheckDocumentContext(ctx context.Context, path, name string, content []byte) ([]Finding, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	checked := make(chan []Finding, 1)
+	go func() {
+		checked <- v.checkDocument(ctx.Done(), path, name, content, nil)
+	}()
+	select {
+	case findings := <-checked:
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return findings, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// checkDocument checks JSON content, giving up once done is closed
+func (v *PEGMCDocValidator) checkDocument(done <-chan struct{}, path, name string, content []byte, pack *Pack) []Finding {
+	value, findings, err := v.validateContent(done, path, content)
+	findings = v.problemFindings(name, findings, err)
+	if err != nil || hasErrors(findings) {
+		return findings
+	}
+	findings = append(findings, v.semanticFindings(path, name, value, pack)...)
+	return findings
+}
+
+// problemFindings reports the result of validating a file for name: the
+// problem that kept it from being validated, or the findings about it
+func (v *PEGMCDocValidator) problemFindings(name string, findings []Finding, err error) []Finding {
+	if err != nil {
+		finding := findingFromError(name, err)
+		if v.warnIncomplete && finding.Rule == RuleIncompleteJSON {
+			finding.Severity = SeverityWarning
+		}
+		return []Finding{finding}
+	}
+	for i := range findings {
+		findings[i].File = name
+	}
+	return findings
+}
+
+// semanticFindings runs the semantic checks for the resource type of a file
+// that passed schema validation, then the registered semantic checks
+func (v *PEGMCDocValidator) semanticFindings(path, name string, value interface{}, pack *Pack) []Finding {
+	resourceType, err := v.determineResourceType(path)
+	if err != nil {
+		return nil
+	}
+	start := time.Now()
+	defer func() { v.timings.add(resourceType, phaseValidate, time.Since(start)) }()
+
+	var checked []Finding
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, check := range fileChecks[resourceType] {
+			checked = append(checked, check(value)...)
+		}
+	case []interface{}:
+		for _, check := range listChecks[resourceType] {
+			checked = append(checked, check(value)...)
+		}
+	}
+	checked = append(checked, v.registeredFindings(path, name, resourceType, value, pack)...)
+	findings := make([]Finding, 0, len(checked))
+	for _, finding := range checked {
+		finding.File = name
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// ValidateNBT validates a binary NBT file, like a structure template, against
+// its mcdoc NBT schema
+func (v *PEGMCDocValidator) ValidateNBT(nbtPath string) error {
+	return validationError(v.validateNBT(v.files, nbtPath))
+}
+
+func (v *PEGMCDocValidator) validateNBT(files fileSystem, nbtPath string) ([]Finding, error) {
+	resourceType, err := v.determineResourceType(nbtPath)
+	if err != nil {
+		return nil, RuleError{RuleUnreadableFile, fmt.Errorf("failed to determine schema path: %w", err)}
+	}
+	// Structures may be nested in folders, so only the first segment matters
+	resourceType, _, _ = strings.Cut(resourceType, "/")
+	nbtSchema, ok := nbtSchemas[resourceType]
+	if !ok {
+		return nil, RuleError{RuleSchemaNotFound, fmt.Errorf("no NBT schema for %s files", resourceType)}
+	}
+
+	schemaPath := filepath.Join(append([]string{v.schemaDir, v.edition}, strings.Split(nbtSchema.schema, "/")...)...) + ".mcdoc"
+	if _, err := v.schemas.Stat(schemaPath); errors.Is(err, fs.ErrNotExist) {
+		return nil, RuleError{RuleSchemaNotFound, fmt.Errorf("schema file not found: %s", schemaPath)}
+	}
+
+	start := time.Now()
+	converter, err := v.convertedSchema(schemaPath)
+	v.timings.addSchema(resourceType, time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+	mainValidator, ok := converter.definitions[nbtSchema.name]
+	if !ok {
+		return nil, RuleError{RuleSchemaError, fmt.Errorf("schema %s does not define %s", schemaPath, nbtSchema.name)}
+	}
+
+	file, err := files.Open(nbtPath)
+	if err != nil {
+		return nil, RuleError{RuleUnreadableFile, fmt.Errorf("failed to read NBT file: %w", err)}
+	}
+	defer file.Close()
+
+	v.timings.addFile(resourceType)
+	start = time.Now()
+	_, data, err := ReadNBT(file)
+	v.timings.add(resourceType, phaseDecode, time.Since(start))
+	if err != nil {
+		return nil, RuleError{RuleInvalidNBT, fmt.Errorf("failed to parse NBT: %w", err)}
+	}
+
+	ctx := v.newContext(converter)
+	ctx.NBT = true
+	start = time.Now()
+	findings := mainValidator.Validate(data, ctx)
+	v.timings.add(resourceType, phaseValidate, time.Since(start))
+	return append(findings, converter.Warnings()...), nil
+}
+
+// unsupportedVersionError explains why a resource type does not exist in the
+// target version or needs a feature flag
+func unsupportedVersionError(resourceType string, bounds BaseValidator, ctx *ValidationContext) error {
+	target := ctx.Version
+	if (BaseValidator{Since: bounds.Since, Until: bounds.Until}).AppliesForVersion(ctx) {
+		return fmt.Errorf("%s files need the %s feature flag, enable it in pack.mcmeta and with --features %s", resourceType, bounds.Feature, bounds.Feature)
+	}
+	if bounds.Since != "" {
+		if since, err := ParseVersion(bounds.Since); err == nil && target.Compare(since) < 0 {
+			return fmt.Errorf("%s files require Minecraft %s or later, target is %s", resourceType, bounds.Since, target)
+		}
+	}
+	if ctx.InclusiveUntil {
+		return fmt.Errorf("%s files were removed after Minecraft %s, target is %s", resourceType, bounds.Until, target)
+	}
+	return fmt.Errorf("%s files were removed in Minecraft %s, target is %s", resourceType, bounds.Until, target)
+}
+
+// loadSchemaFor parses and converts the schema for a JSON file, returning the
+// converter and the validator for the file's resource type
+func (v *PEGMCDocValidator) loadSchemaFor(jsonPath string) (*SchemaConverter, Validator, error) {
+	// Determine the schema file to use
+	schemaPath, err := v.determineSchemaPath(jsonPath)
+	if err != nil {
+		return nil, nil, RuleError{RuleUnreadableFile, fmt.Errorf("failed to determine schema path: %w", err)}
+	}
+
+	// Check if schema file exists
+	if _, err := v.schemas.Stat(schemaPath); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, RuleError{RuleSchemaNotFound, fmt.Errorf("schema file not found: %s", schemaPath)}
+	}
+
+	// Parse and convert the schema, or reuse it from an earlier file
+	resourceType, _ := v.determineResourceType(jsonPath)
+	start := time.Now()
+	converter, err := v.convertedSchema(schemaPath)
+	v.timings.addSchema(resourceType, time.Since(start))
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Find the main validator
-	mainValidator := converter.GetMainValidator()
+	mainValidator := converter.MainValidatorFor(resourceType)
+	if _, declared := converter.Dispatches()["minecraft:resource"][resourceType]; v.strictSchema && !declared {
+		// Other types are a guess at the type of the file
+		return nil, nil, RuleError{RuleSchemaError, fmt.Errorf("%s declares no type for %s files", v.schemaName(schemaPath), resourceType)}
+	}
 	if mainValidator == nil {
 		// If no specific main validator found, create a basic struct validator
 		mainValidator = converter.CreateBasicStructValidator()
 	}
-
-	// Perform actual JSON validation against the parsed schema
-	if err := mainValidator.Validate(jsonData, ctx); err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+	if since, ok := resourceTypeSince[resourceType]; ok {
+		bounds, inner := BaseValidator{}, mainValidator
+		if bounded, ok := mainValidator.(*AttributedValidator); ok {
+			bounds, inner = bounded.BaseValidator, bounded.InnerValidator
+		}
+		if bounds.Since == "" {
+			bounds.Since = since
+			mainValidator = &AttributedValidator{BaseValidator: bounds, InnerValidator: inner}
+		}
 	}
 
-	return nil
+	return converter, mainValidator, nil
+}
+
+// resourceTypeSince are the versions that added resource types whose
+// schemas do not bound them with #[since], so that their files are still
+// rejected for older versions
+var resourceTypeSince = map[string]string{
+	"worldgen/world_preset":                "1.19",
+	"worldgen/flat_level_generator_preset": "1.19",
+}
+
+// newContext creates a validation context for the converted schema
+func (v *PEGMCDocValidator) newContext(converter *SchemaConverter) *ValidationContext {
+	return &ValidationContext{
+		Version:     v.targetVersion,
+		Path:        []string{},
+		Definitions: converter.definitions,
+		Dispatches:  converter.Dispatches(),
+		Aliases:     converter.Aliases(),
+		Lenient:     v.lenient,
+		Features:    v.features,
+
+		StrictSchema:   v.strictSchema,
+		InclusiveUntil: v.inclusiveUntil,
+		ExtraSchemas:   len(v.extraSchemas) > 0,
+	}
 }
 
 func (v *PEGMCDocValidator) parseSchemaWithPEG(schemaPath string) ([]Statement, map[string]Validator, error) {
 	// Read the schema file
-	content, err := os.ReadFile(schemaPath)
+	_, schemas := v.schemaRoot(schemaPath)
+	content, err := schemas.ReadFile(schemaPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to read schema file: %w", err)
 	}
+	source, err := mcdocText(content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read schema file %s: %w", v.schemaName(schemaPath), err)
+	}
 
 	// Create PEG parser
 	parser := &MCDocParser{
-		Buffer: string(content),
+		Buffer: source,
 		Pretty: true,
 	}
+	parser.File = v.schemaName(schemaPath)
 
 	// Initialize parser
 	err = parser.Init()
@@ -104,54 +648,128 @@ func (v *PEGMCDocValidator) parseSchemaWithPEG(schemaPath string) ([]Statement,
 
 	// Parse the content
 	err = parser.Parse()
+	var skipped []skippedStatement
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse mcdoc: %w", err)
+		// One bad statement should not cost the whole schema: parse the
+		// rest without the statements that do not parse, if that works
+		var recovered string
+		recovered, skipped = recoverStatements(source, parser.File, schemaPath)
+		retry := &MCDocParser{Buffer: recovered, Pretty: true}
+		retry.File = parser.File
+		if len(skipped) == 0 || retry.Init() != nil || retry.Parse() != nil {
+			return nil, nil, fmt.Errorf("failed to parse mcdoc: %w", describeParseError(parser, err, schemaPath))
+		}
+		parser = retry
 	}
 
-	// Execute actions to build statements
-	parser.Execute()
+	// Walk the syntax tree to build statements
+	parser.BuildStatements()
 
 	// Return the parsed statements and definitions
+	if len(skipped) > 0 {
+		return parser.Statements, parser.GetDefinitions(), &skippedStatementsError{skipped}
+	}
 	return parser.Statements, parser.GetDefinitions(), nil
 }
 
-func (v *PEGMCDocValidator) findMainValidator(statements []Statement, definitions map[string]Validator) Validator {
-	// Look for dispatch statements first (they define the main entry point)
-	for _, stmt := range statements {
-		if dispatchStmt, ok := stmt.(DispatchStatement); ok {
-			return dispatchStmt.Validator
+// schemaName names a schema file in messages, relative to the data directory
+// of the edition like worldgen/biome.mcdoc, or else to the schema directory
+// like java/util/text.mcdoc
+func (v *PEGMCDocValidator) schemaName(schemaPath string) string {
+	for _, dir := range []string{filepath.Join(v.schemaDir, v.edition, "data"), v.schemaDir} {
+		if rel, err := filepath.Rel(dir, schemaPath); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
 		}
 	}
+	return filepath.ToSlash(schemaPath)
+}
 
-	// If no dispatch statement, look for struct definitions
-	for _, stmt := range statements {
-		if structStmt, ok := stmt.(StructStatement); ok {
-			return structStmt.Validator
-		}
+func (v *PEGMCDocValidator) findMainValidator(statements []Statement, definitions map[string]Validator) Validator {
+	converter := NewSchemaConverter(v.targetVersion, statements)
+	if _, err := converter.ConvertToValidators(); err != nil {
+		return nil
+	}
+	return converter.GetMainValidator()
+}
+
+func (v *PEGMCDocValidator) determineSchemaPath(jsonPath string) (string, error) {
+	resourceType, err := v.determineResourceType(jsonPath)
+	if err != nil {
+		return "", err
 	}
+	return v.SchemaPath(resourceType)
+}
 
-	// If no struct statement, look for type aliases
-	for _, stmt := range statements {
-		if aliasStmt, ok := stmt.(TypeAliasStatement); ok {
-			return aliasStmt.Validator
-		}
+// SchemaPath returns the schema file describing a resource type, like
+// worldgen/noise_settings
+func (v *PEGMCDocValidator) SchemaPath(resourceType string) (string, error) {
+	// Build the schema path: vanilla-mcdoc/java/data/worldgen/noise_settings.mcdoc
+	module := append([]string{v.edition, "data"}, strings.Split(resourceType, "/")...)
+	if schemaPath := v.schemaIndex().ModuleFile(module); schemaPath != "" {
+		return schemaPath, nil
 	}
 
-	// Return nil if no suitable validator found
-	return nil
+	// Several small registries share a schema file named after their
+	// family, like trim.mcdoc for trim_pattern and trim_material
+	if found := v.schemaIndex().DispatchFile("minecraft:resource", resourceType); found != "" {
+		return found, nil
+	}
+
+	return filepath.Join(append([]string{v.schemaDir}, module...)...) + ".mcdoc", nil
 }
 
-func (v *PEGMCDocValidator) determineSchemaPath(jsonPath string) (string, error) {
+// ResourceTypes lists the resource types the schemas of the edition
+// dispatch to, in order
+func (v *PEGMCDocValidator) ResourceTypes() []string {
+	editionDir := filepath.Join(v.schemaDir, v.edition) + string(filepath.Separator)
+	var types []string
+	for resourceType, file := range v.schemaIndex().Dispatches["minecraft:resource"] {
+		if strings.HasPrefix(file, editionDir) && !strings.HasPrefix(resourceType, "%") {
+			types = append(types, resourceType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// determineResourceType returns the resource type of a datapack file, like
+// "worldgen/noise_settings" for data/<namespace>/worldgen/noise_settings/foo.json
+func (v *PEGMCDocValidator) determineResourceType(jsonPath string) (string, error) {
+	if v.resourceType != "" {
+		return v.resourceType, nil
+	}
+
 	// Extract the relative path from the datapack structure
 	// Expected structure: data/(optional namespace)/type/subtype/file.json
-	parts := strings.Split(filepath.Clean(jsonPath), string(os.PathSeparator))
+	parts := pathParts(jsonPath)
+
+	// Bedrock behavior packs keep each resource type in a top level
+	// directory, so the type is the first known directory in the path
+	if v.edition == "bedrock" {
+		for _, part := range parts[:len(parts)-1] {
+			for _, dir := range bedrockPackDirs {
+				if strings.EqualFold(part, dir) {
+					return dir, nil
+				}
+			}
+		}
+		return "", fmt.Errorf("invalid behavior pack structure: %s", jsonPath)
+	}
 
-	// Find the "data" directory and extract the type path
-	dataIndex := -1
+	// Find the "data" directory and extract the type path. Below it come a
+	// namespace, a type and a file. Windows does not tell Data from data, so
+	// neither does the search, but a directory named exactly data wins over
+	// a Data higher up, like the one in C:\Users\alex\Data\mypack\data.
+	candidates := parts[:max(len(parts)-3, 0)]
+	dataIndex := slices.Index(candidates, "data")
+	for i, part := range candidates {
+		if dataIndex == -1 && strings.EqualFold(part, "data") {
+			dataIndex = i
+		}
+	}
 	for i, part := range parts {
-		if part == "data" {
+		if dataIndex == -1 && strings.EqualFold(part, "data") {
 			dataIndex = i
-			break
 		}
 	}
 
@@ -195,9 +813,14 @@ func (v *PEGMCDocValidator) determineSchemaPath(jsonPath string) (string, error)
 		return "", fmt.Errorf("invalid datapack structure: %s", jsonPath)
 	}
 
-	// Build the schema path: vanilla-mcdoc/java/data/worldgen/noise_settings.mcdoc
-	schemaPathParts := append([]string{v.schemaDir, "java", "data"}, typePath...)
-	schemaPath := strings.Join(schemaPathParts, string(os.PathSeparator)) + ".mcdoc"
+	return strings.Join(typePath, "/"), nil
+}
 
-	return schemaPath, nil
-}
\ No newline at end of file
+// pathParts splits a file path into its directories and file name.
+// Backslashes separate parts on every platform, so Windows paths are read
+// alike wherever mcheck runs; resource locations cannot contain them. The
+// volume of a path, like the C: of C:\packs\data, stays its first part.
+func pathParts(file string) []string {
+	file = path.Clean(strings.ReplaceAll(file, "\\", "/"))
+	return strings.Split(strings.TrimPrefix(file, "/"), "/")
+}
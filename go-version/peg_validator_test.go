@@ -1,13 +1,15 @@
-package main
+package mcheck
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
 func TestPEGValidatorBasic(t *testing.T) {
 	// Create a test version
-	version, err := parseVersion("1.20.1")
+	version, err := ParseVersion("1.20.1")
 	if err != nil {
 		t.Fatalf("Failed to parse version: %v", err)
 	}
@@ -46,7 +48,7 @@ func TestPEGValidatorBasic(t *testing.T) {
 }
 
 func TestPEGValidatorFindMainValidator(t *testing.T) {
-	version, err := parseVersion("1.20.1")
+	version, err := ParseVersion("1.20.1")
 	if err != nil {
 		t.Fatalf("Failed to parse version: %v", err)
 	}
@@ -75,7 +77,7 @@ func TestPEGValidatorJSONValidation(t *testing.T) {
 		t.Skip("Schema file not found")
 	}
 
-	version, err := parseVersion("1.20.1")
+	version, err := ParseVersion("1.20.1")
 	if err != nil {
 		t.Fatalf("Failed to parse version: %v", err)
 	}
@@ -87,4 +89,112 @@ func TestPEGValidatorJSONValidation(t *testing.T) {
 	
 	// For now, just check it doesn't panic - we'll improve validation next
 	t.Logf("Validation result: %v", err)
-}
\ No newline at end of file
+}
+func TestPEGValidatorEditions(t *testing.T) {
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, "schemas")
+	if err := validator.SetEdition("legacy"); err == nil {
+		t.Error("Expected an error for an unknown edition")
+	}
+	if err := validator.SetEdition("bedrock"); err != nil {
+		t.Fatalf("Failed to select bedrock: %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		schema string
+	}{
+		{"pack/entities/zombie.json", filepath.Join("schemas", "bedrock", "data", "entities.mcdoc")},
+		{"pack/loot_tables/entities/zombie.json", filepath.Join("schemas", "bedrock", "data", "loot_tables.mcdoc")},
+		{"pack/data/minecraft/damage_type/fall.json", ""},
+	}
+	for _, tt := range tests {
+		schema, err := validator.determineSchemaPath(filepath.FromSlash(tt.path))
+		switch {
+		case tt.schema == "" && err == nil:
+			t.Errorf("Expected no schema for %s, got %s", tt.path, schema)
+		case tt.schema != "" && schema != tt.schema:
+			t.Errorf("Expected schema %s for %s, got %s (%v)", tt.schema, tt.path, schema, err)
+		}
+	}
+
+	// Bedrock packs are validated with the schemas in <schema-dir>/bedrock
+	root := t.TempDir()
+	schemaPath := filepath.Join(root, "schemas", "bedrock", "data", "items.mcdoc")
+	jsonPath := filepath.Join(root, "pack", "items", "ruby.json")
+	for path, content := range map[string]string{
+		schemaPath: "dispatch minecraft:resource[items] to struct Item {\n\tformat_version: string,\n}\n",
+		jsonPath:   `{"format_version": 1}`,
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	validator = NewPEGMCDocValidator(Version{1, 20, 1}, filepath.Join(root, "schemas"))
+	validator.SetEdition("bedrock")
+	err := validator.ValidateJSON(jsonPath)
+	if err == nil || !strings.Contains(err.Error(), "at format_version: expected string") {
+		t.Errorf("Expected format_version error, got %v", err)
+	}
+}
+
+func TestPEGValidatorResourceTypes(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t, "trim", "damage_type", "worldgen/biome")
+	targetVersion, _ := ParseVersion("1.20.1")
+	validator := NewPEGMCDocValidator(targetVersion, schemaDir)
+
+	expected := []string{"damage_type", "trim_material", "trim_pattern", "worldgen/biome"}
+	if types := validator.ResourceTypes(); strings.Join(types, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected resource types %v, got %v", expected, types)
+	}
+
+	for resourceType, file := range map[string]string{
+		"trim_pattern":   "trim.mcdoc",
+		"worldgen/biome": filepath.Join("worldgen", "biome.mcdoc"),
+		"recipe":         "recipe.mcdoc",
+	} {
+		schemaPath, err := validator.SchemaPath(resourceType)
+		if err != nil || schemaPath != filepath.Join(schemaDir, "java", "data", file) {
+			t.Errorf("Expected %s to be described by %s, got %s (%v)", resourceType, file, schemaPath, err)
+		}
+	}
+}
+
+func TestPEGValidatorWindowsPaths(t *testing.T) {
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, "schemas")
+
+	tests := []struct {
+		path         string
+		resourceType string // empty for an invalid path
+	}{
+		{`C:\packs\mypack\data\minecraft\worldgen\biome\plains.json`, "worldgen/biome"},
+		{`c:\packs\mypack\Data\mypack\damage_type\acid.json`, "damage_type"},
+		{`D:/packs/mypack/DATA/mypack/loot_table/chests/loot.json`, "loot_table/chests"},
+		{`\\server\share\pack\data\ns\recipe\bread.json`, "recipe"},
+		{`pack\data\ns\worldgen\..\recipe\bread.json`, "recipe"},
+		{`C:\packs\mypack\database\ns\recipe\bread.json`, ""},
+		{`C:\data\bread.json`, ""},
+		{`/Users/alex/Data/mypack/data/minecraft/worldgen/biome/plains.json`, "worldgen/biome"},
+	}
+	for _, tt := range tests {
+		resourceType, err := validator.determineResourceType(tt.path)
+		switch {
+		case tt.resourceType == "" && err == nil:
+			t.Errorf("Expected %s to be invalid, got %s", tt.path, resourceType)
+		case tt.resourceType != "" && resourceType != tt.resourceType:
+			t.Errorf("Expected resource type %s for %s, got %s (%v)", tt.resourceType, tt.path, resourceType, err)
+		}
+	}
+
+	schemaPath, err := validator.determineSchemaPath(`C:\packs\mypack\data\minecraft\worldgen\biome\plains.json`)
+	if expected := filepath.Join("schemas", "java", "data", "worldgen", "biome.mcdoc"); err != nil || schemaPath != expected {
+		t.Errorf("Expected schema %s, got %s (%v)", expected, schemaPath, err)
+	}
+
+	validator.SetEdition("bedrock")
+	if resourceType, err := validator.determineResourceType(`C:\packs\behavior\Entities\zombie.json`); err != nil || resourceType != "entities" {
+		t.Errorf("Expected entities, got %s (%v)", resourceType, err)
+	}
+}
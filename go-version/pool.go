@@ -0,0 +1,54 @@
+package mcheck
+
+import (
+	"sort"
+	"sync"
+)
+
+// Validating an object needs a set of the fields it matched and its sorted
+// keys, and validating a union collects the errors of its alternatives.
+// These buffers are pooled since a directory run validates millions of
+// values and would otherwise spend much of its time collecting them.
+var (
+	fieldSetPool = sync.Pool{New: func() interface{} { return make(map[string]bool) }}
+	stringsPool  = sync.Pool{New: func() interface{} { return new([]string) }}
+)
+
+// maxPooledSize bounds the buffers returned to the pools, so that one huge
+// object does not keep its buffers alive for the rest of the run
+const maxPooledSize = 1024
+
+func getFieldSet() map[string]bool {
+	return fieldSetPool.Get().(map[string]bool)
+}
+
+func putFieldSet(set map[string]bool) {
+	if len(set) > maxPooledSize {
+		return
+	}
+	clear(set)
+	fieldSetPool.Put(set)
+}
+
+func getStrings() *[]string {
+	return stringsPool.Get().(*[]string)
+}
+
+func putStrings(buffer *[]string) {
+	if cap(*buffer) > maxPooledSize {
+		return
+	}
+	*buffer = (*buffer)[:0]
+	stringsPool.Put(buffer)
+}
+
+// sortedKeys fills buffer with the keys of obj in order
+func sortedKeys(obj map[string]interface{}, buffer *[]string) []string {
+	names := (*buffer)[:0]
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	*buffer = names
+	return names
+}
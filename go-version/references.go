@@ -0,0 +1,413 @@
+package mcheck
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Reference is a reference from a pack file to another resource in the pack
+type Reference struct {
+	File        string   // file containing the reference
+	Path        []string // path to the reference inside the file
+	ID          string   // referenced resource id, with namespace
+	Types       []string // resource types the id may resolve to
+	Description string   // how to name the reference in messages
+
+	// Vanilla lists the vanilla resources the id may also name, with the
+	// version that added them, for references that need not resolve
+	// within the pack
+	Vanilla map[string]Version
+}
+
+// referenceExtractors find the references between files of a pack. They only
+// return references that must resolve within the pack; references into
+// namespaces the pack does not define usually point at vanilla resources.
+var referenceExtractors = []func(pack *Pack) []Reference{
+	templatePoolReferences,
+	structureSetReferences,
+	functionTagReferences,
+	recipeTagReferences,
+	functionCommandReferences,
+	biomeFeatureReferences,
+	placedFeatureReferences,
+}
+
+// References returns every reference between the files of the pack
+func (p *Pack) References() []Reference {
+	var references []Reference
+	for _, extract := range referenceExtractors {
+		references = append(references, extract(p)...)
+	}
+	return references
+}
+
+// Resolve returns the file a reference points to
+func (p *Pack) Resolve(reference Reference) (string, bool) {
+	return p.Lookup(reference.ID, reference.Types...)
+}
+
+// checksJSONReference reports whether a reference to a JSON resource must
+// resolve: one in a namespace the pack defines, or one in the minecraft
+// namespace when the vanilla datapack is known. Vanilla data has no
+// structure files, so references to them only use Defines.
+func (p *Pack) checksJSONReference(id string) bool {
+	return p.Defines(id) || p.Vanilla != nil && strings.HasPrefix(normalizeID(id), "minecraft:")
+}
+
+// structureTypes are the directories structure files live in; 1.21 renamed
+// structures to structure
+var structureTypes = []string{"structure", "structures"}
+
+// Function and function tag directories were singularized in 1.21
+var (
+	functionTypes    = []string{"function", "functions"}
+	functionTagTypes = []string{"tags/function", "tags/functions"}
+)
+
+// readJSON decodes a pack file. Files that fail to decode have already
+// been reported by schema validation, so they are skipped.
+func (p *Pack) readJSON(file string) (map[string]interface{}, bool) {
+	value, ok := readValue(p.files, file)
+	object, _ := value.(map[string]interface{})
+	return object, ok && object != nil
+}
+
+// readValue decodes a file that may be any JSON value, like an item
+// modifier that is a list of functions. Files above streamingSize are
+// validated while streaming and never decoded in full, so they are skipped
+// like files that fail to decode; checkFileIn reports them.
+func readValue(files fileSystem, file string) (interface{}, bool) {
+	if info, err := files.Stat(file); err != nil || info.Size() > streamingSize {
+		return nil, false
+	}
+	data, err := files.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+	value, err := decodeJSON(data)
+	return value, err == nil
+}
+
+// templatePoolReferences finds the fallback pools of template pools and the
+// structure files and processor lists of their elements. A pool naming any
+// of them that is missing crashes structure generation.
+func templatePoolReferences(pack *Pack) []Reference {
+	var references []Reference
+	for _, file := range sortedFiles(pack.Resources["worldgen/template_pool"]) {
+		pool, ok := pack.readJSON(file)
+		if !ok {
+			continue
+		}
+
+		if fallback, ok := pool["fallback"].(string); ok && pack.checksJSONReference(fallback) {
+			references = append(references, Reference{
+				File:        file,
+				Path:        []string{"fallback"},
+				ID:          normalizeID(fallback),
+				Types:       []string{"worldgen/template_pool"},
+				Description: "fallback template pool " + fallback,
+			})
+		}
+
+		elements, _ := pool["elements"].([]interface{})
+		for i, entry := range elements {
+			weighted, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			path := []string{"elements", strconv.Itoa(i), "element"}
+			references = append(references, poolElementReferences(pack, file, path, weighted["element"])...)
+		}
+	}
+	return references
+}
+
+// poolElementReferences finds the structure file and processor list of a
+// single pool element, recursing into the elements of list pool elements.
+// Processor lists given inline rather than by id have nothing to resolve.
+func poolElementReferences(pack *Pack, file string, path []string, value interface{}) []Reference {
+	element, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var references []Reference
+	if location, ok := element["location"].(string); ok && pack.Defines(location) {
+		references = append(references, Reference{
+			File:        file,
+			Path:        append(append([]string{}, path...), "location"),
+			ID:          normalizeID(location),
+			Types:       structureTypes,
+			Description: "structure file for " + location,
+		})
+	}
+	if processors, ok := element["processors"].(string); ok && pack.checksJSONReference(processors) {
+		references = append(references, Reference{
+			File:        file,
+			Path:        append(append([]string{}, path...), "processors"),
+			ID:          normalizeID(processors),
+			Types:       []string{"worldgen/processor_list"},
+			Description: "processor list " + processors,
+		})
+	}
+
+	children, _ := element["elements"].([]interface{})
+	for i, child := range children {
+		childPath := append(append([]string{}, path...), "elements", strconv.Itoa(i))
+		references = append(references, poolElementReferences(pack, file, childPath, child)...)
+	}
+	return references
+}
+
+// structureSetReferences finds the structures placed by structure sets. Their
+// template pools and structure files are found by templatePoolReferences.
+func structureSetReferences(pack *Pack) []Reference {
+	var references []Reference
+	for _, file := range sortedFiles(pack.Resources["worldgen/structure_set"]) {
+		set, ok := pack.readJSON(file)
+		if !ok {
+			continue
+		}
+
+		structures, _ := set["structures"].([]interface{})
+		for i, entry := range structures {
+			element, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			structure, ok := element["structure"].(string)
+			if !ok || !pack.checksJSONReference(structure) {
+				continue
+			}
+			references = append(references, Reference{
+				File:        file,
+				Path:        []string{"structures", strconv.Itoa(i), "structure"},
+				ID:          normalizeID(structure),
+				Types:       []string{"worldgen/structure", "worldgen/configured_structure_feature"},
+				Description: "structure " + structure,
+			})
+		}
+	}
+	return references
+}
+
+// functionTagReferences finds the functions and function tags named by
+// function tags, including the minecraft:load and minecraft:tick tags run by
+// the game. Vanilla defines no functions, so every entry must resolve within
+// the pack, except optional entries which are skipped.
+func functionTagReferences(pack *Pack) []Reference {
+	var references []Reference
+	for _, tagType := range functionTagTypes {
+		for _, id := range sortedIDs(pack.Resources[tagType]) {
+			file := pack.Resources[tagType][id]
+			tag, ok := pack.readJSON(file)
+			if !ok {
+				continue
+			}
+
+			values, _ := tag["values"].([]interface{})
+			for i, entry := range values {
+				path := []string{"values", strconv.Itoa(i)}
+				entryID, ok := entry.(string)
+				if object, isObject := entry.(map[string]interface{}); isObject {
+					if required, ok := object["required"].(bool); ok && !required {
+						continue
+					}
+					entryID, ok = object["id"].(string)
+					path = append(path, "id")
+				}
+				if !ok {
+					continue
+				}
+
+				reference := Reference{File: file, Path: path}
+				if strings.HasPrefix(entryID, "#") {
+					reference.ID, reference.Types = normalizeID(entryID[1:]), functionTagTypes
+					reference.Description = fmt.Sprintf("function tag %s referenced by #%s", entryID, id)
+				} else {
+					reference.ID, reference.Types = normalizeID(entryID), functionTypes
+					reference.Description = fmt.Sprintf("function %s referenced by #%s", normalizeID(entryID), id)
+				}
+				references = append(references, reference)
+			}
+		}
+	}
+	return references
+}
+
+// biomeFeatureReferences finds the placed features of the generation steps
+// of biomes and their carvers, given by id, by a list of ids or by a tag.
+// Carvers were grouped by carving step before 1.21.2.
+func biomeFeatureReferences(pack *Pack) []Reference {
+	var references []Reference
+	for _, file := range sortedFiles(pack.Resources["worldgen/biome"]) {
+		biome, ok := pack.readJSON(file)
+		if !ok {
+			continue
+		}
+
+		steps, _ := biome["features"].([]interface{})
+		for i, step := range steps {
+			references = append(references, holderSetReferences(pack, file, []string{"features", strconv.Itoa(i)}, step, "worldgen/placed_feature", "placed feature")...)
+		}
+		switch carvers := biome["carvers"].(type) {
+		case map[string]interface{}:
+			var steps []string
+			for _, step := range sortedKeys(carvers, &steps) {
+				references = append(references, holderSetReferences(pack, file, []string{"carvers", step}, carvers[step], "worldgen/configured_carver", "carver")...)
+			}
+		default:
+			references = append(references, holderSetReferences(pack, file, []string{"carvers"}, carvers, "worldgen/configured_carver", "carver")...)
+		}
+	}
+	return references
+}
+
+// placedFeatureReferences finds the configured features placed features
+// place, unless given inline
+func placedFeatureReferences(pack *Pack) []Reference {
+	var references []Reference
+	for _, file := range sortedFiles(pack.Resources["worldgen/placed_feature"]) {
+		placed, ok := pack.readJSON(file)
+		if !ok {
+			continue
+		}
+		if feature, ok := placed["feature"].(string); ok && pack.checksJSONReference(feature) {
+			references = append(references, Reference{
+				File:        file,
+				Path:        []string{"feature"},
+				ID:          normalizeID(feature),
+				Types:       []string{"worldgen/configured_feature"},
+				Description: "configured feature " + feature,
+			})
+		}
+	}
+	return references
+}
+
+// holderSetReferences finds the resources of a registry named by a value
+// that is an id, a list of ids or a #tag of the registry
+func holderSetReferences(pack *Pack, file string, path []string, value interface{}, resourceType, description string) []Reference {
+	var references []Reference
+	add := func(path []string, id string) {
+		reference := Reference{File: file, Path: path}
+		if strings.HasPrefix(id, "#") {
+			reference.ID, reference.Types = normalizeID(id[1:]), []string{"tags/" + resourceType}
+			reference.Description = fmt.Sprintf("%s tag %s", description, id)
+		} else {
+			reference.ID, reference.Types = normalizeID(id), []string{resourceType}
+			reference.Description = fmt.Sprintf("%s %s", description, id)
+		}
+		if pack.checksJSONReference(reference.ID) {
+			references = append(references, reference)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		add(path, v)
+	case []interface{}:
+		for i, entry := range v {
+			if id, ok := entry.(string); ok {
+				add(appendPath(path, strconv.Itoa(i)), id)
+			}
+		}
+	}
+	return references
+}
+
+// itemTagTypes are the directories item tags live in; 1.21 singularized them
+var itemTagTypes = []string{"tags/item", "tags/items"}
+
+// recipeIngredientFields are the fields of recipes holding an ingredient or,
+// for key, a map of them
+var recipeIngredientFields = []string{"ingredient", "ingredients", "key", "base", "addition", "template", "input", "material"}
+
+// recipeTagReferences finds the item tags recipe ingredients use, as
+// {"tag": ...} or, since 1.21.2, "#namespace:path". A missing tag leaves the
+// recipe uncraftable without any error from the game. Tags in the minecraft
+// namespace may also be vanilla tags.
+func recipeTagReferences(pack *Pack) []Reference {
+	var references []Reference
+	for _, recipeType := range []string{"recipe", "recipes"} {
+		for _, file := range sortedFiles(pack.Resources[recipeType]) {
+			recipe, ok := pack.readJSON(file)
+			if !ok {
+				continue
+			}
+			for _, field := range recipeIngredientFields {
+				if field == "key" {
+					key, _ := recipe["key"].(map[string]interface{})
+					var symbols []string
+					for _, symbol := range sortedKeys(key, &symbols) {
+						references = ingredientTagReferences(pack, file, []string{"key", symbol}, key[symbol], references)
+					}
+					continue
+				}
+				references = ingredientTagReferences(pack, file, []string{field}, recipe[field], references)
+			}
+		}
+	}
+	return references
+}
+
+// ingredientTagReferences appends the item tags of an ingredient, which may
+// be a list of alternatives
+func ingredientTagReferences(pack *Pack, file string, path []string, value interface{}, references []Reference) []Reference {
+	var tag string
+	switch ingredient := value.(type) {
+	case []interface{}:
+		for i, alternative := range ingredient {
+			references = ingredientTagReferences(pack, file, appendPath(path, strconv.Itoa(i)), alternative, references)
+		}
+		return references
+	case string:
+		if !strings.HasPrefix(ingredient, "#") {
+			return references
+		}
+		tag = ingredient[1:]
+	case map[string]interface{}:
+		var ok bool
+		if tag, ok = ingredient["tag"].(string); !ok {
+			return references
+		}
+		path = appendPath(path, "tag")
+	default:
+		return references
+	}
+
+	id := normalizeID(tag)
+	if !pack.Defines(id) && !strings.HasPrefix(id, "minecraft:") {
+		return references
+	}
+	return append(references, Reference{
+		File:        file,
+		Path:        path,
+		ID:          id,
+		Types:       itemTagTypes,
+		Description: "item tag #" + id,
+		Vanilla:     vanillaItemTags,
+	})
+}
+
+// sortedIDs returns the resource ids of a resource index in a stable order
+func sortedIDs(resources map[string]string) []string {
+	var ids []string
+	for id := range resources {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// sortedFiles returns the files of a resource index in a stable order
+func sortedFiles(resources map[string]string) []string {
+	var files []string
+	for _, file := range resources {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,233 @@
+package mcheck
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SchemaREPL explores schemas interactively: it loads the schema of a
+// resource type, lists the fields of the type at hand, drills into the types
+// of fields and list elements and checks JSON snippets against them
+type SchemaREPL struct {
+	validator *PEGMCDocValidator
+	out       io.Writer
+	ctx       *ValidationContext // nil until a schema is loaded
+	stack     []replFrame        // the loaded type, then each type drilled into
+}
+
+// replFrame is a type the REPL has drilled into
+type replFrame struct {
+	name      string
+	validator Validator
+}
+
+const replHelp = `commands:
+  load <resource-type>   load the schema of a resource type, like worldgen/biome
+  type <name>            switch to a type declared by the loaded schemas, like NoiseRouter
+  fields                 list the fields of the current type
+  show                   describe the current type and the cases it dispatches to
+  cd <field>             drill into a field; [] drills into list elements, .. goes back up
+  check <json>           validate a JSON snippet against the current type
+  help                   print this help
+  quit                   leave
+`
+
+func NewSchemaREPL(validator *PEGMCDocValidator, out io.Writer) *SchemaREPL {
+	return &SchemaREPL{validator: validator, out: out}
+}
+
+// Run reads commands from in until it ends or quit is entered
+func (r *SchemaREPL) Run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxRequestSize)
+	for {
+		fmt.Fprintf(r.out, "%s> ", r.prompt())
+		if !scanner.Scan() {
+			fmt.Fprintln(r.out)
+			return scanner.Err()
+		}
+		command, arg, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if command == "quit" || command == "exit" {
+			return nil
+		}
+		if err := r.execute(command, strings.TrimSpace(arg)); err != nil {
+			fmt.Fprintf(r.out, "error: %v\n", err)
+		}
+	}
+}
+
+// prompt names the current type by the path drilled into it, like
+// worldgen/biome.effects
+func (r *SchemaREPL) prompt() string {
+	if len(r.stack) == 0 {
+		return "mcheck"
+	}
+	names := make([]string, len(r.stack))
+	for i, frame := range r.stack {
+		names[i] = frame.name
+	}
+	return strings.Join(names, ".")
+}
+
+func (r *SchemaREPL) execute(command, arg string) error {
+	if command == "" {
+		return nil
+	}
+	switch command {
+	case "help":
+		fmt.Fprint(r.out, replHelp)
+		return nil
+	case "load":
+		return r.load(arg)
+	}
+
+	if len(r.stack) == 0 {
+		return fmt.Errorf("no schema loaded, use load <resource-type>")
+	}
+	current := r.stack[len(r.stack)-1].validator
+	switch command {
+	case "type":
+		validator, ok := r.ctx.Definitions[arg]
+		if !ok {
+			return fmt.Errorf("no type %s in the loaded schemas", arg)
+		}
+		r.stack = append(r.stack[:1], replFrame{arg, validator})
+	case "fields":
+		r.printFields(current)
+	case "show":
+		info := &HoverInfo{}
+		fmt.Fprintf(r.out, "type: %s\n", describeTypeIn(current, r.ctx))
+		for _, constraint := range collectConstraints(current, r.ctx, info) {
+			fmt.Fprintf(r.out, "constraint: %s\n", constraint)
+		}
+		if union, ok := concreteValidator(current, nil, r.ctx).(*UnionValidator); ok {
+			for _, alt := range union.applicable(r.ctx) {
+				fmt.Fprintf(r.out, "alternative: %s\n", describeTypeIn(alt, r.ctx))
+			}
+		}
+		r.printCases(current)
+	case "cd":
+		return r.cd(current, arg)
+	case "check":
+		value, err := decodeJSON([]byte(arg))
+		if err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		findings := current.Validate(value, r.ctx)
+		for _, finding := range findings {
+			switch finding.Severity {
+			case SeverityWarning:
+				fmt.Fprint(r.out, "warning: ")
+			case SeverityInfo:
+				fmt.Fprint(r.out, "note: ")
+			}
+			fmt.Fprintln(r.out, finding.text())
+		}
+		if !hasErrors(findings) {
+			fmt.Fprintln(r.out, "valid")
+		}
+	default:
+		return fmt.Errorf("unknown command %s, see help", command)
+	}
+	return nil
+}
+
+// load loads the schema of a resource type, replacing the current type
+func (r *SchemaREPL) load(resourceType string) error {
+	if resourceType == "" {
+		return fmt.Errorf("load needs a resource type, like worldgen/biome")
+	}
+	converter, main, schemaPath, err := r.validator.loadResourceType(resourceType)
+	if err != nil {
+		return err
+	}
+	r.ctx = r.validator.newContext(converter)
+	r.stack = []replFrame{{resourceType, main}}
+	fmt.Fprintf(r.out, "loaded %s from %s\n", resourceType, r.validator.schemaName(schemaPath))
+	return nil
+}
+
+// cd drills into a field or the elements of a list
+func (r *SchemaREPL) cd(current Validator, arg string) error {
+	switch arg {
+	case "":
+		return fmt.Errorf("cd needs a field, [] or ..")
+	case "..":
+		if len(r.stack) > 1 {
+			r.stack = r.stack[:len(r.stack)-1]
+		}
+		return nil
+	}
+
+	switch concrete := concreteValidator(current, nil, r.ctx).(type) {
+	case *ArrayValidator:
+		if arg != "[]" {
+			return fmt.Errorf("%s is a list, use cd []", DescribeType(current))
+		}
+		r.stack = append(r.stack, replFrame{"[]", concrete.ElementValidator})
+	case *StructValidator:
+		field, ok := findStructField(concrete, arg, nil, r.ctx)
+		if !ok {
+			return fmt.Errorf("no field %s in %s", arg, DescribeType(concrete))
+		}
+		r.stack = append(r.stack, replFrame{arg, field.Validator})
+	default:
+		return fmt.Errorf("%s has no fields or elements", DescribeType(current))
+	}
+	return nil
+}
+
+// printCases lists the cases of the dispatchers a type picks its fields
+// from, either by being a dynamic dispatch itself or by spreading one, with
+// the keys mapping to each case on one line
+func (r *SchemaREPL) printCases(current Validator) {
+	dispatchers := []Validator{current}
+	if sv, ok := concreteValidator(current, nil, r.ctx).(*StructValidator); ok {
+		dispatchers = sv.SpreadFields
+	}
+	for _, dispatcher := range dispatchers {
+		if attributed, ok := dispatcher.(*AttributedValidator); ok {
+			dispatcher = attributed.InnerValidator
+		}
+		dv, ok := dispatcher.(*DispatchValidator)
+		if !ok || !dv.Dynamic {
+			continue
+		}
+		for _, c := range dispatchCases(r.ctx.Dispatches[dv.Registry]) {
+			if c.Validator.AppliesForVersion(r.ctx) {
+				fmt.Fprintf(r.out, "case %s: %s\n", strings.Join(c.Keys, ", "), DescribeType(c.Validator))
+			}
+		}
+	}
+}
+
+// printFields lists the fields of a struct and the structs it spreads in
+// the order the schema declares them, then its computed fields
+func (r *SchemaREPL) printFields(current Validator) {
+	sv, ok := concreteValidator(current, nil, r.ctx).(*StructValidator)
+	if !ok {
+		fmt.Fprintf(r.out, "%s has no fields\n", DescribeType(current))
+		return
+	}
+
+	members := declaredMembers(sv, nil, r.ctx)
+	for _, field := range members.Fields {
+		name := field.Name
+		if field.Optional {
+			name += "?"
+		}
+		line := fmt.Sprintf("  %-24s %s", name, DescribeType(field.Validator))
+		if bounds := describeBounds(field.BaseValidator); bounds != "" {
+			line += "  (" + bounds + ")"
+		}
+		fmt.Fprintln(r.out, line)
+	}
+	for _, field := range members.Dynamic {
+		fmt.Fprintf(r.out, "  [%s]: %s\n", DescribeType(field.Key), DescribeType(field.Validator))
+	}
+	for _, spread := range members.Unresolved {
+		fmt.Fprintln(r.out, "  ..."+DescribeType(spread))
+	}
+}
@@ -0,0 +1,91 @@
+package mcheck
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestREPL(t *testing.T) {
+	schemaDir, _ := writeTestPack(t, "worldgen/thing", explainTestSchema, `{}`)
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, schemaDir)
+
+	tests := []struct {
+		name     string
+		commands []string
+		expected []string
+	}{
+		{"no schema", []string{"fields"}, []string{"error: no schema loaded"}},
+		{"fields", []string{"load worldgen/thing", "fields"}, []string{
+			"loaded worldgen/thing from worldgen/thing.mcdoc",
+			"  height                   int @ 0..384  (since 1.19)\n  mode                     Mode\n  settings                 struct Settings\n",
+		}},
+		{"drill", []string{"load worldgen/thing", "cd settings", "cd tags", "show", "cd ..", "fields"}, []string{
+			"worldgen/thing.settings.tags> type: [string] @ 1..\nconstraint: length 1..\n",
+			"worldgen/thing.settings>   scale                    float @ 0.5..2\n",
+		}},
+		{"check", []string{"load worldgen/thing", "cd settings", `check {"scale": 3, "tags": []}`, `check {"scale": 1, "tags": ["a"]}`}, []string{
+			"at scale: value 3 must be less than or equal to 2 (range 0.5..2)\nat tags: tags must not be empty (length 1..)\n",
+			"worldgen/thing.settings> valid\n",
+		}},
+		{"named type", []string{"load worldgen/thing", "type Mode", "show", `check "medium"`}, []string{
+			`constraint: one of "fast", "slow"`,
+			`expected one of "fast", "slow", got "medium"`,
+		}},
+		{"mistakes", []string{"load worldgen/unknown", "load worldgen/thing", "cd nothing", "cd mode", "cd fast", "check {", "bogus"}, []string{
+			"error: no schema for worldgen/unknown",
+			"error: no field nothing in struct Thing",
+			"error: Mode has no fields or elements",
+			"error: invalid JSON",
+			"error: unknown command bogus",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			input := strings.NewReader(strings.Join(append(tt.commands, "quit"), "\n"))
+			if err := NewSchemaREPL(validator, &out).Run(input); err != nil {
+				t.Fatalf("REPL failed: %v", err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("Expected output containing %q, got:\n%s", expected, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestREPLDispatchCases(t *testing.T) {
+	schema := `dispatch minecraft:resource["worldgen/template_pool"] to struct TemplatePool {
+	elements: [Element],
+}
+
+struct Element {
+	element_type: #[id="worldgen/structure_pool_element"] string,
+	...minecraft:template_pool_element[[element_type]],
+}
+
+dispatch minecraft:template_pool_element[
+	legacy_single_pool_element, // until the processors of 1.16
+	single_pool_element,
+] to struct SingleElement {
+	location: string,
+}
+
+dispatch minecraft:template_pool_element[empty_pool_element] to struct {}
+`
+	schemaDir, _ := writeTestPack(t, "worldgen/template_pool", schema, `{}`)
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, schemaDir)
+
+	var out bytes.Buffer
+	input := strings.NewReader("load worldgen/template_pool\ncd elements\ncd []\nshow\nquit")
+	if err := NewSchemaREPL(validator, &out).Run(input); err != nil {
+		t.Fatalf("REPL failed: %v", err)
+	}
+	expected := "type: Element\ncase empty_pool_element: struct\ncase legacy_single_pool_element, single_pool_element: struct SingleElement\n"
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("Expected output containing %q, got:\n%s", expected, out.String())
+	}
+}
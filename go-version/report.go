@@ -0,0 +1,83 @@
+package mcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Output formats of findings
+const (
+	FormatText     = "text"     // a line for each finding
+	FormatTemplate = "template" // a report rendered by the --template file
+)
+
+// OutputFormats are the names of the output formats
+var OutputFormats = []string{FormatText, FormatTemplate}
+
+// Report is what a --template file is executed with. Findings hold the
+// problems with the files and SchemaProblems those with the schemas that
+// kept them from being fully checked, both with localized messages.
+type Report struct {
+	Version        string // target version, or the versions --versions names
+	Findings       []Finding
+	SchemaProblems []Finding
+	Errors         int // findings of each severity, schema problems included
+	Warnings       int
+	Notes          int
+}
+
+// newReport sorts findings into a report, translating their messages
+func newReport(findings []Finding, version string, catalog *Catalog) Report {
+	report := Report{Version: version}
+	for _, finding := range findings {
+		finding = catalog.Localize(finding)
+		if finding.SchemaProblem() {
+			report.SchemaProblems = append(report.SchemaProblems, finding)
+		} else {
+			report.Findings = append(report.Findings, finding)
+		}
+		switch finding.Severity {
+		case SeverityError:
+			report.Errors++
+		case SeverityWarning:
+			report.Warnings++
+		case SeverityInfo:
+			report.Notes++
+		}
+	}
+	return report
+}
+
+// reportFuncs are the functions templates can use besides the builtin ones
+var reportFuncs = template.FuncMap{
+	// text describes a finding without its file and rule, like
+	// at pools.[0]: required field 'rolls' is missing
+	"text": Finding.text,
+	// rule names a rule id, like missing-reference for MCHECK003
+	"rule": ruleName,
+	"join": func(separator string, elements []string) string {
+		return strings.Join(elements, separator)
+	},
+	// json writes a value as JSON, for the bodies of webhook requests
+	"json": func(value interface{}) (string, error) {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	},
+}
+
+// parseReportTemplate reads a --template file
+func parseReportTemplate(file string) (*template.Template, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(file)).Funcs(reportFuncs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,81 @@
+package mcheck
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReportTemplate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "discord.tmpl")
+	template := `{"content": {{json (printf "%d errors, %d warnings for %s" .Errors .Warnings .Version)}}}
+{{range .Findings}}{{.File}} {{.Severity}} {{text .}} [{{rule .Rule}}]{{with .Path}} at {{join "/" .}}{{end}}
+{{end}}{{range .SchemaProblems}}schema: {{.Message}}
+{{end}}`
+	if err := os.WriteFile(file, []byte(template), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := parseReportTemplate(file)
+	if err != nil {
+		t.Fatalf("parseReportTemplate failed: %v", err)
+	}
+
+	findings := []Finding{
+		{File: "a.json", Path: []string{"pools", "[0]"}, Severity: SeverityError, Message: "required field 'rolls' is missing", Rule: RuleMissingField},
+		{File: "b.json", Severity: SeverityWarning, Message: "boolean written as 1", Rule: RuleBooleanNumber},
+		{File: "c.json", Severity: SeverityError, Message: "schema file not found", Rule: RuleSchemaNotFound},
+	}
+	report := newReport(findings, "1.21.4", nil)
+	if report.Errors != 2 || report.Warnings != 1 || report.Notes != 0 || len(report.Findings) != 2 || len(report.SchemaProblems) != 1 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, report); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	expected := `{"content": "2 errors, 1 warnings for 1.21.4"}
+a.json error at pools.[0]: required field 'rolls' is missing [` + ruleName(RuleMissingField) + `] at pools/[0]
+b.json warning boolean written as 1 [` + ruleName(RuleBooleanNumber) + `]
+schema: schema file not found
+`
+	if rendered.String() != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, rendered.String())
+	}
+
+	if err := os.WriteFile(file, []byte("{{.Findings"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseReportTemplate(file); err == nil || !strings.Contains(err.Error(), "invalid template") {
+		t.Errorf("Expected an invalid template to fail, got %v", err)
+	}
+}
+
+func TestOutputFormat(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "report.tmpl")
+	if err := os.WriteFile(file, []byte("{{len .Findings}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		format, template string
+		err              string
+	}{
+		{"text", "", ""},
+		{"template", file, ""},
+		{"template", "", "needs a --template file"},
+		{"text", file, "--template needs --format=template"},
+		{"xml", "", "unknown output format"},
+	}
+	for _, tt := range tests {
+		out, err := NewOutput(OutputOptions{Lang: "en", Format: tt.format, Template: tt.template, Version: "1.20.1"})
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s %q: unexpected error %v", tt.format, tt.template, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%s %q: expected an error containing %q, got %v", tt.format, tt.template, tt.err, err)
+		case tt.err == "" && (out.template != nil) != (tt.format == "template"):
+			t.Errorf("%s %q: unexpected template %v", tt.format, tt.template, out.template)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package mcheck
+
+import "strings"
+
+// Rule ids identify each kind of finding. They are stable across releases so
+// that suppressions, baselines and documentation can refer to them; retired
+// ids are never reused.
+const (
+	RuleUnknownField        = "MCHECK001"
+	RuleMissingField        = "MCHECK002"
+	RuleWrongType           = "MCHECK003"
+	RuleLiteralMismatch     = "MCHECK004"
+	RuleInvalidEnumValue    = "MCHECK005"
+	RuleNoUnionMatch        = "MCHECK006"
+	RuleInvalidFormat       = "MCHECK007"
+	RulePatternMismatch     = "MCHECK008"
+	RuleInvalidLength       = "MCHECK009"
+	RuleOutOfRange          = "MCHECK010"
+	RuleNullValue           = "MCHECK011"
+	RuleBooleanNumber       = "MCHECK012"
+	RuleAliasKey            = "MCHECK013"
+	RuleUnknownType         = "MCHECK014"
+	RuleInvalidJSON         = "MCHECK020"
+	RuleInvalidNBT          = "MCHECK021"
+	RuleUnreadableFile      = "MCHECK022"
+	RuleInvalidSNBT         = "MCHECK023"
+	RuleInvalidEncoding     = "MCHECK024"
+	RuleIncompleteJSON      = "MCHECK025"
+	RuleSchemaNotFound      = "MCHECK030"
+	RuleSchemaError         = "MCHECK031"
+	RuleUnsupportedResource = "MCHECK032"
+	RulePartiallyValidated  = "MCHECK033"
+	RuleMissingReference    = "MCHECK040"
+	RuleInvalidStructure    = "MCHECK041"
+	RuleUnusedResource      = "MCHECK042"
+	RuleInvalidFileName     = "MCHECK043"
+	RuleOverBudget          = "MCHECK044"
+	RuleUndefinedObjective  = "MCHECK045"
+	RuleUnusedObjective     = "MCHECK046"
+	RuleItemNBT             = "MCHECK047"
+	RulePackConflict        = "MCHECK048"
+	RuleMergedTag           = "MCHECK049"
+	RuleNoiseBounds         = "MCHECK050"
+	RuleNoiseToggle         = "MCHECK051"
+	RuleBiomeParameters     = "MCHECK052"
+	RuleSplinePoints        = "MCHECK053"
+	RuleStructurePlacement  = "MCHECK054"
+	RuleEmptySelection      = "MCHECK055"
+	RuleItemModifier        = "MCHECK056"
+	RuleWorldPreset         = "MCHECK057"
+)
+
+// RuleInfo describes a rule for listings and documentation
+type RuleInfo struct {
+	ID          string
+	Name        string
+	Description string
+}
+
+// Rules lists every rule in id order
+var Rules = []RuleInfo{
+	{RuleUnknownField, "unknown-field", "An object has a field its schema does not define"},
+	{RuleMissingField, "missing-field", "A required field is missing"},
+	{RuleWrongType, "wrong-type", "A value has the wrong JSON type"},
+	{RuleLiteralMismatch, "literal-mismatch", "A value differs from the literal its schema requires"},
+	{RuleInvalidEnumValue, "invalid-enum-value", "A value is not one of the values of its enum"},
+	{RuleNoUnionMatch, "no-union-match", "A value matches none of the alternatives of a union"},
+	{RuleInvalidFormat, "invalid-format", "A string or number is not a valid UUID, color, formatting code or regex"},
+	{RulePatternMismatch, "pattern-mismatch", "A string does not match the pattern of its #[regex] attribute"},
+	{RuleInvalidLength, "invalid-length", "A string or list is shorter or longer than its schema allows"},
+	{RuleOutOfRange, "out-of-range", "A number is outside the range its schema allows"},
+	{RuleNullValue, "null-value", "A value is null where the schema expects a value or an omitted field"},
+	{RuleBooleanNumber, "boolean-number", "A boolean is written as 0 or 1, or a number as true or false"},
+	{RuleAliasKey, "alias-key", "A type or other dispatch key is an alias, where the schema names a canonical key"},
+	{RuleUnknownType, "unknown-type", "A type or other dispatch key names nothing its dispatcher has a case for"},
+	{RuleInvalidJSON, "invalid-json", "A file is not valid JSON"},
+	{RuleInvalidNBT, "invalid-nbt", "A file is not valid NBT"},
+	{RuleUnreadableFile, "unreadable-file", "A file could not be read or its resource type could not be determined"},
+	{RuleInvalidSNBT, "invalid-snbt", "NBT given inline to a function command is not valid SNBT"},
+	{RuleInvalidEncoding, "invalid-encoding", "A JSON or schema file is not UTF-8 encoded"},
+	{RuleIncompleteJSON, "incomplete-json", "A JSON file is empty or ends in the middle of its document, as if a merge tool or an interrupted copy clobbered it"},
+	{RuleSchemaNotFound, "schema-not-found", "No schema exists for a file's resource type"},
+	{RuleSchemaError, "schema-error", "The schema for a file could not be parsed or converted"},
+	{RuleUnsupportedResource, "unsupported-resource", "A file's resource type does not exist in the target version"},
+	{RulePartiallyValidated, "partially-validated", "A value or file was only partly checked, like a value whose schema type could not be built or a file too large to decode"},
+	{RuleMissingReference, "missing-reference", "A file references a resource that does not exist in the pack"},
+	{RuleInvalidStructure, "invalid-structure", "A referenced structure file is not a valid structure"},
+	{RuleUnusedResource, "unused-resource", "A resource is never referenced by anything the game loads"},
+	{RuleInvalidFileName, "invalid-file-name", "A file or directory in data/ has uppercase letters, spaces or other characters the game does not allow in resource paths"},
+	{RuleOverBudget, "over-budget", "A file is larger or nests deeper, or a pack has more files, than the budget set for it"},
+	{RuleUndefinedObjective, "undefined-objective", "A scoreboard objective is used but no function in the pack creates it"},
+	{RuleUnusedObjective, "unused-objective", "A scoreboard objective is created but nothing in the pack uses it"},
+	{RuleItemNBT, "item-nbt", "A function command gives item NBT or writes item stacks with Count and tag, which 1.20.5 replaced with components"},
+	{RulePackConflict, "pack-conflict", "Two datapacks loaded together define the same resource, and the one loaded later replaces the other"},
+	{RuleMergedTag, "merged-tag", "Datapacks loaded together define the same tag, whose values the game merges unless one sets replace and drops those of the packs before it"},
+	{RuleNoiseBounds, "noise-bounds", "Noise settings min_y and height are not multiples of 16 or exceed the world height"},
+	{RuleNoiseToggle, "noise-toggle", "Aquifers or ore veins are enabled but their density functions are zero"},
+	{RuleBiomeParameters, "biome-parameters", "Multi noise biome parameters are out of range or leave part of the climate space uncovered"},
+	{RuleSplinePoints, "spline-points", "Spline points are not sorted by location"},
+	{RuleStructurePlacement, "structure-placement", "A structure set placement has spacing, salt or frequency values the game rejects or that never place structures"},
+	{RuleEmptySelection, "empty-selection", "A biome or spawner has nothing to place or spawn because every list it picks from is empty"},
+	{RuleItemModifier, "item-modifier", "An item modifier function needs loot context /item modify lacks, is replaced by a later function, or has counts or levels the game clamps"},
+	{RuleWorldPreset, "world-preset", "A world preset has no overworld or defines a dimension twice"},
+}
+
+// ruleName returns the short name of a rule id
+func ruleName(id string) string {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule.Name
+		}
+	}
+	return ""
+}
+
+// LookupRule finds a rule by its id, like MCHECK010, or its name, like
+// out-of-range
+func LookupRule(rule string) (RuleInfo, bool) {
+	for _, info := range Rules {
+		if strings.EqualFold(info.ID, rule) || info.Name == rule {
+			return info, true
+		}
+	}
+	return RuleInfo{}, false
+}
+
+// RuleError attaches a rule id to an error about a whole file, like a file
+// that cannot be parsed or that has no schema
+type RuleError struct {
+	Rule string
+	Err  error
+}
+
+func (e RuleError) Error() string {
+	return e.Err.Error()
+}
+
+func (e RuleError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,96 @@
+package mcheck
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRulesAreUnique(t *testing.T) {
+	ids, names := make(map[string]bool), make(map[string]bool)
+	for _, rule := range Rules {
+		if ids[rule.ID] || names[rule.Name] {
+			t.Errorf("Duplicate rule %s %s", rule.ID, rule.Name)
+		}
+		if rule.Name == "" || rule.Description == "" {
+			t.Errorf("Rule %s needs a name and description", rule.ID)
+		}
+		ids[rule.ID], names[rule.Name] = true, true
+	}
+}
+
+func TestFindingRules(t *testing.T) {
+	_, ctx := convertSchema(t, "1.20.1", converterTestSchema)
+	validator := ctx.Dispatches["minecraft:resource"]["worldgen/thing"]
+
+	tests := []struct {
+		name     string
+		document map[string]interface{}
+		rule     string
+	}{
+		{"missing field", map[string]interface{}{}, RuleMissingField},
+		{"out of range", map[string]interface{}{"type": "simple", "size": 9.0, "mode": "fast", "tags": []interface{}{}, "value": 1.0, "config": map[string]interface{}{"amount": 1.0}}, RuleOutOfRange},
+		{"enum", map[string]interface{}{"type": "simple", "size": 1.0, "mode": "medium", "tags": []interface{}{}, "value": 1.0, "config": map[string]interface{}{"amount": 1.0}}, RuleInvalidEnumValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := validator.Validate(tt.document, ctx)
+			finding, ok := firstError(findings)
+			if !ok {
+				t.Fatal("Expected an error")
+			}
+			if finding.Rule != tt.rule {
+				t.Errorf("Expected rule %s, got %s (%v)", tt.rule, finding.Rule, findings)
+			}
+		})
+	}
+
+	finding := findingFromError("example.json", RuleError{RuleInvalidJSON, errors.New("failed to parse JSON")})
+	if got := finding.String(); got != "example.json: failed to parse JSON [MCHECK020 invalid-json]" {
+		t.Errorf("Unexpected finding %q", got)
+	}
+	finding.Version = "1.21.1"
+	if got := finding.String(); got != "example.json (1.21.1): failed to parse JSON [MCHECK020 invalid-json]" {
+		t.Errorf("Unexpected finding %q", got)
+	}
+}
+
+func TestSchemaProblems(t *testing.T) {
+	ctx := &ValidationContext{Version: Version{1, 20, 1}, Definitions: map[string]Validator{}}
+	union := &UnionValidator{Alternatives: []Validator{
+		&ReferenceValidator{TypeName: "Missing"},
+		&PrimitiveValidator{Type: "int"},
+	}}
+	findings := union.Validate("x", ctx)
+	if len(findings) != 1 || !findings[0].SchemaProblem() {
+		t.Errorf("Expected an unresolved alternative to be a schema problem, got %v", findings)
+	}
+	if findings := union.Validate(float64(1), ctx); len(findings) != 0 {
+		t.Errorf("Expected the int alternative to match, got %v", findings)
+	}
+
+	invalid := Finding{File: "a.json", Severity: SeverityError, Message: "expected int", Rule: RuleWrongType}
+	missing := Finding{File: "b.json", Severity: SeverityError, Message: "schema file not found", Rule: RuleSchemaNotFound}
+	warning := Finding{File: "a.json", Severity: SeverityWarning, Message: "boolean written as 1", Rule: RuleBooleanNumber}
+	tests := []struct {
+		findings []Finding
+		code     int
+	}{
+		{nil, 0},
+		{[]Finding{warning}, 0},
+		{[]Finding{{File: "a.json", Severity: SeverityInfo, Message: "not fully validated", Rule: RulePartiallyValidated}}, 0},
+		{[]Finding{missing}, ExitSchemaProblem},
+		{[]Finding{warning, missing}, ExitSchemaProblem},
+		{[]Finding{invalid, missing}, ExitInvalid},
+	}
+	for _, tt := range tests {
+		err := Output{}.Print(tt.findings, nil)
+		var exit ExitError
+		switch {
+		case tt.code == 0 && err != nil:
+			t.Errorf("Expected %v to pass, got %v", tt.findings, err)
+		case tt.code != 0 && (!errors.As(err, &exit) || exit.Code != tt.code):
+			t.Errorf("Expected %v to exit with %d, got %v", tt.findings, tt.code, err)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package mcheck
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+// schemaCache keeps the schema files a validator has parsed and converted,
+// so that checking a directory parses each file once and files of the same
+// type share one set of validators. Files imported by several schemas, like
+// the item predicates under java/util, are converted once and their
+// validators shared by all of them. Converted validators are never
+// modified, so sharing them is safe.
+type schemaCache struct {
+	mu        sync.Mutex
+	parsed    map[string]parsedSchema
+	converted map[string]convertedSchema
+	modules   map[string]convertedSchema // imported files, converted on their own
+
+	// generation counts the reloads of schema files, so that a schema
+	// parsed or converted while they were reloaded is not kept
+	generation int
+}
+
+type parsedSchema struct {
+	statements []Statement
+	skipped    []skippedStatement // statements left out because they do not parse
+	err        error
+}
+
+type convertedSchema struct {
+	converter *SchemaConverter
+	err       error
+	files     []string // the schema file and the files it imports
+}
+
+// parsedSchema parses a schema file, or returns the statements it was
+// parsed to before. Converting statements replaces them in their slice, so
+// each caller gets its own copy of the slice. A schema with statements that
+// do not parse is parsed without them, which skippedStatements reports.
+func (v *PEGMCDocValidator) parsedSchema(schemaPath string) ([]Statement, error) {
+	parsed := v.parsed(schemaPath)
+	return append([]Statement(nil), parsed.statements...), parsed.err
+}
+
+// skippedStatements returns the statements of a schema file left out because
+// they do not parse
+func (v *PEGMCDocValidator) skippedStatements(schemaPath string) []skippedStatement {
+	return v.parsed(schemaPath).skipped
+}
+
+func (v *PEGMCDocValidator) parsed(schemaPath string) parsedSchema {
+	key := filepath.Clean(schemaPath)
+	v.cache.mu.Lock()
+	parsed, ok := v.cache.parsed[key]
+	generation := v.cache.generation
+	v.cache.mu.Unlock()
+	if !ok {
+		start := time.Now()
+		parsed.statements, _, parsed.err = v.parseSchemaWithPEG(schemaPath)
+		var skipped *skippedStatementsError
+		if errors.As(parsed.err, &skipped) {
+			parsed.skipped, parsed.err = skipped.skipped, nil
+		}
+		v.timings.addParsing(time.Since(start))
+		v.cache.mu.Lock()
+		if v.cache.parsed == nil {
+			v.cache.parsed = make(map[string]parsedSchema)
+		}
+		if v.cache.generation == generation {
+			v.cache.parsed[key] = parsed
+		}
+		v.cache.mu.Unlock()
+	}
+	return parsed
+}
+
+// convertedSchema parses and converts a schema file along with the modules it
+// imports, reusing the result for later files of the same schema
+func (v *PEGMCDocValidator) convertedSchema(schemaPath string) (*SchemaConverter, error) {
+	key := filepath.Clean(schemaPath)
+	v.cache.mu.Lock()
+	converted, ok := v.cache.converted[key]
+	generation := v.cache.generation
+	v.cache.mu.Unlock()
+	if ok {
+		return converted.converter, converted.err
+	}
+
+	converted = v.convertSchemaFile(schemaPath)
+	v.cache.mu.Lock()
+	if v.cache.converted == nil {
+		v.cache.converted = make(map[string]convertedSchema)
+	}
+	if v.cache.generation == generation {
+		v.cache.converted[key] = converted
+	}
+	v.cache.mu.Unlock()
+	return converted.converter, converted.err
+}
+
+func (v *PEGMCDocValidator) convertSchemaFile(schemaPath string) convertedSchema {
+	converted := convertedSchema{files: []string{filepath.Clean(schemaPath)}}
+
+	// Parse the mcdoc schema using our PEG parser
+	statements, err := v.parsedSchema(schemaPath)
+	if err != nil {
+		converted.err = RuleError{RuleSchemaError, fmt.Errorf("failed to parse schema with PEG: %w", err)}
+		return converted
+	}
+
+	// Convert parsed statements to proper validators
+	converter := NewSchemaConverter(v.targetVersion, statements)
+	converter.AddSkipped(v.skippedStatements(schemaPath))
+	for _, file := range v.importFiles(schemaPath, statements) {
+		converted.files = append(converted.files, filepath.Clean(file))
+		converter.AddSkipped(v.skippedStatements(file))
+		module, err := v.convertedModule(file)
+		if err != nil {
+			continue
+		}
+		converter.AddModule(module)
+	}
+	if _, err := converter.ConvertToValidators(); err != nil {
+		converted.err = RuleError{RuleSchemaError, fmt.Errorf("failed to convert statements to validators: %w", err)}
+		return converted
+	}
+	converted.converter = converter
+	return converted
+}
+
+// convertedModule converts a schema file imported by other schemas on its
+// own, reusing the result for every schema importing it. Problems in the
+// file are reported by the schemas importing it.
+func (v *PEGMCDocValidator) convertedModule(schemaPath string) (*SchemaConverter, error) {
+	key := filepath.Clean(schemaPath)
+	v.cache.mu.Lock()
+	converted, ok := v.cache.modules[key]
+	generation := v.cache.generation
+	v.cache.mu.Unlock()
+	if ok {
+		return converted.converter, converted.err
+	}
+
+	converted.files = []string{key}
+	statements, err := v.parsedSchema(schemaPath)
+	if err == nil {
+		imports := v.importFiles(schemaPath, statements)
+		for _, file := range imports {
+			converted.files = append(converted.files, filepath.Clean(file))
+		}
+		converted.converter = NewSchemaConverter(v.targetVersion, statements)
+		converted.converter.AddImports(v.loadImports(imports))
+		converted.converter.ConvertModule()
+	}
+	converted.err = err
+	v.cache.mu.Lock()
+	if v.cache.modules == nil {
+		v.cache.modules = make(map[string]convertedSchema)
+	}
+	if v.cache.generation == generation {
+		v.cache.modules[key] = converted
+	}
+	v.cache.mu.Unlock()
+	return converted.converter, converted.err
+}
+
+// reloadSchemas forgets what the validator holds of schema files that were
+// edited, added or removed, and of the schemas importing them, so that they
+// are parsed and converted again when next used. The index is rebuilt as
+// well. Imports resolve through the declarations it records, so if those
+// changed, or a file that did not parse changed, a schema may now import
+// files it did not before, and every converted schema is dropped. It
+// returns the number of converted schemas dropped.
+func (v *PEGMCDocValidator) reloadSchemas(changed []string) int {
+	index, err := v.buildIndex()
+	if err != nil {
+		index = &SchemaIndex{}
+	}
+	v.indexMu.Lock()
+	old := v.index
+	v.index = index
+	v.indexMu.Unlock()
+
+	v.cache.mu.Lock()
+	defer v.cache.mu.Unlock()
+	v.cache.generation++
+
+	everything := old == nil ||
+		!reflect.DeepEqual(old.Modules, index.Modules) ||
+		!reflect.DeepEqual(old.Dispatches, index.Dispatches) ||
+		!reflect.DeepEqual(old.Types, index.Types) ||
+		!reflect.DeepEqual(old.Injections, index.Injections)
+	isChanged := make(map[string]bool)
+	for _, file := range changed {
+		key := filepath.Clean(file)
+		isChanged[key] = true
+		if parsed, ok := v.cache.parsed[key]; ok && parsed.err != nil {
+			everything = true
+		}
+		delete(v.cache.parsed, key)
+	}
+	affected := func(converted convertedSchema) bool {
+		return everything || slices.ContainsFunc(converted.files, func(file string) bool { return isChanged[file] })
+	}
+
+	for key, converted := range v.cache.modules {
+		if affected(converted) {
+			delete(v.cache.modules, key)
+		}
+	}
+	dropped := 0
+	for key, converted := range v.cache.converted {
+		if affected(converted) {
+			delete(v.cache.converted, key)
+			dropped++
+		}
+	}
+	return dropped
+}
@@ -0,0 +1,144 @@
+package mcheck
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSchemaCache(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t, "trim", "damage_type")
+	targetVersion, _ := ParseVersion("1.20.2")
+	validator := NewPEGMCDocValidator(targetVersion, schemaDir)
+
+	load := func(resourceType, name string) *SchemaConverter {
+		t.Helper()
+		jsonPath := filepath.Join("data", "test", resourceType, name+".json")
+		converter, _, err := validator.loadSchemaFor(jsonPath)
+		if err != nil {
+			t.Fatalf("loadSchemaFor %s failed: %v", jsonPath, err)
+		}
+		return converter
+	}
+
+	first := load("trim_pattern", "a")
+	if load("trim_pattern", "b") != first {
+		t.Error("Expected files of the same type to share a converted schema")
+	}
+	if load("trim_material", "a") != first {
+		t.Error("Expected types declared by the same schema file to share a converted schema")
+	}
+	if load("damage_type", "a") == first {
+		t.Error("Expected files of other types to get their own converted schema")
+	}
+
+	// Cached parses hand out copies, so converting does not disturb them
+	schemaPath := filepath.Join(schemaDir, "java", "data", "trim.mcdoc")
+	statements, err := validator.parsedSchema(schemaPath)
+	if err != nil {
+		t.Fatalf("parsedSchema failed: %v", err)
+	}
+	statements[0] = nil
+	again, _ := validator.parsedSchema(schemaPath)
+	if again[0] == nil {
+		t.Error("Expected cached statements to be unaffected by changes to a returned copy")
+	}
+
+	// A schema is only read once per validator
+	if err := os.WriteFile(schemaPath, []byte("not mcdoc {"), 0o644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	if load("trim_pattern", "c") != first {
+		t.Error("Expected the schema to be reused after it changed on disk")
+	}
+}
+
+func TestSharedModules(t *testing.T) {
+	schemaDir := t.TempDir()
+	for name, content := range map[string]string{
+		"java/data/advancement.mcdoc": `use ::java::util::item::ItemPredicate
+
+dispatch minecraft:resource[advancement] to struct Advancement {
+	item: ItemPredicate,
+}
+`,
+		"java/data/predicate.mcdoc": `use ::java::util::item::ItemPredicate
+
+dispatch minecraft:resource[predicate] to struct Predicate {
+	items: [ItemPredicate],
+}
+`,
+		"java/util/item.mcdoc": `struct ItemPredicate {
+	count?: int @ 1..,
+}
+`,
+	} {
+		path := filepath.Join(schemaDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, schemaDir)
+
+	predicate := func(resourceType string) Validator {
+		t.Helper()
+		converter, _, err := validator.loadSchemaFor(filepath.Join("data", "test", resourceType, "a.json"))
+		if err != nil {
+			t.Fatalf("loadSchemaFor %s failed: %v", resourceType, err)
+		}
+		return converter.definitions["ItemPredicate"]
+	}
+
+	first := predicate("advancement")
+	if first == nil {
+		t.Fatal("Expected ItemPredicate to be imported")
+	}
+	if predicate("predicate") != first {
+		t.Error("Expected schemas importing the same file to share its validators")
+	}
+	if sv, ok := first.(*StructValidator); !ok || len(sv.Fields) != 1 {
+		t.Errorf("Expected the imported struct, got %#v", first)
+	}
+}
+
+func TestReloadSchemas(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t, "trim", "damage_type")
+	targetVersion, _ := ParseVersion("1.20.2")
+	validator := NewPEGMCDocValidator(targetVersion, schemaDir)
+	badPath := filepath.Join("data", "test", "damage_type", "bad.json")
+	bad := []byte(`{"message_id": "bad", "scaling": "never"}`)
+
+	findings := validator.CheckDocument(badPath, "bad.json", bad)
+	if len(findings) != 1 {
+		t.Fatalf("Expected the missing exhaustion to be found, got %v", findings)
+	}
+	trim, _, err := validator.loadSchemaFor(filepath.Join("data", "test", "trim_pattern", "a.json"))
+	if err != nil {
+		t.Fatalf("loadSchemaFor failed: %v", err)
+	}
+
+	schemaPath := filepath.Join(schemaDir, "java", "data", "damage_type.mcdoc")
+	schema, err := os.ReadFile(schemaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema = bytes.Replace(schema, []byte("exhaustion:"), []byte("exhaustion?:"), 1)
+	if err := os.WriteFile(schemaPath, schema, 0o644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	if dropped := validator.reloadSchemas([]string{schemaPath}); dropped != 1 {
+		t.Errorf("Expected only the damage_type schema to be dropped, got %d", dropped)
+	}
+
+	findings = validator.CheckDocument(badPath, "bad.json", bad)
+	if len(findings) != 0 {
+		t.Errorf("Expected the edited schema to allow a missing exhaustion, got %v", findings)
+	}
+	if again, _, _ := validator.loadSchemaFor(filepath.Join("data", "test", "trim_pattern", "a.json")); again != trim {
+		t.Error("Expected the schemas of unchanged files to be kept")
+	}
+}
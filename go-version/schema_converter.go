@@ -1,6 +1,11 @@
-package main
+package mcheck
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -8,7 +13,20 @@ import (
 type SchemaConverter struct {
 	version     Version
 	statements  []Statement
+	imports     []Statement // statements of the schema files statements refer to
+	modules     []*SchemaConverter // schema files converted on their own, shared between schemas
 	definitions map[string]Validator
+	dispatches  map[string]map[string]Validator
+	aliases     map[string]map[string]string // canonical dispatch keys of alias keys, keyed by registry
+	references  map[string]bool           // type names referenced while converting
+	imported    map[string]bool           // type names brought in by use statements and module paths
+	generics    map[string]TypeAliasStatement
+	bindings    map[string]Validator // type parameters of the generic being converted
+	depth       int                  // nesting of generic instantiations
+	patterns    map[string]*regexp.Regexp // compiled #[regex] patterns by source
+	errs        []error                   // problems found in the schema itself
+	skipped     map[string]bool           // types declared by statements skipped because they do not parse
+	warnings    []Finding                 // problems the schema is used despite, reported with every file
 }
 
 func NewSchemaConverter(version Version, statements []Statement) *SchemaConverter {
@@ -16,52 +34,668 @@ func NewSchemaConverter(version Version, statements []Statement) *SchemaConverte
 		version:     version,
 		statements:  statements,
 		definitions: make(map[string]Validator),
+		dispatches:  make(map[string]map[string]Validator),
+		aliases:     make(map[string]map[string]string),
+		references:  make(map[string]bool),
+		imported:    make(map[string]bool),
+		generics:    make(map[string]TypeAliasStatement),
+		patterns:    make(map[string]*regexp.Regexp),
+		skipped:     make(map[string]bool),
 	}
 }
 
+// AddSkipped records statements left out of the schema because they do not
+// parse. The types they declare accept any value instead of being reported
+// as unresolved, and each statement is reported as a schema warning.
+func (sc *SchemaConverter) AddSkipped(skipped []skippedStatement) {
+	for _, statement := range skipped {
+		for _, name := range statement.names {
+			sc.skipped[name] = true
+		}
+		sc.warnings = append(sc.warnings, Finding{
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("schema statement skipped, values of the types it declares are not checked: %v", statement.err),
+			Rule:     RuleSchemaError,
+			Source:   statement.source(),
+		})
+	}
+}
+
+// Warnings returns the problems the schema is used despite
+func (sc *SchemaConverter) Warnings() []Finding {
+	return sc.warnings
+}
+
+// AddImports adds the statements of other schema files, whose types and
+// dispatcher cases become available to the converted statements. Types the
+// converted statements define themselves take precedence.
+func (sc *SchemaConverter) AddImports(statements []Statement) {
+	sc.imports = append(sc.imports, statements...)
+}
+
+// AddModule adds a schema file converted with ConvertModule, whose types and
+// dispatcher cases become available like those of imports without being
+// converted again. The validators of the module are shared, not copied.
+func (sc *SchemaConverter) AddModule(module *SchemaConverter) {
+	sc.modules = append(sc.modules, module)
+}
+
+// ConvertModule converts the statements of a schema file on its own, so that
+// every schema importing it can share its validators through AddModule.
+// Imports only lend their generic aliases, and the types the module refers
+// to are resolved by the schemas importing it, which know all its imports.
+func (sc *SchemaConverter) ConvertModule() error {
+	sc.registerGenerics()
+	sc.convertStatements(sc.statements)
+	return errors.Join(sc.errs...)
+}
+
 // ConvertToValidators creates proper validators from parsed statements
 func (sc *SchemaConverter) ConvertToValidators() (map[string]Validator, error) {
-	// First pass: create basic validators for all defined types
-	for _, stmt := range sc.statements {
+	sc.registerGenerics()
+	sc.addModules()
+	sc.convertStatements(sc.imports)
+	sc.convertStatements(sc.statements)
+	sc.applyInjections()
+	sc.bindUseAliases()
+
+	// Types imported with use statements or referenced by path that could
+	// not be loaded from other schema files accept any value. Other names
+	// are mistakes in the schema.
+	var unresolved []string
+	for name := range sc.references {
+		if _, exists := sc.definitions[name]; !exists && sc.skipped[name] {
+			sc.definitions[name] = &PrimitiveValidator{Type: "any", Unresolved: "type " + name + " is declared by a statement that does not parse"}
+		} else if !exists {
+			sc.definitions[name] = &PrimitiveValidator{Type: "any", Unresolved: "unresolved type " + name}
+			if !sc.imported[name] {
+				unresolved = append(unresolved, name)
+			}
+		}
+	}
+	sort.Strings(unresolved)
+	for _, name := range unresolved {
+		sc.errs = append(sc.errs, fmt.Errorf("unresolved type %s is neither declared nor imported", name))
+	}
+	sc.checkAliasCycles()
+
+	if len(sc.errs) > 0 {
+		return sc.definitions, errors.Join(sc.errs...)
+	}
+	return sc.definitions, nil
+}
+
+// allStatements returns the statements of the imports and modules followed by
+// the converted statements
+func (sc *SchemaConverter) allStatements() []Statement {
+	statements := append([]Statement{}, sc.imports...)
+	for _, module := range sc.modules {
+		statements = append(statements, module.statements...)
+	}
+	return append(statements, sc.statements...)
+}
+
+// registerGenerics registers the generic aliases of all statements. Generic
+// aliases are instantiated where they are used, which may be before they are
+// declared.
+func (sc *SchemaConverter) registerGenerics() {
+	for _, stmt := range sc.allStatements() {
+		if alias, ok := stmt.(TypeAliasStatement); ok && len(alias.TypeParams) > 0 {
+			sc.generics[alias.Name.Name] = alias
+		}
+	}
+}
+
+// addModules copies what the modules declare and refer to into the maps of
+// the converter, leaving the modules themselves untouched
+func (sc *SchemaConverter) addModules() {
+	for _, module := range sc.modules {
+		for name, validator := range module.definitions {
+			sc.definitions[name] = validator
+		}
+		for registry, cases := range module.dispatches {
+			if sc.dispatches[registry] == nil {
+				sc.dispatches[registry] = make(map[string]Validator)
+			}
+			for key, validator := range cases {
+				sc.dispatches[registry][key] = validator
+			}
+		}
+		for registry, aliases := range module.aliases {
+			if sc.aliases[registry] == nil {
+				sc.aliases[registry] = make(map[string]string)
+			}
+			for alias, canonical := range aliases {
+				sc.aliases[registry][alias] = canonical
+			}
+		}
+		for name := range module.references {
+			sc.references[name] = true
+		}
+		for name := range module.imported {
+			sc.imported[name] = true
+		}
+		sc.errs = append(sc.errs, module.errs...)
+	}
+}
+
+// applyInjections adds the fields and values of inject statements to the
+// structs and enums they name. Types converted by modules are shared with
+// other schemas, so the types injected into are copies replacing them among
+// the types and dispatcher cases of the converter. Injections into types no
+// schema file declares are left out, like the types themselves.
+func (sc *SchemaConverter) applyInjections() {
+	replaced := make(map[Validator]Validator)
+	for _, stmt := range sc.allStatements() {
+		inject, ok := stmt.(InjectStatement)
+		if !ok {
+			continue
+		}
+		name := inject.Path.Segments[len(inject.Path.Segments)-1].Value
+		sc.imported[name] = true
+		original, exists := sc.definitions[name]
+		if !exists {
+			continue
+		}
+		bounds := versionBounds(inject.Attributes)
+
+		switch target := original.(type) {
+		case *StructValidator:
+			if inject.Enum {
+				sc.errs = append(sc.errs, fmt.Errorf("inject enum %s: %s is a struct", name, name))
+				continue
+			}
+			injected := sc.convertStruct(inject.Struct)
+			patched := *target
+			patched.Fields = append(target.Fields[:len(target.Fields):len(target.Fields)], injected.Fields...)
+			patched.SpreadFields = append(target.SpreadFields[:len(target.SpreadFields):len(target.SpreadFields)], injected.SpreadFields...)
+			patched.SpreadAt = make([]int, 0, len(patched.SpreadFields))
+			for i := range target.SpreadFields {
+				patched.SpreadAt = append(patched.SpreadAt, spreadPosition(target, i))
+			}
+			for _, at := range injected.SpreadAt {
+				patched.SpreadAt = append(patched.SpreadAt, len(target.Fields)+at)
+			}
+			patched.DynamicFields = append(target.DynamicFields[:len(target.DynamicFields):len(target.DynamicFields)], injected.DynamicFields...)
+			for i := len(target.Fields); i < len(patched.Fields); i++ {
+				if patched.Fields[i].BaseValidator == (BaseValidator{}) {
+					patched.Fields[i].BaseValidator = bounds
+				}
+			}
+			replaced[original] = &patched
+		case *EnumValidator:
+			if !inject.Enum {
+				sc.errs = append(sc.errs, fmt.Errorf("inject struct %s: %s is an enum", name, name))
+				continue
+			}
+			patched := *target
+			patched.Values = append(target.Values[:len(target.Values):len(target.Values)], sc.convertEnum(EnumStatement{Values: inject.Values}).Values...)
+			for i := len(target.Values); i < len(patched.Values); i++ {
+				if patched.Values[i].BaseValidator == (BaseValidator{}) {
+					patched.Values[i].BaseValidator = bounds
+				}
+			}
+			replaced[original] = &patched
+		default:
+			sc.errs = append(sc.errs, fmt.Errorf("cannot inject into %s, which is neither a struct nor an enum", name))
+			continue
+		}
+		sc.definitions[name] = replaced[original]
+	}
+	if len(replaced) == 0 {
+		return
+	}
+
+	// Dispatch statements declaring a struct hold it directly. A type
+	// injected into more than once is replaced by each injection in turn.
+	latest := func(validator Validator) Validator {
+		for patched, ok := replaced[validator]; ok; patched, ok = replaced[validator] {
+			validator = patched
+		}
+		return validator
+	}
+	for _, cases := range sc.dispatches {
+		for key, validator := range cases {
+			if _, ok := replaced[validator]; ok {
+				cases[key] = latest(validator)
+			} else if attributed, ok := validator.(*AttributedValidator); ok {
+				if _, ok := replaced[attributed.InnerValidator]; ok {
+					copied := *attributed
+					copied.InnerValidator = latest(attributed.InnerValidator)
+					cases[key] = &copied
+				}
+			}
+		}
+	}
+}
+
+// bindUseAliases makes the types imported with use ... as Alias available
+// under their alias
+func (sc *SchemaConverter) bindUseAliases() {
+	for _, stmt := range sc.allStatements() {
+		use, ok := stmt.(UseStatement)
+		if !ok || use.Alias == "" {
+			continue
+		}
+		if _, exists := sc.definitions[use.Alias]; exists {
+			continue
+		}
+		if validator, exists := sc.definitions[use.Path.Segments[len(use.Path.Segments)-1].Value]; exists {
+			sc.definitions[use.Alias] = validator
+		}
+	}
+}
+
+// convertStatements converts statements in place, registering the types and
+// dispatcher cases they declare
+func (sc *SchemaConverter) convertStatements(statements []Statement) {
+	for i, stmt := range statements {
 		switch s := stmt.(type) {
 		case StructStatement:
-			// Create a struct validator with basic fields
-			structValidator := &StructValidator{
-				BaseValidator: BaseValidator{},
-				Fields:        []StructField{}, // Empty for now, will be populated later
-			}
-			sc.definitions[s.Name.Name] = structValidator
+			structValidator := sc.convertStruct(s.Struct)
+			structValidator.BaseValidator = versionBounds(s.Attributes)
+			s.Validator = structValidator
+			statements[i] = s
+		case UseStatement:
+			sc.imported[s.Path.Segments[len(s.Path.Segments)-1].Value] = true
+			sc.imported[s.Name()] = true
 		case TypeAliasStatement:
-			// For now, create a primitive validator
-			aliasValidator := &PrimitiveValidator{
-				BaseValidator: BaseValidator{},
-				Type:          "any", // Accept any type for aliases for now
-			}
-			sc.definitions[s.Name.Name] = aliasValidator
+			// Outside of an instantiation type parameters accept any value
+			s.Validator = sc.instantiate(s, nil)
+			sc.definitions[s.Name.Name] = s.Validator
+			statements[i] = s
+		case EnumStatement:
+			s.Validator = sc.convertEnum(s)
+			sc.definitions[s.Name.Name] = s.Validator
+			statements[i] = s
 		case DispatchStatement:
-			// Create a dispatch validator that delegates to the target
-			dispatchValidator := &PrimitiveValidator{
-				BaseValidator: BaseValidator{},
-				Type:          "any", // Accept any structure for dispatch
+			s.Validator = sc.convertType(s.Target)
+			if bounds := versionBounds(s.Attributes); bounds != (BaseValidator{}) {
+				// Keep #[since] and #[until] on the case so that resource
+				// types can be rejected for versions they do not exist in
+				s.Validator = &AttributedValidator{BaseValidator: bounds, InnerValidator: s.Validator}
+			}
+			if sc.dispatches[s.Registry] == nil {
+				sc.dispatches[s.Registry] = make(map[string]Validator)
 			}
-			sc.definitions["_dispatch"] = dispatchValidator
+			for _, key := range s.Keys {
+				sc.dispatches[s.Registry][key] = s.Validator
+			}
+			sc.addAliases(s)
+			statements[i] = s
 		}
 	}
+}
 
-	// Second pass: resolve references and build field validators
-	// For now, keep it simple and focus on basic structure validation
-	
-	return sc.definitions, nil
+// Dispatches returns the dispatcher cases declared by the converted statements
+func (sc *SchemaConverter) Dispatches() map[string]map[string]Validator {
+	return sc.dispatches
+}
+
+// Aliases returns the canonical key of each alias dispatch key, keyed by
+// registry
+func (sc *SchemaConverter) Aliases() map[string]map[string]string {
+	return sc.aliases
+}
+
+// addAliases records the keys of a dispatch marked #[canonical] other than
+// its canonical one as aliases. #[canonical="shaped"] names the canonical
+// key; a bare #[canonical] makes it the first.
+func (sc *SchemaConverter) addAliases(s DispatchStatement) {
+	for _, attr := range s.Attributes {
+		if attr.Name != "canonical" || len(s.Keys) == 0 {
+			continue
+		}
+		canonical := s.Keys[0]
+		if attr.Value != nil {
+			canonical = strings.TrimPrefix(attributeString(attr.Value), "minecraft:")
+		}
+		if sc.aliases[s.Registry] == nil {
+			sc.aliases[s.Registry] = make(map[string]string)
+		}
+		for _, key := range s.Keys {
+			if key != canonical {
+				sc.aliases[s.Registry][key] = canonical
+			}
+		}
+	}
+}
+
+// convertType creates a validator for a type expression
+func (sc *SchemaConverter) convertType(expr Expression) Validator {
+	switch e := expr.(type) {
+	case PrimitiveExpression:
+		return &PrimitiveValidator{Type: e.Name}
+	case Path:
+		name := e.Segments[len(e.Segments)-1].Value
+		if bound, ok := sc.bindings[name]; ok && len(e.Segments) == 1 {
+			return bound
+		}
+		if len(e.Segments) > 1 || e.IsAbsolute {
+			sc.imported[name] = true
+		}
+		sc.references[name] = true
+		return &ReferenceValidator{TypeName: name}
+	case GenericExpression:
+		if base, ok := e.Base.(Path); ok {
+			if alias, ok := sc.generics[base.Segments[len(base.Segments)-1].Value]; ok {
+				return sc.instantiate(alias, e.Args)
+			}
+		}
+		return sc.convertType(e.Base)
+	case DispatchExpression:
+		return &DispatchValidator{Registry: e.Registry, Key: e.Key, Dynamic: e.Dynamic}
+	case UnionExpression:
+		union := &UnionValidator{}
+		for _, alt := range e.Alternatives {
+			union.Alternatives = append(union.Alternatives, sc.convertType(alt))
+		}
+		return union
+	case ArrayExpression:
+		array := &ArrayValidator{ElementValidator: sc.convertType(e.Element), Typed: e.Typed}
+		if e.Length != nil {
+			array.LengthConstraint = convertRange(*e.Length)
+		}
+		return array
+	case ConstrainedExpression:
+		return &ConstrainedValidator{
+			InnerValidator: sc.convertType(e.Type),
+			Constraint:     convertRange(e.Range),
+		}
+	case StructExpression:
+		return sc.convertStruct(e)
+	case AttributedExpression:
+		attributes := make(map[string]string)
+		args := make(map[string]AttributeArgs)
+		for _, attr := range e.Attributes {
+			attributes[attr.Name] = attributeString(attr.Value)
+			args[attr.Name] = attributeArgs(attr.Value)
+		}
+		attributed := &AttributedValidator{
+			BaseValidator:  versionBounds(e.Attributes),
+			InnerValidator: sc.convertType(e.Type),
+			Attributes:     attributes,
+			Args:           args,
+		}
+		for _, name := range []string{"regex", "pattern"} {
+			if source, ok := attributes[name]; ok {
+				attributed.Pattern = sc.compilePattern(source)
+			}
+		}
+		return attributed
+	case StringLiteral, NumberLiteral, BooleanLiteral:
+		return &LiteralValidator{Value: literalValue(e)}
+	}
+	if expr == nil {
+		return &PrimitiveValidator{Type: "any"}
+	}
+	return &PrimitiveValidator{Type: "any", Unresolved: fmt.Sprintf("unsupported type expression %T", expr)}
+}
+
+// maxGenericDepth bounds nested instantiations of generic aliases, so that
+// aliases instantiating themselves like List<T> = (T | [List<T>]) terminate
+const maxGenericDepth = 8
+
+// instantiate converts a type alias with its type parameters bound to args.
+// Parameters without an argument default to any.
+func (sc *SchemaConverter) instantiate(alias TypeAliasStatement, args []Expression) Validator {
+	if len(args) > len(alias.TypeParams) {
+		sc.errs = append(sc.errs, fmt.Errorf("type %s takes %d type parameters, got %d", alias.Name.Name, len(alias.TypeParams), len(args)))
+	}
+	if sc.depth >= maxGenericDepth {
+		return &PrimitiveValidator{Type: "any", Unresolved: "generic type " + alias.Name.Name + " is nested too deeply"}
+	}
+
+	bindings := make(map[string]Validator, len(alias.TypeParams))
+	for i, param := range alias.TypeParams {
+		bindings[param] = &PrimitiveValidator{Type: "any"}
+		if i < len(args) {
+			// Arguments refer to the parameters of the enclosing generic
+			bindings[param] = sc.convertType(args[i])
+		}
+	}
+
+	outer := sc.bindings
+	sc.bindings = bindings
+	sc.depth++
+	validator := sc.convertType(alias.Type)
+	sc.depth--
+	sc.bindings = outer
+	return validator
+}
+
+// checkAliasCycles reports type aliases that resolve to themselves without
+// passing through a struct, array or dispatch, which could never be validated
+func (sc *SchemaConverter) checkAliasCycles() {
+	var names []string
+	for _, stmt := range sc.allStatements() {
+		if alias, ok := stmt.(TypeAliasStatement); ok {
+			names = append(names, alias.Name.Name)
+		}
+	}
+
+	reported := make(map[string]bool)
+	var visit func(chain []string) []string
+	visit = func(chain []string) []string {
+		for _, next := range directReferences(sc.definitions[chain[len(chain)-1]], nil) {
+			for i, name := range chain {
+				if name == next {
+					return append(chain[i:], next)
+				}
+			}
+			if cycle := visit(append(chain, next)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+
+	for _, name := range names {
+		if reported[name] {
+			continue
+		}
+		if cycle := visit([]string{name}); cycle != nil {
+			for _, member := range cycle {
+				reported[member] = true
+			}
+			sc.errs = append(sc.errs, fmt.Errorf("type alias cycle: %s", strings.Join(cycle, " -> ")))
+		}
+	}
+}
+
+// directReferences appends the type names a validator defers to before
+// consuming any of the value
+func directReferences(v Validator, names []string) []string {
+	switch t := v.(type) {
+	case *ReferenceValidator:
+		names = append(names, t.TypeName)
+	case *AttributedValidator:
+		names = directReferences(t.InnerValidator, names)
+	case *ConstrainedValidator:
+		names = directReferences(t.InnerValidator, names)
+	case *UnionValidator:
+		for _, alt := range t.Alternatives {
+			names = directReferences(alt, names)
+		}
+	}
+	return names
+}
+
+// convertStruct creates a struct validator, registering it if it is named
+func (sc *SchemaConverter) convertStruct(expr StructExpression) *StructValidator {
+	structValidator := &StructValidator{Source: expr.Pos}
+	if expr.Name != nil {
+		structValidator.Name = expr.Name.Name
+		sc.definitions[expr.Name.Name] = structValidator
+	}
+
+	for _, field := range expr.Fields {
+		switch {
+		case field.Spread:
+			spread := sc.convertType(field.Type)
+			if bounds := versionBounds(field.Attributes); bounds != (BaseValidator{}) {
+				spread = &AttributedValidator{BaseValidator: bounds, InnerValidator: spread}
+			}
+			structValidator.SpreadFields = append(structValidator.SpreadFields, spread)
+			structValidator.SpreadAt = append(structValidator.SpreadAt, len(structValidator.Fields))
+		case field.Key != nil:
+			structValidator.DynamicFields = append(structValidator.DynamicFields, DynamicField{
+				Key:           sc.convertType(field.Key),
+				Validator:     sc.convertType(field.Type),
+				Optional:      field.Optional,
+				BaseValidator: versionBounds(field.Attributes),
+			})
+		default:
+			structValidator.Fields = append(structValidator.Fields, StructField{
+				Name:          field.Name.Name,
+				Validator:     sc.convertType(field.Type),
+				Optional:      field.Optional,
+				Doc:           field.Doc,
+				Source:        field.Pos,
+				BaseValidator: versionBounds(field.Attributes),
+			})
+		}
+	}
+	return structValidator
+}
+
+func (sc *SchemaConverter) convertEnum(stmt EnumStatement) *EnumValidator {
+	enum := &EnumValidator{
+		BaseValidator: versionBounds(stmt.Attributes),
+		Name:          stmt.Name.Name,
+		Type:          stmt.Type,
+	}
+	for _, value := range stmt.Values {
+		enum.Values = append(enum.Values, EnumValue{
+			BaseValidator: versionBounds(value.Attributes),
+			Name:          value.Name,
+			Value:         literalValue(value.Value),
+			Doc:           value.Doc,
+		})
+	}
+	return enum
+}
+
+// compilePattern compiles a #[regex] pattern, which must match the whole
+// string like Java's String.matches. Patterns are cached so that every use of
+// a pattern in a schema shares one compiled regexp.
+func (sc *SchemaConverter) compilePattern(source string) *regexp.Regexp {
+	if pattern, ok := sc.patterns[source]; ok {
+		return pattern
+	}
+	pattern, err := regexp.Compile("^(?:" + source + ")$")
+	if err != nil {
+		sc.errs = append(sc.errs, fmt.Errorf("invalid regex attribute %q: %w", source, err))
+	}
+	sc.patterns[source] = pattern
+	return pattern
+}
+
+// versionBounds reads the #[since], #[until] and #[feature] attributes gating
+// a declaration into a BaseValidator
+func versionBounds(attributes []Attribute) BaseValidator {
+	bounds := BaseValidator{}
+	for _, attr := range attributes {
+		switch attr.Name {
+		case "since":
+			bounds.Since = attributeString(attr.Value)
+		case "until":
+			bounds.Until = attributeString(attr.Value)
+		case "feature":
+			bounds.Feature = strings.TrimPrefix(attributeString(attr.Value), "minecraft:")
+		}
+	}
+	return bounds
+}
+
+// attributeString returns the plain value of a simple attribute like #[id="item"]
+func attributeString(value Expression) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case StringLiteral:
+		return v.Value
+	}
+	return value.String()
+}
+
+// attributeArgs reads the arguments of an attribute, like the registry and
+// tags of #[id(registry="item", tags=allowed)]
+func attributeArgs(value Expression) AttributeArgs {
+	var args AttributeArgs
+	tree, ok := value.(AttributeTree)
+	if !ok {
+		if value != nil {
+			args.Positional = []string{attributeString(value)}
+		}
+		return args
+	}
+	for _, positional := range tree.Positional {
+		args.Positional = append(args.Positional, attributeString(positional))
+	}
+	if len(tree.Named) > 0 {
+		args.Named = make(map[string][]string, len(tree.Named))
+	}
+	for _, named := range tree.Named {
+		if list, ok := named.Value.(ArrayLiteral); ok {
+			values := []string{}
+			for _, element := range list.Values {
+				values = append(values, attributeString(element))
+			}
+			args.Named[named.Name] = values
+		} else {
+			args.Named[named.Name] = []string{attributeString(named.Value)}
+		}
+	}
+	return args
+}
+
+func literalValue(expr Expression) interface{} {
+	switch e := expr.(type) {
+	case StringLiteral:
+		return e.Value
+	case NumberLiteral:
+		value, _ := strconv.ParseFloat(e.Value, 64)
+		return value
+	case BooleanLiteral:
+		return e.Value
+	}
+	return nil
+}
+
+func convertRange(expr RangeExpression) *RangeValidator {
+	rangeValidator := &RangeValidator{
+		MinExclusive: expr.MinExclusive,
+		MaxExclusive: expr.MaxExclusive,
+	}
+	if expr.Min != nil {
+		if value, err := strconv.ParseFloat(*expr.Min, 64); err == nil {
+			rangeValidator.Min = &value
+		}
+	}
+	if expr.Max != nil {
+		if value, err := strconv.ParseFloat(*expr.Max, 64); err == nil {
+			rangeValidator.Max = &value
+		}
+	}
+	return rangeValidator
+}
+
+// MainValidatorFor returns the validator for a resource type like
+// "worldgen/noise_settings", falling back to GetMainValidator
+func (sc *SchemaConverter) MainValidatorFor(resourceType string) Validator {
+	if validator, exists := sc.dispatches["minecraft:resource"][resourceType]; exists {
+		return validator
+	}
+	return sc.GetMainValidator()
 }
 
 // GetMainValidator finds the primary validator for validation
 func (sc *SchemaConverter) GetMainValidator() Validator {
-	// Look for dispatch statements first
+	// Look for resource dispatch statements first
 	for _, stmt := range sc.statements {
-		if _, ok := stmt.(DispatchStatement); ok {
-			if validator, exists := sc.definitions["_dispatch"]; exists {
-				return validator
-			}
+		if dispatchStmt, ok := stmt.(DispatchStatement); ok && dispatchStmt.Registry == "minecraft:resource" {
+			return dispatchStmt.Validator
 		}
 	}
 
@@ -103,14 +737,14 @@ type BasicStructValidator struct {
 	BaseValidator
 }
 
-func (bsv BasicStructValidator) Validate(value interface{}, ctx *ValidationContext) error {
+func (bsv BasicStructValidator) Validate(value interface{}, ctx *ValidationContext) []Finding {
 	if !bsv.AppliesForVersion(ctx) {
 		return nil
 	}
 	
 	// Accept any map[string]interface{} (JSON object)
 	if _, ok := value.(map[string]interface{}); !ok {
-		return ValidationError{Path: ctx.Path, Message: "expected object structure"}
+		return failf(ctx, RuleWrongType, "expected object structure")
 	}
 	
 	return nil // Accept any fields within the object
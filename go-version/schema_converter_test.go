@@ -0,0 +1,723 @@
+package mcheck
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// convertSchema parses and converts mcdoc source for the given version
+func convertSchema(t *testing.T, version string, input string) (*SchemaConverter, *ValidationContext) {
+	t.Helper()
+
+	targetVersion, err := ParseVersion(version)
+	if err != nil {
+		t.Fatalf("Failed to parse version: %v", err)
+	}
+
+	converter := NewSchemaConverter(targetVersion, parseStatements(t, input))
+	definitions, err := converter.ConvertToValidators()
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+
+	ctx := &ValidationContext{
+		Version:     targetVersion,
+		Path:        []string{},
+		Definitions: definitions,
+		Dispatches:  converter.Dispatches(),
+		Aliases:     converter.Aliases(),
+	}
+	return converter, ctx
+}
+
+const converterTestSchema = `use super::Imported
+
+dispatch minecraft:resource["worldgen/thing"] to struct Thing {
+	type: string,
+	/// How big the thing is.
+	size: int @ 1..4,
+	name?: string @ 1..,
+	mode: Mode,
+	tags: [string] @ ..2,
+	#[since="1.19"]
+	newer: boolean,
+	value: (float | "auto"),
+	imported?: Imported,
+	config?: minecraft:thing_config[[type]],
+}
+
+enum(string) Mode { Fast = "fast", Slow = "slow" }
+
+dispatch minecraft:thing_config[simple] to struct SimpleConfig {
+	amount: int,
+}`
+
+func TestSchemaConverterValidation(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.18.2", converterTestSchema)
+
+	mainValidator := converter.MainValidatorFor("worldgen/thing")
+	if mainValidator == nil {
+		t.Fatal("Expected a main validator for worldgen/thing")
+	}
+
+	valid := map[string]interface{}{
+		"type":     "simple",
+		"size":     float64(2),
+		"mode":     "fast",
+		"tags":     []interface{}{"a"},
+		"value":    "auto",
+		"imported": map[string]interface{}{"anything": true},
+		"config":   map[string]interface{}{"amount": float64(3)},
+	}
+	if err := findingsError(mainValidator.Validate(valid, ctx)); err != nil {
+		t.Errorf("Expected valid document to pass, got: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		field  string
+		value  interface{}
+		remove bool
+	}{
+		{"out of range", "size", float64(5), false},
+		{"empty string", "name", "", false},
+		{"bad enum", "mode", "medium", false},
+		{"too many tags", "tags", []interface{}{"a", "b", "c"}, false},
+		{"bad union", "value", "manual", false},
+		{"bad dispatch case", "config", map[string]interface{}{"amount": "x"}, false},
+		{"missing required", "size", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := make(map[string]interface{})
+			for k, v := range valid {
+				doc[k] = v
+			}
+			if tt.remove {
+				delete(doc, tt.field)
+			} else {
+				doc[tt.field] = tt.value
+			}
+			if err := findingsError(mainValidator.Validate(doc, ctx)); err == nil {
+				t.Errorf("Expected validation to fail when %s is %v", tt.field, tt.value)
+			}
+		})
+	}
+}
+
+func TestSchemaConverterVersionedField(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.19", converterTestSchema)
+
+	doc := map[string]interface{}{
+		"type":  "other",
+		"size":  float64(1),
+		"mode":  "slow",
+		"tags":  []interface{}{},
+		"value": float64(1.5),
+	}
+	if err := findingsError(converter.MainValidatorFor("worldgen/thing").Validate(doc, ctx)); err == nil {
+		t.Error("Expected missing 'newer' field to fail for 1.19")
+	}
+
+	doc["newer"] = true
+	if err := findingsError(converter.MainValidatorFor("worldgen/thing").Validate(doc, ctx)); err != nil {
+		t.Errorf("Expected document to pass for 1.19, got: %v", err)
+	}
+}
+
+const genericTestSchema = `type Tag<E> = struct {
+	replace?: boolean,
+	values: [TagEntry<E>],
+}
+
+type TagEntry<E> = (
+	E |
+	struct ExplicitTagEntry {
+		id: E,
+		required?: boolean,
+	} |
+)
+
+type Ids = ItemIds
+type ItemIds = Count
+type Count = int @ 0..64
+
+dispatch minecraft:resource[item_tag] to Tag<Count>
+
+dispatch minecraft:resource[any_tag] to Tag
+
+struct Stack {
+	count: Ids,
+}
+`
+
+func TestSchemaConverterGenerics(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", genericTestSchema)
+
+	tests := []struct {
+		name         string
+		resourceType string
+		value        interface{}
+		valid        bool
+	}{
+		{"bound parameter", "item_tag", map[string]interface{}{"values": []interface{}{float64(3)}}, true},
+		{"bound parameter out of range", "item_tag", map[string]interface{}{"values": []interface{}{float64(65)}}, false},
+		{"bound parameter in nested generic", "item_tag", map[string]interface{}{"values": []interface{}{
+			map[string]interface{}{"id": float64(1)},
+		}}, true},
+		{"bound parameter in nested generic wrong type", "item_tag", map[string]interface{}{"values": []interface{}{
+			map[string]interface{}{"id": "stone"},
+		}}, false},
+		{"missing argument defaults to any", "any_tag", map[string]interface{}{"values": []interface{}{"stone", true}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := findingsError(converter.MainValidatorFor(tt.resourceType).Validate(tt.value, ctx))
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected validation to fail")
+			}
+		})
+	}
+
+	// Alias chains resolve through every link
+	stack := ctx.Definitions["Stack"]
+	if err := findingsError(stack.Validate(map[string]interface{}{"count": float64(100)}, ctx)); err == nil || !strings.Contains(err.Error(), "at count: value 100 must be less than or equal to 64") {
+		t.Errorf("Expected range error through alias chain, got %v", err)
+	}
+}
+
+func TestSchemaConverterAliasErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		error  string
+	}{
+		{"cycle", "type A = B\ntype B = (string | #[since=\"1.19\"] C)\ntype C = A\n", "type alias cycle: A -> B -> C -> A"},
+		{"self reference", "type A = A\n", "type alias cycle: A -> A"},
+		{"unresolved", "type A = Missing\n", "unresolved type Missing is neither declared nor imported"},
+		{"too many arguments", "type Pair<K, V> = struct { key: K, value: V }\ntype Entry = Pair<string, int, int>\n", "type Pair takes 2 type parameters, got 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter := NewSchemaConverter(Version{1, 20, 1}, parseStatements(t, tt.schema))
+			_, err := converter.ConvertToValidators()
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("Expected error containing %q, got %v", tt.error, err)
+			}
+		})
+	}
+
+	// Imported names and recursion through structs are fine
+	converter := NewSchemaConverter(Version{1, 20, 1}, parseStatements(t, "use ::java::util::Text\ntype A = Text\ntype List = struct { next?: List }\ntype Tree<T> = (T | [Tree<T>])\ntype Root = Tree<string>\n"))
+	if _, err := converter.ConvertToValidators(); err != nil {
+		t.Errorf("Expected no errors, got %v", err)
+	}
+}
+
+const spreadTestSchema = `use ::java::util::Imported
+
+struct Base {
+	type: string,
+	weight: int @ 1..,
+	#[until="1.20"]
+	legacy?: boolean,
+}
+
+type Aliased = Base
+
+type Conditions<C> = struct {
+	conditions: C,
+}
+
+dispatch minecraft:resource[plain] to struct Plain {
+	...Base,
+	name: string,
+}
+
+dispatch minecraft:resource[aliased] to struct AliasedSpread {
+	...Aliased,
+	...Conditions<[string]>,
+}
+
+dispatch minecraft:resource[override] to struct Override {
+	...Base,
+	weight?: float,
+}
+
+dispatch minecraft:resource[versioned] to struct Versioned {
+	#[until="1.20"]
+	...struct {
+		style: string,
+	},
+	#[since="1.20"]
+	...struct {
+		style?: int,
+	},
+}
+
+dispatch minecraft:resource[dispatched] to struct Dispatched {
+	kind: string,
+	...minecraft:kind[[kind]],
+}
+
+dispatch minecraft:kind[square] to struct Square {
+	side: int,
+}
+
+dispatch minecraft:resource[open] to struct Open {
+	...Imported,
+	name: string,
+}
+`
+
+func TestSchemaConverterSpreads(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      string
+		resourceType string
+		document     map[string]interface{}
+		error        string
+	}{
+		{"struct spread", "1.20.1", "plain", map[string]interface{}{"type": "a", "weight": float64(1), "name": "n"}, ""},
+		{"struct spread missing field", "1.20.1", "plain", map[string]interface{}{"type": "a", "name": "n"}, "required field 'weight' is missing"},
+		{"struct spread versioned field", "1.19.4", "plain", map[string]interface{}{"type": "a", "weight": float64(1), "name": "n", "legacy": true}, ""},
+		{"struct spread versioned field removed", "1.20.1", "plain", map[string]interface{}{"type": "a", "weight": float64(1), "name": "n", "legacy": true}, "unexpected field 'legacy'"},
+		{"struct spread field removed at until", "1.20", "plain", map[string]interface{}{"type": "a", "weight": float64(1), "name": "n", "legacy": true}, "unexpected field 'legacy'"},
+		{"versioned spread at boundary", "1.20", "versioned", map[string]interface{}{"style": "bold"}, "at style: expected int"},
+		{"alias and generic spreads", "1.20.1", "aliased", map[string]interface{}{"type": "a", "weight": float64(1), "conditions": []interface{}{"x"}}, ""},
+		{"generic spread argument", "1.20.1", "aliased", map[string]interface{}{"type": "a", "weight": float64(1), "conditions": []interface{}{float64(1)}}, "at conditions.[0]: expected string"},
+		{"override makes field optional", "1.20.1", "override", map[string]interface{}{"type": "a"}, ""},
+		{"override changes field type", "1.20.1", "override", map[string]interface{}{"type": "a", "weight": float64(0.5)}, ""},
+		{"versioned spread before", "1.19.4", "versioned", map[string]interface{}{}, "required field 'style' is missing"},
+		{"versioned spread after", "1.20.1", "versioned", map[string]interface{}{}, ""},
+		{"versioned spread after type", "1.20.1", "versioned", map[string]interface{}{"style": "bold"}, "at style: expected int"},
+		{"dispatched spread", "1.20.1", "dispatched", map[string]interface{}{"kind": "minecraft:square", "side": float64(2)}, ""},
+		{"dispatched spread field", "1.20.1", "dispatched", map[string]interface{}{"kind": "square", "side": "2"}, "at side: expected int"},
+		{"dispatched spread unknown field", "1.20.1", "dispatched", map[string]interface{}{"kind": "square", "side": float64(2), "radius": float64(1)}, "unexpected field 'radius'"},
+		{"unresolved spread is open", "1.20.1", "open", map[string]interface{}{"name": "n", "anything": true}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, ctx := convertSchema(t, tt.version, spreadTestSchema)
+			err := findingsError(converter.MainValidatorFor(tt.resourceType).Validate(tt.document, ctx))
+			switch {
+			case tt.error == "" && err != nil:
+				t.Errorf("Expected valid, got %v", err)
+			case tt.error != "" && (err == nil || !strings.Contains(err.Error(), tt.error)):
+				t.Errorf("Expected error containing %q, got %v", tt.error, err)
+			}
+		})
+	}
+}
+
+const indexSignatureTestSchema = `dispatch minecraft:resource[advancement] to struct Advancement {
+	criteria: struct {
+		[string]: Criterion,
+	},
+	rewards?: struct Rewards {
+		experience?: int,
+	},
+}
+
+struct Criterion {
+	trigger: string,
+	conditions?: struct {
+		[string]: any,
+	},
+}
+
+dispatch minecraft:resource[entity_scores] to struct EntityScores {
+	entity: string,
+	scores: struct {
+		[#[objective] string]: (int | struct { min?: int, max?: int }),
+	},
+}
+
+enum(string) MobCategory {
+	Monster = "monster",
+	Creature = "creature",
+}
+
+dispatch minecraft:resource[spawners] to struct Spawners {
+	name?: string,
+	[MobCategory]: [string],
+}
+`
+
+func TestSchemaConverterIndexSignatures(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", indexSignatureTestSchema)
+
+	tests := []struct {
+		name         string
+		resourceType string
+		document     string
+		error        string
+	}{
+		{"criteria", "advancement", `{"criteria": {"killed_zombie": {"trigger": "minecraft:player_killed_entity"}, "ate": {"trigger": "minecraft:consume_item", "conditions": {"item": {}}}}}`, ""},
+		{"empty criteria", "advancement", `{"criteria": {}}`, ""},
+		{"criterion value", "advancement", `{"criteria": {"killed_zombie": {"conditions": {}}}}`, "at criteria.killed_zombie: required field 'trigger' is missing"},
+		{"declared field next to criteria", "advancement", `{"criteria": {}, "rewards": {"experience": "10"}}`, "at rewards.experience: expected int"},
+		{"scores", "entity_scores", `{"entity": "this", "scores": {"kills": 3, "deaths": {"min": 1}}}`, ""},
+		{"score value", "entity_scores", `{"entity": "this", "scores": {"kills": "3"}}`, "at scores.kills: value does not match any union alternative"},
+		{"enum keys", "spawners", `{"name": "plains", "monster": ["zombie"], "creature": []}`, ""},
+		{"enum key value", "spawners", `{"monster": [1]}`, "at monster.[0]: expected string"},
+		{"unknown enum key", "spawners", `{"ambient": []}`, `unexpected key 'ambient', keys must be MobCategory: expected one of "monster", "creature", got "ambient" [MCHECK005`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := decodeJSON([]byte(tt.document))
+			if err != nil {
+				t.Fatalf("Failed to decode document: %v", err)
+			}
+			findings := converter.MainValidatorFor(tt.resourceType).Validate(document, ctx)
+			failed, ok := firstError(findings)
+			switch {
+			case tt.error == "" && ok:
+				t.Errorf("Expected valid, got %v", findings)
+			case tt.error != "" && (!ok || !strings.Contains(failed.String(), tt.error)):
+				t.Errorf("Expected error containing %q, got %v", tt.error, findings)
+			}
+		})
+	}
+}
+
+func TestSchemaConverterSourcePositions(t *testing.T) {
+	schemaDir, jsonPath := writeTestPack(t, "worldgen/thing", `dispatch minecraft:resource["worldgen/thing"] to struct Thing {
+	name: string,
+	/// Added later
+	#[since="1.19"]
+	size: int,
+	extra?: struct Extra {
+		value: int,
+	},
+}
+`, `{"extra": {}, "other": 1}`)
+
+	validator := NewPEGMCDocValidator(Version{1, 20, 0}, schemaDir)
+	findings := validator.CheckFile(jsonPath, "example.json")
+	expected := []struct{ message, source string }{
+		{"required field 'name' is missing; required by worldgen/thing.mcdoc:2", "worldgen/thing.mcdoc:2"},
+		{"required field 'size' is missing; required by worldgen/thing.mcdoc:4 (since 1.19)", "worldgen/thing.mcdoc:4"},
+		{"required field 'value' is missing; required by worldgen/thing.mcdoc:7", "worldgen/thing.mcdoc:7"},
+		{"unexpected field 'other'", "worldgen/thing.mcdoc:1"},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %v", len(expected), findings)
+	}
+	for i, finding := range findings {
+		if finding.Message != expected[i].message || finding.Source != expected[i].source {
+			t.Errorf("Expected %q from %s, got %q from %s", expected[i].message, expected[i].source, finding.Message, finding.Source)
+		}
+	}
+}
+
+func TestSchemaConverterFeatureFlags(t *testing.T) {
+	schemaDir, jsonPath := writeTestPack(t, "worldgen/thing", `dispatch minecraft:resource["worldgen/thing"] to struct Thing {
+	name: string,
+	#[feature="update_1_21"]
+	breeze?: int,
+	mode: Mode,
+}
+
+enum(string) Mode {
+	Old = "old",
+	#[feature="minecraft:winter_drop"]
+	New = "new",
+}
+
+#[feature="update_1_21"]
+dispatch minecraft:resource["worldgen/trial"] to struct Trial {
+	name: string,
+}
+`, `{"name": "a", "breeze": 1, "mode": "new"}`)
+	trialPath := filepath.Join(filepath.Dir(filepath.Dir(jsonPath)), "trial", "example.json")
+	if err := os.MkdirAll(filepath.Dir(trialPath), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(trialPath, []byte(`{"name": "a"}`), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", trialPath, err)
+	}
+
+	tests := []struct {
+		features []string
+		path     string
+		expected []string
+	}{
+		{nil, jsonPath, []string{
+			`at mode: expected one of "old", got "new"`,
+			"unexpected field 'breeze', it needs the update_1_21 feature flag",
+		}},
+		{[]string{"update_1_21"}, jsonPath, []string{`at mode: expected one of "old", got "new"`}},
+		{[]string{"minecraft:update_1_21", "winter_drop"}, jsonPath, nil},
+		{nil, trialPath, []string{"worldgen/trial files need the update_1_21 feature flag, enable it in pack.mcmeta and with --features update_1_21"}},
+		{[]string{"update_1_21"}, trialPath, nil},
+	}
+
+	for _, tt := range tests {
+		validator := NewPEGMCDocValidator(Version{1, 21, 0}, schemaDir)
+		validator.SetFeatures(tt.features)
+
+		var messages []string
+		for _, finding := range validator.CheckFile(tt.path, "example.json") {
+			messages = append(messages, finding.text())
+		}
+		if strings.Join(messages, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("Expected %q with features %v, got %q", tt.expected, tt.features, messages)
+		}
+	}
+}
+
+func TestSchemaConverterUnresolvedTypes(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", `use ::java::util::text::Text
+
+dispatch minecraft:resource[thing] to struct Thing {
+	name: string,
+	description: Text,
+	lines?: [Text],
+}
+`)
+	main := converter.MainValidatorFor("thing")
+	document := map[string]interface{}{
+		"name":        "a",
+		"description": map[string]interface{}{"text": "b"},
+		"lines":       []interface{}{"c"},
+	}
+
+	findings := main.Validate(document, ctx)
+	if err := findingsError(findings); err != nil {
+		t.Fatalf("Expected values of unresolved types to be accepted, got %v", err)
+	}
+	var notes []string
+	for _, finding := range findings {
+		if finding.Severity != SeverityInfo || finding.Rule != RulePartiallyValidated {
+			t.Errorf("Expected a partially validated note, got %v", finding)
+		}
+		notes = append(notes, finding.text())
+	}
+	expected := []string{
+		"at description: field 'description' not fully validated: unresolved type Text",
+		"at lines.[0]: value not fully validated: unresolved type Text",
+	}
+	if strings.Join(notes, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected notes %q, got %q", expected, notes)
+	}
+
+	if got := DescribeType(ctx.Definitions["Text"]); got != "any (unresolved type Text)" {
+		t.Errorf("Expected the fallback to be described, got %q", got)
+	}
+
+	ctx.StrictSchema = true
+	err := findingsError(main.Validate(document, ctx))
+	if err == nil || !strings.Contains(err.Error(), "at description: field 'description' cannot be fully validated: unresolved type Text") {
+		t.Errorf("Expected unresolved types to fail with --strict-schema, got %v", err)
+	}
+}
+
+func TestStrictSchema(t *testing.T) {
+	schema := `use ::java::util::Imported
+
+dispatch minecraft:resource["worldgen/thing"] to struct Thing {
+	...Imported,
+	effect?: minecraft:effect[[type]],
+	type?: string,
+}
+`
+	tests := []struct {
+		name     string
+		schema   string
+		document string
+		expected string
+	}{
+		{"open spread", schema, `{"anything": 1}`, "cannot check for unknown fields: spread ...Imported cannot be resolved"},
+		{"unloaded dispatcher", schema, `{"type": "a", "effect": {}}`, "at effect: field 'effect' cannot be fully validated: dispatcher minecraft:effect is not loaded"},
+		{"no main type", `struct Other { a?: int }`, `{}`, "worldgen/thing.mcdoc declares no type for worldgen/thing files"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaDir, jsonPath := writeTestPack(t, "worldgen/thing", tt.schema, tt.document)
+			validator := NewPEGMCDocValidator(Version{1, 20, 1}, schemaDir)
+			if findings := validator.CheckFile(jsonPath, "thing.json"); hasErrors(findings) {
+				t.Fatalf("Expected %s to pass without --strict-schema, got %v", tt.document, findings)
+			}
+
+			validator = NewPEGMCDocValidator(Version{1, 20, 1}, schemaDir)
+			validator.SetStrictSchema(true)
+			finding, ok := firstError(validator.CheckFile(jsonPath, "thing.json"))
+			if !ok || !finding.SchemaProblem() || !strings.Contains(finding.text(), tt.expected) {
+				t.Errorf("Expected a schema problem containing %q, got %v", tt.expected, finding)
+			}
+		})
+	}
+}
+
+const aliasTestSchema = `dispatch minecraft:resource[recipe] to struct Recipe {
+	type: string,
+	...minecraft:recipe_serializer[[type]],
+}
+
+dispatch minecraft:resource[holder] to struct Holder {
+	kind: string,
+	value: minecraft:recipe_serializer[[kind]],
+}
+
+#[canonical]
+dispatch minecraft:recipe_serializer[shaped, crafting_shaped] to struct Shaped {
+	pattern: [string],
+}
+
+#[canonical="smelting"]
+dispatch minecraft:recipe_serializer[furnace, smelting] to struct Smelting {
+	ingredient: string,
+}
+`
+
+func TestSchemaConverterDispatchAliases(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", aliasTestSchema)
+
+	tests := []struct {
+		resourceType string
+		document     map[string]interface{}
+		expected     []string
+	}{
+		{"recipe", map[string]interface{}{"type": "minecraft:shaped", "pattern": []interface{}{"a"}}, nil},
+		{"recipe", map[string]interface{}{"type": "crafting_shaped", "pattern": []interface{}{"a"}}, []string{
+			`warning at type: "crafting_shaped" is an alias of "shaped", use the canonical key`,
+		}},
+		{"recipe", map[string]interface{}{"type": "minecraft:furnace", "ingredient": "a"}, []string{
+			`warning at type: "furnace" is an alias of "smelting", use the canonical key`,
+		}},
+		{"recipe", map[string]interface{}{"type": "crafting_shaped"}, []string{
+			"error required field 'pattern' is missing",
+			`warning at type: "crafting_shaped" is an alias of "shaped", use the canonical key`,
+		}},
+		{"holder", map[string]interface{}{"kind": "crafting_shaped", "value": map[string]interface{}{"pattern": []interface{}{}}}, []string{
+			`warning at value: kind "crafting_shaped" is an alias of "shaped", use the canonical key`,
+		}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, finding := range converter.MainValidatorFor(tt.resourceType).Validate(tt.document, ctx) {
+			text := finding.text()
+			if finding.Rule == RuleMissingField {
+				text = finding.Message[:strings.Index(finding.Message, ";")]
+			}
+			got = append(got, string(finding.Severity)+" "+text)
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("Expected %q for %v, got %q", tt.expected, tt.document, got)
+		}
+	}
+}
+
+func TestSchemaConverterUnknownDispatchKeys(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", aliasTestSchema)
+
+	tests := []struct {
+		resourceType string
+		document     map[string]interface{}
+		expected     string
+	}{
+		{"recipe", map[string]interface{}{"type": "minecraft:shapd"}, `warning at type: "shapd" is not a known recipe_serializer, did you mean "shaped"?`},
+		{"recipe", map[string]interface{}{"type": "smeltin"}, `warning at type: "smeltin" is not a known recipe_serializer, did you mean "smelting"?`},
+		{"recipe", map[string]interface{}{"type": "campfire_cooking"}, `warning at type: "campfire_cooking" is not a known recipe_serializer`},
+		{"recipe", map[string]interface{}{"type": "create:mixing"}, `warning at type: "create:mixing" is not a vanilla recipe_serializer; it may come from a mod whose schemas are not loaded`},
+		{"holder", map[string]interface{}{"kind": "shapes", "value": map[string]interface{}{}}, `warning at value: "shapes" is not a known recipe_serializer, did you mean "shaped"?`},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, finding := range converter.MainValidatorFor(tt.resourceType).Validate(tt.document, ctx) {
+			if finding.Rule == RuleUnknownType {
+				got = append(got, string(finding.Severity)+" "+finding.text())
+			}
+		}
+		if strings.Join(got, "\n") != tt.expected {
+			t.Errorf("Expected %q for %v, got %q", tt.expected, tt.document, got)
+		}
+	}
+
+	if closest := closestNames("crafting_shapd", []string{"crafting_shaped", "crafting_shapeless", "smelting"}, 3); strings.Join(closest, " ") != "crafting_shaped" {
+		t.Errorf("Unexpected suggestions %v", closest)
+	}
+}
+
+func TestSchemaConverterMultiKeyDispatch(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", `dispatch minecraft:template_pool_element[
+	legacy_single_pool_element, // until the processors of 1.16
+	"single_pool_element",
+	feature_pool_element,
+] to struct SingleElement {
+	location: string,
+}`)
+
+	cases := converter.Dispatches()["minecraft:template_pool_element"]
+	keys := []string{"legacy_single_pool_element", "single_pool_element", "feature_pool_element"}
+	for _, key := range keys {
+		if cases[key] == nil || cases[key] != cases[keys[0]] {
+			t.Errorf("Expected %s to map to the validator of %s, got %v", key, keys[0], cases[key])
+		}
+		if findings := cases[key].Validate(map[string]interface{}{}, ctx); len(findings) != 1 || findings[0].Rule != RuleMissingField {
+			t.Errorf("Expected a missing location for %s, got %v", key, findings)
+		}
+	}
+	if grouped := dispatchCases(cases); len(grouped) != 1 || strings.Join(grouped[0].Keys, ",") != "feature_pool_element,legacy_single_pool_element,single_pool_element" {
+		t.Errorf("Expected one case for all keys, got %v", grouped)
+	}
+}
+
+func TestSchemaConverterGatedAlternatives(t *testing.T) {
+	schema := `struct IngredientValue {
+	item?: #[id="item"] string,
+	tag?: string,
+}
+
+dispatch minecraft:resource[recipe] to struct Recipe {
+	ingredient: (
+		#[until="1.21.2"] [IngredientValue] |
+		#[until="1.21.2"] IngredientValue |
+		#[since="1.21.2"] #[id="item"] string |
+		#[since="1.21.2"] [#[id="item"] string] |
+	),
+	count: (#[until="1.21.2"] float | #[since="1.21.2"] int),
+}`
+
+	tests := []struct {
+		version  string
+		document string
+		expected string
+	}{
+		{"1.21.1", `{"ingredient": {"item": "stick"}, "count": 1.5}`, ""},
+		{"1.21.2", `{"ingredient": "stick", "count": 1}`, ""},
+		{"1.21.1", `{"ingredient": 1, "count": 1}`, "at ingredient: value does not match any union alternative: at ingredient: expected array, got float64; at ingredient: expected object, got float64"},
+		{"1.21.2", `{"ingredient": {"item": "stick"}, "count": 1}`, "at ingredient: value does not match any union alternative: at ingredient: expected string, got map[string]interface {}; at ingredient: expected array, got map[string]interface {}"},
+		{"1.21.2", `{"ingredient": "stick", "count": 1.5}`, "at count: expected integer, got float"},
+		{"1.21.2", `{"ingredient": "stick", "count": null}`, `at count: null is not allowed here, expected (#[since="1.21.2"] int)`},
+	}
+
+	for _, tt := range tests {
+		converter, ctx := convertSchema(t, tt.version, schema)
+		document, err := decodeJSON([]byte(tt.document))
+		if err != nil {
+			t.Fatalf("Invalid document %s: %v", tt.document, err)
+		}
+		var got []string
+		for _, finding := range converter.MainValidatorFor("recipe").Validate(document, ctx) {
+			got = append(got, finding.text())
+		}
+		if strings.Join(got, "\n") != tt.expected {
+			t.Errorf("%s %s: expected %q, got %q", tt.version, tt.document, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package mcheck
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SchemaIndex records every .mcdoc file below a schema directory with the
+// dispatch keys and types it declares, so that finding the schema of a
+// resource type or the file of a module is a lookup rather than a walk
+// over the directory
+type SchemaIndex struct {
+	Modules    map[string]string            // schema file of each module, like "java/data/trim"
+	Dispatches map[string]map[string]string // schema file declaring each dispatch case, keyed by registry, then key
+	Types      map[string]string            // schema file declaring each top level type, like "java/util/text/Text"
+	Injections map[string][]string          // schema files injecting into each type, keyed like Types
+	Newest     map[string]Version           // newest version named by a #[since] or #[until] of each edition's schemas
+	Bundle     map[string]Version           // version each edition's schemas were written for, if recorded in schemaVersionsFile
+}
+
+// schemaVersionsFile records the versions a schema bundle was written for,
+// one edition per line, like "java 1.20.1". make embed-schemas writes it if
+// given SCHEMA_VERSION, since the attributes of the schemas only name the
+// versions that changed them, which may be older than the bundle.
+const schemaVersionsFile = "mcheck-versions"
+
+var (
+	// indexDispatch matches the head of a dispatch statement, like
+	// dispatch minecraft:resource[trim_material, "trim_pattern"] to, whose
+	// keys may be listed one per line
+	indexDispatch = regexp.MustCompile(`(?m)^dispatch\s+([\w:./-]+)\s*\[([^\]]*)\]`)
+	// indexType matches top level struct, enum and type alias declarations,
+	// and the named structs dispatch statements declare
+	indexType = regexp.MustCompile(`(?m)^(?:struct|type|enum\s*\(\s*\w+\s*\)|dispatch\s+[\w:./-]+\s*\[[^\]]*\]\s*(?:<[^>]*>\s*)?to\s+struct)\s+(\w+)`)
+	// indexComment matches the comments between the keys of a dispatch
+	indexComment = regexp.MustCompile(`//[^\n]*`)
+	// indexInject matches the path of an inject statement, like
+	// inject struct ::java::world::item::ItemStack
+	indexInject = regexp.MustCompile(`(?m)^inject\s+(?:struct|enum\s*\(\s*\w+\s*\))\s+((?:::\s*)?\w+(?:\s*::\s*\w+)*)`)
+	// indexVersion matches the version of a since or until attribute, which
+	// may share its #[...] with others, like #[since="1.21.2", until="1.21.5"]
+	indexVersion = regexp.MustCompile(`\b(?:since|until)\s*=\s*"([0-9.]+)"`)
+)
+
+// BuildSchemaIndex scans the schema files below dir. Declarations are found
+// with a line based scan instead of the parser since only their names are
+// needed, which keeps indexing a full schema tree fast.
+func BuildSchemaIndex(dir string) (*SchemaIndex, error) {
+	return buildSchemaIndex(osFiles, dir)
+}
+
+// buildSchemaIndex scans the schema files below dir in files
+func buildSchemaIndex(files fileSystem, dir string) (*SchemaIndex, error) {
+	index := &SchemaIndex{
+		Modules:    make(map[string]string),
+		Dispatches: make(map[string]map[string]string),
+		Types:      make(map[string]string),
+		Injections: make(map[string][]string),
+		Newest:     make(map[string]Version),
+		Bundle:     make(map[string]Version),
+	}
+	if content, err := files.ReadFile(filepath.Join(dir, schemaVersionsFile)); err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				continue
+			}
+			if version, err := ParseVersion(fields[1]); err == nil {
+				index.Bundle[fields[0]] = version
+			}
+		}
+	}
+	err := files.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		// Entries that cannot be walked or read are left out of the index,
+		// and the schemas they hold are reported where they are used
+		if err != nil && entry != nil {
+			return nil
+		}
+		if err != nil || entry.IsDir() || filepath.Ext(path) != ".mcdoc" {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		module := strings.TrimSuffix(filepath.ToSlash(rel), ".mcdoc")
+		if module == "mod" {
+			module = ""
+		} else {
+			module = strings.TrimSuffix(module, "/mod")
+		}
+		// <module>.mcdoc wins over <module>/mod.mcdoc, as in moduleFile
+		if _, exists := index.Modules[module]; !exists || !strings.HasSuffix(filepath.ToSlash(rel), "/mod.mcdoc") {
+			index.Modules[module] = path
+		}
+
+		content, err := files.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		index.add(module, path, string(content))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// merge adds the declarations of another index that this one does not
+// have, like those of the schemas of mods. The versions the other index
+// names are left out, since they say nothing of these schemas.
+func (index *SchemaIndex) merge(other *SchemaIndex) {
+	for module, file := range other.Modules {
+		if _, exists := index.Modules[module]; !exists {
+			index.Modules[module] = file
+		}
+	}
+	for registry, cases := range other.Dispatches {
+		if index.Dispatches[registry] == nil {
+			index.Dispatches[registry] = make(map[string]string)
+		}
+		for key, file := range cases {
+			if _, exists := index.Dispatches[registry][key]; !exists {
+				index.Dispatches[registry][key] = file
+			}
+		}
+	}
+	for name, file := range other.Types {
+		if _, exists := index.Types[name]; !exists {
+			index.Types[name] = file
+		}
+	}
+	for name, files := range other.Injections {
+		index.Injections[name] = append(index.Injections[name], files...)
+	}
+}
+
+// add records the declarations of a schema file
+func (index *SchemaIndex) add(module, path, content string) {
+	for _, match := range indexDispatch.FindAllStringSubmatch(content, -1) {
+		registry := match[1]
+		if index.Dispatches[registry] == nil {
+			index.Dispatches[registry] = make(map[string]string)
+		}
+		for _, key := range strings.Split(indexComment.ReplaceAllString(match[2], ""), ",") {
+			key = strings.Trim(strings.TrimSpace(key), `"`)
+			if _, exists := index.Dispatches[registry][key]; key != "" && !exists {
+				index.Dispatches[registry][key] = path
+			}
+		}
+	}
+	for _, match := range indexType.FindAllStringSubmatch(content, -1) {
+		index.Types[strings.TrimPrefix(module+"/"+match[1], "/")] = path
+	}
+	for _, match := range indexInject.FindAllStringSubmatch(content, -1) {
+		var current []string
+		if module != "" {
+			current = strings.Split(module, "/")
+		}
+		target := parsePath(match[1])
+		if targetModule, ok := resolveModule(current, target); ok {
+			key := strings.Join(append(targetModule, target.Segments[len(target.Segments)-1].Value), "/")
+			index.Injections[key] = append(index.Injections[key], path)
+		}
+	}
+	edition, _, _ := strings.Cut(module, "/")
+	for _, match := range indexVersion.FindAllStringSubmatch(content, -1) {
+		if version, err := ParseVersion(match[1]); err == nil && version.Compare(index.Newest[edition]) > 0 {
+			index.Newest[edition] = version
+		}
+	}
+}
+
+// Version returns the version the schemas of an edition were written for:
+// the newer of the one recorded for their bundle and the newest version
+// their #[since] and #[until] attributes name, since schemas edited after
+// the bundle was recorded cover the versions they name
+func (index *SchemaIndex) Version(edition string) (Version, bool) {
+	bundle, recorded := index.Bundle[edition]
+	newest, named := index.Newest[edition]
+	if !named || (recorded && bundle.Compare(newest) > 0) {
+		return bundle, recorded
+	}
+	return newest, true
+}
+
+// Predates reports whether the schemas of an edition were written before
+// the target version, and returns the version they were written for.
+// Schemas with no recorded version that name none cannot be told apart and
+// are taken to be current.
+func (index *SchemaIndex) Predates(edition string, target Version) (Version, bool) {
+	version, ok := index.Version(edition)
+	return version, ok && target.Compare(version) > 0
+}
+
+// DispatcherFiles returns the schema files declaring cases of a dispatcher,
+// like minecraft:feature_config, in order
+func (index *SchemaIndex) DispatcherFiles(registry string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, file := range index.Dispatches[registry] {
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// DispatchFile returns the schema file declaring a dispatch case, like
+// trim.mcdoc for minecraft:resource[trim_pattern], or ""
+func (index *SchemaIndex) DispatchFile(registry, key string) string {
+	return index.Dispatches[registry][key]
+}
+
+// ModuleFile returns the schema file of a module, or ""
+func (index *SchemaIndex) ModuleFile(module []string) string {
+	return index.Modules[strings.Join(module, "/")]
+}
+
+// InjectionFiles returns the schema files injecting into a type of a module
+func (index *SchemaIndex) InjectionFiles(module []string, name string) []string {
+	return index.Injections[strings.Join(append(append([]string(nil), module...), name), "/")]
+}
+
+// TypeFile returns the schema file declaring a type in a module, or ""
+func (index *SchemaIndex) TypeFile(module []string, name string) string {
+	return index.Types[strings.Join(append(append([]string(nil), module...), name), "/")]
+}
@@ -0,0 +1,160 @@
+package mcheck
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSchemaIndex(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"java/data/trim.mcdoc": `dispatch minecraft:resource[trim_material] to struct TrimMaterial {}
+
+#[since="1.20"]
+dispatch minecraft:resource[trim_pattern, "smithing/pattern"] to struct TrimPattern {}
+`,
+		"java/data/worldgen/mod.mcdoc": `enum(string) Carvers {
+	Cave = "cave",
+}
+
+type Weighted<T> = struct { data: T }
+`,
+		"java/data/loot/mod.mcdoc":   `struct FromMod {}`,
+		"java/data/loot.mcdoc":       `struct FromFile {}`,
+		"java/util/text.mcdoc":       "struct Indented {\n\tstruct Nested {}\n}\n",
+		"java/util/README.md":        `struct Ignored {}`,
+		"bedrock/entity/mod.mcdoc":   `dispatch minecraft:resource[entities] to struct Entity {}`,
+		"java/data/worldgen/x.mcdoc": `dispatch minecraft:resource[trim_material] to struct Duplicate {}`,
+		"java/mods/extra.mcdoc": `inject struct super::super::data::trim::TrimPattern {}
+
+inject enum(string) :: java::data::worldgen::Carvers {
+	Ravine = "ravine",
+}
+`,
+		"java/data/pool.mcdoc": `dispatch minecraft:pool_element[
+	legacy_single_pool_element, // before 1.17
+	single_pool_element,
+] to struct SingleElement {}
+`,
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	index, err := BuildSchemaIndex(dir)
+	if err != nil {
+		t.Fatalf("BuildSchemaIndex failed: %v", err)
+	}
+	file := func(name string) string {
+		return filepath.Join(dir, filepath.FromSlash(name))
+	}
+
+	for _, tt := range []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"dispatch", index.DispatchFile("minecraft:resource", "trim_pattern"), file("java/data/trim.mcdoc")},
+		{"quoted dispatch key", index.DispatchFile("minecraft:resource", "smithing/pattern"), file("java/data/trim.mcdoc")},
+		{"first dispatch wins", index.DispatchFile("minecraft:resource", "trim_material"), file("java/data/trim.mcdoc")},
+		{"multiline dispatch keys", index.DispatchFile("minecraft:pool_element", "legacy_single_pool_element"), file("java/data/pool.mcdoc")},
+		{"key after a comment", index.DispatchFile("minecraft:pool_element", "single_pool_element"), file("java/data/pool.mcdoc")},
+		{"other edition", index.DispatchFile("minecraft:resource", "entities"), file("bedrock/entity/mod.mcdoc")},
+		{"unknown dispatch", index.DispatchFile("minecraft:resource", "recipe"), ""},
+		{"module file", index.ModuleFile([]string{"java", "util", "text"}), file("java/util/text.mcdoc")},
+		{"mod.mcdoc module", index.ModuleFile([]string{"java", "data", "worldgen"}), file("java/data/worldgen/mod.mcdoc")},
+		{"file wins over mod.mcdoc", index.ModuleFile([]string{"java", "data", "loot"}), file("java/data/loot.mcdoc")},
+		{"enum", index.TypeFile([]string{"java", "data", "worldgen"}, "Carvers"), file("java/data/worldgen/mod.mcdoc")},
+		{"generic alias", index.TypeFile([]string{"java", "data", "worldgen"}, "Weighted"), file("java/data/worldgen/mod.mcdoc")},
+		{"dispatched struct", index.TypeFile([]string{"java", "data", "trim"}, "TrimPattern"), file("java/data/trim.mcdoc")},
+		{"struct of multiline dispatch", index.TypeFile([]string{"java", "data", "pool"}, "SingleElement"), file("java/data/pool.mcdoc")},
+		{"nested types are not exported", index.TypeFile([]string{"java", "util", "text"}, "Nested"), ""},
+		{"other files are skipped", index.TypeFile([]string{"java", "util"}, "Ignored"), ""},
+		{"struct injection", strings.Join(index.InjectionFiles([]string{"java", "data", "trim"}, "TrimPattern"), ","), file("java/mods/extra.mcdoc")},
+		{"enum injection", strings.Join(index.InjectionFiles([]string{"java", "data", "worldgen"}, "Carvers"), ","), file("java/mods/extra.mcdoc")},
+		{"injections are not declarations", index.TypeFile([]string{"java", "mods", "extra"}, "TrimPattern"), ""},
+	} {
+		if tt.got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, tt.got)
+		}
+	}
+}
+
+func TestSchemaIndexPredates(t *testing.T) {
+	index := &SchemaIndex{
+		Dispatches: make(map[string]map[string]string),
+		Types:      make(map[string]string),
+		Newest:     make(map[string]Version),
+	}
+	index.add("java/data/trim", "trim.mcdoc", `struct TrimMaterial {
+	#[since="1.20"]
+	asset_name: string,
+	#[until="1.21.4"]
+	ingredient: string,
+	#[since="1.21.2", until="1.21.5"]
+	override_armor_materials?: struct {},
+}`)
+	index.add("bedrock/entity", "entity.mcdoc", `#[since="1.21.50"] struct Entity {}`)
+
+	if newest := index.Newest["java"]; newest != (Version{1, 21, 5}) {
+		t.Errorf("Expected the newest java version to be 1.21.5, got %v", newest)
+	}
+	if _, predate := index.Predates("java", Version{1, 21, 5}); predate {
+		t.Error("Expected schemas naming 1.21.5 not to predate it")
+	}
+	if newest, predate := index.Predates("java", Version{1, 21, 6}); !predate || newest != (Version{1, 21, 5}) {
+		t.Errorf("Expected schemas up to 1.21.5 to predate 1.21.6, got %v, %v", newest, predate)
+	}
+	if _, predate := index.Predates("bedrock", Version{1, 21, 6}); predate {
+		t.Error("Expected each edition to have versions of its own")
+	}
+	if _, predate := (&SchemaIndex{}).Predates("java", Version{1, 21, 6}); predate {
+		t.Error("Expected schemas naming no versions to be taken as current")
+	}
+}
+
+func TestSchemaIndexPredatesBundle(t *testing.T) {
+	// Nothing changed the schemas after 1.19.4, but the bundle was made for
+	// 1.20.1
+	schemas := newFileSystem("vanilla-mcdoc", fstest.MapFS{
+		schemaVersionsFile:         {Data: []byte("java 1.20.1\n")},
+		"java/data/trim/mod.mcdoc": {Data: []byte(`#[since="1.19.4"] struct Trim {}`)},
+	})
+	index, err := buildSchemaIndex(schemas, "vanilla-mcdoc")
+	if err != nil {
+		t.Fatalf("buildSchemaIndex failed: %v", err)
+	}
+	if newest := index.Newest["java"]; newest != (Version{1, 19, 4}) {
+		t.Errorf("Expected the newest java version named to be 1.19.4, got %v", newest)
+	}
+	if _, predate := index.Predates("java", Version{1, 20, 1}); predate {
+		t.Error("Expected schemas bundled for 1.20.1 not to predate it")
+	}
+	if version, predate := index.Predates("java", Version{1, 20, 2}); !predate || version != (Version{1, 20, 1}) {
+		t.Errorf("Expected schemas bundled for 1.20.1 to predate 1.20.2, got %v, %v", version, predate)
+	}
+
+	// Schemas edited after the bundle was recorded cover the versions they
+	// name
+	schemas = newFileSystem("vanilla-mcdoc", fstest.MapFS{
+		schemaVersionsFile:         {Data: []byte("java 1.20.1\n")},
+		"java/data/trim/mod.mcdoc": {Data: []byte(`#[since="1.21.2"] struct Trim {}`)},
+	})
+	if index, err = buildSchemaIndex(schemas, "vanilla-mcdoc"); err != nil {
+		t.Fatalf("buildSchemaIndex failed: %v", err)
+	}
+	if version, ok := index.Version("java"); !ok || version != (Version{1, 21, 2}) {
+		t.Errorf("Expected the schemas to be written for 1.21.2, got %v, %v", version, ok)
+	}
+	if _, predate := index.Predates("java", Version{1, 21, 2}); predate {
+		t.Error("Expected schemas naming 1.21.2 not to predate it")
+	}
+}
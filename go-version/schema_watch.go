@@ -0,0 +1,96 @@
+package mcheck
+
+import (
+	"context"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// schemaPollInterval is how often the daemon and the language server look
+// for schema files that were edited
+const schemaPollInterval = time.Second
+
+// fileStamp tells whether a file changed between two looks at it
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// schemaWatcher finds the schema files below a directory that were added,
+// removed or modified since it last looked. It polls rather than asking the
+// operating system to notify it, which works the same everywhere and is
+// cheap for the few thousand files of vanilla-mcdoc.
+type schemaWatcher struct {
+	dir   string
+	files map[string]fileStamp
+}
+
+// newSchemaWatcher looks at the schema files below dir for the first time
+func newSchemaWatcher(dir string) *schemaWatcher {
+	return &schemaWatcher{dir: dir, files: scanSchemaFiles(dir)}
+}
+
+// scanSchemaFiles stamps the .mcdoc files below dir. Files that cannot be
+// read are left out, and found changed once they can be.
+func scanSchemaFiles(dir string) map[string]fileStamp {
+	files := make(map[string]fileStamp)
+	osFiles.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || filepath.Ext(path) != ".mcdoc" {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			files[filepath.Clean(path)] = fileStamp{info.ModTime(), info.Size()}
+		}
+		return nil
+	})
+	return files
+}
+
+// changes returns the schema files changed since the last look, in order
+func (w *schemaWatcher) changes() []string {
+	files := scanSchemaFiles(w.dir)
+	var changed []string
+	for file, stamp := range files {
+		if old, ok := w.files[file]; !ok || !old.modTime.Equal(stamp.modTime) || old.size != stamp.size {
+			changed = append(changed, file)
+		}
+	}
+	for file := range w.files {
+		if _, ok := files[file]; !ok {
+			changed = append(changed, file)
+		}
+	}
+	w.files = files
+	sort.Strings(changed)
+	return changed
+}
+
+// watchSchemas looks for changed schema files below dir every interval
+// until ctx is done, passing them to reload
+func watchSchemas(ctx context.Context, dir string, interval time.Duration, reload func(changed []string)) {
+	watcher := newSchemaWatcher(dir)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if changed := watcher.changes(); len(changed) > 0 {
+				reload(changed)
+			}
+		}
+	}
+}
+
+// logReload logs the schemas reloaded after files changed
+func logReload(changed []string, dropped int) {
+	if len(changed) == 1 {
+		log.Printf("%s changed, reloading %d schemas", changed[0], dropped)
+		return
+	}
+	log.Printf("%d schema files changed, reloading %d schemas", len(changed), dropped)
+}
@@ -0,0 +1,45 @@
+package mcheck
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSchemaWatcher(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	kept := write("kept.mcdoc", "struct Kept {}")
+	edited := write("edited.mcdoc", "struct Edited {}")
+	removed := write("removed.mcdoc", "struct Removed {}")
+	watcher := newSchemaWatcher(dir)
+
+	if changed := watcher.changes(); len(changed) != 0 {
+		t.Errorf("Expected no changes yet, got %v", changed)
+	}
+
+	write("edited.mcdoc", "struct Edited { a: int }")
+	added := write("added.mcdoc", "struct Added {}")
+	write("notes.txt", "not a schema")
+	if err := os.Remove(removed); err != nil {
+		t.Fatal(err)
+	}
+	changed := watcher.changes()
+	if expected := []string{added, edited, removed}; !slices.Equal(changed, expected) {
+		t.Errorf("Expected %v to have changed, got %v", expected, changed)
+	}
+	if slices.Contains(changed, kept) {
+		t.Errorf("Expected %s to be unchanged", kept)
+	}
+	if changed := watcher.changes(); len(changed) != 0 {
+		t.Errorf("Expected the changes to be reported once, got %v", changed)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build embedschemas
+
+package mcheck
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:schemas
+var schemaFiles embed.FS
+
+func init() {
+	embeddedSchemas, _ = fs.Sub(schemaFiles, "schemas")
+}
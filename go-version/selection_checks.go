@@ -0,0 +1,61 @@
+package mcheck
+
+// checkBiomeSelections warns about biomes whose feature and spawner lists
+// are all empty. The schema allows them, but a biome that places no
+// features, or sets spawn costs while spawning no mobs, is almost always a
+// list that was emptied by mistake.
+func checkBiomeSelections(value map[string]interface{}) []Finding {
+	var findings []Finding
+	if features, ok := value["features"].([]interface{}); ok && allEmpty(features) {
+		findings = append(findings, Finding{
+			Path:     []string{"features"},
+			Severity: SeverityWarning,
+			Message:  "biome places no features, every generation step is empty",
+			Rule:     RuleEmptySelection,
+		})
+	}
+
+	spawners, hasSpawners := value["spawners"].(map[string]interface{})
+	costs, _ := value["spawn_costs"].(map[string]interface{})
+	if hasSpawners && len(costs) > 0 {
+		categories := make([]interface{}, 0, len(spawners))
+		for _, category := range spawners {
+			categories = append(categories, category)
+		}
+		if allEmpty(categories) {
+			findings = append(findings, Finding{
+				Path:     []string{"spawners"},
+				Severity: SeverityWarning,
+				Message:  "biome sets spawn costs but spawns no mobs, every spawner category is empty",
+				Rule:     RuleEmptySelection,
+			})
+		}
+	}
+	return findings
+}
+
+// checkSpawnPotentials warns about trial spawners given an empty list of
+// entities to spawn, which leaves them without anything to spawn
+func checkSpawnPotentials(value map[string]interface{}) []Finding {
+	if potentials, ok := value["spawn_potentials"].([]interface{}); ok && len(potentials) == 0 {
+		return []Finding{{
+			Path:     []string{"spawn_potentials"},
+			Severity: SeverityWarning,
+			Message:  "spawn_potentials is empty, so the spawner has nothing to spawn; omit it or add an entity",
+			Rule:     RuleEmptySelection,
+		}}
+	}
+	return nil
+}
+
+// allEmpty reports whether every element of a list is itself an empty list.
+// Elements of other types, like tag references in place of a list, count as
+// content.
+func allEmpty(lists []interface{}) bool {
+	for _, list := range lists {
+		if elements, ok := list.([]interface{}); !ok || len(elements) > 0 {
+			return false
+		}
+	}
+	return true
+}
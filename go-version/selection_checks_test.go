@@ -0,0 +1,90 @@
+package mcheck
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSelectionChecks(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType string
+		document     string
+		expected     []string
+	}{
+		{
+			"biome with features", "worldgen/biome",
+			`{"features": [[], ["minecraft:void_start_platform"]], "spawners": {"monster": []}, "spawn_costs": {}}`,
+			nil,
+		},
+		{
+			"feature tag step", "worldgen/biome",
+			`{"features": [[], "#minecraft:ores"]}`,
+			nil,
+		},
+		{
+			"empty feature steps", "worldgen/biome",
+			`{"features": [[], [], []]}`,
+			[]string{": warning: at features: biome places no features, every generation step is empty [MCHECK055 empty-selection]"},
+		},
+		{
+			"spawn costs without spawners", "worldgen/biome",
+			`{"features": [["minecraft:ore_coal"]], "spawners": {"monster": [], "creature": []}, "spawn_costs": {"minecraft:ghast": {"energy_budget": 0.7, "charge": 0.15}}}`,
+			[]string{": warning: at spawners: biome sets spawn costs but spawns no mobs, every spawner category is empty [MCHECK055 empty-selection]"},
+		},
+		{
+			"empty spawn potentials", "trial_spawner",
+			`{"spawn_potentials": []}`,
+			[]string{": warning: at spawn_potentials: spawn_potentials is empty, so the spawner has nothing to spawn; omit it or add an entity [MCHECK055 empty-selection]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := runFileChecks(t, tt.resourceType, tt.document)
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestEmptyLengthConstraints(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", `dispatch minecraft:resource[thing] to struct Thing {
+	pools?: [string] @ 1..,
+	name?: string @ 1..16,
+	entries?: [[int] @ 1..],
+	optional?: [string] @ 0..4,
+}
+`)
+	validator := converter.MainValidatorFor("thing")
+
+	tests := []struct {
+		document string
+		error    string
+	}{
+		{`{"pools": []}`, "at pools: pools must not be empty (length 1..) [MCHECK009 invalid-length]"},
+		{`{"name": ""}`, "at name: name must not be empty (length 1..16)"},
+		{`{"entries": [[1], []]}`, "at entries.[1]: list must not be empty (length 1..)"},
+		{`{"optional": []}`, ""},
+		{`{"pools": ["a"], "name": "a"}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.document, func(t *testing.T) {
+			document, err := decodeJSON([]byte(tt.document))
+			if err != nil {
+				t.Fatalf("Failed to decode document: %v", err)
+			}
+			findings := validator.Validate(document, ctx)
+			failed, ok := firstError(findings)
+			switch {
+			case tt.error == "" && ok:
+				t.Errorf("Expected valid, got %v", findings)
+			case tt.error != "" && (!ok || !strings.Contains(failed.String(), tt.error)):
+				t.Errorf("Expected error containing %q, got %v", tt.error, findings)
+			}
+		})
+	}
+}
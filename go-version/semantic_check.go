@@ -0,0 +1,97 @@
+package mcheck
+
+import "fmt"
+
+// SemanticCheck is a rule run on every file that passes schema validation,
+// for checks the schemas cannot express, like the naming conventions of a
+// datapack style guide. Programs embedding mcheck register their checks with
+// RegisterSemanticCheck, and their findings are reported next to those of the
+// built-in checks.
+type SemanticCheck interface {
+	// Rule describes the findings of the check. Its id and name must differ
+	// from those of the built-in rules and other registered checks.
+	Rule() RuleInfo
+
+	// Check returns the findings about a document. Findings are reported for
+	// the file of the document; those without a rule get the rule of the
+	// check and those without a severity are errors.
+	Check(doc *SemanticDocument) []Finding
+}
+
+// SemanticDocument is a file handed to semantic checks
+type SemanticDocument struct {
+	Path         string      // path of the file on disk
+	Name         string      // name findings are reported for, like data/ns/worldgen/biome/x.json
+	ResourceType string      // like worldgen/biome
+	Value        interface{} // the decoded JSON document
+	Version      Version     // version the file is checked for
+
+	// Type is the schema type of the document and Context the context it
+	// was validated in, which resolves references and dispatches within Type
+	Type    Validator
+	Context *ValidationContext
+
+	// Pack indexes the resources of the pack the file belongs to. It is nil
+	// when the file is checked on its own rather than as part of a pack.
+	Pack *Pack
+}
+
+// semanticChecks are the registered semantic checks
+var semanticChecks []SemanticCheck
+
+// RegisterSemanticCheck adds a check run on every file checked, and lists its
+// rule with the built-in rules. Like the built-in rules, checks are fixed
+// once checking starts, so register them from an init function or before
+// creating validators. It panics if the rule has no id or name or collides
+// with a known rule, since that is a mistake in the embedding program.
+func RegisterSemanticCheck(check SemanticCheck) {
+	rule := check.Rule()
+	if rule.ID == "" || rule.Name == "" {
+		panic("mcheck: semantic check rule needs an id and a name")
+	}
+	if _, exists := LookupRule(rule.ID); exists {
+		panic(fmt.Sprintf("mcheck: rule %s is already defined", rule.ID))
+	}
+	if _, exists := LookupRule(rule.Name); exists {
+		panic(fmt.Sprintf("mcheck: rule %s is already defined", rule.Name))
+	}
+	semanticChecks = append(semanticChecks, check)
+	Rules = append(Rules, rule)
+}
+
+// registeredFindings runs the registered semantic checks on a document that
+// passed schema validation
+func (v *PEGMCDocValidator) registeredFindings(path, name, resourceType string, value interface{}, pack *Pack) []Finding {
+	if len(semanticChecks) == 0 {
+		return nil
+	}
+	converter, mainValidator, err := v.loadSchemaFor(path)
+	if err != nil {
+		return nil
+	}
+	doc := &SemanticDocument{
+		Path:         path,
+		Name:         name,
+		ResourceType: resourceType,
+		Value:        value,
+		Version:      v.targetVersion,
+		Type:         mainValidator,
+		Context:      v.newContext(converter),
+		Pack:         pack,
+	}
+
+	var findings []Finding
+	for _, check := range semanticChecks {
+		rule := check.Rule()
+		for _, finding := range check.Check(doc) {
+			if finding.Rule == "" {
+				finding.Rule = rule.ID
+			}
+			if finding.Severity == "" {
+				finding.Severity = SeverityError
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
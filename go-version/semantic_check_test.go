@@ -0,0 +1,108 @@
+package mcheck
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// messageIDStyle is a style guide rule as a program embedding mcheck would
+// register it: damage type message ids must be snake_case
+type messageIDStyle struct {
+	checked []*SemanticDocument
+}
+
+func (c *messageIDStyle) Rule() RuleInfo {
+	return RuleInfo{ID: "STYLE001", Name: "message-id-style", Description: "A damage type message id is not snake_case"}
+}
+
+func (c *messageIDStyle) Check(doc *SemanticDocument) []Finding {
+	if doc.ResourceType != "damage_type" {
+		return nil
+	}
+	c.checked = append(c.checked, doc)
+	document, _ := doc.Value.(map[string]interface{})
+	messageID, _ := document["message_id"].(string)
+	if strings.IndexFunc(messageID, unicode.IsUpper) < 0 {
+		return nil
+	}
+	return []Finding{{Path: []string{"message_id"}, Severity: SeverityWarning, Message: "message id " + messageID + " is not snake_case"}}
+}
+
+// registerTestCheck registers a check for the duration of a test
+func registerTestCheck(t *testing.T, check SemanticCheck) {
+	t.Helper()
+	savedChecks, savedRules := semanticChecks, Rules
+	t.Cleanup(func() { semanticChecks, Rules = savedChecks, savedRules })
+	Rules = append([]RuleInfo{}, Rules...)
+	RegisterSemanticCheck(check)
+}
+
+func TestSemanticCheck(t *testing.T) {
+	check := &messageIDStyle{}
+	registerTestCheck(t, check)
+
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/damage_type/trap.json":  []byte(`{"message_id": "spikeTrap", "exhaustion": 0.1, "scaling": "never"}`),
+		"data/test/damage_type/acid.json":  []byte(`{"message_id": "acid", "exhaustion": 0.1, "scaling": "never"}`),
+		"data/test/damage_type/float.json": []byte(`{"message_id": "Float", "exhaustion": -1, "scaling": "never"}`),
+	})
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, fixtureSchemaDir(t, "damage_type"))
+	findings, err := validator.CheckPack(context.Background(), root, nil, PackOptions{})
+	if err != nil {
+		t.Fatalf("CheckPack failed: %v", err)
+	}
+
+	var messages []string
+	for _, finding := range findings {
+		messages = append(messages, finding.String())
+	}
+	expected := []string{
+		"data/test/damage_type/float.json: at exhaustion: value -1 must be greater than or equal to 0 (range 0..) [MCHECK010 out-of-range]",
+		"data/test/damage_type/trap.json: warning: at message_id: message id spikeTrap is not snake_case [STYLE001 message-id-style]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+
+	// Files failing schema validation are not handed to the check
+	if len(check.checked) != 2 {
+		t.Fatalf("Expected 2 checked documents, got %d", len(check.checked))
+	}
+	for _, doc := range check.checked {
+		if doc.Pack == nil || doc.Pack.Root != root {
+			t.Errorf("Expected the pack index for %s", doc.Name)
+		}
+		if doc.Version != (Version{1, 20, 1}) {
+			t.Errorf("Expected version 1.20.1, got %s", doc.Version)
+		}
+		if _, ok := concreteValidator(doc.Type, doc.Value, doc.Context).(*StructValidator); !ok {
+			t.Errorf("Expected the damage type struct, got %s", DescribeType(doc.Type))
+		}
+	}
+
+	// Files checked on their own have no pack
+	check.checked = nil
+	file := filepath.Join(root, "data", "test", "damage_type", "trap.json")
+	if findings := validator.CheckFile(file, "trap.json"); len(findings) != 1 || findings[0].Rule != "STYLE001" {
+		t.Errorf("Expected the style warning, got %v", findings)
+	}
+	if len(check.checked) != 1 || check.checked[0].Pack != nil {
+		t.Errorf("Expected a document without pack, got %v", check.checked)
+	}
+	if rule, ok := LookupRule("message-id-style"); !ok || rule.ID != "STYLE001" {
+		t.Errorf("Expected the rule to be listed, got %v", rule)
+	}
+}
+
+func TestRegisterSemanticCheckCollision(t *testing.T) {
+	registerTestCheck(t, &messageIDStyle{})
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering the rule twice to panic")
+		}
+	}()
+	RegisterSemanticCheck(&messageIDStyle{})
+}
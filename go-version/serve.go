@@ -0,0 +1,73 @@
+package mcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxRequestSize bounds the documents accepted by the validation server
+const maxRequestSize = 64 << 20
+
+// validationResponse is the body of a /validate response
+type validationResponse struct {
+	Valid          bool      `json:"valid"`
+	Findings       []Finding `json:"findings"`
+	SchemaProblems []Finding `json:"schema_problems,omitempty"` // kept the document from being checked
+}
+
+// NewValidationHandler serves POST /validate?path=<pack-path>, checking the
+// request body as the file at path within a datapack. The response lists the
+// findings and whether any of them is an error, and separately the schema
+// problems that kept the document from being checked, which do not make it
+// invalid. A check still running after timeout, if it is above zero, is
+// given up on with 503 Service Unavailable.
+func NewValidationHandler(validator *PEGMCDocValidator, timeout time.Duration) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/validate", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "validate requires POST", http.StatusMethodNotAllowed)
+			return
+		}
+		packPath := path.Clean("/" + r.URL.Query().Get("path"))[1:]
+		if !strings.HasSuffix(packPath, ".json") {
+			http.Error(w, "path must name a JSON file within a datapack, like data/minecraft/worldgen/biome/plains.json", http.StatusBadRequest)
+			return
+		}
+		content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		ctx := r.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		checked, err := validator.CheckDocumentContext(ctx, filepath.FromSlash(packPath), packPath, content)
+		if errors.Is(err, context.DeadlineExceeded) {
+			http.Error(w, fmt.Sprintf("validation took longer than %s", timeout), http.StatusServiceUnavailable)
+			return
+		} else if err != nil {
+			// The client has gone away
+			return
+		}
+		findings, schemaProblems := splitFindings(checked)
+		if findings == nil {
+			findings = []Finding{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(validationResponse{Valid: !hasErrors(findings), Findings: findings, SchemaProblems: schemaProblems})
+	})
+	return mux
+}
@@ -0,0 +1,118 @@
+package mcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestValidationHandler(t *testing.T) {
+	targetVersion, _ := ParseVersion("1.20.1")
+	server := httptest.NewServer(NewValidationHandler(NewPEGMCDocValidator(targetVersion, fixtureSchemaDir(t, "damage_type")), 0))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		status   int
+		valid    bool
+		findings []string
+	}{
+		{"valid", "data/test/damage_type/fall.json", `{"message_id": "fall", "exhaustion": 0, "scaling": "never"}`, http.StatusOK, true, nil},
+		{"invalid", "data/test/damage_type/fall.json", `{"exhaustion": 0, "scaling": "never"}`, http.StatusOK, false,
+			[]string{"data/test/damage_type/fall.json: required field 'message_id' is missing; required by damage_type.mcdoc:7 [MCHECK002 missing-field]"}},
+		{"escaping the pack", "../../data/test/damage_type/fall.json", `{"message_id": "fall", "exhaustion": 0, "scaling": "never"}`, http.StatusOK, true, nil},
+		{"not json", "data/test/damage_type/fall.txt", `{}`, http.StatusBadRequest, false, nil},
+		{"no schema", "data/test/worldgen/biome/plains.json", `{}`, http.StatusOK, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := http.Post(server.URL+"/validate?path="+tt.path, "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			defer response.Body.Close()
+			if response.StatusCode != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, response.StatusCode)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var result validationResponse
+			if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+				t.Fatalf("Invalid response: %v", err)
+			}
+			if result.Valid != tt.valid {
+				t.Errorf("Expected valid %v, got %v", tt.valid, result.Valid)
+			}
+			var findings []string
+			for _, finding := range result.Findings {
+				findings = append(findings, finding.String())
+			}
+			if strings.Join(findings, "\n") != strings.Join(tt.findings, "\n") {
+				t.Errorf("Expected findings %v, got %v", tt.findings, findings)
+			}
+			if noSchema := tt.name == "no schema"; noSchema != (len(result.SchemaProblems) > 0) {
+				t.Errorf("Expected schema problems only without a schema, got %v", result.SchemaProblems)
+			}
+		})
+	}
+
+	response, err := http.Get(server.URL + "/validate?path=data/test/damage_type/fall.json")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected GET to be rejected, got status %d", response.StatusCode)
+	}
+}
+
+// blockingFS blocks reading files until release is closed
+type blockingFS struct {
+	fstest.MapFS
+	release chan struct{}
+}
+
+func (fsys blockingFS) ReadFile(name string) ([]byte, error) {
+	<-fsys.release
+	return fsys.MapFS.ReadFile(name)
+}
+
+func TestValidationHandlerTimeout(t *testing.T) {
+	schema, err := os.ReadFile(filepath.Join("tests", "mcdocs", "damage_type.mcdoc"))
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	schemas := blockingFS{MapFS: fstest.MapFS{"java/data/damage_type.mcdoc": {Data: schema}}, release: make(chan struct{})}
+	defer close(schemas.release)
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, filepath.Join(t.TempDir(), "vanilla-mcdoc"))
+	validator.SetSchemaFS(schemas)
+
+	// Loading the schema never ends while the request is handled, so the
+	// handler answers by giving up on it
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/validate?path=data/test/damage_type/fall.json", strings.NewReader(`{"message_id": "fall", "exhaustion": 0, "scaling": "never"}`))
+	handled := make(chan struct{})
+	go func() {
+		NewValidationHandler(validator, 10*time.Millisecond).ServeHTTP(recorder, request)
+		close(handled)
+	}()
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the handler to give up on the request after its timeout")
+	}
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusServiceUnavailable, recorder.Code, recorder.Body)
+	}
+}
@@ -0,0 +1,207 @@
+package mcheck
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseSNBT parses stringified NBT, the text form of NBT that commands take,
+// like {Items:[{id:"minecraft:stick",count:1b}]}. Values decode like binary
+// NBT read by ReadNBT: compounds to maps, lists and arrays to slices, numbers
+// of every type to float64, and true and false to the bytes 1 and 0.
+func parseSNBT(text string) (interface{}, error) {
+	p := &snbtParser{text: text}
+	value, err := p.value(0)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.text) {
+		return nil, p.errorf("unexpected %q after the value", p.text[p.pos:])
+	}
+	return value, nil
+}
+
+// snbtParser reads a value from text at pos
+type snbtParser struct {
+	text string
+	pos  int
+}
+
+func (p *snbtParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("at column %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *snbtParser) skipSpace() {
+	for p.pos < len(p.text) && strings.IndexByte(" \t\r\n", p.text[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// peek returns the next character after any spaces, or 0 at the end
+func (p *snbtParser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.text) {
+		return p.text[p.pos]
+	}
+	return 0
+}
+
+// expect consumes the next character after any spaces, which must be c
+func (p *snbtParser) expect(c byte) error {
+	if p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *snbtParser) value(depth int) (interface{}, error) {
+	if depth > maxNBTDepth {
+		return nil, p.errorf("NBT nested deeper than %d", maxNBTDepth)
+	}
+	switch p.peek() {
+	case '{':
+		return p.compound(depth)
+	case '[':
+		return p.list(depth)
+	case '"', '\'':
+		return p.quoted()
+	case 0:
+		return nil, p.errorf("expected a value")
+	}
+	word := p.word()
+	if word == "" {
+		return nil, p.errorf("unexpected %q", p.text[p.pos])
+	}
+	return snbtScalar(word), nil
+}
+
+func (p *snbtParser) compound(depth int) (interface{}, error) {
+	p.pos++ // {
+	result := make(map[string]interface{})
+	if p.peek() == '}' {
+		p.pos++
+		return result, nil
+	}
+	for {
+		var key string
+		if c := p.peek(); c == '"' || c == '\'' {
+			quoted, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			key = quoted
+		} else if key = p.word(); key == "" {
+			return nil, p.errorf("expected a key")
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		value, err := p.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return result, nil
+		default:
+			return nil, p.errorf("expected ',' or '}'")
+		}
+	}
+}
+
+// list reads a list or, like [I;1,2,3], a typed array
+func (p *snbtParser) list(depth int) (interface{}, error) {
+	p.pos++ // [
+	if rest := p.text[p.pos:]; len(rest) >= 2 && rest[1] == ';' && strings.IndexByte("BIL", rest[0]) >= 0 {
+		p.pos += 2
+	}
+	result := []interface{}{}
+	if p.peek() == ']' {
+		p.pos++
+		return result, nil
+	}
+	for {
+		value, err := p.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return result, nil
+		default:
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+// quoted reads a string in single or double quotes, where a backslash
+// escapes the next character
+func (p *snbtParser) quoted() (string, error) {
+	quote := p.text[p.pos]
+	start := p.pos
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\' && p.pos < len(p.text):
+			b.WriteByte(p.text[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
+
+// word reads an unquoted string or number
+func (p *snbtParser) word() string {
+	start := p.pos
+	for p.pos < len(p.text) && isSNBTWordChar(p.text[p.pos]) {
+		p.pos++
+	}
+	return p.text[start:p.pos]
+}
+
+func isSNBTWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("_-.+", c) >= 0
+}
+
+// snbtScalar converts an unquoted word to the value the game reads it as: a
+// number if it is one, with an optional type suffix like 1b or 0.5f, a byte
+// for true and false, and otherwise a string
+func snbtScalar(word string) interface{} {
+	switch word {
+	case "true":
+		return float64(1)
+	case "false":
+		return float64(0)
+	}
+	number := word
+	if last := word[len(word)-1]; strings.IndexByte("bBsSlLfFdD", last) >= 0 {
+		number = word[:len(word)-1]
+	}
+	if strings.ContainsAny(number, "0123456789") {
+		if value, err := strconv.ParseFloat(number, 64); err == nil && !strings.ContainsAny(number, "xXpPnN_") {
+			return value
+		}
+	}
+	return word
+}
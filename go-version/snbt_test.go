@@ -0,0 +1,49 @@
+package mcheck
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSNBT(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected interface{}
+	}{
+		{`{}`, map[string]interface{}{}},
+		{`{id:"minecraft:stick", count: 2b, 'quoted key':'it\'s'}`, map[string]interface{}{"id": "minecraft:stick", "count": float64(2), "quoted key": "it's"}},
+		{`[1, 2.5f, -3L, 1e2, true, false]`, []interface{}{float64(1), 2.5, float64(-3), float64(100), float64(1), float64(0)}},
+		{`[I; 1, 2]`, []interface{}{float64(1), float64(2)}},
+		{`[B;]`, []interface{}{}},
+		{`{levels:{"minecraft:sharpness":3}}`, map[string]interface{}{"levels": map[string]interface{}{"minecraft:sharpness": float64(3)}}},
+		{`common`, "common"},
+		{`1.2.3`, "1.2.3"},
+	}
+	for _, tt := range tests {
+		value, err := parseSNBT(tt.text)
+		if err != nil {
+			t.Errorf("parseSNBT(%q) failed: %v", tt.text, err)
+			continue
+		}
+		if !reflect.DeepEqual(value, tt.expected) {
+			t.Errorf("parseSNBT(%q) = %#v, expected %#v", tt.text, value, tt.expected)
+		}
+	}
+
+	errors := []struct {
+		text     string
+		expected string
+	}{
+		{`{levels:{minecraft:sharpness:3}}`, "at column 29: expected ',' or '}'"},
+		{`{a:1`, "at column 5: expected ',' or '}'"},
+		{`{a:"open}`, "at column 4: unterminated string"},
+		{`[1] 2`, `at column 5: unexpected "2" after the value`},
+		{``, "at column 1: expected a value"},
+	}
+	for _, tt := range errors {
+		if _, err := parseSNBT(tt.text); err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("parseSNBT(%q) returned %v, expected an error containing %q", tt.text, err, tt.expected)
+		}
+	}
+}
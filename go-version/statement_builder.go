@@ -1,4 +1,4 @@
-package main
+package mcheck
 
 import "strings"
 
@@ -13,6 +13,13 @@ type StatementBuilder struct {
 	
 	// Tree builder for complex nested structures
 	TreeBuilder TreeBuilder
+
+	// File names the schema being parsed in the positions of its
+	// declarations, like worldgen/biome.mcdoc
+	File string
+
+	// EndComments are the comments after the last statement
+	EndComments []string
 }
 
 // Statement represents a top-level mcdoc statement
@@ -22,7 +29,19 @@ type Statement interface {
 
 // UseStatement represents a use statement with its path
 type UseStatement struct {
-	Path Path
+	Path  Path
+	Alias string // local name of the type, from use ... as Alias
+
+	Comments    []string // comments before the statement, including doc comments
+	LineComment string   // comment after the statement on its line
+}
+
+// Name returns the name the statement makes the type available under
+func (us UseStatement) Name() string {
+	if us.Alias != "" {
+		return us.Alias
+	}
+	return us.Path.Segments[len(us.Path.Segments)-1].Value
 }
 
 func (us UseStatement) StatementType() StatementType {
@@ -31,9 +50,15 @@ func (us UseStatement) StatementType() StatementType {
 
 // TypeAliasStatement represents a type alias
 type TypeAliasStatement struct {
-	Name      Identifier
-	Type      Expression
-	Validator Validator
+	Name       Identifier
+	TypeParams []string
+	Type       Expression
+	Validator  Validator
+	Attributes []Attribute
+	Doc        string
+
+	Comments    []string // comments before the statement or inside it, other than its doc
+	LineComment string   // comment after the statement on its last line
 }
 
 func (tas TypeAliasStatement) StatementType() StatementType {
@@ -42,8 +67,14 @@ func (tas TypeAliasStatement) StatementType() StatementType {
 
 // StructStatement represents a struct definition
 type StructStatement struct {
-	Name      Identifier
-	Validator Validator
+	Name       Identifier
+	Struct     StructExpression
+	Validator  Validator
+	Attributes []Attribute
+	Doc        string
+
+	Comments    []string // comments before the statement or inside it, other than its doc
+	LineComment string   // comment after the statement on its last line
 }
 
 func (ss StructStatement) StatementType() StatementType {
@@ -52,8 +83,16 @@ func (ss StructStatement) StatementType() StatementType {
 
 // EnumStatement represents an enum definition
 type EnumStatement struct {
-	Name      Identifier
-	Validator Validator
+	Name       Identifier
+	Type       string // base type of the enum values, e.g. "string"
+	Values     []EnumValueExpression
+	Validator  Validator
+	Attributes []Attribute
+	Doc        string
+
+	Comments    []string // comments before the statement or inside it, other than its doc
+	LineComment string   // comment after the statement on its last line
+	EndComments []string // comments after the last enum value
 }
 
 func (es EnumStatement) StatementType() StatementType {
@@ -62,15 +101,43 @@ func (es EnumStatement) StatementType() StatementType {
 
 // DispatchStatement represents a dispatch statement
 type DispatchStatement struct {
-	Path      string // dispatch path like minecraft:loot_function[apply_bonus]
-	Target    Expression
-	Validator Validator
+	Path       string   // dispatch path like minecraft:loot_function[apply_bonus]
+	Registry   string   // dispatcher name like minecraft:loot_function
+	Keys       []string // dispatched keys like apply_bonus
+	TypeParams []string // type parameters of a generic case, like T in [key]<T>
+	Target     Expression
+	Validator  Validator
+	Attributes []Attribute
+	Doc        string
+
+	Comments    []string // comments before the statement or inside it, other than its doc
+	LineComment string   // comment after the statement on its last line
 }
 
 func (ds DispatchStatement) StatementType() StatementType {
 	return StatementTypeDispatch
 }
 
+// InjectStatement adds fields to a struct or values to an enum declared
+// elsewhere, like inject struct ::java::world::item::ItemStack { ... }
+type InjectStatement struct {
+	Path       Path                  // the struct or enum injected into
+	Enum       bool                  // values injected into an enum rather than fields into a struct
+	Type       string                // base type of an enum, e.g. "string"
+	Struct     StructExpression      // injected fields
+	Values     []EnumValueExpression // injected enum values
+	Attributes []Attribute
+	Doc        string
+
+	Comments    []string // comments before the statement or inside it, other than its doc
+	LineComment string   // comment after the statement on its last line
+	EndComments []string // comments after the last enum value
+}
+
+func (is InjectStatement) StatementType() StatementType {
+	return StatementTypeInject
+}
+
 type StatementType int
 
 const (
@@ -79,6 +146,7 @@ const (
 	StatementTypeStruct
 	StatementTypeEnum
 	StatementTypeDispatch
+	StatementTypeInject
 )
 
 func (sb *StatementBuilder) Init() {
@@ -254,8 +322,17 @@ func (sb *StatementBuilder) PopPathAndAddUseStatement() {
 	pathExpr := sb.ExprStack[len(sb.ExprStack)-1]
 	sb.ExprStack = sb.ExprStack[:len(sb.ExprStack)-1]
 	
+	// use ... as Alias pushes the alias after the path
+	alias := ""
+	if identifier, ok := pathExpr.(Identifier); ok && len(sb.ExprStack) > 0 {
+		alias = identifier.Name
+		sb.PathSegmentStack = sb.PathSegmentStack[:len(sb.PathSegmentStack)-1]
+		pathExpr = sb.ExprStack[len(sb.ExprStack)-1]
+		sb.ExprStack = sb.ExprStack[:len(sb.ExprStack)-1]
+	}
+
 	if path, ok := pathExpr.(Path); ok {
-		stmt := UseStatement{Path: path}
+		stmt := UseStatement{Path: path, Alias: alias}
 		sb.Statements = append(sb.Statements, stmt)
 	}
 }
@@ -0,0 +1,226 @@
+package mcheck
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// FieldStats counts the objects of a struct that set one of its fields
+type FieldStats struct {
+	Name     string `json:"name"`
+	Optional bool   `json:"optional,omitempty"`
+	Uses     int    `json:"uses"`
+}
+
+// StructStats counts how often a struct of the schemas is used, and each of
+// the fields it has in the target version
+type StructStats struct {
+	// Name is the name of the struct, or for structs declared inline the
+	// resource type and path it was first found at, like
+	// worldgen/biome.effects.music
+	Name    string       `json:"name"`
+	Source  string       `json:"source,omitempty"`
+	Objects int          `json:"objects"`
+	Fields  []FieldStats `json:"fields"`
+}
+
+// UnusedFields returns the optional fields no object of the struct sets
+func (s StructStats) UnusedFields() []string {
+	var unused []string
+	for _, field := range s.Fields {
+		if field.Optional && field.Uses == 0 {
+			unused = append(unused, field.Name)
+		}
+	}
+	return unused
+}
+
+// PackStats is how the files of datapacks use the schemas
+type PackStats struct {
+	Files   map[string]int `json:"files"`             // JSON files of each resource type
+	Skipped int            `json:"skipped,omitempty"` // JSON files without a schema, that do not parse or are too large to decode
+	Structs []StructStats  `json:"structs"`           // most used first
+}
+
+// PackStats counts, across the JSON files of the datapacks in roots, the
+// objects of each struct of the schemas and how many of them set each of
+// its fields. Files are walked like they are validated, following
+// references, dispatches and the union alternative each value matches, so
+// that a field is counted for the struct that declares it.
+func (v *PEGMCDocValidator) PackStats(roots ...string) (*PackStats, error) {
+	counter := &statsCounter{structs: make(map[any]*structCounts)}
+	stats := &PackStats{Files: make(map[string]int)}
+	for _, root := range roots {
+		pack, err := LoadPack(root)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range pack.JSONFiles() {
+			resourceType, err := v.determineResourceType(file)
+			if err != nil {
+				stats.Skipped++
+				continue
+			}
+			converter, mainValidator, _, err := v.loadResourceType(resourceType)
+			if err != nil {
+				stats.Skipped++
+				continue
+			}
+			value, ok := readValue(pack.files, file)
+			if !ok {
+				stats.Skipped++
+				continue
+			}
+			ctx := v.newContext(converter)
+			if !mainValidator.AppliesForVersion(ctx) {
+				stats.Skipped++
+				continue
+			}
+			stats.Files[resourceType]++
+			counter.resourceType = resourceType
+			counter.walk(value, mainValidator, ctx, 0)
+		}
+	}
+
+	for _, counts := range counter.structs {
+		stats.Structs = append(stats.Structs, counts.stats)
+	}
+	slices.SortFunc(stats.Structs, func(a, b StructStats) int {
+		if a.Objects != b.Objects {
+			return b.Objects - a.Objects
+		}
+		return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.Source, b.Source))
+	})
+	return stats, nil
+}
+
+// structCounts are the counts of a struct, with its fields by name
+type structCounts struct {
+	stats  StructStats
+	fields map[string]int // index into stats.Fields
+}
+
+// statsCounter walks values and their validators, counting the structs
+// they use
+type statsCounter struct {
+	resourceType string                // of the file being walked
+	structs      map[any]*structCounts // by structKey
+}
+
+// structKey identifies a struct by where it is declared, since the schemas
+// of each resource type convert the types they share anew
+func structKey(sv *StructValidator) any {
+	if sv.Source.Line == 0 {
+		return sv
+	}
+	return sv.Name + "@" + sv.Source.String()
+}
+
+func (c *statsCounter) walk(value interface{}, validator Validator, ctx *ValidationContext, depth int) {
+	if validator == nil || depth > maxSpreadDepth || !validator.AppliesForVersion(ctx) {
+		return
+	}
+
+	switch v := validator.(type) {
+	case *ReferenceValidator:
+		c.walk(value, ctx.Definitions[v.TypeName], ctx, depth+1)
+	case *AttributedValidator:
+		c.walk(value, v.InnerValidator, ctx, depth+1)
+	case *ConstrainedValidator:
+		c.walk(value, v.InnerValidator, ctx, depth+1)
+	case *DispatchValidator:
+		c.walk(value, v.Resolve(value, ctx), ctx, depth+1)
+	case *UnionValidator:
+		c.walk(value, pickAlternative(v, value, ctx), ctx, depth+1)
+	case *ArrayValidator:
+		if arr, ok := value.([]interface{}); ok {
+			arrCtx := ctx.WithParent(arr)
+			for i, elem := range arr {
+				c.walk(elem, v.ElementValidator, arrCtx.Child(fmt.Sprintf("[%d]", i)), depth+1)
+			}
+		}
+	case *StructValidator:
+		if obj, ok := value.(map[string]interface{}); ok {
+			c.walkStruct(obj, v, ctx, ctx.WithParent(obj), nil, depth)
+		}
+	}
+}
+
+// walkStruct counts obj as an object of sv and of the structs it spreads,
+// and walks the values of their fields
+func (c *statsCounter) walkStruct(obj map[string]interface{}, sv *StructValidator, ctx, objCtx *ValidationContext, overridden map[string]bool, depth int) {
+	counts := c.counts(sv, ctx)
+	counts.stats.Objects++
+	for _, field := range sv.Fields {
+		fieldValue, exists := obj[field.Name]
+		if !exists || !field.AppliesForVersion(ctx) || overridden[field.Name] {
+			continue
+		}
+		counts.stats.Fields[counts.fields[field.Name]].Uses++
+		c.walk(fieldValue, field.Validator, objCtx.Child(field.Name), depth+1)
+	}
+
+	declared := sv.declaredFields(ctx, overridden)
+	for _, spread := range sv.SpreadFields {
+		spreadStruct, ok := resolveSpread(spread, obj, objCtx, depth)
+		if ok && spreadStruct != nil && spreadStruct.AppliesForVersion(ctx) {
+			c.walkStruct(obj, spreadStruct, ctx, objCtx, declared, depth+1)
+		}
+	}
+
+	// Keys no field declares are the entries of the dynamic fields, like
+	// the values of a map
+	if len(sv.DynamicFields) == 0 {
+		return
+	}
+	for key, elem := range obj {
+		if _, known := findStructField(sv, key, obj, objCtx); known {
+			continue
+		}
+		for _, dynamic := range sv.DynamicFields {
+			if dynamic.AppliesForVersion(ctx) && !hasErrors(dynamic.Key.Validate(key, objCtx)) {
+				c.walk(elem, dynamic.Validator, objCtx.Child(key), depth+1)
+				break
+			}
+		}
+	}
+}
+
+// counts returns the counts of sv, listing the fields it has in the target
+// version on first use
+func (c *statsCounter) counts(sv *StructValidator, ctx *ValidationContext) *structCounts {
+	key := structKey(sv)
+	if counts, ok := c.structs[key]; ok {
+		return counts
+	}
+	counts := &structCounts{
+		stats:  StructStats{Name: sv.Name, Source: sv.Source.String()},
+		fields: make(map[string]int),
+	}
+	if counts.stats.Name == "" {
+		counts.stats.Name = c.inlineName(ctx)
+	}
+	for _, field := range sv.Fields {
+		if _, seen := counts.fields[field.Name]; seen || !field.AppliesForVersion(ctx) {
+			continue
+		}
+		counts.fields[field.Name] = len(counts.stats.Fields)
+		counts.stats.Fields = append(counts.stats.Fields, FieldStats{Name: field.Name, Optional: field.Optional})
+	}
+	c.structs[key] = counts
+	return counts
+}
+
+// inlineName names a struct declared inline by the resource type and the
+// path of the value it was found at, leaving out array indices
+func (c *statsCounter) inlineName(ctx *ValidationContext) string {
+	name := []string{c.resourceType}
+	for _, segment := range ctx.Path {
+		if !strings.HasPrefix(segment, "[") {
+			name = append(name, segment)
+		}
+	}
+	return strings.Join(name, ".")
+}
@@ -0,0 +1,45 @@
+package mcheck
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPackStats(t *testing.T) {
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, fixtureSchemaDir(t, "damage_type"))
+	first := writePackFiles(t, map[string][]byte{
+		"data/test/damage_type/acid.json":  []byte(`{"message_id": "acid", "exhaustion": 0.1, "scaling": "never", "effects": "burning"}`),
+		"data/test/damage_type/frost.json": []byte(`{"message_id": "frost", "exhaustion": 0, "scaling": "always"}`),
+		"data/test/damage_type/cut.json":   []byte(`{"message_id": "cut",`),
+	})
+	second := writePackFiles(t, map[string][]byte{
+		"data/test/damage_type/sting.json":      []byte(`{"message_id": "sting", "exhaustion": 0, "scaling": "never"}`),
+		"data/test/banner_pattern/stripes.json": []byte(`{}`),
+	})
+
+	stats, err := validator.PackStats(first, second)
+	if err != nil {
+		t.Fatalf("PackStats failed: %v", err)
+	}
+	if stats.Files["damage_type"] != 3 || stats.Skipped != 2 {
+		t.Errorf("Expected 3 damage types counted and 2 files skipped, got %v and %d", stats.Files, stats.Skipped)
+	}
+	if len(stats.Structs) != 1 {
+		t.Fatalf("Expected the DamageType struct only, got %+v", stats.Structs)
+	}
+
+	damageType := stats.Structs[0]
+	if damageType.Name != "DamageType" || damageType.Objects != 3 {
+		t.Errorf("Expected 3 DamageType objects, got %+v", damageType)
+	}
+	uses := make(map[string]int)
+	for _, field := range damageType.Fields {
+		uses[field.Name] = field.Uses
+	}
+	if uses["message_id"] != 3 || uses["effects"] != 1 || uses["death_message_type"] != 0 {
+		t.Errorf("Expected the fields each object sets to be counted, got %v", uses)
+	}
+	if unused := damageType.UnusedFields(); !slices.Equal(unused, []string{"death_message_type"}) {
+		t.Errorf("Expected death_message_type to be the only unused optional field, got %v", unused)
+	}
+}
@@ -0,0 +1,267 @@
+package mcheck
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// streamingSize is the file size above which JSON files are validated while
+// they are decoded. Multi noise biome lists can reach tens of megabytes, and
+// their elements are validated one at a time instead of decoding the whole
+// list first, so memory stays bounded by the largest element.
+const streamingSize = 4 << 20
+
+// streamedArray stands in for an array whose elements were validated while
+// streaming, keeping only its length for length constraints
+type streamedArray struct {
+	length int
+}
+
+// streamJSON validates a JSON document read from r against validator. Arrays
+// that a struct field or array element declares are streamed; everything
+// else is decoded as usual and validated once its enclosing value is read,
+// so findings within streamed arrays are reported before those of the
+// objects holding them. The error is for documents that cannot be read.
+func streamJSON(r io.Reader, validator Validator, ctx *ValidationContext) ([]Finding, error) {
+	decoder := json.NewDecoder(newNonFiniteReader(newTextReader(r)))
+	value, findings, err := streamValue(decoder, validator, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, RuleError{RuleInvalidJSON, fmt.Errorf("failed to parse JSON: unexpected data after the document")}
+	}
+	return append(findings, validator.Validate(value, ctx)...), nil
+}
+
+// streamFile validates a JSON file read from files by streaming it
+func streamFile(files fileSystem, path string, validator Validator, ctx *ValidationContext) ([]Finding, error) {
+	file, err := files.Open(path)
+	if err != nil {
+		return nil, RuleError{RuleUnreadableFile, fmt.Errorf("failed to read JSON file: %w", err)}
+	}
+	defer file.Close()
+	return streamJSON(file, validator, ctx)
+}
+
+// streamValue reads the next value for validator. Arrays the validator
+// declares are validated element by element and returned as a
+// streamedArray; objects are read field by field so that arrays within them
+// can be streamed in turn. It returns the findings of the streamed arrays
+// within the value.
+func streamValue(decoder *json.Decoder, validator Validator, ctx *ValidationContext) (interface{}, []Finding, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, nil, jsonError(err)
+	}
+
+	switch token {
+	case json.Delim('['):
+		if array := streamedArrayValidator(validator, ctx); array != nil {
+			return streamArray(decoder, array, ctx)
+		}
+		var elements []interface{}
+		var findings []Finding
+		for decoder.More() {
+			element, elementFindings, err := streamValue(decoder, nil, ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+			elements = append(elements, element)
+			findings = append(findings, elementFindings...)
+		}
+		if elements == nil {
+			elements = []interface{}{}
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, nil, jsonError(err)
+		}
+		return elements, findings, nil
+	case json.Delim('{'):
+		obj := make(map[string]interface{})
+		objCtx := ctx.WithParent(obj)
+		var findings []Finding
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, nil, jsonError(err)
+			}
+			key := keyToken.(string)
+			value, valueFindings, err := streamValue(decoder, streamedFieldValidator(validator, key, obj, objCtx), objCtx.Child(key))
+			if err != nil {
+				return nil, nil, err
+			}
+			obj[key] = value
+			findings = append(findings, valueFindings...)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, nil, jsonError(err)
+		}
+		return obj, findings, nil
+	}
+	return restoreNonFinite(token), nil, nil
+}
+
+// streamArray validates the elements of an array as they are read
+func streamArray(decoder *json.Decoder, array *ArrayValidator, ctx *ValidationContext) (interface{}, []Finding, error) {
+	length := 0
+	var findings []Finding
+	// The elements are not all read yet, so the list stands in as a parent
+	// without them; dispatches cannot read from lists anyway
+	listCtx := ctx.WithParent(streamedArray{})
+	for decoder.More() {
+		elementCtx := listCtx.Child("[" + strconv.Itoa(length) + "]")
+		element, elementFindings, err := streamValue(decoder, array.ElementValidator, elementCtx)
+		if err != nil {
+			return nil, nil, err
+		}
+		findings = append(findings, elementFindings...)
+		validated := array.ElementValidator.Validate(element, elementCtx)
+		if element == nil && hasErrors(validated) {
+			validated = nullError(array.ElementValidator, elementCtx, false)
+		}
+		findings = append(findings, validated...)
+		length++
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, nil, jsonError(err)
+	}
+	return streamedArray{length: length}, findings, nil
+}
+
+// streamedArrayValidator returns the array validator a value can be streamed
+// with. Only arrays reached through references and attributes qualify; the
+// alternatives of a union might not all be arrays, so their values are
+// decoded in full.
+func streamedArrayValidator(validator Validator, ctx *ValidationContext) *ArrayValidator {
+	for depth := 0; validator != nil && depth <= maxSpreadDepth; depth++ {
+		if !validator.AppliesForVersion(ctx) {
+			return nil
+		}
+		switch v := validator.(type) {
+		case *ArrayValidator:
+			return v
+		case *ReferenceValidator:
+			validator = ctx.Definitions[v.TypeName]
+		case *AttributedValidator:
+			validator = v.InnerValidator
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// streamedFieldValidator returns the validator of a field of a partially
+// read object, or nil if it cannot be known yet. Dispatches are only
+// followed once the key they dispatch on has been read.
+func streamedFieldValidator(validator Validator, key string, obj map[string]interface{}, objCtx *ValidationContext) Validator {
+	for depth := 0; validator != nil && depth <= maxSpreadDepth; depth++ {
+		if !validator.AppliesForVersion(objCtx) {
+			return nil
+		}
+		switch v := validator.(type) {
+		case *ReferenceValidator:
+			validator = objCtx.Definitions[v.TypeName]
+		case *AttributedValidator:
+			validator = v.InnerValidator
+		case *DispatchValidator:
+			if v.Dynamic {
+				if _, found := v.dynamicKey(obj, objCtx); !found {
+					return nil
+				}
+			}
+			validator = v.Resolve(obj, objCtx)
+		case *StructValidator:
+			return v.streamedField(key, obj, objCtx, depth)
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// streamedField finds the declared field named key in a struct or its
+// spreads
+func (sv *StructValidator) streamedField(key string, obj map[string]interface{}, objCtx *ValidationContext, depth int) Validator {
+	for _, field := range sv.Fields {
+		if field.Name == key && field.AppliesForVersion(objCtx) {
+			return field.Validator
+		}
+	}
+	for _, spread := range sv.SpreadFields {
+		spreadStruct, ok := resolveSpread(spread, obj, objCtx, depth)
+		if !ok || spreadStruct == nil || !spreadStruct.AppliesForVersion(objCtx) {
+			continue
+		}
+		if validator := spreadStruct.streamedField(key, obj, objCtx, depth+1); validator != nil {
+			return validator
+		}
+	}
+	return nil
+}
+
+// nonFiniteReader quotes the NaN and Infinity literals of a JSON stream like
+// replaceNonFiniteLiterals, so that decoded tokens can be restored with
+// restoreNonFinite
+type nonFiniteReader struct {
+	in       *bufio.Reader
+	pending  []byte
+	inString bool
+	escaped  bool
+}
+
+func newNonFiniteReader(r io.Reader) *nonFiniteReader {
+	return &nonFiniteReader{in: bufio.NewReader(r)}
+}
+
+func (r *nonFiniteReader) Read(p []byte) (int, error) {
+	for len(r.pending) < len(p) {
+		c, err := r.in.ReadByte()
+		if err != nil {
+			if len(r.pending) > 0 {
+				break
+			}
+			return 0, err
+		}
+		r.pending = r.appendNext(r.pending, c)
+	}
+	n := copy(p, r.pending)
+	r.pending = append(r.pending[:0], r.pending[n:]...)
+	return n, nil
+}
+
+// appendNext appends the output for the input byte c, consuming the rest of
+// a non-finite literal that starts with c
+func (r *nonFiniteReader) appendNext(out []byte, c byte) []byte {
+	if r.inString {
+		switch {
+		case r.escaped:
+			r.escaped = false
+		case c == '\\':
+			r.escaped = true
+		case c == '"':
+			r.inString = false
+		}
+		return append(out, c)
+	}
+	if c == '"' {
+		r.inString = true
+		return append(out, c)
+	}
+
+	for _, nonFinite := range nonFiniteLiterals {
+		if c != nonFinite.literal[0] {
+			continue
+		}
+		rest, _ := r.in.Peek(len(nonFinite.literal) - 1)
+		if string(rest) == nonFinite.literal[1:] {
+			r.in.Discard(len(rest))
+			return append(out, `"\u0000mcheck:`+nonFinite.literal+`"`...)
+		}
+	}
+	return append(out, c)
+}
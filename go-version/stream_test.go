@@ -0,0 +1,210 @@
+package mcheck
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const streamTestSchema = `dispatch minecraft:resource[dimension] to struct Dimension {
+	generator: struct {
+		type: string,
+		...minecraft:generator[[type]],
+	},
+	tags?: [string] @ 1..,
+}
+
+dispatch minecraft:generator[noise] to struct {
+	biomes: [Entry],
+	either?: ([string] | string),
+}
+
+struct Entry {
+	biome: string,
+	parameters: [[float @ -2..2]],
+}
+`
+
+func TestStreamJSON(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", streamTestSchema)
+	validator := converter.MainValidatorFor("dimension")
+
+	documents := []string{
+		`{"generator": {"type": "noise", "biomes": [{"biome": "a", "parameters": [[0, 1], [-1]]}]}}`,
+		`{"generator": {"type": "noise", "biomes": [{"biome": "a", "parameters": [[0, 3]]}]}}`,
+		`{"generator": {"biomes": [{"biome": "a", "parameters": [[0, 3]]}], "type": "noise"}}`,
+		`{"generator": {"type": "noise", "biomes": [{"biome": "a", "parameters": [[0]]}, null]}}`,
+		`{"generator": {"type": "noise", "biomes": [{"biome": "a", "parameters": [[NaN]]}]}}`,
+		`{"generator": {"type": "noise", "biomes": [], "either": ["a", 1]}}`,
+		`{"generator": {"type": "noise", "biomes": []}, "tags": []}`,
+		`{"generator": {"type": "noise", "biomes": []}, "tags": ["a"], "extra": [1, 2]}`,
+		`{"generator": {"type": "noise", "biomes": {}}}`,
+	}
+
+	for _, document := range documents {
+		t.Run(document, func(t *testing.T) {
+			decoded, err := decodeJSON([]byte(document))
+			if err != nil {
+				t.Fatalf("Failed to decode document: %v", err)
+			}
+			// Streamed arrays report their findings first, so only the
+			// findings themselves are compared
+			expected := sortedFindings(validator.Validate(decoded, ctx))
+			findings, err := streamJSON(strings.NewReader(document), validator, ctx)
+			if err != nil {
+				t.Fatalf("Streaming failed: %v", err)
+			}
+			if streamed := sortedFindings(findings); streamed != expected {
+				t.Errorf("Expected %s, streaming gave %s", expected, streamed)
+			}
+		})
+	}
+
+	if _, err := streamJSON(strings.NewReader(`{"generator": {}} {}`), validator, ctx); !strings.Contains(fmt.Sprint(err), "failed to parse JSON") {
+		t.Errorf("Expected a parse error for data after the document, got %v", err)
+	}
+	for _, document := range []string{`{"generator": {`, `{"generator": {"biomes": [`} {
+		if _, err := streamJSON(strings.NewReader(document), validator, ctx); !strings.Contains(fmt.Sprint(err), "file ends in the middle of its JSON document") {
+			t.Errorf("Expected %s to be reported as cut off, got %v", document, err)
+		}
+	}
+}
+
+// sortedFindings describes findings in sorted order
+func sortedFindings(findings []Finding) string {
+	described := make([]string, len(findings))
+	for i, finding := range findings {
+		described[i] = finding.String()
+	}
+	sort.Strings(described)
+	return strings.Join(described, "\n")
+}
+
+func TestValidateHugeJSON(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t)
+	schemaPath := filepath.Join(schemaDir, "java", "data", "dimension.mcdoc")
+	if err := os.MkdirAll(filepath.Dir(schemaPath), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(schemaPath, []byte(streamTestSchema), 0o644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	var document strings.Builder
+	document.WriteString(`{"generator": {"type": "noise", "biomes": [`)
+	count := 0
+	for ; document.Len() <= streamingSize; count++ {
+		document.WriteString(`{"biome": "minecraft:plains", "parameters": [[-1, 1], [0.5, 0.75]]},`)
+	}
+	document.WriteString(`{"biome": "minecraft:plains", "parameters": [[-1, 3]]}]}}`)
+	jsonPath := filepath.Join(t.TempDir(), "data", "test", "dimension", "huge.json")
+	if err := os.MkdirAll(filepath.Dir(jsonPath), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(jsonPath, []byte(document.String()), 0o644); err != nil {
+		t.Fatalf("Failed to write document: %v", err)
+	}
+
+	targetVersion, _ := ParseVersion("1.20.1")
+	err := NewPEGMCDocValidator(targetVersion, schemaDir).ValidateJSON(jsonPath)
+	expected := fmt.Sprintf("at generator.biomes.[%d].parameters.[0].[1]: value 3 must be less than or equal to 2", count)
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("Expected error containing %q, got %v", expected, err)
+	}
+}
+
+// readCountingFS counts the files read in full, as opposed to opened and
+// streamed
+type readCountingFS struct {
+	fstest.MapFS
+	reads map[string]int
+}
+
+func (fsys readCountingFS) ReadFile(name string) ([]byte, error) {
+	fsys.reads[name]++
+	return fsys.MapFS.ReadFile(name)
+}
+
+var _ fs.ReadFileFS = readCountingFS{}
+
+func TestCheckPackHugeJSON(t *testing.T) {
+	var document strings.Builder
+	document.WriteString(`{"generator": {"type": "noise", "biomes": [{"biome": "test:deep", "parameters": [[-1, 1]]},`)
+	count := 1
+	for ; document.Len() <= streamingSize; count++ {
+		document.WriteString(`{"biome": "minecraft:plains", "parameters": [[-1, 1], [0.5, 0.75]]},`)
+	}
+	document.WriteString(`{"biome": "minecraft:plains", "parameters": [[-1, 3]]}]}}`)
+
+	const huge = "data/test/dimension/huge.json"
+	files := readCountingFS{
+		MapFS: fstest.MapFS{huge: {Data: []byte(document.String())}},
+		reads: make(map[string]int),
+	}
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, filepath.Join(t.TempDir(), "vanilla-mcdoc"))
+	validator.SetSchemaFS(fstest.MapFS{"java/data/dimension.mcdoc": {Data: []byte(streamTestSchema)}})
+
+	findings, err := validator.CheckPack(context.Background(), "pack", files, PackOptions{ReportUnused: true})
+	if err != nil {
+		t.Fatalf("CheckPack failed: %v", err)
+	}
+	expected := fmt.Sprintf("at generator.biomes.[%d].parameters.[0].[1]: value 3 must be less than or equal to 2", count)
+	if !slices.ContainsFunc(findings, func(finding Finding) bool { return strings.Contains(finding.String(), expected) }) {
+		t.Errorf("Expected a finding containing %q, got %v", expected, findings)
+	}
+
+	pack, err := LoadPackFS("pack", files)
+	if err != nil {
+		t.Fatalf("LoadPackFS failed: %v", err)
+	}
+	if ids := mentionedIDs(pack, pack.Resources["dimension"]["test:huge"]); !slices.Contains(ids, "test:deep") {
+		t.Errorf("Expected the streamed file to mention test:deep, got %d ids", len(ids))
+	}
+	if files.reads[huge] > 0 {
+		t.Errorf("Expected %s to be streamed, but it was read in full %d times", huge, files.reads[huge])
+	}
+}
+
+func TestCheckPackHugeJSONSkippedChecks(t *testing.T) {
+	biome := func(size int) string {
+		var document strings.Builder
+		document.WriteString(`{"features": [["test:missing"]`)
+		for document.Len() <= size {
+			document.WriteString(`, ["minecraft:ore_iron", "minecraft:ore_gold"]`)
+		}
+		document.WriteString(`]}`)
+		return document.String()
+	}
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, filepath.Join(t.TempDir(), "vanilla-mcdoc"))
+	validator.SetSchemaFS(fstest.MapFS{"java/data/worldgen/biome.mcdoc": {Data: []byte(`dispatch minecraft:resource["worldgen/biome"] to struct Biome {
+	features: [[string]],
+}`)}})
+
+	tests := []struct {
+		name     string
+		document string
+		rule     string
+	}{
+		{"decoded", biome(1024), RuleMissingReference},
+		{"streamed", biome(streamingSize), RulePartiallyValidated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := fstest.MapFS{"data/test/worldgen/biome/plains.json": {Data: []byte(tt.document)}}
+			findings, err := validator.CheckPack(context.Background(), "pack", files, PackOptions{})
+			if err != nil {
+				t.Fatalf("CheckPack failed: %v", err)
+			}
+			if len(findings) != 1 || findings[0].Rule != tt.rule || findings[0].File != "data/test/worldgen/biome/plains.json" {
+				t.Errorf("Expected a %s finding for the biome, got %v", tt.rule, findings)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package mcheck
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// outputStyle is a profile for printing findings to different readers
+type outputStyle string
+
+const (
+	stylePlain outputStyle = "plain" // findings as they are
+	styleEmoji outputStyle = "emoji" // findings marked with an emoji for their severity
+	styleASCII outputStyle = "ascii" // strictly ASCII, for log aggregation systems
+)
+
+// OutputStyles are the names of the output styles
+var OutputStyles = []string{string(stylePlain), string(styleEmoji), string(styleASCII)}
+
+var severityEmoji = map[Severity]string{
+	SeverityError:   "❌",
+	SeverityWarning: "⚠️",
+	SeverityInfo:    "ℹ️",
+}
+
+// parseOutputStyle returns the style of a name, plain if it is empty
+func parseOutputStyle(name string) (outputStyle, error) {
+	if name == "" {
+		return stylePlain, nil
+	}
+	for _, style := range OutputStyles {
+		if name == style {
+			return outputStyle(name), nil
+		}
+	}
+	return "", fmt.Errorf("unknown output style %q, expected one of %s", name, strings.Join(OutputStyles, ", "))
+}
+
+// finding formats a finding in the style
+func (s outputStyle) finding(f Finding) string {
+	text := f.String()
+	if s == styleEmoji {
+		text = severityEmoji[f.Severity] + " " + text
+	}
+	return s.text(text)
+}
+
+// text formats output other than findings in the style. Plain and emoji
+// output leave it alone; ASCII output escapes whatever is not printable
+// ASCII, like the § of formatted text or a translated message.
+func (s outputStyle) text(text string) string {
+	if s != styleASCII {
+		return text
+	}
+	var escaped strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\n' || r == '\t' || r >= ' ' && r < utf8.RuneSelf && r != 0x7f:
+			escaped.WriteRune(r)
+		case r <= 0xffff:
+			fmt.Fprintf(&escaped, `\u%04x`, r)
+		default:
+			fmt.Fprintf(&escaped, `\U%08x`, r)
+		}
+	}
+	return escaped.String()
+}
+
+// Output is how findings are printed: the language of their messages and
+// the style of the lines, or the template rendering a report of them
+type Output struct {
+	catalog  *Catalog
+	style    outputStyle
+	template *template.Template // set by --format=template
+	version  string             // target version, named by the report
+
+	webhook         string // URL notified of failed validations, if set
+	webhookFindings bool   // list every finding in the notification
+}
+
+// finding formats a finding for the output
+func (out Output) finding(f Finding) string {
+	return out.style.finding(out.catalog.Localize(f))
+}
@@ -0,0 +1,32 @@
+package mcheck
+
+import "testing"
+
+func TestOutputStyles(t *testing.T) {
+	finding := Finding{File: "data/test/recipe/café.json", Path: []string{"result"}, Severity: SeverityWarning, Message: "name §zBread has an invalid formatting code 🍞", Rule: RuleInvalidFormat}
+
+	tests := []struct {
+		style    string
+		expected string
+	}{
+		{"plain", "data/test/recipe/café.json: warning: at result: name §zBread has an invalid formatting code 🍞 [MCHECK007 invalid-format]"},
+		{"emoji", "⚠️ data/test/recipe/café.json: warning: at result: name §zBread has an invalid formatting code 🍞 [MCHECK007 invalid-format]"},
+		{"ascii", `data/test/recipe/caf\u00e9.json: warning: at result: name \u00a7zBread has an invalid formatting code \U0001f35e [MCHECK007 invalid-format]`},
+	}
+	for _, tt := range tests {
+		style, err := parseOutputStyle(tt.style)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", tt.style, err)
+		}
+		if text := (Output{style: style}).finding(finding); text != tt.expected {
+			t.Errorf("Expected %s output %q, got %q", tt.style, tt.expected, text)
+		}
+	}
+
+	if text := styleASCII.text("line\n\tindented\x1b[31m\n"); text != "line\n\tindented\\u001b[31m\n" {
+		t.Errorf("Unexpected ASCII text %q", text)
+	}
+	if _, err := parseOutputStyle("fancy"); err == nil {
+		t.Error("Expected an unknown style to fail")
+	}
+}
@@ -0,0 +1,129 @@
+package mcheck
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// utf8BOM is the byte order mark some editors on Windows start UTF-8 files
+// with. The game skips it, so mcheck does too.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// utf16BOMs start the files of editors saving as UTF-16, which the game
+// cannot read
+var utf16BOMs = [][]byte{[]byte("\xff\xfe"), []byte("\xfe\xff")}
+
+// encodingError reports a file that is not UTF-8, at the offset of the
+// first byte that is not
+type encodingError struct {
+	offset int64
+	value  byte
+	utf16  bool
+}
+
+func (e *encodingError) Error() string {
+	if e.utf16 {
+		return "file is UTF-16 encoded, save it as UTF-8"
+	}
+	return fmt.Sprintf("file is not UTF-8 encoded: invalid byte 0x%02X at offset %d, save it as UTF-8", e.value, e.offset)
+}
+
+// decodeText returns file content without its UTF-8 byte order mark, or an
+// encodingError if it is not UTF-8, rather than leaving the parser to fail
+// on the first byte it does not expect
+func decodeText(content []byte) ([]byte, error) {
+	for _, bom := range utf16BOMs {
+		if bytes.HasPrefix(content, bom) {
+			return nil, &encodingError{utf16: true}
+		}
+	}
+	text := bytes.TrimPrefix(content, utf8BOM)
+	if utf8.Valid(text) {
+		return text, nil
+	}
+	offset := len(content) - len(text)
+	for len(text) > 0 {
+		r, size := utf8.DecodeRune(text)
+		if r == utf8.RuneError && size == 1 {
+			return nil, &encodingError{offset: int64(offset), value: text[0]}
+		}
+		text, offset = text[size:], offset+size
+	}
+	return nil, nil
+}
+
+// normalizeNewlines turns the CRLF and CR line endings of source into LF
+func normalizeNewlines(source string) string {
+	if !strings.Contains(source, "\r") {
+		return source
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(source, "\r\n", "\n"), "\r", "\n")
+}
+
+// mcdocText returns schema file content as the parser reads it: UTF-8
+// without a byte order mark, with LF line endings
+func mcdocText(content []byte) (string, error) {
+	text, err := decodeText(content)
+	if err != nil {
+		return "", err
+	}
+	return normalizeNewlines(string(text)), nil
+}
+
+// textReader checks text read from a file like decodeText does, for files
+// read as a stream
+type textReader struct {
+	r       *bufio.Reader
+	offset  int64
+	started bool
+}
+
+func newTextReader(r io.Reader) *textReader {
+	return &textReader{r: bufio.NewReader(r)}
+}
+
+func (t *textReader) Read(p []byte) (int, error) {
+	if !t.started {
+		t.started = true
+		head, _ := t.r.Peek(len(utf8BOM))
+		for _, bom := range utf16BOMs {
+			if bytes.HasPrefix(head, bom) {
+				return 0, &encodingError{utf16: true}
+			}
+		}
+		if bytes.Equal(head, utf8BOM) {
+			t.r.Discard(len(utf8BOM))
+			t.offset = int64(len(utf8BOM))
+		}
+	}
+
+	n := 0
+	for n < len(p) {
+		r, size, err := t.r.ReadRune()
+		if err != nil {
+			if n > 0 && err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+		if r == utf8.RuneError && size == 1 {
+			t.r.UnreadRune()
+			value, _ := t.r.ReadByte()
+			return n, &encodingError{offset: t.offset, value: value}
+		}
+		if n+size > len(p) {
+			t.r.UnreadRune()
+			if n == 0 {
+				return 0, io.ErrShortBuffer
+			}
+			break
+		}
+		n += utf8.EncodeRune(p[n:], r)
+		t.offset += int64(size)
+	}
+	return n, nil
+}
@@ -0,0 +1,90 @@
+package mcheck
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		text    string
+		err     string
+	}{
+		{"plain", `{"a": "é"}`, `{"a": "é"}`, ""},
+		{"byte order mark", "\xef\xbb\xbf{}", "{}", ""},
+		{"latin-1", "{\"a\": \"caf\xe9\"}", "", "invalid byte 0xE9 at offset 10"},
+		{"latin-1 after a byte order mark", "\xef\xbb\xbf{\"\xe9\"}", "", "invalid byte 0xE9 at offset 5"},
+		{"utf-16", "\xff\xfe{\x00}\x00", "", "file is UTF-16 encoded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := decodeText([]byte(tt.content))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Expected an error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil || string(text) != tt.text {
+				t.Errorf("Expected %q, got %q, %v", tt.text, text, err)
+			}
+
+			// Streamed files are read the same, however they are split
+			streamed, err := io.ReadAll(iotest.HalfReader(newTextReader(iotest.OneByteReader(strings.NewReader(tt.content)))))
+			if err != nil || string(streamed) != tt.text {
+				t.Errorf("Expected %q streamed, got %q, %v", tt.text, streamed, err)
+			}
+		})
+	}
+
+	_, err := io.ReadAll(newTextReader(strings.NewReader("[\"é\", \"caf\xe9\"]")))
+	var encoding *encodingError
+	if !errors.As(err, &encoding) || encoding.offset != 11 {
+		t.Errorf("Expected the streamed offset of the invalid byte, got %v", err)
+	}
+}
+
+func TestCheckDocumentEncoding(t *testing.T) {
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, fixtureSchemaDir(t, "damage_type"))
+	path := filepath.Join("data", "test", "damage_type", "acid.json")
+
+	findings := validator.CheckDocument(path, "acid.json", []byte("\xef\xbb\xbf{\"message_id\": \"acid\", \"exhaustion\": 0.1, \"scaling\": \"never\"}\r\n"))
+	if len(findings) != 0 {
+		t.Errorf("Expected a byte order mark and CRLF to be accepted, got %v", findings)
+	}
+
+	findings = validator.CheckDocument(path, "acid.json", []byte("{\"message_id\": \"\xe4tzend\", \"exhaustion\": 0.1, \"scaling\": \"never\"}"))
+	if len(findings) != 1 || findings[0].Rule != RuleInvalidEncoding || !strings.Contains(findings[0].Message, "offset 16") {
+		t.Errorf("Expected the invalid byte to be reported, got %v", findings)
+	}
+}
+
+func TestParseMCDocWindowsText(t *testing.T) {
+	source := "\xef\xbb\xbf/// A thing.\r\n/// Of two lines.\r\nstruct Thing {\r\n\tname: string,\r\n}\r\n"
+	parser, err := parseMCDocSource(source, "thing.mcdoc")
+	if err != nil {
+		t.Fatalf("parseMCDocSource failed: %v", err)
+	}
+	thing, ok := parser.Statements[0].(StructStatement)
+	if !ok {
+		t.Fatalf("Expected a struct, got %T", parser.Statements[0])
+	}
+	if strings.Contains(thing.Doc, "\r") {
+		t.Errorf("Expected the doc comment without carriage returns, got %q", thing.Doc)
+	}
+	formatted, err := FormatMCDoc(source, "thing.mcdoc")
+	if err != nil || !bytes.Equal([]byte(formatted), []byte("/// A thing.\n/// Of two lines.\nstruct Thing {\n\tname: string,\n}\n")) {
+		t.Errorf("Expected the formatted schema with LF line endings, got %q, %v", formatted, err)
+	}
+
+	if _, err := parseMCDocSource("struct Caf\xe9 {}", "cafe.mcdoc"); err == nil || !strings.Contains(err.Error(), "cafe.mcdoc: file is not UTF-8 encoded") {
+		t.Errorf("Expected an encoding error naming the file, got %v", err)
+	}
+}
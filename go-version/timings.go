@@ -0,0 +1,156 @@
+package mcheck
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Timings records where checking files spends its time, by resource type and
+// phase, for the report of --timings. It is only ever printed locally.
+//
+// Parsing a schema is counted for the resource type whose file needed it
+// first, so types sharing schemas with an earlier type seem cheaper than they
+// would be on their own. Validators checking files concurrently keep the
+// totals right, but may count parsing for the wrong resource type.
+type Timings struct {
+	mu      sync.Mutex
+	types   map[string]*typeTimings
+	parsing time.Duration // parsing not yet counted for a resource type
+}
+
+type typeTimings struct {
+	files     int
+	durations [phaseCount]time.Duration
+}
+
+// timingPhase is a phase of checking a file
+type timingPhase int
+
+const (
+	phaseParse    timingPhase = iota // parsing schema files
+	phaseBuild                       // converting schemas to validators
+	phaseDecode                      // decoding JSON and NBT files
+	phaseValidate                    // validating documents and running semantic checks
+	phaseCount
+)
+
+var phaseNames = [phaseCount]string{"parse schemas", "build validators", "decode", "validate"}
+
+func (t *Timings) entry(resourceType string) *typeTimings {
+	if t.types == nil {
+		t.types = make(map[string]*typeTimings)
+	}
+	entry := t.types[resourceType]
+	if entry == nil {
+		entry = &typeTimings{}
+		t.types[resourceType] = entry
+	}
+	return entry
+}
+
+// add records the time a phase took for a file of a resource type. Like the
+// other recording methods it does nothing on a nil Timings, so validators
+// record unconditionally.
+func (t *Timings) add(resourceType string, phase timingPhase, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entry(resourceType).durations[phase] += d
+}
+
+// addFile counts a file checked for a resource type
+func (t *Timings) addFile(resourceType string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entry(resourceType).files++
+}
+
+// addParsing records the time parsing a schema file took, to be counted by
+// the next addSchema
+func (t *Timings) addParsing(d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.parsing += d
+}
+
+// addSchema records the time loading the schema of a resource type took:
+// the parsing recorded meanwhile, and the rest as building validators
+func (t *Timings) addSchema(resourceType string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parse := min(t.parsing, d)
+	t.parsing = 0
+	entry := t.entry(resourceType)
+	entry.durations[phaseParse] += parse
+	entry.durations[phaseBuild] += d - parse
+}
+
+// Write prints the report: a row for each resource type, slowest first, and
+// a row of totals
+func (t *Timings) Write(w io.Writer) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	type row struct {
+		name  string
+		times typeTimings
+		total time.Duration
+	}
+	var rows []row
+	var totals typeTimings
+	for name, entry := range t.types {
+		r := row{name: name, times: *entry}
+		for phase, d := range entry.durations {
+			r.total += d
+			totals.durations[phase] += d
+		}
+		totals.files += entry.files
+		rows = append(rows, r)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].total != rows[j].total {
+			return rows[i].total > rows[j].total
+		}
+		return rows[i].name < rows[j].name
+	})
+	total := row{name: "total", times: totals}
+	for _, d := range totals.durations {
+		total.total += d
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "resource type\tfiles")
+	for _, name := range phaseNames {
+		fmt.Fprint(tw, "\t"+name)
+	}
+	fmt.Fprintln(tw, "\ttotal")
+	for _, r := range append(rows, total) {
+		fmt.Fprintf(tw, "%s\t%d", r.name, r.times.files)
+		for _, d := range r.times.durations {
+			fmt.Fprint(tw, "\t"+formatDuration(d))
+		}
+		fmt.Fprintln(tw, "\t"+formatDuration(r.total))
+	}
+	return tw.Flush()
+}
+
+// formatDuration formats a duration in milliseconds, which keeps the columns
+// of the report comparable at a glance
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,63 @@
+package mcheck
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimings(t *testing.T) {
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/damage_type/acid.json":  []byte(`{"message_id": "acid", "exhaustion": 0.1, "scaling": "never"}`),
+		"data/test/damage_type/spike.json": []byte(`{"message_id": "spike", "exhaustion": 0.1, "scaling": "never"}`),
+	})
+	timings := &Timings{}
+	validator := NewPEGMCDocValidator(Version{1, 20, 1}, fixtureSchemaDir(t, "damage_type"))
+	validator.SetTimings(timings)
+	if _, err := validator.CheckPack(context.Background(), root, nil, PackOptions{}); err != nil {
+		t.Fatalf("CheckPack failed: %v", err)
+	}
+
+	entry := timings.types["damage_type"]
+	if entry == nil || entry.files != 2 {
+		t.Fatalf("Expected 2 damage type files, got %+v", timings.types)
+	}
+	if entry.durations[phaseParse] <= 0 {
+		t.Error("Expected time spent parsing the schema")
+	}
+	if timings.parsing != 0 {
+		t.Errorf("Expected all parsing to be counted for a resource type, %s is left", timings.parsing)
+	}
+
+	// Validators without timings record nothing
+	var none *Timings
+	none.add("damage_type", phaseValidate, time.Second)
+	none.addSchema("damage_type", time.Second)
+}
+
+func TestTimingsReport(t *testing.T) {
+	timings := &Timings{}
+	timings.addFile("worldgen/biome")
+	timings.addFile("worldgen/biome")
+	timings.addParsing(30 * time.Millisecond)
+	timings.addSchema("worldgen/biome", 40*time.Millisecond)
+	timings.add("worldgen/biome", phaseDecode, 2*time.Millisecond)
+	timings.add("worldgen/biome", phaseValidate, 3*time.Millisecond)
+	timings.addFile("recipe")
+	timings.addSchema("recipe", 5*time.Millisecond)
+	timings.add("recipe", phaseValidate, 250*time.Microsecond)
+
+	var out bytes.Buffer
+	if err := timings.Write(&out); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	expected := `resource type   files  parse schemas  build validators  decode  validate  total
+worldgen/biome  2      30.0ms         10.0ms            2.0ms   3.0ms     45.0ms
+recipe          1      0.0ms          5.0ms             0.0ms   0.2ms     5.2ms
+total           3      30.0ms         15.0ms            2.0ms   3.2ms     50.2ms
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected report:\n%s", out.String())
+	}
+}
@@ -0,0 +1,162 @@
+// vanilla-fixtures extracts the vanilla datapack JSON of a Minecraft release
+// into tests/good/vanilla/<version>, giving the validator a ground-truth
+// corpus where every file must pass.
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	var (
+		version string
+		output  string
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "vanilla-fixtures <server.jar | generated-dir>",
+		Short: "Extract vanilla datapack JSON into the test fixtures",
+		Long: `vanilla-fixtures copies the JSON files of the vanilla datapack into
+<out>/<version>/data. The source is either a server jar, whose bundled jar
+holds the datapack, or the output directory of the data generator:
+
+  java -DbundlerMainClass=net.minecraft.data.Main -jar server.jar --server --output generated`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if version == "" {
+				return fmt.Errorf("--version is required")
+			}
+
+			var files map[string][]byte
+			info, err := os.Stat(args[0])
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				files, err = readGenerated(args[0])
+			} else {
+				files, err = readJar(args[0])
+			}
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				return fmt.Errorf("no datapack JSON found in %s", args[0])
+			}
+
+			target := filepath.Join(output, version)
+			if err := os.RemoveAll(target); err != nil {
+				return err
+			}
+			for name, content := range files {
+				path := filepath.Join(target, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					return err
+				}
+				if err := os.WriteFile(path, content, 0o644); err != nil {
+					return err
+				}
+			}
+			fmt.Printf("extracted %d files into %s\n", len(files), target)
+			return nil
+		},
+	}
+
+	rootCmd.Flags().StringVarP(&version, "version", "v", "", "Minecraft version the source belongs to")
+	rootCmd.Flags().StringVarP(&output, "out", "o", filepath.Join("tests", "good", "vanilla"), "Directory receiving one subdirectory per version")
+
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// isDatapackJSON reports whether a slash separated path is a JSON file of
+// the vanilla datapack
+func isDatapackJSON(name string) bool {
+	return strings.HasPrefix(name, "data/") && path.Ext(name) == ".json"
+}
+
+// readGenerated reads the datapack JSON below a data generator output directory
+func readGenerated(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !isDatapackJSON(name) {
+			return nil
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		files[name] = content
+		return nil
+	})
+	return files, err
+}
+
+// readJar reads the datapack JSON from a server jar. Since 1.18 the
+// downloadable jar is a bundler that holds the real server jar under
+// META-INF/versions, so nested jars are searched as well.
+func readJar(file string) (map[string][]byte, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	if err := readZip(content, files); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return files, nil
+}
+
+func readZip(content []byte, files map[string][]byte) error {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return err
+	}
+	for _, entry := range archive.File {
+		nested := strings.HasPrefix(entry.Name, "META-INF/versions/") && path.Ext(entry.Name) == ".jar"
+		if !nested && !isDatapackJSON(entry.Name) {
+			continue
+		}
+		data, err := readEntry(entry)
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name, err)
+		}
+		if nested {
+			if err := readZip(data, files); err != nil {
+				return fmt.Errorf("%s: %w", entry.Name, err)
+			}
+			continue
+		}
+		files[entry.Name] = data
+	}
+	return nil
+}
+
+func readEntry(entry *zip.File) ([]byte, error) {
+	reader, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func zipBytes(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range files {
+		writer, err := archive.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		if _, err := writer.Write(content); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Failed to close archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadJar(t *testing.T) {
+	server := zipBytes(t, map[string][]byte{
+		"data/minecraft/damage_type/fall.json":   []byte(`{"message_id": "fall"}`),
+		"data/minecraft/structure/igloo/top.nbt": {0x0a},
+		"assets/minecraft/lang/en_us.json":       []byte(`{}`),
+		"net/minecraft/server/Main.class":        {0xca, 0xfe},
+		"data/minecraft/tags/block/logs.json":    []byte(`{"values": []}`),
+	})
+	bundler := zipBytes(t, map[string][]byte{
+		"META-INF/versions/1.20.1/server-1.20.1.jar": server,
+		"META-INF/libraries/com/google/gson.jar":     zipBytes(t, map[string][]byte{"data/x.json": []byte(`{}`)}),
+		"net/minecraft/bundler/Main.class":           {0xca, 0xfe},
+	})
+
+	jar := filepath.Join(t.TempDir(), "server.jar")
+	if err := os.WriteFile(jar, bundler, 0o644); err != nil {
+		t.Fatalf("Failed to write jar: %v", err)
+	}
+
+	files, err := readJar(jar)
+	if err != nil {
+		t.Fatalf("readJar failed: %v", err)
+	}
+	expected := map[string][]byte{
+		"data/minecraft/damage_type/fall.json": []byte(`{"message_id": "fall"}`),
+		"data/minecraft/tags/block/logs.json":  []byte(`{"values": []}`),
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %v, got %v", expected, files)
+	}
+}
+
+func TestReadGenerated(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"data/minecraft/damage_type/fall.json": `{"message_id": "fall"}`,
+		"reports/blocks.json":                  `{}`,
+		".cache/cache":                         ``,
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	files, err := readGenerated(dir)
+	if err != nil {
+		t.Fatalf("readGenerated failed: %v", err)
+	}
+	if len(files) != 1 || string(files["data/minecraft/damage_type/fall.json"]) != `{"message_id": "fall"}` {
+		t.Errorf("Expected only the damage type, got %v", files)
+	}
+}
@@ -0,0 +1,176 @@
+package mcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// unusedCandidateTypes are the resource types the game only uses when
+// something references them. Other types, like advancements, recipes, tags,
+// dimensions and structure sets, are loaded on their own and are the roots
+// the reachability check starts from.
+var unusedCandidateTypes = map[string]bool{
+	"dimension_type":              true,
+	"function":                    true,
+	"functions":                   true,
+	"item_modifier":               true,
+	"item_modifiers":              true,
+	"loot_table":                  true,
+	"loot_tables":                 true,
+	"predicate":                   true,
+	"predicates":                  true,
+	"structure":                   true,
+	"structures":                  true,
+	"worldgen/biome":              true,
+	"worldgen/configured_carver":  true,
+	"worldgen/configured_feature": true,
+	"worldgen/density_function":   true,
+	"worldgen/noise":              true,
+	"worldgen/noise_settings":     true,
+	"worldgen/placed_feature":     true,
+	"worldgen/processor_list":     true,
+	"worldgen/structure":          true,
+	"worldgen/template_pool":      true,
+}
+
+// resourceLocationPattern matches namespaced ids inside JSON strings and
+// function files, optionally as a tag reference
+var resourceLocationPattern = regexp.MustCompile(`#?[a-z0-9_.-]+:[a-z0-9_./-]+`)
+
+// checkUnused warns about resources that nothing reachable from the roots of
+// the pack references. Resources in the minecraft namespace are always roots,
+// since they override resources vanilla references itself.
+func checkUnused(pack *Pack, opts PackOptions) []Finding {
+	if !opts.ReportUnused {
+		return nil
+	}
+
+	type resource struct{ resourceType, id string }
+	resources := make(map[string]resource)
+	byID := make(map[string][]string)
+	for resourceType, files := range pack.Resources {
+		for id, file := range files {
+			resources[file] = resource{resourceType, id}
+			byID[id] = append(byID[id], file)
+		}
+	}
+
+	// Files reference every resource whose id they mention
+	edges := make(map[string][]string)
+	for file := range resources {
+		for _, id := range mentionedIDs(pack, file) {
+			edges[file] = append(edges[file], byID[strings.TrimPrefix(id, "#")]...)
+		}
+	}
+	for _, reference := range pack.References() {
+		if target, ok := pack.Resolve(reference); ok {
+			edges[reference.File] = append(edges[reference.File], target)
+		}
+	}
+
+	var queue []string
+	reachable := make(map[string]bool)
+	for file, r := range resources {
+		namespace, _, _ := strings.Cut(r.id, ":")
+		if !unusedCandidateTypes[r.resourceType] || namespace == "minecraft" {
+			reachable[file] = true
+			queue = append(queue, file)
+		}
+	}
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+		for _, target := range edges[file] {
+			if !reachable[target] {
+				reachable[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+
+	var findings []Finding
+	for file, r := range resources {
+		if reachable[file] {
+			continue
+		}
+		findings = append(findings, Finding{
+			File:     pack.RelativePath(file),
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("%s %s is never referenced", r.resourceType, r.id),
+			Rule:     RuleUnusedResource,
+		})
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].File < findings[j].File })
+	return findings
+}
+
+// mentionedIDs returns the namespaced ids mentioned by a JSON or function
+// file. This over-approximates references, since a mentioned id may name a
+// resource of another type, which errs on the side of not reporting a
+// resource as unused.
+func mentionedIDs(pack *Pack, file string) []string {
+	switch {
+	case strings.HasSuffix(file, ".json"):
+		if info, err := pack.files.Stat(file); err == nil && info.Size() > streamingSize {
+			return streamedIDs(pack.files, file)
+		}
+		value, ok := pack.readJSON(file)
+		if !ok {
+			return nil
+		}
+		var ids []string
+		collectIDs(value, &ids)
+		return ids
+	case strings.HasSuffix(file, ".mcfunction"):
+		data, err := pack.files.ReadFile(file)
+		if err != nil {
+			return nil
+		}
+		return resourceLocationPattern.FindAllString(string(data), -1)
+	}
+	return nil
+}
+
+// collectIDs appends the strings and object keys in a JSON value that look
+// like namespaced ids
+func collectIDs(value interface{}, ids *[]string) {
+	switch v := value.(type) {
+	case string:
+		if resourceLocationPattern.FindString(v) == v {
+			*ids = append(*ids, v)
+		}
+	case map[string]interface{}:
+		for key, element := range v {
+			collectIDs(key, ids)
+			collectIDs(element, ids)
+		}
+	case []interface{}:
+		for _, element := range v {
+			collectIDs(element, ids)
+		}
+	}
+}
+
+// streamedIDs returns the ids mentioned by a JSON file too large to decode,
+// reading it a token at a time
+func streamedIDs(files fileSystem, file string) []string {
+	f, err := files.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	decoder := json.NewDecoder(newNonFiniteReader(newTextReader(f)))
+	var ids []string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ids
+		}
+		if s, ok := token.(string); ok {
+			collectIDs(s, &ids)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package mcheck
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnusedResources(t *testing.T) {
+	root := writePackFiles(t, map[string][]byte{
+		"data/test/dimension/world.json":                          []byte(`{"type": "test:world_type", "generator": {"type": "minecraft:noise", "settings": "minecraft:overworld", "biome_source": {"type": "minecraft:fixed", "biome": "test:plains"}}}`),
+		"data/test/dimension_type/world_type.json":                []byte(`{}`),
+		"data/test/worldgen/biome/plains.json":                    []byte(`{"features": [["test:flowers"]]}`),
+		"data/test/worldgen/placed_feature/flowers.json":          []byte(`{"feature": "test:flower_patch", "placement": []}`),
+		"data/test/worldgen/configured_feature/flower_patch.json": []byte(`{"type": "minecraft:flower"}`),
+		"data/test/worldgen/configured_feature/dead.json":         []byte(`{"type": "minecraft:ore"}`),
+		"data/test/loot_table/chests/reward.json":                 []byte(`{}`),
+		"data/test/loot_table/chests/forgotten.json":              []byte(`{}`),
+		"data/test/advancement/root.json":                         []byte(`{"rewards": {"function": "test:reward"}}`),
+		"data/test/function/reward.mcfunction":                    []byte("loot give @s loot test:chests/reward\n"),
+		"data/test/function/orphan.mcfunction":                    []byte("say nobody calls me\n"),
+		"data/minecraft/loot_table/blocks/dirt.json":              []byte(`{}`),
+	})
+
+	pack, err := LoadPack(root)
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+
+	if findings := checkUnused(pack, PackOptions{}); len(findings) != 0 {
+		t.Errorf("Unused resources should only be reported when requested, got %v", findings)
+	}
+
+	var messages []string
+	for _, finding := range checkUnused(pack, PackOptions{ReportUnused: true}) {
+		if finding.Severity != SeverityWarning {
+			t.Errorf("Expected a warning, got %s", finding.Severity)
+		}
+		messages = append(messages, finding.String())
+	}
+	expected := []string{
+		"data/test/function/orphan.mcfunction: warning: function test:orphan is never referenced [MCHECK042 unused-resource]",
+		"data/test/loot_table/chests/forgotten.json: warning: loot_table test:chests/forgotten is never referenced [MCHECK042 unused-resource]",
+		"data/test/worldgen/configured_feature/dead.json: warning: worldgen/configured_feature test:dead is never referenced [MCHECK042 unused-resource]",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+	}
+}
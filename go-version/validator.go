@@ -1,10 +1,16 @@
-package main
+package mcheck
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"math"
 	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode/utf16"
 )
 
 // Version represents a Minecraft version for comparison
@@ -18,35 +24,37 @@ func (v Version) String() string {
 	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
 }
 
+// Compare returns a negative number, zero or a positive number as v comes
+// before, is or comes after other. Only the sign is meaningful.
 func (v Version) Compare(other Version) int {
 	if v.Major != other.Major {
-		return v.Major - other.Major
+		return cmp.Compare(v.Major, other.Major)
 	}
 	if v.Minor != other.Minor {
-		return v.Minor - other.Minor
+		return cmp.Compare(v.Minor, other.Minor)
 	}
-	return v.Patch - other.Patch
+	return cmp.Compare(v.Patch, other.Patch)
 }
 
-func parseVersion(s string) (Version, error) {
+func ParseVersion(s string) (Version, error) {
 	parts := strings.Split(s, ".")
 	if len(parts) < 2 || len(parts) > 3 {
 		return Version{}, fmt.Errorf("invalid version format: %s", s)
 	}
 
-	major, err := strconv.Atoi(parts[0])
+	major, err := parseVersionPart(parts[0])
 	if err != nil {
 		return Version{}, fmt.Errorf("invalid major version: %s", parts[0])
 	}
 
-	minor, err := strconv.Atoi(parts[1])
+	minor, err := parseVersionPart(parts[1])
 	if err != nil {
 		return Version{}, fmt.Errorf("invalid minor version: %s", parts[1])
 	}
 
 	patch := 0
 	if len(parts) == 3 {
-		patch, err = strconv.Atoi(parts[2])
+		patch, err = parseVersionPart(parts[2])
 		if err != nil {
 			return Version{}, fmt.Errorf("invalid patch version: %s", parts[2])
 		}
@@ -55,48 +63,209 @@ func parseVersion(s string) (Version, error) {
 	return Version{Major: major, Minor: minor, Patch: patch}, nil
 }
 
+// parseVersionPart parses a number of a version, which is all digits: no
+// signs, which strconv.Atoi would accept
+func parseVersionPart(s string) (int, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, fmt.Errorf("not a number: %q", s)
+	}
+	return strconv.Atoi(s)
+}
+
+// ParseVersions parses a list of versions and ranges like
+// "1.20.1,1.20.5..1.21" into the versions it names, in order. Either end of
+// a range may be left out to start from the oldest or end at the newest known
+// version. A version without a patch, like 1.21, stands for the whole 1.21.x
+// line: on its own and as the end of a range it takes in every known patch
+// release.
+func ParseVersions(spec string) ([]Version, error) {
+	var versions []Version
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		from, to, isRange := strings.Cut(item, "..")
+		if !isRange {
+			to = from
+		}
+		if from == "" {
+			from = KnownVersions[0]
+		}
+		if to == "" {
+			to = KnownVersions[len(KnownVersions)-1]
+		}
+
+		low, err := ParseVersion(from)
+		if err != nil {
+			return nil, err
+		}
+		high, err := ParseVersion(to)
+		if err != nil {
+			return nil, err
+		}
+		if low.Compare(high) > 0 {
+			return nil, fmt.Errorf("version range %s ends before it starts", item)
+		}
+		wholeLine := strings.Count(to, ".") == 1
+
+		found := false
+		for _, known := range KnownVersions {
+			version, _ := ParseVersion(known)
+			inLine := wholeLine && version.Major == high.Major && version.Minor == high.Minor
+			if version.Compare(low) >= 0 && (version.Compare(high) <= 0 || inLine) {
+				versions = appendVersion(versions, version)
+				found = true
+			}
+		}
+		// Versions mcheck does not know yet can still be targeted one by one
+		if !found {
+			if isRange {
+				return nil, fmt.Errorf("no known versions in %s", item)
+			}
+			versions = appendVersion(versions, low)
+		}
+	}
+	slices.SortFunc(versions, Version.Compare)
+	return versions, nil
+}
+
+// appendVersion appends a version unless versions has it already
+func appendVersion(versions []Version, version Version) []Version {
+	if slices.Contains(versions, version) {
+		return versions
+	}
+	return append(versions, version)
+}
+
+// KnownVersions are the Minecraft releases offered for --version, in order.
+// Any version parseVersion accepts can be targeted.
+var KnownVersions = []string{
+	"1.16", "1.16.1", "1.16.2", "1.16.3", "1.16.4", "1.16.5",
+	"1.17", "1.17.1",
+	"1.18", "1.18.1", "1.18.2",
+	"1.19", "1.19.1", "1.19.2", "1.19.3", "1.19.4",
+	"1.20", "1.20.1", "1.20.2", "1.20.3", "1.20.4", "1.20.5", "1.20.6",
+	"1.21", "1.21.1", "1.21.2", "1.21.3", "1.21.4", "1.21.5", "1.21.6",
+}
+
+// KnownFeatures are the feature flags offered for --features. Datapacks
+// enable them in pack.mcmeta to use experimental content.
+var KnownFeatures = []string{
+	"bundle", "minecart_improvements", "redstone_experiments", "trade_rebalance",
+	"update_1_20", "update_1_21", "winter_drop",
+}
+
 // ValidationContext holds context information for validation
 type ValidationContext struct {
 	Version     Version
-	Path        []string // current path in the JSON for error reporting
+	Path        []string // current path in the JSON for error reporting, never modified in place
 	Definitions map[string]Validator // type definitions from use statements and type aliases
+	Dispatches  map[string]map[string]Validator // dispatcher cases keyed by registry, then key
+	Aliases     map[string]map[string]string    // canonical keys of alias dispatch keys, keyed by registry
+	Parents     []interface{} // enclosing objects of the current value, innermost last
+	NBT         bool          // validating NBT data, where booleans are stored as bytes
+	Lenient     bool          // accept 0/1 for booleans and true/false for numbers with a warning
+	Features    map[string]bool // enabled feature flags, like update_1_21
+
+	// StrictSchema makes values that cannot be fully checked errors, like
+	// those of types that fell back to any
+	StrictSchema bool
+
+	// InclusiveUntil keeps declarations in the version their #[until] names,
+	// as mcheck did before it followed vanilla-mcdoc, where until is the
+	// first version without them
+	InclusiveUntil bool
+
+	// Done ends validation early once it is closed, leaving the findings
+	// incomplete, like when the client of a request has gone away
+	Done <-chan struct{}
+
+	// ExtraSchemas tells that the schemas of mods are loaded, so that a
+	// modded dispatch key none of them declares is unknown rather than
+	// expected
+	ExtraSchemas bool
+}
+
+// Child returns a context for validating a field or element of the current
+// value. Contexts are never modified once created: the child gets its own copy
+// of the path, so errors keep the path they were created with and a context
+// can be shared by validations running on several goroutines.
+func (ctx *ValidationContext) Child(segment string) *ValidationContext {
+	child := *ctx
+	child.Path = append(ctx.Path[:len(ctx.Path):len(ctx.Path)], segment)
+	return &child
+}
+
+// cancelled reports whether validation was given up on, see Done
+func (ctx *ValidationContext) cancelled() bool {
+	select {
+	case <-ctx.Done:
+		return true
+	default:
+		return false
+	}
 }
 
-// ValidationError represents a validation error
-type ValidationError struct {
-	Path    []string
-	Message string
+// WithParent returns a context whose innermost enclosing object is obj
+func (ctx *ValidationContext) WithParent(obj interface{}) *ValidationContext {
+	child := *ctx
+	child.Parents = append(ctx.Parents[:len(ctx.Parents):len(ctx.Parents)], obj)
+	return &child
 }
 
-func (e ValidationError) Error() string {
-	if len(e.Path) == 0 {
-		return e.Message
+// beforeUntil reports whether the target version comes before an #[until]
+// bound, which is exclusive unless InclusiveUntil is set
+func (ctx *ValidationContext) beforeUntil(until Version) bool {
+	if ctx.InclusiveUntil {
+		return ctx.Version.Compare(until) <= 0
 	}
-	return fmt.Sprintf("at %s: %s", strings.Join(e.Path, "."), e.Message)
+	return ctx.Version.Compare(until) < 0
 }
 
-// Validator interface for all validation types
+// failf returns a finding about the current value that fails validation
+func failf(ctx *ValidationContext, rule, format string, args ...interface{}) []Finding {
+	return []Finding{{Path: ctx.Path, Severity: SeverityError, Message: fmt.Sprintf(format, args...), Rule: rule, format: format, args: args}}
+}
+
+// warnf returns a finding about the current value that does not fail
+// validation
+func warnf(ctx *ValidationContext, rule, format string, args ...interface{}) []Finding {
+	return []Finding{{Path: ctx.Path, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...), Rule: rule, format: format, args: args}}
+}
+
+// notef returns a finding noting something about the current value that is
+// neither wrong nor questionable
+func notef(ctx *ValidationContext, rule, format string, args ...interface{}) []Finding {
+	return []Finding{{Path: ctx.Path, Severity: SeverityInfo, Message: fmt.Sprintf(format, args...), Rule: rule, format: format, args: args}}
+}
+
+// Validator interface for all validation types. Validate returns what it
+// found wrong with a value, which is valid unless a finding is an error.
 type Validator interface {
-	Validate(value interface{}, ctx *ValidationContext) error
+	Validate(value interface{}, ctx *ValidationContext) []Finding
 	AppliesForVersion(ctx *ValidationContext) bool
 }
 
 // BaseValidator contains common fields for version checking
 type BaseValidator struct {
-	Since string // version when this was introduced
-	Until string // version when this was removed
+	Since   string // version when this was introduced
+	Until   string // first version without this
+	Feature string // feature flag this needs, like update_1_21
 }
 
+// AppliesForVersion reports whether a declaration exists in the target
+// version with the enabled feature flags
 func (bv BaseValidator) AppliesForVersion(ctx *ValidationContext) bool {
+	if bv.Feature != "" && !ctx.Features[bv.Feature] {
+		return false
+	}
 	if bv.Since != "" {
-		sinceVersion, err := parseVersion(bv.Since)
+		sinceVersion, err := ParseVersion(bv.Since)
 		if err == nil && ctx.Version.Compare(sinceVersion) < 0 {
 			return false
 		}
 	}
 	if bv.Until != "" {
-		untilVersion, err := parseVersion(bv.Until)
-		if err == nil && ctx.Version.Compare(untilVersion) > 0 {
+		untilVersion, err := ParseVersion(bv.Until)
+		if err == nil && !ctx.beforeUntil(untilVersion) {
 			return false
 		}
 	}
@@ -106,10 +275,15 @@ func (bv BaseValidator) AppliesForVersion(ctx *ValidationContext) bool {
 // PrimitiveValidator validates primitive types (string, int, float, boolean)
 type PrimitiveValidator struct {
 	BaseValidator
-	Type string // "string", "int", "float", "boolean", "double", "any"
+	Type string // "string", "byte", "short", "int", "long", "float", "double", "boolean", "any" or "unsafe"
+
+	// Unresolved says why the schema type of an any validator could not be
+	// built, like "unresolved type BiomeEffects". Values it accepts are
+	// noted as not fully validated.
+	Unresolved string
 }
 
-func (pv PrimitiveValidator) Validate(value interface{}, ctx *ValidationContext) error {
+func (pv PrimitiveValidator) Validate(value interface{}, ctx *ValidationContext) []Finding {
 	if !pv.AppliesForVersion(ctx) {
 		return nil
 	}
@@ -117,35 +291,108 @@ func (pv PrimitiveValidator) Validate(value interface{}, ctx *ValidationContext)
 	switch pv.Type {
 	case "string":
 		if _, ok := value.(string); !ok {
-			return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("expected string, got %T", value)}
+			return failf(ctx, RuleWrongType, "expected string, got %T", value)
 		}
-	case "int":
+	case "byte", "short", "int", "long":
 		switch v := value.(type) {
 		case float64:
-			if v != float64(int64(v)) {
-				return ValidationError{Path: ctx.Path, Message: "expected integer, got float"}
+			if math.IsInf(v, 0) || v != math.Trunc(v) {
+				return failf(ctx, RuleWrongType, "expected integer, got float")
+			}
+			if bounds := integerBounds[pv.Type]; v < float64(bounds[0]) || v > float64(bounds[1]) {
+				return failf(ctx, RuleOutOfRange, "value %g is out of range for %s (%d..%d)", v, pv.Type, bounds[0], bounds[1])
 			}
 		case int, int64:
 			// OK
+		case bool:
+			return booleanNumber(v, pv.Type, ctx)
 		default:
-			return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("expected int, got %T", value)}
+			return failf(ctx, RuleWrongType, "expected %s, got %T", pv.Type, value)
 		}
 	case "float", "double":
-		if _, ok := value.(float64); !ok {
-			return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("expected float, got %T", value)}
+		if v, ok := value.(bool); ok {
+			return booleanNumber(v, pv.Type, ctx)
+		}
+		v, ok := value.(float64)
+		if !ok {
+			return failf(ctx, RuleWrongType, "expected float, got %T", value)
+		}
+		if pv.Type == "float" && math.Abs(v) > math.MaxFloat32 && !math.IsInf(v, 0) {
+			return failf(ctx, RuleOutOfRange, "value %g is out of range for float", v)
 		}
 	case "boolean":
+		if v, ok := value.(float64); ok && ctx.NBT && (v == 0 || v == 1) {
+			return nil
+		}
+		if v, ok := value.(float64); ok && (v == 0 || v == 1) {
+			return numberBoolean(v, ctx)
+		}
 		if _, ok := value.(bool); !ok {
-			return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("expected boolean, got %T", value)}
+			return failf(ctx, RuleWrongType, "expected boolean, got %T", value)
+		}
+	case "any", "unsafe":
+		// any type is always valid, and unsafe marks values the game reads
+		// without checking them
+		if pv.Unresolved != "" {
+			if ctx.StrictSchema {
+				return failf(ctx, RulePartiallyValidated, "%s cannot be fully validated: %s", valueSubject(ctx), pv.Unresolved)
+			}
+			return notef(ctx, RulePartiallyValidated, "%s not fully validated: %s", valueSubject(ctx), pv.Unresolved)
 		}
-	case "any":
-		// any type is always valid
 	default:
-		return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("unknown primitive type: %s", pv.Type)}
+		return failf(ctx, RuleSchemaError, "unknown primitive type: %s", pv.Type)
 	}
 	return nil
 }
 
+// integerBounds are the smallest and largest values of the integer types,
+// which the game reads JSON numbers as
+var integerBounds = map[string][2]int64{
+	"byte":  {math.MinInt8, math.MaxInt8},
+	"short": {math.MinInt16, math.MaxInt16},
+	"int":   {math.MinInt32, math.MaxInt32},
+	"long":  {math.MinInt64, math.MaxInt64},
+}
+
+// isIntegerType reports whether a primitive type holds whole numbers
+func isIntegerType(primitive string) bool {
+	_, ok := integerBounds[primitive]
+	return ok
+}
+
+// valueSubject names the current value in messages, like "field 'effects'"
+func valueSubject(ctx *ValidationContext) string {
+	if len(ctx.Path) > 0 && !strings.HasPrefix(ctx.Path[len(ctx.Path)-1], "[") {
+		return fmt.Sprintf("field '%s'", ctx.Path[len(ctx.Path)-1])
+	}
+	return "value"
+}
+
+// The game reads JSON numbers as booleans (non-zero is true) and booleans as
+// the numbers 1 and 0, so these mixups work in game but are easy to get
+// wrong. They are errors, or warnings in lenient mode.
+
+// numberBoolean handles 0 or 1 written where a boolean is expected
+func numberBoolean(value float64, ctx *ValidationContext) []Finding {
+	suggestion := value != 0
+	if ctx.Lenient {
+		return warnf(ctx, RuleBooleanNumber, "number %g is read as %t, write %t instead", value, suggestion, suggestion)
+	}
+	return failf(ctx, RuleBooleanNumber, "expected boolean, got number %g; write %t instead", value, suggestion)
+}
+
+// booleanNumber handles true or false written where a number is expected
+func booleanNumber(value bool, numberType string, ctx *ValidationContext) []Finding {
+	suggestion := 0
+	if value {
+		suggestion = 1
+	}
+	if ctx.Lenient {
+		return warnf(ctx, RuleBooleanNumber, "%t is read as %d, write %d instead", value, suggestion, suggestion)
+	}
+	return failf(ctx, RuleBooleanNumber, "expected %s, got boolean %t; write %d instead", numberType, value, suggestion)
+}
+
 // RangeValidator validates numeric ranges with inclusive/exclusive bounds
 type RangeValidator struct {
 	BaseValidator
@@ -155,7 +402,7 @@ type RangeValidator struct {
 	MaxExclusive bool
 }
 
-func (rv RangeValidator) Validate(value interface{}, ctx *ValidationContext) error {
+func (rv RangeValidator) Validate(value interface{}, ctx *ValidationContext) []Finding {
 	if !rv.AppliesForVersion(ctx) {
 		return nil
 	}
@@ -168,18 +415,31 @@ func (rv RangeValidator) Validate(value interface{}, ctx *ValidationContext) err
 		numValue = float64(v)
 	case int64:
 		numValue = float64(v)
+	case bool:
+		// Lenient mode lets booleans through as the numbers they are read as
+		if !ctx.Lenient {
+			return failf(ctx, RuleWrongType, "expected number for range validation, got %T", value)
+		}
+		if v {
+			numValue = 1
+		}
 	default:
-		return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("expected number for range validation, got %T", value)}
+		return failf(ctx, RuleWrongType, "expected number for range validation, got %T", value)
 	}
 	
+	// NaN compares false against every bound, so reject it explicitly
+	if math.IsNaN(numValue) {
+		return failf(ctx, RuleOutOfRange, "value NaN is outside range %s", describeRange(&rv))
+	}
+
 	if rv.Min != nil {
 		if rv.MinExclusive {
 			if numValue <= *rv.Min {
-				return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("value %g must be greater than %g", numValue, *rv.Min)}
+				return failf(ctx, RuleOutOfRange, "value %g must be greater than %g (range %s)", numValue, *rv.Min, describeRange(&rv))
 			}
 		} else {
 			if numValue < *rv.Min {
-				return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("value %g must be greater than or equal to %g", numValue, *rv.Min)}
+				return failf(ctx, RuleOutOfRange, "value %g must be greater than or equal to %g (range %s)", numValue, *rv.Min, describeRange(&rv))
 			}
 		}
 	}
@@ -187,11 +447,11 @@ func (rv RangeValidator) Validate(value interface{}, ctx *ValidationContext) err
 	if rv.Max != nil {
 		if rv.MaxExclusive {
 			if numValue >= *rv.Max {
-				return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("value %g must be less than %g", numValue, *rv.Max)}
+				return failf(ctx, RuleOutOfRange, "value %g must be less than %g (range %s)", numValue, *rv.Max, describeRange(&rv))
 			}
 		} else {
 			if numValue > *rv.Max {
-				return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("value %g must be less than or equal to %g", numValue, *rv.Max)}
+				return failf(ctx, RuleOutOfRange, "value %g must be less than or equal to %g (range %s)", numValue, *rv.Max, describeRange(&rv))
 			}
 		}
 	}
@@ -204,36 +464,107 @@ type ArrayValidator struct {
 	BaseValidator
 	ElementValidator Validator
 	LengthConstraint *RangeValidator
+
+	// Typed is the element type of a typed array like int[], which holds
+	// NBT byte, int or long arrays such as the four ints of a UUID. It is
+	// empty for lists like [int].
+	Typed string
 }
 
-func (av ArrayValidator) Validate(value interface{}, ctx *ValidationContext) error {
+func (av ArrayValidator) Validate(value interface{}, ctx *ValidationContext) []Finding {
 	if !av.AppliesForVersion(ctx) {
 		return nil
 	}
 	
+	// Streamed arrays had their elements validated as they were read
+	if streamed, ok := value.(streamedArray); ok {
+		return av.lengthError(streamed.length, ctx)
+	}
+
 	arr, ok := value.([]interface{})
 	if !ok {
-		return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("expected array, got %T", value)}
+		if av.Typed != "" {
+			return failf(ctx, RuleWrongType, "expected %s array, got %T", av.Typed, value)
+		}
+		return failf(ctx, RuleWrongType, "expected array, got %T", value)
 	}
 	
 	// Validate array length if constrained
-	if av.LengthConstraint != nil {
-		lengthValue := float64(len(arr))
-		if err := av.LengthConstraint.Validate(lengthValue, ctx); err != nil {
-			return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("array length validation failed: %s", err.Error())}
-		}
+	if findings := av.lengthError(len(arr), ctx); findings != nil {
+		return findings
 	}
 	
-	// Validate each element
+	// Validate each element. The list is a level of its own for dynamic
+	// dispatches of its elements, see dynamicKey.
+	var findings []Finding
+	listCtx := ctx.WithParent(arr)
 	for i, elem := range arr {
-		ctx.Path = append(ctx.Path, fmt.Sprintf("[%d]", i))
-		if err := av.ElementValidator.Validate(elem, ctx); err != nil {
-			return err
+		if ctx.cancelled() {
+			return findings
+		}
+		elemCtx := listCtx.Child(fmt.Sprintf("[%d]", i))
+		elemFindings := av.ElementValidator.Validate(elem, elemCtx)
+		if elem == nil && hasErrors(elemFindings) {
+			elemFindings = nullError(av.ElementValidator, elemCtx, false)
 		}
-		ctx.Path = ctx.Path[:len(ctx.Path)-1]
+		findings = append(findings, elemFindings...)
 	}
 	
-	return nil
+	return findings
+}
+
+// lengthError reports a length outside the array's length range. Typed
+// arrays of a fixed length, like the int[] @ 4 of a UUID, say how many
+// elements they need.
+func (av ArrayValidator) lengthError(length int, ctx *ValidationContext) []Finding {
+	rv := av.LengthConstraint
+	if rv == nil {
+		return nil
+	}
+	kind := "list"
+	if av.Typed != "" {
+		kind = av.Typed + " array"
+	}
+	if length == 0 && requiresContent(rv) {
+		return emptyError(kind, rv, ctx)
+	}
+	failed, ok := firstError(rv.Validate(float64(length), ctx))
+	if !ok {
+		return nil
+	}
+	if av.Typed == "" {
+		return failf(ctx, RuleInvalidLength, "array length validation failed: %s", failed.text())
+	}
+	if rv.Min != nil && rv.Max != nil && *rv.Min == *rv.Max {
+		return failf(ctx, RuleInvalidLength, "%s must have %g elements, got %d", kind, *rv.Min, length)
+	}
+	return failf(ctx, RuleInvalidLength, "%s has %d elements, expected %s", kind, length, describeRange(rv))
+}
+
+// requiresContent reports whether a length range rules out empty values
+func requiresContent(rv *RangeValidator) bool {
+	return rv.Min != nil && (*rv.Min > 0 || (*rv.Min == 0 && rv.MinExclusive))
+}
+
+// emptyError reports an empty list or string whose length range requires
+// content, naming the field like "pools must not be empty"
+func emptyError(kind string, rv *RangeValidator, ctx *ValidationContext) []Finding {
+	name := kind
+	if len(ctx.Path) > 0 && !strings.HasPrefix(ctx.Path[len(ctx.Path)-1], "[") {
+		name = ctx.Path[len(ctx.Path)-1]
+	}
+	return failf(ctx, RuleInvalidLength, "%s must not be empty (length %s)", name, describeRange(rv))
+}
+
+// nullError reports a JSON null the validator rejected. Minecraft reads null
+// as a missing value, so optional fields and computed keys should be omitted
+// instead, and everywhere else a value of the expected type is needed.
+func nullError(validator Validator, ctx *ValidationContext, omittable bool) []Finding {
+	message := fmt.Sprintf("null is not allowed here, expected %s", describeTypeIn(validator, ctx))
+	if omittable {
+		message = "null is not allowed here, omit the field instead"
+	}
+	return failf(ctx, RuleNullValue, "%s", message)
 }
 
 // StructField represents a field in a struct validator
@@ -241,75 +572,305 @@ type StructField struct {
 	Name      string
 	Validator Validator
 	Optional  bool
+	Doc       string    // doc comment from the schema
+	Source    SourcePos // where the schema declares the field
+	BaseValidator
+}
+
+// DynamicField is a computed field like [#[id="item"] string]: int, which
+// applies to every key of an object matching the key type
+type DynamicField struct {
+	Key       Validator
+	Validator Validator
+	Optional  bool
 	BaseValidator
 }
 
 // StructValidator validates object structures
 type StructValidator struct {
 	BaseValidator
-	Fields       []StructField
-	SpreadFields []Validator // for ...OtherStruct syntax
+	Name          string    // empty for anonymous structs
+	Source        SourcePos // where the schema declares the struct
+	Fields        []StructField
+	SpreadFields  []Validator    // for ...OtherStruct syntax
+	SpreadAt      []int          // number of Fields declared before each spread
+	DynamicFields []DynamicField // for [KeyType]: ValueType syntax
 }
 
-func (sv StructValidator) Validate(value interface{}, ctx *ValidationContext) error {
-	if !sv.AppliesForVersion(ctx) {
+func (sv StructValidator) Validate(value interface{}, ctx *ValidationContext) []Finding {
+	if !sv.AppliesForVersion(ctx) || ctx.cancelled() {
 		return nil
 	}
 	
 	obj, ok := value.(map[string]interface{})
 	if !ok {
-		return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("expected object, got %T", value)}
+		return failf(ctx, RuleWrongType, "expected object, got %T", value)
 	}
 	
+	// Make the object available to dynamic dispatches like minecraft:foo[[type]]
+	objCtx := ctx.WithParent(obj)
+
 	// Track which fields we've seen
-	seenFields := make(map[string]bool)
+	seenFields := getFieldSet()
+	defer putFieldSet(seenFields)
+	open, findings := sv.validateFields(obj, ctx, objCtx, seenFields, nil, 0)
+	if open {
+		return findings
+	}
+
+	buffer := getStrings()
+	defer putStrings(buffer)
+	for _, fieldName := range sortedKeys(obj, buffer) {
+		if seenFields[fieldName] {
+			continue
+		}
+		if keyFindings := sv.keyError(fieldName, objCtx); keyFindings != nil {
+			findings = append(findings, keyFindings...)
+			continue
+		}
+		finding := sv.structFinding(ctx, RuleUnknownField, "unexpected field '%s'", fieldName)
+		if feature := sv.disabledFeature(fieldName, ctx); feature != "" {
+			finding.Message += fmt.Sprintf(", it needs the %s feature flag", feature)
+		}
+		finding.Source = sv.Source.String()
+		findings = append(findings, finding)
+	}
 	
+	return findings
+}
+
+// structFinding returns a finding about the object as a whole, naming the
+// struct that declares its fields
+func (sv StructValidator) structFinding(ctx *ValidationContext, rule, format string, args ...interface{}) Finding {
+	finding := failf(ctx, rule, format, args...)[0]
+	finding.Schema = sv.Name
+	return finding
+}
+
+// disabledFeature returns the feature flag that a field of the target
+// version is missing for, or "" if the struct declares no such field
+func (sv StructValidator) disabledFeature(name string, ctx *ValidationContext) string {
+	for _, field := range sv.Fields {
+		if field.Name == name && field.Feature != "" && !ctx.Features[field.Feature] {
+			if (BaseValidator{Since: field.Since, Until: field.Until}).AppliesForVersion(ctx) {
+				return field.Feature
+			}
+		}
+	}
+	return ""
+}
+
+// missingField reports a required field that is absent, saying which schema
+// declaration requires it, like "required by worldgen/biome.mcdoc:42 (since
+// 1.19)", so that users can look up why
+func (sv StructValidator) missingField(ctx *ValidationContext, field StructField) Finding {
+	finding := sv.structFinding(ctx, RuleMissingField, "required field '%s' is missing", field.Name)
+	if field.Source.Line == 0 {
+		return finding
+	}
+	finding.Source = field.Source.String()
+	finding.Message += "; required by " + finding.Source
+	if bounds := describeBounds(BaseValidator{Since: field.Since, Feature: field.Feature}); bounds != "" {
+		finding.Message += " (" + bounds + ")"
+	}
+	return finding
+}
+
+// keyError explains why a key matched none of the struct's computed fields,
+// like [#[id] ArmorMaterial]: string, using the first key type's error.
+// It returns nil for structs without computed fields.
+func (sv StructValidator) keyError(key string, objCtx *ValidationContext) []Finding {
+	for _, dynamic := range sv.DynamicFields {
+		if !dynamic.AppliesForVersion(objCtx) {
+			continue
+		}
+		failed, ok := firstError(dynamic.Key.Validate(key, objCtx.Child(key)))
+		if !ok {
+			continue
+		}
+		return failf(objCtx, failed.Rule, "unexpected key '%s', keys must be %s: %s", key, DescribeType(dynamic.Key), failed.Message)
+	}
+	return nil
+}
+
+// validateFields validates the declared, spread and computed fields of the
+// struct against obj, recording the fields it accounts for in seenFields. It
+// reports whether the struct is open to any other fields, which is the case
+// when one of its spreads cannot be resolved, like a type from another file.
+// Fields named in overridden are declared by a struct spreading this one and
+// take precedence over its own.
+func (sv StructValidator) validateFields(obj map[string]interface{}, ctx, objCtx *ValidationContext, seenFields, overridden map[string]bool, depth int) (bool, []Finding) {
 	// Validate each defined field
+	var findings []Finding
 	for _, field := range sv.Fields {
-		if !field.AppliesForVersion(ctx) {
+		if !field.AppliesForVersion(ctx) || overridden[field.Name] {
 			continue
 		}
 		
 		fieldValue, exists := obj[field.Name]
 		if !exists {
 			if !field.Optional {
-				return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("required field '%s' is missing", field.Name)}
+				findings = append(findings, sv.missingField(ctx, field))
 			}
 			continue
 		}
 		
 		seenFields[field.Name] = true
-		ctx.Path = append(ctx.Path, field.Name)
-		if err := field.Validator.Validate(fieldValue, ctx); err != nil {
-			return err
+		fieldCtx := objCtx.Child(field.Name)
+		fieldFindings := field.Validator.Validate(fieldValue, fieldCtx)
+		if fieldValue == nil && hasErrors(fieldFindings) {
+			fieldFindings = nullError(field.Validator, fieldCtx, field.Optional)
 		}
-		ctx.Path = ctx.Path[:len(ctx.Path)-1]
+		findings = append(findings, fieldFindings...)
 	}
 	
-	// Validate spread fields (additional properties allowed by ...OtherStruct)
-	for fieldName, fieldValue := range obj {
-		if seenFields[fieldName] {
+	// Spreads like ...Other or ...minecraft:foo[[type]] add the fields of
+	// the struct they resolve to, except for those declared here
+	open := false
+	declared := sv.declaredFields(ctx, overridden)
+	for _, spread := range sv.SpreadFields {
+		dispatch, isDispatch := spread.(*DispatchValidator)
+		if isDispatch && dispatch.AppliesForVersion(ctx) {
+			findings = append(findings, dispatch.unknownKeyWarning(obj, objCtx, objCtx)...)
+		}
+		spreadStruct, ok := resolveSpread(spread, obj, objCtx, depth)
+		if !ok {
+			if ctx.StrictSchema {
+				findings = append(findings, failf(ctx, RulePartiallyValidated, "cannot check for unknown fields: spread ...%s cannot be resolved", DescribeType(spread))...)
+			}
+			open = true
 			continue
 		}
-		
-		// Try to validate against spread fields
-		validated := false
-		for _, spreadValidator := range sv.SpreadFields {
-			ctx.Path = append(ctx.Path, fieldName)
-			if err := spreadValidator.Validate(fieldValue, ctx); err == nil {
-				validated = true
-				ctx.Path = ctx.Path[:len(ctx.Path)-1]
-				break
+		if spreadStruct == nil || !spreadStruct.AppliesForVersion(ctx) {
+			continue
+		}
+		spreadOpen, spreadFindings := spreadStruct.validateFields(obj, ctx, objCtx, seenFields, declared, depth+1)
+		findings = append(findings, spreadFindings...)
+		if isDispatch {
+			findings = append(findings, dispatch.aliasWarning(obj, objCtx, objCtx)...)
+		}
+		open = open || spreadOpen
+	}
+
+	// Computed fields validate every remaining key matching their key type
+	if len(sv.DynamicFields) == 0 {
+		return open, findings
+	}
+	buffer := getStrings()
+	defer putStrings(buffer)
+	names := sortedKeys(obj, buffer)
+	for _, dynamic := range sv.DynamicFields {
+		if !dynamic.AppliesForVersion(ctx) {
+			continue
+		}
+		for _, fieldName := range names {
+			fieldCtx := objCtx.Child(fieldName)
+			if seenFields[fieldName] || hasErrors(dynamic.Key.Validate(fieldName, fieldCtx)) {
+				continue
 			}
-			ctx.Path = ctx.Path[:len(ctx.Path)-1]
+			seenFields[fieldName] = true
+			fieldFindings := dynamic.Validator.Validate(obj[fieldName], fieldCtx)
+			if obj[fieldName] == nil && hasErrors(fieldFindings) {
+				fieldFindings = nullError(dynamic.Validator, fieldCtx, true)
+			}
+			findings = append(findings, fieldFindings...)
 		}
-		
-		if !validated && len(sv.SpreadFields) == 0 {
-			return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("unexpected field '%s'", fieldName)}
+	}
+
+	return open, findings
+}
+
+// declaredFields returns the names of the fields that override those of the
+// struct's spreads: its own fields for the version and those it is overridden by
+func (sv StructValidator) declaredFields(ctx *ValidationContext, overridden map[string]bool) map[string]bool {
+	if len(sv.SpreadFields) == 0 {
+		return nil
+	}
+	declared := make(map[string]bool, len(overridden)+len(sv.Fields))
+	for name := range overridden {
+		declared[name] = true
+	}
+	for _, field := range sv.Fields {
+		if field.AppliesForVersion(ctx) {
+			declared[field.Name] = true
 		}
 	}
-	
-	return nil
+	return declared
+}
+
+// maxSpreadDepth bounds spread resolution so that recursive schemas terminate
+const maxSpreadDepth = 32
+
+// resolveSpread finds the struct a spread adds fields from. It returns false
+// when the spread cannot be resolved, and a nil struct when the spread
+// resolves to nothing, like a dispatch case that is not applicable.
+func resolveSpread(spread Validator, obj map[string]interface{}, ctx *ValidationContext, depth int) (*StructValidator, bool) {
+	if depth > maxSpreadDepth {
+		return nil, false
+	}
+
+	switch v := spread.(type) {
+	case *StructValidator:
+		return v, true
+	case *ReferenceValidator:
+		definition, ok := ctx.Definitions[v.TypeName]
+		if !ok {
+			return nil, false
+		}
+		return resolveSpread(definition, obj, ctx, depth+1)
+	case *AttributedValidator:
+		if !v.AppliesForVersion(ctx) {
+			return nil, true
+		}
+		return resolveSpread(v.InnerValidator, obj, ctx, depth+1)
+	case *DispatchValidator:
+		if !v.AppliesForVersion(ctx) {
+			return nil, true
+		}
+		target := v.Resolve(obj, ctx)
+		if target == nil {
+			return nil, false
+		}
+		return resolveSpread(target, obj, ctx, depth+1)
+	case *UnionValidator:
+		if !v.AppliesForVersion(ctx) {
+			return nil, true
+		}
+		return resolveUnionSpread(v, obj, ctx, depth+1)
+	}
+	return nil, false
+}
+
+// resolveUnionSpread finds the alternative of a union of structs that a
+// spread adds fields from, like the case of a dispatcher declared as
+// (struct A {...} | struct B {...}). It is the first alternative whose fields
+// the object has without errors, or else the one accounting for the most
+// fields of the object, so that errors are reported against the struct the
+// object was most likely meant to be.
+func resolveUnionSpread(union *UnionValidator, obj map[string]interface{}, ctx *ValidationContext, depth int) (*StructValidator, bool) {
+	var best *StructValidator
+	bestSeen := -1
+	for _, alt := range union.applicable(ctx) {
+		candidate, ok := resolveSpread(alt, obj, ctx, depth)
+		if !ok {
+			return nil, false
+		}
+		if candidate == nil || !candidate.AppliesForVersion(ctx) {
+			continue
+		}
+
+		seen := getFieldSet()
+		open, findings := candidate.validateFields(obj, ctx, ctx, seen, nil, depth+1)
+		matched := len(seen)
+		putFieldSet(seen)
+		if open || !hasErrors(findings) {
+			return candidate, true
+		}
+		if matched > bestSeen {
+			best, bestSeen = candidate, matched
+		}
+	}
+	return best, true
 }
 
 // UnionValidator validates union types (value must match one of the alternatives)
@@ -318,24 +879,81 @@ type UnionValidator struct {
 	Alternatives []Validator
 }
 
-func (uv UnionValidator) Validate(value interface{}, ctx *ValidationContext) error {
+func (uv UnionValidator) Validate(value interface{}, ctx *ValidationContext) []Finding {
 	if !uv.AppliesForVersion(ctx) {
 		return nil
 	}
 	
-	var errors []string
-	for _, alt := range uv.Alternatives {
-		if err := alt.Validate(value, ctx); err == nil {
-			return nil // Successfully validated against one alternative
-		} else {
-			errors = append(errors, err.Error())
+	// In lenient mode an alternative matching exactly, like int for 1 in
+	// (boolean | int), wins over one accepting the value with a warning
+	strict := ctx
+	if ctx.Lenient {
+		exact := *ctx
+		exact.Lenient = false
+		strict = &exact
+	}
+
+	alternatives := uv.applicable(ctx)
+	if len(alternatives) == 1 {
+		// Errors are those of the only alternative rather than a union of one
+		return alternatives[0].Validate(value, ctx)
+	}
+
+	buffer := getStrings()
+	defer putStrings(buffer)
+	errors := *buffer
+	var schemaProblem []Finding
+	for _, alt := range alternatives {
+		findings := alt.Validate(value, strict)
+		failed, ok := firstError(findings)
+		if !ok {
+			*buffer = errors
+			return findings // Successfully validated against one alternative
+		}
+		if failed.SchemaProblem() && schemaProblem == nil {
+			schemaProblem = []Finding{failed}
+		}
+		errors = append(errors, failed.text())
+	}
+	*buffer = errors
+	if ctx.Lenient {
+		// Only the warnings of the alternative that matches are kept
+		for _, alt := range alternatives {
+			if findings := alt.Validate(value, ctx); !hasErrors(findings) {
+				return findings
+			}
 		}
 	}
 	
-	return ValidationError{
-		Path:    ctx.Path,
-		Message: fmt.Sprintf("value does not match any union alternative: %s", strings.Join(errors, "; ")),
+	// An alternative the schema cannot resolve might have matched, so the
+	// value is not known to be wrong
+	if schemaProblem != nil {
+		return schemaProblem
 	}
+	if len(errors) == 0 {
+		return failf(ctx, RuleNoUnionMatch, "no union alternative is available in version %s", ctx.Version)
+	}
+	return failf(ctx, RuleNoUnionMatch, "value does not match any union alternative: %s", strings.Join(errors, "; "))
+}
+
+// applicable returns the alternatives that exist in the target version.
+// Alternatives gated out of it by #[since], #[until] or #[feature] must not
+// match vacuously, nor be suggested by errors and descriptions, so they are
+// left out before matching.
+func (uv UnionValidator) applicable(ctx *ValidationContext) []Validator {
+	for i, alt := range uv.Alternatives {
+		if alt.AppliesForVersion(ctx) {
+			continue
+		}
+		alternatives := append([]Validator(nil), uv.Alternatives[:i]...)
+		for _, alt := range uv.Alternatives[i+1:] {
+			if alt.AppliesForVersion(ctx) {
+				alternatives = append(alternatives, alt)
+			}
+		}
+		return alternatives
+	}
+	return uv.Alternatives
 }
 
 // LiteralValidator validates literal values (strings, numbers, booleans)
@@ -344,13 +962,13 @@ type LiteralValidator struct {
 	Value interface{}
 }
 
-func (lv LiteralValidator) Validate(value interface{}, ctx *ValidationContext) error {
+func (lv LiteralValidator) Validate(value interface{}, ctx *ValidationContext) []Finding {
 	if !lv.AppliesForVersion(ctx) {
 		return nil
 	}
 	
 	if !reflect.DeepEqual(value, lv.Value) {
-		return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("expected literal value %v, got %v", lv.Value, value)}
+		return failf(ctx, RuleLiteralMismatch, "expected literal value %v, got %v", lv.Value, value)
 	}
 	return nil
 }
@@ -361,34 +979,63 @@ type ReferenceValidator struct {
 	TypeName string
 }
 
-func (rv ReferenceValidator) Validate(value interface{}, ctx *ValidationContext) error {
+func (rv ReferenceValidator) Validate(value interface{}, ctx *ValidationContext) []Finding {
 	if !rv.AppliesForVersion(ctx) {
 		return nil
 	}
 	
 	validator, exists := ctx.Definitions[rv.TypeName]
 	if !exists {
-		return ValidationError{Path: ctx.Path, Message: fmt.Sprintf("undefined type reference: %s", rv.TypeName)}
+		return failf(ctx, RuleSchemaError, "undefined type reference: %s", rv.TypeName)
 	}
 	
-	return validator.Validate(value, ctx)
+	// Findings within the type that no inner type claims relate to it
+	findings := validator.Validate(value, ctx)
+	for i := range findings {
+		if findings[i].Schema == "" {
+			findings[i].Schema = rv.TypeName
+		}
+	}
+	return findings
 }
 
 // AttributedValidator wraps another validator with attributes (version constraints)
 type AttributedValidator struct {
 	BaseValidator
 	InnerValidator Validator
-	Attributes     map[string]string // attribute name -> value
+	Attributes     map[string]string        // attribute name -> value
+	Args           map[string]AttributeArgs // attribute name -> structured arguments
+	Pattern        *regexp.Regexp           // compiled #[regex] constraint for strings
 }
 
-func (av AttributedValidator) Validate(value interface{}, ctx *ValidationContext) error {
+func (av AttributedValidator) Validate(value interface{}, ctx *ValidationContext) []Finding {
 	if !av.AppliesForVersion(ctx) {
 		return nil
 	}
 	
-	// TODO: Handle specific attributes like #[id], #[nbt_path], etc.
-	// For now, just validate the inner type
-	return av.InnerValidator.Validate(value, ctx)
+	findings := av.InnerValidator.Validate(value, ctx)
+	if hasErrors(findings) {
+		return findings
+	}
+
+	if text, ok := value.(string); ok && av.Pattern != nil && !av.Pattern.MatchString(text) {
+		source := av.Attributes["regex"]
+		if source == "" {
+			source = av.Attributes["pattern"]
+		}
+		return append(findings, failf(ctx, RulePatternMismatch, "string %q does not match pattern %s", text, source)...)
+	}
+
+	// Apply the attributes that constrain values, like #[uuid] and #[color],
+	// in order of their names so that the same one is reported each time
+	for _, name := range slices.Sorted(maps.Keys(av.Args)) {
+		if check, ok := attributeChecks[name]; ok {
+			if err := check(av.Args[name], value); err != nil {
+				return append(findings, failf(ctx, RuleInvalidFormat, "%s", err.Error())...)
+			}
+		}
+	}
+	return findings
 }
 
 // ConstrainedValidator applies constraints (like ranges) to a base type
@@ -398,16 +1045,317 @@ type ConstrainedValidator struct {
 	Constraint     Validator // typically a RangeValidator
 }
 
-func (cv ConstrainedValidator) Validate(value interface{}, ctx *ValidationContext) error {
+func (cv ConstrainedValidator) Validate(value interface{}, ctx *ValidationContext) []Finding {
 	if !cv.AppliesForVersion(ctx) {
 		return nil
 	}
 	
-	// First validate the base type
-	if err := cv.InnerValidator.Validate(value, ctx); err != nil {
-		return err
+	// First validate the base type, naming the range when the value itself
+	// has the wrong type
+	findings := cv.InnerValidator.Validate(value, ctx)
+	if hasErrors(findings) {
+		if rangeValidator, isRange := cv.Constraint.(*RangeValidator); isRange {
+			for i := range findings {
+				if findings[i].Severity == SeverityError && len(findings[i].Path) == len(ctx.Path) {
+					findings[i].Message += fmt.Sprintf(" (range %s)", describeRange(rangeValidator))
+				}
+			}
+		}
+		return findings
+	}
+	
+	// Ranges on strings constrain their length
+	if str, ok := value.(string); ok {
+		if rangeValidator, isRange := cv.Constraint.(*RangeValidator); isRange && str == "" && requiresContent(rangeValidator) {
+			return append(findings, emptyError("string", rangeValidator, ctx)...)
+		}
+		if failed, ok := firstError(cv.Constraint.Validate(float64(stringLength(str)), ctx)); ok {
+			return append(findings, failf(ctx, RuleInvalidLength, "string length validation failed: %s", failed.text())...)
+		}
+		return findings
 	}
 	
 	// Then apply the constraint
-	return cv.Constraint.Validate(value, ctx)
+	return append(findings, cv.Constraint.Validate(value, ctx)...)
+}
+
+// stringLength returns the length of a string as the game counts it, in the
+// UTF-16 code units of a Java string rather than in bytes
+func stringLength(str string) int {
+	length := 0
+	for _, r := range str {
+		if n := utf16.RuneLen(r); n > 0 {
+			length += n
+		} else {
+			length++
+		}
+	}
+	return length
+}
+
+// EnumValue is a single allowed value of an enum
+type EnumValue struct {
+	BaseValidator
+	Name  string
+	Value interface{}
+	Doc   string
+}
+
+// EnumValidator validates that a value is one of the values of an enum
+type EnumValidator struct {
+	BaseValidator
+	Name   string
+	Type   string // base type of the values, e.g. "string" or "int"
+	Values []EnumValue
+}
+
+func (ev EnumValidator) Validate(value interface{}, ctx *ValidationContext) []Finding {
+	if !ev.AppliesForVersion(ctx) {
+		return nil
+	}
+
+	var allowed []string
+	for _, enumValue := range ev.Values {
+		if !enumValue.AppliesForVersion(ctx) {
+			continue
+		}
+		if reflect.DeepEqual(value, enumValue.Value) {
+			return nil
+		}
+		allowed = append(allowed, fmt.Sprintf("%#v", enumValue.Value))
+	}
+
+	if len(allowed) == 0 {
+		return failf(ctx, RuleInvalidEnumValue, "%s takes no values, got %#v", ev.Name, value)
+	}
+	return failf(ctx, RuleInvalidEnumValue, "expected one of %s, got %#v", strings.Join(allowed, ", "), value)
+}
+
+// DispatchValidator validates a value against a dispatcher case like
+// minecraft:block_predicate[[type]] (dynamic) or minecraft:resource[biome] (static)
+type DispatchValidator struct {
+	BaseValidator
+	Registry string
+	Key      string // static key, or the accessor for dynamic dispatches
+	Dynamic  bool
+}
+
+func (dv DispatchValidator) Validate(value interface{}, ctx *ValidationContext) []Finding {
+	if !dv.AppliesForVersion(ctx) {
+		return nil
+	}
+
+	holder := (*ValidationContext)(nil)
+	if len(ctx.Parents) == 0 {
+		// The value holds its own key, like a resource dispatched on its type
+		holder = ctx
+	}
+	validator := dv.Resolve(value, ctx)
+	if validator == nil {
+		// Cases for this dispatcher are not loaded, accept the value as is
+		if _, loaded := ctx.Dispatches[dv.Registry]; !loaded && !hasBuiltinCases(dv.Registry) && ctx.StrictSchema {
+			return failf(ctx, RulePartiallyValidated, "%s cannot be fully validated: dispatcher %s is not loaded", valueSubject(ctx), dv.Registry)
+		}
+		return dv.unknownKeyWarning(value, ctx, holder)
+	}
+	findings := validator.Validate(value, ctx)
+	findings = append(findings, dv.unknownKeyWarning(value, ctx, holder)...)
+	return append(findings, dv.aliasWarning(value, ctx, holder)...)
+}
+
+// aliasWarning warns about a dynamic dispatch on an alias key, like
+// crafting_shaped for shaped. The warning is about the field holding the key
+// given the context of the object holding it, and about value otherwise.
+func (dv DispatchValidator) aliasWarning(value interface{}, ctx, holder *ValidationContext) []Finding {
+	if !dv.Dynamic || len(ctx.Aliases[dv.Registry]) == 0 {
+		return nil
+	}
+	key, ok := dv.dynamicKey(value, ctx)
+	if !ok {
+		return nil
+	}
+	canonical, ok := ctx.Aliases[dv.Registry][key]
+	if !ok {
+		return nil
+	}
+	if holder != nil && !strings.ContainsAny(dv.Key, ".%") {
+		return warnf(holder.Child(dv.Key), RuleAliasKey, "%q is an alias of %q, use the canonical key", key, canonical)
+	}
+	return warnf(ctx, RuleAliasKey, "%s %q is an alias of %q, use the canonical key", dv.Key, key, canonical)
+}
+
+// unknownKeyWarning warns about a dynamic dispatch on a key the dispatcher
+// has no case for, like a recipe of type crafting_shapd, suggesting the keys
+// it is closest to. Keys in namespaces other than minecraft are expected to
+// come from mods the schemas do not describe, unless the schemas of mods
+// are loaded. Like aliasWarning, the warning is about the field holding the
+// key if the object holding it is given.
+func (dv DispatchValidator) unknownKeyWarning(value interface{}, ctx, holder *ValidationContext) []Finding {
+	cases, loaded := ctx.Dispatches[dv.Registry]
+	if !dv.Dynamic || !loaded || hasBuiltinCases(dv.Registry) {
+		return nil
+	}
+	key, ok := dv.dynamicKey(value, ctx)
+	if !ok {
+		return nil
+	}
+	if _, known := cases[key]; known {
+		return nil
+	}
+	if _, alias := ctx.Aliases[dv.Registry][key]; alias {
+		return nil
+	}
+	if holder != nil && !strings.ContainsAny(dv.Key, ".%") {
+		ctx = holder.Child(dv.Key)
+	}
+
+	registry := strings.TrimPrefix(dv.Registry, "minecraft:")
+	namespace, _, modded := strings.Cut(key, ":")
+	modded = modded && namespace != "minecraft"
+	if modded && !ctx.ExtraSchemas {
+		return warnf(ctx, RuleUnknownType, "%q is not a vanilla %s; it may come from a mod whose schemas are not loaded", key, registry)
+	}
+	keys := make([]string, 0, len(cases))
+	for name := range cases {
+		if !strings.HasPrefix(name, "%") {
+			keys = append(keys, name)
+		}
+	}
+	suggestions := closestNames(key, keys, 3)
+	switch {
+	case modded && len(suggestions) > 0:
+		return warnf(ctx, RuleUnknownType, "%q is not a %s the extra schemas declare, did you mean %s?", key, registry, quotedList(suggestions, "or"))
+	case modded:
+		return warnf(ctx, RuleUnknownType, "%q is not a %s the extra schemas declare", key, registry)
+	case len(suggestions) > 0:
+		return warnf(ctx, RuleUnknownType, "%q is not a known %s, did you mean %s?", key, registry, quotedList(suggestions, "or"))
+	}
+	return warnf(ctx, RuleUnknownType, "%q is not a known %s", key, registry)
+}
+
+// closestNames returns up to limit names within a few edits of name,
+// closest first
+func closestNames(name string, names []string, limit int) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+	maxDistance := max(2, len(name)/3)
+	var candidates []candidate
+	for _, other := range names {
+		if distance := editDistance(name, other); distance <= maxDistance {
+			candidates = append(candidates, candidate{other, distance})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.name, b.name))
+	})
+	var closest []string
+	for i := 0; i < len(candidates) && i < limit; i++ {
+		closest = append(closest, candidates[i].name)
+	}
+	return closest
+}
+
+// editDistance counts the insertions, deletions and substitutions turning a
+// into b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// quotedList writes names like "a", "b" or "c"
+func quotedList(names []string, conjunction string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = strconv.Quote(name)
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " " + conjunction + " " + quoted[len(quoted)-1]
+}
+
+// Resolve finds the dispatcher case for value, or nil if it is not known
+func (dv DispatchValidator) Resolve(value interface{}, ctx *ValidationContext) Validator {
+	cases, ok := ctx.Dispatches[dv.Registry]
+	if !ok && !hasBuiltinCases(dv.Registry) {
+		return nil
+	}
+
+	key := dv.Key
+	if dv.Dynamic {
+		var found bool
+		key, found = dv.dynamicKey(value, ctx)
+		if !found {
+			key = "%none"
+		}
+	}
+
+	if validator, ok := cases[key]; ok {
+		return validator
+	}
+	// Cases the game data provides, like the properties of a block
+	if validator := builtinCase(dv.Registry, key); validator != nil {
+		return validator
+	}
+	return cases["%unknown"]
+}
+
+// dynamicKey evaluates the accessor of a dynamic dispatch like [[type]] or [[%parent.type]].
+// The accessor starts at the innermost enclosing object or list: in a field
+// like properties: [mcdoc:block_state_keys[[%parent.block]]] it starts at the
+// list, so %parent is the object holding the list.
+func (dv DispatchValidator) dynamicKey(value interface{}, ctx *ValidationContext) (string, bool) {
+	level := len(ctx.Parents) - 1
+	var current interface{} = value
+	if level >= 0 {
+		current = ctx.Parents[level]
+	}
+
+	for _, segment := range strings.Split(dv.Key, ".") {
+		switch segment {
+		case "%key":
+			if len(ctx.Path) == 0 {
+				return "", false
+			}
+			current = ctx.Path[len(ctx.Path)-1]
+		case "%parent":
+			level--
+			if level < 0 {
+				return "", false
+			}
+			current = ctx.Parents[level]
+		default:
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return "", false
+			}
+			current, ok = obj[segment]
+			if !ok {
+				return "", false
+			}
+		}
+	}
+
+	key, ok := current.(string)
+	if !ok {
+		return "", false
+	}
+	return strings.TrimPrefix(key, "minecraft:"), true
 }
\ No newline at end of file
@@ -1,6 +1,10 @@
-package main
+package mcheck
 
 import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -17,10 +21,16 @@ func TestVersionParsing(t *testing.T) {
 		{"1", Version{}, true},
 		{"1.2.3.4", Version{}, true},
 		{"1.x.2", Version{}, true},
+		{"1.100", Version{1, 100, 0}, false},
+		{"26.1.2", Version{26, 1, 2}, false},
+		{"1.-1", Version{}, true},
+		{"+1.20", Version{}, true},
+		{"1..2", Version{}, true},
+		{"1.99999999999999999999", Version{}, true},
 	}
 
 	for _, test := range tests {
-		result, err := parseVersion(test.input)
+		result, err := ParseVersion(test.input)
 		if test.hasError {
 			if err == nil {
 				t.Errorf("Expected error for input %s, but got none", test.input)
@@ -37,10 +47,10 @@ func TestVersionParsing(t *testing.T) {
 }
 
 func TestVersionComparison(t *testing.T) {
-	v1, _ := parseVersion("1.20.1")
-	v2, _ := parseVersion("1.20.2")
-	v3, _ := parseVersion("1.19.4")
-	v4, _ := parseVersion("2.0.0")
+	v1, _ := ParseVersion("1.20.1")
+	v2, _ := ParseVersion("1.20.2")
+	v3, _ := ParseVersion("1.19.4")
+	v4, _ := ParseVersion("2.0.0")
 
 	if v1.Compare(v2) >= 0 {
 		t.Error("1.20.1 should be less than 1.20.2")
@@ -57,10 +67,91 @@ func TestVersionComparison(t *testing.T) {
 	if v1.Compare(v1) != 0 {
 		t.Error("1.20.1 should be equal to itself")
 	}
+
+	// Minor and patch numbers compare as numbers, not strings
+	ordered := []string{"1.9", "1.9.10", "1.21.6", "1.99", "1.100", "2.0", "26.1"}
+	for i := 1; i < len(ordered); i++ {
+		before, _ := ParseVersion(ordered[i-1])
+		after, _ := ParseVersion(ordered[i])
+		if before.Compare(after) >= 0 || after.Compare(before) <= 0 {
+			t.Errorf("%s should be less than %s", before, after)
+		}
+	}
+	huge := Version{math.MaxInt, 0, 0}
+	if huge.Compare(Version{math.MinInt, 0, 0}) <= 0 {
+		t.Error("Compare overflowed comparing extreme versions")
+	}
+}
+
+func TestParseVersions(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected string
+		error    string
+	}{
+		{"1.20.1", "1.20.1", ""},
+		{"1.21", "1.21.0 1.21.1 1.21.2 1.21.3 1.21.4 1.21.5 1.21.6", ""},
+		{"1.20.5..1.21.1", "1.20.5 1.20.6 1.21.0 1.21.1", ""},
+		{"1.20.5..1.21", "1.20.5 1.20.6 1.21.0 1.21.1 1.21.2 1.21.3 1.21.4 1.21.5 1.21.6", ""},
+		{"1.21.5..", "1.21.5 1.21.6", ""},
+		{"..1.16.2", "1.16.0 1.16.1 1.16.2", ""},
+		{"1.21.6, 1.16.5,1.21.6", "1.16.5 1.21.6", ""},
+		{"1.100", "1.100.0", ""},
+		{"26.1", "26.1.0", ""},
+		{"1.21..1.20", "", "ends before it starts"},
+		{"1.100..1.101", "", "no known versions"},
+		{"1.x", "", "invalid minor version"},
+	}
+
+	for _, tt := range tests {
+		versions, err := ParseVersions(tt.spec)
+		if tt.error != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("Expected error containing %q for %s, got %v", tt.error, tt.spec, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %v", tt.spec, err)
+			continue
+		}
+		names := make([]string, len(versions))
+		for i, version := range versions {
+			names[i] = version.String()
+		}
+		if got := strings.Join(names, " "); got != tt.expected {
+			t.Errorf("Expected %s to name %s, got %s", tt.spec, tt.expected, got)
+		}
+	}
+}
+
+func TestVersionBounds(t *testing.T) {
+	bounds := BaseValidator{Since: "1.19", Until: "1.20.2"}
+	tests := []struct {
+		version   Version
+		inclusive bool
+		expected  bool
+	}{
+		{Version{1, 18, 2}, false, false},
+		{Version{1, 19, 0}, false, true},
+		{Version{1, 20, 1}, false, true},
+		{Version{1, 20, 2}, false, false},
+		{Version{1, 20, 3}, false, false},
+		{Version{1, 19, 0}, true, true},
+		{Version{1, 20, 2}, true, true},
+		{Version{1, 20, 3}, true, false},
+	}
+
+	for _, tt := range tests {
+		ctx := &ValidationContext{Version: tt.version, InclusiveUntil: tt.inclusive}
+		if got := bounds.AppliesForVersion(ctx); got != tt.expected {
+			t.Errorf("Expected %v for %s (inclusive until %v), got %v", tt.expected, tt.version, tt.inclusive, got)
+		}
+	}
 }
 
 func TestVersionString(t *testing.T) {
-	v, _ := parseVersion("1.20.1")
+	v, _ := ParseVersion("1.20.1")
 	expected := "1.20.1"
 	if v.String() != expected {
 		t.Errorf("Expected version string %s, got %s", expected, v.String())
@@ -77,12 +168,12 @@ func TestPrimitiveValidator(t *testing.T) {
 	stringValidator := &PrimitiveValidator{Type: "string"}
 	
 	// Valid string
-	if err := stringValidator.Validate("hello", ctx); err != nil {
+	if err := findingsError(stringValidator.Validate("hello", ctx)); err != nil {
 		t.Errorf("Expected string validation to pass, got: %v", err)
 	}
 	
 	// Invalid string (number)
-	if err := stringValidator.Validate(42, ctx); err == nil {
+	if err := findingsError(stringValidator.Validate(42, ctx)); err == nil {
 		t.Error("Expected string validation to fail for number, but it passed")
 	}
 
@@ -90,12 +181,12 @@ func TestPrimitiveValidator(t *testing.T) {
 	intValidator := &PrimitiveValidator{Type: "int"}
 	
 	// Valid int (JSON unmarshals numbers as float64)
-	if err := intValidator.Validate(float64(42), ctx); err != nil {
+	if err := findingsError(intValidator.Validate(float64(42), ctx)); err != nil {
 		t.Errorf("Expected int validation to pass for float64, got: %v", err)
 	}
 	
 	// Invalid int (string)
-	if err := intValidator.Validate("42", ctx); err == nil {
+	if err := findingsError(intValidator.Validate("42", ctx)); err == nil {
 		t.Error("Expected int validation to fail for string, but it passed")
 	}
 
@@ -103,12 +194,12 @@ func TestPrimitiveValidator(t *testing.T) {
 	boolValidator := &PrimitiveValidator{Type: "boolean"}
 	
 	// Valid boolean
-	if err := boolValidator.Validate(true, ctx); err != nil {
+	if err := findingsError(boolValidator.Validate(true, ctx)); err != nil {
 		t.Errorf("Expected boolean validation to pass, got: %v", err)
 	}
 	
 	// Invalid boolean (string)
-	if err := boolValidator.Validate("true", ctx); err == nil {
+	if err := findingsError(boolValidator.Validate("true", ctx)); err == nil {
 		t.Error("Expected boolean validation to fail for string, but it passed")
 	}
 }
@@ -140,7 +231,7 @@ func TestStructValidator(t *testing.T) {
 		"required_field": "hello",
 		"optional_field": float64(42),
 	}
-	if err := structValidator.Validate(validData, ctx); err != nil {
+	if err := findingsError(structValidator.Validate(validData, ctx)); err != nil {
 		t.Errorf("Expected validation to pass for valid struct, got: %v", err)
 	}
 
@@ -148,7 +239,7 @@ func TestStructValidator(t *testing.T) {
 	validDataMinimal := map[string]interface{}{
 		"required_field": "hello",
 	}
-	if err := structValidator.Validate(validDataMinimal, ctx); err != nil {
+	if err := findingsError(structValidator.Validate(validDataMinimal, ctx)); err != nil {
 		t.Errorf("Expected validation to pass for struct with only required field, got: %v", err)
 	}
 
@@ -156,7 +247,7 @@ func TestStructValidator(t *testing.T) {
 	invalidDataMissing := map[string]interface{}{
 		"optional_field": float64(42),
 	}
-	if err := structValidator.Validate(invalidDataMissing, ctx); err == nil {
+	if err := findingsError(structValidator.Validate(invalidDataMissing, ctx)); err == nil {
 		t.Error("Expected validation to fail for struct missing required field, but it passed")
 	}
 
@@ -165,7 +256,291 @@ func TestStructValidator(t *testing.T) {
 		"required_field":   "hello",
 		"unexpected_field": "bad",
 	}
-	if err := structValidator.Validate(invalidDataExtra, ctx); err == nil {
+	if err := findingsError(structValidator.Validate(invalidDataExtra, ctx)); err == nil {
 		t.Error("Expected validation to fail for struct with unexpected field, but it passed")
 	}
-}
\ No newline at end of file
+}
+
+func TestValidationContextPaths(t *testing.T) {
+	ctx := &ValidationContext{
+		Version: Version{1, 20, 1},
+		Path:    []string{},
+	}
+
+	// Sibling children must not share storage, even when the parent path has
+	// spare capacity
+	parent := ctx.Child("a")
+	parent.Path = append(make([]string, 0, 8), parent.Path...)
+	first, second := parent.Child("b"), parent.Child("c")
+	if strings.Join(first.Path, ".") != "a.b" || strings.Join(second.Path, ".") != "a.c" {
+		t.Errorf("Children share paths: %v and %v", first.Path, second.Path)
+	}
+	if len(ctx.Path) != 0 || len(parent.Path) != 1 {
+		t.Errorf("Parent paths were modified: %v and %v", ctx.Path, parent.Path)
+	}
+
+	// Errors keep their paths once validation moves on, and a context can be
+	// shared between goroutines
+	validator := &StructValidator{Fields: []StructField{
+		{Name: "items", Validator: &ArrayValidator{ElementValidator: &PrimitiveValidator{Type: "int"}}},
+	}}
+	var wg sync.WaitGroup
+	results := make([][]Finding, 16)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			items := []interface{}{1.0, 2.0, 3.0}
+			items[i%3] = "bad"
+			results[i] = validator.Validate(map[string]interface{}{"items": items}, ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, findings := range results {
+		if len(findings) != 1 {
+			t.Fatalf("Expected one finding, got %v", findings)
+		}
+		expected := fmt.Sprintf("items.[%d]", i%3)
+		if got := strings.Join(findings[0].Path, "."); got != expected {
+			t.Errorf("Expected path %s, got %s", expected, got)
+		}
+	}
+}
+
+func TestNullValues(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", `dispatch minecraft:resource[thing] to struct Thing {
+	size: int @ 1..4,
+	name?: string,
+	data?: any,
+	tags?: [string],
+	extra?: struct {
+		[string]: int,
+	},
+}
+`)
+	validator := converter.MainValidatorFor("thing")
+
+	tests := []struct {
+		name     string
+		document string
+		error    string
+	}{
+		{"required field", `{"size": null}`, "at size: null is not allowed here, expected int @ 1..4 [MCHECK011 null-value]"},
+		{"optional field", `{"size": 1, "name": null}`, "at name: null is not allowed here, omit the field instead [MCHECK011 null-value]"},
+		{"computed field", `{"size": 1, "extra": {"a": null}}`, "at extra.a: null is not allowed here, omit the field instead"},
+		{"list element", `{"size": 1, "tags": ["a", null]}`, "at tags.[1]: null is not allowed here, expected string"},
+		{"any accepts null", `{"size": 1, "data": null}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := decodeJSON([]byte(tt.document))
+			if err != nil {
+				t.Fatalf("Failed to decode document: %v", err)
+			}
+			findings := validator.Validate(document, ctx)
+			failed, ok := firstError(findings)
+			switch {
+			case tt.error == "" && ok:
+				t.Errorf("Expected valid, got %v", findings)
+			case tt.error != "" && (!ok || !strings.Contains(failed.String(), tt.error)):
+				t.Errorf("Expected error containing %q, got %v", tt.error, findings)
+			}
+		})
+	}
+}
+
+func TestBooleanNumbers(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", `dispatch minecraft:resource[thing] to struct Thing {
+	enabled?: boolean,
+	count?: int,
+	weight?: float @ 0..1,
+	either?: (boolean | int),
+}
+`)
+	validator := converter.MainValidatorFor("thing")
+
+	tests := []struct {
+		name     string
+		document string
+		error    string
+		warning  string
+	}{
+		{"number for boolean", `{"enabled": 1}`, "at enabled: expected boolean, got number 1; write true instead [MCHECK012 boolean-number]", "at enabled: number 1 is read as true, write true instead [MCHECK012 boolean-number]"},
+		{"boolean for int", `{"count": false}`, "at count: expected int, got boolean false; write 0 instead", "at count: false is read as 0, write 0 instead"},
+		{"boolean for range", `{"weight": true}`, "at weight: expected float, got boolean true; write 1 instead", "at weight: true is read as 1, write 1 instead"},
+		{"other numbers stay type errors", `{"enabled": 2}`, "expected boolean, got float64 [MCHECK003 wrong-type]", "expected boolean, got float64"},
+		{"union prefers exact match", `{"either": 1}`, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := decodeJSON([]byte(tt.document))
+			if err != nil {
+				t.Fatalf("Failed to decode document: %v", err)
+			}
+
+			findings := validator.Validate(document, ctx)
+			failed, ok := firstError(findings)
+			switch {
+			case tt.error == "" && len(findings) > 0:
+				t.Errorf("Expected valid, got %v", findings)
+			case tt.error != "" && (!ok || !strings.Contains(failed.String(), tt.error)):
+				t.Errorf("Expected error containing %q, got %v", tt.error, findings)
+			}
+
+			// Lenient mode turns the mixups into warnings
+			lenient := *ctx
+			lenient.Lenient = true
+			findings = validator.Validate(document, &lenient)
+			if tt.warning == "" {
+				if len(findings) > 0 {
+					t.Errorf("Expected no problems in lenient mode, got %v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || !strings.Contains(findings[0].String(), tt.warning) {
+				t.Errorf("Expected one problem containing %q in lenient mode, got %v", tt.warning, findings)
+			}
+		})
+	}
+}
+
+func TestFindings(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", `dispatch minecraft:resource[thing] to struct Thing {
+	name: string,
+	size: int @ 1..4,
+	enabled?: boolean,
+	parts?: [Part],
+}
+
+struct Part {
+	weight: int,
+}
+`)
+	validator := converter.MainValidatorFor("thing")
+
+	// Every problem is reported, not just the first
+	document, err := decodeJSON([]byte(`{"size": 9, "parts": [{"weight": "a"}, {}], "color": "red"}`))
+	if err != nil {
+		t.Fatalf("Failed to decode document: %v", err)
+	}
+	findings := validator.Validate(document, ctx)
+	expected := []string{
+		"required field 'name' is missing",
+		"at size: value 9 must be less than or equal to 4",
+		"at parts.[0].weight: expected int, got string",
+		"at parts.[1]: required field 'weight' is missing",
+		"unexpected field 'color'",
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %v", len(expected), findings)
+	}
+	for i, finding := range findings {
+		if finding.Severity != SeverityError || !strings.HasPrefix(finding.text(), expected[i]) {
+			t.Errorf("Expected error %q, got %v", expected[i], finding)
+		}
+	}
+	if findings[0].Schema != "Thing" || findings[0].Rule != RuleMissingField || findings[3].Schema != "Part" {
+		t.Errorf("Expected missing fields to name their struct, got %+v and %+v", findings[0], findings[3])
+	}
+
+	// Warnings are findings that leave the value valid
+	lenient := *ctx
+	lenient.Lenient = true
+	document, _ = decodeJSON([]byte(`{"name": "a", "size": 2, "enabled": 1}`))
+	findings = validator.Validate(document, &lenient)
+	if len(findings) != 1 || findings[0].Severity != SeverityWarning || hasErrors(findings) {
+		t.Errorf("Expected one warning, got %v", findings)
+	}
+	if err := findingsError(findings); err != nil {
+		t.Errorf("Expected warnings not to fail validation, got %v", err)
+	}
+}
+
+func TestNumericWidths(t *testing.T) {
+	converter, ctx := convertSchema(t, "1.20.1", `type integerish = int
+
+dispatch minecraft:resource[thing] to struct Thing {
+	b?: byte,
+	s?: short,
+	i?: int,
+	l?: long,
+	f?: float,
+	d?: double,
+	bits?: byte @ 0..1 [] @ 4,
+	seeds?: long[],
+	raw?: unsafe,
+	named?: integerish,
+}
+`)
+	validator := converter.MainValidatorFor("thing")
+
+	tests := []struct {
+		document string
+		expected string
+	}{
+		{`{"b": -128, "s": 32767, "i": -2147483648, "l": 9007199254740991, "f": 1e38, "d": 1e300}`, ""},
+		{`{"b": 128}`, "at b: value 128 is out of range for byte (-128..127)"},
+		{`{"s": -32769}`, "at s: value -32769 is out of range for short (-32768..32767)"},
+		{`{"i": 2147483648}`, "at i: value 2.147483648e+09 is out of range for int (-2147483648..2147483647)"},
+		{`{"l": 1.5}`, "at l: expected integer, got float"},
+		{`{"f": 1e39}`, "at f: value 1e+39 is out of range for float"},
+		{`{"bits": [0, 1, 1, 0]}`, ""},
+		{`{"bits": [0, 2, 1, 0]}`, "at bits.[1]: value 2 must be less than or equal to 1 (range 0..1)"},
+		{`{"bits": [0, 1]}`, "at bits: byte array must have 4 elements, got 2"},
+		{`{"seeds": [1, 2]}`, ""},
+		{`{"seeds": ["1"]}`, "at seeds.[0]: expected long, got string"},
+		{`{"raw": {"anything": [1, "x"]}}`, ""},
+		{`{"named": 1.5}`, "at named: expected integer, got float"},
+	}
+
+	for _, tt := range tests {
+		document, err := decodeJSON([]byte(tt.document))
+		if err != nil {
+			t.Fatalf("Invalid document %s: %v", tt.document, err)
+		}
+		var got []string
+		for _, finding := range validator.Validate(document, ctx) {
+			got = append(got, finding.text())
+		}
+		if strings.Join(got, "\n") != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.document, tt.expected, got)
+		}
+	}
+}
+
+func TestValidationCancelled(t *testing.T) {
+	validator := &StructValidator{Fields: []StructField{
+		{Name: "items", Validator: &ArrayValidator{ElementValidator: &PrimitiveValidator{Type: "int"}}},
+	}}
+	document := map[string]interface{}{"items": []interface{}{"bad", "bad"}}
+	if findings := validator.Validate(document, &ValidationContext{}); len(findings) != 2 {
+		t.Fatalf("Expected both items to be reported, got %v", findings)
+	}
+
+	done := make(chan struct{})
+	close(done)
+	if findings := validator.Validate(document, &ValidationContext{Done: done}); len(findings) != 0 {
+		t.Errorf("Expected a cancelled validation to stop, got %v", findings)
+	}
+	if findings := (&ArrayValidator{ElementValidator: &PrimitiveValidator{Type: "int"}}).Validate(document["items"], &ValidationContext{Done: done}); len(findings) != 0 {
+		t.Errorf("Expected a cancelled validation to skip the elements, got %v", findings)
+	}
+}
+
+func TestStringLength(t *testing.T) {
+	three := 3.0
+	validator := &ConstrainedValidator{
+		InnerValidator: &PrimitiveValidator{Type: "string"},
+		Constraint:     &RangeValidator{Max: &three},
+	}
+	// The game counts UTF-16 code units, so é is one and 😀 two
+	if findings := validator.Validate("ééé", &ValidationContext{}); len(findings) != 0 {
+		t.Errorf("Expected three accented letters to fit a length of 3, got %v", findings)
+	}
+	if findings := validator.Validate("😀😀", &ValidationContext{}); len(findings) != 1 || findings[0].Rule != RuleInvalidLength {
+		t.Errorf("Expected two emoji to exceed a length of 3, got %v", findings)
+	}
+}
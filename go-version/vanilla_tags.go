@@ -0,0 +1,94 @@
+package mcheck
+
+// vanillaItemTags are the item tags of the vanilla datapack, by the version
+// that added them. Recipes may use them without the pack defining them.
+// Tags vanilla removed, like carpets before it became wool_carpets, are
+// left out rather than bounded, since few packs target those versions.
+var vanillaItemTags = vanillaTags(map[string][]string{
+	"1.13": {
+		"acacia_logs", "anvil", "banners", "birch_logs", "boats", "buttons",
+		"dark_oak_logs", "doors", "fishes", "jungle_logs", "leaves", "logs",
+		"oak_logs", "planks", "rails", "sand", "saplings", "slabs",
+		"spruce_logs", "stairs", "stone_bricks", "trapdoors", "wooden_buttons",
+		"wooden_doors", "wooden_pressure_plates", "wooden_slabs",
+		"wooden_stairs", "wooden_trapdoors", "wool",
+	},
+	"1.14": {
+		"arrows", "beds", "fences", "lectern_books", "music_discs", "signs",
+		"small_flowers", "walls", "wooden_fences",
+	},
+	"1.15": {"flowers", "tall_flowers"},
+	"1.16": {
+		"beacon_payment_items", "coals", "creeper_drop_music_discs",
+		"crimson_stems", "gold_ores", "ignored_by_piglin_babies",
+		"logs_that_burn", "non_flammable_wood", "piglin_loved",
+		"piglin_repellents", "soul_fire_base_blocks", "stone_crafting_materials",
+		"stone_tool_materials", "warped_stems",
+	},
+	"1.16.2": {"piglin_food"},
+	"1.17": {
+		"axolotl_tempt_items", "candles", "cluster_max_harvestables",
+		"coal_ores", "copper_ores", "diamond_ores", "emerald_ores",
+		"fox_food", "freeze_immune_wearables", "iron_ores", "lapis_ores",
+		"occludes_vibration_signals", "redstone_ores", "terracotta",
+	},
+	"1.18": {"dirt"},
+	"1.19": {
+		"chest_boats", "compasses", "completes_find_tree_tutorial",
+		"dampens_vibrations", "mangrove_logs", "overworld_natural_logs",
+		"wool_carpets",
+	},
+	"1.19.3": {"bamboo_blocks"},
+	"1.19.4": {
+		"axes", "cherry_logs", "hanging_signs", "hoes", "pickaxes", "shovels",
+		"smelts_to_glass", "sniffer_food", "stone_buttons", "swords", "tools",
+		"trim_materials", "trim_templates", "trimmable_armor",
+	},
+	"1.20": {
+		"breaks_decorated_pots", "decorated_pot_ingredients",
+		"decorated_pot_sherds", "noteblock_top_instruments",
+		"villager_plantable_seeds",
+	},
+	"1.20.3": {"wall_hanging_signs"},
+	"1.20.5": {
+		"armadillo_food", "bee_food", "camel_food", "cat_food", "chest_armor",
+		"chicken_food", "cow_food", "dyeable", "enchantable/armor",
+		"enchantable/bow", "enchantable/chest_armor", "enchantable/crossbow",
+		"enchantable/durability", "enchantable/equippable",
+		"enchantable/fire_aspect", "enchantable/fishing",
+		"enchantable/foot_armor", "enchantable/head_armor",
+		"enchantable/leg_armor", "enchantable/mining",
+		"enchantable/mining_loot", "enchantable/sharp_weapon",
+		"enchantable/sword", "enchantable/trident", "enchantable/vanishing",
+		"enchantable/weapon", "foot_armor", "frog_food", "goat_food",
+		"head_armor", "horse_food", "horse_tempt_items", "leg_armor",
+		"llama_food", "llama_tempt_items", "meat", "ocelot_food", "panda_food",
+		"parrot_food", "parrot_poisonous_food", "pig_food", "rabbit_food",
+		"sheep_food", "skulls", "strider_food",
+		"strider_tempt_items", "turtle_food", "wolf_food",
+	},
+	"1.21": {"enchantable/mace"},
+	"1.21.2": {
+		"bundles", "diamond_tool_materials", "gold_tool_materials",
+		"iron_tool_materials", "netherite_tool_materials",
+		"pale_oak_logs", "repairs_chain_armor", "repairs_diamond_armor",
+		"repairs_gold_armor", "repairs_iron_armor", "repairs_leather_armor",
+		"repairs_netherite_armor", "repairs_turtle_helmet",
+		"repairs_wolf_armor", "wooden_tool_materials",
+	},
+})
+
+// vanillaTags indexes tag names grouped by the version that added them
+func vanillaTags(byVersion map[string][]string) map[string]Version {
+	tags := make(map[string]Version)
+	for since, names := range byVersion {
+		version, err := ParseVersion(since)
+		if err != nil {
+			panic(err)
+		}
+		for _, name := range names {
+			tags["minecraft:"+name] = version
+		}
+	}
+	return tags
+}
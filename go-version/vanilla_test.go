@@ -0,0 +1,47 @@
+package mcheck
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestVanillaFixtures validates the vanilla datapack extracted by
+// `make vanilla-fixtures` into tests/good/vanilla/<version>. Every file the
+// schemas describe must pass; it is skipped when the corpus or the
+// vanilla-mcdoc checkout is missing.
+func TestVanillaFixtures(t *testing.T) {
+	if _, err := os.Stat("vanilla-mcdoc"); err != nil {
+		t.Skip("vanilla-mcdoc not found")
+	}
+	versions, err := os.ReadDir(filepath.Join("tests", "good", "vanilla"))
+	if err != nil {
+		t.Skip("no vanilla fixtures, run make vanilla-fixtures")
+	}
+
+	for _, entry := range versions {
+		version, err := ParseVersion(entry.Name())
+		if !entry.IsDir() || err != nil {
+			continue
+		}
+		validator := NewPEGMCDocValidator(version, "vanilla-mcdoc")
+		root := filepath.Join("tests", "good", "vanilla", entry.Name())
+
+		t.Run(entry.Name(), func(t *testing.T) {
+			filepath.WalkDir(root, func(path string, file os.DirEntry, err error) error {
+				if err != nil || file.IsDir() || filepath.Ext(path) != ".json" {
+					return err
+				}
+				for _, finding := range validator.CheckFile(path, path) {
+					// Only files the schemas describe are expected to pass
+					if finding.Rule == RuleSchemaNotFound || finding.Severity != SeverityError {
+						continue
+					}
+					t.Error(strings.TrimSpace(finding.String()))
+				}
+				return nil
+			})
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package mcheck
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// BuildVersion is the release of the binary, set with
+// -ldflags "-X mcheck.BuildVersion=v1.2.3"
+var BuildVersion = "dev"
+
+// VersionInfo describes a binary and the schemas it validates with, so that
+// a bug report says what was run
+type VersionInfo struct {
+	Version   string       `json:"version"`
+	Commit    string       `json:"commit,omitempty"` // source revision the binary was built from
+	Date      string       `json:"date,omitempty"`   // time of that revision
+	Modified  bool         `json:"modified,omitempty"`
+	Go        string       `json:"go"`
+	Platform  string       `json:"platform"`
+	Schemas   SchemaBundle `json:"schemas"`
+	Minecraft VersionRange `json:"minecraft"`
+}
+
+// SchemaBundle identifies a vanilla-mcdoc checkout or download
+type SchemaBundle struct {
+	Dir    string            `json:"dir,omitempty"`
+	Commit string            `json:"commit,omitempty"`
+	Date   string            `json:"date,omitempty"`
+	Newest map[string]string `json:"newest,omitempty"` // version the schemas of each edition were written for
+	Error  string            `json:"error,omitempty"`  // why the schemas could not be found
+}
+
+// VersionRange is the range of Minecraft releases mcheck knows and the one
+// targeted
+type VersionRange struct {
+	Oldest string `json:"oldest"`
+	Newest string `json:"newest"`
+	Target string `json:"target"`
+}
+
+// BuildVersionInfo collects the version of the binary from the linker flags
+// and the build info the go command embeds, and the revision of the schemas
+// in schemaDir
+func BuildVersionInfo(schemaDir, target string) VersionInfo {
+	info := VersionInfo{
+		Version:  BuildVersion,
+		Go:       runtime.Version(),
+		Platform: runtime.GOOS + "/" + runtime.GOARCH,
+		Minecraft: VersionRange{
+			Oldest: KnownVersions[0],
+			Newest: KnownVersions[len(KnownVersions)-1],
+			Target: target,
+		},
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		// go install mcheck@v1.2.3 records the module version
+		if info.Version == "dev" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+			info.Version = build.Main.Version
+		}
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Commit = setting.Value
+			case "vcs.time":
+				info.Date = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	dir, schemas, err := findSchemaDir(schemaDir)
+	if err != nil {
+		info.Schemas.Error = err.Error()
+	} else {
+		info.Schemas = readSchemaBundle(schemas, dir)
+	}
+	return info
+}
+
+// tarballDirPattern matches the directory a GitHub tarball unpacks to, like
+// SpyglassMC-vanilla-mcdoc-1a2b3c4, which ends in the commit it was made from
+var tarballDirPattern = regexp.MustCompile(`-([0-9a-f]{7,40})$`)
+
+// readSchemaBundle finds the revision of a schema directory, from git if it
+// is a clone and otherwise from the name of an unpacked tarball, and the
+// version its schemas were written for
+func readSchemaBundle(schemas fileSystem, dir string) SchemaBundle {
+	bundle := SchemaBundle{Dir: dir}
+	if index, err := buildSchemaIndex(schemas, dir); err == nil && len(index.Newest)+len(index.Bundle) > 0 {
+		bundle.Newest = make(map[string]string)
+		for _, edition := range Editions {
+			if version, ok := index.Version(edition); ok {
+				bundle.Newest[edition] = version.String()
+			}
+		}
+	}
+
+	// Only a clone of its own, a schema directory within another repository
+	// would report that repository's revision
+	if _, err := schemas.Stat(filepath.Join(dir, ".git")); err == nil {
+		cmd := exec.Command("git", "log", "-1", "--format=%H %cI")
+		cmd.Dir = dir
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			bundle.Error = fmt.Sprintf("git log failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+			return bundle
+		}
+		if fields := strings.Fields(string(output)); len(fields) == 2 {
+			bundle.Commit, bundle.Date = fields[0], fields[1]
+		}
+		return bundle
+	}
+
+	if match := tarballDirPattern.FindStringSubmatch(filepath.Base(filepath.Clean(dir))); match != nil {
+		bundle.Commit = match[1]
+	}
+	return bundle
+}
+
+func (info VersionInfo) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "mcheck %s (%s, %s)\n", info.Version, info.Go, info.Platform)
+	if info.Commit != "" {
+		fmt.Fprintf(&b, "commit:    %s", info.Commit)
+		if info.Date != "" {
+			fmt.Fprintf(&b, " %s", info.Date)
+		}
+		if info.Modified {
+			b.WriteString(" (modified)")
+		}
+		b.WriteString("\n")
+	}
+
+	switch schemas := info.Schemas; {
+	case schemas.Dir == "":
+		fmt.Fprintf(&b, "schemas:   %s\n", schemas.Error)
+	case schemas.Commit == "":
+		fmt.Fprintf(&b, "schemas:   %s at an unknown revision\n", schemas.Dir)
+	default:
+		fmt.Fprintf(&b, "schemas:   %s at %s", schemas.Dir, schemas.Commit)
+		if schemas.Date != "" {
+			fmt.Fprintf(&b, " %s", schemas.Date)
+		}
+		b.WriteString("\n")
+	}
+	if len(info.Schemas.Newest) > 0 {
+		var editions []string
+		for _, edition := range Editions {
+			if newest, ok := info.Schemas.Newest[edition]; ok {
+				editions = append(editions, edition+" "+newest)
+			}
+		}
+		fmt.Fprintf(&b, "           written for %s\n", strings.Join(editions, ", "))
+	}
+
+	fmt.Fprintf(&b, "minecraft: %s to %s, targeting %s\n", info.Minecraft.Oldest, info.Minecraft.Newest, info.Minecraft.Target)
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package mcheck
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadSchemaBundle(t *testing.T) {
+	tarball := filepath.Join(t.TempDir(), "SpyglassMC-vanilla-mcdoc-1a2b3c4")
+	if bundle := readSchemaBundle(osFiles, tarball); bundle.Commit != "1a2b3c4" {
+		t.Errorf("Expected the commit of an unpacked tarball to come from its name, got %+v", bundle)
+	}
+	if bundle := readSchemaBundle(osFiles, t.TempDir()); bundle.Commit != "" || bundle.Error != "" {
+		t.Errorf("Expected a plain directory to have no known revision, got %+v", bundle)
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	clone := fixtureSchemaDir(t, "trim")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "schemas"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = clone
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, output)
+		}
+	}
+	bundle := readSchemaBundle(osFiles, clone)
+	if len(bundle.Commit) != 40 || bundle.Date == "" {
+		t.Errorf("Expected the commit and date of a clone, got %+v", bundle)
+	}
+}
+
+func TestVersionInfo(t *testing.T) {
+	schemaDir := fixtureSchemaDir(t, "trim")
+	info := BuildVersionInfo(schemaDir, "1.20.1")
+	if info.Schemas.Dir != schemaDir || info.Minecraft.Oldest != KnownVersions[0] || info.Minecraft.Target != "1.20.1" {
+		t.Errorf("Unexpected version info %+v", info)
+	}
+	text := info.String()
+	for _, expected := range []string{"mcheck " + BuildVersion, "schemas:   " + schemaDir + " at an unknown revision", "written for java 1.21.5", "targeting 1.20.1"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected %q in\n%s", expected, text)
+		}
+	}
+
+	// Without schemas the version is still reported, with the reason
+	missing := BuildVersionInfo("", "1.20.1")
+	if _, err := os.Stat("vanilla-mcdoc"); err != nil && missing.Schemas.Error == "" {
+		t.Errorf("Expected a missing schema directory to be explained, got %+v", missing.Schemas)
+	}
+}
@@ -0,0 +1,332 @@
+package mcheck
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// fileCheck is a semantic check of a decoded file that has passed schema
+// validation, for constraints a type check cannot express. Findings are
+// returned without a file name; the caller fills it in.
+type fileCheck func(value map[string]interface{}) []Finding
+
+// fileChecks are the semantic checks for each resource type
+var fileChecks = map[string][]fileCheck{
+	"worldgen/noise_settings":   {checkNoiseBounds, checkNoiseToggles, checkSplines},
+	"worldgen/density_function": {checkSplines},
+	"worldgen/structure_set":    {checkStructurePlacement},
+	"dimension":                 {checkMultiNoiseParameters},
+	"worldgen/biome":            {checkBiomeSelections},
+	"trial_spawner":             {checkSpawnPotentials},
+	"worldgen/world_preset":     {checkWorldPresetDimensions},
+	"item_modifier":             {checkItemModifiers},
+	"item_modifiers":            {checkItemModifiers},
+}
+
+// listCheck is a semantic check of a decoded file that is a list, like an
+// item modifier listing its functions
+type listCheck func(value []interface{}) []Finding
+
+// listChecks are the semantic checks for resource types whose files may be
+// lists
+var listChecks = map[string][]listCheck{
+	"item_modifier":  {checkItemModifierList},
+	"item_modifiers": {checkItemModifierList},
+}
+
+// Limits of the world height from the game's dimension type
+const (
+	minWorldY      = -2032
+	maxWorldHeight = 4064
+)
+
+// checkNoiseBounds checks that the noise min_y and height are multiples of
+// 16 and stay within the world height limits
+func checkNoiseBounds(value map[string]interface{}) []Finding {
+	noise, ok := value["noise"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	minY, hasMinY := noise["min_y"].(float64)
+	height, hasHeight := noise["height"].(float64)
+
+	var findings []Finding
+	for _, field := range []struct {
+		name  string
+		value float64
+		ok    bool
+	}{{"min_y", minY, hasMinY}, {"height", height, hasHeight}} {
+		if field.ok && math.Mod(field.value, 16) != 0 {
+			findings = append(findings, worldgenError([]string{"noise", field.name}, RuleNoiseBounds,
+				"%s %g must be a multiple of 16", field.name, field.value))
+		}
+	}
+	if hasMinY && hasHeight && minY+height > minWorldY+maxWorldHeight {
+		findings = append(findings, worldgenError([]string{"noise"}, RuleNoiseBounds,
+			"min_y + height is %g, but the world cannot extend above y=%d", minY+height, minWorldY+maxWorldHeight-1))
+	}
+	return findings
+}
+
+// checkNoiseToggles warns when aquifers or ore veins are enabled but the
+// density functions that drive them are constant zero, which silently
+// disables the feature
+func checkNoiseToggles(value map[string]interface{}) []Finding {
+	router, ok := value["noise_router"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	toggles := []struct {
+		field   string
+		drivers []string
+	}{
+		{"aquifers_enabled", []string{"barrier", "fluid_level_floodedness", "fluid_level_spread", "lava"}},
+		{"ore_veins_enabled", []string{"vein_toggle", "vein_ridged", "vein_gap"}},
+	}
+
+	var findings []Finding
+	for _, toggle := range toggles {
+		if enabled, _ := value[toggle.field].(bool); !enabled {
+			continue
+		}
+		allZero := true
+		for _, driver := range toggle.drivers {
+			if constant, ok := router[driver].(float64); !ok || constant != 0 {
+				allZero = false
+			}
+		}
+		if allZero {
+			findings = append(findings, Finding{
+				Path:     []string{toggle.field},
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("%s has no effect because noise_router %v are all 0", toggle.field, toggle.drivers),
+				Rule:     RuleNoiseToggle,
+			})
+		}
+	}
+	return findings
+}
+
+// multiNoiseParameters are the climate parameters of multi noise biome entries
+var multiNoiseParameters = []string{"temperature", "humidity", "continentalness", "erosion", "weirdness", "depth"}
+
+// checkMultiNoiseParameters checks the climate parameters of a multi noise
+// biome source. Each value or [min, max] range must be within -2..2, and
+// every parameter should be covered over -1..1; points outside all ranges
+// still get the nearest biome, which is rarely intended.
+func checkMultiNoiseParameters(value map[string]interface{}) []Finding {
+	generator, _ := value["generator"].(map[string]interface{})
+	source, _ := generator["biome_source"].(map[string]interface{})
+	biomes, ok := source["biomes"].([]interface{})
+	if !ok {
+		return nil
+	}
+	basePath := []string{"generator", "biome_source", "biomes"}
+
+	var findings []Finding
+	intervals := make(map[string][][2]float64)
+	for i, entry := range biomes {
+		biome, _ := entry.(map[string]interface{})
+		parameters, ok := biome["parameters"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, name := range multiNoiseParameters {
+			path := append(append([]string{}, basePath...), strconv.Itoa(i), "parameters", name)
+			interval, ok := parameterInterval(parameters[name])
+			if !ok {
+				continue
+			}
+			switch {
+			case interval[0] < -2 || interval[1] > 2:
+				findings = append(findings, worldgenError(path, RuleBiomeParameters,
+					"%s %g..%g is outside -2..2", name, interval[0], interval[1]))
+			case interval[0] > interval[1]:
+				findings = append(findings, worldgenError(path, RuleBiomeParameters,
+					"%s range %g..%g has min greater than max", name, interval[0], interval[1]))
+			default:
+				intervals[name] = append(intervals[name], interval)
+			}
+		}
+	}
+
+	for _, name := range multiNoiseParameters {
+		if gap, ok := coverageGap(intervals[name], -1, 1); ok {
+			findings = append(findings, Finding{
+				Path:     basePath,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("no biome covers %s %g..%g, the nearest biome will be used there", name, gap[0], gap[1]),
+				Rule:     RuleBiomeParameters,
+			})
+		}
+	}
+	return findings
+}
+
+// parameterInterval reads a climate parameter, a number or [min, max]
+func parameterInterval(value interface{}) ([2]float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return [2]float64{v, v}, true
+	case []interface{}:
+		if len(v) != 2 {
+			return [2]float64{}, false
+		}
+		min, minOK := v[0].(float64)
+		max, maxOK := v[1].(float64)
+		return [2]float64{min, max}, minOK && maxOK
+	}
+	return [2]float64{}, false
+}
+
+// coverageGap returns the first part of from..to not covered by intervals
+func coverageGap(intervals [][2]float64, from, to float64) ([2]float64, bool) {
+	sorted := append([][2]float64{}, intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+
+	covered := from
+	for _, interval := range sorted {
+		if interval[0] > covered {
+			return [2]float64{covered, math.Min(interval[0], to)}, true
+		}
+		covered = math.Max(covered, interval[1])
+		if covered >= to {
+			return [2]float64{}, false
+		}
+	}
+	if covered < to {
+		return [2]float64{covered, to}, true
+	}
+	return [2]float64{}, false
+}
+
+// checkSplines checks that the points of every cubic spline in a file are
+// sorted by location, which the game requires to interpolate between them
+func checkSplines(value map[string]interface{}) []Finding {
+	var findings []Finding
+	walkSplines(value, nil, &findings)
+	return findings
+}
+
+func walkSplines(value interface{}, path []string, findings *[]Finding) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if points, ok := v["points"].([]interface{}); ok && v["coordinate"] != nil {
+			checkSplinePoints(points, append(append([]string{}, path...), "points"), findings)
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walkSplines(v[key], append(append([]string{}, path...), key), findings)
+		}
+	case []interface{}:
+		for i, element := range v {
+			walkSplines(element, append(append([]string{}, path...), strconv.Itoa(i)), findings)
+		}
+	}
+}
+
+func checkSplinePoints(points []interface{}, path []string, findings *[]Finding) {
+	previous := math.Inf(-1)
+	for i, entry := range points {
+		point, _ := entry.(map[string]interface{})
+		location, ok := point["location"].(float64)
+		if !ok {
+			continue
+		}
+		pointPath := append(append([]string{}, path...), strconv.Itoa(i), "location")
+		switch {
+		case location < previous:
+			*findings = append(*findings, worldgenError(pointPath, RuleSplinePoints,
+				"spline point location %g is less than the previous location %g", location, previous))
+		case location == previous:
+			*findings = append(*findings, Finding{
+				Path:     pointPath,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("spline point location %g repeats the previous location", location),
+				Rule:     RuleSplinePoints,
+			})
+		}
+		previous = location
+	}
+}
+
+// checkStructurePlacement warns about structure set placements the game
+// rejects or that never place anything: random spread spacing that is not
+// larger than separation, salts that do not fit in a 32-bit integer and
+// frequencies outside 0..1 or of 0
+func checkStructurePlacement(value map[string]interface{}) []Finding {
+	placement, ok := value["placement"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var findings []Finding
+	warn := func(field, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			Path:     []string{"placement", field},
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf(format, args...),
+			Rule:     RuleStructurePlacement,
+		})
+	}
+
+	spacing, hasSpacing := placement["spacing"].(float64)
+	separation, hasSeparation := placement["separation"].(float64)
+	if hasSpacing && hasSeparation && spacing <= separation {
+		warn("spacing", "spacing %g must be greater than separation %g", spacing, separation)
+	}
+
+	if salt, ok := placement["salt"].(float64); ok && (salt != math.Trunc(salt) || salt < 0 || salt > math.MaxInt32) {
+		warn("salt", "salt %g does not fit in a non-negative 32-bit integer", salt)
+	}
+
+	if frequency, ok := placement["frequency"].(float64); ok {
+		switch {
+		case frequency < 0 || frequency > 1:
+			warn("frequency", "frequency %g is outside 0..1", frequency)
+		case frequency == 0:
+			warn("frequency", "frequency 0 means the structures of this set never generate")
+		}
+	}
+	return findings
+}
+
+// checkWorldPresetDimensions checks the dimension map of a world preset: the
+// game refuses presets without an overworld, and ids written with and
+// without the minecraft namespace name the same dimension twice
+func checkWorldPresetDimensions(value map[string]interface{}) []Finding {
+	dimensions, ok := value["dimensions"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var findings []Finding
+	seen := make(map[string]string)
+	var keys []string
+	for _, key := range sortedKeys(dimensions, &keys) {
+		id := normalizeID(key)
+		if other, ok := seen[id]; ok {
+			findings = append(findings, worldgenError([]string{"dimensions", key}, RuleWorldPreset,
+				"dimension %s is also defined as %s", key, other))
+			continue
+		}
+		seen[id] = key
+	}
+	if _, ok := seen["minecraft:overworld"]; !ok {
+		findings = append(findings, worldgenError([]string{"dimensions"}, RuleWorldPreset,
+			"world preset has no minecraft:overworld dimension, which the game requires"))
+	}
+	return findings
+}
+
+// worldgenError creates an error finding for a worldgen check
+func worldgenError(path []string, rule, format string, args ...interface{}) Finding {
+	return Finding{Path: path, Severity: SeverityError, Message: fmt.Sprintf(format, args...), Rule: rule}
+}
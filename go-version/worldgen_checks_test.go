@@ -0,0 +1,115 @@
+package mcheck
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+// runFileChecks decodes a document and runs the file checks of a resource type
+func runFileChecks(t *testing.T, resourceType, document string) []string {
+	t.Helper()
+
+	var value map[string]interface{}
+	if err := json.Unmarshal([]byte(document), &value); err != nil {
+		t.Fatalf("Invalid test document: %v", err)
+	}
+	var messages []string
+	for _, check := range fileChecks[resourceType] {
+		for _, finding := range check(value) {
+			messages = append(messages, finding.String())
+		}
+	}
+	return messages
+}
+
+func TestWorldgenChecks(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType string
+		document     string
+		expected     []string
+	}{
+		{
+			"aligned noise", "worldgen/noise_settings",
+			`{"noise": {"min_y": -64, "height": 384}}`,
+			nil,
+		},
+		{
+			"misaligned noise", "worldgen/noise_settings",
+			`{"noise": {"min_y": -60, "height": 4080}}`,
+			[]string{
+				": at noise.min_y: min_y -60 must be a multiple of 16 [MCHECK050 noise-bounds]",
+				": at noise: min_y + height is 4020, but the world cannot extend above y=2031 [MCHECK050 noise-bounds]",
+			},
+		},
+		{
+			"disabled ore veins", "worldgen/noise_settings",
+			`{"ore_veins_enabled": true, "aquifers_enabled": true, "noise_router": {"vein_toggle": 0, "vein_ridged": 0, "vein_gap": 0, "barrier": "minecraft:overworld/barrier", "fluid_level_floodedness": 0, "fluid_level_spread": 0, "lava": 0}}`,
+			[]string{": warning: at ore_veins_enabled: ore_veins_enabled has no effect because noise_router [vein_toggle vein_ridged vein_gap] are all 0 [MCHECK051 noise-toggle]"},
+		},
+		{
+			"unsorted spline", "worldgen/density_function",
+			`{"type": "minecraft:spline", "spline": {"coordinate": "minecraft:overworld/continents", "points": [
+				{"location": -0.5, "value": 0, "derivative": 0},
+				{"location": 0.2, "value": {"coordinate": "minecraft:overworld/erosion", "points": [{"location": 0.1, "value": 1, "derivative": 0}, {"location": 0.1, "value": 2, "derivative": 0}]}, "derivative": 0},
+				{"location": 0.1, "value": 1, "derivative": 0}
+			]}}`,
+			[]string{
+				": at spline.points.2.location: spline point location 0.1 is less than the previous location 0.2 [MCHECK053 spline-points]",
+				": warning: at spline.points.1.value.points.1.location: spline point location 0.1 repeats the previous location [MCHECK053 spline-points]",
+			},
+		},
+		{
+			"valid placement", "worldgen/structure_set",
+			`{"placement": {"type": "minecraft:random_spread", "spacing": 34, "separation": 8, "salt": 14357617, "frequency": 0.5}}`,
+			nil,
+		},
+		{
+			"bad placement", "worldgen/structure_set",
+			`{"placement": {"type": "minecraft:random_spread", "spacing": 8, "separation": 8, "salt": 4294967296, "frequency": 0}}`,
+			[]string{
+				": warning: at placement.spacing: spacing 8 must be greater than separation 8 [MCHECK054 structure-placement]",
+				": warning: at placement.salt: salt 4.294967296e+09 does not fit in a non-negative 32-bit integer [MCHECK054 structure-placement]",
+				": warning: at placement.frequency: frequency 0 means the structures of this set never generate [MCHECK054 structure-placement]",
+			},
+		},
+		{
+			"multi noise", "dimension",
+			`{"generator": {"type": "minecraft:noise", "biome_source": {"type": "minecraft:multi_noise", "biomes": [
+				{"biome": "test:a", "parameters": {"temperature": [-1, 0], "humidity": [-1, 1], "continentalness": [-1, 1], "erosion": [-1, 1], "weirdness": [-1, 1], "depth": 0, "offset": 0}},
+				{"biome": "test:b", "parameters": {"temperature": [0.5, 3], "humidity": [1, -1], "continentalness": [-1, 1], "erosion": [-1, 1], "weirdness": [-1, 1], "depth": [-1, 1], "offset": 0}}
+			]}}}`,
+			[]string{
+				": at generator.biome_source.biomes.1.parameters.temperature: temperature 0.5..3 is outside -2..2 [MCHECK052 biome-parameters]",
+				": at generator.biome_source.biomes.1.parameters.humidity: humidity range 1..-1 has min greater than max [MCHECK052 biome-parameters]",
+				": warning: at generator.biome_source.biomes: no biome covers temperature 0..1, the nearest biome will be used there [MCHECK052 biome-parameters]",
+			},
+		},
+		{
+			"world preset dimensions", "worldgen/world_preset",
+			`{"dimensions": {"minecraft:overworld": {}, "overworld": {}, "test:moon": {}}}`,
+			[]string{": at dimensions.overworld: dimension overworld is also defined as minecraft:overworld [MCHECK057 world-preset]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := runFileChecks(t, tt.resourceType, tt.document)
+			if strings.Join(messages, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Unexpected findings:\n%s", strings.Join(messages, "\n"))
+			}
+		})
+	}
+}
+
+func TestWorldgenChecksVanillaFixture(t *testing.T) {
+	data, err := os.ReadFile("tests/good/data/worldgen/noise_settings/end.json")
+	if err != nil {
+		t.Skip("fixture not available")
+	}
+	if messages := runFileChecks(t, "worldgen/noise_settings", string(data)); len(messages) != 0 {
+		t.Errorf("Expected no findings for vanilla noise settings, got:\n%s", strings.Join(messages, "\n"))
+	}
+}